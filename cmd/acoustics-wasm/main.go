@@ -11,17 +11,13 @@ import (
 	"github.com/MeKo-Tech/algo-pde/poisson"
 )
 
-type PlanEntry struct {
-	plan *poisson.Plan
-	nx   int
-	ny   int
-	dx   float64
-	dy   float64
-	bcX  int
-	bcY  int
-}
+// planConfigs maps plan IDs handed to JS to their grid setup; the plans
+// themselves, one per (grid, alpha), live in planCache.
+var planConfigs = make(map[string]poisson.PlanConfig)
 
-var planCache = make(map[string]*PlanEntry)
+// planCache bounds the number of live plans; each holds eigenvalue tables and
+// transform plans for one grid and alpha.
+var planCache, _ = poisson.NewCache(8)
 
 func main() {
 	// Register Go functions for JS
@@ -66,7 +62,7 @@ func InitPlan(this js.Value, args []js.Value) interface{} {
 	planID := generatePlanID(nx, ny, dx, dy, bcX, bcY)
 
 	// Check if plan already exists
-	if _, exists := planCache[planID]; exists {
+	if _, exists := planConfigs[planID]; exists {
 		return jsSuccess(map[string]interface{}{
 			"planID": planID,
 			"nx":     nx,
@@ -75,28 +71,19 @@ func InitPlan(this js.Value, args []js.Value) interface{} {
 		})
 	}
 
-	// Create new plan
-	// Note: We'll set alpha=0 initially, will be overridden per solve
-	plan, err := poisson.NewHelmholtzPlan(
-		2,
-		[]int{nx, ny},
-		[]float64{dx, dy},
-		[]poisson.BCType{poisson.BCType(bcX), poisson.BCType(bcY)},
-		0, // alpha will be set per-solve via new plan creation
-	)
-	if err != nil {
+	// Create the alpha=0 plan up front to validate the setup; per-frequency
+	// plans are created on demand by Solve.
+	cfg := poisson.PlanConfig{
+		Dims:            []int{nx, ny},
+		Spacing:         []float64{dx, dy},
+		BC:              []poisson.BCType{poisson.BCType(bcX), poisson.BCType(bcY)},
+		PooledWorkspace: true,
+	}
+	if _, err := planCache.Plan(cfg); err != nil {
 		return jsError(fmt.Sprintf("Failed to create plan: %v", err))
 	}
 
-	planCache[planID] = &PlanEntry{
-		plan: plan,
-		nx:   nx,
-		ny:   ny,
-		dx:   dx,
-		dy:   dy,
-		bcX:  bcX,
-		bcY:  bcY,
-	}
+	planConfigs[planID] = cfg
 
 	return jsSuccess(map[string]interface{}{
 		"planID": planID,
@@ -121,29 +108,25 @@ func Solve(this js.Value, args []js.Value) interface{} {
 	srcRadius := args[4].Float()
 
 	// Get cached plan metadata
-	entry, exists := planCache[planID]
+	cfg, exists := planConfigs[planID]
 	if !exists {
 		return jsError("Plan not found. Call InitPlan first.")
 	}
 
-	// Create new Helmholtz plan with specific alpha
-	// This is necessary because alpha is baked into the plan
-	plan, err := poisson.NewHelmholtzPlan(
-		2,
-		[]int{entry.nx, entry.ny},
-		[]float64{entry.dx, entry.dy},
-		[]poisson.BCType{poisson.BCType(entry.bcX), poisson.BCType(entry.bcY)},
-		alpha,
-	)
+	// Alpha is baked into the plan, so each frequency has its own cached plan
+	cfg.Alpha = alpha
+	plan, err := planCache.Plan(cfg)
 	if err != nil {
 		return jsError(fmt.Sprintf("Failed to create Helmholtz plan: %v", err))
 	}
 
+	nx, ny := cfg.Dims[0], cfg.Dims[1]
+
 	// Build Gaussian source
-	rhs := buildGaussianSource(entry.nx, entry.ny, sx, sy, srcRadius)
+	rhs := buildGaussianSource(nx, ny, sx, sy, srcRadius)
 
 	// Solve
-	dst := make([]float64, entry.nx*entry.ny)
+	dst := make([]float64, nx*ny)
 	if err := plan.Solve(dst, rhs); err != nil {
 		return jsError(fmt.Sprintf("Solve failed: %v", err))
 	}
@@ -177,16 +160,16 @@ func GetPlanInfo(this js.Value, args []js.Value) interface{} {
 	}
 
 	planID := args[0].String()
-	entry, exists := planCache[planID]
+	cfg, exists := planConfigs[planID]
 	if !exists {
 		return jsError("Plan not found")
 	}
 
 	return jsSuccess(map[string]interface{}{
-		"nx": entry.nx,
-		"ny": entry.ny,
-		"dx": entry.dx,
-		"dy": entry.dy,
+		"nx": cfg.Dims[0],
+		"ny": cfg.Dims[1],
+		"dx": cfg.Spacing[0],
+		"dy": cfg.Spacing[1],
 	})
 }
 
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Case describes one canonical solve. The RHS is drawn from a PCG generator
+// seeded with Seed, so every platform sees bit-identical inputs.
+type Case struct {
+	Name  string    `json:"name"`
+	N     []int     `json:"n"`
+	H     []float64 `json:"h"`
+	BC    []string  `json:"bc"`
+	Alpha float64   `json:"alpha"`
+	Seed  uint64    `json:"seed"`
+}
+
+// Dataset is the stored result of a Case.
+type Dataset struct {
+	Case     Case      `json:"case"`
+	Checksum string    `json:"checksum"`
+	MaxAbs   float64   `json:"max_abs"`
+	L2       float64   `json:"l2"`
+	Solution []float64 `json:"solution"`
+}
+
+// Comparison quantifies the difference between a stored and a freshly
+// generated dataset.
+type Comparison struct {
+	Name      string
+	Identical bool
+	MaxAbs    float64
+	MaxRel    float64
+}
+
+// defaultCases covers every boundary condition in 1D/2D/3D, mixed axes,
+// Poisson and Helmholtz, and power-of-two as well as odd sizes.
+func defaultCases() []Case {
+	return []Case{
+		{Name: "periodic-1d-64", N: []int{64}, H: []float64{1.0 / 64}, BC: []string{"Periodic"}, Seed: 1},
+		{Name: "dirichlet-1d-63", N: []int{63}, H: []float64{1.0 / 64}, BC: []string{"Dirichlet"}, Seed: 2},
+		{Name: "neumann-1d-50", N: []int{50}, H: []float64{1.0 / 50}, BC: []string{"Neumann"}, Seed: 3},
+		{Name: "periodic-2d-32x32", N: []int{32, 32}, H: []float64{1.0 / 32, 1.0 / 32}, BC: []string{"Periodic", "Periodic"}, Seed: 4},
+		{Name: "dirichlet-2d-31x17", N: []int{31, 17}, H: []float64{1.0 / 32, 1.0 / 18}, BC: []string{"Dirichlet", "Dirichlet"}, Seed: 5},
+		{Name: "neumann-2d-24x20", N: []int{24, 20}, H: []float64{1.0 / 24, 1.0 / 20}, BC: []string{"Neumann", "Neumann"}, Seed: 6},
+		{Name: "mixed-2d-32x15", N: []int{32, 15}, H: []float64{1.0 / 32, 1.0 / 16}, BC: []string{"Periodic", "Dirichlet"}, Seed: 7},
+		{
+			Name: "helmholtz-2d-16x16", N: []int{16, 16}, H: []float64{1.0 / 16, 1.0 / 16},
+			BC: []string{"Neumann", "Periodic"}, Alpha: 2.5, Seed: 8,
+		},
+		{
+			Name: "mixed-3d-16x12x10", N: []int{16, 12, 10}, H: []float64{1.0 / 16, 1.0 / 13, 1.0 / 10},
+			BC: []string{"Periodic", "Dirichlet", "Neumann"}, Seed: 9,
+		},
+	}
+}
+
+// generate solves c and returns its dataset.
+func generate(c Case) (*Dataset, error) {
+	bc := make([]poisson.BCType, len(c.BC))
+	for axis, name := range c.BC {
+		t, err := parseBC(name)
+		if err != nil {
+			return nil, fmt.Errorf("case %s: %w", c.Name, err)
+		}
+		bc[axis] = t
+	}
+
+	plan, err := poisson.NewHelmholtzPlan(len(c.N), c.N, c.H, bc, c.Alpha,
+		poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithWorkers(1))
+	if err != nil {
+		return nil, fmt.Errorf("case %s: %w", c.Name, err)
+	}
+
+	size := 1
+	for _, n := range c.N {
+		size *= n
+	}
+
+	rng := rand.New(rand.NewPCG(c.Seed, 0))
+	rhs := make([]float64, size)
+	for i := range rhs {
+		rhs[i] = 2*rng.Float64() - 1
+	}
+
+	sol := make([]float64, size)
+	if err := plan.Solve(sol, rhs); err != nil {
+		return nil, fmt.Errorf("case %s: %w", c.Name, err)
+	}
+
+	ds := &Dataset{Case: c, Checksum: checksum(sol), Solution: sol}
+	for _, v := range sol {
+		ds.MaxAbs = math.Max(ds.MaxAbs, math.Abs(v))
+		ds.L2 += v * v
+	}
+	ds.L2 = math.Sqrt(ds.L2 / float64(size))
+
+	return ds, nil
+}
+
+// compare regenerates ref's case and compares the solutions.
+func compare(ref *Dataset) (Comparison, error) {
+	if checksum(ref.Solution) != ref.Checksum {
+		return Comparison{}, fmt.Errorf("case %s: stored checksum does not match stored solution", ref.Case.Name)
+	}
+
+	got, err := generate(ref.Case)
+	if err != nil {
+		return Comparison{}, err
+	}
+
+	if len(got.Solution) != len(ref.Solution) {
+		return Comparison{}, fmt.Errorf("case %s: solution length %d, reference %d",
+			ref.Case.Name, len(got.Solution), len(ref.Solution))
+	}
+
+	cmp := Comparison{Name: ref.Case.Name, Identical: got.Checksum == ref.Checksum}
+	for i, v := range got.Solution {
+		cmp.MaxAbs = math.Max(cmp.MaxAbs, math.Abs(v-ref.Solution[i]))
+	}
+	if ref.MaxAbs > 0 {
+		cmp.MaxRel = cmp.MaxAbs / ref.MaxAbs
+	}
+
+	return cmp, nil
+}
+
+// checksum returns the SHA-256 of the little-endian IEEE-754 bits of values.
+func checksum(values []float64) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, v := range values {
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+		h.Write(buf[:])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func parseBC(name string) (poisson.BCType, error) {
+	for _, bc := range []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann} {
+		if bc.String() == name {
+			return bc, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown boundary condition %q", name)
+}
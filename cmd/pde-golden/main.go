@@ -0,0 +1,147 @@
+// Command pde-golden generates and checks canonical solution datasets.
+//
+// Datasets cover every boundary condition, several sizes and Helmholtz
+// shifts, with RHS fields drawn from fixed seeds. Generate a reference set on
+// one platform and compare on another to quantify floating-point differences
+// (e.g. amd64 vs arm64 vs wasm):
+//
+//	pde-golden -out testdata/golden
+//	pde-golden -compare testdata/golden -tol 1e-12
+//
+// Each case is stored as <name>.json with the solution, its SHA-256 checksum
+// over the IEEE-754 bits, and summary norms; manifest.json lists all
+// checksums. Compare mode reports bit-identical cases and, for the others,
+// the max absolute and relative difference. It exits with status 1 when a
+// relative difference exceeds -tol.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const manifestName = "manifest.json"
+
+func main() {
+	out := flag.String("out", "", "directory to write reference datasets to")
+	ref := flag.String("compare", "", "directory with reference datasets to compare against")
+	tol := flag.Float64("tol", 1e-12, "maximum allowed relative difference in compare mode")
+	flag.Parse()
+
+	var err error
+	switch {
+	case *out != "" && *ref == "":
+		err = writeDatasets(os.Stdout, *out)
+	case *ref != "" && *out == "":
+		err = compareDatasets(os.Stdout, *ref, *tol)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pde-golden:", err)
+		os.Exit(1)
+	}
+}
+
+func writeDatasets(w io.Writer, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	manifest := make(map[string]string)
+	for _, c := range defaultCases() {
+		ds, err := generate(c)
+		if err != nil {
+			return err
+		}
+
+		if err := writeJSON(filepath.Join(dir, c.Name+".json"), ds); err != nil {
+			return err
+		}
+
+		manifest[c.Name] = ds.Checksum
+		fmt.Fprintf(w, "%-24s %s\n", c.Name, ds.Checksum)
+	}
+
+	return writeJSON(filepath.Join(dir, manifestName), manifest)
+}
+
+var errTolerance = errors.New("datasets differ beyond tolerance")
+
+func compareDatasets(w io.Writer, dir string, tol float64) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	failed := 0
+	compared := 0
+	for _, path := range paths {
+		if filepath.Base(path) == manifestName {
+			continue
+		}
+
+		var ref Dataset
+		if err := readJSON(path, &ref); err != nil {
+			return err
+		}
+
+		cmp, err := compare(&ref)
+		if err != nil {
+			return err
+		}
+		compared++
+
+		status := "identical"
+		if !cmp.Identical {
+			status = "within tolerance"
+			if cmp.MaxRel > tol {
+				status = "FAIL"
+				failed++
+			}
+		}
+		fmt.Fprintf(w, "%-24s %-16s max_abs=%.3e max_rel=%.3e\n", cmp.Name, status, cmp.MaxAbs, cmp.MaxRel)
+	}
+
+	if compared == 0 {
+		return fmt.Errorf("no datasets found in %s", dir)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d cases: %w (tol %g)", failed, compared, errTolerance, tol)
+	}
+
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", strings.TrimSuffix(filepath.Base(path), ".json"), err)
+	}
+
+	return nil
+}
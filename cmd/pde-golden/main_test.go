@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_Deterministic(t *testing.T) {
+	for _, c := range defaultCases() {
+		a, err := generate(c)
+		if err != nil {
+			t.Fatalf("generate(%s) failed: %v", c.Name, err)
+		}
+
+		b, err := generate(c)
+		if err != nil {
+			t.Fatalf("generate(%s) failed: %v", c.Name, err)
+		}
+
+		if a.Checksum != b.Checksum {
+			t.Fatalf("case %s: checksums differ between runs", c.Name)
+		}
+		if a.MaxAbs == 0 {
+			t.Fatalf("case %s: trivial solution", c.Name)
+		}
+	}
+}
+
+func TestWriteAndCompareDatasets(t *testing.T) {
+	dir := t.TempDir()
+
+	var out bytes.Buffer
+	if err := writeDatasets(&out, dir); err != nil {
+		t.Fatalf("writeDatasets failed: %v", err)
+	}
+
+	out.Reset()
+	if err := compareDatasets(&out, dir, 0); err != nil {
+		t.Fatalf("compareDatasets failed: %v\n%s", err, out.String())
+	}
+	if got, want := strings.Count(out.String(), "identical"), len(defaultCases()); got != want {
+		t.Fatalf("%d identical cases, want %d:\n%s", got, want, out.String())
+	}
+
+	path := filepath.Join(dir, "periodic-1d-64.json")
+	var ds Dataset
+	if err := readJSON(path, &ds); err != nil {
+		t.Fatalf("readJSON failed: %v", err)
+	}
+	ds.Solution[3] += 1e-3 * ds.MaxAbs
+	ds.Checksum = checksum(ds.Solution)
+	if err := writeJSON(path, &ds); err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+
+	out.Reset()
+	if err := compareDatasets(&out, dir, 1e-6); !errors.Is(err, errTolerance) {
+		t.Fatalf("expected errTolerance, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "FAIL") {
+		t.Fatalf("missing FAIL line:\n%s", out.String())
+	}
+}
+
+func TestCompare_CorruptChecksum(t *testing.T) {
+	ds, err := generate(defaultCases()[0])
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	ds.Solution[0]++
+	if _, err := compare(ds); err == nil {
+		t.Fatal("expected checksum error")
+	}
+}
+
+func TestCompareDatasets_Empty(t *testing.T) {
+	var out bytes.Buffer
+	if err := compareDatasets(&out, t.TempDir(), 0); err == nil {
+		t.Fatal("expected error for empty directory")
+	}
+}
@@ -0,0 +1,216 @@
+package decomp
+
+import (
+	"fmt"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Block is the part of the global grid owned by one rank.
+type Block struct {
+	// Offset is the global index of the block's first point along each axis.
+	Offset [3]int
+
+	// Shape is the block's extent along each axis.
+	Shape grid.Shape
+}
+
+// LocalToGlobal returns the global index of local point (i, j, k).
+func (b Block) LocalToGlobal(i, j, k int) [3]int {
+	return [3]int{b.Offset[0] + i, b.Offset[1] + j, b.Offset[2] + k}
+}
+
+// GlobalToLocal returns the local index of global point g and whether the
+// block contains it.
+func (b Block) GlobalToLocal(g [3]int) ([3]int, bool) {
+	var l [3]int
+	for axis := range 3 {
+		l[axis] = g[axis] - b.Offset[axis]
+		if l[axis] < 0 || l[axis] >= b.Shape[axis] {
+			return l, false
+		}
+	}
+
+	return l, true
+}
+
+// Decomposition partitions a global grid into a row-major process grid of
+// blocks.
+type Decomposition struct {
+	global grid.Shape
+	procs  [3]int
+
+	// starts[axis] holds the procs[axis]+1 block boundaries along axis.
+	starts [3][]int
+}
+
+// New partitions global into procs[0]×procs[1]×procs[2] blocks. Every axis
+// must have at least as many points as processes.
+func New(global grid.Shape, procs [3]int) (*Decomposition, error) {
+	for axis := range 3 {
+		if global[axis] < 1 {
+			return nil, &poisson.ValidationError{
+				Field:   "global",
+				Message: fmt.Sprintf("axis %d: size must be positive", axis),
+			}
+		}
+
+		if procs[axis] < 1 || procs[axis] > global[axis] {
+			return nil, &poisson.ValidationError{
+				Field:   "procs",
+				Message: fmt.Sprintf("axis %d: must be in [1, %d]", axis, global[axis]),
+			}
+		}
+	}
+
+	d := &Decomposition{global: global, procs: procs}
+	for axis := range 3 {
+		d.starts[axis] = split(global[axis], procs[axis])
+	}
+
+	return d, nil
+}
+
+// NewSlab splits global into nprocs slabs along axis.
+func NewSlab(global grid.Shape, axis, nprocs int) (*Decomposition, error) {
+	if axis < 0 || axis > 2 {
+		return nil, &poisson.ValidationError{Field: "axis", Message: "must be 0, 1, or 2"}
+	}
+
+	procs := [3]int{1, 1, 1}
+	procs[axis] = nprocs
+
+	return New(global, procs)
+}
+
+// NewPencil splits global into p0×p1 pencils aligned with axis: every block
+// holds complete lines along axis, and the two remaining axes (in increasing
+// order) are split into p0 and p1 parts.
+func NewPencil(global grid.Shape, axis, p0, p1 int) (*Decomposition, error) {
+	if axis < 0 || axis > 2 {
+		return nil, &poisson.ValidationError{Field: "axis", Message: "must be 0, 1, or 2"}
+	}
+
+	procs := [3]int{1, 1, 1}
+	a, b := otherAxes(axis)
+	procs[a] = p0
+	procs[b] = p1
+
+	return New(global, procs)
+}
+
+// Global returns the global grid shape.
+func (d *Decomposition) Global() grid.Shape {
+	return d.global
+}
+
+// Procs returns the process grid.
+func (d *Decomposition) Procs() [3]int {
+	return d.procs
+}
+
+// Size returns the number of ranks.
+func (d *Decomposition) Size() int {
+	return d.procs[0] * d.procs[1] * d.procs[2]
+}
+
+// Coords returns the process-grid coordinates of rank.
+func (d *Decomposition) Coords(rank int) [3]int {
+	return [3]int{
+		rank / (d.procs[1] * d.procs[2]),
+		rank / d.procs[2] % d.procs[1],
+		rank % d.procs[2],
+	}
+}
+
+// Rank returns the rank at process-grid coordinates c.
+func (d *Decomposition) Rank(c [3]int) int {
+	return (c[0]*d.procs[1]+c[1])*d.procs[2] + c[2]
+}
+
+// Block returns the block owned by rank.
+func (d *Decomposition) Block(rank int) Block {
+	c := d.Coords(rank)
+
+	var b Block
+	for axis := range 3 {
+		lo, hi := d.starts[axis][c[axis]], d.starts[axis][c[axis]+1]
+		b.Offset[axis] = lo
+		b.Shape[axis] = hi - lo
+	}
+
+	return b
+}
+
+// Owner returns the rank owning global point g, or -1 if g lies outside the
+// global grid.
+func (d *Decomposition) Owner(g [3]int) int {
+	var c [3]int
+	for axis := range 3 {
+		if g[axis] < 0 || g[axis] >= d.global[axis] {
+			return -1
+		}
+		c[axis] = owner(d.starts[axis], g[axis])
+	}
+
+	return d.Rank(c)
+}
+
+// Neighbor returns the rank adjacent to rank along axis in direction dir
+// (-1 or +1). Along periodic axes the process grid wraps around; otherwise
+// ok is false at the domain boundary.
+func (d *Decomposition) Neighbor(rank, axis, dir int, periodic bool) (neighbor int, ok bool) {
+	c := d.Coords(rank)
+	c[axis] += dir
+
+	if c[axis] < 0 || c[axis] >= d.procs[axis] {
+		if !periodic {
+			return -1, false
+		}
+		c[axis] = (c[axis] + d.procs[axis]) % d.procs[axis]
+	}
+
+	return d.Rank(c), true
+}
+
+// split returns the p+1 boundaries of a balanced split of n points.
+func split(n, p int) []int {
+	starts := make([]int, p+1)
+	base, extra := n/p, n%p
+	for i := range p {
+		size := base
+		if i < extra {
+			size++
+		}
+		starts[i+1] = starts[i] + size
+	}
+
+	return starts
+}
+
+// owner returns the part of a split containing index g.
+func owner(starts []int, g int) int {
+	lo, hi := 0, len(starts)-2
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if starts[mid] <= g {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	return lo
+}
+
+func otherAxes(axis int) (int, int) {
+	switch axis {
+	case 0:
+		return 1, 2
+	case 1:
+		return 0, 2
+	default:
+		return 0, 1
+	}
+}
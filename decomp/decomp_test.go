@@ -0,0 +1,102 @@
+package decomp_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/decomp"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestDecomposition_CoversGlobalOnce(t *testing.T) {
+	global := grid.NewShape3D(10, 7, 5)
+
+	decomps := map[string]func() (*decomp.Decomposition, error){
+		"Slab": func() (*decomp.Decomposition, error) { return decomp.NewSlab(global, 0, 3) },
+		"Pencil": func() (*decomp.Decomposition, error) {
+			return decomp.NewPencil(global, 2, 3, 2)
+		},
+		"Block": func() (*decomp.Decomposition, error) { return decomp.New(global, [3]int{2, 3, 2}) },
+	}
+
+	for name, construct := range decomps {
+		t.Run(name, func(t *testing.T) {
+			d, err := construct()
+			if err != nil {
+				t.Fatalf("construct failed: %v", err)
+			}
+
+			owned := make([]int, global.Size())
+			for rank := range d.Size() {
+				if got := d.Rank(d.Coords(rank)); got != rank {
+					t.Fatalf("Rank(Coords(%d)) = %d", rank, got)
+				}
+
+				b := d.Block(rank)
+				for i := range b.Shape[0] {
+					for j := range b.Shape[1] {
+						for k := range b.Shape[2] {
+							g := b.LocalToGlobal(i, j, k)
+							owned[grid.Index3D(g[0], g[1], g[2], global)]++
+
+							if owner := d.Owner(g); owner != rank {
+								t.Fatalf("Owner(%v) = %d, want %d", g, owner, rank)
+							}
+							if l, ok := b.GlobalToLocal(g); !ok || l != [3]int{i, j, k} {
+								t.Fatalf("GlobalToLocal(%v) = %v, %v", g, l, ok)
+							}
+						}
+					}
+				}
+			}
+
+			for idx, count := range owned {
+				if count != 1 {
+					t.Fatalf("point %d owned %d times", idx, count)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPencil_KeepsPencilAxisWhole(t *testing.T) {
+	d, err := decomp.NewPencil(grid.NewShape3D(8, 6, 4), 1, 2, 2)
+	if err != nil {
+		t.Fatalf("NewPencil failed: %v", err)
+	}
+
+	if d.Procs() != [3]int{2, 1, 2} {
+		t.Fatalf("Procs() = %v, want [2 1 2]", d.Procs())
+	}
+	if b := d.Block(3); b.Shape != grid.NewShape3D(4, 6, 2) || b.Offset != [3]int{4, 0, 2} {
+		t.Fatalf("Block(3) = %+v", b)
+	}
+}
+
+func TestDecomposition_Neighbor(t *testing.T) {
+	d, err := decomp.NewSlab(grid.NewShape2D(9, 4), 0, 3)
+	if err != nil {
+		t.Fatalf("NewSlab failed: %v", err)
+	}
+
+	if _, ok := d.Neighbor(0, 0, -1, false); ok {
+		t.Fatal("expected no low neighbor at a non-periodic boundary")
+	}
+	if n, ok := d.Neighbor(0, 0, -1, true); !ok || n != 2 {
+		t.Fatalf("periodic low neighbor of rank 0 = %d, %v; want 2", n, ok)
+	}
+	if n, ok := d.Neighbor(1, 0, +1, false); !ok || n != 2 {
+		t.Fatalf("high neighbor of rank 1 = %d, %v; want 2", n, ok)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	var vErr *poisson.ValidationError
+	if _, err := decomp.New(grid.NewShape2D(4, 4), [3]int{5, 1, 1}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for too many processes, got %v", err)
+	}
+	if _, err := decomp.NewSlab(grid.NewShape2D(4, 4), 3, 2); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for invalid axis, got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+// Package decomp splits grids across processes and exchanges halo layers
+// between them, as groundwork for distributed solves.
+//
+// A Decomposition partitions a global grid.Shape into a process grid of
+// blocks. NewSlab splits one axis, NewPencil splits the two axes other than
+// the pencil axis, and New accepts an arbitrary process grid. Blocks are
+// balanced: along each axis the first n%p blocks hold one extra point. Ranks
+// are numbered row-major over the process grid, and Block maps between a
+// block's local indices and global indices.
+//
+// A Halo describes a local array padded with ghost layers around the block
+// interior. Halo.Exchange fills the ghosts from neighboring blocks over a
+// Transport, axis by axis so that edge and corner ghosts are filled as well.
+// Transports are user-pluggable; NewChannelNetwork provides an in-process
+// implementation for tests and shared-memory runs. After an exchange,
+// Halo.FillBoundary fills the ghosts on faces at the edge of the global grid
+// with the grid.Field rules, and Halo.Field wraps a local array as a
+// grid.Field.
+//
+// PeriodicSolver builds on pencil decompositions to solve periodic 3D
+// Poisson/Helmholtz problems distributed over many ranks, transposing between
+// z-, y- and x-pencils so every FFT runs on complete local lines.
+//
+// Data layout is row-major, as in the rest of the module.
+package decomp
@@ -0,0 +1,199 @@
+package decomp
+
+import (
+	"fmt"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Halo describes the local array of one rank: its block padded with width
+// ghost layers on both sides of every axis with more than one global point.
+// The array is row-major with shape Padded(); the block interior is the
+// Interior() view, which can be passed directly to poisson's SolveView.
+type Halo struct {
+	d        *Decomposition
+	rank     int
+	width    int
+	periodic [3]bool
+
+	block  Block
+	pad    [3]int
+	padded grid.Shape
+
+	sendBuf []float64
+	recvBuf []float64
+}
+
+// NewHalo creates the halo layout of rank with the given ghost width.
+// periodic selects, per axis, whether ghosts at the global boundary wrap
+// around to the opposite side; otherwise they are left untouched by
+// Exchange. Every block must hold at least width points along padded axes.
+func NewHalo(d *Decomposition, rank, width int, periodic [3]bool) (*Halo, error) {
+	if rank < 0 || rank >= d.Size() {
+		return nil, &poisson.ValidationError{Field: "rank", Message: fmt.Sprintf("must be in [0, %d)", d.Size())}
+	}
+
+	if width < 0 {
+		return nil, &poisson.ValidationError{Field: "width", Message: "must be non-negative"}
+	}
+
+	h := &Halo{d: d, rank: rank, width: width, periodic: periodic, block: d.Block(rank)}
+
+	maxFace := 0
+	for axis := range 3 {
+		if d.global[axis] > 1 {
+			h.pad[axis] = width
+		}
+
+		for r := range d.Size() {
+			if h.pad[axis] > 0 && d.Block(r).Shape[axis] < width {
+				return nil, &poisson.ValidationError{
+					Field:   "width",
+					Message: fmt.Sprintf("axis %d: exceeds the smallest block (%d points)", axis, d.Block(r).Shape[axis]),
+				}
+			}
+		}
+
+		h.padded[axis] = h.block.Shape[axis] + 2*h.pad[axis]
+	}
+
+	for axis := range 3 {
+		maxFace = max(maxFace, h.pad[axis]*h.padded.Size()/h.padded[axis])
+	}
+	h.sendBuf = make([]float64, maxFace)
+	h.recvBuf = make([]float64, maxFace)
+
+	return h, nil
+}
+
+// Block returns the block owned by the halo's rank.
+func (h *Halo) Block() Block {
+	return h.block
+}
+
+// Padded returns the shape of the local array including ghost layers.
+func (h *Halo) Padded() grid.Shape {
+	return h.padded
+}
+
+// Interior returns the view of the block interior inside the padded array.
+func (h *Halo) Interior() grid.View {
+	return grid.Window(h.padded, h.pad, h.block.Shape)
+}
+
+// Field wraps data (shape Padded()) as a grid.Field with the halo's ghost
+// widths, the layout stencil code reads after Exchange and FillBoundary.
+func (h *Halo) Field(data []float64) *grid.Field {
+	return &grid.Field{Shape: h.block.Shape, Pad: h.pad, Data: data}
+}
+
+// FillBoundary fills the ghost layers of data (shape Padded()) on the faces
+// of the block that lie on the global boundary of a non-periodic axis, with
+// the rule bc of that axis as in grid.Field.FillHalo. Called after Exchange,
+// it completes the ghosts exactly as FillHalo would on the undecomposed
+// field, edges and corners included.
+func (h *Halo) FillBoundary(data []float64, bc [3]grid.HaloBC) error {
+	if len(data) != h.padded.Size() {
+		return &poisson.SizeError{Expected: h.padded.Size(), Got: len(data), Context: "halo boundary"}
+	}
+
+	f := h.Field(data)
+	for axis := range 3 {
+		if h.pad[axis] == 0 || h.periodic[axis] {
+			continue
+		}
+
+		if _, ok := h.d.Neighbor(h.rank, axis, -1, false); !ok {
+			f.FillFace(axis, false, bc[axis])
+		}
+		if _, ok := h.d.Neighbor(h.rank, axis, +1, false); !ok {
+			f.FillFace(axis, true, bc[axis])
+		}
+	}
+
+	return nil
+}
+
+// Exchange fills the ghost layers of data (shape Padded()) with the
+// neighboring blocks' interior values. All ranks must call Exchange with
+// their own halo and transport.
+//
+// Axes are exchanged in order, each including the ghost layers of the other
+// axes, so edge and corner ghosts end up with the values of diagonal
+// neighbors.
+func (h *Halo) Exchange(t Transport, data []float64) error {
+	if len(data) != h.padded.Size() {
+		return &poisson.SizeError{Expected: h.padded.Size(), Got: len(data), Context: "halo exchange"}
+	}
+
+	for axis := range 3 {
+		w := h.pad[axis]
+		if w == 0 {
+			continue
+		}
+
+		n := h.block.Shape[axis]
+		face := w * h.padded.Size() / h.padded[axis]
+		low, hasLow := h.d.Neighbor(h.rank, axis, -1, h.periodic[axis])
+		high, hasHigh := h.d.Neighbor(h.rank, axis, +1, h.periodic[axis])
+
+		// Tag 2*axis carries a low interior face (to the low neighbor's
+		// high ghosts), tag 2*axis+1 a high interior face.
+		if hasLow {
+			h.copyLayers(data, h.sendBuf[:face], axis, w, w, true)
+			if err := t.Send(low, 2*axis, h.sendBuf[:face]); err != nil {
+				return fmt.Errorf("axis %d: send to rank %d: %w", axis, low, err)
+			}
+		}
+
+		if hasHigh {
+			h.copyLayers(data, h.sendBuf[:face], axis, n, w, true)
+			if err := t.Send(high, 2*axis+1, h.sendBuf[:face]); err != nil {
+				return fmt.Errorf("axis %d: send to rank %d: %w", axis, high, err)
+			}
+		}
+
+		if hasHigh {
+			if err := t.Recv(high, 2*axis, h.recvBuf[:face]); err != nil {
+				return fmt.Errorf("axis %d: recv from rank %d: %w", axis, high, err)
+			}
+			h.copyLayers(data, h.recvBuf[:face], axis, w+n, w, false)
+		}
+
+		if hasLow {
+			if err := t.Recv(low, 2*axis+1, h.recvBuf[:face]); err != nil {
+				return fmt.Errorf("axis %d: recv from rank %d: %w", axis, low, err)
+			}
+			h.copyLayers(data, h.recvBuf[:face], axis, 0, w, false)
+		}
+	}
+
+	return nil
+}
+
+// copyLayers copies the count layers of data starting at padded index start
+// along axis into buf (pack) or from buf into data (unpack). The layers span
+// the full padded extent of the other axes.
+func (h *Halo) copyLayers(data, buf []float64, axis, start, count int, pack bool) {
+	lo := [3]int{}
+	hi := h.padded
+	lo[axis] = start
+	hi[axis] = start + count
+
+	stride := grid.RowMajorStride(h.padded)
+	idx := 0
+	for i := lo[0]; i < hi[0]; i++ {
+		for j := lo[1]; j < hi[1]; j++ {
+			for k := lo[2]; k < hi[2]; k++ {
+				off := grid.Index(i, j, k, stride)
+				if pack {
+					buf[idx] = data[off]
+				} else {
+					data[off] = buf[idx]
+				}
+				idx++
+			}
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package decomp_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/decomp"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// exchangeAll runs one halo exchange on every rank concurrently, starting
+// from local arrays whose interiors hold the global index of each point and
+// whose ghosts hold -1.
+func exchangeAll(t *testing.T, d *decomp.Decomposition, width int, periodic [3]bool) ([]*decomp.Halo, [][]float64) {
+	t.Helper()
+
+	global := d.Global()
+	transports := decomp.NewChannelNetwork(d.Size())
+	halos := make([]*decomp.Halo, d.Size())
+	data := make([][]float64, d.Size())
+
+	for rank := range d.Size() {
+		h, err := decomp.NewHalo(d, rank, width, periodic)
+		if err != nil {
+			t.Fatalf("NewHalo failed: %v", err)
+		}
+		halos[rank] = h
+
+		data[rank] = make([]float64, h.Padded().Size())
+		for i := range data[rank] {
+			data[rank][i] = -1
+		}
+
+		b, view := h.Block(), h.Interior()
+		for i := range b.Shape[0] {
+			for j := range b.Shape[1] {
+				for k := range b.Shape[2] {
+					g := b.LocalToGlobal(i, j, k)
+					data[rank][view.Index(i, j, k)] = float64(grid.Index3D(g[0], g[1], g[2], global))
+				}
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, d.Size())
+	for rank := range d.Size() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[rank] = halos[rank].Exchange(transports[rank], data[rank])
+		}()
+	}
+	wg.Wait()
+
+	for rank, err := range errs {
+		if err != nil {
+			t.Fatalf("rank %d: Exchange failed: %v", rank, err)
+		}
+	}
+
+	return halos, data
+}
+
+func TestHalo_ExchangePeriodicIncludesCorners(t *testing.T) {
+	global := grid.NewShape2D(9, 8)
+	d, err := decomp.New(global, [3]int{3, 2, 1})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	const width = 2
+	halos, data := exchangeAll(t, d, width, [3]bool{true, true, false})
+
+	for rank, h := range halos {
+		b, padded := h.Block(), h.Padded()
+		stride := grid.RowMajorStride(padded)
+		for i := range padded[0] {
+			for j := range padded[1] {
+				gi := (b.Offset[0] + i - width + global[0]) % global[0]
+				gj := (b.Offset[1] + j - width + global[1]) % global[1]
+				want := float64(grid.Index3D(gi, gj, 0, global))
+				if got := data[rank][grid.Index(i, j, 0, stride)]; got != want {
+					t.Fatalf("rank %d padded (%d, %d) = %g, want %g", rank, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestHalo_ExchangeLeavesBoundaryGhosts(t *testing.T) {
+	d, err := decomp.NewSlab(grid.NewShape1D(12), 0, 4)
+	if err != nil {
+		t.Fatalf("NewSlab failed: %v", err)
+	}
+
+	_, data := exchangeAll(t, d, 1, [3]bool{})
+
+	first, last := data[0], data[d.Size()-1]
+	if first[0] != -1 || last[len(last)-1] != -1 {
+		t.Fatalf("boundary ghosts modified: %v ... %v", first, last)
+	}
+	if first[len(first)-1] != 3 || last[0] != 8 {
+		t.Fatalf("interior ghosts = %g, %g; want 3, 8", first[len(first)-1], last[0])
+	}
+}
+
+// Exchange followed by FillBoundary reproduces grid.Field.FillHalo on the
+// undecomposed field, ghosts at edges and corners included.
+func TestHalo_FillBoundaryMatchesField(t *testing.T) {
+	global := grid.NewShape3D(9, 8, 5)
+	d, err := decomp.New(global, [3]int{3, 2, 1})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	const width = 2
+	periodic := [3]bool{false, true, false}
+	bc := [3]grid.HaloBC{grid.HaloDirichlet, grid.HaloPeriodic, grid.HaloNeumann}
+
+	want := grid.NewField(global, width)
+	packed := make([]float64, global.Size())
+	for i := range packed {
+		packed[i] = float64(i)
+	}
+	want.SetInterior(packed)
+	want.FillHalo(bc)
+	wantStride := grid.RowMajorStride(want.Padded())
+
+	halos, data := exchangeAll(t, d, width, periodic)
+	for rank, h := range halos {
+		if err := h.FillBoundary(data[rank], bc); err != nil {
+			t.Fatalf("rank %d: FillBoundary failed: %v", rank, err)
+		}
+
+		b, padded := h.Block(), h.Padded()
+		stride := grid.RowMajorStride(padded)
+		for i := range padded[0] {
+			for j := range padded[1] {
+				for k := range padded[2] {
+					w := want.Data[grid.Index(b.Offset[0]+i, b.Offset[1]+j, b.Offset[2]+k, wantStride)]
+					if got := data[rank][grid.Index(i, j, k, stride)]; got != w {
+						t.Fatalf("rank %d padded (%d, %d, %d) = %g, want %g", rank, i, j, k, got, w)
+					}
+				}
+			}
+		}
+	}
+
+	var sErr *poisson.SizeError
+	if err := halos[0].FillBoundary(make([]float64, 3), bc); !errors.As(err, &sErr) {
+		t.Fatalf("expected SizeError, got %v", err)
+	}
+}
+
+func TestNewHalo_Invalid(t *testing.T) {
+	d, err := decomp.NewSlab(grid.NewShape2D(6, 4), 0, 3)
+	if err != nil {
+		t.Fatalf("NewSlab failed: %v", err)
+	}
+
+	var vErr *poisson.ValidationError
+	if _, err := decomp.NewHalo(d, 0, 3, [3]bool{}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for width > block, got %v", err)
+	}
+
+	h, err := decomp.NewHalo(d, 0, 1, [3]bool{})
+	if err != nil {
+		t.Fatalf("NewHalo failed: %v", err)
+	}
+	var sErr *poisson.SizeError
+	if err := h.Exchange(decomp.NewChannelNetwork(3)[0], make([]float64, 3)); !errors.As(err, &sErr) {
+		t.Fatalf("expected SizeError, got %v", err)
+	}
+}
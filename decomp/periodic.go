@@ -0,0 +1,207 @@
+package decomp
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Message tags used by PeriodicSolver transposes. Halo exchanges use tags
+// 0..5, so both can share a transport.
+const (
+	tagZToY = 16 + iota
+	tagYToX
+	tagXToY
+	tagYToZ
+)
+
+// PeriodicSolver solves (alpha - Δ) u = f on a periodic 3D grid distributed
+// over a p0×p1 process grid, using the same discrete Laplacian as
+// poisson.NewPlan3DPeriodic.
+//
+// Each rank holds one z-pencil of the input and output (complete z lines,
+// x split p0 ways, y split p1 ways; see Block). A solve transforms along z,
+// transposes to y-pencils, transforms along y, transposes to x-pencils,
+// transforms along x and divides by the eigenvalues, then runs the same
+// steps backwards. Only pencils are ever held in memory, so the global grid
+// may exceed the memory of any single rank. Transposes go through the
+// Transport, e.g. NewChannelNetwork in-process or a user-provided Send/Recv.
+//
+// For alpha = 0 the zero mode is dropped: the RHS mean is removed and the
+// solution has zero mean (poisson.NullspaceSubtractMean semantics).
+//
+// Every rank creates its own solver and all ranks must call Solve together.
+// A PeriodicSolver is not safe for concurrent use.
+type PeriodicSolver struct {
+	rank  int
+	alpha float64
+
+	// z, y and x are the pencil decompositions aligned with each axis.
+	z, y, x *Decomposition
+
+	eig [3][]float64
+	fft [3]*poisson.FFTPlan
+
+	zBuf, yBuf, xBuf []complex128
+	msgBuf           []float64
+}
+
+// NewPeriodicSolver creates the solver of rank for a global grid with
+// spacings h, split over p0×p1 ranks. alpha must be non-negative. Every axis
+// must have at least as many points as ranks splitting it in any of the
+// three pencil orientations (nx >= p0, ny >= p0 and p1, nz >= p1).
+func NewPeriodicSolver(global grid.Shape, h [3]float64, alpha float64, p0, p1, rank int) (*PeriodicSolver, error) {
+	for axis := range 3 {
+		if !(h[axis] > 0) || math.IsInf(h[axis], 0) {
+			return nil, poisson.ErrInvalidSpacing
+		}
+	}
+
+	if !(alpha >= 0) || math.IsInf(alpha, 0) {
+		return nil, &poisson.ValidationError{Field: "alpha", Message: "must be non-negative and finite"}
+	}
+
+	s := &PeriodicSolver{rank: rank, alpha: alpha}
+
+	var err error
+	if s.z, err = NewPencil(global, 2, p0, p1); err != nil {
+		return nil, fmt.Errorf("z pencils: %w", err)
+	}
+	if s.y, err = NewPencil(global, 1, p0, p1); err != nil {
+		return nil, fmt.Errorf("y pencils: %w", err)
+	}
+	if s.x, err = NewPencil(global, 0, p0, p1); err != nil {
+		return nil, fmt.Errorf("x pencils: %w", err)
+	}
+
+	if rank < 0 || rank >= s.z.Size() {
+		return nil, &poisson.ValidationError{Field: "rank", Message: fmt.Sprintf("must be in [0, %d)", s.z.Size())}
+	}
+
+	for axis := range 3 {
+		s.eig[axis] = fd.EigenvaluesPeriodic(global[axis], h[axis])
+		if s.fft[axis], err = poisson.NewFFTPlan(global[axis]); err != nil {
+			return nil, fmt.Errorf("axis %d: %w", axis, err)
+		}
+	}
+
+	s.zBuf = make([]complex128, s.z.Block(rank).Shape.Size())
+	s.yBuf = make([]complex128, s.y.Block(rank).Shape.Size())
+	s.xBuf = make([]complex128, s.x.Block(rank).Shape.Size())
+
+	maxMsg := 0
+	for _, d := range []*Decomposition{s.z, s.y, s.x} {
+		for q := range d.Size() {
+			maxMsg = max(maxMsg, d.Block(q).Shape.Size())
+		}
+	}
+	s.msgBuf = make([]float64, 2*maxMsg)
+
+	return s, nil
+}
+
+// Decomposition returns the z-pencil decomposition of the input and output.
+func (s *PeriodicSolver) Decomposition() *Decomposition {
+	return s.z
+}
+
+// Block returns the z-pencil owned by the solver's rank; dst and rhs of
+// Solve are this block in row-major order.
+func (s *PeriodicSolver) Block() Block {
+	return s.z.Block(s.rank)
+}
+
+// Solve computes the rank's z-pencil of the solution into dst from its
+// z-pencil of the RHS. dst and rhs may alias. t must be the transport of the
+// solver's rank.
+func (s *PeriodicSolver) Solve(t Transport, dst, rhs []float64) error {
+	if dst == nil || rhs == nil {
+		return poisson.ErrNilBuffer
+	}
+
+	if len(dst) != len(s.zBuf) || len(rhs) != len(s.zBuf) {
+		return poisson.ErrSizeMismatch
+	}
+
+	if t.Rank() != s.rank {
+		return &poisson.ValidationError{Field: "t", Message: fmt.Sprintf("transport rank %d, solver rank %d", t.Rank(), s.rank)}
+	}
+
+	for i, v := range rhs {
+		s.zBuf[i] = complex(v, 0)
+	}
+
+	if err := s.transformLines(s.zBuf, s.z, 2, false); err != nil {
+		return err
+	}
+	if err := redistribute(t, s.z, s.y, s.zBuf, s.yBuf, tagZToY, s.msgBuf); err != nil {
+		return fmt.Errorf("z to y transpose: %w", err)
+	}
+	if err := s.transformLines(s.yBuf, s.y, 1, false); err != nil {
+		return err
+	}
+	if err := redistribute(t, s.y, s.x, s.yBuf, s.xBuf, tagYToX, s.msgBuf); err != nil {
+		return fmt.Errorf("y to x transpose: %w", err)
+	}
+	if err := s.transformLines(s.xBuf, s.x, 0, false); err != nil {
+		return err
+	}
+
+	s.applyEigenvalues()
+
+	if err := s.transformLines(s.xBuf, s.x, 0, true); err != nil {
+		return err
+	}
+	if err := redistribute(t, s.x, s.y, s.xBuf, s.yBuf, tagXToY, s.msgBuf); err != nil {
+		return fmt.Errorf("x to y transpose: %w", err)
+	}
+	if err := s.transformLines(s.yBuf, s.y, 1, true); err != nil {
+		return err
+	}
+	if err := redistribute(t, s.y, s.z, s.yBuf, s.zBuf, tagYToZ, s.msgBuf); err != nil {
+		return fmt.Errorf("y to z transpose: %w", err)
+	}
+	if err := s.transformLines(s.zBuf, s.z, 2, true); err != nil {
+		return err
+	}
+
+	for i, v := range s.zBuf {
+		dst[i] = real(v)
+	}
+
+	return nil
+}
+
+func (s *PeriodicSolver) transformLines(buf []complex128, d *Decomposition, axis int, inverse bool) error {
+	if err := s.fft[axis].TransformLines(buf, d.Block(s.rank).Shape, axis, inverse); err != nil {
+		if inverse {
+			return fmt.Errorf("inverse axis %d: %w", axis, err)
+		}
+		return fmt.Errorf("forward axis %d: %w", axis, err)
+	}
+
+	return nil
+}
+
+// applyEigenvalues divides the x-pencil coefficients by alpha + λ.
+func (s *PeriodicSolver) applyEigenvalues() {
+	b := s.x.Block(s.rank)
+	idx := 0
+	for i := range b.Shape[0] {
+		for j := range b.Shape[1] {
+			for k := range b.Shape[2] {
+				g := b.LocalToGlobal(i, j, k)
+				denom := s.alpha + s.eig[0][g[0]] + s.eig[1][g[1]] + s.eig[2][g[2]]
+				if denom == 0 {
+					s.xBuf[idx] = 0
+				} else {
+					s.xBuf[idx] /= complex(denom, 0)
+				}
+				idx++
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package decomp_test
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/decomp"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const distributedTol = 1e-10
+
+// solveDistributed scatters rhs to the z-pencils of p0×p1 ranks, solves on
+// all ranks concurrently and gathers the global solution.
+func solveDistributed(
+	t *testing.T, global grid.Shape, h [3]float64, alpha float64, p0, p1 int, rhs []float64,
+) []float64 {
+	t.Helper()
+
+	size := p0 * p1
+	transports := decomp.NewChannelNetwork(size)
+	sol := make([]float64, global.Size())
+
+	var wg sync.WaitGroup
+	errs := make([]error, size)
+	blocks := make([]decomp.Block, size)
+	locals := make([][]float64, size)
+	for rank := range size {
+		solver, err := decomp.NewPeriodicSolver(global, h, alpha, p0, p1, rank)
+		if err != nil {
+			t.Fatalf("NewPeriodicSolver failed: %v", err)
+		}
+
+		blocks[rank] = solver.Block()
+		local := make([]float64, blocks[rank].Shape.Size())
+		forEachLocal(blocks[rank], func(l int, g [3]int) {
+			local[l] = rhs[grid.Index3D(g[0], g[1], g[2], global)]
+		})
+		locals[rank] = local
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[rank] = solver.Solve(transports[rank], local, local)
+		}()
+	}
+	wg.Wait()
+
+	for rank, err := range errs {
+		if err != nil {
+			t.Fatalf("rank %d: Solve failed: %v", rank, err)
+		}
+
+		forEachLocal(blocks[rank], func(l int, g [3]int) {
+			sol[grid.Index3D(g[0], g[1], g[2], global)] = locals[rank][l]
+		})
+	}
+
+	return sol
+}
+
+func forEachLocal(b decomp.Block, fn func(local int, g [3]int)) {
+	idx := 0
+	for i := range b.Shape[0] {
+		for j := range b.Shape[1] {
+			for k := range b.Shape[2] {
+				fn(idx, b.LocalToGlobal(i, j, k))
+				idx++
+			}
+		}
+	}
+}
+
+func TestPeriodicSolver_MatchesSerialPlan(t *testing.T) {
+	global := grid.NewShape3D(8, 6, 5)
+	h := [3]float64{0.1, 0.2, 0.3}
+
+	rhs := make([]float64, global.Size())
+	for idx := range rhs {
+		rhs[idx] = math.Sin(0.37*float64(idx)) + 0.25
+	}
+
+	tests := []struct {
+		name   string
+		alpha  float64
+		p0, p1 int
+	}{
+		{"Poisson2x3", 0, 2, 3},
+		{"Helmholtz3x2", 1.5, 3, 2},
+		{"Serial", 0.5, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := poisson.NewHelmholtzPlan(3, global[:], h[:],
+				[]poisson.BCType{poisson.Periodic, poisson.Periodic, poisson.Periodic}, tt.alpha,
+				poisson.WithNullspace(poisson.NullspaceSubtractMean))
+			if err != nil {
+				t.Fatalf("NewHelmholtzPlan failed: %v", err)
+			}
+
+			want := make([]float64, len(rhs))
+			if err := plan.Solve(want, rhs); err != nil {
+				t.Fatalf("Solve failed: %v", err)
+			}
+
+			got := solveDistributed(t, global, h, tt.alpha, tt.p0, tt.p1, rhs)
+			for idx := range want {
+				if diff := math.Abs(got[idx] - want[idx]); diff > distributedTol {
+					t.Fatalf("index %d differs by %g", idx, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPeriodicSolver_Invalid(t *testing.T) {
+	global := grid.NewShape3D(4, 4, 2)
+	h := [3]float64{1, 1, 1}
+
+	var vErr *poisson.ValidationError
+	if _, err := decomp.NewPeriodicSolver(global, h, -1, 1, 1, 0); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for negative alpha, got %v", err)
+	}
+	if _, err := decomp.NewPeriodicSolver(global, h, 0, 2, 3, 0); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for nz < p1, got %v", err)
+	}
+	if _, err := decomp.NewPeriodicSolver(global, h, 0, 2, 2, 4); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for rank out of range, got %v", err)
+	}
+	if _, err := decomp.NewPeriodicSolver(global, [3]float64{1, 0, 1}, 0, 1, 1, 0); !errors.Is(err, poisson.ErrInvalidSpacing) {
+		t.Fatalf("expected ErrInvalidSpacing, got %v", err)
+	}
+}
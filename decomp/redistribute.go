@@ -0,0 +1,118 @@
+package decomp
+
+import (
+	"fmt"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
+// redistribute moves a complex array distributed over the blocks of src into
+// the blocks of dst (a transpose when both are pencil decompositions). Both
+// decompositions must cover the same global grid with the same ranks. in and
+// out are the calling rank's row-major local blocks. Every rank must call
+// redistribute with the same decompositions and tag.
+//
+// Complex values travel as interleaved (re, im) float64 pairs. The rank's
+// own part is copied directly, without going through the transport.
+func redistribute(t Transport, src, dst *Decomposition, in, out []complex128, tag int, buf []float64) error {
+	rank := t.Rank()
+	srcBlock, dstBlock := src.Block(rank), dst.Block(rank)
+
+	for q := range src.Size() {
+		lo, hi, ok := intersect(srcBlock, dst.Block(q))
+		if !ok {
+			continue
+		}
+
+		if q == rank {
+			copyRegion(out, dstBlock, in, srcBlock, lo, hi)
+			continue
+		}
+
+		msg := packRegion(buf, in, srcBlock, lo, hi)
+		if err := t.Send(q, tag, msg); err != nil {
+			return fmt.Errorf("send to rank %d: %w", q, err)
+		}
+	}
+
+	for q := range src.Size() {
+		if q == rank {
+			continue
+		}
+
+		lo, hi, ok := intersect(src.Block(q), dstBlock)
+		if !ok {
+			continue
+		}
+
+		msg := buf[:2*regionSize(lo, hi)]
+		if err := t.Recv(q, tag, msg); err != nil {
+			return fmt.Errorf("recv from rank %d: %w", q, err)
+		}
+		unpackRegion(out, dstBlock, msg, lo, hi)
+	}
+
+	return nil
+}
+
+// intersect returns the global index range [lo, hi) shared by a and b.
+func intersect(a, b Block) (lo, hi [3]int, ok bool) {
+	for axis := range 3 {
+		lo[axis] = max(a.Offset[axis], b.Offset[axis])
+		hi[axis] = min(a.Offset[axis]+a.Shape[axis], b.Offset[axis]+b.Shape[axis])
+		if lo[axis] >= hi[axis] {
+			return lo, hi, false
+		}
+	}
+
+	return lo, hi, true
+}
+
+func regionSize(lo, hi [3]int) int {
+	return (hi[0] - lo[0]) * (hi[1] - lo[1]) * (hi[2] - lo[2])
+}
+
+// forEachPoint calls fn with the local index in block b of every global
+// point of [lo, hi), in row-major order.
+func forEachPoint(b Block, lo, hi [3]int, fn func(local int)) {
+	stride := grid.RowMajorStride(b.Shape)
+	for i := lo[0]; i < hi[0]; i++ {
+		for j := lo[1]; j < hi[1]; j++ {
+			off := grid.Index(i-b.Offset[0], j-b.Offset[1], lo[2]-b.Offset[2], stride)
+			for k := lo[2]; k < hi[2]; k++ {
+				fn(off)
+				off++
+			}
+		}
+	}
+}
+
+func packRegion(buf []float64, in []complex128, b Block, lo, hi [3]int) []float64 {
+	buf = buf[:0]
+	forEachPoint(b, lo, hi, func(local int) {
+		buf = append(buf, real(in[local]), imag(in[local]))
+	})
+
+	return buf
+}
+
+func unpackRegion(out []complex128, b Block, msg []float64, lo, hi [3]int) {
+	idx := 0
+	forEachPoint(b, lo, hi, func(local int) {
+		out[local] = complex(msg[idx], msg[idx+1])
+		idx += 2
+	})
+}
+
+func copyRegion(out []complex128, outBlock Block, in []complex128, inBlock Block, lo, hi [3]int) {
+	inStride := grid.RowMajorStride(inBlock.Shape)
+	outStride := grid.RowMajorStride(outBlock.Shape)
+	n := hi[2] - lo[2]
+	for i := lo[0]; i < hi[0]; i++ {
+		for j := lo[1]; j < hi[1]; j++ {
+			src := grid.Index(i-inBlock.Offset[0], j-inBlock.Offset[1], lo[2]-inBlock.Offset[2], inStride)
+			dst := grid.Index(i-outBlock.Offset[0], j-outBlock.Offset[1], lo[2]-outBlock.Offset[2], outStride)
+			copy(out[dst:dst+n], in[src:src+n])
+		}
+	}
+}
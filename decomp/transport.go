@@ -0,0 +1,106 @@
+package decomp
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Transport moves float64 messages between ranks. Implementations wrap MPI,
+// sockets, or in-process channels.
+//
+// Send must not wait for the matching Recv (it is buffered from the caller's
+// point of view), and the caller may reuse data once Send returns. Recv
+// blocks until a message from rank from with the given tag arrives and
+// copies it into data, whose length must match the message. Messages with
+// the same (from, to, tag) are delivered in order.
+type Transport interface {
+	// Rank returns the rank this transport sends from.
+	Rank() int
+
+	// Send sends data to rank to.
+	Send(to, tag int, data []float64) error
+
+	// Recv receives a message from rank from into data.
+	Recv(from, tag int, data []float64) error
+}
+
+// NewChannelNetwork returns in-process transports for n ranks; element r is
+// the transport of rank r. Each transport must be used by one goroutine at a
+// time.
+func NewChannelNetwork(n int) []Transport {
+	net := &channelNetwork{queues: make(map[channelKey][][]float64)}
+	net.cond = sync.NewCond(&net.mu)
+
+	transports := make([]Transport, n)
+	for rank := range n {
+		transports[rank] = &channelTransport{net: net, rank: rank, size: n}
+	}
+
+	return transports
+}
+
+type channelKey struct {
+	from, to, tag int
+}
+
+type channelNetwork struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues map[channelKey][][]float64
+}
+
+type channelTransport struct {
+	net  *channelNetwork
+	rank int
+	size int
+}
+
+func (t *channelTransport) Rank() int {
+	return t.rank
+}
+
+func (t *channelTransport) Send(to, tag int, data []float64) error {
+	if to < 0 || to >= t.size {
+		return &poisson.ValidationError{Field: "to", Message: fmt.Sprintf("rank %d outside [0, %d)", to, t.size)}
+	}
+
+	msg := append([]float64(nil), data...)
+	key := channelKey{from: t.rank, to: to, tag: tag}
+
+	t.net.mu.Lock()
+	t.net.queues[key] = append(t.net.queues[key], msg)
+	t.net.mu.Unlock()
+	t.net.cond.Broadcast()
+
+	return nil
+}
+
+func (t *channelTransport) Recv(from, tag int, data []float64) error {
+	if from < 0 || from >= t.size {
+		return &poisson.ValidationError{Field: "from", Message: fmt.Sprintf("rank %d outside [0, %d)", from, t.size)}
+	}
+
+	key := channelKey{from: from, to: t.rank, tag: tag}
+
+	t.net.mu.Lock()
+	for len(t.net.queues[key]) == 0 {
+		t.net.cond.Wait()
+	}
+	msg := t.net.queues[key][0]
+	t.net.queues[key] = t.net.queues[key][1:]
+	t.net.mu.Unlock()
+
+	if len(msg) != len(data) {
+		return &poisson.SizeError{
+			Expected: len(data),
+			Got:      len(msg),
+			Context:  fmt.Sprintf("message from rank %d with tag %d", from, tag),
+		}
+	}
+
+	copy(data, msg)
+
+	return nil
+}
@@ -27,6 +27,12 @@
 //   - r2r: Real-to-real transforms (DST/DCT) via FFT
 //   - grid: Grid shapes, strides, and indexing utilities
 //   - fd: Finite difference operators and eigenvalues
+//   - fd/gonumfd: Optional gonum adapters for fd matrices and plan operators
+//   - scenario: Declarative diffusion simulation runner
+//   - spectral: Spectral derivatives, gradients and Laplacians
+//   - decomp: Domain decomposition and halo exchange
+//   - homogenize: Effective conductivity tensors of periodic microstructures
+//   - par: Parallel-loop primitives shared by the packages above
 //
 // # Example
 //
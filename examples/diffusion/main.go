@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/MeKo-Tech/algo-pde/fd"
 	"github.com/MeKo-Tech/algo-pde/poisson"
 )
 
@@ -47,18 +48,5 @@ func main() {
 		panic(err)
 	}
 
-	fmt.Printf("max |u^{n+1}|: %.6f\n", maxAbs(u1))
-}
-
-func maxAbs(values []float64) float64 {
-	max := 0.0
-	for _, v := range values {
-		if v < 0 {
-			v = -v
-		}
-		if v > max {
-			max = v
-		}
-	}
-	return max
+	fmt.Printf("max |u^{n+1}|: %.6f\n", fd.NormLinf(u1))
 }
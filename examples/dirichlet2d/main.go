@@ -34,10 +34,9 @@ func main() {
 	rhs := make([]float64, nx*ny)
 	uExact := make([]float64, nx*ny)
 
-	for i := 0; i < nx; i++ {
-		x := float64(i+1) * hx
-		for j := 0; j < ny; j++ {
-			y := float64(j+1) * hy
+	xs, ys := plan.Coordinates(0), plan.Coordinates(1)
+	for i, x := range xs {
+		for j, y := range ys {
 			val := math.Sin(math.Pi*x) * math.Sin(math.Pi*y)
 			uExact[i*ny+j] = val
 			rhs[i*ny+j] = 2.0 * math.Pi * math.Pi * val
@@ -49,12 +48,6 @@ func main() {
 		panic(err)
 	}
 
-	maxErr := 0.0
-	for i := range u {
-		diff := math.Abs(u[i] - uExact[i])
-		if diff > maxErr {
-			maxErr = diff
-		}
-	}
+	maxErr := poisson.MaxAbsDiff(u, uExact)
 	fmt.Printf("Max Error: %.3e\n", maxErr)
 }
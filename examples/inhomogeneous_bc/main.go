@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"math"
 
 	"github.com/MeKo-Tech/algo-pde/fd"
 	"github.com/MeKo-Tech/algo-pde/grid"
@@ -68,21 +67,5 @@ func main() {
 		panic(err)
 	}
 
-	fmt.Printf("max error: %.3e\n", maxAbsDiff(got, u))
-}
-
-func maxAbsDiff(a, b []float64) float64 {
-	if len(a) != len(b) {
-		return math.Inf(1)
-	}
-
-	max := 0.0
-	for i := range a {
-		diff := math.Abs(a[i] - b[i])
-		if diff > max {
-			max = diff
-		}
-	}
-
-	return max
+	fmt.Printf("max error: %.3e\n", poisson.MaxAbsDiff(got, u))
 }
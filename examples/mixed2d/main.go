@@ -34,10 +34,9 @@ func main() {
 	rhs := make([]float64, nx*ny)
 	uExact := make([]float64, nx*ny)
 
-	for i := 0; i < nx; i++ {
-		x := float64(i) * hx
-		for j := 0; j < ny; j++ {
-			y := float64(j+1) * hy
+	xs, ys := plan.Coordinates(0), plan.Coordinates(1)
+	for i, x := range xs {
+		for j, y := range ys {
 			val := math.Sin(2.0*math.Pi*x) * math.Sin(math.Pi*y)
 			uExact[i*ny+j] = val
 			rhs[i*ny+j] = 5.0 * math.Pi * math.Pi * val
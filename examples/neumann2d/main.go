@@ -35,10 +35,9 @@ func main() {
 	rhs := make([]float64, nx*ny)
 	uExact := make([]float64, nx*ny)
 
-	for i := 0; i < nx; i++ {
-		x := (float64(i) + 0.5) * hx
-		for j := 0; j < ny; j++ {
-			y := (float64(j) + 0.5) * hy
+	xs, ys := plan.Coordinates(0), plan.Coordinates(1)
+	for i, x := range xs {
+		for j, y := range ys {
 			val := math.Cos(math.Pi*x) * math.Cos(math.Pi*y)
 			uExact[i*ny+j] = val
 			rhs[i*ny+j] = 2.0 * math.Pi * math.Pi * val
@@ -55,7 +54,7 @@ func main() {
 		[]int{nx, ny},
 		[]float64{hx, hy},
 		[]poisson.BCType{poisson.Neumann, poisson.Neumann},
-		poisson.WithSubtractMean(),
+		poisson.WithNullspace(poisson.NullspaceSubtractMean),
 	)
 	if err != nil {
 		panic(err)
@@ -34,10 +34,10 @@ func main() {
 	// 4. Solve
 	u := make([]float64, nx)
 
-	// We use WithSubtractMean() to handle the zero mode (singular matrix for periodic Poisson)
+	// We use WithNullspace(NullspaceSubtractMean) to handle the zero mode (singular matrix for periodic Poisson)
 	// automatically by subtracting the mean from RHS (which should be 0 anyway for sin)
 	// and setting the mean of the solution to 0.
-	plan, err = poisson.NewPlan1DPeriodic(nx, hx, poisson.WithSubtractMean())
+	plan, err = poisson.NewPlan1DPeriodic(nx, hx, poisson.WithNullspace(poisson.NullspaceSubtractMean))
 	if err != nil {
 		panic(err)
 	}
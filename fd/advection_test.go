@@ -0,0 +1,166 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func constantVelocity(n int, v float64) []float64 {
+	vel := make([]float64, n)
+	for i := range vel {
+		vel[i] = v
+	}
+
+	return vel
+}
+
+// Centered advection with a constant velocity is the velocity times Grad1D.
+func TestAdvect1DCenteredMatchesGrad(t *testing.T) {
+	const n, c = 10, -1.5
+
+	for _, bc := range []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann} {
+		u := testField(n)
+		grad := make([]float64, n)
+		Grad1D(grad, u, 0.1, bc)
+
+		got := make([]float64, n)
+		Advect1D(got, u, constantVelocity(n, c), 0.1, bc, Centered)
+		for i := range n {
+			if math.Abs(got[i]-c*grad[i]) > 1e-12 {
+				t.Fatalf("%v i=%d: got %v want %v", bc, i, got[i], c*grad[i])
+			}
+		}
+	}
+}
+
+// The upwind schemes converge at first and second order for a periodic
+// mode and either sign of the velocity.
+func TestAdvect1DUpwindOrder(t *testing.T) {
+	errorAt := func(n int, v float64, scheme AdvectionScheme) float64 {
+		h := 1.0 / float64(n)
+		u := make([]float64, n)
+		want := make([]float64, n)
+		for i := range n {
+			x := float64(i) * h
+			u[i] = math.Sin(2 * math.Pi * x)
+			want[i] = v * 2 * math.Pi * math.Cos(2*math.Pi*x)
+		}
+
+		got := make([]float64, n)
+		Advect1D(got, u, constantVelocity(n, v), h, poisson.Periodic, scheme)
+
+		return ErrorLinf(got, want)
+	}
+
+	for _, v := range []float64{1, -1} {
+		for _, tc := range []struct {
+			scheme   AdvectionScheme
+			minRatio float64
+		}{
+			{Upwind, 1.8},
+			{Upwind2, 3.5},
+		} {
+			if ratio := errorAt(32, v, tc.scheme) / errorAt(64, v, tc.scheme); ratio < tc.minRatio {
+				t.Errorf("%v v=%v: error ratio %v, want at least %v", tc.scheme, v, ratio, tc.minRatio)
+			}
+		}
+	}
+}
+
+// Upwinding picks the side the flow comes from: with Dirichlet inflow the
+// first point sees the zero ghost, and a downstream point is unaffected by
+// its downstream neighbor.
+func TestAdvect1DUpwindDirection(t *testing.T) {
+	u := []float64{1, 2, 4, 8}
+	got := make([]float64, 4)
+
+	Advect1D(got, u, constantVelocity(4, 2), 0.5, poisson.Dirichlet, Upwind)
+	want := []float64{4, 4, 8, 16}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("v>0 i=%d: got %v want %v", i, got[i], want[i])
+		}
+	}
+
+	Advect1D(got, u, constantVelocity(4, -2), 0.5, poisson.Neumann, Upwind)
+	want = []float64{-4, -8, -16, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("v<0 i=%d: got %v want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// The 2D and 3D operators sum the 1D operators along each axis.
+func TestAdvect3DMatchesLines(t *testing.T) {
+	nx, ny, nz := 4, 5, 6
+	shape := grid.NewShape3D(nx, ny, nz)
+	h := [3]float64{0.3, 0.2, 0.1}
+	bc := [3]poisson.BCType{poisson.Neumann, poisson.Periodic, poisson.Dirichlet}
+
+	total := shape.Size()
+	u := testField(total)
+	vel := [3][]float64{coefficientField(total), make([]float64, total), make([]float64, total)}
+	for i := range total {
+		vel[1][i] = math.Cos(0.7 * float64(i))
+		vel[2][i] = -vel[0][i] + 1.2
+	}
+
+	for _, scheme := range []AdvectionScheme{Upwind, Centered, Upwind2} {
+		got := make([]float64, total)
+		Advect3D(got, u, vel[0], vel[1], vel[2], shape, h, bc, scheme)
+
+		want := make([]float64, total)
+		n := [3]int{nx, ny, nz}
+		stride := grid.RowMajorStride(shape)
+		for axis := range 3 {
+			line := make([]float64, n[axis])
+			v := make([]float64, n[axis])
+			out := make([]float64, n[axis])
+
+			for p := range total {
+				if (p/stride[axis])%n[axis] != 0 {
+					continue
+				}
+
+				for i := range n[axis] {
+					line[i] = u[p+i*stride[axis]]
+					v[i] = vel[axis][p+i*stride[axis]]
+				}
+
+				Advect1D(out, line, v, h[axis], bc[axis], scheme)
+				for i := range n[axis] {
+					want[p+i*stride[axis]] += out[i]
+				}
+			}
+		}
+
+		for p := range total {
+			if math.Abs(got[p]-want[p]) > 1e-12 {
+				t.Fatalf("%v p=%d: got %v want %v", scheme, p, got[p], want[p])
+			}
+		}
+
+		got2 := make([]float64, nx*ny)
+		Advect2D(got2, u[:nx*ny], vel[0][:nx*ny], vel[1][:nx*ny], grid.NewShape2D(nx, ny), [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]}, scheme)
+		Advect2D(u[:nx*ny], u[:nx*ny], vel[0][:nx*ny], vel[1][:nx*ny], grid.NewShape2D(nx, ny), [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]}, scheme)
+		for p := range nx * ny {
+			if u[p] != got2[p] {
+				t.Fatalf("%v 2D in-place p=%d: got %v want %v", scheme, p, u[p], got2[p])
+			}
+		}
+		copy(u, testField(total))
+	}
+}
+
+func TestCourantNumber(t *testing.T) {
+	vx := []float64{1, -3, 2}
+	vy := []float64{0.5, 0, -1}
+
+	if got := CourantNumber(0.01, []float64{0.1, 0.05}, vx, vy); math.Abs(got-0.5) > 1e-15 {
+		t.Errorf("CourantNumber = %v, want 0.5", got)
+	}
+}
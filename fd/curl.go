@@ -0,0 +1,102 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Curl2D writes the scalar curl ∂vy/∂x - ∂vx/∂y of the collocated vector
+// field (vx, vy) into dst, using the centered differences and per-axis
+// boundary handling of Grad2D. As the per-axis differences commute,
+// Curl2D of a Grad2D field vanishes for every combination of boundary
+// conditions. It is safe to call with dst == vx or dst == vy.
+func Curl2D(dst, vx, vy []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	if nx == 0 || ny == 0 {
+		return
+	}
+
+	total := nx * ny
+	if len(dst) != total || len(vx) != total || len(vy) != total {
+		return
+	}
+
+	if aliases(dst, vx) {
+		vx = append([]float64(nil), vx...)
+	}
+	if aliases(dst, vy) {
+		vy = append([]float64(nil), vy...)
+	}
+
+	inv2Hx := 0.5 / h[0]
+	inv2Hy := 0.5 / h[1]
+
+	for i := range nx {
+		for j := range ny {
+			idx := i*ny + j
+
+			left, right := neighbors(vy, idx, i, nx, ny, bc[0])
+			down, up := neighbors(vx, idx, j, ny, 1, bc[1])
+			dst[idx] = (right-left)*inv2Hx - (up-down)*inv2Hy
+		}
+	}
+}
+
+// Curl3D writes the curl
+//
+//	(∂vz/∂y - ∂vy/∂z, ∂vx/∂z - ∂vz/∂x, ∂vy/∂x - ∂vx/∂y)
+//
+// of the collocated vector field (vx, vy, vz) into (cx, cy, cz), using the
+// centered differences and per-axis boundary handling of Grad3D. Curl3D of
+// a Grad3D field and Div3D of a Curl3D field vanish for every combination
+// of boundary conditions. It is safe to call with outputs aliasing inputs.
+func Curl3D(cx, cy, cz, vx, vy, vz []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	nz := shape[2]
+	if nx == 0 || ny == 0 || nz == 0 {
+		return
+	}
+
+	total := nx * ny * nz
+	for _, s := range [][]float64{cx, cy, cz, vx, vy, vz} {
+		if len(s) != total {
+			return
+		}
+	}
+
+	if aliases(vx, cx, cy, cz) {
+		vx = append([]float64(nil), vx...)
+	}
+	if aliases(vy, cx, cy, cz) {
+		vy = append([]float64(nil), vy...)
+	}
+	if aliases(vz, cx, cy, cz) {
+		vz = append([]float64(nil), vz...)
+	}
+
+	inv2Hx := 0.5 / h[0]
+	inv2Hy := 0.5 / h[1]
+	inv2Hz := 0.5 / h[2]
+
+	plane := ny * nz
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				idx := i*plane + j*nz + k
+
+				vyLeft, vyRight := neighbors(vy, idx, i, nx, plane, bc[0])
+				vzLeft, vzRight := neighbors(vz, idx, i, nx, plane, bc[0])
+				vxDown, vxUp := neighbors(vx, idx, j, ny, nz, bc[1])
+				vzDown, vzUp := neighbors(vz, idx, j, ny, nz, bc[1])
+				vxBack, vxFront := neighbors(vx, idx, k, nz, 1, bc[2])
+				vyBack, vyFront := neighbors(vy, idx, k, nz, 1, bc[2])
+
+				cx[idx] = (vzUp-vzDown)*inv2Hy - (vyFront-vyBack)*inv2Hz
+				cy[idx] = (vxFront-vxBack)*inv2Hz - (vzRight-vzLeft)*inv2Hx
+				cz[idx] = (vyRight-vyLeft)*inv2Hx - (vxUp-vxDown)*inv2Hy
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestCurl2DOfGradientVanishes(t *testing.T) {
+	nx, ny := 7, 6
+	shape := grid.NewShape2D(nx, ny)
+	h := [2]float64{0.3, 0.2}
+
+	for _, bc := range [][2]poisson.BCType{
+		{poisson.Periodic, poisson.Periodic},
+		{poisson.Dirichlet, poisson.Neumann},
+		{poisson.Neumann, poisson.Dirichlet},
+	} {
+		u := testField(nx * ny)
+		gx := make([]float64, nx*ny)
+		gy := make([]float64, nx*ny)
+		Grad2D(gx, gy, u, shape, h, bc)
+
+		Curl2D(gx, gx, gy, shape, h, bc)
+		for i, w := range gx {
+			if math.Abs(w) > 1e-10 {
+				t.Fatalf("%v i=%d: curl = %v, want 0", bc, i, w)
+			}
+		}
+	}
+}
+
+// The curl of (0, u) is ∂u/∂x and that of (u, 0) is -∂u/∂y.
+func TestCurl2DMatchesGradComponents(t *testing.T) {
+	nx, ny := 5, 8
+	shape := grid.NewShape2D(nx, ny)
+	h := [2]float64{0.3, 0.2}
+	bc := [2]poisson.BCType{poisson.Neumann, poisson.Periodic}
+
+	u := testField(nx * ny)
+	zero := make([]float64, nx*ny)
+	gx := make([]float64, nx*ny)
+	gy := make([]float64, nx*ny)
+	Grad2D(gx, gy, u, shape, h, bc)
+
+	curl := make([]float64, nx*ny)
+	Curl2D(curl, zero, u, shape, h, bc)
+	for i := range curl {
+		if math.Abs(curl[i]-gx[i]) > 1e-12 {
+			t.Fatalf("(0, u) i=%d: got %v want %v", i, curl[i], gx[i])
+		}
+	}
+
+	Curl2D(curl, u, zero, shape, h, bc)
+	for i := range curl {
+		if math.Abs(curl[i]+gy[i]) > 1e-12 {
+			t.Fatalf("(u, 0) i=%d: got %v want %v", i, curl[i], -gy[i])
+		}
+	}
+}
+
+func TestCurl3DIdentities(t *testing.T) {
+	nx, ny, nz := 4, 5, 6
+	shape := grid.NewShape3D(nx, ny, nz)
+	h := [3]float64{0.3, 0.2, 0.5}
+	bc := [3]poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+	total := nx * ny * nz
+
+	// curl(grad u) = 0, computed in place.
+	u := testField(total)
+	gx := make([]float64, total)
+	gy := make([]float64, total)
+	gz := make([]float64, total)
+	Grad3D(gx, gy, gz, u, shape, h, bc)
+	Curl3D(gx, gy, gz, gx, gy, gz, shape, h, bc)
+
+	for i := range total {
+		if norm := math.Abs(gx[i]) + math.Abs(gy[i]) + math.Abs(gz[i]); norm > 1e-10 {
+			t.Fatalf("curl(grad u) at %d = (%v, %v, %v), want 0", i, gx[i], gy[i], gz[i])
+		}
+	}
+
+	// div(curl v) = 0.
+	vx := testField(total)
+	vy := make([]float64, total)
+	vz := make([]float64, total)
+	for i := range total {
+		vy[i] = math.Cos(0.3 * float64(i))
+		vz[i] = float64(i%7) * 0.2
+	}
+
+	cx := make([]float64, total)
+	cy := make([]float64, total)
+	cz := make([]float64, total)
+	Curl3D(cx, cy, cz, vx, vy, vz, shape, h, bc)
+
+	div := make([]float64, total)
+	Div3D(div, cx, cy, cz, shape, h, bc)
+	for i, d := range div {
+		if math.Abs(d) > 1e-10 {
+			t.Fatalf("div(curl v) at %d = %v, want 0", i, d)
+		}
+	}
+}
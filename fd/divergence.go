@@ -0,0 +1,182 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Div2D writes the centered-difference divergence ∂vx/∂x + ∂vy/∂y of the
+// collocated vector field (vx, vy) into dst, with per-axis boundary handling
+// set by bc as in Grad2D. It is safe to call with dst == vx or dst == vy.
+//
+// Collocated differences pair with the wide stencil -Div2D(Grad2D(u)), not
+// with the compact Laplacian of Apply2D and the solver; use DivMAC2D for a
+// right-hand side that is exactly compatible with the solver.
+func Div2D(dst, vx, vy []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	if nx == 0 || ny == 0 {
+		return
+	}
+
+	total := nx * ny
+	if len(dst) != total || len(vx) != total || len(vy) != total {
+		return
+	}
+
+	if aliases(dst, vx) {
+		vx = append([]float64(nil), vx...)
+	}
+	if aliases(dst, vy) {
+		vy = append([]float64(nil), vy...)
+	}
+
+	inv2Hx := 0.5 / h[0]
+	inv2Hy := 0.5 / h[1]
+
+	for i := range nx {
+		for j := range ny {
+			idx := i*ny + j
+
+			left, right := neighbors(vx, idx, i, nx, ny, bc[0])
+			down, up := neighbors(vy, idx, j, ny, 1, bc[1])
+			dst[idx] = (right-left)*inv2Hx + (up-down)*inv2Hy
+		}
+	}
+}
+
+// Div3D writes the centered-difference divergence of the collocated vector
+// field (vx, vy, vz) into dst, with per-axis boundary handling set by bc as
+// in Grad3D. It is safe to call with dst equal to any component. See Div2D
+// for the pairing with the Laplacian.
+func Div3D(dst, vx, vy, vz []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	nz := shape[2]
+	if nx == 0 || ny == 0 || nz == 0 {
+		return
+	}
+
+	total := nx * ny * nz
+	if len(dst) != total || len(vx) != total || len(vy) != total || len(vz) != total {
+		return
+	}
+
+	if aliases(dst, vx) {
+		vx = append([]float64(nil), vx...)
+	}
+	if aliases(dst, vy) {
+		vy = append([]float64(nil), vy...)
+	}
+	if aliases(dst, vz) {
+		vz = append([]float64(nil), vz...)
+	}
+
+	inv2Hx := 0.5 / h[0]
+	inv2Hy := 0.5 / h[1]
+	inv2Hz := 0.5 / h[2]
+
+	plane := ny * nz
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				idx := i*plane + j*nz + k
+
+				left, right := neighbors(vx, idx, i, nx, plane, bc[0])
+				down, up := neighbors(vy, idx, j, ny, nz, bc[1])
+				back, front := neighbors(vz, idx, k, nz, 1, bc[2])
+				dst[idx] = (right-left)*inv2Hx + (up-down)*inv2Hy + (front-back)*inv2Hz
+			}
+		}
+	}
+}
+
+// Faces returns the number of staggered (MAC) faces along an axis of n grid
+// points with boundary condition bc: n for Periodic, where the last face
+// wraps onto the first, and n+1 otherwise.
+//
+// Face f lies halfway between points f-1 and f, so faces 0 and n are the
+// boundary faces: between the Dirichlet ghost zero and the first point, or
+// on the Neumann wall of the first cell.
+func Faces(n int, bc poisson.BCType) int {
+	if bc == poisson.Periodic {
+		return n
+	}
+
+	return n + 1
+}
+
+// DivMAC2D writes the divergence of the staggered (MAC) vector field
+// (vx, vy) into dst: at point (i, j),
+//
+//	(vx[i+1, j] - vx[i, j]) / hx + (vy[i, j+1] - vy[i, j]) / hy.
+//
+// vx holds the x-faces in row-major shape (Faces(nx, bc[0]), ny) and vy the
+// y-faces in shape (nx, Faces(ny, bc[1])). For the face gradient
+// (u[i] - u[i-1]) / h, with the ghost values of Apply2D at the boundary
+// faces, -DivMAC2D is exactly the Laplacian stencil of Apply2D and the
+// solver, so the projection of a MAC velocity field solves a consistent
+// problem. With a Neumann axis the boundary faces must carry zero normal
+// velocity to match the homogeneous condition; a net flux through them
+// leaves a right-hand side without mean zero. dst must not overlap the
+// face arrays.
+func DivMAC2D(dst, vx, vy []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	if nx == 0 || ny == 0 {
+		return
+	}
+
+	fx := Faces(nx, bc[0])
+	fy := Faces(ny, bc[1])
+	if len(dst) != nx*ny || len(vx) != fx*ny || len(vy) != nx*fy {
+		return
+	}
+
+	invHx := 1.0 / h[0]
+	invHy := 1.0 / h[1]
+
+	for i := range nx {
+		for j := range ny {
+			dx := vx[((i+1)%fx)*ny+j] - vx[i*ny+j]
+			dy := vy[i*fy+(j+1)%fy] - vy[i*fy+j]
+			dst[i*ny+j] = dx*invHx + dy*invHy
+		}
+	}
+}
+
+// DivMAC3D writes the divergence of the staggered (MAC) vector field
+// (vx, vy, vz) into dst. The face arrays have row-major shapes
+// (Faces(nx, bc[0]), ny, nz), (nx, Faces(ny, bc[1]), nz) and
+// (nx, ny, Faces(nz, bc[2])); see DivMAC2D for the layout and the pairing
+// with the solver's Laplacian. dst must not overlap the face arrays.
+func DivMAC3D(dst, vx, vy, vz []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	nz := shape[2]
+	if nx == 0 || ny == 0 || nz == 0 {
+		return
+	}
+
+	fx := Faces(nx, bc[0])
+	fy := Faces(ny, bc[1])
+	fz := Faces(nz, bc[2])
+	if len(dst) != nx*ny*nz || len(vx) != fx*ny*nz || len(vy) != nx*fy*nz || len(vz) != nx*ny*fz {
+		return
+	}
+
+	invHx := 1.0 / h[0]
+	invHy := 1.0 / h[1]
+	invHz := 1.0 / h[2]
+
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				dx := vx[(((i+1)%fx)*ny+j)*nz+k] - vx[(i*ny+j)*nz+k]
+				dy := vy[(i*fy+(j+1)%fy)*nz+k] - vy[(i*fy+j)*nz+k]
+				dz := vz[(i*ny+j)*fz+(k+1)%fz] - vz[(i*ny+j)*fz+k]
+				dst[(i*ny+j)*nz+k] = dx*invHx + dy*invHy + dz*invHz
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// faceValue returns the MAC face gradient (u[f] - u[f-1]) / h along an axis
+// of n points, using the ghost values of the Laplacian stencils.
+func faceValue(u func(i int) float64, f, n int, h float64, bc poisson.BCType) float64 {
+	value := func(i int) float64 {
+		switch {
+		case i >= 0 && i < n:
+			return u(i)
+		case bc == poisson.Periodic:
+			return u((i + n) % n)
+		case bc == poisson.Neumann && i < 0:
+			return u(0)
+		case bc == poisson.Neumann:
+			return u(n - 1)
+		default:
+			return 0
+		}
+	}
+
+	return (value(f) - value(f-1)) / h
+}
+
+func testField(n int) []float64 {
+	src := make([]float64, n)
+	for i := range src {
+		src[i] = math.Sin(0.7*float64(i)) + 0.1*float64(i%5)
+	}
+
+	return src
+}
+
+func TestDiv2DMatchesGradComponents(t *testing.T) {
+	nx, ny := 7, 6
+	shape := grid.NewShape2D(nx, ny)
+	h := [2]float64{0.3, 0.2}
+	bc := [2]poisson.BCType{poisson.Neumann, poisson.Dirichlet}
+
+	u := testField(nx * ny)
+	gx := make([]float64, nx*ny)
+	gy := make([]float64, nx*ny)
+	Grad2D(gx, gy, u, shape, h, bc)
+
+	zero := make([]float64, nx*ny)
+	div := make([]float64, nx*ny)
+
+	Div2D(div, u, zero, shape, h, bc)
+	for i := range div {
+		if math.Abs(div[i]-gx[i]) > 1e-12 {
+			t.Fatalf("x-component i=%d: got %v want %v", i, div[i], gx[i])
+		}
+	}
+
+	// In place for the y-component.
+	v := append([]float64(nil), u...)
+	Div2D(v, zero, v, shape, h, bc)
+	for i := range v {
+		if math.Abs(v[i]-gy[i]) > 1e-12 {
+			t.Fatalf("y-component i=%d: got %v want %v", i, v[i], gy[i])
+		}
+	}
+}
+
+func TestDiv3DMatchesGradComponents(t *testing.T) {
+	nx, ny, nz := 4, 5, 3
+	shape := grid.NewShape3D(nx, ny, nz)
+	h := [3]float64{0.3, 0.2, 0.5}
+	bc := [3]poisson.BCType{poisson.Periodic, poisson.Neumann, poisson.Dirichlet}
+	total := nx * ny * nz
+
+	u := testField(total)
+	gx := make([]float64, total)
+	gy := make([]float64, total)
+	gz := make([]float64, total)
+	Grad3D(gx, gy, gz, u, shape, h, bc)
+
+	div := make([]float64, total)
+	Div3D(div, u, u, u, shape, h, bc)
+	for i := range div {
+		if want := gx[i] + gy[i] + gz[i]; math.Abs(div[i]-want) > 1e-12 {
+			t.Fatalf("i=%d: got %v want %v", i, div[i], want)
+		}
+	}
+}
+
+// -DivMAC of the face gradient is the solver's Laplacian stencil.
+func TestDivMAC2DOfFaceGradientIsLaplacian(t *testing.T) {
+	nx, ny := 7, 6
+	shape := grid.NewShape2D(nx, ny)
+	h := [2]float64{0.3, 0.2}
+
+	for _, bc := range [][2]poisson.BCType{
+		{poisson.Periodic, poisson.Periodic},
+		{poisson.Dirichlet, poisson.Neumann},
+		{poisson.Neumann, poisson.Periodic},
+	} {
+		u := testField(nx * ny)
+
+		fx, fy := Faces(nx, bc[0]), Faces(ny, bc[1])
+		vx := make([]float64, fx*ny)
+		vy := make([]float64, nx*fy)
+		for f := range fx {
+			for j := range ny {
+				vx[f*ny+j] = faceValue(func(i int) float64 { return u[i*ny+j] }, f, nx, h[0], bc[0])
+			}
+		}
+		for i := range nx {
+			for f := range fy {
+				vy[i*fy+f] = faceValue(func(j int) float64 { return u[i*ny+j] }, f, ny, h[1], bc[1])
+			}
+		}
+
+		div := make([]float64, nx*ny)
+		DivMAC2D(div, vx, vy, shape, h, bc)
+
+		want := make([]float64, nx*ny)
+		Apply2D(want, u, shape, h, bc)
+
+		for i := range div {
+			if math.Abs(-div[i]-want[i]) > 1e-10 {
+				t.Fatalf("%v i=%d: got %v want %v", bc, i, -div[i], want[i])
+			}
+		}
+	}
+}
+
+func TestDivMAC3DOfFaceGradientIsLaplacian(t *testing.T) {
+	nx, ny, nz := 4, 5, 3
+	shape := grid.NewShape3D(nx, ny, nz)
+	h := [3]float64{0.3, 0.2, 0.5}
+	bc := [3]poisson.BCType{poisson.Dirichlet, poisson.Periodic, poisson.Neumann}
+	total := nx * ny * nz
+
+	u := testField(total)
+	at := func(i, j, k int) float64 { return u[(i*ny+j)*nz+k] }
+
+	fx, fy, fz := Faces(nx, bc[0]), Faces(ny, bc[1]), Faces(nz, bc[2])
+	vx := make([]float64, fx*ny*nz)
+	vy := make([]float64, nx*fy*nz)
+	vz := make([]float64, nx*ny*fz)
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				for f := range fx {
+					vx[(f*ny+j)*nz+k] = faceValue(func(i int) float64 { return at(i, j, k) }, f, nx, h[0], bc[0])
+				}
+				for f := range fy {
+					vy[(i*fy+f)*nz+k] = faceValue(func(j int) float64 { return at(i, j, k) }, f, ny, h[1], bc[1])
+				}
+				for f := range fz {
+					vz[(i*ny+j)*fz+f] = faceValue(func(k int) float64 { return at(i, j, k) }, f, nz, h[2], bc[2])
+				}
+			}
+		}
+	}
+
+	div := make([]float64, total)
+	DivMAC3D(div, vx, vy, vz, shape, h, bc)
+
+	want := make([]float64, total)
+	Apply3D(want, u, shape, h, bc)
+
+	for i := range div {
+		if math.Abs(-div[i]-want[i]) > 1e-10 {
+			t.Fatalf("i=%d: got %v want %v", i, -div[i], want[i])
+		}
+	}
+}
+
+func TestFaces(t *testing.T) {
+	if got := Faces(8, poisson.Periodic); got != 8 {
+		t.Errorf("Faces(8, Periodic) = %d, want 8", got)
+	}
+
+	for _, bc := range []poisson.BCType{poisson.Dirichlet, poisson.Neumann} {
+		if got := Faces(8, bc); got != 9 {
+			t.Errorf("Faces(8, %v) = %d, want 9", bc, got)
+		}
+	}
+}
@@ -2,7 +2,20 @@
 //
 // This package implements the mathematical foundations for the spectral Poisson solver:
 //   - Eigenvalue formulas for the discrete Laplacian
-//   - Laplacian stencil application (for testing and validation)
+//   - Laplacian stencil application in 1D-3D and N dimensions (for testing and validation),
+//     with parallel, cache-blocked 2D/3D kernels for residuals on large grids
+//   - Helmholtz operator application (α - Δ) for residuals of Helmholtz solves
+//   - Variable-coefficient diffusion operator -∇·(κ∇u)
+//   - Assembled sparse matrices of the operators, with gonum adapters in fd/gonumfd
+//   - Gradient, divergence and curl operators with the same boundary conventions
+//   - Grid-weighted norms and error metrics
+//   - Second-difference operator and eigenbasis on non-uniform 1D grids
+//   - Boundary fluxes ∂u/∂n of solved fields
+//   - Interpolation between cell-centered and node grids
+//   - Upwind and centered advection operators
+//   - Isotropic 9-point (2D) and 27-point (3D) Laplacian stencils
+//   - Jacobi, Gauss–Seidel and red-black relaxation smoothers
+//   - Numerical symmetry check of operators
 //
 // # Eigenvalues
 //
@@ -25,8 +38,135 @@
 //
 //	Λ(i,j,k) = λ_x(i) + λ_y(j) + λ_z(k)
 //
+// # Fourth-order stencils
+//
+// Apply1D4, Apply2D4 and Apply3D4 apply the wide five-point stencil per
+// axis, (30u_i - 16(u_{i±1}) + u_{i±2})/(12h²), with the values beyond the
+// ends extended by the symmetry of each boundary condition. The modes above
+// stay its eigenvectors, with eigenvalues (Eigenvalues4)
+//
+//	λ_m = (15 - 16*cos(θ_m) + cos(2θ_m)) / (6h²)
+//
+// for the same mode angles θ_m, so a fourth-order solver can be validated
+// like the second-order one.
+//
+// # Isotropic stencils
+//
+// Apply2D9 and Apply3D27 apply the compact stencils of poisson.Isotropic9
+// and poisson.Isotropic27. Both are products of the per-axis second
+// differences L_a of Apply1D,
+//
+//	Σ L_a - Σ_{a<b} (h_a² + h_b²)/12 · L_a L_b + (hx²hy² + hy²hz² + hz²hx²)/90 · L_x L_y L_z
+//
+// (the last term in 3D only), so the boundary ghosts of each axis carry over
+// and the product modes stay eigenvectors. Eigenvalues2D9 and Eigenvalues3D27
+// tabulate the same polynomial of the per-axis eigenvalues.
+//
+// # Variable coefficients
+//
+// ApplyVariable1D, ApplyVariable2D and ApplyVariable3D apply the
+// conservative operator -∇·(κ∇u) with face coefficients the harmonic means
+// of the two adjacent values of κ: the operator the iterative and multigrid
+// paths target, with the boundary handling of Apply1D and reducing to it
+// for κ = 1. It is symmetric, and conserves the sum of u without Dirichlet
+// axes.
+//
+// # Matrices
+//
+// NewHelmholtzMatrix and NewVariableMatrix assemble the second-order
+// operators as a symmetric CSR Matrix with entry access (At, DoNonZero,
+// DoRowNonZero) and matrix-vector products (MulVec) on plain slices, so fd
+// itself has no gonum dependency. The fd/gonumfd subpackage wraps a Matrix
+// as a gonum mat.Matrix, mat.Symmetric and mat.NonZeroDoer and fills
+// mat.VecDense from grid windows. Dense and CSR export the dense and sparse
+// arrays for other linear-algebra packages, and Gather and Scatter move grid
+// windows to and from the packed vectors the matrices act on.
+//
+// # Smoothers
+//
+// Jacobi, GaussSeidel and the red-black ordering of NewRedBlack relax
+// A u = f in place through the Operator interface, the dimensions and
+// row-wise non-zero entries that Matrix provides. They are the
+// smoothing steps of a multigrid cycle behind the Smoother interface, with
+// Residual computing the defect to restrict; Relax runs one alone as a
+// simple iterative solver for small problems.
+//
+// SymmetryError checks an Operator for symmetry, ⟨Au, v⟩ = ⟨u, Av⟩, on
+// random vectors, and SymmetryErrorFunc does the same for a matrix-free
+// operator such as poisson.Plan.OperatorFunc. New boundary and stencil
+// combinations and custom operators should pass it before they are handed
+// to the smoothers or a CG-type solver.
+//
+// # Non-uniform grids
+//
+// ApplyNonUniform1D applies the flux-form second difference with one
+// spacing per gap, the ghost points of Apply1D included. EigenNonUniform
+// returns its eigenvalues and eigenvectors for Dirichlet and Neumann axes
+// from a symmetric tridiagonal eigensolver; the modes are orthonormal in
+// the inner product weighted by the dual cell widths, so they form the
+// transform of a non-uniform axis just as the sine and cosine modes do for
+// a uniform one.
+//
 // # Nullspace
 //
 // Periodic and Neumann have λ_0 = 0 (the constant mode).
 // This must be handled specially in the solver.
+//
+// # Gradients
+//
+// Grad1D, Grad2D and Grad3D apply centered differences (u_{i+1} - u_{i-1})/(2h)
+// with the ghost values of the Laplacian stencils: periodic wrap, zero
+// beyond a Dirichlet end and the mirrored value beyond a Neumann end. A
+// sampled eigenmode with wavenumber θ is differentiated exactly up to the
+// factor sin(θh)/(θh).
+//
+// # Divergence
+//
+// Div2D and Div3D are the collocated counterparts of the gradients. For
+// projection methods, DivMAC2D and DivMAC3D take staggered (MAC) velocities
+// on the Faces of each axis: the divergence of the face gradient
+// (u[i] - u[i-1])/h is exactly minus the solver's Laplacian, so the
+// resulting right-hand side is compatible with the solver by construction.
+//
+// # Curl
+//
+// Curl2D (a scalar) and Curl3D (a vector) use the collocated differences of
+// the gradients. The per-axis differences commute, so curl(grad u) = 0 and
+// div(curl v) = 0 hold exactly for every combination of boundary conditions.
+//
+// # Boundary fluxes
+//
+// BoundaryFlux extracts the outward normal derivative on one face of a
+// solved field, for heat fluxes and forces. Dirichlet faces use the
+// second-order one-sided difference to the boundary value, which sits at the
+// ghost position one spacing outside the grid; Neumann faces return the
+// imposed derivative with the outward sign.
+//
+// # Advection
+//
+// Advect1D, Advect2D and Advect3D evaluate v·∇u for a collocated velocity
+// with a selectable AdvectionScheme: first-order Upwind, second-order
+// Centered and second-order Upwind2. Values beyond the ends follow the
+// boundary symmetry of the fourth-order stencils, so a Dirichlet end acts as
+// a zero inflow and a Neumann end as a wall. In operator splitting, an
+// explicit advection step with CourantNumber below the scheme's limit
+// alternates with the implicit diffusion solve of a Helmholtz plan.
+//
+// # Cells and nodes
+//
+// The solver samples Dirichlet axes at nodes x = (i+1)h and Neumann axes at
+// cell centers x = (i+0.5)h; feeding a field of one convention to a plan of
+// the other shifts it by h/2, an O(h) error. CellsToNodes and NodesToCells
+// convert a field along one axis by second-order averaging, between n cells
+// and the NodeCount(n, bc) nodes of the same spacing, using the boundary
+// ghost values at the ends.
+//
+// # Norms
+//
+// NormL2 and ErrorL2 weight the squared values by the cell volume hx·hy·...,
+// so errors on grids of different resolution are comparable; NormLinf and
+// ErrorLinf take the largest magnitude. NormH1 adds the squared face
+// differences with the ghost values of the Laplacian, which makes its
+// gradient part the energy hx·hy·...·⟨u, -Δu⟩ of the solver's operator. The
+// relative variants divide by the norm of the reference solution.
 package fd
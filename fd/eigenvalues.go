@@ -64,6 +64,58 @@ func EigenvaluesNeumann(n int, h float64) []float64 {
 	return Eigenvalues(n, h, poisson.Neumann)
 }
 
+// Eigenvalues4 computes the 1D eigenvalues of the fourth-order wide
+// Laplacian stencil of Apply1D4 for the given boundary condition type, in
+// the order of Eigenvalues. With θ the mode angle of Eigenvalues
+// (2πm/N, πm/(N+1) or πm/N),
+//
+//	λ_m = (15 - 16*cos(θ) + cos(2θ)) / (6h²),
+//
+// which agrees with θ²/h² up to O(θ⁶/h²), where the second-order
+// (2 - 2*cos(θ))/h² is off by O(θ⁴/h²).
+func Eigenvalues4(n int, h float64, bc poisson.BCType) []float64 {
+	eig := make([]float64, n)
+	h2 := h * h
+
+	symbol := func(theta float64) float64 {
+		return (15.0 - 16.0*math.Cos(theta) + math.Cos(2.0*theta)) / (6.0 * h2)
+	}
+
+	switch bc {
+	case poisson.Periodic:
+		for m := range n {
+			eig[m] = symbol(2.0 * math.Pi * float64(m) / float64(n))
+		}
+
+	case poisson.Dirichlet:
+		for m := 1; m <= n; m++ {
+			eig[m-1] = symbol(math.Pi * float64(m) / float64(n+1))
+		}
+
+	case poisson.Neumann:
+		for m := range n {
+			eig[m] = symbol(math.Pi * float64(m) / float64(n))
+		}
+	}
+
+	return eig
+}
+
+// EigenvaluesPeriodic4 computes fourth-order eigenvalues for periodic BC.
+func EigenvaluesPeriodic4(n int, h float64) []float64 {
+	return Eigenvalues4(n, h, poisson.Periodic)
+}
+
+// EigenvaluesDirichlet4 computes fourth-order eigenvalues for Dirichlet BC.
+func EigenvaluesDirichlet4(n int, h float64) []float64 {
+	return Eigenvalues4(n, h, poisson.Dirichlet)
+}
+
+// EigenvaluesNeumann4 computes fourth-order eigenvalues for Neumann BC.
+func EigenvaluesNeumann4(n int, h float64) []float64 {
+	return Eigenvalues4(n, h, poisson.Neumann)
+}
+
 // HasZeroEigenvalue returns true if the given BC has a zero eigenvalue
 // (nullspace / constant mode).
 func HasZeroEigenvalue(bc poisson.BCType) bool {
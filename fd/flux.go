@@ -0,0 +1,104 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// FaceSize returns the number of points on a boundary face of shape: the
+// product of the two remaining axes, the length of BoundaryData.Values.
+func FaceSize(shape grid.Shape, face poisson.BoundaryFace) int {
+	axis := int(face) / 2
+	if axis > 2 || face < 0 {
+		return 0
+	}
+
+	return shape[(axis+1)%3] * shape[(axis+2)%3]
+}
+
+// BoundaryFlux writes the outward normal derivative ∂u/∂n of the solved
+// field u on a boundary face into dst, in the row-major face order of
+// BoundaryData.Values. bc is the boundary condition of the face's axis and
+// values the boundary data of the solve (nil for homogeneous), as passed to
+// Plan.SolveWithBC.
+//
+// On a Dirichlet face the boundary lies at the ghost point, one spacing
+// before the first point (after the last), and carries the value g; the
+// derivative is the second-order one-sided difference
+//
+//	∂u/∂n = (3g - 4u_0 + u_1) / (2h),
+//
+// falling back to (g - u_0)/h for a single point. On a Neumann face the
+// derivative is the imposed one: -g on a low face and g on a high face,
+// since Values hold the derivative along the positive axis. A heat flux is
+// -κ times the result.
+//
+// It returns poisson.ErrNilBuffer for a nil dst or u, a *poisson.SizeError
+// if u, dst or a non-nil values does not match the grid, and a
+// *poisson.ValidationError for an unknown face, a face beyond the grid's
+// dimension or a Periodic axis, which has no boundary.
+func BoundaryFlux(dst, u []float64, shape grid.Shape, h [3]float64, face poisson.BoundaryFace, bc poisson.BCType, values []float64) error {
+	if dst == nil || u == nil {
+		return poisson.ErrNilBuffer
+	}
+
+	axis := int(face) / 2
+	if face < 0 || axis > 2 {
+		return &poisson.ValidationError{Field: "Face", Message: "unknown boundary face"}
+	}
+
+	if axis >= shape.Dim() {
+		return &poisson.ValidationError{Field: "Face", Message: face.String() + " face not valid for this dimension"}
+	}
+
+	if bc == poisson.Periodic {
+		return &poisson.ValidationError{Field: "bc", Message: "periodic axes have no boundary faces"}
+	}
+
+	if len(u) != shape.Size() {
+		return &poisson.SizeError{Expected: shape.Size(), Got: len(u), Context: "BoundaryFlux u"}
+	}
+
+	size := FaceSize(shape, face)
+	if len(dst) != size {
+		return &poisson.SizeError{Expected: size, Got: len(dst), Context: "BoundaryFlux dst"}
+	}
+
+	if values != nil && len(values) != size {
+		return &poisson.SizeError{Expected: size, Got: len(values), Context: "BoundaryFlux values"}
+	}
+
+	high := face%2 == 1
+	n := shape[axis]
+
+	// step points from the boundary point into the interior.
+	step := grid.RowMajorStride(shape)[axis]
+	if high {
+		step = -step
+	}
+
+	for idx, f := range poisson.FaceCells(shape, face) {
+		g := 0.0
+		if values != nil {
+			g = values[f]
+		}
+
+		switch bc {
+		case poisson.Neumann:
+			if high {
+				dst[f] = g
+			} else {
+				dst[f] = -g
+			}
+
+		case poisson.Dirichlet:
+			if n > 1 {
+				dst[f] = (3*g - 4*u[idx] + u[idx+step]) / (2 * h[axis])
+			} else {
+				dst[f] = (g - u[idx]) / h[axis]
+			}
+		}
+	}
+
+	return nil
+}
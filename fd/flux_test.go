@@ -0,0 +1,163 @@
+package fd
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// The one-sided difference is exact for quadratics, with the boundary value
+// taken at the Dirichlet ghost position.
+func TestBoundaryFluxDirichletQuadratic(t *testing.T) {
+	nx, ny, nz := 5, 4, 3
+	shape := grid.NewShape3D(nx, ny, nz)
+	h := [3]float64{0.2, 0.25, 0.3}
+	size := [3]float64{float64(nx+1) * h[0], float64(ny+1) * h[1], float64(nz+1) * h[2]}
+
+	exact := func(x, y, z float64) float64 { return x*x - 2*y*y + 3*z*z + x*y - z + 1 }
+	grad := func(x, y, z float64) [3]float64 { return [3]float64{2*x + y, -4*y + x, 6*z - 1} }
+
+	u := make([]float64, shape.Size())
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				u[(i*ny+j)*nz+k] = exact(float64(i+1)*h[0], float64(j+1)*h[1], float64(k+1)*h[2])
+			}
+		}
+	}
+
+	for face := poisson.XLow; face <= poisson.ZHigh; face++ {
+		axis := int(face) / 2
+		high := face%2 == 1
+		sign := -1.0
+		if high {
+			sign = 1
+		}
+
+		// Face points in row-major order over the remaining axes, with
+		// the boundary coordinate on the face axis.
+		var points [][3]float64
+		for i := range nx {
+			for j := range ny {
+				for k := range nz {
+					idx := [3]int{i, j, k}
+					if idx[axis] != 0 {
+						continue
+					}
+
+					p := [3]float64{float64(i+1) * h[0], float64(j+1) * h[1], float64(k+1) * h[2]}
+					p[axis] = 0
+					if high {
+						p[axis] = size[axis]
+					}
+					points = append(points, p)
+				}
+			}
+		}
+
+		values := make([]float64, len(points))
+		for f, p := range points {
+			values[f] = exact(p[0], p[1], p[2])
+		}
+
+		dst := make([]float64, FaceSize(shape, face))
+		if err := BoundaryFlux(dst, u, shape, h, face, poisson.Dirichlet, values); err != nil {
+			t.Fatalf("%v: %v", face, err)
+		}
+
+		for f, p := range points {
+			want := sign * grad(p[0], p[1], p[2])[axis]
+			if math.Abs(dst[f]-want) > 1e-10 {
+				t.Fatalf("%v point %d: ∂u/∂n = %v, want %v", face, f, dst[f], want)
+			}
+		}
+	}
+}
+
+func TestBoundaryFluxNeumann(t *testing.T) {
+	shape := grid.NewShape2D(4, 3)
+	u := testField(shape.Size())
+	g := []float64{1, -2, 3}
+
+	dst := make([]float64, 3)
+	if err := BoundaryFlux(dst, u, shape, [3]float64{0.1, 0.1, 1}, poisson.XLow, poisson.Neumann, g); err != nil {
+		t.Fatal(err)
+	}
+	for f := range g {
+		if dst[f] != -g[f] {
+			t.Errorf("XLow point %d: got %v want %v", f, dst[f], -g[f])
+		}
+	}
+
+	if err := BoundaryFlux(dst, u, shape, [3]float64{0.1, 0.1, 1}, poisson.XHigh, poisson.Neumann, nil); err != nil {
+		t.Fatal(err)
+	}
+	for f := range dst {
+		if dst[f] != 0 {
+			t.Errorf("homogeneous XHigh point %d: got %v want 0", f, dst[f])
+		}
+	}
+}
+
+// For a solved homogeneous Dirichlet problem the boundary fluxes balance the
+// source: ∮ ∂u/∂n = -∫ f up to the discretization error.
+func TestBoundaryFluxBalancesSource(t *testing.T) {
+	n := 48
+	h := 1.0 / float64(n+1)
+
+	plan, err := poisson.NewPlan(2, []int{n, n}, []float64{h, h}, []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rhs := make([]float64, n*n)
+	for i := range rhs {
+		rhs[i] = 1
+	}
+
+	u := make([]float64, n*n)
+	if err := plan.Solve(u, rhs); err != nil {
+		t.Fatal(err)
+	}
+
+	shape := grid.NewShape2D(n, n)
+	total := 0.0
+	dst := make([]float64, n)
+	for face := poisson.XLow; face <= poisson.YHigh; face++ {
+		if err := BoundaryFlux(dst, u, shape, [3]float64{h, h, 1}, face, poisson.Dirichlet, nil); err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range dst {
+			total += v * h
+		}
+	}
+
+	// -Δu = 1 on the unit square: the outward flux is -1.
+	if math.Abs(total+1) > 0.05 {
+		t.Errorf("net boundary flux = %v, want -1", total)
+	}
+}
+
+func TestBoundaryFluxErrors(t *testing.T) {
+	shape := grid.NewShape2D(4, 3)
+	u := make([]float64, shape.Size())
+	h := [3]float64{0.1, 0.1, 1}
+
+	if err := BoundaryFlux(make([]float64, 3), u, shape, h, poisson.XLow, poisson.Periodic, nil); err == nil {
+		t.Error("periodic axis: expected an error")
+	}
+	if err := BoundaryFlux(make([]float64, 3), u, shape, h, poisson.ZLow, poisson.Dirichlet, nil); err == nil {
+		t.Error("Z face of a 2D grid: expected an error")
+	}
+
+	var serr *poisson.SizeError
+	if err := BoundaryFlux(make([]float64, 4), u, shape, h, poisson.XLow, poisson.Dirichlet, nil); !errors.As(err, &serr) {
+		t.Errorf("short dst: got %v, want a SizeError", err)
+	}
+	if err := BoundaryFlux(nil, u, shape, h, poisson.XLow, poisson.Dirichlet, nil); !errors.Is(err, poisson.ErrNilBuffer) {
+		t.Errorf("nil dst: got %v, want ErrNilBuffer", err)
+	}
+}
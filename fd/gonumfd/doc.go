@@ -0,0 +1,32 @@
+// Package gonumfd adapts the assembled fd matrices and the operators of
+// poisson plans to gonum's linear algebra interfaces.
+//
+// The core packages of the module work on plain slices and do not depend on
+// gonum; this package is the optional bridge and the only one that imports
+// gonum.org/v1/gonum. Programs that do not import it do not build gonum.
+//
+// Matrix wraps an *fd.Matrix as a mat.Matrix, mat.Symmetric and sparse
+// mat.NonZeroDoer/mat.RowNonZeroDoer/mat.ColNonZeroDoer without copying
+// the CSR arrays:
+//
+//	m, err := fd.NewHelmholtzMatrix(alpha, n, h, bc)
+//	...
+//	a := gonumfd.NewMatrix(m)
+//	var chol mat.Cholesky
+//	chol.Factorize(a)
+//
+// Gather and Scatter move grid windows, e.g. the interior of a field with
+// ghost layers, to and from *mat.VecDense in the row-major order of the
+// matrices.
+//
+// Operator and Preconditioner expose a poisson.Plan with the method set of
+// gonum.org/v1/exp/linsolve: Operator is a linsolve MulVecToer and
+// Preconditioner.PreconSolve fits linsolve.Settings.PreconSolve, so the fast
+// solve can precondition Krylov iterations on nearby problems:
+//
+//	res, err := linsolve.Iterative(gonumfd.NewMatrix(m), b, &linsolve.CG{},
+//		&linsolve.Settings{PreconSolve: gonumfd.NewPreconditioner(plan).PreconSolve})
+//
+// Matrix.MulVecTo has the same signature, so the wrapped fd matrices are
+// linsolve operators as well.
+package gonumfd
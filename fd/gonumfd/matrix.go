@@ -0,0 +1,161 @@
+package gonumfd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ mat.Matrix         = (*Matrix)(nil)
+	_ mat.Symmetric      = (*Matrix)(nil)
+	_ mat.NonZeroDoer    = (*Matrix)(nil)
+	_ mat.RowNonZeroDoer = (*Matrix)(nil)
+	_ mat.ColNonZeroDoer = (*Matrix)(nil)
+)
+
+// Matrix is an fd.Matrix seen as a gonum matrix. The assembled operators
+// are symmetric, so Matrix is its own transpose. Like the wrapped matrix it
+// is immutable and safe for concurrent use.
+type Matrix struct {
+	m *fd.Matrix
+}
+
+// NewMatrix wraps m. The CSR arrays are shared, not copied.
+func NewMatrix(m *fd.Matrix) *Matrix {
+	return &Matrix{m: m}
+}
+
+// Unwrap returns the wrapped fd.Matrix.
+func (a *Matrix) Unwrap() *fd.Matrix {
+	return a.m
+}
+
+// Dims returns the number of rows and columns.
+func (a *Matrix) Dims() (r, c int) {
+	return a.m.Dims()
+}
+
+// At returns the entry in row i and column j. It panics if i or j is out of
+// range.
+func (a *Matrix) At(i, j int) float64 {
+	return a.m.At(i, j)
+}
+
+// T returns the receiver, since the matrix is symmetric.
+func (a *Matrix) T() mat.Matrix {
+	return a
+}
+
+// SymmetricDim returns the number of rows (and columns).
+func (a *Matrix) SymmetricDim() int {
+	r, _ := a.m.Dims()
+	return r
+}
+
+// DoNonZero calls fn for each non-zero entry, row by row.
+func (a *Matrix) DoNonZero(fn func(i, j int, v float64)) {
+	a.m.DoNonZero(fn)
+}
+
+// DoRowNonZero calls fn for each non-zero entry of row i.
+func (a *Matrix) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	a.m.DoRowNonZero(i, fn)
+}
+
+// DoColNonZero calls fn for each non-zero entry of column j, which by
+// symmetry are the entries of row j.
+func (a *Matrix) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	a.m.DoRowNonZero(j, func(_, i int, v float64) {
+		fn(i, j, v)
+	})
+}
+
+// MulVecTo computes dst = A x (or Aᵀ x, which is the same), with the
+// signature of the gonum.org/v1/exp/linsolve MulVecToer. An empty dst is
+// resized; otherwise its length must match, or MulVecTo panics with
+// mat.ErrShape.
+func (a *Matrix) MulVecTo(dst *mat.VecDense, _ bool, x mat.Vector) {
+	n := a.SymmetricDim()
+	mulVecTo(dst, x, n, a.m.MulVec)
+}
+
+// Gather copies the window of shape at view in src into dst, as fd.Gather.
+// An empty dst is resized to shape.Size(). It panics with mat.ErrShape if
+// dst has another length or the view does not fit src.
+func Gather(dst *mat.VecDense, src []float64, shape grid.Shape, view grid.View) {
+	n := shape.Size()
+	if !view.Fits(shape, len(src)) {
+		panic(mat.ErrShape)
+	}
+
+	out, flush := vecDst(dst, n)
+	fd.Gather(out, src, shape, view)
+	flush()
+}
+
+// Scatter copies src back into the window of shape at view in dst; it is
+// the inverse of Gather. It panics with mat.ErrShape if src does not have
+// length shape.Size() or the view does not fit dst.
+func Scatter(dst []float64, src mat.Vector, shape grid.Shape, view grid.View) {
+	n := shape.Size()
+	if src.Len() != n || !view.Fits(shape, len(dst)) {
+		panic(mat.ErrShape)
+	}
+
+	fd.Scatter(dst, vecData(src), shape, view)
+}
+
+// mulVecTo runs the slice product mul for gonum vectors of length n. Errors
+// of mul are size mismatches, which gonum reports by panicking.
+func mulVecTo(dst *mat.VecDense, x mat.Vector, n int, mul func(dst, x []float64) error) {
+	if x.Len() != n {
+		panic(mat.ErrShape)
+	}
+
+	out, flush := vecDst(dst, n)
+	if err := mul(out, vecData(x)); err != nil {
+		panic(err)
+	}
+	flush()
+}
+
+// vecData returns the elements of v as a contiguous slice, sharing the
+// backing data of a unit-stride *mat.VecDense and copying otherwise.
+func vecData(v mat.Vector) []float64 {
+	if vd, ok := v.(*mat.VecDense); ok {
+		if raw := vd.RawVector(); raw.Inc == 1 {
+			return raw.Data[:raw.N]
+		}
+	}
+
+	data := make([]float64, v.Len())
+	for i := range data {
+		data[i] = v.AtVec(i)
+	}
+
+	return data
+}
+
+// vecDst returns a contiguous slice to write the n elements of dst into,
+// resizing an empty dst, and a flush function that stores the slice into
+// dst if it is not dst's own data.
+func vecDst(dst *mat.VecDense, n int) ([]float64, func()) {
+	if dst.IsEmpty() {
+		dst.ReuseAsVec(n)
+	}
+	if dst.Len() != n {
+		panic(mat.ErrShape)
+	}
+
+	if raw := dst.RawVector(); raw.Inc == 1 {
+		return raw.Data[:n], func() {}
+	}
+
+	out := make([]float64, n)
+	return out, func() {
+		for i, v := range out {
+			dst.SetVec(i, v)
+		}
+	}
+}
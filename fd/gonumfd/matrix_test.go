@@ -0,0 +1,162 @@
+package gonumfd_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/fd/gonumfd"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+	"gonum.org/v1/gonum/mat"
+)
+
+// stencilDense assembles the Helmholtz stencil column by column with
+// fd.ApplyHelmholtz, independently of fd.Matrix.
+func stencilDense(alpha float64, n []int, h []float64, bc []poisson.BCType) *mat.Dense {
+	size := 1
+	for _, v := range n {
+		size *= v
+	}
+
+	dense := mat.NewDense(size, size, nil)
+	e := make([]float64, size)
+	col := make([]float64, size)
+	for j := range size {
+		e[j] = 1
+		fd.ApplyHelmholtz(col, e, alpha, n, h, bc)
+		e[j] = 0
+
+		dense.SetCol(j, col)
+	}
+
+	return dense
+}
+
+func TestMatrixMatchesDenseStencil(t *testing.T) {
+	for _, tc := range []struct {
+		n  []int
+		h  []float64
+		bc []poisson.BCType
+	}{
+		{[]int{6}, []float64{0.5}, []poisson.BCType{poisson.Periodic}},
+		{[]int{5, 4}, []float64{0.2, 0.3}, []poisson.BCType{poisson.Dirichlet, poisson.Periodic}},
+		{[]int{3, 4, 5}, []float64{0.3, 0.2, 0.1}, []poisson.BCType{poisson.Neumann, poisson.Dirichlet, poisson.Periodic}},
+	} {
+		const alpha = 1.5
+
+		m, err := fd.NewHelmholtzMatrix(alpha, tc.n, tc.h, tc.bc)
+		if err != nil {
+			t.Fatalf("NewHelmholtzMatrix failed: %v", err)
+		}
+		a := gonumfd.NewMatrix(m)
+		want := stencilDense(alpha, tc.n, tc.h, tc.bc)
+
+		if !mat.EqualApprox(a, want, 1e-12) || !mat.EqualApprox(a.T(), want.T(), 1e-12) {
+			t.Fatalf("n=%v: wrapper differs from the dense stencil:\n%v\nwant\n%v", tc.n, mat.Formatted(a), mat.Formatted(want))
+		}
+
+		size := a.SymmetricDim()
+		nnz := 0
+		a.DoNonZero(func(i, j int, v float64) {
+			nnz++
+			if v != want.At(i, j) {
+				t.Fatalf("n=%v: DoNonZero (%d, %d) = %v, want %v", tc.n, i, j, v, want.At(i, j))
+			}
+		})
+		if nnz != m.NNZ() {
+			t.Fatalf("n=%v: DoNonZero visited %d entries, want %d", tc.n, nnz, m.NNZ())
+		}
+
+		a.DoColNonZero(size-1, func(i, j int, v float64) {
+			if j != size-1 || v != want.At(i, j) {
+				t.Fatalf("n=%v: DoColNonZero (%d, %d) = %v, want %v", tc.n, i, j, v, want.At(i, j))
+			}
+		})
+
+		x := mat.NewVecDense(size, nil)
+		for i := range size {
+			x.SetVec(i, math.Sin(0.7*float64(i))+0.1*float64(i%5))
+		}
+
+		var got, ref mat.VecDense
+		a.MulVecTo(&got, false, x)
+		ref.MulVec(want, x)
+		if !mat.EqualApprox(&got, &ref, 1e-12) {
+			t.Fatalf("n=%v: MulVecTo = %v, want %v", tc.n, mat.Formatted(got.T()), mat.Formatted(ref.T()))
+		}
+
+		// A strided destination and source go through the copying paths.
+		strided := mat.NewDense(size, 2, nil)
+		strided.SetCol(0, x.RawVector().Data)
+		col := strided.ColView(1).(*mat.VecDense)
+		a.MulVecTo(col, false, strided.ColView(0))
+		if !mat.EqualApprox(col, &ref, 1e-12) {
+			t.Fatalf("n=%v: strided MulVecTo = %v, want %v", tc.n, mat.Formatted(col.T()), mat.Formatted(ref.T()))
+		}
+	}
+}
+
+func TestMatrixCholeskySolve(t *testing.T) {
+	n := []int{6, 5}
+	h := []float64{0.2, 0.25}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Neumann}
+
+	m, err := fd.NewHelmholtzMatrix(0, n, h, bc)
+	if err != nil {
+		t.Fatalf("NewHelmholtzMatrix failed: %v", err)
+	}
+	a := gonumfd.NewMatrix(m)
+
+	var chol mat.Cholesky
+	if ok := chol.Factorize(a); !ok {
+		t.Fatal("Cholesky factorization failed for the SPD Dirichlet operator")
+	}
+
+	size := a.SymmetricDim()
+	b := mat.NewVecDense(size, nil)
+	for i := range size {
+		b.SetVec(i, float64(i%7)-3)
+	}
+
+	var u, r mat.VecDense
+	if err := chol.SolveVecTo(&u, b); err != nil {
+		t.Fatalf("SolveVecTo failed: %v", err)
+	}
+
+	a.MulVecTo(&r, false, &u)
+	r.SubVec(&r, b)
+	if res := mat.Norm(&r, math.Inf(1)); res > 1e-9 {
+		t.Fatalf("residual %g", res)
+	}
+}
+
+func TestGatherScatter(t *testing.T) {
+	parent := grid.NewShape2D(5, 6)
+	shape := grid.NewShape2D(3, 4)
+	view := grid.SubView(parent, [3]int{1, 1, 0})
+
+	field := make([]float64, parent.Size())
+	for i := range field {
+		field[i] = float64(i)
+	}
+
+	var vec mat.VecDense
+	gonumfd.Gather(&vec, field, shape, view)
+	if vec.Len() != shape.Size() || vec.AtVec(0) != field[view.Index(0, 0, 0)] || vec.AtVec(vec.Len()-1) != field[view.Index(2, 3, 0)] {
+		t.Fatalf("Gather: got %v", mat.Formatted(vec.T()))
+	}
+
+	vec.ScaleVec(2, &vec)
+	gonumfd.Scatter(field, &vec, shape, view)
+	if got, want := field[view.Index(1, 2, 0)], vec.AtVec(1*4+2); got != want {
+		t.Errorf("Scatter: got %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() != mat.ErrShape {
+			t.Error("expected mat.ErrShape panic for a short vector")
+		}
+	}()
+	gonumfd.Scatter(field, mat.NewVecDense(2, nil), shape, view)
+}
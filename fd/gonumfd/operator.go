@@ -0,0 +1,79 @@
+package gonumfd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/poisson"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Operator is the discrete operator of a poisson.Plan with the method set of
+// the gonum.org/v1/exp/linsolve MulVecToer, so a plan can be passed to
+// linsolve.Iterative as the system matrix:
+//
+//	res, err := linsolve.Iterative(gonumfd.NewOperator(plan), b, &linsolve.CG{}, nil)
+//
+// It shares scratch buffers with the plan and must not be used concurrently
+// with it.
+type Operator struct {
+	op *poisson.LinearOperator
+	n  int
+}
+
+// NewOperator returns the operator of plan, see poisson.Plan.Operator.
+func NewOperator(plan *poisson.Plan) *Operator {
+	op := plan.Operator()
+	n, _ := op.Dims()
+
+	return &Operator{op: op, n: n}
+}
+
+// Dims returns the operator dimensions (rows, columns).
+func (o *Operator) Dims() (r, c int) {
+	return o.n, o.n
+}
+
+// MulVecTo computes dst = A x (or Aᵀ x, which is the same). An empty dst is
+// resized; otherwise its length must match, or MulVecTo panics with
+// mat.ErrShape.
+func (o *Operator) MulVecTo(dst *mat.VecDense, _ bool, x mat.Vector) {
+	mulVecTo(dst, x, o.n, o.op.MulVec)
+}
+
+// Preconditioner is the fast solve of a poisson.Plan as the inverse of its
+// operator. Its PreconSolve method value has the type of the linsolve
+// Settings.PreconSolve field:
+//
+//	settings := &linsolve.Settings{PreconSolve: gonumfd.NewPreconditioner(plan).PreconSolve}
+//
+// See poisson.Preconditioner for nullspace problems. It shares scratch
+// buffers with the plan and must not be used concurrently with it.
+type Preconditioner struct {
+	pc *poisson.Preconditioner
+	n  int
+}
+
+// NewPreconditioner returns the solve of plan as a preconditioner, see
+// poisson.Plan.Preconditioner.
+func NewPreconditioner(plan *poisson.Plan) *Preconditioner {
+	n, _ := plan.Operator().Dims()
+
+	return &Preconditioner{pc: plan.Preconditioner(), n: n}
+}
+
+// PreconSolve solves A dst = rhs (Aᵀ = A, so trans is ignored). An empty dst
+// is resized; a dst or rhs of another length is an error.
+func (m *Preconditioner) PreconSolve(dst *mat.VecDense, rhs mat.Vector, trans bool) error {
+	if rhs.Len() != m.n {
+		return &poisson.SizeError{Expected: m.n, Got: rhs.Len(), Context: "PreconSolve rhs"}
+	}
+	if !dst.IsEmpty() && dst.Len() != m.n {
+		return &poisson.SizeError{Expected: m.n, Got: dst.Len(), Context: "PreconSolve dst"}
+	}
+
+	out, flush := vecDst(dst, m.n)
+	if err := m.pc.PreconSolve(out, trans, vecData(rhs)); err != nil {
+		return err
+	}
+	flush()
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package gonumfd_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/fd/gonumfd"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+	"gonum.org/v1/gonum/mat"
+)
+
+// mulVecToer and preconSolver are the operator interface and the
+// Settings.PreconSolve type of gonum.org/v1/exp/linsolve, declared here so
+// the adapters are checked against them without importing the exp module.
+type (
+	mulVecToer interface {
+		MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector)
+	}
+	preconSolver func(dst *mat.VecDense, rhs mat.Vector, trans bool) error
+)
+
+var (
+	_ mulVecToer = (*gonumfd.Operator)(nil)
+	_ mulVecToer = (*gonumfd.Matrix)(nil)
+)
+
+// cg is preconditioned conjugate gradients as run by linsolve.Iterative
+// with linsolve.CG: it returns the solution and the number of iterations to
+// reach a relative residual below tol.
+func cg(t *testing.T, a mulVecToer, b *mat.VecDense, precon preconSolver, tol float64) (*mat.VecDense, int) {
+	t.Helper()
+
+	n := b.Len()
+	x := mat.NewVecDense(n, nil)
+	r := mat.VecDenseCopyOf(b)
+	var z, p, ap mat.VecDense
+
+	bnorm := mat.Norm(b, 2)
+	var rz float64
+	for iter := 1; iter <= 10*n; iter++ {
+		if err := precon(&z, r, false); err != nil {
+			t.Fatalf("PreconSolve failed: %v", err)
+		}
+
+		rzNew := mat.Dot(r, &z)
+		if iter == 1 {
+			p.CloneFromVec(&z)
+		} else {
+			p.AddScaledVec(&z, rzNew/rz, &p)
+		}
+		rz = rzNew
+
+		a.MulVecTo(&ap, false, &p)
+		alpha := rz / mat.Dot(&p, &ap)
+		x.AddScaledVec(x, alpha, &p)
+		r.AddScaledVec(r, -alpha, &ap)
+
+		if mat.Norm(r, 2) <= tol*bnorm {
+			return x, iter
+		}
+	}
+
+	t.Fatalf("CG did not converge in %d iterations", 10*n)
+	return nil, 0
+}
+
+func TestOperatorCGMatchesSolve(t *testing.T) {
+	n := []int{12, 10}
+	h := []float64{1.0 / 13, 1.0 / 11}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}
+
+	plan, err := poisson.NewHelmholtzPlan(2, n, h, bc, 0.5)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	size := n[0] * n[1]
+	b := mat.NewVecDense(size, nil)
+	for i := range size {
+		b.SetVec(i, math.Sin(0.3*float64(i))+0.2)
+	}
+
+	want := make([]float64, size)
+	if err := plan.Solve(want, b.RawVector().Data); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	identity := func(dst *mat.VecDense, rhs mat.Vector, _ bool) error {
+		dst.CloneFromVec(rhs)
+		return nil
+	}
+
+	x, _ := cg(t, gonumfd.NewOperator(plan), b, identity, 1e-12)
+	if d := maxDiff(x, want); d > 1e-8 {
+		t.Fatalf("unpreconditioned CG differs from Solve by %g", d)
+	}
+
+	// Preconditioned by its own inverse, CG converges in one step.
+	x, iters := cg(t, gonumfd.NewOperator(plan), b, gonumfd.NewPreconditioner(plan).PreconSolve, 1e-10)
+	if iters != 1 || maxDiff(x, want) > 1e-10 {
+		t.Fatalf("self-preconditioned CG: %d iterations, difference %g", iters, maxDiff(x, want))
+	}
+}
+
+func TestPreconditionerAcceleratesVariableCoefficients(t *testing.T) {
+	n := []int{16, 16}
+	h := []float64{1.0 / 17, 1.0 / 17}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}
+	size := n[0] * n[1]
+
+	kappa := make([]float64, size)
+	for i := range kappa {
+		kappa[i] = 1 + 0.5*math.Sin(0.4*float64(i))
+	}
+
+	m, err := fd.NewVariableMatrix(kappa, n, h, bc)
+	if err != nil {
+		t.Fatalf("NewVariableMatrix failed: %v", err)
+	}
+
+	plan, err := poisson.NewHelmholtzPlan(2, n, h, bc, 0)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	b := mat.NewVecDense(size, nil)
+	for i := range size {
+		b.SetVec(i, 1)
+	}
+
+	identity := func(dst *mat.VecDense, rhs mat.Vector, _ bool) error {
+		dst.CloneFromVec(rhs)
+		return nil
+	}
+
+	a := gonumfd.NewMatrix(m)
+	plain, plainIters := cg(t, a, b, identity, 1e-10)
+	precond, precondIters := cg(t, a, b, gonumfd.NewPreconditioner(plan).PreconSolve, 1e-10)
+
+	if precondIters >= plainIters {
+		t.Errorf("preconditioned CG took %d iterations, unpreconditioned %d", precondIters, plainIters)
+	}
+	if d := maxDiff(precond, plain.RawVector().Data); d > 1e-7 {
+		t.Errorf("preconditioned and plain CG solutions differ by %g", d)
+	}
+}
+
+func TestPreconSolveSizeErrors(t *testing.T) {
+	plan, err := poisson.NewHelmholtzPlan(1, []int{8}, []float64{0.1}, []poisson.BCType{poisson.Dirichlet}, 1)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	pc := gonumfd.NewPreconditioner(plan)
+	var sizeErr *poisson.SizeError
+	if err := pc.PreconSolve(&mat.VecDense{}, mat.NewVecDense(7, nil), false); !errors.As(err, &sizeErr) {
+		t.Errorf("short rhs: got %v, want SizeError", err)
+	}
+	if err := pc.PreconSolve(mat.NewVecDense(3, nil), mat.NewVecDense(8, nil), false); !errors.As(err, &sizeErr) {
+		t.Errorf("short dst: got %v, want SizeError", err)
+	}
+}
+
+func maxDiff(v *mat.VecDense, want []float64) float64 {
+	d := 0.0
+	for i, w := range want {
+		d = math.Max(d, math.Abs(v.AtVec(i)-w))
+	}
+
+	return d
+}
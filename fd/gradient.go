@@ -0,0 +1,144 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// neighbors returns the values left and right of src[idx], the element at
+// position i of an axis with n points and the given stride. Beyond the ends
+// it uses the ghost values of the Laplacian stencils: the wrapped value for
+// Periodic, the mirrored value u[-1] = u[0] for Neumann and 0 for Dirichlet.
+func neighbors(src []float64, idx, i, n, stride int, bc poisson.BCType) (left, right float64) {
+	switch {
+	case i > 0:
+		left = src[idx-stride]
+	case bc == poisson.Periodic:
+		left = src[idx+stride*(n-1)]
+	case bc == poisson.Neumann:
+		left = src[idx]
+	}
+
+	switch {
+	case i+1 < n:
+		right = src[idx+stride]
+	case bc == poisson.Periodic:
+		right = src[idx-stride*(n-1)]
+	case bc == poisson.Neumann:
+		right = src[idx]
+	}
+
+	return left, right
+}
+
+// aliases reports whether any of dsts shares its first element with src.
+func aliases(src []float64, dsts ...[]float64) bool {
+	for _, dst := range dsts {
+		if &dst[0] == &src[0] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Grad1D applies the centered first difference to src and writes into dst.
+// The result is (u_{i+1} - u_{i-1}) / (2h) with the ghost values of Apply1D
+// beyond the ends (periodic wrap, Dirichlet zero, Neumann mirror), so
+// sampled modes of the solver's eigenbases are differentiated exactly up to
+// the factor sin(θh)/(θh) of the wavenumber θ. It is safe to call with
+// dst == src.
+func Grad1D(dst, src []float64, h float64, bc poisson.BCType) {
+	n := len(src)
+	if n == 0 || len(dst) != n {
+		return
+	}
+
+	if aliases(src, dst) {
+		src = append([]float64(nil), src...)
+	}
+
+	inv2H := 0.5 / h
+	for i := range n {
+		left, right := neighbors(src, i, i, n, 1, bc)
+		dst[i] = (right - left) * inv2H
+	}
+}
+
+// Grad2D writes the centered-difference gradient of src into gx (∂/∂x) and
+// gy (∂/∂y), with per-axis boundary handling set by bc as in Grad1D and the
+// row-major layout of Apply2D. It is safe to call with gx or gy == src.
+func Grad2D(gx, gy, src []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	if nx == 0 || ny == 0 {
+		return
+	}
+
+	total := nx * ny
+	if len(src) != total || len(gx) != total || len(gy) != total {
+		return
+	}
+
+	if aliases(src, gx, gy) {
+		src = append([]float64(nil), src...)
+	}
+
+	inv2Hx := 0.5 / h[0]
+	inv2Hy := 0.5 / h[1]
+
+	for i := range nx {
+		for j := range ny {
+			idx := i*ny + j
+
+			left, right := neighbors(src, idx, i, nx, ny, bc[0])
+			gx[idx] = (right - left) * inv2Hx
+
+			down, up := neighbors(src, idx, j, ny, 1, bc[1])
+			gy[idx] = (up - down) * inv2Hy
+		}
+	}
+}
+
+// Grad3D writes the centered-difference gradient of src into gx, gy and gz,
+// with per-axis boundary handling set by bc as in Grad1D and the row-major
+// layout of Apply3D. It is safe to call with any of gx, gy, gz == src.
+func Grad3D(gx, gy, gz, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	nz := shape[2]
+	if nx == 0 || ny == 0 || nz == 0 {
+		return
+	}
+
+	total := nx * ny * nz
+	if len(src) != total || len(gx) != total || len(gy) != total || len(gz) != total {
+		return
+	}
+
+	if aliases(src, gx, gy, gz) {
+		src = append([]float64(nil), src...)
+	}
+
+	inv2Hx := 0.5 / h[0]
+	inv2Hy := 0.5 / h[1]
+	inv2Hz := 0.5 / h[2]
+
+	plane := ny * nz
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				idx := i*plane + j*nz + k
+
+				left, right := neighbors(src, idx, i, nx, plane, bc[0])
+				gx[idx] = (right - left) * inv2Hx
+
+				down, up := neighbors(src, idx, j, ny, nz, bc[1])
+				gy[idx] = (up - down) * inv2Hy
+
+				back, front := neighbors(src, idx, k, nz, 1, bc[2])
+				gz[idx] = (front - back) * inv2Hz
+			}
+		}
+	}
+}
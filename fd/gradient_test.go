@@ -0,0 +1,155 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// gradMode samples an eigenmode of the 1D Laplacian for bc on n points and
+// its exact centered difference, f'(x) * sin(θh)/(θh).
+func gradMode(n, m int, bc poisson.BCType) (h float64, u, du []float64) {
+	u = make([]float64, n)
+	du = make([]float64, n)
+
+	for i := range n {
+		var x, theta float64
+		switch bc {
+		case poisson.Periodic:
+			h = 1.0 / float64(n)
+			x = float64(i) * h
+			theta = 2.0 * math.Pi * float64(m)
+		case poisson.Dirichlet:
+			h = 1.0 / float64(n+1)
+			x = float64(i+1) * h
+			theta = math.Pi * float64(m)
+		case poisson.Neumann:
+			h = 1.0 / float64(n)
+			x = (float64(i) + 0.5) * h
+			theta = math.Pi * float64(m)
+		}
+
+		factor := math.Sin(theta*h) / h
+		if bc == poisson.Neumann {
+			u[i] = math.Cos(theta * x)
+			du[i] = -math.Sin(theta*x) * factor
+		} else {
+			u[i] = math.Sin(theta * x)
+			du[i] = math.Cos(theta*x) * factor
+		}
+	}
+
+	return h, u, du
+}
+
+func TestGrad1DModes(t *testing.T) {
+	for _, bc := range []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann} {
+		n, m := 12, 2
+		h, u, want := gradMode(n, m, bc)
+
+		got := make([]float64, n)
+		Grad1D(got, u, h, bc)
+
+		for i := range n {
+			if math.Abs(got[i]-want[i]) > 1e-12 {
+				t.Fatalf("%v mode m=%d i=%d: got %v want %v", bc, m, i, got[i], want[i])
+			}
+		}
+
+		// In place gives the same result.
+		Grad1D(u, u, h, bc)
+		for i := range n {
+			if u[i] != got[i] {
+				t.Fatalf("%v in-place i=%d: got %v want %v", bc, i, u[i], got[i])
+			}
+		}
+	}
+}
+
+func TestGrad1DConstant(t *testing.T) {
+	n := 7
+	src := make([]float64, n)
+	for i := range src {
+		src[i] = 3.0
+	}
+
+	dst := make([]float64, n)
+	for _, bc := range []poisson.BCType{poisson.Periodic, poisson.Neumann} {
+		Grad1D(dst, src, 0.1, bc)
+		for i := range n {
+			if dst[i] != 0 {
+				t.Fatalf("%v constant i=%d: got %v want 0", bc, i, dst[i])
+			}
+		}
+	}
+}
+
+func TestGrad2DMixedModes(t *testing.T) {
+	nx, ny := 10, 8
+	bc := [2]poisson.BCType{poisson.Dirichlet, poisson.Neumann}
+	hx, ux, dux := gradMode(nx, 3, bc[0])
+	hy, uy, duy := gradMode(ny, 1, bc[1])
+
+	src := make([]float64, nx*ny)
+	for i := range nx {
+		for j := range ny {
+			src[i*ny+j] = ux[i] * uy[j]
+		}
+	}
+
+	gx := make([]float64, nx*ny)
+	gy := src // in place for one component
+	Grad2D(gx, gy, src, grid.NewShape2D(nx, ny), [2]float64{hx, hy}, bc)
+
+	for i := range nx {
+		for j := range ny {
+			idx := i*ny + j
+			if want := dux[i] * uy[j]; math.Abs(gx[idx]-want) > 1e-12 {
+				t.Fatalf("gx i=%d j=%d: got %v want %v", i, j, gx[idx], want)
+			}
+			if want := ux[i] * duy[j]; math.Abs(gy[idx]-want) > 1e-12 {
+				t.Fatalf("gy i=%d j=%d: got %v want %v", i, j, gy[idx], want)
+			}
+		}
+	}
+}
+
+func TestGrad3DMixedModes(t *testing.T) {
+	nx, ny, nz := 6, 5, 8
+	bc := [3]poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+	hx, ux, dux := gradMode(nx, 1, bc[0])
+	hy, uy, duy := gradMode(ny, 2, bc[1])
+	hz, uz, duz := gradMode(nz, 3, bc[2])
+
+	total := nx * ny * nz
+	src := make([]float64, total)
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				src[(i*ny+j)*nz+k] = ux[i] * uy[j] * uz[k]
+			}
+		}
+	}
+
+	gx := make([]float64, total)
+	gy := make([]float64, total)
+	gz := make([]float64, total)
+	Grad3D(gx, gy, gz, src, grid.NewShape3D(nx, ny, nz), [3]float64{hx, hy, hz}, bc)
+
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				idx := (i*ny+j)*nz + k
+				want := [3]float64{dux[i] * uy[j] * uz[k], ux[i] * duy[j] * uz[k], ux[i] * uy[j] * duz[k]}
+				got := [3]float64{gx[idx], gy[idx], gz[idx]}
+				for axis := range 3 {
+					if math.Abs(got[axis]-want[axis]) > 1e-12 {
+						t.Fatalf("axis %d at (%d,%d,%d): got %v want %v", axis, i, j, k, got[axis], want[axis])
+					}
+				}
+			}
+		}
+	}
+}
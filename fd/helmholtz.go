@@ -0,0 +1,48 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// ApplyHelmholtz applies the Helmholtz operator (α - Δ) of
+// poisson.NewHelmholtzPlan to src and writes into dst: α*u plus the
+// negative Laplacian stencil of Apply1D, Apply2D or Apply3D. The grid is
+// described by the n, h and bc slices of the plan (one entry per axis, 1 to
+// 3 axes), so the residual of a solve is f - ApplyHelmholtz(u) with the
+// plan's own arguments; alpha = 0 gives the Poisson operator -Δ. It does
+// nothing if the slices disagree in length or src and dst do not match the
+// grid. It is safe to call with dst == src.
+func ApplyHelmholtz(dst, src []float64, alpha float64, n []int, h []float64, bc []poisson.BCType) {
+	dim := len(n)
+	if dim < 1 || dim > 3 || len(h) != dim || len(bc) != dim {
+		return
+	}
+
+	shape := grid.Shape{1, 1, 1}
+	copy(shape[:], n)
+
+	total := shape.Size()
+	if total == 0 || len(src) != total || len(dst) != total {
+		return
+	}
+
+	if aliases(src, dst) {
+		src = append([]float64(nil), src...)
+	}
+
+	switch dim {
+	case 1:
+		Apply1D(dst, src, h[0], bc[0])
+	case 2:
+		Apply2D(dst, src, shape, [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+	case 3:
+		Apply3D(dst, src, shape, [3]float64{h[0], h[1], h[2]}, [3]poisson.BCType{bc[0], bc[1], bc[2]})
+	}
+
+	if alpha != 0 {
+		for i, u := range src {
+			dst[i] += alpha * u
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestApplyHelmholtzResidualOfPlanSolve(t *testing.T) {
+	for _, tc := range []struct {
+		n     []int
+		h     []float64
+		bc    []poisson.BCType
+		alpha float64
+	}{
+		{[]int{16}, []float64{0.1}, []poisson.BCType{poisson.Neumann}, 3},
+		{[]int{8, 6}, []float64{0.1, 0.2}, []poisson.BCType{poisson.Dirichlet, poisson.Periodic}, 2.5},
+		{[]int{4, 5, 6}, []float64{0.3, 0.2, 0.1}, []poisson.BCType{poisson.Periodic, poisson.Neumann, poisson.Dirichlet}, -1.5},
+	} {
+		plan, err := poisson.NewHelmholtzPlan(len(tc.n), tc.n, tc.h, tc.bc, tc.alpha)
+		if err != nil {
+			t.Fatalf("NewHelmholtzPlan failed: %v", err)
+		}
+
+		size := 1
+		for _, n := range tc.n {
+			size *= n
+		}
+
+		rhs := testField(size)
+		u := make([]float64, len(rhs))
+		if err := plan.Solve(u, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+
+		// In place: u becomes (α - Δ)u, which must reproduce rhs.
+		ApplyHelmholtz(u, u, tc.alpha, tc.n, tc.h, tc.bc)
+		for i := range rhs {
+			if math.Abs(u[i]-rhs[i]) > 1e-9 {
+				t.Fatalf("n=%v: (α - Δ)u[%d] = %v, want %v", tc.n, i, u[i], rhs[i])
+			}
+		}
+	}
+}
+
+func TestApplyHelmholtzZeroAlphaIsLaplacian(t *testing.T) {
+	n := []int{5, 4}
+	h := []float64{0.2, 0.3}
+	bc := []poisson.BCType{poisson.Neumann, poisson.Dirichlet}
+
+	u := testField(20)
+	got := make([]float64, 20)
+	want := make([]float64, 20)
+	ApplyHelmholtz(got, u, 0, n, h, bc)
+	Apply2D(want, u, grid.NewShape2D(5, 4), [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("i=%d: got %v want %v", i, got[i], want[i])
+		}
+	}
+
+	// Mismatched slices leave dst alone.
+	ApplyHelmholtz(got, u, 1, n, h[:1], bc)
+	if got[0] != want[0] {
+		t.Errorf("dst modified for mismatched h: %v", got[0])
+	}
+}
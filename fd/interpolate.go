@@ -0,0 +1,136 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// NodeCount returns the number of nodes between n cells along an axis with
+// boundary condition bc: n for Periodic, where node 0 lies between the last
+// and the first cell, and the n-1 interior nodes otherwise.
+//
+// A cell grid of n points at x = (i+0.5)h, the grid of a Neumann axis, and
+// the node grid of NodeCount points at x = (i+1)h, the grid of a Dirichlet
+// axis, cover the same domain with the same spacing h = L/n.
+func NodeCount(n int, bc poisson.BCType) int {
+	if bc == poisson.Periodic {
+		return n
+	}
+
+	return max(n-1, 0)
+}
+
+// CellCount returns the number of cells around n nodes along an axis with
+// boundary condition bc; it inverts NodeCount.
+func CellCount(n int, bc poisson.BCType) int {
+	if bc == poisson.Periodic {
+		return n
+	}
+
+	return n + 1
+}
+
+// CellsToNodes interpolates the cell-centered field src of the given shape
+// to the nodes along axis and writes into dst, whose shape has
+// NodeCount(shape[axis], bc) points along axis and is otherwise the same.
+// Each node takes the mean of its two cells, which is second-order
+// accurate; on a Periodic axis node 0 averages the last and the first cell.
+// It does nothing if the lengths do not match.
+func CellsToNodes(dst, src []float64, shape grid.Shape, axis int, bc poisson.BCType) {
+	n := shape[axis]
+	m := NodeCount(n, bc)
+	outer, inner, ok := axisExtents(dst, src, shape, axis, m)
+	if !ok {
+		return
+	}
+
+	for o := range outer {
+		s := src[o*n*inner : (o+1)*n*inner]
+		d := dst[o*m*inner : (o+1)*m*inner]
+
+		for i := range m {
+			// Node i of a periodic axis lies before cell i, otherwise after.
+			left, right := i, i+1
+			if bc == poisson.Periodic {
+				left, right = (i+n-1)%n, i
+			}
+
+			for r := range inner {
+				d[i*inner+r] = 0.5 * (s[left*inner+r] + s[right*inner+r])
+			}
+		}
+	}
+}
+
+// NodesToCells interpolates the node field src of the given shape to the
+// cells along axis and writes into dst, whose shape has
+// CellCount(shape[axis], bc) points along axis and is otherwise the same.
+// Each cell takes the mean of its two nodes; the boundary nodes beyond the
+// ends hold the ghost values of Apply1D, zero for Dirichlet and the nearest
+// node for Neumann (zero slope), so both conventions stay second-order
+// accurate. It does nothing if the lengths do not match.
+func NodesToCells(dst, src []float64, shape grid.Shape, axis int, bc poisson.BCType) {
+	n := shape[axis]
+	m := CellCount(n, bc)
+	outer, inner, ok := axisExtents(dst, src, shape, axis, m)
+	if !ok {
+		return
+	}
+
+	for o := range outer {
+		s := src[o*n*inner : (o+1)*n*inner]
+		d := dst[o*m*inner : (o+1)*m*inner]
+
+		for i := range m {
+			for r := range inner {
+				var left, right float64
+				if bc == poisson.Periodic {
+					// Cell i lies between nodes i and i+1.
+					left, right = s[i*inner+r], s[((i+1)%n)*inner+r]
+				} else {
+					// Cell i lies between nodes i-1 and i.
+					left, right = nodeGhost(s, 0, r, inner, bc), nodeGhost(s, n-1, r, inner, bc)
+					if i > 0 {
+						left = s[(i-1)*inner+r]
+					}
+					if i < n {
+						right = s[i*inner+r]
+					}
+				}
+
+				d[i*inner+r] = 0.5 * (left + right)
+			}
+		}
+	}
+}
+
+// nodeGhost returns the boundary value next to node i of line r: zero for
+// Dirichlet and the node itself for Neumann.
+func nodeGhost(s []float64, i, r, inner int, bc poisson.BCType) float64 {
+	if bc == poisson.Neumann {
+		return s[i*inner+r]
+	}
+
+	return 0
+}
+
+// axisExtents returns the number of lines before and the stride after axis
+// in shape, and whether src matches shape and dst the shape with m points
+// along axis.
+func axisExtents(dst, src []float64, shape grid.Shape, axis, m int) (outer, inner int, ok bool) {
+	if axis < 0 || axis > 2 || shape.Size() == 0 || m == 0 {
+		return 0, 0, false
+	}
+
+	outer, inner = 1, 1
+	for a := range axis {
+		outer *= shape[a]
+	}
+	for a := axis + 1; a < 3; a++ {
+		inner *= shape[a]
+	}
+
+	ok = len(src) == shape.Size() && len(dst) == outer*m*inner
+
+	return outer, inner, ok
+}
@@ -0,0 +1,121 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// interpolationErrors returns the max errors of CellsToNodes and
+// NodesToCells for f sampled on n cells of the unit interval.
+func interpolationErrors(n int, bc poisson.BCType, f func(x float64) float64) (toNodes, toCells float64) {
+	h := 1.0 / float64(n)
+	m := NodeCount(n, bc)
+
+	// Periodic nodes sit at x = ih, the others at the interior x = (i+1)h.
+	nodeX := func(i int) float64 {
+		if bc == poisson.Periodic {
+			return float64(i) * h
+		}
+
+		return float64(i+1) * h
+	}
+
+	cells := make([]float64, n)
+	for i := range cells {
+		cells[i] = f((float64(i) + 0.5) * h)
+	}
+
+	nodes := make([]float64, m)
+	for i := range nodes {
+		nodes[i] = f(nodeX(i))
+	}
+
+	gotNodes := make([]float64, m)
+	CellsToNodes(gotNodes, cells, grid.NewShape1D(n), 0, bc)
+
+	gotCells := make([]float64, n)
+	NodesToCells(gotCells, nodes, grid.NewShape1D(m), 0, bc)
+
+	return ErrorLinf(gotNodes, nodes), ErrorLinf(gotCells, cells)
+}
+
+func TestInterpolationSecondOrder(t *testing.T) {
+	cases := []struct {
+		bc poisson.BCType
+		f  func(x float64) float64
+	}{
+		{poisson.Periodic, func(x float64) float64 { return math.Sin(2*math.Pi*x) + 0.5 }},
+		{poisson.Dirichlet, func(x float64) float64 { return math.Sin(math.Pi * x) }},
+		{poisson.Neumann, func(x float64) float64 { return math.Cos(math.Pi * x) }},
+	}
+
+	for _, tc := range cases {
+		nodes16, cells16 := interpolationErrors(16, tc.bc, tc.f)
+		nodes32, cells32 := interpolationErrors(32, tc.bc, tc.f)
+
+		if ratio := nodes16 / nodes32; ratio < 3.5 {
+			t.Errorf("%v CellsToNodes: error ratio %v (%v -> %v), want ~4", tc.bc, ratio, nodes16, nodes32)
+		}
+		if ratio := cells16 / cells32; ratio < 3.5 {
+			t.Errorf("%v NodesToCells: error ratio %v (%v -> %v), want ~4", tc.bc, ratio, cells16, cells32)
+		}
+	}
+}
+
+func TestInterpolationAlongAxis(t *testing.T) {
+	nx, ny, nz := 3, 5, 2
+	shape := grid.NewShape3D(nx, ny, nz)
+	src := testField(shape.Size())
+
+	for _, bc := range []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann} {
+		m := NodeCount(ny, bc)
+		dst := make([]float64, nx*m*nz)
+		CellsToNodes(dst, src, shape, 1, bc)
+
+		back := make([]float64, shape.Size())
+		NodesToCells(back, dst, grid.NewShape3D(nx, m, nz), 1, bc)
+
+		line := make([]float64, ny)
+		want := make([]float64, m)
+		wantBack := make([]float64, ny)
+		for i := range nx {
+			for k := range nz {
+				for j := range ny {
+					line[j] = src[(i*ny+j)*nz+k]
+				}
+
+				CellsToNodes(want, line, grid.NewShape1D(ny), 0, bc)
+				NodesToCells(wantBack, want, grid.NewShape1D(m), 0, bc)
+
+				for j := range m {
+					if got := dst[(i*m+j)*nz+k]; got != want[j] {
+						t.Fatalf("%v nodes (%d,%d,%d): got %v want %v", bc, i, j, k, got, want[j])
+					}
+				}
+				for j := range ny {
+					if got := back[(i*ny+j)*nz+k]; got != wantBack[j] {
+						t.Fatalf("%v cells (%d,%d,%d): got %v want %v", bc, i, j, k, got, wantBack[j])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestInterpolationConstant(t *testing.T) {
+	cells := []float64{2, 2, 2, 2}
+	nodes := make([]float64, 3)
+	CellsToNodes(nodes, cells, grid.NewShape1D(4), 0, poisson.Neumann)
+
+	back := make([]float64, 4)
+	NodesToCells(back, nodes, grid.NewShape1D(3), 0, poisson.Neumann)
+
+	for i, v := range back {
+		if v != 2 {
+			t.Errorf("Neumann round trip of a constant: cell %d = %v, want 2", i, v)
+		}
+	}
+}
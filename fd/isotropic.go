@@ -0,0 +1,157 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// applyAxis writes the second difference (2u_i - u_{i-1} - u_{i+1})/h² of
+// src along one axis of shape into dst, with the ghost values of Apply1D.
+// dst and src must not alias.
+func applyAxis(dst, src []float64, shape grid.Shape, axis int, h float64, bc poisson.BCType) {
+	n := shape[axis]
+	stride := grid.RowMajorStride(shape)[axis]
+	invH2 := 1.0 / (h * h)
+
+	for idx, u := range src {
+		left, right := neighbors(src, idx, idx/stride%n, n, stride, bc)
+		dst[idx] = (2.0*u - left - right) * invH2
+	}
+}
+
+// isotropicWeights returns the weights of the product terms of the
+// isotropic stencils, as for poisson.Isotropic9 and poisson.Isotropic27:
+// (h_a² + h_b²)/12 for the axis pairs (x,y), (y,z), (x,z), and
+// (hx²hy² + hy²hz² + hz²hx²)/90 for the triple product.
+func isotropicWeights(h [3]float64) (pair [3]float64, triple float64) {
+	h2 := [3]float64{h[0] * h[0], h[1] * h[1], h[2] * h[2]}
+	pair = [3]float64{(h2[0] + h2[1]) / 12, (h2[1] + h2[2]) / 12, (h2[0] + h2[2]) / 12}
+	triple = (h2[0]*h2[1] + h2[1]*h2[2] + h2[2]*h2[0]) / 90
+
+	return pair, triple
+}
+
+// Apply2D9 applies the isotropic 9-point negative Laplacian of
+// poisson.Isotropic9 to src and writes into dst. With L_x and L_y the
+// second differences of Apply2D along each axis it is
+//
+//	L_x + L_y - (hx² + hy²)/12 · L_x L_y,
+//
+// which for hx = hy = h is (20u - 4Σ edge - Σ corner neighbors)/(6h²), with
+// the ghost values of Apply2D beyond the edges and corners. It is safe to
+// call with dst == src.
+func Apply2D9(dst, src []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	if nx == 0 || ny == 0 {
+		return
+	}
+
+	total := nx * ny
+	if len(src) != total || len(dst) != total {
+		return
+	}
+
+	shape = grid.NewShape2D(nx, ny)
+	lx := make([]float64, total)
+	ly := make([]float64, total)
+	lxy := make([]float64, total)
+	applyAxis(lx, src, shape, 0, h[0], bc[0])
+	applyAxis(ly, src, shape, 1, h[1], bc[1])
+	applyAxis(lxy, ly, shape, 0, h[0], bc[0])
+
+	pair, _ := isotropicWeights([3]float64{h[0], h[1], 0})
+	for idx := range dst {
+		dst[idx] = lx[idx] + ly[idx] - pair[0]*lxy[idx]
+	}
+}
+
+// Apply3D27 applies the isotropic 27-point negative Laplacian of
+// poisson.Isotropic27 to src and writes into dst:
+//
+//	Σ L_a - Σ_{a<b} (h_a² + h_b²)/12 · L_a L_b + (hx²hy² + hy²hz² + hz²hx²)/90 · L_x L_y L_z,
+//
+// which for equal spacings is (128u - 14Σ face - 3Σ edge - Σ corner
+// neighbors)/(30h²). It is safe to call with dst == src.
+func Apply3D27(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
+	if shape[0] == 0 || shape[1] == 0 || shape[2] == 0 {
+		return
+	}
+
+	total := shape.Size()
+	if len(src) != total || len(dst) != total {
+		return
+	}
+
+	var axis [3][]float64
+	for a := range axis {
+		axis[a] = make([]float64, total)
+		applyAxis(axis[a], src, shape, a, h[a], bc[a])
+	}
+
+	sum := make([]float64, total)
+	for idx := range sum {
+		sum[idx] = axis[0][idx] + axis[1][idx] + axis[2][idx]
+	}
+
+	pair, triple := isotropicWeights(h)
+	tmp := make([]float64, total)
+	for e, ab := range [3][2]int{{0, 1}, {1, 2}, {0, 2}} {
+		applyAxis(tmp, axis[ab[1]], shape, ab[0], h[ab[0]], bc[ab[0]])
+		for idx, v := range tmp {
+			sum[idx] -= pair[e] * v
+		}
+	}
+
+	// L_x L_y L_z, reusing the L_z buffer.
+	applyAxis(tmp, axis[2], shape, 1, h[1], bc[1])
+	applyAxis(axis[2], tmp, shape, 0, h[0], bc[0])
+	for idx, v := range axis[2] {
+		sum[idx] += triple * v
+	}
+
+	copy(dst, sum)
+}
+
+// Eigenvalues2D9 returns the eigenvalues of Apply2D9 as a row-major table
+// over the modes of the solver's transforms, with the per-axis indices of
+// Eigenvalues: λ = λx + λy - (hx² + hy²)/12 · λx λy.
+func Eigenvalues2D9(shape grid.Shape, h [2]float64, bc [2]poisson.BCType) []float64 {
+	nx, ny := shape[0], shape[1]
+	ex := Eigenvalues(nx, h[0], bc[0])
+	ey := Eigenvalues(ny, h[1], bc[1])
+	pair, _ := isotropicWeights([3]float64{h[0], h[1], 0})
+
+	eig := make([]float64, nx*ny)
+	for i, lx := range ex {
+		for j, ly := range ey {
+			eig[i*ny+j] = lx + ly - pair[0]*lx*ly
+		}
+	}
+
+	return eig
+}
+
+// Eigenvalues3D27 returns the eigenvalues of Apply3D27 as a row-major table
+// over the modes of the solver's transforms, with the per-axis indices of
+// Eigenvalues.
+func Eigenvalues3D27(shape grid.Shape, h [3]float64, bc [3]poisson.BCType) []float64 {
+	nx, ny, nz := shape[0], shape[1], shape[2]
+	ex := Eigenvalues(nx, h[0], bc[0])
+	ey := Eigenvalues(ny, h[1], bc[1])
+	ez := Eigenvalues(nz, h[2], bc[2])
+	pair, triple := isotropicWeights(h)
+
+	eig := make([]float64, nx*ny*nz)
+	for i, lx := range ex {
+		for j, ly := range ey {
+			for k, lz := range ez {
+				eig[(i*ny+j)*nz+k] = lx + ly + lz -
+					pair[0]*lx*ly - pair[1]*ly*lz - pair[2]*lx*lz +
+					triple*lx*ly*lz
+			}
+		}
+	}
+
+	return eig
+}
@@ -0,0 +1,189 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// For equal periodic spacings the product form reduces to the classic
+// weights (20, -4, -1)/6 and (128, -14, -3, -1)/30.
+func TestIsotropicStencilWeights(t *testing.T) {
+	const n, h = 5, 0.25
+
+	at := func(i int) int { return (i%n + n) % n }
+
+	u := testField(n * n)
+	got := make([]float64, n*n)
+	Apply2D9(got, u, grid.NewShape2D(n, n), [2]float64{h, h}, [2]poisson.BCType{poisson.Periodic, poisson.Periodic})
+
+	for i := range n {
+		for j := range n {
+			want := 20 * u[i*n+j]
+			for di := -1; di <= 1; di++ {
+				for dj := -1; dj <= 1; dj++ {
+					switch math.Abs(float64(di)) + math.Abs(float64(dj)) {
+					case 1:
+						want -= 4 * u[at(i+di)*n+at(j+dj)]
+					case 2:
+						want -= u[at(i+di)*n+at(j+dj)]
+					}
+				}
+			}
+			want /= 6 * h * h
+
+			if math.Abs(got[i*n+j]-want) > 1e-10 {
+				t.Fatalf("9-point (%d,%d): got %v want %v", i, j, got[i*n+j], want)
+			}
+		}
+	}
+
+	weights := [4]float64{128, -14, -3, -1}
+	u = testField(n * n * n)
+	got = make([]float64, n*n*n)
+	Apply3D27(got, u, grid.NewShape3D(n, n, n), [3]float64{h, h, h}, [3]poisson.BCType{poisson.Periodic, poisson.Periodic, poisson.Periodic})
+
+	for i := range n {
+		for j := range n {
+			for k := range n {
+				want := 0.0
+				for di := -1; di <= 1; di++ {
+					for dj := -1; dj <= 1; dj++ {
+						for dk := -1; dk <= 1; dk++ {
+							w := weights[di*di+dj*dj+dk*dk]
+							want += w * u[(at(i+di)*n+at(j+dj))*n+at(k+dk)]
+						}
+					}
+				}
+				want /= 30 * h * h
+
+				if idx := (i*n+j)*n + k; math.Abs(got[idx]-want) > 1e-10 {
+					t.Fatalf("27-point (%d,%d,%d): got %v want %v", i, j, k, got[idx], want)
+				}
+			}
+		}
+	}
+}
+
+// Products of the per-axis modes are eigenvectors with the tabulated
+// eigenvalues under every boundary condition.
+func TestIsotropicEigenvalues(t *testing.T) {
+	n := [3]int{6, 5, 4}
+	bc := [3]poisson.BCType{poisson.Neumann, poisson.Dirichlet, poisson.Periodic}
+	m := [3]int{2, 3, 1}
+
+	var h [3]float64
+	var mode [3][]float64
+	for a := range 3 {
+		h[a], mode[a], _ = gradMode(n[a], m[a], bc[a])
+	}
+
+	// Dirichlet mode m has index m-1; the others index m.
+	index := [3]int{m[0], m[1] - 1, m[2]}
+
+	shape := grid.NewShape3D(n[0], n[1], n[2])
+	u := make([]float64, shape.Size())
+	for i := range n[0] {
+		for j := range n[1] {
+			for k := range n[2] {
+				u[(i*n[1]+j)*n[2]+k] = mode[0][i] * mode[1][j] * mode[2][k]
+			}
+		}
+	}
+
+	got := make([]float64, len(u))
+	Apply3D27(got, u, shape, h, bc)
+	lambda := Eigenvalues3D27(shape, h, bc)[(index[0]*n[1]+index[1])*n[2]+index[2]]
+	for idx := range u {
+		if math.Abs(got[idx]-lambda*u[idx]) > 1e-9 {
+			t.Fatalf("27-point idx=%d: got %v want %v", idx, got[idx], lambda*u[idx])
+		}
+	}
+
+	shape2 := grid.NewShape2D(n[0], n[1])
+	u2 := make([]float64, n[0]*n[1])
+	for i := range n[0] {
+		for j := range n[1] {
+			u2[i*n[1]+j] = mode[0][i] * mode[1][j]
+		}
+	}
+
+	got2 := make([]float64, len(u2))
+	h2 := [2]float64{h[0], h[1]}
+	bc2 := [2]poisson.BCType{bc[0], bc[1]}
+	Apply2D9(got2, u2, shape2, h2, bc2)
+	lambda = Eigenvalues2D9(shape2, h2, bc2)[index[0]*n[1]+index[1]]
+	for idx := range u2 {
+		if math.Abs(got2[idx]-lambda*u2[idx]) > 1e-9 {
+			t.Fatalf("9-point idx=%d: got %v want %v", idx, got2[idx], lambda*u2[idx])
+		}
+	}
+}
+
+// The 9-point symbol of a plane wave depends far less on its direction than
+// the 5-point symbol.
+func TestIsotropic9Anisotropy(t *testing.T) {
+	const h, k = 1.0, 0.5
+
+	symbol := func(theta float64, isotropic bool) float64 {
+		lx := 4 * math.Pow(math.Sin(k*math.Cos(theta)*h/2), 2) / (h * h)
+		ly := 4 * math.Pow(math.Sin(k*math.Sin(theta)*h/2), 2) / (h * h)
+		if !isotropic {
+			return lx + ly
+		}
+
+		return lx + ly - h*h/6*lx*ly
+	}
+
+	spread := func(isotropic bool) float64 {
+		return math.Abs(symbol(0, isotropic) - symbol(math.Pi/4, isotropic))
+	}
+
+	if spread(true) > 0.1*spread(false) {
+		t.Errorf("9-point spread %v not well below 5-point spread %v", spread(true), spread(false))
+	}
+}
+
+// The solver with WithStencil inverts Apply2D9 and Apply3D27.
+func TestIsotropicMatchesSolver(t *testing.T) {
+	n := [3]int{8, 6, 5}
+	h := [3]float64{0.1, 0.15, 0.2}
+	bc := [3]poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic}
+
+	shape := grid.NewShape3D(n[0], n[1], n[2])
+	u := testField(shape.Size())
+	f := make([]float64, len(u))
+	Apply3D27(f, u, shape, h, bc)
+
+	plan, err := poisson.NewPlan(3, n[:], h[:], bc[:], poisson.WithStencil(poisson.Isotropic27))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]float64, len(u))
+	if err := plan.Solve(got, f); err != nil {
+		t.Fatal(err)
+	}
+	if e := ErrorLinf(got, u); e > 1e-9 {
+		t.Errorf("27-point solve error %v", e)
+	}
+
+	u2 := testField(n[0] * n[1])
+	f2 := make([]float64, len(u2))
+	Apply2D9(f2, u2, grid.NewShape2D(n[0], n[1]), [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+
+	plan2, err := poisson.NewPlan(2, n[:2], h[:2], bc[:2], poisson.WithStencil(poisson.Isotropic9))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got2 := make([]float64, len(u2))
+	if err := plan2.Solve(got2, f2); err != nil {
+		t.Fatal(err)
+	}
+	if e := ErrorLinf(got2, u2); e > 1e-9 {
+		t.Errorf("9-point solve error %v", e)
+	}
+}
@@ -2,6 +2,7 @@ package fd
 
 import (
 	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/par"
 	"github.com/MeKo-Tech/algo-pde/poisson"
 )
 
@@ -72,6 +73,13 @@ func Apply1D(dst, src []float64, h float64, bc poisson.BCType) {
 // The result is (2*u - u_{i-1} - u_{i+1})/hx^2 + (2*u - u_{j-1} - u_{j+1})/hy^2
 // with per-axis boundary handling set by bc. It is safe to call with dst == src.
 func Apply2D(dst, src []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType) {
+	Apply2DParallel(dst, src, shape, h, bc, 1)
+}
+
+// Apply2DParallel is Apply2D split over workers goroutines by rows of x;
+// workers <= 0 selects runtime.GOMAXPROCS. The result does not depend on the
+// worker count.
+func Apply2DParallel(dst, src []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType, workers int) {
 	nx := shape[0]
 	ny := shape[1]
 	if nx == 0 || ny == 0 {
@@ -92,105 +100,20 @@ func Apply2D(dst, src []float64, shape grid.Shape, h [2]float64, bc [2]poisson.B
 	invHx2 := 1.0 / (h[0] * h[0])
 	invHy2 := 1.0 / (h[1] * h[1])
 
-	for i := range nx {
-		row := i * ny
-
-		for j := range ny {
-			idx := row + j
-			u := src[idx]
-
-			var left, right float64
-			switch {
-			case i > 0:
-				left = src[(i-1)*ny+j]
-			case bc[0] == poisson.Periodic:
-				left = src[(nx-1)*ny+j]
-			case bc[0] == poisson.Neumann:
-				left = src[idx]
-			default:
-				left = 0
-			}
-
-			switch {
-			case i+1 < nx:
-				right = src[(i+1)*ny+j]
-			case bc[0] == poisson.Periodic:
-				right = src[j]
-			case bc[0] == poisson.Neumann:
-				right = src[idx]
-			default:
-				right = 0
-			}
+	_ = par.For(par.Workers(workers), nx, func(_, start, end int) error {
+		for i := start; i < end; i++ {
+			row := i * ny
 
-			var down, up float64
-			switch {
-			case j > 0:
-				down = src[row+j-1]
-			case bc[1] == poisson.Periodic:
-				down = src[row+ny-1]
-			case bc[1] == poisson.Neumann:
-				down = src[idx]
-			default:
-				down = 0
-			}
-
-			switch {
-			case j+1 < ny:
-				up = src[row+j+1]
-			case bc[1] == poisson.Periodic:
-				up = src[row]
-			case bc[1] == poisson.Neumann:
-				up = src[idx]
-			default:
-				up = 0
-			}
-
-			dst[idx] = (2.0*u-left-right)*invHx2 + (2.0*u-down-up)*invHy2
-		}
-	}
-}
-
-// Apply3D applies the 3D negative Laplacian stencil to src and writes into dst.
-// The result sums 1D stencils in x/y/z with per-axis boundary handling set by bc.
-// It is safe to call with dst == src.
-func Apply3D(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
-	nx := shape[0]
-	ny := shape[1]
-	nz := shape[2]
-	if nx == 0 || ny == 0 || nz == 0 {
-		return
-	}
-
-	total := nx * ny * nz
-	if len(src) != total || len(dst) != total {
-		return
-	}
-
-	if &dst[0] == &src[0] {
-		tmp := make([]float64, total)
-		copy(tmp, src)
-		src = tmp
-	}
-
-	invHx2 := 1.0 / (h[0] * h[0])
-	invHy2 := 1.0 / (h[1] * h[1])
-	invHz2 := 1.0 / (h[2] * h[2])
-
-	plane := ny * nz
-	for i := range nx {
-		iPlane := i * plane
-		for j := range ny {
-			row := iPlane + j*nz
-			for k := range nz {
-				idx := row + k
+			for j := range ny {
+				idx := row + j
 				u := src[idx]
 
 				var left, right float64
 				switch {
 				case i > 0:
-					left = src[idx-plane]
+					left = src[(i-1)*ny+j]
 				case bc[0] == poisson.Periodic:
-					left = src[idx+plane*(nx-1)]
+					left = src[(nx-1)*ny+j]
 				case bc[0] == poisson.Neumann:
 					left = src[idx]
 				default:
@@ -199,9 +122,9 @@ func Apply3D(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.B
 
 				switch {
 				case i+1 < nx:
-					right = src[idx+plane]
+					right = src[(i+1)*ny+j]
 				case bc[0] == poisson.Periodic:
-					right = src[idx-plane*(nx-1)]
+					right = src[j]
 				case bc[0] == poisson.Neumann:
 					right = src[idx]
 				default:
@@ -211,9 +134,9 @@ func Apply3D(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.B
 				var down, up float64
 				switch {
 				case j > 0:
-					down = src[idx-nz]
+					down = src[row+j-1]
 				case bc[1] == poisson.Periodic:
-					down = src[row+(ny-1)*nz+k]
+					down = src[row+ny-1]
 				case bc[1] == poisson.Neumann:
 					down = src[idx]
 				default:
@@ -222,42 +145,192 @@ func Apply3D(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.B
 
 				switch {
 				case j+1 < ny:
-					up = src[idx+nz]
+					up = src[row+j+1]
 				case bc[1] == poisson.Periodic:
-					up = src[iPlane+k]
+					up = src[row]
 				case bc[1] == poisson.Neumann:
 					up = src[idx]
 				default:
 					up = 0
 				}
 
-				var back, front float64
-				switch {
-				case k > 0:
-					back = src[idx-1]
-				case bc[2] == poisson.Periodic:
-					back = src[row+nz-1]
-				case bc[2] == poisson.Neumann:
-					back = src[idx]
-				default:
-					back = 0
-				}
+				dst[idx] = (2.0*u-left-right)*invHx2 + (2.0*u-down-up)*invHy2
+			}
+		}
 
-				switch {
-				case k+1 < nz:
-					front = src[idx+1]
-				case bc[2] == poisson.Periodic:
-					front = src[row]
-				case bc[2] == poisson.Neumann:
-					front = src[idx]
-				default:
-					front = 0
-				}
+		return nil
+	})
+}
+
+// Apply3D applies the 3D negative Laplacian stencil to src and writes into dst.
+// The result sums 1D stencils in x/y/z with per-axis boundary handling set by bc.
+// It is safe to call with dst == src.
+func Apply3D(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
+	Apply3DParallel(dst, src, shape, h, bc, 1)
+}
+
+// applyBlockBytes bounds the working set of one block of Apply3DParallel:
+// the rows of a y block in three adjacent x planes, which stay in cache
+// while the block sweeps along x.
+const applyBlockBytes = 1 << 18
+
+// Apply3DParallel is Apply3D split over workers goroutines by slabs of x
+// planes; workers <= 0 selects runtime.GOMAXPROCS. Each worker sweeps its
+// slab in blocks of y rows, so the neighbor planes of a block are reused
+// from cache instead of memory on large grids. The result does not depend
+// on the worker count.
+func Apply3DParallel(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType, workers int) {
+	nx := shape[0]
+	ny := shape[1]
+	nz := shape[2]
+	if nx == 0 || ny == 0 || nz == 0 {
+		return
+	}
+
+	total := nx * ny * nz
+	if len(src) != total || len(dst) != total {
+		return
+	}
+
+	if &dst[0] == &src[0] {
+		tmp := make([]float64, total)
+		copy(tmp, src)
+		src = tmp
+	}
 
-				dst[idx] = (2.0*u-left-right)*invHx2 +
-					(2.0*u-down-up)*invHy2 +
-					(2.0*u-back-front)*invHz2
+	invHx2 := 1.0 / (h[0] * h[0])
+	invHy2 := 1.0 / (h[1] * h[1])
+	invHz2 := 1.0 / (h[2] * h[2])
+
+	plane := ny * nz
+	block := max(1, applyBlockBytes/(3*8*nz))
+
+	_ = par.For(par.Workers(workers), nx, func(_, start, end int) error {
+		for j0 := 0; j0 < ny; j0 += block {
+			j1 := min(j0+block, ny)
+			for i := start; i < end; i++ {
+				iPlane := i * plane
+				for j := j0; j < j1; j++ {
+					row := iPlane + j*nz
+					for k := range nz {
+						idx := row + k
+						u := src[idx]
+
+						var left, right float64
+						switch {
+						case i > 0:
+							left = src[idx-plane]
+						case bc[0] == poisson.Periodic:
+							left = src[idx+plane*(nx-1)]
+						case bc[0] == poisson.Neumann:
+							left = src[idx]
+						default:
+							left = 0
+						}
+
+						switch {
+						case i+1 < nx:
+							right = src[idx+plane]
+						case bc[0] == poisson.Periodic:
+							right = src[idx-plane*(nx-1)]
+						case bc[0] == poisson.Neumann:
+							right = src[idx]
+						default:
+							right = 0
+						}
+
+						var down, up float64
+						switch {
+						case j > 0:
+							down = src[idx-nz]
+						case bc[1] == poisson.Periodic:
+							down = src[row+(ny-1)*nz+k]
+						case bc[1] == poisson.Neumann:
+							down = src[idx]
+						default:
+							down = 0
+						}
+
+						switch {
+						case j+1 < ny:
+							up = src[idx+nz]
+						case bc[1] == poisson.Periodic:
+							up = src[iPlane+k]
+						case bc[1] == poisson.Neumann:
+							up = src[idx]
+						default:
+							up = 0
+						}
+
+						var back, front float64
+						switch {
+						case k > 0:
+							back = src[idx-1]
+						case bc[2] == poisson.Periodic:
+							back = src[row+nz-1]
+						case bc[2] == poisson.Neumann:
+							back = src[idx]
+						default:
+							back = 0
+						}
+
+						switch {
+						case k+1 < nz:
+							front = src[idx+1]
+						case bc[2] == poisson.Periodic:
+							front = src[row]
+						case bc[2] == poisson.Neumann:
+							front = src[idx]
+						default:
+							front = 0
+						}
+
+						dst[idx] = (2.0*u-left-right)*invHx2 +
+							(2.0*u-down-up)*invHy2 +
+							(2.0*u-back-front)*invHz2
+					}
+				}
 			}
 		}
+
+		return nil
+	})
+}
+
+// ApplyND applies the N-dimensional negative Laplacian stencil to src and
+// writes into dst, for the row-major layout of grid.NDShape used by
+// poisson.PlanNDPeriodic (last axis contiguous). The result sums the 1D stencils of Apply1D along every
+// axis, with per-axis boundary handling set by bc; h and bc need one entry
+// per axis of shape. It is safe to call with dst == src.
+func ApplyND(dst, src []float64, shape grid.NDShape, h []float64, bc []poisson.BCType) {
+	dim := shape.Dim()
+	if dim == 0 || len(h) != dim || len(bc) != dim {
+		return
+	}
+
+	total := shape.Size()
+	if total == 0 || len(src) != total || len(dst) != total {
+		return
+	}
+
+	if aliases(src, dst) {
+		src = append([]float64(nil), src...)
+	}
+
+	strides := shape.Strides()
+	invH2 := make([]float64, dim)
+	for axis, spacing := range h {
+		invH2[axis] = 1.0 / (spacing * spacing)
+	}
+
+	for idx, u := range src {
+		sum := 0.0
+		for axis, s := range strides {
+			n := shape[axis]
+			left, right := neighbors(src, idx, idx/s%n, n, s, bc[axis])
+			sum += (2.0*u - left - right) * invH2[axis]
+		}
+
+		dst[idx] = sum
 	}
 }
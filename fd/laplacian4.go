@@ -0,0 +1,147 @@
+package fd
+
+import (
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// extended returns the value at position i+d along an axis of n points with
+// the given stride, where src[idx] is at position i. Positions outside the
+// axis follow the symmetry under which the solver's eigenbasis for bc is
+// closed: periodic wrap, odd reflection about the Dirichlet ghost points -1
+// and n (which are zero), even reflection about the Neumann faces at -1/2
+// and n-1/2.
+func extended(src []float64, idx, i, d, n, stride int, bc poisson.BCType) float64 {
+	j := i + d
+	sign := 1.0
+
+	switch bc {
+	case poisson.Periodic:
+		j = ((j % n) + n) % n
+
+	case poisson.Dirichlet:
+		period := 2 * (n + 1)
+		j = ((j+1)%period+period)%period - 1
+		if j == -1 || j == n {
+			return 0
+		}
+		if j > n {
+			j = 2*n - j
+			sign = -1
+		}
+
+	case poisson.Neumann:
+		period := 2 * n
+		j = (j%period + period) % period
+		if j >= n {
+			j = period - 1 - j
+		}
+	}
+
+	return sign * src[idx+(j-i)*stride]
+}
+
+// stencil4 returns the fourth-order wide second difference
+// 30u - 16(u_{-1} + u_{+1}) + (u_{-2} + u_{+2}) of src[idx] along an axis,
+// without the factor 1/(12h²).
+func stencil4(src []float64, idx, i, n, stride int, bc poisson.BCType) float64 {
+	near := extended(src, idx, i, -1, n, stride, bc) + extended(src, idx, i, 1, n, stride, bc)
+	far := extended(src, idx, i, -2, n, stride, bc) + extended(src, idx, i, 2, n, stride, bc)
+
+	return 30.0*src[idx] - 16.0*near + far
+}
+
+// Apply1D4 applies the fourth-order 1D negative Laplacian stencil to src and
+// writes into dst. The result is
+//
+//	(30*u_i - 16*(u_{i-1} + u_{i+1}) + u_{i-2} + u_{i+2}) / (12h²),
+//
+// the wide five-point stencil, with the values beyond the ends extended by
+// the symmetry of the boundary condition (see Eigenvalues4), so the modes of
+// the solver's transforms remain its eigenvectors. It is safe to call with
+// dst == src.
+func Apply1D4(dst, src []float64, h float64, bc poisson.BCType) {
+	n := len(src)
+	if n == 0 || len(dst) != n {
+		return
+	}
+
+	if aliases(src, dst) {
+		src = append([]float64(nil), src...)
+	}
+
+	inv12H2 := 1.0 / (12.0 * h * h)
+	for i := range n {
+		dst[i] = stencil4(src, i, i, n, 1, bc) * inv12H2
+	}
+}
+
+// Apply2D4 applies the fourth-order 2D negative Laplacian stencil to src and
+// writes into dst: the sum of the Apply1D4 stencils along x and y, with
+// per-axis boundary handling set by bc and the row-major layout of Apply2D.
+// It is safe to call with dst == src.
+func Apply2D4(dst, src []float64, shape grid.Shape, h [2]float64, bc [2]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	if nx == 0 || ny == 0 {
+		return
+	}
+
+	total := nx * ny
+	if len(src) != total || len(dst) != total {
+		return
+	}
+
+	if aliases(src, dst) {
+		src = append([]float64(nil), src...)
+	}
+
+	invHx := 1.0 / (12.0 * h[0] * h[0])
+	invHy := 1.0 / (12.0 * h[1] * h[1])
+
+	for i := range nx {
+		for j := range ny {
+			idx := i*ny + j
+			dst[idx] = stencil4(src, idx, i, nx, ny, bc[0])*invHx +
+				stencil4(src, idx, j, ny, 1, bc[1])*invHy
+		}
+	}
+}
+
+// Apply3D4 applies the fourth-order 3D negative Laplacian stencil to src and
+// writes into dst: the sum of the Apply1D4 stencils along x, y and z, with
+// per-axis boundary handling set by bc and the row-major layout of Apply3D.
+// It is safe to call with dst == src.
+func Apply3D4(dst, src []float64, shape grid.Shape, h [3]float64, bc [3]poisson.BCType) {
+	nx := shape[0]
+	ny := shape[1]
+	nz := shape[2]
+	if nx == 0 || ny == 0 || nz == 0 {
+		return
+	}
+
+	total := nx * ny * nz
+	if len(src) != total || len(dst) != total {
+		return
+	}
+
+	if aliases(src, dst) {
+		src = append([]float64(nil), src...)
+	}
+
+	invHx := 1.0 / (12.0 * h[0] * h[0])
+	invHy := 1.0 / (12.0 * h[1] * h[1])
+	invHz := 1.0 / (12.0 * h[2] * h[2])
+
+	plane := ny * nz
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				idx := i*plane + j*nz + k
+				dst[idx] = stencil4(src, idx, i, nx, plane, bc[0])*invHx +
+					stencil4(src, idx, j, ny, nz, bc[1])*invHy +
+					stencil4(src, idx, k, nz, 1, bc[2])*invHz
+			}
+		}
+	}
+}
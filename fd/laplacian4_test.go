@@ -0,0 +1,200 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// mode samples eigenmode m of the Laplacian for bc on n points, in the
+// index order of Eigenvalues.
+func mode(n, m int, bc poisson.BCType) []float64 {
+	u := make([]float64, n)
+	for i := range n {
+		switch bc {
+		case poisson.Periodic:
+			u[i] = math.Cos(2.0*math.Pi*float64(m*i)/float64(n) + 0.3)
+		case poisson.Dirichlet:
+			u[i] = math.Sin(math.Pi * float64((m+1)*(i+1)) / float64(n+1))
+		case poisson.Neumann:
+			u[i] = math.Cos(math.Pi * float64(m) * (float64(i) + 0.5) / float64(n))
+		}
+	}
+
+	return u
+}
+
+func TestApply1D4Modes(t *testing.T) {
+	for _, bc := range []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann} {
+		for _, n := range []int{1, 2, 3, 9} {
+			h := 0.25
+			eig := Eigenvalues4(n, h, bc)
+			for m := range n {
+				u := mode(n, m, bc)
+				dst := make([]float64, n)
+				Apply1D4(dst, u, h, bc)
+
+				for i := range n {
+					if want := eig[m] * u[i]; math.Abs(dst[i]-want) > 1e-10 {
+						t.Fatalf("%v n=%d m=%d i=%d: got %v want %v", bc, n, m, i, dst[i], want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestApply2D4And3D4Modes(t *testing.T) {
+	nx, ny, nz := 6, 5, 7
+	h := [3]float64{0.2, 0.3, 0.25}
+	bc := [3]poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic}
+	ux, uy, uz := mode(nx, 2, bc[0]), mode(ny, 3, bc[1]), mode(nz, 1, bc[2])
+	lx, ly, lz := Eigenvalues4(nx, h[0], bc[0])[2], Eigenvalues4(ny, h[1], bc[1])[3], Eigenvalues4(nz, h[2], bc[2])[1]
+
+	src2 := make([]float64, nx*ny)
+	for i := range nx {
+		for j := range ny {
+			src2[i*ny+j] = ux[i] * uy[j]
+		}
+	}
+
+	want2 := make([]float64, len(src2))
+	for i, v := range src2 {
+		want2[i] = (lx + ly) * v
+	}
+
+	Apply2D4(src2, src2, grid.NewShape2D(nx, ny), [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+	for i := range src2 {
+		if math.Abs(src2[i]-want2[i]) > 1e-10 {
+			t.Fatalf("2D i=%d: got %v want %v", i, src2[i], want2[i])
+		}
+	}
+
+	src3 := make([]float64, nx*ny*nz)
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				src3[(i*ny+j)*nz+k] = ux[i] * uy[j] * uz[k]
+			}
+		}
+	}
+
+	dst3 := make([]float64, len(src3))
+	Apply3D4(dst3, src3, grid.NewShape3D(nx, ny, nz), h, bc)
+	for i := range dst3 {
+		if want := (lx + ly + lz) * src3[i]; math.Abs(dst3[i]-want) > 1e-10 {
+			t.Fatalf("3D i=%d: got %v want %v", i, dst3[i], want)
+		}
+	}
+}
+
+// The fourth-order stencil's error for a smooth periodic function drops by
+// about 16 when h halves, against about 4 for the second-order stencil.
+func TestApply1D4FourthOrder(t *testing.T) {
+	maxErr := func(n int, apply func(dst, src []float64, h float64, bc poisson.BCType)) float64 {
+		h := 1.0 / float64(n)
+		u := make([]float64, n)
+		for i := range u {
+			u[i] = math.Exp(math.Sin(2.0 * math.Pi * float64(i) * h))
+		}
+
+		dst := make([]float64, n)
+		apply(dst, u, h, poisson.Periodic)
+
+		worst := 0.0
+		for i := range u {
+			x := 2.0 * math.Pi * float64(i) * h
+			// -u'' for u = exp(sin(2πx)).
+			exact := -4.0 * math.Pi * math.Pi * (math.Cos(x)*math.Cos(x) - math.Sin(x)) * u[i]
+			worst = math.Max(worst, math.Abs(dst[i]-exact))
+		}
+
+		return worst
+	}
+
+	ratio4 := maxErr(32, Apply1D4) / maxErr(64, Apply1D4)
+	if ratio4 < 14 || ratio4 > 18 {
+		t.Errorf("fourth-order error ratio = %v, want about 16", ratio4)
+	}
+
+	ratio2 := maxErr(32, Apply1D) / maxErr(64, Apply1D)
+	if ratio2 < 3.5 || ratio2 > 4.5 {
+		t.Errorf("second-order error ratio = %v, want about 4", ratio2)
+	}
+}
+
+func TestEigenvalues4(t *testing.T) {
+	n := 16
+	h := 1.0 / float64(n)
+
+	for _, tt := range []struct {
+		bc       poisson.BCType
+		specific []float64
+	}{
+		{poisson.Periodic, EigenvaluesPeriodic4(n, h)},
+		{poisson.Dirichlet, EigenvaluesDirichlet4(n, h)},
+		{poisson.Neumann, EigenvaluesNeumann4(n, h)},
+	} {
+		generic := Eigenvalues4(n, h, tt.bc)
+		second := Eigenvalues(n, h, tt.bc)
+
+		for m := range generic {
+			if generic[m] != tt.specific[m] {
+				t.Errorf("%v: eigenvalue[%d] mismatch: %v vs %v", tt.bc, m, generic[m], tt.specific[m])
+			}
+		}
+
+		if ZeroEigenvalueIndex(tt.bc) == 0 && math.Abs(generic[0]) > tolerance {
+			t.Errorf("%v: λ_0 = %v, expected 0", tt.bc, generic[0])
+		}
+
+		// The lowest nonzero mode is closer to the continuum value θ²/h².
+		m := 1
+		theta := math.Pi / float64(n)
+		if tt.bc == poisson.Periodic {
+			theta = 2.0 * math.Pi / float64(n)
+		} else if tt.bc == poisson.Dirichlet {
+			m = 0
+			theta = math.Pi / float64(n+1)
+		}
+
+		exact := theta * theta / (h * h)
+		if math.Abs(generic[m]-exact) >= math.Abs(second[m]-exact) {
+			t.Errorf("%v: λ = %v is not closer to %v than the second-order %v", tt.bc, generic[m], exact, second[m])
+		}
+	}
+}
+
+// The wide stencil evaluated on a grid.Field whose halo is filled with the
+// matching HaloBC agrees with Apply3D4: both share one ghost convention.
+func TestApply3D4MatchesFieldHalo(t *testing.T) {
+	shape := grid.NewShape3D(5, 4, 6)
+	h := [3]float64{0.3, 0.2, 0.5}
+	bc := [3]poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic}
+
+	src := testField(shape.Size())
+	want := make([]float64, len(src))
+	Apply3D4(want, src, shape, h, bc)
+
+	f := grid.NewField(shape, 2)
+	f.SetInterior(src)
+	f.FillHalo([3]grid.HaloBC{grid.HaloBC(bc[0]), grid.HaloBC(bc[1]), grid.HaloBC(bc[2])})
+
+	v := f.Interior()
+	for i := range shape[0] {
+		for j := range shape[1] {
+			for k := range shape[2] {
+				at := func(di, dj, dk int) float64 { return f.Data[v.Index(i+di, j+dj, k+dk)] }
+				got := (30*at(0, 0, 0) - 16*(at(-1, 0, 0)+at(1, 0, 0)) + at(-2, 0, 0) + at(2, 0, 0)) / (12 * h[0] * h[0])
+				got += (30*at(0, 0, 0) - 16*(at(0, -1, 0)+at(0, 1, 0)) + at(0, -2, 0) + at(0, 2, 0)) / (12 * h[1] * h[1])
+				got += (30*at(0, 0, 0) - 16*(at(0, 0, -1)+at(0, 0, 1)) + at(0, 0, -2) + at(0, 0, 2)) / (12 * h[2] * h[2])
+
+				if idx := (i*shape[1]+j)*shape[2] + k; math.Abs(got-want[idx]) > 1e-10 {
+					t.Fatalf("(%d,%d,%d): got %v want %v", i, j, k, got, want[idx])
+				}
+			}
+		}
+	}
+}
@@ -333,3 +333,129 @@ func TestApply3DNeumannModes(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyNDMatchesApply3D(t *testing.T) {
+	nx, ny, nz := 5, 4, 6
+	h := [3]float64{0.3, 0.2, 0.5}
+	bc := [3]poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic}
+
+	src := testField(nx * ny * nz)
+	want := make([]float64, len(src))
+	Apply3D(want, src, grid.NewShape3D(nx, ny, nz), h, bc)
+
+	got := make([]float64, len(src))
+	ApplyND(got, src, poisson.Shape{nx, ny, nz}, h[:], bc[:])
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > tolerance {
+			t.Fatalf("i=%d: got %v want %v", i, got[i], want[i])
+		}
+	}
+
+	ApplyND(src, src, poisson.Shape{nx, ny, nz}, h[:], bc[:])
+	for i := range src {
+		if src[i] != got[i] {
+			t.Fatalf("in-place i=%d: got %v want %v", i, src[i], got[i])
+		}
+	}
+}
+
+// A product of per-axis modes in 4D is an eigenvector with the summed
+// eigenvalue.
+func TestApplyNDModes(t *testing.T) {
+	shape := poisson.Shape{4, 5, 3, 6}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann, poisson.Dirichlet}
+	m := []int{1, 2, 1, 4}
+
+	h := make([]float64, len(shape))
+	modes := make([][]float64, len(shape))
+	lambda := 0.0
+	for axis, n := range shape {
+		h[axis], modes[axis], _ = gradMode(n, m[axis], bc[axis])
+
+		index := m[axis]
+		if bc[axis] == poisson.Dirichlet {
+			index--
+		}
+		lambda += Eigenvalues(n, h[axis], bc[axis])[index]
+	}
+
+	u := make([]float64, shape.Size())
+	for idx := range u {
+		u[idx] = 1
+		rest := idx
+		for axis := len(shape) - 1; axis >= 0; axis-- {
+			u[idx] *= modes[axis][rest%shape[axis]]
+			rest /= shape[axis]
+		}
+	}
+
+	got := make([]float64, len(u))
+	ApplyND(got, u, shape, h, bc)
+	for idx := range u {
+		if math.Abs(got[idx]-lambda*u[idx]) > 1e-9 {
+			t.Fatalf("idx=%d: got %v want %v", idx, got[idx], lambda*u[idx])
+		}
+	}
+}
+
+// The parallel kernels match the serial ones for any worker count, also
+// when the y rows split into several cache blocks.
+func TestApplyParallelMatchesSerial(t *testing.T) {
+	shape := grid.NewShape3D(5, 12, 2048)
+	h := [3]float64{0.3, 0.2, 0.1}
+	bc := [3]poisson.BCType{poisson.Neumann, poisson.Periodic, poisson.Dirichlet}
+
+	src := testField(shape.Size())
+	want := make([]float64, len(src))
+	got := make([]float64, len(src))
+
+	ApplyND(want, src, poisson.Shape{shape[0], shape[1], shape[2]}, h[:], bc[:])
+	for _, workers := range []int{1, 2, 3, 0} {
+		Apply3DParallel(got, src, shape, h, bc, workers)
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("3D workers=%d i=%d: got %v want %v", workers, i, got[i], want[i])
+			}
+		}
+	}
+
+	shape2 := grid.NewShape2D(7, 9)
+	h2 := [2]float64{h[0], h[1]}
+	bc2 := [2]poisson.BCType{bc[0], bc[1]}
+	src2 := testField(shape2.Size())
+	want2 := make([]float64, len(src2))
+	Apply2D(want2, src2, shape2, h2, bc2)
+
+	for _, workers := range []int{2, 4, 16} {
+		got2 := append([]float64(nil), src2...)
+		Apply2DParallel(got2, got2, shape2, h2, bc2, workers)
+		for i := range got2 {
+			if got2[i] != want2[i] {
+				t.Fatalf("2D workers=%d i=%d: got %v want %v", workers, i, got2[i], want2[i])
+			}
+		}
+	}
+}
+
+func BenchmarkApply3DParallel(b *testing.B) {
+	const n = 128
+
+	shape := grid.NewShape3D(n, n, n)
+	h := [3]float64{1, 1, 1}
+	bc := [3]poisson.BCType{poisson.Dirichlet, poisson.Dirichlet, poisson.Dirichlet}
+	src := testField(shape.Size())
+	dst := make([]float64, len(src))
+
+	for _, workers := range []int{1, 0} {
+		name := "serial"
+		if workers == 0 {
+			name = "gomaxprocs"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			for range b.N {
+				Apply3DParallel(dst, src, shape, h, bc, workers)
+			}
+		})
+	}
+}
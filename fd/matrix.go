@@ -0,0 +1,309 @@
+package fd
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Matrix is an assembled discrete operator on a grid of up to three axes,
+// stored in compressed sparse row (CSR) form with rows and columns in the
+// row-major grid order of Apply2D and Apply3D.
+//
+// Matrix works on plain slices so that fd does not depend on gonum; the
+// fd/gonumfd subpackage wraps it as a gonum mat.Matrix, mat.Symmetric and
+// sparse mat.NonZeroDoer. Dense returns the row-major dense data and CSR the
+// compressed sparse row arrays for other linear-algebra packages. All
+// assembled operators are symmetric.
+//
+// A Matrix is immutable and safe for concurrent use.
+type Matrix struct {
+	size    int
+	indptr  []int // row r holds entries indptr[r]..indptr[r+1]-1
+	indices []int // column of each entry, increasing within a row
+	data    []float64
+}
+
+// NewHelmholtzMatrix assembles the operator (α - Δ) of ApplyHelmholtz, with
+// the second-order Laplacian stencil and boundary handling of Apply1D per
+// axis. n, h and bc are the per-axis slices of poisson.NewHelmholtzPlan;
+// alpha = 0 gives the Laplacian -Δ.
+func NewHelmholtzMatrix(alpha float64, n []int, h []float64, bc []poisson.BCType) (*Matrix, error) {
+	shape, err := matrixShape(n, h, bc)
+	if err != nil {
+		return nil, err
+	}
+
+	return assemble(shape, func(row *matrixRow, p int, idx [3]int) {
+		row.add(p, alpha)
+		for axis := range len(n) {
+			w := 1.0 / (h[axis] * h[axis])
+			row.axis(p, idx[axis], shape[axis], strideOf(shape, axis), bc[axis], func(int) float64 { return w })
+		}
+	}), nil
+}
+
+// NewVariableMatrix assembles the variable-coefficient operator -∇·(κ∇u) of
+// ApplyVariable1D, ApplyVariable2D and ApplyVariable3D, with one positive
+// coefficient kappa per grid point.
+func NewVariableMatrix(kappa []float64, n []int, h []float64, bc []poisson.BCType) (*Matrix, error) {
+	shape, err := matrixShape(n, h, bc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(kappa) != shape.Size() {
+		return nil, &poisson.SizeError{Expected: shape.Size(), Got: len(kappa), Context: "kappa"}
+	}
+
+	return assemble(shape, func(row *matrixRow, p int, idx [3]int) {
+		for axis := range len(n) {
+			invH2 := 1.0 / (h[axis] * h[axis])
+			row.axis(p, idx[axis], shape[axis], strideOf(shape, axis), bc[axis], func(q int) float64 {
+				if q < 0 {
+					// Dirichlet ghost: the boundary point's own coefficient.
+					return kappa[p] * invH2
+				}
+
+				return harmonic(kappa[p], kappa[q]) * invH2
+			})
+		}
+	}), nil
+}
+
+// matrixShape validates the per-axis slices of a matrix constructor and
+// returns the grid shape.
+func matrixShape(n []int, h []float64, bc []poisson.BCType) (grid.Shape, error) {
+	dim := len(n)
+	if dim < 1 || dim > 3 {
+		return grid.Shape{}, &poisson.ValidationError{Field: "n", Message: "must have 1 to 3 axes", Err: poisson.ErrInvalidSize}
+	}
+
+	if len(h) != dim || len(bc) != dim {
+		return grid.Shape{}, &poisson.ValidationError{Field: "h, bc", Message: "must have one entry per axis", Err: poisson.ErrSizeMismatch}
+	}
+
+	shape := grid.Shape{1, 1, 1}
+	for axis, size := range n {
+		if size < 1 {
+			return grid.Shape{}, &poisson.ValidationError{Field: "n", Message: "must be positive", Err: poisson.ErrInvalidSize}
+		}
+
+		if !(h[axis] > 0) {
+			return grid.Shape{}, &poisson.ValidationError{Field: "h", Message: "must be positive", Err: poisson.ErrInvalidSpacing}
+		}
+
+		shape[axis] = size
+	}
+
+	return shape, nil
+}
+
+// strideOf returns the row-major stride of axis in shape.
+func strideOf(shape grid.Shape, axis int) int {
+	return grid.RowMajorStride(shape)[axis]
+}
+
+// matrixRow accumulates the entries of one row.
+type matrixRow struct {
+	cols []int
+	vals []float64
+}
+
+func (r *matrixRow) add(col int, v float64) {
+	r.cols = append(r.cols, col)
+	r.vals = append(r.vals, v)
+}
+
+// axis adds the second difference along one axis for point p at position i
+// of n with the given stride: Σ w (u_p - u_q) over the two neighbors q, with
+// the ghost rules of Apply1D. weight(q) gives the face weight to neighbor q,
+// or with q = -1 to a Dirichlet ghost point.
+func (r *matrixRow) axis(p, i, n, stride int, bc poisson.BCType, weight func(q int) float64) {
+	for _, side := range [2]int{-1, 1} {
+		q := -1
+		switch {
+		case i+side >= 0 && i+side < n:
+			q = p + side*stride
+		case bc == poisson.Periodic:
+			q = p - side*stride*(n-1)
+		case bc == poisson.Neumann:
+			continue // no flux: the ghost equals u_p
+		}
+
+		w := weight(q)
+		r.add(p, w)
+		if q >= 0 {
+			r.add(q, -w)
+		}
+	}
+}
+
+// assemble builds the CSR matrix of the grid, with the entries of row p at
+// grid index idx given by fill.
+func assemble(shape grid.Shape, fill func(row *matrixRow, p int, idx [3]int)) *Matrix {
+	size := shape.Size()
+	m := &Matrix{size: size, indptr: make([]int, 1, size+1)}
+
+	var row matrixRow
+	p := 0
+	for i := range shape[0] {
+		for j := range shape[1] {
+			for k := range shape[2] {
+				row.cols, row.vals = row.cols[:0], row.vals[:0]
+				fill(&row, p, [3]int{i, j, k})
+				m.appendRow(&row)
+				p++
+			}
+		}
+	}
+
+	return m
+}
+
+// appendRow sorts the entries of row by column, sums duplicates (a periodic
+// axis of two points has the same neighbor on both sides) and appends the
+// non-zero ones.
+func (m *Matrix) appendRow(row *matrixRow) {
+	order := make([]int, len(row.cols))
+	for e := range order {
+		order[e] = e
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(row.cols[a], row.cols[b]) })
+
+	for e := 0; e < len(order); {
+		col, sum := row.cols[order[e]], 0.0
+		for ; e < len(order) && row.cols[order[e]] == col; e++ {
+			sum += row.vals[order[e]]
+		}
+
+		if sum != 0 {
+			m.indices = append(m.indices, col)
+			m.data = append(m.data, sum)
+		}
+	}
+
+	m.indptr = append(m.indptr, len(m.indices))
+}
+
+// Dims returns the matrix dimensions (rows, columns): both the number of
+// grid points.
+func (m *Matrix) Dims() (r, c int) {
+	return m.size, m.size
+}
+
+// At returns the entry in row i and column j. It panics if i or j is out of
+// range.
+func (m *Matrix) At(i, j int) float64 {
+	if i < 0 || i >= m.size || j < 0 || j >= m.size {
+		panic("fd: matrix index out of range")
+	}
+
+	cols := m.indices[m.indptr[i]:m.indptr[i+1]]
+	if e, found := slices.BinarySearch(cols, j); found {
+		return m.data[m.indptr[i]+e]
+	}
+
+	return 0
+}
+
+// NNZ returns the number of stored non-zero entries.
+func (m *Matrix) NNZ() int {
+	return len(m.data)
+}
+
+// DoNonZero calls fn for each non-zero entry, row by row.
+func (m *Matrix) DoNonZero(fn func(i, j int, v float64)) {
+	for i := range m.size {
+		for e := m.indptr[i]; e < m.indptr[i+1]; e++ {
+			fn(i, m.indices[e], m.data[e])
+		}
+	}
+}
+
+// DoRowNonZero calls fn for each non-zero entry of row i, in increasing
+// column order. It panics if i is out of range.
+func (m *Matrix) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if i < 0 || i >= m.size {
+		panic("fd: matrix row out of range")
+	}
+
+	for e := m.indptr[i]; e < m.indptr[i+1]; e++ {
+		fn(i, m.indices[e], m.data[e])
+	}
+}
+
+// MulVecTo computes dst = A x (or Aᵀ x, which is the same). dst and x may
+// alias.
+func (m *Matrix) MulVecTo(dst []float64, _ bool, x []float64) error {
+	return m.MulVec(dst, x)
+}
+
+// MulVec computes dst = A x. dst and x may alias.
+func (m *Matrix) MulVec(dst, x []float64) error {
+	if len(dst) != m.size {
+		return &poisson.SizeError{Expected: m.size, Got: len(dst), Context: "MulVec dst"}
+	}
+
+	if len(x) != m.size {
+		return &poisson.SizeError{Expected: m.size, Got: len(x), Context: "MulVec x"}
+	}
+
+	if aliases(x, dst) {
+		x = append([]float64(nil), x...)
+	}
+
+	for i := range m.size {
+		sum := 0.0
+		for e := m.indptr[i]; e < m.indptr[i+1]; e++ {
+			sum += m.data[e] * x[m.indices[e]]
+		}
+		dst[i] = sum
+	}
+
+	return nil
+}
+
+// Dense returns the matrix as row-major dense data. It costs O(N²) memory.
+func (m *Matrix) Dense() []float64 {
+	dense := make([]float64, m.size*m.size)
+	m.DoNonZero(func(i, j int, v float64) {
+		dense[i*m.size+j] = v
+	})
+
+	return dense
+}
+
+// CSR returns the compressed sparse row arrays: the entries of row r are
+// data[indptr[r]:indptr[r+1]] in the columns indices[indptr[r]:indptr[r+1]].
+// The slices are shared with the matrix and must not be modified.
+func (m *Matrix) CSR() (indptr, indices []int, data []float64) {
+	return m.indptr, m.indices, m.data
+}
+
+// Gather copies the window of shape at view in src into the packed vector
+// dst of length shape.Size(), in the row-major order of the matrices, e.g.
+// the interior of a field with ghost layers into a solver's vector. It does
+// nothing if the view does not fit src or dst has the
+// wrong length.
+func Gather(dst, src []float64, shape grid.Shape, view grid.View) {
+	if len(dst) != shape.Size() || !view.Fits(shape, len(src)) {
+		return
+	}
+
+	view.Shape = shape
+	view.CopyOut(dst, src)
+}
+
+// Scatter copies the packed vector src back into the window of shape at
+// view in dst; it is the inverse of Gather.
+func Scatter(dst, src []float64, shape grid.Shape, view grid.View) {
+	if len(src) != shape.Size() || !view.Fits(shape, len(dst)) {
+		return
+	}
+
+	view.Shape = shape
+	view.CopyIn(dst, src)
+}
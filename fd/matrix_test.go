@@ -0,0 +1,144 @@
+package fd
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestHelmholtzMatrixMatchesApply(t *testing.T) {
+	for _, tc := range []struct {
+		n  []int
+		h  []float64
+		bc []poisson.BCType
+	}{
+		{[]int{1}, []float64{0.5}, []poisson.BCType{poisson.Periodic}},
+		{[]int{2}, []float64{0.5}, []poisson.BCType{poisson.Periodic}},
+		{[]int{7}, []float64{0.5}, []poisson.BCType{poisson.Neumann}},
+		{[]int{5, 4}, []float64{0.2, 0.3}, []poisson.BCType{poisson.Dirichlet, poisson.Periodic}},
+		{[]int{3, 4, 5}, []float64{0.3, 0.2, 0.1}, []poisson.BCType{poisson.Neumann, poisson.Dirichlet, poisson.Periodic}},
+	} {
+		const alpha = 1.5
+
+		m, err := NewHelmholtzMatrix(alpha, tc.n, tc.h, tc.bc)
+		if err != nil {
+			t.Fatalf("NewHelmholtzMatrix failed: %v", err)
+		}
+
+		size, _ := m.Dims()
+		u := testField(size)
+		want := make([]float64, size)
+		ApplyHelmholtz(want, u, alpha, tc.n, tc.h, tc.bc)
+
+		if err := m.MulVecTo(u, false, u); err != nil {
+			t.Fatalf("MulVecTo failed: %v", err)
+		}
+
+		for i := range u {
+			if math.Abs(u[i]-want[i]) > 1e-10 {
+				t.Fatalf("n=%v: (A u)[%d] = %v, want %v", tc.n, i, u[i], want[i])
+			}
+		}
+
+		dense := m.Dense()
+		for i := range size {
+			for j := range size {
+				if dense[i*size+j] != m.At(i, j) || m.At(i, j) != m.At(j, i) {
+					t.Fatalf("n=%v: A[%d][%d] = %v, dense %v, transposed %v", tc.n, i, j, m.At(i, j), dense[i*size+j], m.At(j, i))
+				}
+			}
+		}
+	}
+}
+
+func TestVariableMatrixMatchesApply(t *testing.T) {
+	n := []int{4, 5}
+	h := []float64{0.2, 0.3}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Neumann}
+	kappa := coefficientField(20)
+
+	m, err := NewVariableMatrix(kappa, n, h, bc)
+	if err != nil {
+		t.Fatalf("NewVariableMatrix failed: %v", err)
+	}
+
+	u := testField(20)
+	got := make([]float64, 20)
+	want := make([]float64, 20)
+	if err := m.MulVec(got, u); err != nil {
+		t.Fatalf("MulVec failed: %v", err)
+	}
+	ApplyVariable2D(want, u, kappa, grid.NewShape2D(4, 5), [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+
+	for i := range got {
+		if math.Abs(got[i]-want[i]) > 1e-10 {
+			t.Fatalf("(A u)[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// Five-point stencil: at most 5 entries per row, symmetric.
+	nnz := 0
+	m.DoNonZero(func(i, j int, v float64) {
+		nnz++
+		if m.At(j, i) != v {
+			t.Errorf("A[%d][%d] = %v, A[%d][%d] = %v", i, j, v, j, i, m.At(j, i))
+		}
+	})
+	if nnz != m.NNZ() || nnz > 5*20 {
+		t.Errorf("DoNonZero visited %d entries, NNZ = %d", nnz, m.NNZ())
+	}
+
+	indptr, indices, data := m.CSR()
+	if len(indptr) != 21 || len(indices) != m.NNZ() || len(data) != m.NNZ() {
+		t.Errorf("CSR arrays of lengths %d, %d, %d", len(indptr), len(indices), len(data))
+	}
+}
+
+func TestMatrixErrors(t *testing.T) {
+	if _, err := NewHelmholtzMatrix(0, []int{4, 0}, []float64{1, 1}, []poisson.BCType{poisson.Periodic, poisson.Periodic}); !errors.Is(err, poisson.ErrInvalidSize) {
+		t.Errorf("zero size: got %v, want ErrInvalidSize", err)
+	}
+
+	if _, err := NewHelmholtzMatrix(0, []int{4}, []float64{-1}, []poisson.BCType{poisson.Periodic}); !errors.Is(err, poisson.ErrInvalidSpacing) {
+		t.Errorf("negative spacing: got %v, want ErrInvalidSpacing", err)
+	}
+
+	if _, err := NewVariableMatrix(make([]float64, 3), []int{4}, []float64{1}, []poisson.BCType{poisson.Periodic}); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Errorf("short kappa: got %v, want ErrSizeMismatch", err)
+	}
+
+	m, err := NewHelmholtzMatrix(0, []int{4}, []float64{1}, []poisson.BCType{poisson.Periodic})
+	if err != nil {
+		t.Fatalf("NewHelmholtzMatrix failed: %v", err)
+	}
+
+	if err := m.MulVec(make([]float64, 4), make([]float64, 3)); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Errorf("short x: got %v, want ErrSizeMismatch", err)
+	}
+}
+
+func TestGatherScatter(t *testing.T) {
+	parent := grid.NewShape2D(5, 6)
+	shape := grid.NewShape2D(3, 4)
+	view := grid.SubView(parent, [3]int{1, 1, 0})
+
+	field := testField(parent.Size())
+	vec := make([]float64, shape.Size())
+	Gather(vec, field, shape, view)
+
+	if vec[0] != field[view.Index(0, 0, 0)] || vec[len(vec)-1] != field[view.Index(2, 3, 0)] {
+		t.Fatalf("Gather: got %v", vec)
+	}
+
+	for i := range vec {
+		vec[i] *= 2
+	}
+
+	Scatter(field, vec, shape, view)
+	if field[view.Index(1, 2, 0)] != vec[1*4+2] {
+		t.Errorf("Scatter: got %v, want %v", field[view.Index(1, 2, 0)], vec[1*4+2])
+	}
+}
@@ -0,0 +1,238 @@
+package fd
+
+import (
+	"math"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// nonUniformPoints returns the number of grid points described by the
+// spacings h of ApplyNonUniform1D for bc, or 0 if there are none.
+func nonUniformPoints(h []float64, bc poisson.BCType) int {
+	if bc == poisson.Periodic {
+		return len(h)
+	}
+
+	return max(len(h)-1, 0)
+}
+
+// nonUniformRight returns the spacing between point i and its right
+// neighbor, wrapping onto h[0] at the end of a Periodic axis.
+func nonUniformRight(h []float64, i int) float64 {
+	return h[(i+1)%len(h)]
+}
+
+// nonUniformWidth returns the width of the dual cell of point i: half the
+// spacings to its two neighbors.
+func nonUniformWidth(h []float64, i int) float64 {
+	return 0.5 * (h[i] + nonUniformRight(h, i))
+}
+
+// ApplyNonUniform1D applies the second-difference operator -d²/dx² on a
+// non-uniform grid to src and writes into dst:
+//
+//	(Au)_i = ((u_i - u_{i-1})/h_i + (u_i - u_{i+1})/h_{i+1}) / w_i,
+//
+// with w_i = (h_i + h_{i+1})/2 the width of the dual cell of point i. h[i]
+// is the spacing between points i-1 and i, where points -1 and n are the
+// ghost points of Apply1D: len(h) = n+1 for Dirichlet (zero ghosts on the
+// boundary, so h[0] and h[n] are the distances to the walls) and Neumann
+// (mirrored ghosts, so h[0] and h[n] are twice the distances to the walls
+// of a cell-centered grid), and len(h) = n for Periodic, where h[0] spans
+// the wrap from point n-1 to point 0. With all spacings equal it is Apply1D.
+// It does nothing if the lengths do not match, and is safe to call with
+// dst == src.
+func ApplyNonUniform1D(dst, src []float64, h []float64, bc poisson.BCType) {
+	n := nonUniformPoints(h, bc)
+	if n == 0 || len(src) != n || len(dst) != n {
+		return
+	}
+
+	if aliases(src, dst) {
+		src = append([]float64(nil), src...)
+	}
+
+	for i := range n {
+		u := src[i]
+		left, right := neighbors(src, i, i, n, 1, bc)
+		dst[i] = ((u-left)/h[i] + (u-right)/nonUniformRight(h, i)) / nonUniformWidth(h, i)
+	}
+}
+
+// EigenNonUniform computes the eigenvalues and eigenvectors of the
+// non-uniform operator of ApplyNonUniform1D for a Dirichlet or Neumann
+// axis, the basis of a transform along a non-uniform grid axis. The
+// spacings h are those of ApplyNonUniform1D (len(h) = n+1); with all
+// spacings equal the eigenvalues are those of Eigenvalues.
+//
+// The operator A = W⁻¹K, with K symmetric tridiagonal and W = diag(w_i) the
+// dual cell widths, is symmetric in the weighted inner product
+// ⟨u, v⟩ = Σ w_i u_i v_i. It is reduced to the symmetric tridiagonal matrix
+// W^(-1/2) K W^(-1/2) and diagonalized by the implicit QL method.
+//
+// values holds the n eigenvalues in ascending order and vectors the
+// eigenvectors row by row: mode m is vectors[m*n : (m+1)*n], with
+// A v_m = λ_m v_m. The modes are orthonormal in the weighted inner product
+// and signed so that their first component is positive, like the sampled
+// sine and cosine modes of the uniform case, so a field transforms as
+//
+//	û_m = Σ_i w_i v_m[i] u_i,   u_i = Σ_m û_m v_m[i].
+//
+// It returns a *poisson.ValidationError for fewer than two spacings,
+// non-positive or non-finite spacings, or a Periodic axis, whose wrap
+// breaks the tridiagonal structure.
+func EigenNonUniform(h []float64, bc poisson.BCType) (values, vectors []float64, err error) {
+	if bc == poisson.Periodic {
+		return nil, nil, &poisson.ValidationError{Field: "bc", Message: "periodic axes are not supported"}
+	}
+
+	n := nonUniformPoints(h, bc)
+	if n == 0 {
+		return nil, nil, &poisson.ValidationError{Field: "h", Message: "must have n+1 spacings for n >= 1 points", Err: poisson.ErrInvalidSize}
+	}
+
+	for _, s := range h {
+		if !(s > 0) || math.IsInf(s, 1) {
+			return nil, nil, &poisson.ValidationError{Field: "h", Message: "must be positive and finite", Err: poisson.ErrInvalidSpacing}
+		}
+	}
+
+	// Symmetric tridiagonal W^(-1/2) K W^(-1/2): diagonal d, and e[i]
+	// coupling points i and i+1.
+	scale := make([]float64, n)
+	for i := range n {
+		scale[i] = 1 / math.Sqrt(nonUniformWidth(h, i))
+	}
+
+	values = make([]float64, n)
+	e := make([]float64, n)
+	for i := range n {
+		diag := 1/h[i] + 1/h[i+1]
+		if bc == poisson.Neumann {
+			// No flux through the walls.
+			if i == 0 {
+				diag -= 1 / h[0]
+			}
+			if i == n-1 {
+				diag -= 1 / h[n]
+			}
+		}
+
+		values[i] = diag * scale[i] * scale[i]
+		if i+1 < n {
+			e[i] = -scale[i] * scale[i+1] / h[i+1]
+		}
+	}
+
+	z := make([]float64, n*n)
+	for i := range n {
+		z[i*n+i] = 1
+	}
+
+	tridiagonalEigen(values, e, z)
+
+	// Column m of z is the eigenvector y_m of the symmetric matrix; v_m =
+	// W^(-1/2) y_m is orthonormal in the weighted inner product.
+	vectors = make([]float64, n*n)
+	for m := range n {
+		sign := 1.0
+		if z[m] < 0 {
+			sign = -1
+		}
+
+		for i := range n {
+			vectors[m*n+i] = sign * z[i*n+m] * scale[i]
+		}
+	}
+
+	return values, vectors, nil
+}
+
+// tridiagonalEigen diagonalizes the symmetric tridiagonal matrix with
+// diagonal d and off-diagonal e (e[i] couples rows i and i+1, e[n-1] is
+// ignored) by the implicit QL method (tql2 of EISPACK). On return d holds
+// the eigenvalues in ascending order and the columns of the row-major n×n
+// matrix z, which must be the identity on entry, the orthonormal
+// eigenvectors. e is destroyed.
+func tridiagonalEigen(d, e, z []float64) {
+	n := len(d)
+	e[n-1] = 0
+
+	const eps = 0x1p-52
+
+	f, tst1 := 0.0, 0.0
+	for l := range n {
+		tst1 = math.Max(tst1, math.Abs(d[l])+math.Abs(e[l]))
+
+		m := l
+		for m < n-1 && math.Abs(e[m]) > eps*tst1 {
+			m++
+		}
+
+		for m > l {
+			// One QL step per pass, shifted by the eigenvalue of the
+			// leading 2×2 block nearest d[l], until e[l] vanishes.
+			g := d[l]
+			p := (d[l+1] - g) / (2 * e[l])
+			r := math.Copysign(math.Hypot(p, 1), p)
+			d[l] = e[l] / (p + r)
+			d[l+1] = e[l] * (p + r)
+			dl1 := d[l+1]
+			shift := g - d[l]
+			for i := l + 2; i < n; i++ {
+				d[i] -= shift
+			}
+			f += shift
+
+			// QL sweep of plane rotations from m-1 up to l.
+			p = d[m]
+			c, c2, c3 := 1.0, 1.0, 1.0
+			el1 := e[l+1]
+			s, s2 := 0.0, 0.0
+			for i := m - 1; i >= l; i-- {
+				c3, c2, s2 = c2, c, s
+				g = c * e[i]
+				hh := c * p
+				r = math.Hypot(p, e[i])
+				e[i+1] = s * r
+				s = e[i] / r
+				c = p / r
+				p = c*d[i] - s*g
+				d[i+1] = hh + s*(c*g+s*d[i])
+
+				for k := range n {
+					zk := z[k*n : (k+1)*n]
+					zk[i], zk[i+1] = c*zk[i]-s*zk[i+1], s*zk[i]+c*zk[i+1]
+				}
+			}
+
+			p = -s * s2 * c3 * el1 * e[l] / dl1
+			e[l] = s * p
+			d[l] = c * p
+
+			if math.Abs(e[l]) <= eps*tst1 {
+				break
+			}
+		}
+
+		d[l] += f
+		e[l] = 0
+	}
+
+	// Selection sort of the eigenvalues, swapping the eigenvector columns.
+	for i := range n - 1 {
+		k := i
+		for j := i + 1; j < n; j++ {
+			if d[j] < d[k] {
+				k = j
+			}
+		}
+
+		if k != i {
+			d[i], d[k] = d[k], d[i]
+			for r := range n {
+				z[r*n+i], z[r*n+k] = z[r*n+k], z[r*n+i]
+			}
+		}
+	}
+}
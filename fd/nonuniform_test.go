@@ -0,0 +1,153 @@
+package fd
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// stretched returns n+1 geometrically growing spacings.
+func stretched(n int) []float64 {
+	h := make([]float64, n+1)
+	for i := range h {
+		h[i] = 0.05 * math.Pow(1.15, float64(i))
+	}
+
+	return h
+}
+
+func uniformSpacings(count int, h float64) []float64 {
+	s := make([]float64, count)
+	for i := range s {
+		s[i] = h
+	}
+
+	return s
+}
+
+func TestApplyNonUniform1DUniformMatchesApply1D(t *testing.T) {
+	const n, h = 9, 0.2
+
+	u := testField(n)
+	got := make([]float64, n)
+	want := make([]float64, n)
+
+	for _, bc := range []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann} {
+		count := n + 1
+		if bc == poisson.Periodic {
+			count = n
+		}
+
+		ApplyNonUniform1D(got, u, uniformSpacings(count, h), bc)
+		Apply1D(want, u, h, bc)
+		for i := range n {
+			if math.Abs(got[i]-want[i]) > 1e-10 {
+				t.Fatalf("%v i=%d: got %v want %v", bc, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestEigenNonUniformUniformModes(t *testing.T) {
+	const n, h = 8, 0.125
+
+	for _, bc := range []poisson.BCType{poisson.Dirichlet, poisson.Neumann} {
+		values, vectors, err := EigenNonUniform(uniformSpacings(n+1, h), bc)
+		if err != nil {
+			t.Fatalf("%v: %v", bc, err)
+		}
+
+		want := Eigenvalues(n, h, bc)
+		for m := range n {
+			if math.Abs(values[m]-want[m]) > 1e-12*(1+want[m]) {
+				t.Errorf("%v: λ_%d = %v, want %v", bc, m, values[m], want[m])
+			}
+
+			// The sampled sine (Dirichlet) or cosine (Neumann) modes,
+			// normalized in the weighted inner product.
+			for i := range n {
+				var v float64
+				if bc == poisson.Dirichlet {
+					v = math.Sqrt(2/(float64(n+1)*h)) * math.Sin(math.Pi*float64((m+1)*(i+1))/float64(n+1))
+				} else {
+					v = math.Sqrt(2/(float64(n)*h)) * math.Cos(math.Pi*float64(m)*(float64(i)+0.5)/float64(n))
+					if m == 0 {
+						v /= math.Sqrt2
+					}
+				}
+
+				if got := vectors[m*n+i]; math.Abs(got-v) > 1e-10 {
+					t.Fatalf("%v: v_%d[%d] = %v, want %v", bc, m, i, got, v)
+				}
+			}
+		}
+	}
+}
+
+func TestEigenNonUniformStretched(t *testing.T) {
+	const n = 12
+
+	h := stretched(n)
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 * (h[i] + h[i+1])
+	}
+
+	for _, bc := range []poisson.BCType{poisson.Dirichlet, poisson.Neumann} {
+		values, vectors, err := EigenNonUniform(h, bc)
+		if err != nil {
+			t.Fatalf("%v: %v", bc, err)
+		}
+
+		av := make([]float64, n)
+		for m := range n {
+			v := vectors[m*n : (m+1)*n]
+			if m > 0 && values[m] < values[m-1] {
+				t.Fatalf("%v: eigenvalues not ascending at %d", bc, m)
+			}
+
+			ApplyNonUniform1D(av, v, h, bc)
+			for i := range n {
+				if math.Abs(av[i]-values[m]*v[i]) > 1e-9*values[n-1] {
+					t.Fatalf("%v mode %d: (Av)[%d] = %v, want λv = %v", bc, m, i, av[i], values[m]*v[i])
+				}
+			}
+
+			for k := range m + 1 {
+				dot := 0.0
+				for i := range n {
+					dot += w[i] * v[i] * vectors[k*n+i]
+				}
+
+				want := 0.0
+				if k == m {
+					want = 1
+				}
+
+				if math.Abs(dot-want) > 1e-10 {
+					t.Fatalf("%v: ⟨v_%d, v_%d⟩ = %v, want %v", bc, m, k, dot, want)
+				}
+			}
+		}
+
+		if bc == poisson.Neumann && math.Abs(values[0]) > 1e-9 {
+			t.Errorf("Neumann: smallest eigenvalue %v, want 0", values[0])
+		}
+	}
+}
+
+func TestEigenNonUniformErrors(t *testing.T) {
+	if _, _, err := EigenNonUniform([]float64{0.1}, poisson.Dirichlet); !errors.Is(err, poisson.ErrInvalidSize) {
+		t.Errorf("one spacing: got %v, want ErrInvalidSize", err)
+	}
+	if _, _, err := EigenNonUniform([]float64{0.1, 0, 0.1}, poisson.Neumann); !errors.Is(err, poisson.ErrInvalidSpacing) {
+		t.Errorf("zero spacing: got %v, want ErrInvalidSpacing", err)
+	}
+
+	var verr *poisson.ValidationError
+	if _, _, err := EigenNonUniform(stretched(4), poisson.Periodic); !errors.As(err, &verr) {
+		t.Errorf("periodic: got %v, want a ValidationError", err)
+	}
+}
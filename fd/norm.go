@@ -0,0 +1,150 @@
+package fd
+
+import (
+	"math"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// cellVolume returns the product of the spacings h, the volume of one grid
+// cell (1 for an empty h).
+func cellVolume(h []float64) float64 {
+	vol := 1.0
+	for _, s := range h {
+		vol *= s
+	}
+
+	return vol
+}
+
+// NormLinf returns the max norm max_i |u_i| (0 for an empty u).
+func NormLinf(u []float64) float64 {
+	return poisson.MaxNorm(u)
+}
+
+// NormL2 returns the discrete L2 norm sqrt(Σ u_i² · hx·hy·...), with one
+// spacing per axis in h. It approximates the continuous norm on the grid and
+// stays comparable across resolutions, unlike the plain Euclidean norm.
+func NormL2(u []float64, h []float64) float64 {
+	return poisson.L2Norm(u, cellVolume(h))
+}
+
+// NormH1 returns the discrete H1 norm sqrt(‖u‖² + ‖∇u‖²) of u on the grid
+// of n, h and bc (the per-axis slices of poisson.NewPlan, 1 to 3 axes).
+// The gradient part sums the squared face differences (u_q - u_p)/h with the
+// ghost values of Apply1D: every face on a Periodic axis, the boundary faces
+// to the zero ghost on a Dirichlet axis and none beyond a Neumann wall. It
+// is therefore the energy of the solver's Laplacian,
+//
+//	‖∇u‖² = hx·hy·... · ⟨u, ApplyHelmholtz(u, α=0)⟩.
+//
+// It returns +Inf if the slices disagree in length or u does not match the
+// grid.
+func NormH1(u []float64, n []int, h []float64, bc []poisson.BCType) float64 {
+	dim := len(n)
+	if dim < 1 || dim > 3 || len(h) != dim || len(bc) != dim {
+		return math.Inf(1)
+	}
+
+	shape := grid.Shape{1, 1, 1}
+	copy(shape[:], n)
+	if len(u) != shape.Size() {
+		return math.Inf(1)
+	}
+
+	sum := 0.0
+	for _, v := range u {
+		sum += v * v
+	}
+
+	stride := grid.RowMajorStride(shape)
+	for axis := range dim {
+		invH2 := 1.0 / (h[axis] * h[axis])
+		size := shape[axis]
+
+		for p, v := range u {
+			i := (p / stride[axis]) % size
+
+			// The face to the right of every point, plus the left boundary
+			// face of a Dirichlet axis.
+			right := 0.0
+			switch {
+			case i+1 < size:
+				right = u[p+stride[axis]]
+			case bc[axis] == poisson.Periodic:
+				right = u[p-stride[axis]*(size-1)]
+			case bc[axis] == poisson.Neumann:
+				right = v
+			}
+			sum += (right - v) * (right - v) * invH2
+
+			if i == 0 && bc[axis] == poisson.Dirichlet {
+				sum += v * v * invH2
+			}
+		}
+	}
+
+	return math.Sqrt(sum * cellVolume(h))
+}
+
+// Mean returns the arithmetic mean of u (0 for an empty u). On a uniform
+// grid it is the volume-weighted mean, i.e. the component of u in the
+// nullspace of a fully periodic or Neumann Laplacian.
+func Mean(u []float64) float64 {
+	if len(u) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range u {
+		sum += v
+	}
+
+	return sum / float64(len(u))
+}
+
+// ErrorLinf returns the max-norm error max_i |got_i - want_i|. It returns
+// +Inf if the lengths differ.
+func ErrorLinf(got, want []float64) float64 {
+	return poisson.MaxAbsDiff(got, want)
+}
+
+// ErrorL2 returns the discrete L2 norm of got - want with the spacings h, as
+// in NormL2. It returns +Inf if the lengths differ.
+func ErrorL2(got, want []float64, h []float64) float64 {
+	if len(got) != len(want) {
+		return math.Inf(1)
+	}
+
+	sum := 0.0
+	for i := range got {
+		d := got[i] - want[i]
+		sum += d * d
+	}
+
+	return math.Sqrt(sum * cellVolume(h))
+}
+
+// RelativeErrorLinf returns ErrorLinf(got, want) / NormLinf(want), or the
+// absolute error if want is zero.
+func RelativeErrorLinf(got, want []float64) float64 {
+	return relative(ErrorLinf(got, want), NormLinf(want))
+}
+
+// RelativeErrorL2 returns ErrorL2(got, want, h) / NormL2(want, h), or the
+// absolute error if want is zero. The spacings cancel, so h only matters
+// for the absolute fallback.
+func RelativeErrorL2(got, want []float64, h []float64) float64 {
+	return relative(ErrorL2(got, want, h), NormL2(want, h))
+}
+
+// relative divides an error by the norm of the reference, falling back to
+// the absolute error for a zero reference.
+func relative(diff, norm float64) float64 {
+	if norm == 0 {
+		return diff
+	}
+
+	return diff / norm
+}
@@ -0,0 +1,104 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestNorms(t *testing.T) {
+	u := []float64{3, -4, 0, 0}
+	h := []float64{0.5, 0.5}
+
+	if got := NormLinf(u); got != 4 {
+		t.Errorf("NormLinf = %v, want 4", got)
+	}
+	if got := NormL2(u, h); got != 2.5 {
+		t.Errorf("NormL2 = %v, want 2.5", got)
+	}
+	if got := Mean(u); got != -0.25 {
+		t.Errorf("Mean = %v, want -0.25", got)
+	}
+	if NormLinf(nil) != 0 || NormL2(nil, h) != 0 || Mean(nil) != 0 {
+		t.Error("norms of an empty vector are not zero")
+	}
+}
+
+// The gradient part of NormH1 is the energy of the solver's Laplacian.
+func TestNormH1MatchesLaplacianEnergy(t *testing.T) {
+	n := []int{5, 4, 6}
+	h := []float64{0.3, 0.2, 0.5}
+	vol := h[0] * h[1] * h[2]
+
+	for _, bc := range [][]poisson.BCType{
+		{poisson.Dirichlet, poisson.Neumann, poisson.Periodic},
+		{poisson.Periodic, poisson.Dirichlet, poisson.Neumann},
+		{poisson.Neumann, poisson.Periodic, poisson.Dirichlet},
+	} {
+		u := testField(n[0] * n[1] * n[2])
+		lu := make([]float64, len(u))
+		ApplyHelmholtz(lu, u, 0, n, h, bc)
+
+		energy := 0.0
+		for i := range u {
+			energy += u[i] * (u[i] + lu[i])
+		}
+
+		want := math.Sqrt(energy * vol)
+		if got := NormH1(u, n, h, bc); math.Abs(got-want) > tolerance*want {
+			t.Errorf("%v: NormH1 = %v, want %v", bc, got, want)
+		}
+	}
+}
+
+func TestNormH1Constant(t *testing.T) {
+	u := []float64{2, 2, 2, 2}
+	h := []float64{0.25}
+
+	// A constant has no gradient on periodic and Neumann axes; Dirichlet
+	// adds the jumps to the zero ghosts at both ends.
+	for _, tc := range []struct {
+		bc   poisson.BCType
+		want float64
+	}{
+		{poisson.Periodic, NormL2(u, h)},
+		{poisson.Neumann, NormL2(u, h)},
+		{poisson.Dirichlet, math.Sqrt((16 + 2*4/(0.25*0.25)) * 0.25)},
+	} {
+		if got := NormH1(u, []int{4}, h, []poisson.BCType{tc.bc}); math.Abs(got-tc.want) > tolerance {
+			t.Errorf("%v: NormH1 = %v, want %v", tc.bc, got, tc.want)
+		}
+	}
+
+	if got := NormH1(u, []int{5}, h, []poisson.BCType{poisson.Periodic}); !math.IsInf(got, 1) {
+		t.Errorf("NormH1 with mismatched size = %v, want +Inf", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	want := []float64{3, -4, 0, 0}
+	got := []float64{3, -4, 0.5, 0}
+	h := []float64{0.25}
+
+	if e := ErrorLinf(got, want); e != 0.5 {
+		t.Errorf("ErrorLinf = %v, want 0.5", e)
+	}
+	if e := ErrorL2(got, want, h); e != 0.25 {
+		t.Errorf("ErrorL2 = %v, want 0.25", e)
+	}
+	if e := RelativeErrorLinf(got, want); e != 0.125 {
+		t.Errorf("RelativeErrorLinf = %v, want 0.125", e)
+	}
+	if e := RelativeErrorL2(got, want, h); math.Abs(e-0.1) > tolerance {
+		t.Errorf("RelativeErrorL2 = %v, want 0.1", e)
+	}
+
+	zero := make([]float64, 4)
+	if e := RelativeErrorL2(got, zero, h); e != NormL2(got, h) {
+		t.Errorf("RelativeErrorL2 against zero = %v, want the absolute error %v", e, NormL2(got, h))
+	}
+	if e := ErrorL2(got, want[:3], h); !math.IsInf(e, 1) {
+		t.Errorf("ErrorL2 with mismatched lengths = %v, want +Inf", e)
+	}
+}
@@ -0,0 +1,139 @@
+package fd
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// smoothers returns the three smoothers for the 2D Dirichlet Laplacian on
+// an n×n grid, and its matrix.
+func smoothers(t *testing.T, n int) (*Matrix, map[string]Smoother) {
+	t.Helper()
+
+	h := 1.0 / float64(n+1)
+	m, err := NewHelmholtzMatrix(0, []int{n, n}, []float64{h, h}, []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewHelmholtzMatrix failed: %v", err)
+	}
+
+	jacobi, err := NewJacobi(m, 0.8)
+	if err != nil {
+		t.Fatalf("NewJacobi failed: %v", err)
+	}
+
+	gs, err := NewGaussSeidel(m)
+	if err != nil {
+		t.Fatalf("NewGaussSeidel failed: %v", err)
+	}
+
+	rb, err := NewRedBlack(m, grid.NewShape2D(n, n))
+	if err != nil {
+		t.Fatalf("NewRedBlack failed: %v", err)
+	}
+
+	return m, map[string]Smoother{"Jacobi": jacobi, "GaussSeidel": gs, "RedBlack": rb}
+}
+
+// Sweeps on the homogeneous problem damp an oscillatory error mode strongly
+// and a smooth one barely: the smoothing property multigrid relies on. The
+// oscillatory mode stays clear of the highest frequencies, which red-black
+// ordering couples to the smooth modes.
+func TestSmoothersDampOscillatoryModes(t *testing.T) {
+	const n = 16
+
+	_, all := smoothers(t, n)
+	zero := make([]float64, n*n)
+
+	reduction := func(s Smoother, m int) float64 {
+		_, mode, _ := gradMode(n, m, poisson.Dirichlet)
+		u := make([]float64, n*n)
+		for i := range n {
+			for j := range n {
+				u[i*n+j] = mode[i] * mode[j]
+			}
+		}
+
+		before := NormL2(u, []float64{1})
+		if err := s.Smooth(u, zero, 2); err != nil {
+			t.Fatalf("Smooth failed: %v", err)
+		}
+
+		return NormL2(u, []float64{1}) / before
+	}
+
+	for name, s := range all {
+		if r := reduction(s, 3*n/4); r > 0.3 {
+			t.Errorf("%s: oscillatory mode reduced to %v, want at most 0.3", name, r)
+		}
+		if r := reduction(s, 1); r < 0.9 {
+			t.Errorf("%s: smooth mode reduced to %v, want above 0.9", name, r)
+		}
+	}
+}
+
+// As standalone solvers the smoothers converge to the direct solution.
+func TestRelaxMatchesSolver(t *testing.T) {
+	const n = 8
+
+	m, all := smoothers(t, n)
+	h := 1.0 / float64(n+1)
+
+	f := testField(n * n)
+	plan, err := poisson.NewPlan(2, []int{n, n}, []float64{h, h}, []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]float64, n*n)
+	if err := plan.Solve(want, f); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, s := range all {
+		u := make([]float64, n*n)
+		sweeps, res, err := Relax(s, m, u, f, 1e-10, 5000)
+		if err != nil {
+			t.Fatalf("%s: Relax failed: %v", name, err)
+		}
+		if res > 1e-10 {
+			t.Fatalf("%s: residual %v after %d sweeps", name, res, sweeps)
+		}
+
+		if e := ErrorLinf(u, want) / NormLinf(want); e > 1e-8 {
+			t.Errorf("%s: relative error %v after %d sweeps", name, e, sweeps)
+		}
+	}
+}
+
+func TestSmootherErrors(t *testing.T) {
+	m, all := smoothers(t, 4)
+
+	var vErr *poisson.ValidationError
+	if _, err := NewJacobi(m, 0); !errors.As(err, &vErr) {
+		t.Errorf("NewJacobi(omega=0): expected ValidationError, got %v", err)
+	}
+	if _, err := NewJacobi(m, math.NaN()); !errors.As(err, &vErr) {
+		t.Errorf("NewJacobi(omega=NaN): expected ValidationError, got %v", err)
+	}
+	if _, err := NewGaussSeidel(nil); !errors.As(err, &vErr) {
+		t.Errorf("NewGaussSeidel(nil): expected ValidationError, got %v", err)
+	}
+
+	var sErr *poisson.SizeError
+	if _, err := NewRedBlack(m, grid.NewShape2D(4, 5)); !errors.As(err, &sErr) {
+		t.Errorf("NewRedBlack shape mismatch: expected SizeError, got %v", err)
+	}
+
+	for name, s := range all {
+		if err := s.Smooth(make([]float64, 15), make([]float64, 16), 1); !errors.As(err, &sErr) {
+			t.Errorf("%s: expected SizeError, got %v", name, err)
+		}
+		if err := s.Smooth(nil, make([]float64, 16), 1); !errors.Is(err, poisson.ErrNilBuffer) {
+			t.Errorf("%s: expected ErrNilBuffer, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package fd
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// SymmetryError returns the largest relative asymmetry of op over trials
+// pairs of random vectors u, v drawn from seed,
+//
+//	|⟨Au, v⟩ - ⟨u, Av⟩| / (‖Au‖‖v‖ + ‖u‖‖Av‖),
+//
+// which is zero for a symmetric operator up to rounding (about 1e-15) and
+// at most 1. The spectral solvers and CG-type methods need symmetry, so a
+// new boundary or stencil combination, or a custom operator, should pass
+// with a small tolerance. It returns a *poisson.ValidationError for a nil
+// or non-square op or trials < 1.
+func SymmetryError(op Operator, trials int, seed uint64) (float64, error) {
+	size, err := operatorSize(op)
+	if err != nil {
+		return 0, err
+	}
+
+	apply := func(dst, src []float64) error {
+		for i := range size {
+			sum := 0.0
+			op.DoRowNonZero(i, func(_, j int, v float64) {
+				sum += v * src[j]
+			})
+			dst[i] = sum
+		}
+
+		return nil
+	}
+
+	return SymmetryErrorFunc(apply, size, trials, seed)
+}
+
+// SymmetryErrorFunc is SymmetryError for a matrix-free operator of the given
+// size that computes dst = A src, such as poisson.Plan.OperatorFunc. Errors
+// from apply are returned as is.
+func SymmetryErrorFunc(apply func(dst, src []float64) error, size, trials int, seed uint64) (float64, error) {
+	if apply == nil {
+		return 0, &poisson.ValidationError{Field: "apply", Message: "operator is nil"}
+	}
+
+	if size < 1 {
+		return 0, &poisson.ValidationError{Field: "size", Message: "must be positive", Err: poisson.ErrInvalidSize}
+	}
+
+	if trials < 1 {
+		return 0, &poisson.ValidationError{Field: "trials", Message: "must be at least 1"}
+	}
+
+	rng := rand.New(rand.NewPCG(seed, uint64(size)))
+	u := make([]float64, size)
+	v := make([]float64, size)
+	au := make([]float64, size)
+	av := make([]float64, size)
+
+	worst := 0.0
+	for range trials {
+		for i := range size {
+			u[i] = 2*rng.Float64() - 1
+			v[i] = 2*rng.Float64() - 1
+		}
+
+		if err := apply(au, u); err != nil {
+			return 0, err
+		}
+		if err := apply(av, v); err != nil {
+			return 0, err
+		}
+
+		var auv, uav float64
+		for i := range size {
+			auv += au[i] * v[i]
+			uav += u[i] * av[i]
+		}
+
+		scale := poisson.L2Norm(au, 1)*poisson.L2Norm(v, 1) + poisson.L2Norm(u, 1)*poisson.L2Norm(av, 1)
+		if scale == 0 {
+			continue
+		}
+
+		worst = math.Max(worst, math.Abs(auv-uav)/scale)
+	}
+
+	return worst, nil
+}
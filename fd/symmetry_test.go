@@ -0,0 +1,89 @@
+package fd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestSymmetryErrorOperators(t *testing.T) {
+	n := []int{5, 4, 6}
+	h := []float64{0.3, 0.2, 0.5}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic}
+
+	helmholtz, err := NewHelmholtzMatrix(1.5, n, h, bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variable, err := NewVariableMatrix(coefficientField(n[0]*n[1]*n[2]), n, h, bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, op := range map[string]Operator{"Helmholtz": helmholtz, "Variable": variable} {
+		asym, err := SymmetryError(op, 3, 1)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if asym > 1e-14 {
+			t.Errorf("%s: asymmetry %v", name, asym)
+		}
+	}
+
+	plan, err := poisson.NewPlan(3, n, h, bc, poisson.WithStencil(poisson.Isotropic27))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asym, err := SymmetryErrorFunc(plan.OperatorFunc(), n[0]*n[1]*n[2], 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asym > 1e-14 {
+		t.Errorf("Isotropic27 plan operator: asymmetry %v", asym)
+	}
+}
+
+// Upwind advection is not symmetric, and the check says so.
+func TestSymmetryErrorDetectsAsymmetry(t *testing.T) {
+	const n = 16
+
+	advect := func(dst, src []float64) error {
+		Advect1D(dst, src, constantVelocity(n, 1), 0.1, poisson.Periodic, Upwind)
+		return nil
+	}
+
+	asym, err := SymmetryErrorFunc(advect, n, 3, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asym < 0.05 {
+		t.Errorf("upwind advection: asymmetry %v, want clearly non-zero", asym)
+	}
+}
+
+func TestSymmetryErrorInvalid(t *testing.T) {
+	identity := func(dst, src []float64) error {
+		copy(dst, src)
+		return nil
+	}
+
+	var vErr *poisson.ValidationError
+	if _, err := SymmetryErrorFunc(identity, 4, 0, 1); !errors.As(err, &vErr) {
+		t.Errorf("trials=0: expected ValidationError, got %v", err)
+	}
+	if _, err := SymmetryErrorFunc(identity, 0, 1, 1); !errors.Is(err, poisson.ErrInvalidSize) {
+		t.Errorf("size=0: expected ErrInvalidSize, got %v", err)
+	}
+	if _, err := SymmetryError(nil, 1, 1); !errors.As(err, &vErr) {
+		t.Errorf("nil op: expected ValidationError, got %v", err)
+	}
+
+	want := errors.New("apply failed")
+	failing := func(_, _ []float64) error { return want }
+	if _, err := SymmetryErrorFunc(failing, 4, 1, 1); !errors.Is(err, want) {
+		t.Errorf("failing apply: got %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,121 @@
+package fd
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func coefficientField(n int) []float64 {
+	k := make([]float64, n)
+	for i := range k {
+		k[i] = 1.5 + math.Sin(1.3*float64(i))
+	}
+
+	return k
+}
+
+func TestApplyVariableConstantMatchesApply(t *testing.T) {
+	const c = 2.5
+
+	nx, ny, nz := 5, 4, 6
+	h := [3]float64{0.3, 0.2, 0.5}
+	bc := [3]poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic}
+
+	k := make([]float64, nx*ny*nz)
+	for i := range k {
+		k[i] = c
+	}
+
+	u1 := testField(nx)
+	got1 := make([]float64, nx)
+	want1 := make([]float64, nx)
+	for _, b := range bc {
+		ApplyVariable1D(got1, u1, k[:nx], h[0], b)
+		Apply1D(want1, u1, h[0], b)
+		for i := range got1 {
+			if math.Abs(got1[i]-c*want1[i]) > 1e-10 {
+				t.Fatalf("1D %v i=%d: got %v want %v", b, i, got1[i], c*want1[i])
+			}
+		}
+	}
+
+	shape2 := grid.NewShape2D(nx, ny)
+	u2 := testField(nx * ny)
+	got2 := make([]float64, nx*ny)
+	want2 := make([]float64, nx*ny)
+	ApplyVariable2D(got2, u2, k[:nx*ny], shape2, [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+	Apply2D(want2, u2, shape2, [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+	for i := range got2 {
+		if math.Abs(got2[i]-c*want2[i]) > 1e-10 {
+			t.Fatalf("2D i=%d: got %v want %v", i, got2[i], c*want2[i])
+		}
+	}
+
+	shape3 := grid.NewShape3D(nx, ny, nz)
+	u3 := testField(nx * ny * nz)
+	want3 := make([]float64, len(u3))
+	Apply3D(want3, u3, shape3, h, bc)
+	ApplyVariable3D(u3, u3, k, shape3, h, bc)
+	for i := range u3 {
+		if math.Abs(u3[i]-c*want3[i]) > 1e-10 {
+			t.Fatalf("3D i=%d: got %v want %v", i, u3[i], c*want3[i])
+		}
+	}
+}
+
+func TestApplyVariable1DHarmonicFaces(t *testing.T) {
+	k := []float64{1, 3}
+	u := []float64{2, -1}
+	dst := make([]float64, 2)
+
+	ApplyVariable1D(dst, u, k, 0.5, poisson.Neumann)
+
+	// One interior face with harmonic mean 1.5 and no flux through the ends.
+	flux := 1.5 * (u[0] - u[1]) / 0.25
+	if math.Abs(dst[0]-flux) > tolerance || math.Abs(dst[1]+flux) > tolerance {
+		t.Errorf("got %v, want [%v %v]", dst, flux, -flux)
+	}
+}
+
+// The operator is symmetric, and conserves the sum without Dirichlet axes.
+func TestApplyVariable2DSymmetricAndConservative(t *testing.T) {
+	nx, ny := 6, 5
+	shape := grid.NewShape2D(nx, ny)
+	h := [2]float64{0.2, 0.3}
+	k := coefficientField(nx * ny)
+
+	u := testField(nx * ny)
+	v := make([]float64, nx*ny)
+	for i := range v {
+		v[i] = math.Cos(0.4*float64(i)) - 0.2
+	}
+
+	for _, bc := range [][2]poisson.BCType{
+		{poisson.Periodic, poisson.Neumann},
+		{poisson.Dirichlet, poisson.Periodic},
+		{poisson.Neumann, poisson.Dirichlet},
+	} {
+		au := make([]float64, nx*ny)
+		av := make([]float64, nx*ny)
+		ApplyVariable2D(au, u, k, shape, h, bc)
+		ApplyVariable2D(av, v, k, shape, h, bc)
+
+		var vAu, uAv, sum float64
+		for i := range u {
+			vAu += v[i] * au[i]
+			uAv += u[i] * av[i]
+			sum += au[i]
+		}
+
+		if math.Abs(vAu-uAv) > 1e-9 {
+			t.Errorf("%v: <v, Au> = %v, <u, Av> = %v", bc, vAu, uAv)
+		}
+
+		if bc[0] != poisson.Dirichlet && bc[1] != poisson.Dirichlet && math.Abs(sum) > 1e-9 {
+			t.Errorf("%v: sum of Au = %v, want 0", bc, sum)
+		}
+	}
+}
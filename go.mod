@@ -2,6 +2,8 @@ module github.com/MeKo-Tech/algo-pde
 
 go 1.25.0
 
-require github.com/MeKo-Christian/algo-fft v0.4.2
-
-require golang.org/x/sys v0.39.0 // indirect
+require (
+	github.com/MeKo-Christian/algo-fft v0.4.2
+	golang.org/x/sys v0.39.0
+	gonum.org/v1/gonum v0.17.0
+)
@@ -0,0 +1,32 @@
+package grid
+
+// Coordinates returns the positions of the n grid points along an axis with
+// spacing h, measured from the lower domain edge, for the sample layout that
+// goes with the boundary rule bc (grid.HaloBC(bc) for a poisson.BCType):
+//
+//   - HaloPeriodic: x_i = i*h on [0, n*h)
+//   - HaloDirichlet: interior nodes x_i = (i+1)*h of [0, (n+1)*h]
+//   - HaloNeumann: cell centers x_i = (i+0.5)*h of [0, n*h]
+//
+// HaloKeep samples like HaloPeriodic. These are the points at which FillHalo
+// places its ghosts, the fd stencils and the solvers expect values.
+func Coordinates(n int, h float64, bc HaloBC) []float64 {
+	if n <= 0 {
+		return nil
+	}
+
+	offset := 0.0
+	switch bc {
+	case HaloDirichlet:
+		offset = 1
+	case HaloNeumann:
+		offset = 0.5
+	}
+
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = (float64(i) + offset) * h
+	}
+
+	return x
+}
@@ -0,0 +1,55 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCoordinates(t *testing.T) {
+	tests := []struct {
+		bc   HaloBC
+		want []float64
+	}{
+		{HaloPeriodic, []float64{0, 0.25, 0.5, 0.75}},
+		{HaloDirichlet, []float64{0.25, 0.5, 0.75, 1}},
+		{HaloNeumann, []float64{0.125, 0.375, 0.625, 0.875}},
+	}
+
+	for _, tt := range tests {
+		if got := Coordinates(4, 0.25, tt.bc); !slices.Equal(got, tt.want) {
+			t.Errorf("Coordinates(%d) = %v, want %v", tt.bc, got, tt.want)
+		}
+	}
+
+	if got := Coordinates(0, 0.25, HaloPeriodic); got != nil {
+		t.Errorf("Coordinates(0) = %v, want nil", got)
+	}
+}
+
+// FillHalo's ghosts on the low side continue the coordinates, i*h beyond
+// x_0 for ghost layer i: Dirichlet reflects oddly about x = 0, reproducing
+// u = x, and Neumann evenly, reproducing u = x².
+func TestCoordinatesMatchHalo(t *testing.T) {
+	const n, w, h = 5, 2, 0.25
+
+	for bc, u := range map[HaloBC]func(x float64) float64{
+		HaloDirichlet: func(x float64) float64 { return x },
+		HaloNeumann:   func(x float64) float64 { return x * x },
+	} {
+		x := Coordinates(n, h, bc)
+		f := NewField(NewShape1D(n), w)
+
+		interior := make([]float64, n)
+		for i, xi := range x {
+			interior[i] = u(xi)
+		}
+		f.SetInterior(interior)
+		f.FillHalo([3]HaloBC{bc, bc, bc})
+
+		for d := 1; d <= w; d++ {
+			if got, want := f.Data[w-d], u(x[0]-float64(d)*h); got != want {
+				t.Errorf("bc %d: ghost %d = %v, want %v", bc, d, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package grid
+
+// HaloBC selects how FillHalo fills the ghost layers on a side of an axis.
+// The values match poisson.BCType, so grid.HaloBC(bc) converts a plan's
+// boundary condition.
+type HaloBC int
+
+const (
+	// HaloPeriodic wraps around: ghosts copy the interior points at the
+	// opposite end.
+	HaloPeriodic HaloBC = iota
+
+	// HaloDirichlet reflects oddly about zero-valued boundary nodes one
+	// spacing beyond the interior, the solver's Dirichlet convention: the
+	// first ghost layer is zero and layer d > 1 is -u at interior point d-2
+	// from the end.
+	HaloDirichlet
+
+	// HaloNeumann reflects evenly about the cell face half a spacing beyond
+	// the interior, the solver's Neumann convention: ghost layer d copies
+	// interior point d-1 from the end.
+	HaloNeumann
+
+	// HaloKeep leaves the ghosts untouched, e.g. on faces filled from a
+	// neighboring block by decomp.Halo.Exchange.
+	HaloKeep
+)
+
+// Field is a row-major array holding an interior grid of shape Shape inside
+// Pad ghost layers on both sides of every axis. Stencil codes read neighbors
+// of boundary points from the ghosts after FillHalo, and the decomposition
+// layer exchanges the same layout between blocks.
+type Field struct {
+	// Shape is the interior shape.
+	Shape Shape
+
+	// Pad is the ghost width per axis.
+	Pad [3]int
+
+	// Data holds the padded array of shape Padded().
+	Data []float64
+}
+
+// NewField allocates a zero Field of the interior shape with width ghost
+// layers on both sides of each axis longer than one point; the unused axes
+// of 1D and 2D shapes get none. It panics if width is negative.
+func NewField(shape Shape, width int) *Field {
+	if width < 0 {
+		panic("grid: negative halo width")
+	}
+
+	f := &Field{Shape: shape}
+	for axis := range 3 {
+		if shape[axis] > 1 {
+			f.Pad[axis] = width
+		}
+	}
+	f.Data = make([]float64, f.Padded().Size())
+
+	return f
+}
+
+// Padded returns the shape of the padded array, ghosts included.
+func (f *Field) Padded() Shape {
+	return Shape{f.Shape[0] + 2*f.Pad[0], f.Shape[1] + 2*f.Pad[1], f.Shape[2] + 2*f.Pad[2]}
+}
+
+// Interior returns the view of the interior inside Data. Index(-1, j, k)
+// and the like address the ghosts.
+func (f *Field) Interior() View {
+	return Window(f.Padded(), f.Pad, f.Shape)
+}
+
+// CopyInterior copies the interior into the packed row-major array dst of
+// Shape.Size() elements.
+func (f *Field) CopyInterior(dst []float64) {
+	f.Interior().CopyOut(dst, f.Data)
+}
+
+// SetInterior copies the packed row-major array src of Shape.Size()
+// elements into the interior, leaving the ghosts untouched.
+func (f *Field) SetInterior(src []float64) {
+	f.Interior().CopyIn(f.Data, src)
+}
+
+// FillHalo fills the ghost layers from the interior with the per-axis rule
+// bc, on both sides of each axis. Axes are filled in order, each including
+// the ghost layers of the axes before it, so edge and corner ghosts follow
+// both rules. Every padded axis must hold at least Pad interior points.
+func (f *Field) FillHalo(bc [3]HaloBC) {
+	for axis := range 3 {
+		f.FillFace(axis, false, bc[axis])
+		f.FillFace(axis, true, bc[axis])
+	}
+}
+
+// FillFace fills the ghost layers on one side of axis, the high side if
+// high is set, with the rule bc. The layers span the full padded extent of
+// the other axes.
+func (f *Field) FillFace(axis int, high bool, bc HaloBC) {
+	w := f.Pad[axis]
+	if w == 0 || bc == HaloKeep {
+		return
+	}
+
+	n := f.Shape[axis]
+	padded := f.Padded()
+	stride := RowMajorStride(padded)[axis]
+
+	// ghost is the padded position along axis of ghost layer d (1-based,
+	// counted outward), source that of the interior point it copies with
+	// the given sign.
+	for d := 1; d <= w; d++ {
+		ghost, sign := w-d, 1.0
+		var source int
+		switch bc {
+		case HaloPeriodic:
+			source = w + n - d
+		case HaloNeumann:
+			source = w + d - 1
+		case HaloDirichlet:
+			source, sign = w+d-2, -1
+		}
+
+		if high {
+			ghost = w + n - 1 + d
+			switch bc {
+			case HaloPeriodic:
+				source = w + d - 1
+			case HaloNeumann:
+				source = w + n - d
+			case HaloDirichlet:
+				source = w + n - d + 1
+			}
+		}
+
+		zero := bc == HaloDirichlet && d == 1
+		f.eachLayerPoint(axis, func(base int) {
+			if zero {
+				f.Data[base+ghost*stride] = 0
+				return
+			}
+			f.Data[base+ghost*stride] = sign * f.Data[base+source*stride]
+		})
+	}
+}
+
+// eachLayerPoint calls fn with the padded index of every point of the
+// padded array whose coordinate along axis is zero.
+func (f *Field) eachLayerPoint(axis int, fn func(base int)) {
+	hi := f.Padded()
+	stride := RowMajorStride(hi)
+	hi[axis] = 1
+
+	for i := range hi[0] {
+		for j := range hi[1] {
+			for k := range hi[2] {
+				fn(Index(i, j, k, stride))
+			}
+		}
+	}
+}
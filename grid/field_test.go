@@ -0,0 +1,61 @@
+package grid
+
+import "testing"
+
+func TestField_FillHalo1D(t *testing.T) {
+	tests := []struct {
+		bc   HaloBC
+		want []float64
+	}{
+		{HaloPeriodic, []float64{3, 4, 1, 2, 3, 4, 1, 2}},
+		{HaloDirichlet, []float64{-1, 0, 1, 2, 3, 4, 0, -4}},
+		{HaloNeumann, []float64{2, 1, 1, 2, 3, 4, 4, 3}},
+		{HaloKeep, []float64{7, 7, 1, 2, 3, 4, 7, 7}},
+	}
+
+	for _, tt := range tests {
+		f := NewField(NewShape1D(4), 2)
+		for i := range f.Data {
+			f.Data[i] = 7
+		}
+		f.SetInterior([]float64{1, 2, 3, 4})
+		f.FillHalo([3]HaloBC{tt.bc, HaloPeriodic, HaloPeriodic})
+
+		for i, w := range tt.want {
+			if f.Data[i] != w {
+				t.Errorf("bc %d: Data = %v, want %v", tt.bc, f.Data, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestField_LayoutAndCorners(t *testing.T) {
+	f := NewField(NewShape2D(3, 2), 1)
+	if f.Pad != [3]int{1, 1, 0} || f.Padded() != NewShape2D(5, 4) || len(f.Data) != 20 {
+		t.Fatalf("Pad %v, Padded %v, len %d", f.Pad, f.Padded(), len(f.Data))
+	}
+
+	src := []float64{1, 2, 3, 4, 5, 6}
+	f.SetInterior(src)
+	f.FillHalo([3]HaloBC{HaloNeumann, HaloDirichlet, HaloKeep})
+
+	v := f.Interior()
+	if got := f.Data[v.Index(1, 1, 0)]; got != 4 {
+		t.Errorf("interior (1, 1) = %g, want 4", got)
+	}
+	if got := f.Data[v.Index(-1, 0, 0)]; got != 1 {
+		t.Errorf("Neumann ghost (-1, 0) = %g, want 1", got)
+	}
+	if got := f.Data[v.Index(3, -1, 0)]; got != 0 {
+		t.Errorf("corner ghost (3, -1) = %g, want 0", got)
+	}
+
+	dst := make([]float64, len(src))
+	f.CopyInterior(dst)
+	for i := range src {
+		if dst[i] != src[i] {
+			t.Fatalf("CopyInterior = %v, want %v", dst, src)
+		}
+	}
+}
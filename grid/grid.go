@@ -1,4 +1,6 @@
-// Package grid provides shape, stride, and indexing utilities for N-dimensional grids.
+// Package grid provides shape, stride, and indexing utilities for N-dimensional
+// grids, and halo-padded fields whose ghost layers follow the periodic,
+// Dirichlet and Neumann conventions of the fd and poisson packages.
 package grid
 
 // Shape represents the dimensions of an N-dimensional grid.
@@ -53,6 +55,148 @@ func RowMajorStride(s Shape) Stride {
 	return Stride{s[1] * s[2], s[2], 1}
 }
 
+// View describes a window into a (possibly larger) buffer: element
+// (i, j, k) of the window lives at
+// Offset + i*Stride[0] + j*Stride[1] + k*Stride[2]. Views let solvers,
+// stencils and transforms read and write sub-regions of larger arrays, such
+// as the interiors of arrays with ghost/halo layers, without packing them
+// first.
+type View struct {
+	// Shape is the extent of the window, used by Sub, Step, CopyOut and
+	// CopyIn. SubView and hand-built views may leave it zero and pass the
+	// extent separately, as to Fits.
+	Shape Shape
+
+	Offset int
+	Stride Stride
+}
+
+// PackedView returns the view of a contiguous row-major array of shape s.
+func PackedView(s Shape) View {
+	return View{Shape: s, Stride: RowMajorStride(s)}
+}
+
+// SubView returns the view of the window starting at origin inside a
+// row-major array of shape parent, e.g. the interior of an array padded with
+// ghost cells. The window extent is left to the caller; Window records it.
+func SubView(parent Shape, origin [3]int) View {
+	stride := RowMajorStride(parent)
+	return View{
+		Offset: Index(origin[0], origin[1], origin[2], stride),
+		Stride: stride,
+	}
+}
+
+// Window returns the view of the window of the given shape starting at
+// origin inside a row-major array of shape parent.
+func Window(parent Shape, origin [3]int, shape Shape) View {
+	v := SubView(parent, origin)
+	v.Shape = shape
+
+	return v
+}
+
+// Sub returns the view of the window of the given shape starting at element
+// origin of v, in the same buffer.
+func (v View) Sub(origin [3]int, shape Shape) View {
+	return View{
+		Shape:  shape,
+		Offset: v.Index(origin[0], origin[1], origin[2]),
+		Stride: v.Stride,
+	}
+}
+
+// Step returns the view of every step[axis]-th element of v along each
+// axis, starting with element (0, 0, 0), e.g. the coarse points of a
+// multigrid hierarchy for step {2, 2, 2}. Steps must be positive.
+func (v View) Step(step [3]int) View {
+	out := View{Offset: v.Offset}
+	for axis := range 3 {
+		out.Shape[axis] = (v.Shape[axis] + step[axis] - 1) / step[axis]
+		out.Stride[axis] = v.Stride[axis] * step[axis]
+	}
+
+	return out
+}
+
+// IsPacked reports whether v addresses a contiguous row-major array of
+// shape s from its start, so its elements can be copied as one block.
+func (v View) IsPacked(s Shape) bool {
+	return v.Offset == 0 && v.Stride == RowMajorStride(s)
+}
+
+// CopyOut copies the window v.Shape of buf into the packed row-major array
+// dst of v.Shape.Size() elements. It panics if the window does not fit buf
+// or dst has the wrong length.
+func (v View) CopyOut(dst, buf []float64) {
+	v.copyWindow(buf, dst, true)
+}
+
+// CopyIn copies the packed row-major array src of v.Shape.Size() elements
+// into the window v.Shape of buf; it is the inverse of CopyOut. It panics if
+// the window does not fit buf or src has the wrong length.
+func (v View) CopyIn(buf, src []float64) {
+	v.copyWindow(buf, src, false)
+}
+
+// copyWindow copies the window of buf to (out) or from packed, one line
+// along the last axis at a time.
+func (v View) copyWindow(buf, packed []float64, out bool) {
+	if len(packed) != v.Shape.Size() || !v.Fits(v.Shape, len(buf)) {
+		panic("grid: view does not fit buffer")
+	}
+
+	if v.IsPacked(v.Shape) {
+		if out {
+			copy(packed, buf[:len(packed)])
+		} else {
+			copy(buf, packed)
+		}
+		return
+	}
+
+	nz := v.Shape[2]
+	p := 0
+	for i := range v.Shape[0] {
+		for j := range v.Shape[1] {
+			line := buf[v.Index(i, j, 0):]
+			if out {
+				CopyStrided(packed[p:p+nz], 1, line, v.Stride[2], nz)
+			} else {
+				CopyStrided(line, v.Stride[2], packed[p:p+nz], 1, nz)
+			}
+			p += nz
+		}
+	}
+}
+
+// Index returns the buffer index of element (i, j, k).
+func (v View) Index(i, j, k int) int {
+	return v.Offset + i*v.Stride[0] + j*v.Stride[1] + k*v.Stride[2]
+}
+
+// Fits reports whether every element of a window of shape s lies inside a
+// buffer of the given length, with distinct elements at distinct indices
+// along each axis (positive strides for axes longer than one).
+func (v View) Fits(s Shape, length int) bool {
+	if v.Offset < 0 {
+		return false
+	}
+
+	last := v.Offset
+	for axis := range 3 {
+		if s[axis] < 1 {
+			return false
+		}
+		if s[axis] > 1 && v.Stride[axis] < 1 {
+			return false
+		}
+		last += (s[axis] - 1) * v.Stride[axis]
+	}
+
+	return last < length
+}
+
 // Index1D returns the linear index for a 1D coordinate.
 func Index1D(i int) int {
 	return i
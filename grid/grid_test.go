@@ -393,3 +393,121 @@ func TestCopyContiguousToStrided(t *testing.T) {
 		}
 	}
 }
+
+func TestSubView(t *testing.T) {
+	parent := NewShape2D(6, 5)
+	view := SubView(parent, [3]int{1, 1, 0})
+
+	if got, want := view.Index(0, 0, 0), 6; got != want {
+		t.Errorf("Index(0,0,0) = %d, want %d", got, want)
+	}
+	if got, want := view.Index(2, 3, 0), 3*5+4; got != want {
+		t.Errorf("Index(2,3,0) = %d, want %d", got, want)
+	}
+
+	interior := NewShape2D(4, 3)
+	if !view.Fits(interior, parent.Size()) {
+		t.Error("interior view should fit parent")
+	}
+	if view.Fits(NewShape2D(6, 3), parent.Size()) {
+		t.Error("oversized view should not fit parent")
+	}
+	if (View{Offset: -1, Stride: RowMajorStride(interior)}).Fits(interior, 100) {
+		t.Error("negative offset should not fit")
+	}
+	if (View{Stride: Stride{0, 1, 1}}).Fits(interior, 100) {
+		t.Error("zero stride on a long axis should not fit")
+	}
+
+	packed := PackedView(interior)
+	if !packed.Fits(interior, interior.Size()) || packed.Fits(interior, interior.Size()-1) {
+		t.Error("packed view should exactly fit its shape")
+	}
+}
+
+func TestView_CopyOutIn(t *testing.T) {
+	parent := NewShape3D(5, 6, 4)
+	buf := make([]float64, parent.Size())
+	for i := range buf {
+		buf[i] = float64(i)
+	}
+
+	view := Window(parent, [3]int{1, 2, 1}, NewShape3D(3, 3, 2))
+	out := make([]float64, view.Shape.Size())
+	view.CopyOut(out, buf)
+
+	p := 0
+	for i := range 3 {
+		for j := range 3 {
+			for k := range 2 {
+				if want := float64(Index3D(i+1, j+2, k+1, parent)); out[p] != want {
+					t.Fatalf("out[%d] = %v, want %v", p, out[p], want)
+				}
+				p++
+			}
+		}
+	}
+
+	for i := range out {
+		out[i] = -out[i]
+	}
+	view.CopyIn(buf, out)
+
+	for idx, v := range buf {
+		i, j, k := FromIndex3D(idx, parent)
+		inside := i >= 1 && i < 4 && j >= 2 && j < 5 && k >= 1 && k < 3
+		if want := float64(idx); inside && v != -want || !inside && v != want {
+			t.Fatalf("buf[%d] = %v after CopyIn (inside=%v)", idx, v, inside)
+		}
+	}
+
+	packed := PackedView(parent)
+	all := make([]float64, parent.Size())
+	packed.CopyOut(all, buf)
+	for i := range all {
+		if all[i] != buf[i] {
+			t.Fatalf("packed CopyOut differs at %d", i)
+		}
+	}
+}
+
+// Sub nests windows and Step picks every n-th point of a window.
+func TestView_SubStep(t *testing.T) {
+	parent := NewShape2D(9, 8)
+	outer := Window(parent, [3]int{1, 1, 0}, NewShape2D(7, 6))
+
+	inner := outer.Sub([3]int{2, 1, 0}, NewShape2D(3, 4))
+	if inner != Window(parent, [3]int{3, 2, 0}, NewShape2D(3, 4)) {
+		t.Errorf("Sub = %+v", inner)
+	}
+
+	coarse := outer.Step([3]int{2, 2, 1})
+	if coarse.Shape != NewShape2D(4, 3) {
+		t.Fatalf("Step shape = %v, want [4 3 1]", coarse.Shape)
+	}
+	for i := range coarse.Shape[0] {
+		for j := range coarse.Shape[1] {
+			if got, want := coarse.Index(i, j, 0), outer.Index(2*i, 2*j, 0); got != want {
+				t.Errorf("Step Index(%d,%d) = %d, want %d", i, j, got, want)
+			}
+		}
+	}
+	if !coarse.Fits(coarse.Shape, parent.Size()) {
+		t.Error("stepped view should fit parent")
+	}
+
+	if !PackedView(parent).IsPacked(parent) || outer.IsPacked(parent) {
+		t.Error("IsPacked misclassifies views")
+	}
+}
+
+func TestView_CopyOutPanicsOnMisfit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a window that does not fit")
+		}
+	}()
+
+	view := Window(NewShape1D(4), [3]int{2, 0, 0}, NewShape1D(3))
+	view.CopyOut(make([]float64, 3), make([]float64, 4))
+}
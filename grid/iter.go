@@ -0,0 +1,71 @@
+package grid
+
+import "iter"
+
+// NumLines returns the number of lines along axis: the product of the sizes
+// of the other two axes.
+func (s Shape) NumLines(axis int) int {
+	n := 1
+	for d := range 3 {
+		if d != axis {
+			n *= s[d]
+		}
+	}
+
+	return n
+}
+
+// Lines returns an iterator over the start indices of the lines along axis,
+// in increasing order, for
+//
+//	stride := grid.RowMajorStride(shape)[axis]
+//	for start := range shape.Lines(axis) {
+//		// elements start + i*stride for i < shape[axis]
+//	}
+//
+// It is the range-over-func form of LineIterator, visiting the same lines.
+func (s Shape) Lines(axis int) iter.Seq[int] {
+	return s.LineRange(axis, 0, s.NumLines(axis))
+}
+
+// LineRange is Lines restricted to lines first through last-1 of the
+// NumLines(axis) lines, so that workers can split the lines into contiguous
+// ranges, e.g. the [start, end) chunks of a parallel loop.
+func (s Shape) LineRange(axis, first, last int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		stride := RowMajorStride(s)
+
+		// o0 and o1 are the other two axes, o1 the faster.
+		o0, o1 := 0, 1
+		switch axis {
+		case 0:
+			o0, o1 = 1, 2
+		case 1:
+			o0, o1 = 0, 2
+		}
+
+		if s[o1] == 0 {
+			return
+		}
+
+		for l := first; l < last; l++ {
+			if !yield(l/s[o1]*stride[o0] + l%s[o1]*stride[o1]) {
+				return
+			}
+		}
+	}
+}
+
+// Planes returns an iterator over the start indices of the planes
+// orthogonal to axis, in increasing order. It is the range-over-func form of
+// PlaneIterator.
+func (s Shape) Planes(axis int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		stride := RowMajorStride(s)[axis]
+		for p := range s[axis] {
+			if !yield(p * stride) {
+				return
+			}
+		}
+	}
+}
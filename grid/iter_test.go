@@ -0,0 +1,67 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+)
+
+// Lines visits the same lines as LineIterator, in increasing order, and
+// LineRange chunks concatenate to Lines.
+func TestShape_Lines(t *testing.T) {
+	for _, shape := range []Shape{NewShape1D(5), NewShape2D(3, 4), NewShape3D(3, 4, 5)} {
+		for axis := range shape.Dim() {
+			var want []int
+			it := NewLineIterator(shape, axis)
+			for ok := true; ok; ok = it.Next() {
+				want = append(want, it.StartIndex())
+			}
+			slices.Sort(want)
+
+			got := slices.Collect(shape.Lines(axis))
+			if !slices.Equal(got, want) || !slices.IsSorted(got) {
+				t.Errorf("%v axis %d: Lines = %v, want %v", shape, axis, got, want)
+			}
+			if shape.NumLines(axis) != len(want) {
+				t.Errorf("%v axis %d: NumLines = %d, want %d", shape, axis, shape.NumLines(axis), len(want))
+			}
+
+			var chunks []int
+			for first := 0; first < len(want); first += 3 {
+				last := min(first+3, len(want))
+				chunks = slices.AppendSeq(chunks, shape.LineRange(axis, first, last))
+			}
+			if !slices.Equal(chunks, want) {
+				t.Errorf("%v axis %d: LineRange chunks = %v, want %v", shape, axis, chunks, want)
+			}
+		}
+	}
+}
+
+func TestShape_Planes(t *testing.T) {
+	shape := NewShape3D(3, 4, 5)
+	for axis := range 3 {
+		var want []int
+		it := NewPlaneIterator(shape, axis)
+		for ok := true; ok; ok = it.Next() {
+			want = append(want, it.StartIndex())
+		}
+
+		if got := slices.Collect(shape.Planes(axis)); !slices.Equal(got, want) {
+			t.Errorf("axis %d: Planes = %v, want %v", axis, got, want)
+		}
+	}
+}
+
+func TestShape_LinesBreak(t *testing.T) {
+	n := 0
+	for range NewShape3D(3, 4, 5).Lines(2) {
+		n++
+		if n == 4 {
+			break
+		}
+	}
+
+	if n != 4 {
+		t.Errorf("visited %d lines before break, want 4", n)
+	}
+}
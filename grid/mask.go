@@ -0,0 +1,219 @@
+package grid
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+)
+
+// Mask marks every point of a grid of shape Shape as active or inactive,
+// one bit per point in row-major order. Masked solvers (obstacles, the
+// capacitance-matrix method) and masked norms work on the active points
+// only; the reductions below skip inactive ones.
+type Mask struct {
+	shape Shape
+	bits  []uint64
+}
+
+// NewMask returns a mask of shape with every point inactive.
+func NewMask(shape Shape) *Mask {
+	return &Mask{shape: shape, bits: make([]uint64, (shape.Size()+63)/64)}
+}
+
+// NewMaskFunc returns a mask of shape whose point (i, j, k) is active if
+// active(i, j, k) reports true, e.g. a fluid region outside an obstacle.
+func NewMaskFunc(shape Shape, active func(i, j, k int) bool) *Mask {
+	m := NewMask(shape)
+	idx := 0
+	for i := range shape[0] {
+		for j := range shape[1] {
+			for k := range shape[2] {
+				if active(i, j, k) {
+					m.Set(idx, true)
+				}
+				idx++
+			}
+		}
+	}
+
+	return m
+}
+
+// Shape returns the grid shape of the mask.
+func (m *Mask) Shape() Shape {
+	return m.shape
+}
+
+// Get reports whether the point with row-major index idx is active.
+func (m *Mask) Get(idx int) bool {
+	return m.bits[idx/64]&(1<<(idx%64)) != 0
+}
+
+// Set marks the point with row-major index idx active or inactive.
+func (m *Mask) Set(idx int, active bool) {
+	if active {
+		m.bits[idx/64] |= 1 << (idx % 64)
+	} else {
+		m.bits[idx/64] &^= 1 << (idx % 64)
+	}
+}
+
+// Fill marks every point active or inactive.
+func (m *Mask) Fill(active bool) {
+	var word uint64
+	if active {
+		word = math.MaxUint64
+	}
+	for w := range m.bits {
+		m.bits[w] = word
+	}
+	m.clearTail()
+}
+
+// Count returns the number of active points.
+func (m *Mask) Count() int {
+	n := 0
+	for _, w := range m.bits {
+		n += bits.OnesCount64(w)
+	}
+
+	return n
+}
+
+// Clone returns an independent copy of m.
+func (m *Mask) Clone() *Mask {
+	return &Mask{shape: m.shape, bits: append([]uint64(nil), m.bits...)}
+}
+
+// Not inverts m in place, swapping active and inactive points.
+func (m *Mask) Not() {
+	for w := range m.bits {
+		m.bits[w] = ^m.bits[w]
+	}
+	m.clearTail()
+}
+
+// And keeps only the points active in both m and other. It panics if the
+// shapes differ.
+func (m *Mask) And(other *Mask) {
+	m.combine(other, func(a, b uint64) uint64 { return a & b })
+}
+
+// Or marks the points active in m or other. It panics if the shapes
+// differ.
+func (m *Mask) Or(other *Mask) {
+	m.combine(other, func(a, b uint64) uint64 { return a | b })
+}
+
+func (m *Mask) combine(other *Mask, op func(a, b uint64) uint64) {
+	if other.shape != m.shape {
+		panic("grid: mask shapes differ")
+	}
+	for w := range m.bits {
+		m.bits[w] = op(m.bits[w], other.bits[w])
+	}
+}
+
+// clearTail clears the bits past the last point, which Count and Active
+// would otherwise see.
+func (m *Mask) clearTail() {
+	if rem := m.shape.Size() % 64; rem != 0 {
+		m.bits[len(m.bits)-1] &= 1<<rem - 1
+	}
+}
+
+// Active returns an iterator over the row-major indices of the active
+// points, in increasing order. It skips inactive words of 64 points at once.
+func (m *Mask) Active() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for w, word := range m.bits {
+			for word != 0 {
+				if !yield(w*64 + bits.TrailingZeros64(word)) {
+					return
+				}
+				word &= word - 1
+			}
+		}
+	}
+}
+
+// Interface returns the mask of the active points of m with an inactive
+// neighbor along some axis, the layer next to an obstacle on which the
+// capacitance-matrix method places its unknowns. Points on the edge of the
+// grid only count inactive neighbors inside it.
+func (m *Mask) Interface() *Mask {
+	out := NewMask(m.shape)
+	stride := RowMajorStride(m.shape)
+
+	for idx := range m.Active() {
+		i, j, k := FromIndex3D(idx, m.shape)
+		pos := [3]int{i, j, k}
+		for axis := range 3 {
+			if pos[axis] > 0 && !m.Get(idx-stride[axis]) ||
+				pos[axis] < m.shape[axis]-1 && !m.Get(idx+stride[axis]) {
+				out.Set(idx, true)
+				break
+			}
+		}
+	}
+
+	return out
+}
+
+// Sum returns the sum of v over the active points. v holds one value per
+// grid point; Sum, Dot and MaxAbs panic if its length does not match.
+func (m *Mask) Sum(v []float64) float64 {
+	m.checkLen(len(v))
+
+	sum := 0.0
+	for idx := range m.Active() {
+		sum += v[idx]
+	}
+
+	return sum
+}
+
+// Dot returns the inner product of a and b over the active points; the
+// masked discrete L2 norm is sqrt(Dot(v, v)·cellVolume).
+func (m *Mask) Dot(a, b []float64) float64 {
+	m.checkLen(len(a))
+	m.checkLen(len(b))
+
+	sum := 0.0
+	for idx := range m.Active() {
+		sum += a[idx] * b[idx]
+	}
+
+	return sum
+}
+
+// MaxAbs returns the largest absolute value of v over the active points, 0
+// if there are none.
+func (m *Mask) MaxAbs(v []float64) float64 {
+	m.checkLen(len(v))
+
+	maxAbs := 0.0
+	for idx := range m.Active() {
+		maxAbs = math.Max(maxAbs, math.Abs(v[idx]))
+	}
+
+	return maxAbs
+}
+
+// FillInactive sets v to value at every inactive point, e.g. zero inside
+// an obstacle before a solve. It panics if the length of v does not match.
+func (m *Mask) FillInactive(v []float64, value float64) {
+	m.checkLen(len(v))
+
+	for idx := range v {
+		if !m.Get(idx) {
+			v[idx] = value
+		}
+	}
+}
+
+func (m *Mask) checkLen(n int) {
+	if n != m.shape.Size() {
+		panic("grid: length does not match mask shape")
+	}
+}
@@ -0,0 +1,122 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+)
+
+// disk marks the points of a 2D grid outside a circular obstacle.
+func disk(shape Shape) *Mask {
+	return NewMaskFunc(shape, func(i, j, _ int) bool {
+		di, dj := i-shape[0]/2, j-shape[1]/2
+		return di*di+dj*dj > 4
+	})
+}
+
+func TestMask_Basics(t *testing.T) {
+	shape := NewShape3D(3, 5, 7) // 105 points: two words and a partial one
+	m := NewMask(shape)
+
+	if m.Count() != 0 || m.Shape() != shape {
+		t.Fatalf("new mask: Count = %d, Shape = %v", m.Count(), m.Shape())
+	}
+
+	m.Fill(true)
+	if m.Count() != shape.Size() {
+		t.Errorf("Fill(true): Count = %d, want %d", m.Count(), shape.Size())
+	}
+
+	m.Set(0, false)
+	m.Set(64, false)
+	m.Set(104, false)
+	if m.Get(0) || m.Get(64) || m.Get(104) || !m.Get(63) || m.Count() != shape.Size()-3 {
+		t.Errorf("Set/Get: Count = %d", m.Count())
+	}
+
+	m.Not()
+	if got := slices.Collect(m.Active()); !slices.Equal(got, []int{0, 64, 104}) {
+		t.Errorf("Not: Active = %v, want [0 64 104]", got)
+	}
+}
+
+func TestMask_Combine(t *testing.T) {
+	shape := NewShape2D(9, 9)
+	outside := disk(shape)
+	left := NewMaskFunc(shape, func(i, _, _ int) bool { return i < 4 })
+
+	both := outside.Clone()
+	both.And(left)
+	either := outside.Clone()
+	either.Or(left)
+
+	for idx := range shape.Size() {
+		a, b := outside.Get(idx), left.Get(idx)
+		if both.Get(idx) != (a && b) || either.Get(idx) != (a || b) {
+			t.Fatalf("point %d: And/Or = %v/%v for %v, %v", idx, both.Get(idx), either.Get(idx), a, b)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for different shapes")
+		}
+	}()
+	both.And(NewMask(NewShape2D(9, 8)))
+}
+
+func TestMask_Reductions(t *testing.T) {
+	shape := NewShape2D(9, 9)
+	m := disk(shape)
+
+	v := make([]float64, shape.Size())
+	for i := range v {
+		v[i] = float64(i%7) - 3
+	}
+
+	var sum, dot, maxAbs float64
+	for idx := range v {
+		if m.Get(idx) {
+			sum += v[idx]
+			dot += v[idx] * v[idx]
+			maxAbs = max(maxAbs, v[idx], -v[idx])
+		}
+	}
+
+	if m.Sum(v) != sum || m.Dot(v, v) != dot || m.MaxAbs(v) != maxAbs {
+		t.Errorf("Sum/Dot/MaxAbs = %v/%v/%v, want %v/%v/%v", m.Sum(v), m.Dot(v, v), m.MaxAbs(v), sum, dot, maxAbs)
+	}
+
+	m.FillInactive(v, 0)
+	for idx := range v {
+		if !m.Get(idx) && v[idx] != 0 {
+			t.Fatalf("FillInactive left v[%d] = %v", idx, v[idx])
+		}
+	}
+}
+
+// The interface of the region outside a disk is the ring of points next to
+// the obstacle, not the edge of the grid.
+func TestMask_Interface(t *testing.T) {
+	shape := NewShape2D(9, 9)
+	m := disk(shape)
+	ring := m.Interface()
+
+	for idx := range shape.Size() {
+		i, j, _ := FromIndex3D(idx, shape)
+		next := false
+		for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			ni, nj := i+d[0], j+d[1]
+			if ni >= 0 && ni < 9 && nj >= 0 && nj < 9 && !m.Get(Index3D(ni, nj, 0, shape)) {
+				next = true
+			}
+		}
+
+		if want := m.Get(idx) && next; ring.Get(idx) != want {
+			t.Errorf("point (%d,%d): Interface = %v, want %v", i, j, ring.Get(idx), want)
+		}
+	}
+
+	if ring.Count() == 0 || ring.Get(0) {
+		t.Errorf("Interface: Count = %d, corner active = %v", ring.Count(), ring.Get(0))
+	}
+}
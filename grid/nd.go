@@ -0,0 +1,178 @@
+package grid
+
+// NDShape is the shape of a row-major grid of any rank: the last axis is
+// contiguous. It is the N-dimensional counterpart of Shape, for problems
+// beyond three axes.
+type NDShape []int
+
+// Dim returns the number of dimensions.
+func (s NDShape) Dim() int {
+	return len(s)
+}
+
+// Size returns the total number of elements, 0 for an empty shape.
+func (s NDShape) Size() int {
+	if len(s) == 0 {
+		return 0
+	}
+
+	size := 1
+	for _, n := range s {
+		size *= n
+	}
+
+	return size
+}
+
+// N returns the size along the given axis, 0 for an axis out of range.
+func (s NDShape) N(axis int) int {
+	if axis < 0 || axis >= len(s) {
+		return 0
+	}
+
+	return s[axis]
+}
+
+// Strides returns the row-major (C-order) strides of the shape: the
+// product of the sizes of the later axes.
+func (s NDShape) Strides() []int {
+	stride := make([]int, len(s))
+	step := 1
+	for axis := len(s) - 1; axis >= 0; axis-- {
+		stride[axis] = step
+		step *= s[axis]
+	}
+
+	return stride
+}
+
+// Index returns the linear row-major index of coords, one per axis.
+func (s NDShape) Index(coords []int) int {
+	idx := 0
+	for axis, c := range coords {
+		idx = idx*s[axis] + c
+	}
+
+	return idx
+}
+
+// Coords writes the coordinates of the linear index idx into coords, which
+// needs one entry per axis, and returns it.
+func (s NDShape) Coords(idx int, coords []int) []int {
+	for axis := len(s) - 1; axis >= 0; axis-- {
+		coords[axis] = idx % s[axis]
+		idx /= s[axis]
+	}
+
+	return coords
+}
+
+// Next advances coords to the next point in row-major order, the last axis
+// fastest. It returns false and resets coords to zero after the last point,
+// so a loop over all points is
+//
+//	coords := make([]int, s.Dim())
+//	for ok := true; ok; ok = s.Next(coords) { ... }
+func (s NDShape) Next(coords []int) bool {
+	for axis := len(coords) - 1; axis >= 0; axis-- {
+		coords[axis]++
+		if coords[axis] < s[axis] {
+			return true
+		}
+		coords[axis] = 0
+	}
+
+	return false
+}
+
+// NDLineIterator iterates over the lines along one axis of an NDShape, like
+// LineIterator for Shape. It starts on the first line; Next moves to the
+// following one.
+type NDLineIterator struct {
+	shape  NDShape
+	stride []int
+	axis   int
+
+	// other holds the non-axis axes and pos the position along them; the
+	// last entry varies fastest.
+	other []int
+	pos   []int
+	start int
+
+	done bool
+}
+
+// NewNDLineIterator creates an iterator over the lines along axis.
+func NewNDLineIterator(shape NDShape, axis int) *NDLineIterator {
+	other := make([]int, 0, len(shape))
+	for d := range shape {
+		if d != axis {
+			other = append(other, d)
+		}
+	}
+
+	return &NDLineIterator{
+		shape:  shape,
+		stride: shape.Strides(),
+		axis:   axis,
+		other:  other,
+		pos:    make([]int, len(other)),
+	}
+}
+
+// Next advances to the next line. Returns false when done.
+func (it *NDLineIterator) Next() bool {
+	if it.done {
+		return false
+	}
+
+	for i := len(it.other) - 1; i >= 0; i-- {
+		d := it.other[i]
+		it.pos[i]++
+		it.start += it.stride[d]
+		if it.pos[i] < it.shape[d] {
+			return true
+		}
+
+		it.start -= it.pos[i] * it.stride[d]
+		it.pos[i] = 0
+	}
+
+	it.done = true
+
+	return false
+}
+
+// Reset resets the iterator to the first line.
+func (it *NDLineIterator) Reset() {
+	for i := range it.pos {
+		it.pos[i] = 0
+	}
+	it.start = 0
+	it.done = false
+}
+
+// StartIndex returns the linear index of the first element of the current
+// line.
+func (it *NDLineIterator) StartIndex() int {
+	return it.start
+}
+
+// LineStride returns the stride to advance along the line.
+func (it *NDLineIterator) LineStride() int {
+	return it.stride[it.axis]
+}
+
+// LineLength returns the number of elements in each line.
+func (it *NDLineIterator) LineLength() int {
+	return it.shape[it.axis]
+}
+
+// NumLines returns the total number of lines.
+func (it *NDLineIterator) NumLines() int {
+	if it.shape[it.axis] == 0 {
+		return 0
+	}
+
+	return it.shape.Size() / it.shape[it.axis]
+}
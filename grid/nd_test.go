@@ -0,0 +1,89 @@
+package grid
+
+import "testing"
+
+func TestNDShape_Basics(t *testing.T) {
+	s := NDShape{2, 3, 4, 5}
+
+	if s.Dim() != 4 || s.Size() != 120 || s.N(2) != 4 || s.N(4) != 0 {
+		t.Errorf("Dim/Size/N = %d/%d/%d/%d, want 4/120/4/0", s.Dim(), s.Size(), s.N(2), s.N(4))
+	}
+
+	if NDShape(nil).Size() != 0 {
+		t.Errorf("empty Size() = %d, want 0", NDShape(nil).Size())
+	}
+
+	want := []int{60, 20, 5, 1}
+	for axis, stride := range s.Strides() {
+		if stride != want[axis] {
+			t.Errorf("Strides()[%d] = %d, want %d", axis, stride, want[axis])
+		}
+	}
+}
+
+// Next visits the points in linear order, and Index and Coords convert
+// between them.
+func TestNDShape_IndexCoordsNext(t *testing.T) {
+	s := NDShape{3, 1, 4, 2}
+	coords := make([]int, s.Dim())
+	back := make([]int, s.Dim())
+
+	idx := 0
+	for ok := true; ok; ok = s.Next(coords) {
+		if got := s.Index(coords); got != idx {
+			t.Fatalf("Index(%v) = %d, want %d", coords, got, idx)
+		}
+
+		s.Coords(idx, back)
+		for axis := range coords {
+			if back[axis] != coords[axis] {
+				t.Fatalf("Coords(%d) = %v, want %v", idx, back, coords)
+			}
+		}
+
+		idx++
+	}
+
+	if idx != s.Size() {
+		t.Errorf("Next visited %d points, want %d", idx, s.Size())
+	}
+	for axis, c := range coords {
+		if c != 0 {
+			t.Errorf("coords[%d] = %d after the last point, want 0", axis, c)
+		}
+	}
+}
+
+// The lines along each axis cover every element exactly once.
+func TestNDLineIterator(t *testing.T) {
+	s := NDShape{2, 3, 4, 5}
+
+	for axis := range s.Dim() {
+		it := NewNDLineIterator(s, axis)
+		if it.LineLength() != s[axis] || it.LineStride() != s.Strides()[axis] {
+			t.Fatalf("axis %d: LineLength/LineStride = %d/%d", axis, it.LineLength(), it.LineStride())
+		}
+
+		for pass := range 2 {
+			seen := make([]int, s.Size())
+			lines := 0
+			for ok := true; ok; ok = it.Next() {
+				for i := range it.LineLength() {
+					seen[it.StartIndex()+i*it.LineStride()]++
+				}
+				lines++
+			}
+
+			if lines != it.NumLines() {
+				t.Errorf("axis %d pass %d: %d lines, NumLines() = %d", axis, pass, lines, it.NumLines())
+			}
+			for idx, n := range seen {
+				if n != 1 {
+					t.Fatalf("axis %d pass %d: element %d visited %d times", axis, pass, idx, n)
+				}
+			}
+
+			it.Reset()
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package grid
+
+// transposeTile is the tile edge of the blocked transposes: a 32×32 tile of
+// complex128 is 16 KiB, so a source and a destination tile stay in L1.
+const transposeTile = 32
+
+// Transpose2D writes the transpose of the row-major rows×cols matrix src to
+// dst, a row-major cols×rows matrix. It works on square tiles so that both
+// sides are read and written in runs of adjacent elements. dst and src must
+// not overlap.
+func Transpose2D[T any](dst, src []T, rows, cols int) {
+	TransposeStrided2D(dst, rows, src, cols, rows, cols)
+}
+
+// TransposeStrided2D is Transpose2D for matrices embedded in larger arrays:
+// element (i, j) of the rows×cols source lives at src[i*srcStride+j] and is
+// written to dst[j*dstStride+i]. The strides must be at least cols and rows
+// respectively. The poisson blocked transform strategy uses it to gather
+// adjacent strided lines into contiguous scratch.
+func TransposeStrided2D[T any](dst []T, dstStride int, src []T, srcStride int, rows, cols int) {
+	for i0 := 0; i0 < rows; i0 += transposeTile {
+		i1 := min(i0+transposeTile, rows)
+		for j0 := 0; j0 < cols; j0 += transposeTile {
+			j1 := min(j0+transposeTile, cols)
+			for j := j0; j < j1; j++ {
+				out := dst[j*dstStride:]
+				for i := i0; i < i1; i++ {
+					out[i] = src[i*srcStride+j]
+				}
+			}
+		}
+	}
+}
+
+// Permute returns the shape with axis a of the result taken from axis
+// perm[a] of s.
+func (s Shape) Permute(perm [3]int) Shape {
+	return Shape{s[perm[0]], s[perm[1]], s[perm[2]]}
+}
+
+// Transpose3D permutes the axes of the row-major array src of the given
+// shape into dst, of shape shape.Permute(perm): element (d0, d1, d2) of dst
+// is the element of src whose index along axis perm[a] is d_a. For example
+// perm {2, 1, 0} swaps x and z, making z-lines the slowest axis and x-lines
+// contiguous for axis-contiguous processing. Permutations that keep the last
+// axis copy whole rows; the others are transposed tile by tile. dst and src
+// must not overlap. It panics if perm is not a permutation of 0, 1, 2.
+func Transpose3D[T any](dst, src []T, shape Shape, perm [3]int) {
+	if perm[0] == perm[1] || perm[0] == perm[2] || perm[1] == perm[2] ||
+		min(perm[0], perm[1], perm[2]) < 0 || max(perm[0], perm[1], perm[2]) > 2 {
+		panic("grid: invalid axis permutation")
+	}
+
+	n := shape.Permute(perm)
+	srcStride := RowMajorStride(shape)
+	dstStride := RowMajorStride(n)
+
+	// s[a] is the source stride of destination axis a.
+	var s Stride
+	for a := range 3 {
+		s[a] = srcStride[perm[a]]
+	}
+
+	if perm[2] == 2 {
+		for d0 := range n[0] {
+			for d1 := range n[1] {
+				in := d0*s[0] + d1*s[1]
+				copy(dst[Index(d0, d1, 0, dstStride):][:n[2]], src[in:in+n[2]])
+			}
+		}
+		return
+	}
+
+	// q is the destination axis that is contiguous in src, r the remaining
+	// one. Each r-slice is a strided 2D transpose between axes q and 2.
+	q, r := 0, 1
+	if perm[1] == 2 {
+		q, r = 1, 0
+	}
+
+	for dr := range n[r] {
+		TransposeStrided2D(dst[dr*dstStride[r]:], dstStride[q], src[dr*s[r]:], s[2], n[2], n[q])
+	}
+}
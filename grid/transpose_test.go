@@ -0,0 +1,98 @@
+package grid
+
+import "testing"
+
+func TestTranspose2D(t *testing.T) {
+	// Sizes straddling the tile edge exercise partial tiles.
+	for _, size := range [][2]int{{1, 1}, {3, 5}, {33, 31}, {64, 70}} {
+		rows, cols := size[0], size[1]
+		src := make([]float64, rows*cols)
+		for i := range src {
+			src[i] = float64(i)
+		}
+
+		dst := make([]float64, rows*cols)
+		Transpose2D(dst, src, rows, cols)
+
+		for i := range rows {
+			for j := range cols {
+				if got, want := dst[j*rows+i], src[i*cols+j]; got != want {
+					t.Fatalf("%dx%d: dst(%d,%d) = %v, want %v", rows, cols, j, i, got, want)
+				}
+			}
+		}
+	}
+}
+
+// A strided transpose touches only its window of a larger array.
+func TestTransposeStrided2D(t *testing.T) {
+	const rows, cols, srcStride, dstStride = 37, 3, 5, 40
+
+	src := make([]complex128, rows*srcStride)
+	for i := range src {
+		src[i] = complex(float64(i), -float64(i))
+	}
+
+	dst := make([]complex128, cols*dstStride)
+	for i := range dst {
+		dst[i] = -1
+	}
+	TransposeStrided2D(dst, dstStride, src, srcStride, rows, cols)
+
+	for idx, v := range dst {
+		j, i := idx/dstStride, idx%dstStride
+		want := complex128(-1)
+		if i < rows {
+			want = src[i*srcStride+j]
+		}
+		if v != want {
+			t.Fatalf("dst[%d] = %v, want %v", idx, v, want)
+		}
+	}
+}
+
+func TestTranspose3D(t *testing.T) {
+	shape := NewShape3D(5, 34, 7)
+	src := make([]float64, shape.Size())
+	for i := range src {
+		src[i] = float64(i)
+	}
+
+	for _, perm := range [][3]int{{0, 1, 2}, {1, 0, 2}, {0, 2, 1}, {2, 1, 0}, {1, 2, 0}, {2, 0, 1}} {
+		n := shape.Permute(perm)
+		dst := make([]float64, shape.Size())
+		Transpose3D(dst, src, shape, perm)
+
+		for idx, v := range dst {
+			var d, c [3]int
+			d[0], d[1], d[2] = FromIndex3D(idx, n)
+			for a := range 3 {
+				c[perm[a]] = d[a]
+			}
+			if want := src[Index3D(c[0], c[1], c[2], shape)]; v != want {
+				t.Fatalf("perm %v: dst%v = %v, want %v", perm, d, v, want)
+			}
+		}
+	}
+}
+
+func TestTranspose3DInvalidPerm(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a repeated axis")
+		}
+	}()
+
+	Transpose3D(make([]float64, 8), make([]float64, 8), NewShape3D(2, 2, 2), [3]int{0, 0, 2})
+}
+
+func BenchmarkTranspose2D(b *testing.B) {
+	const n = 1024
+	src := make([]float64, n*n)
+	dst := make([]float64, n*n)
+
+	b.SetBytes(int64(16 * n * n))
+	for b.Loop() {
+		Transpose2D(dst, src, n, n)
+	}
+}
@@ -0,0 +1,349 @@
+package homogenize
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Option configures Solve.
+type Option func(*options)
+
+type options struct {
+	tol     float64
+	maxIter int
+	workers int
+}
+
+// WithTolerance sets the relative residual at which the corrector iteration
+// stops (default 1e-10).
+func WithTolerance(tol float64) Option {
+	return func(o *options) {
+		o.tol = tol
+	}
+}
+
+// WithMaxIterations bounds the iterations per corrector (default 1000).
+func WithMaxIterations(n int) Option {
+	return func(o *options) {
+		o.maxIter = n
+	}
+}
+
+// WithWorkers sets the number of parallel workers of the preconditioner
+// plan. 0 uses runtime.GOMAXPROCS.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// ConvergenceError is returned by Solve when a corrector does not reach the
+// tolerance within the iteration limit.
+type ConvergenceError struct {
+	// Direction is the macro direction e_i of the corrector.
+	Direction  int
+	Iterations int
+	Residual   float64
+	Tolerance  float64
+}
+
+func (e *ConvergenceError) Error() string {
+	return fmt.Sprintf("homogenize: corrector %d did not converge: relative residual %g after %d iterations (tolerance %g)",
+		e.Direction, e.Residual, e.Iterations, e.Tolerance)
+}
+
+// Result holds the solution of the cell problems.
+type Result struct {
+	// Effective is the effective conductivity tensor, Effective[j][i] being
+	// the mean flux along axis j for the unit macro gradient e_i. It is
+	// symmetric up to the solver tolerance.
+	Effective [][]float64
+
+	// Correctors holds the zero-mean periodic corrector χ_i per direction.
+	Correctors [][]float64
+
+	// Iterations is the number of conjugate-gradient iterations per corrector.
+	Iterations []int
+
+	plan *poisson.Plan
+}
+
+// Field writes the full cell field u_i = (x_i - c_i) + χ_i of direction i
+// into dst, where c_i is the mean grid position along axis i.
+func (r *Result) Field(dst []float64, i int) error {
+	if i < 0 || i >= len(r.Correctors) {
+		return &poisson.ValidationError{Field: "i", Message: "direction outside the cell dimension"}
+	}
+
+	if len(dst) != len(r.Correctors[i]) {
+		return poisson.ErrSizeMismatch
+	}
+
+	copy(dst, r.Correctors[i])
+	grad := make([]float64, len(r.Correctors))
+	grad[i] = 1
+
+	return r.plan.AddMeanGradient(dst, grad)
+}
+
+// Solve solves the cell problems of the periodic cell with grid sizes n,
+// spacings h and conductivity kappa (row-major, one value per grid point),
+// and returns the correctors and the effective tensor.
+func Solve(n []int, h []float64, kappa []float64, opts ...Option) (*Result, error) {
+	o := options{tol: 1e-10, maxIter: 1000}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	if !(o.tol > 0) {
+		return nil, &poisson.ValidationError{Field: "Tolerance", Message: "must be positive"}
+	}
+	if o.maxIter < 1 {
+		return nil, &poisson.ValidationError{Field: "MaxIterations", Message: "must be positive"}
+	}
+
+	dim := len(n)
+	if dim < 1 || dim > 3 {
+		return nil, &poisson.ValidationError{Field: "n", Message: "must list 1, 2, or 3 sizes"}
+	}
+	if len(h) != dim {
+		return nil, &poisson.ValidationError{Field: "h", Message: "length must match n"}
+	}
+
+	bc := make([]poisson.BCType, dim)
+	for axis := range bc {
+		bc[axis] = poisson.Periodic
+	}
+
+	plan, err := poisson.NewPlan(dim, n, h, bc,
+		poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithWorkers(o.workers))
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := newCell(n, h, kappa)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Effective:  make([][]float64, dim),
+		Correctors: make([][]float64, dim),
+		Iterations: make([]int, dim),
+		plan:       plan,
+	}
+	for i := range dim {
+		res.Effective[i] = make([]float64, dim)
+	}
+
+	for i := range dim {
+		chi := make([]float64, c.size)
+		iters, err := c.solveCorrector(chi, i, plan, o)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Correctors[i] = chi
+		res.Iterations[i] = iters
+		for j := range dim {
+			res.Effective[j][i] = c.meanFlux(chi, i, j)
+		}
+	}
+
+	return res, nil
+}
+
+// cell holds the discretized variable-coefficient operator
+// A χ = -Σ_a D_a⁻(κ_a D_a⁺ χ) on the periodic grid.
+type cell struct {
+	dim  int
+	size int
+	h    [3]float64
+
+	// next[a][p] is the index of the neighbor p+e_a (periodic), and
+	// face[a][p] the conductivity on the face between p and next[a][p].
+	next [3][]int
+	face [3][]float64
+
+	// ref is the constant conductivity of the preconditioner.
+	ref float64
+}
+
+func newCell(n []int, h []float64, kappa []float64) (*cell, error) {
+	dim := len(n)
+	shape := [3]int{1, 1, 1}
+	c := &cell{dim: dim, size: 1}
+	for axis := range dim {
+		shape[axis] = n[axis]
+		c.h[axis] = h[axis]
+		c.size *= n[axis]
+	}
+
+	if len(kappa) != c.size {
+		return nil, &poisson.SizeError{Expected: c.size, Got: len(kappa), Context: "conductivity"}
+	}
+
+	for p, k := range kappa {
+		if !(k > 0) || math.IsInf(k, 0) {
+			return nil, &poisson.ValidationError{
+				Field:   fmt.Sprintf("kappa[%d]", p),
+				Message: "must be positive and finite",
+			}
+		}
+		c.ref += k
+	}
+	c.ref /= float64(c.size)
+
+	stride := [3]int{shape[1] * shape[2], shape[2], 1}
+	for axis := range dim {
+		c.next[axis] = make([]int, c.size)
+		c.face[axis] = make([]float64, c.size)
+	}
+
+	p := 0
+	for i := range shape[0] {
+		for j := range shape[1] {
+			for k := range shape[2] {
+				idx := [3]int{i, j, k}
+				for axis := range dim {
+					q := p + stride[axis]
+					if idx[axis] == shape[axis]-1 {
+						q -= shape[axis] * stride[axis]
+					}
+
+					c.next[axis][p] = q
+					c.face[axis][p] = 2 * kappa[p] * kappa[q] / (kappa[p] + kappa[q])
+				}
+				p++
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// apply computes dst = A x.
+func (c *cell) apply(dst, x []float64) {
+	clear(dst)
+	for axis := range c.dim {
+		inv := 1 / (c.h[axis] * c.h[axis])
+		next, face := c.next[axis], c.face[axis]
+		for p, q := range next {
+			flux := face[p] * (x[q] - x[p]) * inv
+			dst[p] -= flux
+			dst[q] += flux
+		}
+	}
+}
+
+// rhs computes the source of corrector i, D_i⁻ κ_i.
+func (c *cell) rhs(dst []float64, i int) {
+	clear(dst)
+	inv := 1 / c.h[i]
+	for p, q := range c.next[i] {
+		f := c.face[i][p] * inv
+		dst[p] += f
+		dst[q] -= f
+	}
+}
+
+// meanFlux returns the mean flux along axis j for macro direction i,
+// ⟨κ_j (δ_ij + D_j⁺ χ_i)⟩.
+func (c *cell) meanFlux(chi []float64, i, j int) float64 {
+	delta := 0.0
+	if i == j {
+		delta = 1
+	}
+
+	total := 0.0
+	for p, q := range c.next[j] {
+		total += c.face[j][p] * (delta + (chi[q]-chi[p])/c.h[j])
+	}
+
+	return total / float64(c.size)
+}
+
+// solveCorrector solves A χ = D_i⁻ κ_i by preconditioned conjugate
+// gradients, with the constant-coefficient operator ref*(-Δ) as
+// preconditioner. chi must be zero on entry.
+func (c *cell) solveCorrector(chi []float64, i int, plan *poisson.Plan, o options) (int, error) {
+	r := make([]float64, c.size)
+	z := make([]float64, c.size)
+	d := make([]float64, c.size)
+	ad := make([]float64, c.size)
+
+	c.rhs(r, i)
+	norm0 := math.Sqrt(dot(r, r))
+	if norm0 == 0 {
+		return 0, nil
+	}
+
+	precondition := func() error {
+		if err := plan.Solve(z, r); err != nil {
+			return err
+		}
+		for p := range z {
+			z[p] /= c.ref
+		}
+		return nil
+	}
+
+	if err := precondition(); err != nil {
+		return 0, err
+	}
+	copy(d, z)
+	rz := dot(r, z)
+
+	res := 1.0
+	for iter := 1; iter <= o.maxIter; iter++ {
+		c.apply(ad, d)
+		step := rz / dot(d, ad)
+		for p := range chi {
+			chi[p] += step * d[p]
+			r[p] -= step * ad[p]
+		}
+
+		res = math.Sqrt(dot(r, r)) / norm0
+		if res <= o.tol {
+			removeMean(chi)
+			return iter, nil
+		}
+
+		if err := precondition(); err != nil {
+			return iter, err
+		}
+		rzNext := dot(r, z)
+		beta := rzNext / rz
+		rz = rzNext
+		for p := range d {
+			d[p] = z[p] + beta*d[p]
+		}
+	}
+
+	return o.maxIter, &ConvergenceError{Direction: i, Iterations: o.maxIter, Residual: res, Tolerance: o.tol}
+}
+
+func dot(a, b []float64) float64 {
+	total := 0.0
+	for p := range a {
+		total += a[p] * b[p]
+	}
+
+	return total
+}
+
+func removeMean(x []float64) {
+	mean := 0.0
+	for _, v := range x {
+		mean += v
+	}
+	mean /= float64(len(x))
+
+	for p := range x {
+		x[p] -= mean
+	}
+}
@@ -0,0 +1,138 @@
+package homogenize_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/homogenize"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestSolve_ConstantConductivity(t *testing.T) {
+	kappa := make([]float64, 6*4)
+	for p := range kappa {
+		kappa[p] = 2.5
+	}
+
+	res, err := homogenize.Solve([]int{6, 4}, []float64{0.2, 0.3}, kappa)
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	for j := range 2 {
+		for i := range 2 {
+			want := 0.0
+			if i == j {
+				want = 2.5
+			}
+			if math.Abs(res.Effective[j][i]-want) > 1e-14 {
+				t.Errorf("Effective[%d][%d] = %g, want %g", j, i, res.Effective[j][i], want)
+			}
+		}
+		if res.Iterations[j] != 0 {
+			t.Errorf("direction %d took %d iterations, want 0", j, res.Iterations[j])
+		}
+	}
+}
+
+func TestSolve_Laminate(t *testing.T) {
+	// Layers normal to x: the effective tensor is the harmonic mean of the
+	// x-face conductivities across the layers and the arithmetic mean along
+	// them.
+	nx, ny := 8, 4
+	kappa := make([]float64, nx*ny)
+	layer := []float64{1, 1, 10, 10, 10, 1, 3, 3}
+	for i := range nx {
+		for j := range ny {
+			kappa[i*ny+j] = layer[i]
+		}
+	}
+
+	res, err := homogenize.Solve([]int{nx, ny}, []float64{0.125, 0.25}, kappa, homogenize.WithTolerance(1e-12))
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	harmonic, arithmetic := 0.0, 0.0
+	for i := range nx {
+		a, b := layer[i], layer[(i+1)%nx]
+		harmonic += (a + b) / (2 * a * b)
+		arithmetic += a
+	}
+	harmonic = float64(nx) / harmonic
+	arithmetic /= float64(nx)
+
+	if got := res.Effective[0][0]; math.Abs(got-harmonic) > 1e-9 {
+		t.Errorf("K_xx = %g, want harmonic mean %g", got, harmonic)
+	}
+	if got := res.Effective[1][1]; math.Abs(got-arithmetic) > 1e-9 {
+		t.Errorf("K_yy = %g, want arithmetic mean %g", got, arithmetic)
+	}
+	if math.Abs(res.Effective[0][1]) > 1e-9 || math.Abs(res.Effective[1][0]) > 1e-9 {
+		t.Errorf("off-diagonal entries %g, %g, want 0", res.Effective[0][1], res.Effective[1][0])
+	}
+
+	// The full field of direction x rises by the box length across the cell
+	// on average, while the corrector stays periodic.
+	u := make([]float64, nx*ny)
+	if err := res.Field(u, 0); err != nil {
+		t.Fatalf("Field failed: %v", err)
+	}
+	chi := res.Correctors[0]
+	if diff := (u[(nx-1)*ny] - u[0]) - (chi[(nx-1)*ny] - chi[0]); math.Abs(diff-float64(nx-1)*0.125) > 1e-12 {
+		t.Errorf("linear part across the cell = %g, want %g", diff, float64(nx-1)*0.125)
+	}
+}
+
+func TestSolve_InclusionBoundsAndSymmetry(t *testing.T) {
+	n := 8
+	kappa := make([]float64, n*n)
+	mean, harmonic := 0.0, 0.0
+	for i := range n {
+		for j := range n {
+			k := 1.0
+			if i >= 2 && i < 5 && j >= 3 && j < 7 {
+				k = 20
+			}
+			kappa[i*n+j] = k
+			mean += k
+			harmonic += 1 / k
+		}
+	}
+	mean /= float64(n * n)
+	harmonic = float64(n*n) / harmonic
+
+	res, err := homogenize.Solve([]int{n, n}, []float64{0.1, 0.1}, kappa, homogenize.WithTolerance(1e-12))
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if d := math.Abs(res.Effective[0][1] - res.Effective[1][0]); d > 1e-9 {
+		t.Errorf("tensor not symmetric: off-diagonals differ by %g", d)
+	}
+	for axis := range 2 {
+		if k := res.Effective[axis][axis]; k < harmonic || k > mean {
+			t.Errorf("K[%d][%d] = %g outside the bounds [%g, %g]", axis, axis, k, harmonic, mean)
+		}
+	}
+}
+
+func TestSolve_Validation(t *testing.T) {
+	var verr *poisson.ValidationError
+	if _, err := homogenize.Solve([]int{4}, []float64{1}, []float64{1, 0, 1, 1}); !errors.As(err, &verr) {
+		t.Errorf("zero conductivity: got %v", err)
+	}
+
+	var serr *poisson.SizeError
+	if _, err := homogenize.Solve([]int{4}, []float64{1}, []float64{1, 1}); !errors.As(err, &serr) {
+		t.Errorf("short conductivity: got %v", err)
+	}
+
+	kappa := []float64{1, 100, 1, 100, 1, 100, 1, 50}
+	var cerr *homogenize.ConvergenceError
+	if _, err := homogenize.Solve([]int{2, 4}, []float64{1, 1}, kappa, homogenize.WithMaxIterations(1),
+		homogenize.WithTolerance(1e-14)); !errors.As(err, &cerr) {
+		t.Errorf("iteration limit: got %v", err)
+	}
+}
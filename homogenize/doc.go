@@ -0,0 +1,21 @@
+// Package homogenize computes effective (homogenized) conductivity tensors of
+// periodic microstructures.
+//
+// For a periodic cell with conductivity κ(x) > 0, the corrector χ_i of macro
+// direction e_i solves the cell problem
+//
+//	-∇·(κ(x)(e_i + ∇χ_i)) = 0,   χ_i periodic with zero mean,
+//
+// and the effective tensor is the cell average of the resulting flux,
+// K_ji = ⟨κ (δ_ij + ∂_j χ_i)⟩. The full field u_i = x_i + χ_i has the imposed
+// mean gradient e_i (see poisson.Plan.SolveWithMeanGradient).
+//
+// Solve discretizes the cell problem with the conservative second-order
+// stencil on the periodic grid of poisson plans (points x = i*h), with
+// face conductivities the harmonic mean of the two adjacent grid values.
+// Each corrector is found by conjugate gradients preconditioned with a
+// constant-coefficient periodic Poisson plan, so the iteration count depends
+// on the contrast max κ / min κ but not on the grid size.
+//
+// Data layout is row-major, as in the rest of the module.
+package homogenize
@@ -0,0 +1,164 @@
+// Package par provides the parallel-loop primitives shared by the solvers,
+// the finite difference kernels and the transforms: worker-count helpers and
+// loops that split a range of tasks over goroutines.
+//
+// For splits the tasks statically into one contiguous chunk per worker,
+// the cheapest choice when every task costs about the same (lines of one
+// length, slabs of one size). ForChunked hands out fixed-size chunks on
+// demand and ForGuided chunks that shrink as the work runs out, both for
+// uneven tasks. Every loop passes fn the index of the goroutine running it,
+// in [0, workers), so callers can keep per-worker scratch.
+package par
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Workers returns the worker count to use for a requested count: workers
+// itself if positive, otherwise runtime.GOMAXPROCS.
+func Workers(workers int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
+// Clamp limits workers to the range [1, tasks], so that no worker is left
+// without a task; it returns 1 if there are no tasks.
+func Clamp(workers, tasks int) int {
+	if tasks < 1 {
+		return 1
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > tasks {
+		return tasks
+	}
+	return workers
+}
+
+// For splits tasks into at most workers contiguous chunks and calls fn for
+// each chunk [start, end) on its own goroutine, waiting for all of them. A
+// single worker or task runs fn on the calling goroutine. It returns the
+// first error reported by any chunk.
+func For(workers, tasks int, fn func(worker, start, end int) error) error {
+	if tasks <= 0 {
+		return nil
+	}
+	if workers <= 1 || tasks == 1 {
+		return fn(0, 0, tasks)
+	}
+
+	chunk := (tasks + workers - 1) / workers
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var err error
+
+	for w := 0; w < workers; w++ {
+		start := w * chunk
+		if start >= tasks {
+			break
+		}
+		end := start + chunk
+		if end > tasks {
+			end = tasks
+		}
+
+		wg.Add(1)
+		go func(worker, start, end int) {
+			defer wg.Done()
+			if e := fn(worker, start, end); e != nil {
+				errOnce.Do(func() {
+					err = e
+				})
+			}
+		}(w, start, end)
+	}
+
+	wg.Wait()
+	return err
+}
+
+// ForChunked calls fn for consecutive chunks [start, end) of chunk tasks
+// (the last may be shorter), handed out in order to at most workers
+// goroutines as they become free. A chunk size below 1 is treated as 1.
+// After a chunk fails no further chunks are started; the first error is
+// returned.
+func ForChunked(workers, tasks, chunk int, fn func(worker, start, end int) error) error {
+	chunk = max(chunk, 1)
+
+	return dynamic(workers, tasks, fn, func(int) int {
+		return chunk
+	})
+}
+
+// ForGuided is ForChunked with guided scheduling: each chunk holds the
+// remaining tasks divided by twice the worker count, but at least minChunk,
+// so the loop starts with large chunks and finishes with small ones that
+// even out the load.
+func ForGuided(workers, tasks, minChunk int, fn func(worker, start, end int) error) error {
+	minChunk = max(minChunk, 1)
+	workers = Clamp(workers, tasks)
+
+	return dynamic(workers, tasks, fn, func(remaining int) int {
+		return max(remaining/(2*workers), minChunk)
+	})
+}
+
+// dynamic runs fn over chunks of tasks claimed on demand by at most workers
+// goroutines; size returns the length of the next chunk given the number of
+// unclaimed tasks.
+func dynamic(workers, tasks int, fn func(worker, start, end int) error, size func(remaining int) int) error {
+	if tasks <= 0 {
+		return nil
+	}
+	if workers <= 1 || tasks == 1 {
+		return fn(0, 0, tasks)
+	}
+
+	var next atomic.Int64
+	var failed atomic.Bool
+	var errOnce sync.Once
+	var err error
+
+	// claim reserves the next chunk, returning false when none is left.
+	claim := func() (int, int, bool) {
+		for {
+			start := int(next.Load())
+			if start >= tasks || failed.Load() {
+				return 0, 0, false
+			}
+
+			end := min(start+size(tasks-start), tasks)
+			if next.CompareAndSwap(int64(start), int64(end)) {
+				return start, end, true
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := range Clamp(workers, tasks) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start, end, ok := claim(); ok; start, end, ok = claim() {
+				if e := fn(w, start, end); e != nil {
+					failed.Store(true)
+					errOnce.Do(func() {
+						err = e
+					})
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,101 @@
+package par
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type loop func(workers, tasks int, fn func(worker, start, end int) error) error
+
+func loops() map[string]loop {
+	return map[string]loop{
+		"For": For,
+		"ForChunked": func(workers, tasks int, fn func(worker, start, end int) error) error {
+			return ForChunked(workers, tasks, 3, fn)
+		},
+		"ForGuided": func(workers, tasks int, fn func(worker, start, end int) error) error {
+			return ForGuided(workers, tasks, 2, fn)
+		},
+	}
+}
+
+// Every loop visits each task exactly once, with worker indices below the
+// worker count.
+func TestLoopsCoverTasks(t *testing.T) {
+	for name, run := range loops() {
+		for _, workers := range []int{1, 3, 8} {
+			for _, tasks := range []int{0, 1, 7, 100} {
+				seen := make([]atomic.Int32, tasks)
+				err := run(workers, tasks, func(worker, start, end int) error {
+					if worker < 0 || worker >= workers || start >= end {
+						t.Errorf("%s(%d, %d): chunk [%d, %d) on worker %d", name, workers, tasks, start, end, worker)
+					}
+					for i := start; i < end; i++ {
+						seen[i].Add(1)
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("%s(%d, %d): %v", name, workers, tasks, err)
+				}
+
+				for i := range seen {
+					if n := seen[i].Load(); n != 1 {
+						t.Fatalf("%s(%d, %d): task %d visited %d times", name, workers, tasks, i, n)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLoopsReturnError(t *testing.T) {
+	want := errors.New("chunk failed")
+
+	for name, run := range loops() {
+		err := run(4, 50, func(_, start, end int) error {
+			if start <= 20 && 20 < end {
+				return want
+			}
+			return nil
+		})
+		if !errors.Is(err, want) {
+			t.Errorf("%s: got %v, want %v", name, err, want)
+		}
+	}
+}
+
+// Guided chunks shrink towards the minimum as the tasks run out.
+func TestForGuidedChunkSizes(t *testing.T) {
+	var first, last atomic.Int64
+	first.Store(-1)
+
+	_ = ForGuided(2, 1000, 4, func(_, start, end int) error {
+		if start == 0 {
+			first.Store(int64(end - start))
+		}
+		if end == 1000 {
+			last.Store(int64(end - start))
+		}
+		return nil
+	})
+
+	if first.Load() != 250 || last.Load() > 4 {
+		t.Errorf("first/last chunk = %d/%d, want 250/<=4", first.Load(), last.Load())
+	}
+}
+
+func TestWorkersClamp(t *testing.T) {
+	if Workers(3) != 3 || Workers(0) < 1 || Workers(-2) < 1 {
+		t.Errorf("Workers(3/0/-2) = %d/%d/%d", Workers(3), Workers(0), Workers(-2))
+	}
+
+	for _, tt := range []struct{ workers, tasks, want int }{
+		{4, 10, 4}, {4, 2, 2}, {0, 5, 1}, {3, 0, 1},
+	} {
+		if got := Clamp(tt.workers, tt.tasks); got != tt.want {
+			t.Errorf("Clamp(%d, %d) = %d, want %d", tt.workers, tt.tasks, got, tt.want)
+		}
+	}
+}
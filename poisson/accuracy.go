@@ -0,0 +1,156 @@
+package poisson
+
+// Path identifies the numeric path a plan uses for its transforms.
+type Path int
+
+const (
+	// PathComplex128 is the default path: complex float64 FFTs and
+	// float64 real-to-real transforms.
+	PathComplex128 Path = iota
+
+	// PathRealFloat32 is the real FFT path selected by WithRealFFT on 2D/3D
+	// periodic plans. Transforms run in float32.
+	PathRealFloat32
+
+	// PathGPU is reserved for future single-precision GPU transforms. No
+	// plan selects it yet; the WithGPU backend runs in double precision and
+	// keeps PathComplex128.
+	PathGPU
+
+	// PathRealFloat64 is the real FFT path selected by WithRealFFT together
+	// with WithRealFFTPrecision(RealFFTFloat64). Transforms run in float64.
+	PathRealFloat64
+)
+
+// String returns the string representation of the path.
+func (p Path) String() string {
+	switch p {
+	case PathComplex128:
+		return "complex128"
+	case PathRealFloat32:
+		return "real-float32"
+	case PathGPU:
+		return "gpu"
+	case PathRealFloat64:
+		return "real-float64"
+	default:
+		return "Unknown"
+	}
+}
+
+// AccuracyProfile documents the accuracy to expect from a solver path, so
+// tests can assert against the tolerance that matches the path actually in
+// use instead of hard-coding one value.
+//
+// Errors are relative max-norm errors, measured against the solution of the
+// discrete (finite-difference) problem and scaled by max|u|. They hold for
+// grids up to about 2^24 points with O(1) data; badly scaled data or very
+// fine grids (large condition numbers) need proportionally looser bounds.
+type AccuracyProfile struct {
+	// Path is the numeric path the profile describes.
+	Path Path `json:"-"`
+
+	// Name is Path.String(), for machine-readable output.
+	Name string `json:"path"`
+
+	// Epsilon is the machine epsilon of the working precision.
+	Epsilon float64 `json:"epsilon"`
+
+	// SolutionTol bounds the relative error of a solution.
+	SolutionTol float64 `json:"solution_tol"`
+
+	// ResidualTol bounds the relative residual ‖A u - f‖∞ / ‖f‖∞, e.g. for
+	// WithVerify.
+	ResidualTol float64 `json:"residual_tol"`
+}
+
+var accuracyProfiles = [...]AccuracyProfile{
+	PathComplex128: {
+		Path:        PathComplex128,
+		Name:        PathComplex128.String(),
+		Epsilon:     0x1p-52,
+		SolutionTol: 1e-10,
+		ResidualTol: 1e-9,
+	},
+	PathRealFloat32: {
+		Path:        PathRealFloat32,
+		Name:        PathRealFloat32.String(),
+		Epsilon:     0x1p-23,
+		SolutionTol: 1e-5,
+		ResidualTol: 1e-3,
+	},
+	PathGPU: {
+		Path:        PathGPU,
+		Name:        PathGPU.String(),
+		Epsilon:     0x1p-23,
+		SolutionTol: 1e-5,
+		ResidualTol: 1e-3,
+	},
+	PathRealFloat64: {
+		Path:        PathRealFloat64,
+		Name:        PathRealFloat64.String(),
+		Epsilon:     0x1p-52,
+		SolutionTol: 1e-10,
+		ResidualTol: 1e-9,
+	},
+}
+
+// AccuracyProfileFor returns the documented profile for a path. Unknown paths
+// return the PathComplex128 profile.
+func AccuracyProfileFor(path Path) AccuracyProfile {
+	if path < 0 || int(path) >= len(accuracyProfiles) {
+		return accuracyProfiles[PathComplex128]
+	}
+
+	return accuracyProfiles[path]
+}
+
+// AccuracyProfiles returns the profiles of all paths, in Path order.
+func AccuracyProfiles() []AccuracyProfile {
+	return append([]AccuracyProfile(nil), accuracyProfiles[:]...)
+}
+
+// AccuracyProfile returns the accuracy profile of the plan's path.
+func (p *Plan) AccuracyProfile() AccuracyProfile {
+	return AccuracyProfileFor(PathComplex128)
+}
+
+// AccuracyProfile returns the accuracy profile of the plan's path.
+func (p *Plan1DPeriodic) AccuracyProfile() AccuracyProfile {
+	return AccuracyProfileFor(PathComplex128)
+}
+
+// AccuracyProfile returns the accuracy profile of the plan's path. Plans
+// created with WithRealFFT(true) report PathRealFloat32 (or PathRealFloat64
+// with WithRealFFTPrecision) only when the real FFT was actually enabled for
+// the grid size.
+func (p *Plan2DPeriodic) AccuracyProfile() AccuracyProfile {
+	if p.r64 != nil {
+		return AccuracyProfileFor(PathRealFloat64)
+	}
+	if p.useR {
+		return AccuracyProfileFor(PathRealFloat32)
+	}
+
+	return AccuracyProfileFor(PathComplex128)
+}
+
+// AccuracyProfile returns the accuracy profile of the plan's path. Plans
+// created with WithRealFFT(true) report PathRealFloat32 (or PathRealFloat64
+// with WithRealFFTPrecision) only when the real FFT was actually enabled for
+// the grid size.
+func (p *Plan3DPeriodic) AccuracyProfile() AccuracyProfile {
+	if p.r64 != nil {
+		return AccuracyProfileFor(PathRealFloat64)
+	}
+	if p.useR {
+		return AccuracyProfileFor(PathRealFloat32)
+	}
+
+	return AccuracyProfileFor(PathComplex128)
+}
+
+// AccuracyProfile returns the accuracy profile of the plan's path.
+func (p *PlanNDPeriodic) AccuracyProfile() AccuracyProfile {
+	return AccuracyProfileFor(PathComplex128)
+}
@@ -0,0 +1,84 @@
+package poisson_test
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestAccuracyProfiles(t *testing.T) {
+	profiles := poisson.AccuracyProfiles()
+	if len(profiles) != 4 {
+		t.Fatalf("got %d profiles, want 4", len(profiles))
+	}
+
+	for i, p := range profiles {
+		if p.Path != poisson.Path(i) || p.Name != p.Path.String() {
+			t.Fatalf("profile %d has path %v (%q)", i, p.Path, p.Name)
+		}
+		if p.SolutionTol < p.Epsilon || p.ResidualTol < p.Epsilon {
+			t.Fatalf("profile %s has tolerance below epsilon", p.Name)
+		}
+	}
+
+	if f64, f32 := poisson.AccuracyProfileFor(poisson.PathComplex128), poisson.AccuracyProfileFor(poisson.PathRealFloat32); f32.SolutionTol <= f64.SolutionTol {
+		t.Fatalf("float32 tolerance %g not looser than float64 %g", f32.SolutionTol, f64.SolutionTol)
+	}
+
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"path":"real-float32"`) {
+		t.Fatalf("JSON missing path name: %s", data)
+	}
+}
+
+func TestAccuracyProfile_MatchesSolveError(t *testing.T) {
+	for _, useReal := range []bool{false, true} {
+		nx, ny := 32, 32
+		h := [2]float64{1.0 / float64(nx), 1.0 / float64(ny)}
+
+		plan, err := poisson.NewPlan2DPeriodic(nx, ny, h[0], h[1], poisson.WithRealFFT(useReal))
+		if err != nil {
+			t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+		}
+
+		profile := plan.AccuracyProfile()
+		if !useReal && profile.Path != poisson.PathComplex128 {
+			t.Fatalf("complex plan reports path %v", profile.Path)
+		}
+
+		u := make([]float64, nx*ny)
+		for i := range nx {
+			for j := range ny {
+				u[i*ny+j] = math.Sin(2*math.Pi*float64(i)*h[0]) * math.Cos(4*math.Pi*float64(j)*h[1])
+			}
+		}
+
+		rhs := make([]float64, nx*ny)
+		fd.Apply2D(rhs, u, grid.NewShape2D(nx, ny), h, [2]poisson.BCType{poisson.Periodic, poisson.Periodic})
+
+		got := make([]float64, nx*ny)
+		if err := plan.Solve(got, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+
+		if diff := maxAbsDiff(got, u); diff > profile.SolutionTol {
+			t.Fatalf("%s: error %g exceeds profile tolerance %g", profile.Name, diff, profile.SolutionTol)
+		}
+	}
+
+	plan, err := poisson.NewPlan(1, []int{8}, []float64{1}, []poisson.BCType{poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	if p := plan.AccuracyProfile(); p.Path != poisson.PathComplex128 {
+		t.Fatalf("Plan reports path %v", p.Path)
+	}
+}
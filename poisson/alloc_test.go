@@ -0,0 +1,162 @@
+package poisson_test
+
+import (
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// allocRuns is the number of runs AllocsPerRun averages over.
+const allocRuns = 10
+
+// raceEnabled is set in race builds, whose instrumentation allocates.
+var raceEnabled bool
+
+// requireNoAllocs fails if fn allocates. fn runs once beforehand so that
+// lazily built state (e.g. precomputed symbol tables) is in place.
+func requireNoAllocs(t *testing.T, name string, fn func() error) {
+	t.Helper()
+
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful with -race")
+	}
+
+	if err := fn(); err != nil {
+		t.Fatalf("%s failed: %v", name, err)
+	}
+
+	if allocs := testing.AllocsPerRun(allocRuns, func() { _ = fn() }); allocs != 0 {
+		t.Errorf("%s allocated %v times per run, want 0", name, allocs)
+	}
+}
+
+func TestPlan_SolveDoesNotAllocate(t *testing.T) {
+	one := poisson.WithWorkers(1)
+	ws := poisson.NewWorkspace(0, 0)
+
+	cases := []struct {
+		name string
+		dim  int
+		n    []int
+		bc   []poisson.BCType
+		opts []poisson.Option
+	}{
+		{"1D periodic", 1, []int{16}, []poisson.BCType{poisson.Periodic}, nil},
+		{"1D Dirichlet", 1, []int{16}, []poisson.BCType{poisson.Dirichlet}, nil},
+		{"2D Dirichlet/Neumann", 2, []int{8, 6}, []poisson.BCType{poisson.Dirichlet, poisson.Neumann}, nil},
+		{"3D mixed", 3, []int{4, 4, 4}, []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}, nil},
+		{"precomputed inverse", 2, []int{8, 6}, []poisson.BCType{poisson.Periodic, poisson.Neumann},
+			[]poisson.Option{poisson.WithPrecomputedInverse()}},
+		{"pooled workspace", 2, []int{8, 6}, []poisson.BCType{poisson.Dirichlet, poisson.Neumann},
+			[]poisson.Option{poisson.WithPooledWorkspace()}},
+		{"external workspace", 2, []int{8, 6}, []poisson.BCType{poisson.Dirichlet, poisson.Neumann},
+			[]poisson.Option{poisson.WithWorkspace(&ws)}},
+	}
+
+	for _, tc := range cases {
+		h := make([]float64, tc.dim)
+		size := 1
+		for axis := range h {
+			h[axis] = 0.1
+			size *= tc.n[axis]
+		}
+
+		plan, err := poisson.NewPlan(tc.dim, tc.n, h, tc.bc, append(tc.opts, one)...)
+		if err != nil {
+			t.Fatalf("%s: NewPlan failed: %v", tc.name, err)
+		}
+
+		rhs := make([]float64, size)
+		for i := range rhs {
+			rhs[i] = float64(i%2)*2 - 1
+		}
+		dst := make([]float64, size)
+
+		requireNoAllocs(t, tc.name+" Solve", func() error { return plan.Solve(dst, rhs) })
+		requireNoAllocs(t, tc.name+" SolveInPlace", func() error {
+			copy(dst, rhs)
+			return plan.SolveInPlace(dst)
+		})
+	}
+}
+
+func TestPlan_SolveVariantsDoNotAllocate(t *testing.T) {
+	one := poisson.WithWorkers(1)
+	n := []int{8, 6}
+	h := []float64{0.1, 0.2}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Neumann}
+
+	plan, err := poisson.NewPlan(2, n, h, bc, one)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 48)
+	dst := make([]float64, 48)
+	faces := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Values: make([]float64, n[1])},
+		{Face: poisson.XHigh, Type: poisson.Dirichlet, Func: func(t float64, _ int) float64 { return t }},
+		{Face: poisson.YHigh, Type: poisson.Neumann, Values: make([]float64, n[0])},
+	}
+	gauge := poisson.WithGauge(1)
+
+	requireNoAllocs(t, "SolveWithBC", func() error { return plan.SolveWithBC(dst, rhs, faces) })
+	requireNoAllocs(t, "SolveWithBCAt", func() error { return plan.SolveWithBCAt(0.5, dst, rhs, faces) })
+	requireNoAllocs(t, "SolveBoundaryAt", func() error { return plan.SolveBoundaryAt(0.5, dst, faces) })
+	requireNoAllocs(t, "Solve with a reused SolveOption", func() error { return plan.Solve(dst, rhs, gauge) })
+
+	helmholtz, err := poisson.NewHelmholtzPlan(2, n, h, bc, 3, one)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+	requireNoAllocs(t, "Helmholtz Solve", func() error { return helmholtz.Solve(dst, rhs) })
+
+	hyper, err := poisson.NewHyperdiffusionPlan(2, n, h, bc, 0.1, 2, one)
+	if err != nil {
+		t.Fatalf("NewHyperdiffusionPlan failed: %v", err)
+	}
+	requireNoAllocs(t, "hyperdiffusion Solve", func() error { return hyper.Solve(dst, rhs) })
+
+	layered, err := poisson.NewLayeredHelmholtzPlan([]int{8, 6, 2}, h, bc, []float64{0, 1}, one)
+	if err != nil {
+		t.Fatalf("NewLayeredHelmholtzPlan failed: %v", err)
+	}
+	rhs3 := make([]float64, 96)
+	dst3 := make([]float64, 96)
+	requireNoAllocs(t, "layered Solve", func() error { return layered.Solve(dst3, rhs3) })
+}
+
+func TestPeriodicPlans_SolveDoesNotAllocate(t *testing.T) {
+	one := poisson.WithWorkers(1)
+
+	p1, err := poisson.NewPlan1DPeriodic(16, 0.1, one)
+	if err != nil {
+		t.Fatalf("NewPlan1DPeriodic failed: %v", err)
+	}
+
+	p2, err := poisson.NewPlan2DPeriodic(8, 8, 0.1, 0.1, one)
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+
+	p3, err := poisson.NewPlan3DPeriodic(4, 4, 4, 0.1, 0.1, 0.1, one)
+	if err != nil {
+		t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+	}
+
+	pn, err := poisson.NewPlanNDPeriodic(poisson.Shape{4, 4, 2, 2}, []float64{0.1, 0.1, 0.1, 0.1}, one)
+	if err != nil {
+		t.Fatalf("NewPlanNDPeriodic failed: %v", err)
+	}
+
+	buf := make([]float64, 64)
+	rhs := make([]float64, 64)
+	for i := range rhs {
+		rhs[i] = float64(i%4) - 1.5
+	}
+
+	requireNoAllocs(t, "Plan1DPeriodic", func() error { return p1.Solve(buf[:16], rhs[:16]) })
+	requireNoAllocs(t, "Plan2DPeriodic", func() error { return p2.Solve(buf, rhs) })
+	requireNoAllocs(t, "Plan3DPeriodic", func() error { return p3.Solve(buf, rhs) })
+	requireNoAllocs(t, "PlanNDPeriodic", func() error { return pn.Solve(buf, rhs) })
+}
@@ -0,0 +1,143 @@
+package poisson
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// tuneCandidate is one configuration benchmarked by WithAutoTune.
+type tuneCandidate struct {
+	workers  int
+	strategy TransformStrategy
+	realFFT  bool
+}
+
+func (c tuneCandidate) String() string {
+	return fmt.Sprintf("workers=%d strategy=%s realFFT=%t", c.workers, c.strategy, c.realFFT)
+}
+
+// option applies the candidate on top of the caller's options and disables
+// tuning for the candidate plan.
+func (c tuneCandidate) option() Option {
+	return func(o *Options) {
+		o.Workers = c.workers
+		o.TransformStrategy = c.strategy
+		o.UseRealFFT = c.realFFT
+		o.AutoTune = 0
+	}
+}
+
+// tuneCandidates lists the configurations to benchmark for opts, the
+// default configuration first: worker counts up to the requested (or
+// GOMAXPROCS) count, blocked transforms if the plan has strided axes, and
+// complex FFTs as an alternative to a requested real FFT.
+func tuneCandidates(opts Options, strided bool) []tuneCandidate {
+	maxWorkers := effectiveWorkers(opts.Workers)
+	workers := []int{maxWorkers}
+	for w := 1; w < maxWorkers; w *= 2 {
+		workers = append(workers, w)
+	}
+
+	strategies := []TransformStrategy{TransformStrided}
+	if strided {
+		strategies = append(strategies, TransformBlocked)
+	}
+
+	realFFT := []bool{false}
+	if opts.UseRealFFT {
+		realFFT = []bool{true, false}
+	}
+
+	var candidates []tuneCandidate
+	for _, r := range realFFT {
+		for _, s := range strategies {
+			for _, w := range workers {
+				candidates = append(candidates, tuneCandidate{workers: w, strategy: s, realFFT: r})
+			}
+		}
+	}
+
+	return candidates
+}
+
+// autoTune builds a plan per candidate with build (appending the candidate
+// option to opts) and keeps the one whose solve of a zero RHS of size(plan)
+// points is fastest, until budget is spent. It returns the kept plan and
+// the WithAutoTune report entry.
+func autoTune[P any](
+	budget time.Duration, candidates []tuneCandidate, opts []Option,
+	build func(...Option) (P, error), size func(P) int, solve func(P, []float64, []float64) error,
+) (P, OptionReport, error) {
+	var (
+		best     P
+		bestTime time.Duration
+		choice   tuneCandidate
+		tried    int
+		reason   string
+	)
+
+	var dst, rhs []float64
+	start := time.Now()
+
+	for i, c := range candidates {
+		if i > 0 && time.Since(start) >= budget {
+			break
+		}
+
+		plan, err := build(append(slices.Clone(opts), c.option())...)
+		if err != nil {
+			return best, OptionReport{}, err
+		}
+
+		if dst == nil {
+			dst = make([]float64, size(plan))
+			rhs = make([]float64, size(plan))
+		}
+
+		elapsed, err := timeSolve(func() error { return solve(plan, dst, rhs) })
+		if err != nil {
+			// Solves that fail (e.g. NullspaceError) fail for every
+			// candidate; keep the default configuration.
+			if i == 0 {
+				best, choice = plan, c
+				reason = fmt.Sprintf("solve failed during tuning (%v); ", err)
+			}
+			break
+		}
+
+		tried++
+		if i == 0 || elapsed < bestTime {
+			best, bestTime, choice = plan, elapsed, c
+		}
+	}
+
+	entry := OptionReport{
+		Option: "WithAutoTune",
+		Status: OptionHonored,
+		Reason: fmt.Sprintf("%skept %s (%d of %d candidates timed)", reason, choice, tried, len(candidates)),
+	}
+
+	return best, entry, nil
+}
+
+// timeSolve returns the fastest of tuneRepetitions solves, after one
+// untimed warm-up solve.
+func timeSolve(solve func() error) (time.Duration, error) {
+	if err := solve(); err != nil {
+		return 0, err
+	}
+
+	var best time.Duration
+	for rep := range tuneRepetitions {
+		start := time.Now()
+		if err := solve(); err != nil {
+			return 0, err
+		}
+		if d := time.Since(start); rep == 0 || d < best {
+			best = d
+		}
+	}
+
+	return best, nil
+}
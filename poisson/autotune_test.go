@@ -0,0 +1,91 @@
+package poisson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_AutoTuneKeepsResults(t *testing.T) {
+	n := []int{8, 6, 4}
+	h := []float64{0.1, 0.2, 0.3}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+
+	tuned, err := poisson.NewHelmholtzPlan(3, n, h, bc, 1, poisson.WithAutoTune(time.Second))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan with auto-tuning failed: %v", err)
+	}
+
+	entry := requireStatus(t, tuned.Report(), "WithAutoTune", poisson.OptionHonored)
+	if !strings.Contains(entry.Reason, "kept workers=") {
+		t.Errorf("reason %q does not name the kept configuration", entry.Reason)
+	}
+
+	plain, err := poisson.NewHelmholtzPlan(3, n, h, bc, 1)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 192)
+	for i := range rhs {
+		rhs[i] = float64(i%7) - 3
+	}
+	got := make([]float64, 192)
+	want := make([]float64, 192)
+	if err := tuned.Solve(got, rhs); err != nil {
+		t.Fatalf("tuned Solve failed: %v", err)
+	}
+	if err := plain.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if diff := maxAbsDiff(got, want); diff > 1e-12 {
+		t.Errorf("tuned plan differs by %g", diff)
+	}
+}
+
+func TestPeriodicPlans_AutoTune(t *testing.T) {
+	p2, err := poisson.NewPlan2DPeriodic(8, 8, 0.1, 0.1, poisson.WithRealFFT(true), poisson.WithAutoTune(time.Second))
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+	entry := requireStatus(t, p2.Report(), "WithAutoTune", poisson.OptionHonored)
+	if !strings.Contains(entry.Reason, "candidates timed") {
+		t.Errorf("unexpected reason %q", entry.Reason)
+	}
+
+	p3, err := poisson.NewPlan3DPeriodic(4, 4, 4, 0.1, 0.1, 0.1, poisson.WithAutoTune(time.Second))
+	if err != nil {
+		t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+	}
+	requireStatus(t, p3.Report(), "WithAutoTune", poisson.OptionHonored)
+
+	// A plan whose solves always fail keeps the default configuration.
+	strict, err := poisson.NewPlan2DPeriodic(8, 8, 0.1, 0.1,
+		poisson.WithNullspace(poisson.NullspaceError), poisson.WithAutoTune(time.Second))
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+	entry = requireStatus(t, strict.Report(), "WithAutoTune", poisson.OptionHonored)
+	if !strings.Contains(entry.Reason, "solve failed during tuning") {
+		t.Errorf("unexpected reason %q", entry.Reason)
+	}
+
+	p1, err := poisson.NewPlan1DPeriodic(8, 0.1, poisson.WithAutoTune(time.Second))
+	if err != nil {
+		t.Fatalf("NewPlan1DPeriodic failed: %v", err)
+	}
+	requireStatus(t, p1.Report(), "WithAutoTune", poisson.OptionRejected)
+}
+
+func TestWithAutoTune_Validation(t *testing.T) {
+	_, err := poisson.ApplyOptions(poisson.DefaultOptions(), []poisson.Option{poisson.WithAutoTune(-time.Second)})
+
+	var verr *poisson.ValidationError
+	if !errors.As(err, &verr) || verr.Field != "AutoTune" {
+		t.Errorf("negative budget: got %v", err)
+	}
+}
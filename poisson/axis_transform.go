@@ -28,6 +28,18 @@ func (t *fftAxisTransform) Inverse(data []complex128, shape grid.Shape, axis int
 	return t.plan.TransformLines(data, shape, axis, true)
 }
 
+func (t *fftAxisTransform) inverseReal(data []complex128, shape grid.Shape, axis int, dst []float64, shift float64) error {
+	return t.plan.inverseLinesReal(data, shape, axis, dst, shift)
+}
+
+func (t *fftAxisTransform) setBlocked(enabled bool) {
+	t.plan.setBlocked(enabled)
+}
+
+func (t *fftAxisTransform) limitWorkers(n int) {
+	t.plan.limitWorkers(n)
+}
+
 func (t *fftAxisTransform) Length() int {
 	return t.plan.Len()
 }
@@ -37,13 +49,13 @@ func (t *fftAxisTransform) NormalizationFactor() float64 {
 }
 
 type dstAxisTransform struct {
-	plan     *r2r.DSTPlan
-	realBuf  []float64
-	imagBuf  []float64
-	workers  int
-	plans    []*r2r.DSTPlan
-	realBufs [][]float64
-	imagBufs [][]float64
+	plan    *r2r.DSTPlan
+	lineBuf []float64 // real parts of a line, then its imaginary parts
+	workers int
+	plans   []*r2r.DSTPlan
+	bufs    [][]float64
+	blocks  lineBlocks
+	workerLimit
 }
 
 func newDSTAxisTransform(n int, workers int) (AxisTransform, error) {
@@ -55,8 +67,7 @@ func newDSTAxisTransform(n int, workers int) (AxisTransform, error) {
 	workers = effectiveWorkers(workers)
 	transform := &dstAxisTransform{
 		plan:    plan,
-		realBuf: make([]float64, n),
-		imagBuf: make([]float64, n),
+		lineBuf: make([]float64, 2*n),
 		workers: workers,
 	}
 
@@ -65,23 +76,19 @@ func newDSTAxisTransform(n int, workers int) (AxisTransform, error) {
 	}
 
 	plans := make([]*r2r.DSTPlan, workers)
-	realBufs := make([][]float64, workers)
-	imagBufs := make([][]float64, workers)
+	bufs := make([][]float64, workers)
 	plans[0] = plan
-	realBufs[0] = transform.realBuf
-	imagBufs[0] = transform.imagBuf
+	bufs[0] = transform.lineBuf
 	for i := 1; i < workers; i++ {
-		clone, err := r2r.NewDSTPlan(n)
+		clone, err := plan.Clone()
 		if err != nil {
 			return nil, err
 		}
 		plans[i] = clone
-		realBufs[i] = make([]float64, n)
-		imagBufs[i] = make([]float64, n)
+		bufs[i] = make([]float64, 2*n)
 	}
 	transform.plans = plans
-	transform.realBufs = realBufs
-	transform.imagBufs = imagBufs
+	transform.bufs = bufs
 
 	return transform, nil
 }
@@ -94,6 +101,74 @@ func (t *dstAxisTransform) Inverse(data []complex128, shape grid.Shape, axis int
 	return t.transformLines(data, shape, axis, true)
 }
 
+// inverseReal transforms only the real part of each line: the imaginary
+// part is discarded by the output anyway.
+func (t *dstAxisTransform) inverseReal(data []complex128, shape grid.Shape, axis int, dst []float64, shift float64) error {
+	if data == nil || dst == nil {
+		return ErrNilBuffer
+	}
+
+	if len(data) != shape.Size() || len(dst) != len(data) || shape.N(axis) != t.plan.Len() {
+		return ErrSizeMismatch
+	}
+
+	lineLen := shape.N(axis)
+	lineStride := grid.RowMajorStride(shape)[axis]
+	if t.blocks.active(lineStride) {
+		return t.transformBlocked(data, lineLen, lineStride, true, dst, shift)
+	}
+
+	numLines := lineCount(shape, axis)
+	workers := clampWorkers(t.capped(t.workers), numLines)
+	pass := linePass{data: data, shape: shape, axis: axis, inverse: true, out: dst, shift: shift}
+
+	return parallelRun(workers, numLines, t, pass, (*dstAxisTransform).realLines)
+}
+
+// realLines is the body of inverseReal for lines [startLine, endLine).
+func (t *dstAxisTransform) realLines(pass linePass, worker, startLine, endLine int) error {
+	plan, lineBuf := t.worker(worker)
+	realBuf := lineBuf[:plan.Len()]
+	lineStride := grid.RowMajorStride(pass.shape)[pass.axis]
+
+	for line := startLine; line < endLine; line++ {
+		start := lineStartIndex(pass.shape, pass.axis, line)
+		for i := range realBuf {
+			realBuf[i] = real(pass.data[start+i*lineStride])
+		}
+
+		if err := plan.Inverse(realBuf, realBuf); err != nil {
+			return fmt.Errorf("DST real line: %w", err)
+		}
+
+		for i, v := range realBuf {
+			pass.out[start+i*lineStride] = v + pass.shift
+		}
+	}
+	return nil
+}
+
+func (t *dstAxisTransform) setBlocked(enabled bool) {
+	t.blocks.set(enabled, t.plan.Len(), t.workers)
+}
+
+// worker returns the plan and line buffer of worker w.
+func (t *dstAxisTransform) worker(w int) (*r2r.DSTPlan, []float64) {
+	if t.plans == nil {
+		return t.plan, t.lineBuf
+	}
+
+	return t.plans[w], t.bufs[w]
+}
+
+// transformBlocked runs the TransformBlocked strategy; see lineBlocks.run.
+func (t *dstAxisTransform) transformBlocked(data []complex128, n, stride int, inverse bool, out []float64, shift float64) error {
+	return t.blocks.run(data, n, stride, t.capped(t.workers), out, shift, func(worker int, line []complex128) error {
+		plan, lineBuf := t.worker(worker)
+		return t.transformLine(plan, lineBuf, line, 0, n, 1, inverse)
+	})
+}
+
 func (t *dstAxisTransform) Length() int {
 	return t.plan.Len()
 }
@@ -122,33 +197,37 @@ func (t *dstAxisTransform) transformLines(
 
 	lineLen := shape.N(axis)
 	lineStride := grid.RowMajorStride(shape)[axis]
+	if t.blocks.active(lineStride) {
+		return t.transformBlocked(data, lineLen, lineStride, inverse, nil, 0)
+	}
+
 	numLines := lineCount(shape, axis)
-	workers := clampWorkers(t.workers, numLines)
-
-	return parallelFor(workers, numLines, func(worker, startLine, endLine int) error {
-		plan := t.plan
-		realBuf := t.realBuf
-		imagBuf := t.imagBuf
-		if workers > 1 {
-			plan = t.plans[worker]
-			realBuf = t.realBufs[worker]
-			imagBuf = t.imagBufs[worker]
-		}
+	workers := clampWorkers(t.capped(t.workers), numLines)
+	pass := linePass{data: data, shape: shape, axis: axis, inverse: inverse}
+
+	return parallelRun(workers, numLines, t, pass, (*dstAxisTransform).lines)
+}
+
+// lines is the body of transformLines for lines [startLine, endLine).
+func (t *dstAxisTransform) lines(pass linePass, worker, startLine, endLine int) error {
+	plan, lineBuf := t.worker(worker)
+	lineLen := pass.shape.N(pass.axis)
+	lineStride := grid.RowMajorStride(pass.shape)[pass.axis]
 
-		for line := startLine; line < endLine; line++ {
-			start := lineStartIndex(shape, axis, line)
-			if err := t.transformLine(plan, realBuf, imagBuf, data, start, lineLen, lineStride, inverse); err != nil {
-				return err
-			}
+	for line := startLine; line < endLine; line++ {
+		start := lineStartIndex(pass.shape, pass.axis, line)
+		if err := t.transformLine(plan, lineBuf, pass.data, start, lineLen, lineStride, pass.inverse); err != nil {
+			return err
 		}
-		return nil
-	})
+	}
+	return nil
 }
 
+// transformLine transforms the real and imaginary parts of one line as a
+// batch of two vectors in lineBuf.
 func (t *dstAxisTransform) transformLine(
 	plan *r2r.DSTPlan,
-	realBuf []float64,
-	imagBuf []float64,
+	lineBuf []float64,
 	data []complex128,
 	start int,
 	length int,
@@ -157,44 +236,35 @@ func (t *dstAxisTransform) transformLine(
 ) error {
 	for i := 0; i < length; i++ {
 		v := data[start+i*stride]
-		realBuf[i] = real(v)
-		imagBuf[i] = imag(v)
+		lineBuf[i] = real(v)
+		lineBuf[length+i] = imag(v)
 	}
 
 	var err error
 	if inverse {
-		err = plan.Inverse(realBuf, realBuf)
-	} else {
-		err = plan.Forward(realBuf, realBuf)
-	}
-	if err != nil {
-		return fmt.Errorf("DST real line: %w", err)
-	}
-
-	if inverse {
-		err = plan.Inverse(imagBuf, imagBuf)
+		err = plan.InverseMany(lineBuf, lineBuf, 2)
 	} else {
-		err = plan.Forward(imagBuf, imagBuf)
+		err = plan.ForwardMany(lineBuf, lineBuf, 2)
 	}
 	if err != nil {
-		return fmt.Errorf("DST imag line: %w", err)
+		return fmt.Errorf("DST line: %w", err)
 	}
 
 	for i := 0; i < length; i++ {
-		data[start+i*stride] = complex(realBuf[i], imagBuf[i])
+		data[start+i*stride] = complex(lineBuf[i], lineBuf[length+i])
 	}
 
 	return nil
 }
 
 type dctAxisTransform struct {
-	plan     *r2r.DCT2Plan
-	realBuf  []float64
-	imagBuf  []float64
-	workers  int
-	plans    []*r2r.DCT2Plan
-	realBufs [][]float64
-	imagBufs [][]float64
+	plan    *r2r.DCT2Plan
+	lineBuf []float64 // real parts of a line, then its imaginary parts
+	workers int
+	plans   []*r2r.DCT2Plan
+	bufs    [][]float64
+	blocks  lineBlocks
+	workerLimit
 }
 
 func newDCTAxisTransform(n int, workers int) (AxisTransform, error) {
@@ -206,8 +276,7 @@ func newDCTAxisTransform(n int, workers int) (AxisTransform, error) {
 	workers = effectiveWorkers(workers)
 	transform := &dctAxisTransform{
 		plan:    plan,
-		realBuf: make([]float64, n),
-		imagBuf: make([]float64, n),
+		lineBuf: make([]float64, 2*n),
 		workers: workers,
 	}
 
@@ -216,23 +285,19 @@ func newDCTAxisTransform(n int, workers int) (AxisTransform, error) {
 	}
 
 	plans := make([]*r2r.DCT2Plan, workers)
-	realBufs := make([][]float64, workers)
-	imagBufs := make([][]float64, workers)
+	bufs := make([][]float64, workers)
 	plans[0] = plan
-	realBufs[0] = transform.realBuf
-	imagBufs[0] = transform.imagBuf
+	bufs[0] = transform.lineBuf
 	for i := 1; i < workers; i++ {
-		clone, err := r2r.NewDCT2Plan(n)
+		clone, err := plan.Clone()
 		if err != nil {
 			return nil, err
 		}
 		plans[i] = clone
-		realBufs[i] = make([]float64, n)
-		imagBufs[i] = make([]float64, n)
+		bufs[i] = make([]float64, 2*n)
 	}
 	transform.plans = plans
-	transform.realBufs = realBufs
-	transform.imagBufs = imagBufs
+	transform.bufs = bufs
 
 	return transform, nil
 }
@@ -245,6 +310,74 @@ func (t *dctAxisTransform) Inverse(data []complex128, shape grid.Shape, axis int
 	return t.transformLines(data, shape, axis, true)
 }
 
+// inverseReal transforms only the real part of each line: the imaginary
+// part is discarded by the output anyway.
+func (t *dctAxisTransform) inverseReal(data []complex128, shape grid.Shape, axis int, dst []float64, shift float64) error {
+	if data == nil || dst == nil {
+		return ErrNilBuffer
+	}
+
+	if len(data) != shape.Size() || len(dst) != len(data) || shape.N(axis) != t.plan.Len() {
+		return ErrSizeMismatch
+	}
+
+	lineLen := shape.N(axis)
+	lineStride := grid.RowMajorStride(shape)[axis]
+	if t.blocks.active(lineStride) {
+		return t.transformBlocked(data, lineLen, lineStride, true, dst, shift)
+	}
+
+	numLines := lineCount(shape, axis)
+	workers := clampWorkers(t.capped(t.workers), numLines)
+	pass := linePass{data: data, shape: shape, axis: axis, inverse: true, out: dst, shift: shift}
+
+	return parallelRun(workers, numLines, t, pass, (*dctAxisTransform).realLines)
+}
+
+// realLines is the body of inverseReal for lines [startLine, endLine).
+func (t *dctAxisTransform) realLines(pass linePass, worker, startLine, endLine int) error {
+	plan, lineBuf := t.worker(worker)
+	realBuf := lineBuf[:plan.Len()]
+	lineStride := grid.RowMajorStride(pass.shape)[pass.axis]
+
+	for line := startLine; line < endLine; line++ {
+		start := lineStartIndex(pass.shape, pass.axis, line)
+		for i := range realBuf {
+			realBuf[i] = real(pass.data[start+i*lineStride])
+		}
+
+		if err := plan.Inverse(realBuf, realBuf); err != nil {
+			return fmt.Errorf("DCT-II real line: %w", err)
+		}
+
+		for i, v := range realBuf {
+			pass.out[start+i*lineStride] = v + pass.shift
+		}
+	}
+	return nil
+}
+
+func (t *dctAxisTransform) setBlocked(enabled bool) {
+	t.blocks.set(enabled, t.plan.Len(), t.workers)
+}
+
+// worker returns the plan and line buffer of worker w.
+func (t *dctAxisTransform) worker(w int) (*r2r.DCT2Plan, []float64) {
+	if t.plans == nil {
+		return t.plan, t.lineBuf
+	}
+
+	return t.plans[w], t.bufs[w]
+}
+
+// transformBlocked runs the TransformBlocked strategy; see lineBlocks.run.
+func (t *dctAxisTransform) transformBlocked(data []complex128, n, stride int, inverse bool, out []float64, shift float64) error {
+	return t.blocks.run(data, n, stride, t.capped(t.workers), out, shift, func(worker int, line []complex128) error {
+		plan, lineBuf := t.worker(worker)
+		return t.transformLine(plan, lineBuf, line, 0, n, 1, inverse)
+	})
+}
+
 func (t *dctAxisTransform) Length() int {
 	return t.plan.Len()
 }
@@ -273,33 +406,37 @@ func (t *dctAxisTransform) transformLines(
 
 	lineLen := shape.N(axis)
 	lineStride := grid.RowMajorStride(shape)[axis]
+	if t.blocks.active(lineStride) {
+		return t.transformBlocked(data, lineLen, lineStride, inverse, nil, 0)
+	}
+
 	numLines := lineCount(shape, axis)
-	workers := clampWorkers(t.workers, numLines)
-
-	return parallelFor(workers, numLines, func(worker, startLine, endLine int) error {
-		plan := t.plan
-		realBuf := t.realBuf
-		imagBuf := t.imagBuf
-		if workers > 1 {
-			plan = t.plans[worker]
-			realBuf = t.realBufs[worker]
-			imagBuf = t.imagBufs[worker]
-		}
+	workers := clampWorkers(t.capped(t.workers), numLines)
+	pass := linePass{data: data, shape: shape, axis: axis, inverse: inverse}
 
-		for line := startLine; line < endLine; line++ {
-			start := lineStartIndex(shape, axis, line)
-			if err := t.transformLine(plan, realBuf, imagBuf, data, start, lineLen, lineStride, inverse); err != nil {
-				return err
-			}
+	return parallelRun(workers, numLines, t, pass, (*dctAxisTransform).lines)
+}
+
+// lines is the body of transformLines for lines [startLine, endLine).
+func (t *dctAxisTransform) lines(pass linePass, worker, startLine, endLine int) error {
+	plan, lineBuf := t.worker(worker)
+	lineLen := pass.shape.N(pass.axis)
+	lineStride := grid.RowMajorStride(pass.shape)[pass.axis]
+
+	for line := startLine; line < endLine; line++ {
+		start := lineStartIndex(pass.shape, pass.axis, line)
+		if err := t.transformLine(plan, lineBuf, pass.data, start, lineLen, lineStride, pass.inverse); err != nil {
+			return err
 		}
-		return nil
-	})
+	}
+	return nil
 }
 
+// transformLine transforms the real and imaginary parts of one line as a
+// batch of two vectors in lineBuf.
 func (t *dctAxisTransform) transformLine(
 	plan *r2r.DCT2Plan,
-	realBuf []float64,
-	imagBuf []float64,
+	lineBuf []float64,
 	data []complex128,
 	start int,
 	length int,
@@ -308,31 +445,22 @@ func (t *dctAxisTransform) transformLine(
 ) error {
 	for i := 0; i < length; i++ {
 		v := data[start+i*stride]
-		realBuf[i] = real(v)
-		imagBuf[i] = imag(v)
+		lineBuf[i] = real(v)
+		lineBuf[length+i] = imag(v)
 	}
 
 	var err error
 	if inverse {
-		err = plan.Inverse(realBuf, realBuf)
-	} else {
-		err = plan.Forward(realBuf, realBuf)
-	}
-	if err != nil {
-		return fmt.Errorf("DCT-II real line: %w", err)
-	}
-
-	if inverse {
-		err = plan.Inverse(imagBuf, imagBuf)
+		err = plan.InverseMany(lineBuf, lineBuf, 2)
 	} else {
-		err = plan.Forward(imagBuf, imagBuf)
+		err = plan.ForwardMany(lineBuf, lineBuf, 2)
 	}
 	if err != nil {
-		return fmt.Errorf("DCT-II imag line: %w", err)
+		return fmt.Errorf("DCT-II line: %w", err)
 	}
 
 	for i := 0; i < length; i++ {
-		data[start+i*stride] = complex(realBuf[i], imagBuf[i])
+		data[start+i*stride] = complex(lineBuf[i], lineBuf[length+i])
 	}
 
 	return nil
@@ -1,5 +1,11 @@
 package poisson
 
+import (
+	"iter"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
 // BCType represents the type of boundary condition.
 type BCType int
 
@@ -63,12 +69,86 @@ const (
 	ZHigh
 )
 
+// String returns the name of the face, e.g. "XLow".
+func (f BoundaryFace) String() string {
+	switch f {
+	case XLow:
+		return "XLow"
+	case XHigh:
+		return "XHigh"
+	case YLow:
+		return "YLow"
+	case YHigh:
+		return "YHigh"
+	case ZLow:
+		return "ZLow"
+	case ZHigh:
+		return "ZHigh"
+	default:
+		return "Unknown"
+	}
+}
+
+// FaceCells returns an iterator over the grid points of shape adjacent to
+// face, yielding the row-major index of each point and its index on the
+// face: row-major over the two remaining axes, the layout of
+// BoundaryData.Values. It yields nothing for an unknown face.
+//
+//	for cell, f := range poisson.FaceCells(shape, poisson.XHigh) {
+//		rhs[cell] += values[f] * scale
+//	}
+func FaceCells(shape grid.Shape, face BoundaryFace) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		axis, ok := faceAxis(face)
+		if !ok {
+			return
+		}
+
+		stride := grid.RowMajorStride(shape)
+		base := 0
+		if highFace(face) {
+			base = (shape[axis] - 1) * stride[axis]
+		}
+
+		// a and b span the face in row-major order.
+		a, b := 1, 2
+		switch axis {
+		case 1:
+			a, b = 0, 2
+		case 2:
+			a, b = 0, 1
+		}
+
+		f := 0
+		for i := range shape[a] {
+			for j := range shape[b] {
+				if !yield(base+i*stride[a]+j*stride[b], f) {
+					return
+				}
+				f++
+			}
+		}
+	}
+}
+
+// BoundaryFunc returns the boundary value at time t for the face point idx.
+// idx indexes the face in row-major order over the two remaining axes,
+// matching the layout of BoundaryData.Values.
+type BoundaryFunc func(t float64, idx int) float64
+
 // BoundaryData associates boundary values with a face and BC type.
+//
+// Values holds fixed face data. For time-dependent problems, set Func instead;
+// it is evaluated at solve time by Plan.SolveWithBCAt. Values and Func are
+// mutually exclusive.
 type BoundaryData struct {
 	Face   BoundaryFace
 	Type   BCType
 	Values []float64
+	Func   BoundaryFunc
 }
 
-// BoundaryConditions is a collection of boundary data entries.
+// BoundaryConditions is a collection of boundary data entries, at most one
+// per face. Faces without an entry keep the homogeneous condition of the
+// plan's boundary type, so only inhomogeneous faces need to be listed.
 type BoundaryConditions []BoundaryData
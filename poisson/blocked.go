@@ -0,0 +1,142 @@
+package poisson
+
+import (
+	"time"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
+// transposeBlock is the number of adjacent lines TransformBlocked copies to
+// contiguous scratch at once: 8 complex128 values span two cache lines.
+const transposeBlock = 8
+
+// tuneRepetitions is the number of timed forward passes per strategy for
+// TransformAuto; the fastest pass counts.
+const tuneRepetitions = 3
+
+// blockable is implemented by axis transforms that support TransformBlocked.
+type blockable interface {
+	setBlocked(enabled bool)
+}
+
+// lineBlocks holds the state of the TransformBlocked strategy of one axis
+// transform.
+type lineBlocks struct {
+	enabled bool
+
+	// bufs holds transposeBlock contiguous lines per worker.
+	bufs [][]complex128
+}
+
+func (b *lineBlocks) set(enabled bool, n, workers int) {
+	if enabled && b.bufs == nil {
+		b.bufs = make([][]complex128, workers)
+		for w := range b.bufs {
+			b.bufs[w] = make([]complex128, transposeBlock*n)
+		}
+	}
+
+	b.enabled = enabled
+}
+
+// active reports whether lines with the given stride take the blocked path.
+func (b *lineBlocks) active(stride int) bool {
+	return b.enabled && stride > 1
+}
+
+// run transforms every line of length n and stride stride in data: blocks of
+// up to transposeBlock adjacent lines (consecutive start indices) are copied
+// to contiguous scratch with grid.TransposeStrided2D, lineFn transforms each
+// scratch line in place, and the block is copied back. Both copies read and write whole runs of adjacent
+// elements instead of one element per cache line. If out is non-nil, the
+// real part plus shift is stored in out instead of data.
+func (b *lineBlocks) run(
+	data []complex128, n, stride, workers int, out []float64, shift float64,
+	lineFn func(worker int, line []complex128) error,
+) error {
+	perOuter := (stride + transposeBlock - 1) / transposeBlock
+	numBlocks := len(data) / (n * stride) * perOuter
+	workers = clampWorkers(min(workers, len(b.bufs)), numBlocks)
+
+	return parallelFor(workers, numBlocks, func(worker, startBlock, endBlock int) error {
+		buf := b.bufs[worker]
+		for blk := startBlock; blk < endBlock; blk++ {
+			inner := blk % perOuter * transposeBlock
+			base := blk/perOuter*n*stride + inner
+			width := min(transposeBlock, stride-inner)
+
+			grid.TransposeStrided2D(buf, n, data[base:], stride, n, width)
+
+			for l := range width {
+				if err := lineFn(worker, buf[l*n:(l+1)*n]); err != nil {
+					return err
+				}
+			}
+
+			if out == nil {
+				grid.TransposeStrided2D(data[base:], stride, buf, n, width, n)
+				continue
+			}
+
+			for i := range n {
+				row := out[base+i*stride : base+i*stride+width]
+				for l := range row {
+					row[l] = real(buf[l*n+i]) + shift
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// applyTransformStrategy configures t, the transform along axis of shape,
+// for strategy. TransformAuto times forward transforms of scratch (a buffer
+// of shape, overwritten) with both strategies and keeps the faster one.
+// Transforms without blocked support and contiguous axes are left unchanged.
+func applyTransformStrategy(t AxisTransform, strategy TransformStrategy, scratch []complex128, shape grid.Shape, axis int) error {
+	b, ok := t.(blockable)
+	if !ok || grid.RowMajorStride(shape)[axis] == 1 {
+		return nil
+	}
+
+	switch strategy {
+	case TransformBlocked:
+		b.setBlocked(true)
+		return nil
+	case TransformAuto:
+	default:
+		return nil
+	}
+
+	var best [2]time.Duration
+	for s, blocked := range []bool{false, true} {
+		b.setBlocked(blocked)
+		for rep := range tuneRepetitions {
+			start := time.Now()
+			if err := t.Forward(scratch, shape, axis); err != nil {
+				return err
+			}
+			if d := time.Since(start); rep == 0 || d < best[s] {
+				best[s] = d
+			}
+		}
+	}
+	b.setBlocked(best[1] < best[0])
+	clear(scratch)
+
+	return nil
+}
+
+// blockedEnabled reports whether t uses the TransformBlocked strategy.
+func blockedEnabled(t AxisTransform) bool {
+	switch t := t.(type) {
+	case *fftAxisTransform:
+		return t.plan.blocks.enabled
+	case *dstAxisTransform:
+		return t.blocks.enabled
+	case *dctAxisTransform:
+		return t.blocks.enabled
+	default:
+		return false
+	}
+}
@@ -0,0 +1,96 @@
+package poisson_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const blockedTol = 1e-12
+
+func TestPlan_TransformStrategiesAgree(t *testing.T) {
+	// ny*nz = 35 and nz = 5 leave partial blocks of adjacent lines.
+	n := []int{9, 7, 5}
+	h := []float64{0.1, 0.2, 0.3}
+
+	rhs := make([]float64, n[0]*n[1]*n[2])
+	for i := range rhs {
+		rhs[i] = math.Sin(0.9*float64(i)) + 0.5
+	}
+
+	for _, bc := range [][]poisson.BCType{
+		{poisson.Periodic, poisson.Periodic, poisson.Periodic},
+		{poisson.Dirichlet, poisson.Neumann, poisson.Periodic},
+		{poisson.Neumann, poisson.Dirichlet, poisson.Dirichlet},
+	} {
+		for _, workers := range []int{1, 3} {
+			name := fmt.Sprintf("%v/%v/workers=%d", bc[0], bc[1], workers)
+			t.Run(name, func(t *testing.T) {
+				solve := func(strategy poisson.TransformStrategy, opts ...poisson.SolveOption) []float64 {
+					plan, err := poisson.NewHelmholtzPlan(3, n, h, bc, 1.5,
+						poisson.WithWorkers(workers), poisson.WithTransformStrategy(strategy))
+					if err != nil {
+						t.Fatalf("NewHelmholtzPlan: %v", err)
+					}
+
+					sol := make([]float64, len(rhs))
+					if err := plan.Solve(sol, rhs, opts...); err != nil {
+						t.Fatalf("Solve: %v", err)
+					}
+					return sol
+				}
+
+				want := solve(poisson.TransformStrided)
+				for _, strategy := range []poisson.TransformStrategy{poisson.TransformBlocked, poisson.TransformAuto} {
+					if d := maxAbsDiff(solve(strategy), want); d > blockedTol {
+						t.Fatalf("%s: max diff %g", strategy, d)
+					}
+				}
+
+				// WithVerify takes the unfused output path.
+				if d := maxAbsDiff(solve(poisson.TransformBlocked, poisson.WithVerify(1e-6)), want); d > blockedTol {
+					t.Fatalf("blocked with verify: max diff %g", d)
+				}
+			})
+		}
+	}
+}
+
+func TestPlan3DPeriodic_TransformBlocked(t *testing.T) {
+	nx, ny, nz := 6, 10, 3
+	rhs := make([]float64, nx*ny*nz)
+	for i := range rhs {
+		rhs[i] = math.Cos(0.7 * float64(i))
+	}
+
+	solve := func(strategy poisson.TransformStrategy) []float64 {
+		plan, err := poisson.NewPlan3DPeriodic(nx, ny, nz, 0.1, 0.1, 0.1,
+			poisson.WithSubtractMean(), poisson.WithTransformStrategy(strategy))
+		if err != nil {
+			t.Fatalf("NewPlan3DPeriodic: %v", err)
+		}
+
+		sol := make([]float64, len(rhs))
+		if err := plan.Solve(sol, rhs); err != nil {
+			t.Fatalf("Solve: %v", err)
+		}
+		return sol
+	}
+
+	if d := maxAbsDiff(solve(poisson.TransformBlocked), solve(poisson.TransformStrided)); d > blockedTol {
+		t.Fatalf("max diff %g", d)
+	}
+}
+
+func TestWithTransformStrategy_RejectsUnknown(t *testing.T) {
+	_, err := poisson.NewPlan(1, []int{8}, []float64{0.1}, []poisson.BCType{poisson.Dirichlet},
+		poisson.WithTransformStrategy(poisson.TransformStrategy(42)))
+
+	var verr *poisson.ValidationError
+	if !errors.As(err, &verr) || verr.Field != "TransformStrategy" {
+		t.Fatalf("got %v, want TransformStrategy ValidationError", err)
+	}
+}
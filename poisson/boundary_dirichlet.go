@@ -1,6 +1,10 @@
 package poisson
 
-import "github.com/MeKo-Tech/algo-pde/grid"
+import (
+	"fmt"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
 
 // ApplyDirichletRHS adds inhomogeneous Dirichlet boundary contributions to rhs.
 // The rhs slice is modified in-place and uses row-major ordering.
@@ -18,10 +22,6 @@ func ApplyDirichletRHS(rhs []float64, shape grid.Shape, h [3]float64, bc Boundar
 		}
 	}
 
-	dim := shape.Dim()
-	nx, ny, nz := shape[0], shape[1], shape[2]
-	plane := ny * nz
-
 	for _, data := range bc {
 		if data.Type != Dirichlet {
 			return &ValidationError{
@@ -30,89 +30,41 @@ func ApplyDirichletRHS(rhs []float64, shape grid.Shape, h [3]float64, bc Boundar
 			}
 		}
 
-		switch data.Face {
-		case XLow, XHigh:
-			if dim < 1 {
-				return &ValidationError{Field: "Face", Message: "X face not valid for this dimension"}
-			}
-			expectedFace := ny * nz
-			if len(data.Values) != expectedFace {
-				return &SizeError{
-					Expected: expectedFace,
-					Got:      len(data.Values),
-					Context:  "X face values",
-				}
-			}
-
-			invHx2 := 1.0 / (h[0] * h[0])
-			base := 0
-			if data.Face == XHigh {
-				base = (nx - 1) * plane
-			}
-			for j := 0; j < ny; j++ {
-				row := base + j*nz
-				valRow := j * nz
-				for k := 0; k < nz; k++ {
-					rhs[row+k] += data.Values[valRow+k] * invHx2
-				}
-			}
+		axis, err := checkFaceValues(data, shape)
+		if err != nil {
+			return err
+		}
 
-		case YLow, YHigh:
-			if dim < 2 {
-				return &ValidationError{Field: "Face", Message: "Y face not valid for this dimension"}
-			}
-			expectedFace := nx * nz
-			if len(data.Values) != expectedFace {
-				return &SizeError{
-					Expected: expectedFace,
-					Got:      len(data.Values),
-					Context:  "Y face values",
-				}
-			}
+		invH2 := 1.0 / (h[axis] * h[axis])
+		for cell, f := range FaceCells(shape, data.Face) {
+			rhs[cell] += data.Values[f] * invH2
+		}
+	}
 
-			invHy2 := 1.0 / (h[1] * h[1])
-			j := 0
-			if data.Face == YHigh {
-				j = ny - 1
-			}
-			for i := 0; i < nx; i++ {
-				base := i*plane + j*nz
-				valRow := i * nz
-				for k := 0; k < nz; k++ {
-					rhs[base+k] += data.Values[valRow+k] * invHy2
-				}
-			}
+	return nil
+}
 
-		case ZLow, ZHigh:
-			if dim < 3 {
-				return &ValidationError{Field: "Face", Message: "Z face not valid for this dimension"}
-			}
-			expectedFace := nx * ny
-			if len(data.Values) != expectedFace {
-				return &SizeError{
-					Expected: expectedFace,
-					Got:      len(data.Values),
-					Context:  "Z face values",
-				}
-			}
+// checkFaceValues returns the axis of data.Face, or an error if the face is
+// unknown, beyond the dimension of shape, or data.Values does not cover it.
+func checkFaceValues(data BoundaryData, shape grid.Shape) (int, error) {
+	axis, ok := faceAxis(data.Face)
+	if !ok {
+		return 0, &ValidationError{Field: "Face", Message: "unknown boundary face"}
+	}
 
-			invHz2 := 1.0 / (h[2] * h[2])
-			k := 0
-			if data.Face == ZHigh {
-				k = nz - 1
-			}
-			for i := 0; i < nx; i++ {
-				base := i * plane
-				valRow := i * ny
-				for j := 0; j < ny; j++ {
-					rhs[base+j*nz+k] += data.Values[valRow+j] * invHz2
-				}
-			}
+	name := "XYZ"[axis : axis+1]
+	if axis >= shape.Dim() {
+		return 0, &ValidationError{Field: "Face", Message: name + " face not valid for this dimension"}
+	}
 
-		default:
-			return &ValidationError{Field: "Face", Message: "unknown boundary face"}
+	expectedFace := shape[(axis+1)%3] * shape[(axis+2)%3]
+	if len(data.Values) != expectedFace {
+		return 0, &SizeError{
+			Expected: expectedFace,
+			Got:      len(data.Values),
+			Context:  fmt.Sprintf("%s face values", name),
 		}
 	}
 
-	return nil
+	return axis, nil
 }
@@ -19,10 +19,6 @@ func ApplyNeumannRHS(rhs []float64, shape grid.Shape, h [3]float64, bc BoundaryC
 		}
 	}
 
-	dim := shape.Dim()
-	nx, ny, nz := shape[0], shape[1], shape[2]
-	plane := ny * nz
-
 	for _, data := range bc {
 		if data.Type != Neumann {
 			return &ValidationError{
@@ -31,102 +27,18 @@ func ApplyNeumannRHS(rhs []float64, shape grid.Shape, h [3]float64, bc BoundaryC
 			}
 		}
 
-		switch data.Face {
-		case XLow, XHigh:
-			if dim < 1 {
-				return &ValidationError{Field: "Face", Message: "X face not valid for this dimension"}
-			}
-			expectedFace := ny * nz
-			if len(data.Values) != expectedFace {
-				return &SizeError{
-					Expected: expectedFace,
-					Got:      len(data.Values),
-					Context:  "X face values",
-				}
-			}
-
-			invHx := 1.0 / h[0]
-			scale := -invHx
-			if data.Face == XHigh {
-				scale = invHx
-			}
-
-			base := 0
-			if data.Face == XHigh {
-				base = (nx - 1) * plane
-			}
-			for j := 0; j < ny; j++ {
-				row := base + j*nz
-				valRow := j * nz
-				for k := 0; k < nz; k++ {
-					rhs[row+k] += data.Values[valRow+k] * scale
-				}
-			}
-
-		case YLow, YHigh:
-			if dim < 2 {
-				return &ValidationError{Field: "Face", Message: "Y face not valid for this dimension"}
-			}
-			expectedFace := nx * nz
-			if len(data.Values) != expectedFace {
-				return &SizeError{
-					Expected: expectedFace,
-					Got:      len(data.Values),
-					Context:  "Y face values",
-				}
-			}
-
-			invHy := 1.0 / h[1]
-			scale := -invHy
-			if data.Face == YHigh {
-				scale = invHy
-			}
-
-			j := 0
-			if data.Face == YHigh {
-				j = ny - 1
-			}
-			for i := 0; i < nx; i++ {
-				base := i*plane + j*nz
-				valRow := i * nz
-				for k := 0; k < nz; k++ {
-					rhs[base+k] += data.Values[valRow+k] * scale
-				}
-			}
-
-		case ZLow, ZHigh:
-			if dim < 3 {
-				return &ValidationError{Field: "Face", Message: "Z face not valid for this dimension"}
-			}
-			expectedFace := nx * ny
-			if len(data.Values) != expectedFace {
-				return &SizeError{
-					Expected: expectedFace,
-					Got:      len(data.Values),
-					Context:  "Z face values",
-				}
-			}
-
-			invHz := 1.0 / h[2]
-			scale := -invHz
-			if data.Face == ZHigh {
-				scale = invHz
-			}
+		axis, err := checkFaceValues(data, shape)
+		if err != nil {
+			return err
+		}
 
-			k := 0
-			if data.Face == ZHigh {
-				k = nz - 1
-			}
-			for i := 0; i < nx; i++ {
-				base := i * plane
-				valRow := i * ny
-				for j := 0; j < ny; j++ {
-					rhs[base+j*nz+k] += data.Values[valRow+j] * scale
-				}
-			}
+		scale := -1.0 / h[axis]
+		if highFace(data.Face) {
+			scale = -scale
+		}
 
-		default:
-			return &ValidationError{Field: "Face", Message: "unknown boundary face"}
+		for cell, f := range FaceCells(shape, data.Face) {
+			rhs[cell] += data.Values[f] * scale
 		}
 	}
 
@@ -0,0 +1,112 @@
+package poisson
+
+// Builder assembles a Plan step by step, as an alternative to the positional
+// constructors:
+//
+//	plan, err := poisson.NewBuilder().
+//		Dims(nx, ny).
+//		Spacing(hx, hy).
+//		BC(poisson.Dirichlet, poisson.Neumann).
+//		Alpha(2).
+//		Workers(8).
+//		Build()
+//
+// Build picks the constructor from the settings (NewHelmholtzPlan, or
+// NewAnisotropicHelmholtzPlan when Coefficients is set) and validates like
+// it. A Builder can be reused; Build does not modify it.
+type Builder struct {
+	n     []int
+	h     []float64
+	bc    []BCType
+	alpha float64
+	coeff []float64
+	opts  []Option
+}
+
+// NewBuilder returns an empty Builder: Poisson (alpha 0) with default
+// options.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Dims sets the grid size per axis; the number of sizes is the dimension.
+func (b *Builder) Dims(n ...int) *Builder {
+	b.n = append([]int(nil), n...)
+	return b
+}
+
+// Spacing sets the grid spacing per axis. A single value applies to every
+// axis.
+func (b *Builder) Spacing(h ...float64) *Builder {
+	b.h = append([]float64(nil), h...)
+	return b
+}
+
+// BC sets the boundary condition per axis. A single value applies to every
+// axis.
+func (b *Builder) BC(bc ...BCType) *Builder {
+	b.bc = append([]BCType(nil), bc...)
+	return b
+}
+
+// Alpha sets the Helmholtz shift of (alpha - Δ)u = f.
+func (b *Builder) Alpha(alpha float64) *Builder {
+	b.alpha = alpha
+	return b
+}
+
+// Coefficients sets per-axis diffusion coefficients, as for
+// NewAnisotropicHelmholtzPlan.
+func (b *Builder) Coefficients(coeff ...float64) *Builder {
+	b.coeff = append([]float64(nil), coeff...)
+	return b
+}
+
+// Workers sets the worker count (see WithWorkers).
+func (b *Builder) Workers(workers int) *Builder {
+	return b.Options(WithWorkers(workers))
+}
+
+// Options appends plan options; later options override earlier ones.
+func (b *Builder) Options(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Build creates the plan.
+func (b *Builder) Build() (*Plan, error) {
+	dim := len(b.n)
+	if dim == 0 {
+		return nil, &ValidationError{
+			Field:   "Dims",
+			Message: "must be set",
+		}
+	}
+
+	h := b.h
+	if len(h) == 1 && dim > 1 {
+		h = broadcast(h[0], dim)
+	}
+
+	bc := b.bc
+	if len(bc) == 1 && dim > 1 {
+		bc = broadcast(bc[0], dim)
+	}
+
+	opts := append([]Option(nil), b.opts...)
+	if b.coeff != nil {
+		return NewAnisotropicHelmholtzPlan(dim, b.n, h, bc, b.alpha, b.coeff, opts...)
+	}
+
+	return NewHelmholtzPlan(dim, b.n, h, bc, b.alpha, opts...)
+}
+
+// broadcast returns a slice of n copies of v.
+func broadcast[T any](v T, n int) []T {
+	s := make([]T, n)
+	for i := range s {
+		s[i] = v
+	}
+
+	return s
+}
@@ -0,0 +1,95 @@
+package poisson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestBuilder_MatchesConstructors(t *testing.T) {
+	n := []int{8, 6}
+	h := []float64{0.1, 0.2}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Neumann}
+
+	built, err := poisson.NewBuilder().
+		Dims(n...).
+		Spacing(h...).
+		BC(bc...).
+		Alpha(2).
+		Workers(1).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	want, err := poisson.NewHelmholtzPlan(2, n, h, bc, 2, poisson.WithWorkers(1))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	aniso, err := poisson.NewBuilder().Dims(n...).Spacing(h...).BC(bc...).Alpha(2).Coefficients(1, 0.5).Build()
+	if err != nil {
+		t.Fatalf("Build (anisotropic) failed: %v", err)
+	}
+
+	wantAniso, err := poisson.NewAnisotropicHelmholtzPlan(2, n, h, bc, 2, []float64{1, 0.5})
+	if err != nil {
+		t.Fatalf("NewAnisotropicHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 48)
+	for i := range rhs {
+		rhs[i] = float64(i%7) - 3
+	}
+
+	for _, pair := range [][2]*poisson.Plan{{built, want}, {aniso, wantAniso}} {
+		got := make([]float64, len(rhs))
+		ref := make([]float64, len(rhs))
+		if err := pair[0].Solve(got, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+		if err := pair[1].Solve(ref, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+		if d := maxAbsDiff(got, ref); d != 0 {
+			t.Fatalf("built plan differs from constructor by %g", d)
+		}
+	}
+}
+
+func TestBuilder_BroadcastsSingleValues(t *testing.T) {
+	b := poisson.NewBuilder().Dims(4, 4, 4).Spacing(0.25).BC(poisson.Periodic)
+
+	plan, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for axis := range 3 {
+		if got := plan.AxisEigenvalues(axis); len(got) != 4 {
+			t.Fatalf("axis %d has %d eigenvalues, want 4", axis, len(got))
+		}
+	}
+
+	// The builder is reusable and Build leaves it unchanged.
+	if _, err := b.Alpha(1).Build(); err != nil {
+		t.Fatalf("second Build failed: %v", err)
+	}
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	var vErr *poisson.ValidationError
+
+	if _, err := poisson.NewBuilder().Spacing(0.1).BC(poisson.Periodic).Build(); !errors.As(err, &vErr) || vErr.Field != "Dims" {
+		t.Fatalf("expected Dims ValidationError, got %v", err)
+	}
+
+	if _, err := poisson.NewBuilder().Dims(4, 4).Spacing(0.1, 0.1, 0.1).BC(poisson.Periodic).Build(); err == nil {
+		t.Fatal("expected an error for mismatched spacing")
+	}
+
+	if _, err := poisson.NewBuilder().Dims(4, 4).Spacing(0.1).BC(poisson.Periodic).Coefficients(1).Build(); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for coefficient count, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package poisson
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+)
+
+// Cache keeps up to a fixed number of plans, keyed by their PlanConfig
+// (shape, spacing, boundary conditions, alpha, coefficients and options),
+// and evicts the least recently used plan when full. Services that solve
+// for a changing set of setups, e.g. one Helmholtz plan per frequency,
+// reuse plans instead of rebuilding eigenvalue tables and transforms.
+//
+// Spacing and boundary conditions are compared after broadcasting, so
+// {Spacing: [0.1]} and {Spacing: [0.1, 0.1]} share a plan. Plans are
+// shared between callers of Plan and, like any Plan, must not be used
+// concurrently; the Cache itself is safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List // front is most recently used
+	stats    CacheStats
+}
+
+// CacheStats counts cache lookups.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+type cacheEntry struct {
+	key  string
+	plan *Plan
+}
+
+// NewCache returns a cache holding at most capacity plans.
+func NewCache(capacity int) (*Cache, error) {
+	if capacity < 1 {
+		return nil, &ValidationError{
+			Field:   "capacity",
+			Message: "must be positive",
+		}
+	}
+
+	return &Cache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}, nil
+}
+
+// Plan returns the cached plan for cfg, creating it with NewPlanFromConfig
+// on a miss. Invalid configurations return the validation error and are
+// not counted.
+func (c *Cache) Plan(cfg PlanConfig) (*Plan, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	key, err := cacheKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.stats.Hits++
+		c.lru.MoveToFront(elem)
+
+		return elem.Value.(*cacheEntry).plan, nil
+	}
+
+	plan, err := NewPlanFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.stats.Misses++
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, plan: plan})
+
+	for c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+
+	return plan, nil
+}
+
+// Contains reports whether a plan for cfg is cached, without counting a
+// lookup or changing the eviction order.
+func (c *Cache) Contains(cfg PlanConfig) bool {
+	if cfg.Validate() != nil {
+		return false
+	}
+
+	key, err := cacheKey(cfg)
+	if err != nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.entries[key]
+
+	return ok
+}
+
+// Len returns the number of cached plans.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// Stats returns the lookup counters.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// Clear drops all cached plans. The counters are kept.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.lru.Init()
+}
+
+// cacheKey encodes a validated cfg with spacing and boundary conditions
+// broadcast to every axis.
+func cacheKey(cfg PlanConfig) (string, error) {
+	dim := len(cfg.Dims)
+	if len(cfg.Spacing) == 1 {
+		cfg.Spacing = broadcast(cfg.Spacing[0], dim)
+	}
+	if len(cfg.BC) == 1 {
+		cfg.BC = broadcast(cfg.BC[0], dim)
+	}
+
+	key, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	return string(key), nil
+}
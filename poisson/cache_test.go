@@ -0,0 +1,140 @@
+package poisson_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func helmholtzConfig(alpha float64) poisson.PlanConfig {
+	return poisson.PlanConfig{
+		Dims:    []int{8, 6},
+		Spacing: []float64{0.1},
+		BC:      []poisson.BCType{poisson.Dirichlet, poisson.Neumann},
+		Alpha:   alpha,
+	}
+}
+
+func TestCache_HitsMissesAndEviction(t *testing.T) {
+	cache, err := poisson.NewCache(2)
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	p1, err := cache.Plan(helmholtzConfig(1))
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	// Broadcast and explicit spacing describe the same plan.
+	explicit := helmholtzConfig(1)
+	explicit.Spacing = []float64{0.1, 0.1}
+	again, err := cache.Plan(explicit)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if again != p1 {
+		t.Error("equivalent config did not hit the cache")
+	}
+
+	if _, err := cache.Plan(helmholtzConfig(2)); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	// Touch alpha=1 so alpha=2 is least recently used, then overflow.
+	if _, err := cache.Plan(helmholtzConfig(1)); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if _, err := cache.Plan(helmholtzConfig(3)); err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if !cache.Contains(helmholtzConfig(1)) || cache.Contains(helmholtzConfig(2)) || !cache.Contains(helmholtzConfig(3)) {
+		t.Error("least recently used plan was not the one evicted")
+	}
+
+	want := poisson.CacheStats{Hits: 2, Misses: 3, Evictions: 1}
+	if got := cache.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+
+	cache.Clear()
+	if cache.Len() != 0 || cache.Contains(helmholtzConfig(1)) {
+		t.Error("Clear left plans in the cache")
+	}
+}
+
+func TestCache_DistinguishesOptions(t *testing.T) {
+	cache, err := poisson.NewCache(4)
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	base := helmholtzConfig(0)
+	base.BC = []poisson.BCType{poisson.Periodic}
+	subtract := base
+	subtract.Nullspace = poisson.NullspaceSubtractMean
+
+	a, err := cache.Plan(base)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	b, err := cache.Plan(subtract)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if a == b {
+		t.Error("configs with different options share a plan")
+	}
+}
+
+func TestCache_InvalidConfig(t *testing.T) {
+	if _, err := poisson.NewCache(0); err == nil {
+		t.Error("NewCache(0) succeeded")
+	}
+
+	cache, err := poisson.NewCache(1)
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	bad := helmholtzConfig(1)
+	bad.Dims = nil
+	var verr *poisson.ValidationError
+	if _, err := cache.Plan(bad); !errors.As(err, &verr) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+	if got := cache.Stats(); got != (poisson.CacheStats{}) {
+		t.Errorf("invalid config counted: %+v", got)
+	}
+}
+
+func TestCache_ConcurrentLookups(t *testing.T) {
+	cache, err := poisson.NewCache(2)
+	if err != nil {
+		t.Fatalf("NewCache failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 6 {
+				if _, err := cache.Plan(helmholtzConfig(float64((g + i) % 3))); err != nil {
+					t.Errorf("Plan failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := cache.Stats(); stats.Hits+stats.Misses != 24 {
+		t.Errorf("lookups = %d, want 24", stats.Hits+stats.Misses)
+	}
+}
@@ -0,0 +1,200 @@
+package poisson
+
+import (
+	"fmt"
+	"math"
+)
+
+// Stage is one spectral operation of a Pipeline: a multiplier applied to
+// every mode in the transform basis of the pipeline's grid. *Plan is a Stage
+// (its solve), as are FilterStage and DerivativeStage.
+type Stage interface {
+	// stageSymbol returns the multiplier of mode idx (row-major spectral
+	// index) on the grid of base.
+	stageSymbol(base *Plan, idx int) (complex128, error)
+}
+
+// Pipeline applies a sequence of spectral operations that are all diagonal
+// in one transform basis with a single forward/inverse transform pair: the
+// stage symbols are multiplied once, at Chain time, into one table.
+//
+// A Pipeline shares the transforms and workspace of its first plan; it must
+// not be used concurrently with that plan.
+type Pipeline struct {
+	plan   *Plan
+	symbol []complex128
+}
+
+// Chain composes stages into a Pipeline. The result of Apply equals running
+// the stages one after another, e.g. Chain(a, b) applies the solve of plan a
+// and then the solve of plan b, but transforms only once.
+//
+// The first *Plan among stages defines the grid; every other plan must have
+// the same dimensions, sizes, spacings and boundary conditions. A plan stage
+// contributes its inverse symbol 1/(alpha + λ) including its spectral filter
+// and resonance handling; resonant modes fail here rather than in Apply.
+// Plans with a nullspace drop the zero mode, i.e. the pipeline behaves as if
+// the RHS mean were subtracted and the solution had zero mean.
+func Chain(stages ...Stage) (*Pipeline, error) {
+	var base *Plan
+	for _, s := range stages {
+		if p, ok := s.(*Plan); ok {
+			base = p
+			break
+		}
+	}
+
+	if base == nil {
+		return nil, &ValidationError{
+			Field:   "stages",
+			Message: "must contain at least one *Plan",
+		}
+	}
+
+	for i, s := range stages {
+		switch s := s.(type) {
+		case *Plan:
+			if s.dim != base.dim || s.n != base.n || s.h != base.h || s.bc != base.bc {
+				return nil, &ValidationError{
+					Field:   fmt.Sprintf("stages[%d]", i),
+					Message: "plan grid does not match the first plan",
+				}
+			}
+		case derivativeStage:
+			if s.axis < 0 || s.axis >= base.dim || base.bc[s.axis] != Periodic {
+				return nil, &ValidationError{
+					Field:   fmt.Sprintf("stages[%d]", i),
+					Message: "derivative needs a periodic axis of the grid",
+				}
+			}
+		case nil:
+			return nil, &ValidationError{
+				Field:   fmt.Sprintf("stages[%d]", i),
+				Message: "must not be nil",
+			}
+		}
+	}
+
+	size := base.size()
+	symbol := make([]complex128, size)
+	for idx := range symbol {
+		m := complex(1, 0)
+		for _, s := range stages {
+			v, err := s.stageSymbol(base, idx)
+			if err != nil {
+				return nil, err
+			}
+			m *= v
+		}
+		symbol[idx] = m
+	}
+
+	return &Pipeline{plan: base, symbol: symbol}, nil
+}
+
+// Apply runs the pipeline on src and stores the result in dst. dst and src
+// may alias.
+func (c *Pipeline) Apply(dst, src []float64) error {
+	if dst == nil || src == nil {
+		return ErrNilBuffer
+	}
+
+	p := c.plan
+	size := p.size()
+	if err := checkBuffers("Pipeline.Apply", size, dst, src, "src"); err != nil {
+		return err
+	}
+
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
+	for i, v := range src {
+		p.work.Complex[i] = complex(v, 0)
+	}
+
+	if err := p.forwardAll(); err != nil {
+		return err
+	}
+
+	workers := clampWorkers(p.opts.Workers, size)
+	if err := parallelFor(workers, size, func(_ int, start, end int) error {
+		for idx := start; idx < end; idx++ {
+			p.work.Complex[idx] *= c.symbol[idx]
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return p.inverseAll(dst, 0)
+}
+
+func (p *Plan) stageSymbol(_ *Plan, idx int) (complex128, error) {
+	inv, err := p.modeInverse(idx, p.opts.SpectralFilter)
+	if err != nil {
+		return 0, err
+	}
+
+	return complex(inv, 0), nil
+}
+
+type filterStage struct {
+	filter FilterFunc
+}
+
+// FilterStage returns a Stage that multiplies every mode by filter, as
+// WithFilter does for a single solve.
+func FilterStage(filter FilterFunc) Stage {
+	return filterStage{filter: filter}
+}
+
+func (s filterStage) stageSymbol(base *Plan, idx int) (complex128, error) {
+	ny, nz := base.n[1], base.n[2]
+	eta := [3]float64{base.eta[0][idx/(ny*nz)]}
+	if base.dim > 1 {
+		eta[1] = base.eta[1][idx/nz%ny]
+	}
+	if base.dim > 2 {
+		eta[2] = base.eta[2][idx%nz]
+	}
+
+	return complex(s.filter(eta), 0), nil
+}
+
+type derivativeStage struct {
+	axis int
+}
+
+// DerivativeStage returns a Stage computing the spectral first derivative
+// d/dx_axis, with symbol i*k for wavenumber k and the Nyquist mode set to
+// zero (as in the spectral package). The axis must be periodic: on
+// Dirichlet and Neumann axes the derivative maps sines to cosines and is not
+// diagonal in the solver basis. Use one pipeline per gradient component.
+func DerivativeStage(axis int) Stage {
+	return derivativeStage{axis: axis}
+}
+
+func (s derivativeStage) stageSymbol(base *Plan, idx int) (complex128, error) {
+	n := base.n[s.axis]
+	m := idx / base.stride(s.axis) % n
+	if 2*m == n {
+		return 0, nil
+	}
+	if 2*m > n {
+		m -= n
+	}
+
+	k := 2 * math.Pi * float64(m) / (float64(n) * base.h[s.axis])
+
+	return complex(0, k), nil
+}
+
+// stride returns the row-major stride of axis in the plan's workspace.
+func (p *Plan) stride(axis int) int {
+	s := 1
+	for a := 2; a > axis; a-- {
+		s *= p.n[a]
+	}
+
+	return s
+}
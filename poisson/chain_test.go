@@ -0,0 +1,154 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const chainTol = 1e-12
+
+func TestChain_MatchesSequentialSolves(t *testing.T) {
+	n := []int{12, 10}
+	h := []float64{0.1, 0.15}
+
+	for _, bc := range [][]poisson.BCType{
+		{poisson.Periodic, poisson.Periodic},
+		{poisson.Dirichlet, poisson.Neumann},
+	} {
+		t.Run(bc[0].String()+"/"+bc[1].String(), func(t *testing.T) {
+			a, err := poisson.NewHelmholtzPlan(2, n, h, bc, 2)
+			if err != nil {
+				t.Fatalf("plan a: %v", err)
+			}
+			b, err := poisson.NewHyperdiffusionPlan(2, n, h, bc, 0.001, 2, poisson.WithSpectralFilter(poisson.TwoThirdsFilter()))
+			if err != nil {
+				t.Fatalf("plan b: %v", err)
+			}
+
+			src := make([]float64, n[0]*n[1])
+			for i := range src {
+				src[i] = math.Sin(0.4*float64(i)) + 0.3
+			}
+
+			want := make([]float64, len(src))
+			if err := a.Solve(want, src); err != nil {
+				t.Fatalf("Solve a: %v", err)
+			}
+			if err := b.SolveInPlace(want); err != nil {
+				t.Fatalf("Solve b: %v", err)
+			}
+
+			pipe, err := poisson.Chain(a, b)
+			if err != nil {
+				t.Fatalf("Chain: %v", err)
+			}
+
+			got := append([]float64(nil), src...)
+			if err := pipe.Apply(got, got); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			if d := maxAbsDiff(got, want); d > chainTol {
+				t.Fatalf("max diff %g", d)
+			}
+		})
+	}
+}
+
+func TestChain_FilterStageMatchesWithFilter(t *testing.T) {
+	n := []int{16}
+	plan, err := poisson.NewHelmholtzPlan(1, n, []float64{0.1}, []poisson.BCType{poisson.Dirichlet}, 1)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan: %v", err)
+	}
+
+	src := make([]float64, n[0])
+	for i := range src {
+		src[i] = float64(i%5) - 2
+	}
+
+	filter := poisson.ExponentialFilter(36, 8)
+	want := make([]float64, len(src))
+	if err := plan.Solve(want, src, poisson.WithFilter(filter)); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+
+	pipe, err := poisson.Chain(plan, poisson.FilterStage(filter))
+	if err != nil {
+		t.Fatalf("Chain: %v", err)
+	}
+
+	got := make([]float64, len(src))
+	if err := pipe.Apply(got, src); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if d := maxAbsDiff(got, want); d > chainTol {
+		t.Fatalf("max diff %g", d)
+	}
+}
+
+func TestChain_SolveThenDerivative(t *testing.T) {
+	nx, ny := 32, 8
+	hx, hy := 1.0/float64(nx), 1.0/float64(ny)
+	alpha := 3.0
+
+	plan, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, []float64{hx, hy},
+		[]poisson.BCType{poisson.Periodic, poisson.Periodic}, alpha)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan: %v", err)
+	}
+
+	pipe, err := poisson.Chain(plan, poisson.DerivativeStage(0))
+	if err != nil {
+		t.Fatalf("Chain: %v", err)
+	}
+
+	// f = sin(2πx): u = f/(alpha + λ), du/dx = 2π cos(2πx)/(alpha + λ).
+	lambda := (2 - 2*math.Cos(2*math.Pi*hx)) / (hx * hx)
+	src := make([]float64, nx*ny)
+	want := make([]float64, nx*ny)
+	for i := range nx {
+		x := float64(i) * hx
+		for j := range ny {
+			src[i*ny+j] = math.Sin(2 * math.Pi * x)
+			want[i*ny+j] = 2 * math.Pi * math.Cos(2*math.Pi*x) / (alpha + lambda)
+		}
+	}
+
+	got := make([]float64, nx*ny)
+	if err := pipe.Apply(got, src); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if d := maxAbsDiff(got, want); d > chainTol {
+		t.Fatalf("max diff %g", d)
+	}
+}
+
+func TestChain_Validation(t *testing.T) {
+	dirichlet, err := poisson.NewPlan(1, []int{8}, []float64{0.1}, []poisson.BCType{poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan: %v", err)
+	}
+	other, err := poisson.NewPlan(1, []int{9}, []float64{0.1}, []poisson.BCType{poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan: %v", err)
+	}
+
+	var verr *poisson.ValidationError
+	for name, stages := range map[string][]poisson.Stage{
+		"no plan":             {poisson.FilterStage(poisson.TwoThirdsFilter())},
+		"grid mismatch":       {dirichlet, other},
+		"non-periodic d/dx":   {dirichlet, poisson.DerivativeStage(0)},
+		"derivative bad axis": {dirichlet, poisson.DerivativeStage(1)},
+		"nil stage":           {dirichlet, nil},
+	} {
+		if _, err := poisson.Chain(stages...); !errors.As(err, &verr) {
+			t.Errorf("%s: got %v, want ValidationError", name, err)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package poisson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// PlanConfig is a serializable description of a Plan, so that services and
+// command-line tools can keep solver setups in configuration files:
+//
+//	{"dims": [128, 64], "spacing": [0.01], "bc": ["dirichlet", "neumann"],
+//	 "alpha": 2, "nullspace": "subtract_mean", "workers": 4}
+//
+// Enumerations are written by name (see BCType.MarshalText). The struct
+// carries json and yaml tags and its enums implement encoding.TextUnmarshaler,
+// so YAML decoders that honour both read the same documents.
+//
+// Options that hold Go values (spectral filters, workspaces, divide kernels)
+// have no config field; pass them to NewPlanFromConfig directly.
+type PlanConfig struct {
+	// Dims is the grid size per axis; its length is the dimension (1-3).
+	Dims []int `json:"dims" yaml:"dims"`
+
+	// Spacing is the grid spacing per axis. A single value applies to
+	// every axis.
+	Spacing []float64 `json:"spacing" yaml:"spacing"`
+
+	// BC is the boundary condition per axis. A single value applies to
+	// every axis.
+	BC []BCType `json:"bc" yaml:"bc"`
+
+	// Alpha is the Helmholtz shift of (alpha - Δ)u = f; 0 is Poisson.
+	Alpha float64 `json:"alpha,omitempty" yaml:"alpha,omitempty"`
+
+	// Coefficients are optional per-axis diffusion coefficients, as for
+	// NewAnisotropicHelmholtzPlan.
+	Coefficients []float64 `json:"coefficients,omitempty" yaml:"coefficients,omitempty"`
+
+	Workers            int               `json:"workers,omitempty" yaml:"workers,omitempty"`
+	Nullspace          NullspaceHandling `json:"nullspace,omitempty" yaml:"nullspace,omitempty"`
+	SolutionMean       *float64          `json:"solution_mean,omitempty" yaml:"solution_mean,omitempty"`
+	RealFFT            bool              `json:"real_fft,omitempty" yaml:"real_fft,omitempty"`
+	RealFFTPrecision   RealFFTPrecision  `json:"real_fft_precision,omitempty" yaml:"real_fft_precision,omitempty"`
+	InPlace            bool              `json:"in_place,omitempty" yaml:"in_place,omitempty"`
+	Resonance          ResonanceHandling `json:"resonance,omitempty" yaml:"resonance,omitempty"`
+	ResonanceTolerance float64           `json:"resonance_tolerance,omitempty" yaml:"resonance_tolerance,omitempty"`
+	Tikhonov           float64           `json:"tikhonov,omitempty" yaml:"tikhonov,omitempty"`
+	FluxBalance        bool              `json:"flux_balance,omitempty" yaml:"flux_balance,omitempty"`
+	PrecomputedInverse bool              `json:"precomputed_inverse,omitempty" yaml:"precomputed_inverse,omitempty"`
+	PooledWorkspace    bool              `json:"pooled_workspace,omitempty" yaml:"pooled_workspace,omitempty"`
+	TransformStrategy  TransformStrategy `json:"transform_strategy,omitempty" yaml:"transform_strategy,omitempty"`
+	CheckFinite        bool              `json:"check_finite,omitempty" yaml:"check_finite,omitempty"`
+	Stencil            Stencil           `json:"stencil,omitempty" yaml:"stencil,omitempty"`
+}
+
+// LoadPlanConfig decodes a JSON PlanConfig from r and validates it. Unknown
+// fields are rejected, so typos in config files do not pass silently.
+func LoadPlanConfig(r io.Reader) (PlanConfig, error) {
+	var cfg PlanConfig
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return PlanConfig{}, fmt.Errorf("poisson: decode plan config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return PlanConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// NewPlanFromConfig validates cfg and creates its plan. opts are applied
+// after the options of cfg, so they can add or override settings.
+func NewPlanFromConfig(cfg PlanConfig, opts ...Option) (*Plan, error) {
+	if err := cfg.Validate(opts...); err != nil {
+		return nil, err
+	}
+
+	b := NewBuilder().
+		Dims(cfg.Dims...).
+		Spacing(cfg.Spacing...).
+		BC(cfg.BC...).
+		Alpha(cfg.Alpha).
+		Options(cfg.Options()...).
+		Options(opts...)
+	if cfg.Coefficients != nil {
+		b.Coefficients(cfg.Coefficients...)
+	}
+
+	return b.Build()
+}
+
+// Validate checks cfg, together with any extra options, without allocating
+// a plan: the grid description, the coefficients and the option values and
+// combinations that the plan constructors would reject.
+func (c PlanConfig) Validate(opts ...Option) error {
+	dim := len(c.Dims)
+	if dim < 1 || dim > 3 {
+		return &ValidationError{
+			Field:   "dims",
+			Message: "must list 1, 2, or 3 sizes",
+		}
+	}
+
+	if len(c.Spacing) != 1 && len(c.Spacing) != dim {
+		return &ValidationError{
+			Field:   "spacing",
+			Message: "must have one value or one per axis",
+		}
+	}
+
+	if len(c.BC) != 1 && len(c.BC) != dim {
+		return &ValidationError{
+			Field:   "bc",
+			Message: "must have one value or one per axis",
+		}
+	}
+
+	for axis, n := range c.Dims {
+		if n < 1 {
+			return &ValidationError{
+				Field:   fmt.Sprintf("dims[%d]", axis),
+				Message: "must be positive",
+			}
+		}
+	}
+
+	for axis, h := range c.Spacing {
+		if !(h > 0) || math.IsInf(h, 0) {
+			return &ValidationError{
+				Field:   fmt.Sprintf("spacing[%d]", axis),
+				Message: "must be positive and finite",
+			}
+		}
+	}
+
+	for axis, bc := range c.BC {
+		if _, err := bc.MarshalText(); err != nil {
+			return &ValidationError{
+				Field:   fmt.Sprintf("bc[%d]", axis),
+				Message: "unsupported boundary condition",
+			}
+		}
+	}
+
+	if math.IsNaN(c.Alpha) || math.IsInf(c.Alpha, 0) {
+		return &ValidationError{
+			Field:   "alpha",
+			Message: "must be finite",
+		}
+	}
+
+	if c.Coefficients != nil && len(c.Coefficients) != dim {
+		return &ValidationError{
+			Field:   "coefficients",
+			Message: "length must match dims",
+		}
+	}
+
+	for axis, coeff := range c.Coefficients {
+		if coeff < 0 || math.IsNaN(coeff) || math.IsInf(coeff, 0) {
+			return &ValidationError{
+				Field:   fmt.Sprintf("coefficients[%d]", axis),
+				Message: "must be finite and non-negative",
+			}
+		}
+	}
+
+	_, err := ApplyOptions(DefaultOptions(), append(c.Options(), opts...))
+
+	return err
+}
+
+// Options returns the plan options described by cfg. Settings at their zero
+// value are omitted, leaving the defaults in place.
+func (c PlanConfig) Options() []Option {
+	var opts []Option
+	if c.Workers != 0 {
+		opts = append(opts, WithWorkers(c.Workers))
+	}
+	if c.Nullspace != NullspaceZeroMode {
+		opts = append(opts, WithNullspace(c.Nullspace))
+	}
+	if c.SolutionMean != nil {
+		opts = append(opts, WithSolutionMean(*c.SolutionMean))
+	}
+	if c.RealFFT {
+		opts = append(opts, WithRealFFT(true))
+	}
+	if c.RealFFTPrecision != RealFFTFloat32 {
+		opts = append(opts, WithRealFFTPrecision(c.RealFFTPrecision))
+	}
+	if c.InPlace {
+		opts = append(opts, WithInPlace(true))
+	}
+	if c.Resonance != ResonanceError {
+		opts = append(opts, WithResonance(c.Resonance))
+	}
+	if c.ResonanceTolerance != 0 {
+		opts = append(opts, WithResonanceTolerance(c.ResonanceTolerance))
+	}
+	if c.Tikhonov != 0 {
+		opts = append(opts, WithTikhonov(c.Tikhonov))
+	}
+	if c.FluxBalance {
+		opts = append(opts, WithFluxBalance())
+	}
+	if c.PrecomputedInverse {
+		opts = append(opts, WithPrecomputedInverse())
+	}
+	if c.PooledWorkspace {
+		opts = append(opts, WithPooledWorkspace())
+	}
+	if c.TransformStrategy != TransformStrided {
+		opts = append(opts, WithTransformStrategy(c.TransformStrategy))
+	}
+	if c.CheckFinite {
+		opts = append(opts, WithCheckFinite())
+	}
+	if c.Stencil != StandardStencil {
+		opts = append(opts, WithStencil(c.Stencil))
+	}
+
+	return opts
+}
+
+// Text names of the enumerations in configuration files. Decoding also
+// accepts the String() names, and ignores case.
+var (
+	bcTypeNames            = []string{Periodic: "periodic", Dirichlet: "dirichlet", Neumann: "neumann"}
+	nullspaceNames         = []string{NullspaceZeroMode: "zero_mode", NullspaceSubtractMean: "subtract_mean", NullspaceError: "error"}
+	resonanceNames         = []string{ResonanceError: "error", ResonancePseudoInverse: "pseudo_inverse", ResonanceTikhonov: "tikhonov"}
+	transformStrategyNames = []string{TransformStrided: "strided", TransformBlocked: "blocked", TransformAuto: "auto"}
+	realFFTPrecisionNames  = []string{RealFFTFloat32: "float32", RealFFTFloat64: "float64"}
+	stencilNames           = []string{StandardStencil: "standard", Isotropic9: "isotropic9", Isotropic27: "isotropic27"}
+)
+
+// MarshalText implements encoding.TextMarshaler ("periodic", "dirichlet",
+// "neumann").
+func (bc BCType) MarshalText() ([]byte, error) {
+	return marshalEnum("boundary condition", int(bc), bcTypeNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (bc *BCType) UnmarshalText(text []byte) error {
+	return unmarshalEnum((*int)(bc), "boundary condition", text, bcTypeNames, func(i int) string { return BCType(i).String() })
+}
+
+// MarshalText implements encoding.TextMarshaler ("zero_mode",
+// "subtract_mean", "error").
+func (h NullspaceHandling) MarshalText() ([]byte, error) {
+	return marshalEnum("nullspace handling", int(h), nullspaceNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (h *NullspaceHandling) UnmarshalText(text []byte) error {
+	return unmarshalEnum((*int)(h), "nullspace handling", text, nullspaceNames,
+		func(i int) string { return NullspaceHandling(i).String() })
+}
+
+// MarshalText implements encoding.TextMarshaler ("error", "pseudo_inverse",
+// "tikhonov").
+func (h ResonanceHandling) MarshalText() ([]byte, error) {
+	return marshalEnum("resonance handling", int(h), resonanceNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (h *ResonanceHandling) UnmarshalText(text []byte) error {
+	return unmarshalEnum((*int)(h), "resonance handling", text, resonanceNames,
+		func(i int) string { return ResonanceHandling(i).String() })
+}
+
+// MarshalText implements encoding.TextMarshaler ("strided", "blocked",
+// "auto").
+func (s TransformStrategy) MarshalText() ([]byte, error) {
+	return marshalEnum("transform strategy", int(s), transformStrategyNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *TransformStrategy) UnmarshalText(text []byte) error {
+	return unmarshalEnum((*int)(s), "transform strategy", text, transformStrategyNames,
+		func(i int) string { return TransformStrategy(i).String() })
+}
+
+// MarshalText implements encoding.TextMarshaler ("float32", "float64").
+func (p RealFFTPrecision) MarshalText() ([]byte, error) {
+	return marshalEnum("real FFT precision", int(p), realFFTPrecisionNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *RealFFTPrecision) UnmarshalText(text []byte) error {
+	return unmarshalEnum((*int)(p), "real FFT precision", text, realFFTPrecisionNames,
+		func(i int) string { return RealFFTPrecision(i).String() })
+}
+
+// MarshalText implements encoding.TextMarshaler ("standard", "isotropic9",
+// "isotropic27").
+func (s Stencil) MarshalText() ([]byte, error) {
+	return marshalEnum("stencil", int(s), stencilNames)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Stencil) UnmarshalText(text []byte) error {
+	return unmarshalEnum((*int)(s), "stencil", text, stencilNames,
+		func(i int) string { return Stencil(i).String() })
+}
+
+func marshalEnum(kind string, value int, names []string) ([]byte, error) {
+	if value < 0 || value >= len(names) {
+		return nil, fmt.Errorf("poisson: unknown %s %d", kind, value)
+	}
+
+	return []byte(names[value]), nil
+}
+
+func unmarshalEnum(dst *int, kind string, text []byte, names []string, goName func(int) string) error {
+	s := string(text)
+	for i, name := range names {
+		if strings.EqualFold(s, name) || strings.EqualFold(s, goName(i)) {
+			*dst = i
+			return nil
+		}
+	}
+
+	return &ValidationError{
+		Field:   kind,
+		Message: fmt.Sprintf("unknown name %q (want one of %s)", s, strings.Join(names, ", ")),
+	}
+}
@@ -0,0 +1,161 @@
+package poisson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestLoadPlanConfig_MatchesConstructor(t *testing.T) {
+	cfg, err := poisson.LoadPlanConfig(strings.NewReader(`{
+		"dims": [8, 6],
+		"spacing": [0.1],
+		"bc": ["dirichlet", "Neumann"],
+		"alpha": 2,
+		"nullspace": "subtract_mean",
+		"workers": 1
+	}`))
+	if err != nil {
+		t.Fatalf("LoadPlanConfig failed: %v", err)
+	}
+
+	fromConfig, err := poisson.NewPlanFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewPlanFromConfig failed: %v", err)
+	}
+
+	direct, err := poisson.NewHelmholtzPlan(2, []int{8, 6}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann}, 2,
+		poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithWorkers(1))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 48)
+	for i := range rhs {
+		rhs[i] = float64(i%5) - 1
+	}
+	got := make([]float64, 48)
+	want := make([]float64, 48)
+
+	if err := fromConfig.Solve(got, rhs); err != nil {
+		t.Fatalf("config plan Solve failed: %v", err)
+	}
+	if err := direct.Solve(want, rhs); err != nil {
+		t.Fatalf("direct plan Solve failed: %v", err)
+	}
+
+	if diff := maxAbsDiff(got, want); diff != 0 {
+		t.Errorf("config plan differs from constructor by %g", diff)
+	}
+}
+
+func TestPlanConfig_JSONRoundTrip(t *testing.T) {
+	mean := 1.5
+	cfg := poisson.PlanConfig{
+		Dims:              []int{4, 4, 4},
+		Spacing:           []float64{0.1, 0.2, 0.3},
+		BC:                []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann},
+		Coefficients:      []float64{1, 2, 3},
+		Nullspace:         poisson.NullspaceSubtractMean,
+		SolutionMean:      &mean,
+		Tikhonov:          0.1,
+		RealFFTPrecision:  poisson.RealFFTFloat64,
+		TransformStrategy: poisson.TransformBlocked,
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"bc":["periodic","dirichlet","neumann"]`) {
+		t.Errorf("boundary conditions not encoded by name: %s", data)
+	}
+
+	got, err := poisson.LoadPlanConfig(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("LoadPlanConfig failed: %v", err)
+	}
+
+	if got.BC[2] != poisson.Neumann || got.Nullspace != poisson.NullspaceSubtractMean ||
+		got.TransformStrategy != poisson.TransformBlocked || *got.SolutionMean != mean ||
+		got.Coefficients[1] != 2 || got.Tikhonov != 0.1 || got.RealFFTPrecision != poisson.RealFFTFloat64 {
+		t.Errorf("round trip changed config: %+v", got)
+	}
+
+	if _, err := poisson.NewPlanFromConfig(got); err != nil {
+		t.Errorf("NewPlanFromConfig failed: %v", err)
+	}
+}
+
+func TestPlanConfig_ValidateBeforeAllocation(t *testing.T) {
+	valid := func() poisson.PlanConfig {
+		return poisson.PlanConfig{
+			Dims:    []int{8, 8},
+			Spacing: []float64{0.1},
+			BC:      []poisson.BCType{poisson.Periodic},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		modify func(*poisson.PlanConfig)
+		field  string
+	}{
+		{"no dims", func(c *poisson.PlanConfig) { c.Dims = nil }, "dims"},
+		{"four dims", func(c *poisson.PlanConfig) { c.Dims = []int{2, 2, 2, 2} }, "dims"},
+		{"zero size", func(c *poisson.PlanConfig) { c.Dims[1] = 0 }, "dims[1]"},
+		{"spacing count", func(c *poisson.PlanConfig) { c.Spacing = []float64{0.1, 0.1, 0.1} }, "spacing"},
+		{"negative spacing", func(c *poisson.PlanConfig) { c.Spacing[0] = -1 }, "spacing[0]"},
+		{"bc count", func(c *poisson.PlanConfig) { c.BC = nil }, "bc"},
+		{"unknown bc", func(c *poisson.PlanConfig) { c.BC[0] = poisson.BCType(7) }, "bc[0]"},
+		{"coefficient count", func(c *poisson.PlanConfig) { c.Coefficients = []float64{1} }, "coefficients"},
+		{"negative coefficient", func(c *poisson.PlanConfig) { c.Coefficients = []float64{1, -1} }, "coefficients[1]"},
+		{"negative workers", func(c *poisson.PlanConfig) { c.Workers = -2 }, "Workers"},
+		{"option conflict", func(c *poisson.PlanConfig) {
+			mean := 1.0
+			c.Nullspace = poisson.NullspaceError
+			c.SolutionMean = &mean
+		}, "SolutionMean"},
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+
+	for _, tc := range cases {
+		cfg := valid()
+		tc.modify(&cfg)
+
+		err := cfg.Validate()
+		var verr *poisson.ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: expected ValidationError, got %v", tc.name, err)
+			continue
+		}
+		if verr.Field != tc.field {
+			t.Errorf("%s: field = %q, want %q", tc.name, verr.Field, tc.field)
+		}
+
+		if _, err := poisson.NewPlanFromConfig(cfg); err == nil {
+			t.Errorf("%s: NewPlanFromConfig accepted invalid config", tc.name)
+		}
+	}
+}
+
+func TestLoadPlanConfig_RejectsUnknownInput(t *testing.T) {
+	inputs := map[string]string{
+		"unknown field": `{"dims": [8], "spacing": [1], "bc": ["periodic"], "worker": 2}`,
+		"unknown bc":    `{"dims": [8], "spacing": [1], "bc": ["robin"]}`,
+		"invalid":       `{"dims": [8], "spacing": [1], "bc": ["periodic"], "nullspace": "error", "solution_mean": 1}`,
+	}
+
+	for name, input := range inputs {
+		if _, err := poisson.LoadPlanConfig(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: LoadPlanConfig accepted %s", name, input)
+		}
+	}
+}
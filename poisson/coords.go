@@ -0,0 +1,100 @@
+package poisson
+
+import (
+	"math"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
+// Coordinates returns the positions of the n grid points along an axis with
+// spacing h and boundary condition bc, measured from the lower domain edge:
+//
+//   - Periodic: x_i = i*h on [0, n*h)
+//   - Dirichlet: interior points x_i = (i+1)*h of [0, (n+1)*h]
+//   - Neumann: cell centers x_i = (i+0.5)*h of [0, n*h]
+//
+// These are the points at which the plans expect right-hand sides and
+// return solutions; see grid.Coordinates.
+func Coordinates(n int, h float64, bc BCType) []float64 {
+	return grid.Coordinates(n, h, grid.HaloBC(bc))
+}
+
+// Coordinates returns the grid point positions along axis, or nil for an
+// invalid axis. See the package-level Coordinates for the conventions.
+func (p *Plan) Coordinates(axis int) []float64 {
+	if axis < 0 || axis >= p.dim {
+		return nil
+	}
+
+	return Coordinates(p.n[axis], p.h[axis], p.bc[axis])
+}
+
+// Sample fills dst (row-major, length nx*ny*nz) with f evaluated at every
+// grid point of the plan. Unused trailing coordinates of 1D and 2D plans
+// are passed as 0.
+func (p *Plan) Sample(dst []float64, f func(x, y, z float64) float64) error {
+	if dst == nil {
+		return ErrNilBuffer
+	}
+
+	if len(dst) != p.size() {
+		return &SizeError{Expected: p.size(), Got: len(dst), Context: "Sample dst"}
+	}
+
+	var x [3][]float64
+	for axis := range 3 {
+		x[axis] = []float64{0}
+		if axis < p.dim {
+			x[axis] = p.Coordinates(axis)
+		}
+	}
+
+	idx := 0
+	for _, xi := range x[0] {
+		for _, yj := range x[1] {
+			for _, zk := range x[2] {
+				dst[idx] = f(xi, yj, zk)
+				idx++
+			}
+		}
+	}
+
+	return nil
+}
+
+// MaxNorm returns the largest absolute value in v (0 for an empty v).
+func MaxNorm(v []float64) float64 {
+	m := 0.0
+	for _, x := range v {
+		m = math.Max(m, math.Abs(x))
+	}
+
+	return m
+}
+
+// L2Norm returns the discrete L2 norm sqrt(Σ v_i² · cellVolume), which
+// approximates the continuous norm on a grid whose cells have the given
+// volume (the product of the spacings, e.g. hx*hy in 2D).
+func L2Norm(v []float64, cellVolume float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+
+	return math.Sqrt(sum * cellVolume)
+}
+
+// MaxAbsDiff returns max_i |a_i - b_i|, the max-norm error of a against a
+// reference b. It returns +Inf if the lengths differ.
+func MaxAbsDiff(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return math.Inf(1)
+	}
+
+	m := 0.0
+	for i := range a {
+		m = math.Max(m, math.Abs(a[i]-b[i]))
+	}
+
+	return m
+}
@@ -0,0 +1,84 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestCoordinates_Conventions(t *testing.T) {
+	tests := []struct {
+		bc   poisson.BCType
+		want []float64
+	}{
+		{poisson.Periodic, []float64{0, 0.25, 0.5, 0.75}},
+		{poisson.Dirichlet, []float64{0.25, 0.5, 0.75, 1}},
+		{poisson.Neumann, []float64{0.125, 0.375, 0.625, 0.875}},
+	}
+
+	for _, tt := range tests {
+		if got := poisson.Coordinates(4, 0.25, tt.bc); !slices.Equal(got, tt.want) {
+			t.Errorf("Coordinates(%s) = %v, want %v", tt.bc, got, tt.want)
+		}
+	}
+
+	if got := poisson.Coordinates(0, 0.25, poisson.Periodic); got != nil {
+		t.Errorf("Coordinates(0) = %v, want nil", got)
+	}
+}
+
+func TestPlanCoordinatesAndSample(t *testing.T) {
+	plan, err := poisson.NewPlan(3, []int{3, 2, 4}, []float64{0.5, 0.25, 0.125},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	if got, want := plan.Coordinates(1), []float64{0.125, 0.375}; !slices.Equal(got, want) {
+		t.Errorf("Coordinates(1) = %v, want %v", got, want)
+	}
+	if plan.Coordinates(3) != nil || plan.Coordinates(-1) != nil {
+		t.Error("invalid axis returned coordinates")
+	}
+
+	dst := make([]float64, 3*2*4)
+	if err := plan.Sample(dst, func(x, y, z float64) float64 { return 100*x + 10*y + z }); err != nil {
+		t.Fatalf("Sample failed: %v", err)
+	}
+
+	// Point (i, j, k) = (2, 1, 3) is x = 1.5, y = 0.375, z = 0.375.
+	if got, want := dst[(2*2+1)*4+3], 150+3.75+0.375; got != want {
+		t.Errorf("dst[2,1,3] = %v, want %v", got, want)
+	}
+
+	var sizeErr *poisson.SizeError
+	if err := plan.Sample(dst[:5], func(x, y, z float64) float64 { return 0 }); !errors.As(err, &sizeErr) {
+		t.Errorf("expected SizeError, got %v", err)
+	}
+	if err := plan.Sample(nil, func(x, y, z float64) float64 { return 0 }); !errors.Is(err, poisson.ErrNilBuffer) {
+		t.Errorf("expected ErrNilBuffer, got %v", err)
+	}
+}
+
+func TestNorms(t *testing.T) {
+	v := []float64{3, -4, 0}
+
+	if got := poisson.MaxNorm(v); got != 4 {
+		t.Errorf("MaxNorm = %v, want 4", got)
+	}
+	if got := poisson.L2Norm(v, 0.25); got != 2.5 {
+		t.Errorf("L2Norm = %v, want 2.5", got)
+	}
+	if got := poisson.MaxAbsDiff(v, []float64{1, 1, 1}); got != 5 {
+		t.Errorf("MaxAbsDiff = %v, want 5", got)
+	}
+	if got := poisson.MaxAbsDiff(v, v[:2]); !math.IsInf(got, 1) {
+		t.Errorf("MaxAbsDiff with mismatched lengths = %v, want +Inf", got)
+	}
+	if poisson.MaxNorm(nil) != 0 || poisson.L2Norm(nil, 1) != 0 {
+		t.Error("norms of an empty vector are not zero")
+	}
+}
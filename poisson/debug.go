@@ -0,0 +1,163 @@
+package poisson
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+)
+
+// DebugDump writes the plan's resolved configuration to w as plain
+// "key: value" lines: grid and boundary conditions, eigenvalue extrema per
+// axis, option resolution, worker configuration and workspace sizes.
+// Attach its output to bug reports.
+func (p *Plan) DebugDump(w io.Writer) error {
+	d := &dumpWriter{w: w}
+
+	d.printf("plan: Plan (dim=%d)\n", p.dim)
+	d.printf("grid: n=%v h=%v\n", p.n[:p.dim], p.h[:p.dim])
+	d.printf("bc: %v\n", p.bc[:p.dim])
+	if slabs := p.slabs(); slabs > 1 {
+		d.printf("slabs: %d\n", slabs)
+	}
+
+	if p.sliceAlpha != nil {
+		d.printf("alpha: layered min=%g max=%g\n", slices.Min(p.sliceAlpha), slices.Max(p.sliceAlpha))
+	} else {
+		d.printf("alpha: %g\n", p.alpha)
+	}
+	d.printf("coefficients: %v\n", p.coeff[:p.dim])
+	if p.power != 1 {
+		d.printf("symbol: %g*λ^%d\n", p.scale, p.power)
+	}
+
+	for axis := 0; axis < p.dim; axis++ {
+		d.eigenvalues(axis, p.eig[axis])
+	}
+	lo, hi := p.MinMaxEigenvalue()
+	d.printf("operator eigenvalues: min=%g max=%g\n", lo, hi)
+	d.printf("nullspace: %t\n", p.hasNullspace())
+
+	if p.opts.ReferenceTransforms {
+		d.printf("transforms: reference (direct sums)\n")
+	} else {
+		d.printf("transforms: fast\n")
+	}
+	if p.opts.UseRealFFT {
+		d.printf("real FFT: disabled: not supported by Plan\n")
+	} else {
+		d.printf("real FFT: not requested\n")
+	}
+	var blocked []int
+	for axis := 0; axis < p.dim; axis++ {
+		if blockedEnabled(p.tr[axis]) {
+			blocked = append(blocked, axis)
+		}
+	}
+	d.printf("blocked axes: %v\n", blocked)
+	d.printf("spectral filter: %t\n", p.opts.SpectralFilter != nil)
+	d.printf("divide kernel: %s (line fast path: %t)\n", p.kernel.Name(), p.canDivideLines())
+	d.printf("precomputed inverse: %t (built: %t)\n", p.opts.PrecomputedInverse, p.inverse != nil)
+	d.printf("gpu: %t\n", p.gpu != nil)
+
+	d.options(p.opts, p.WorkBytes(), p.AccuracyProfile())
+
+	return d.err
+}
+
+// DebugDump writes the plan's resolved configuration to w.
+// See Plan.DebugDump for the format.
+func (p *Plan1DPeriodic) DebugDump(w io.Writer) error {
+	d := &dumpWriter{w: w}
+
+	d.printf("plan: Plan1DPeriodic\n")
+	d.printf("grid: n=[%d] h=[%g]\n", p.n, p.h)
+	d.eigenvalues(0, p.eig)
+	d.printf("real FFT: not supported by Plan1DPeriodic\n")
+	d.options(p.opts, p.work.Bytes(), p.AccuracyProfile())
+
+	return d.err
+}
+
+// DebugDump writes the plan's resolved configuration to w, including whether
+// WithRealFFT took effect and, if not, why. See Plan.DebugDump for the format.
+func (p *Plan2DPeriodic) DebugDump(w io.Writer) error {
+	d := &dumpWriter{w: w}
+
+	d.printf("plan: Plan2DPeriodic\n")
+	d.printf("grid: n=[%d %d] h=[%g %g]\n", p.nx, p.ny, p.hx, p.hy)
+	d.eigenvalues(0, p.eigX)
+	d.eigenvalues(1, p.eigY)
+	d.printf("real FFT: %s\n", p.realFFT)
+	d.options(p.opts, p.work.Bytes()+4*len(p.rbuf)+8*len(p.rspec)+p.r64.bytes(), p.AccuracyProfile())
+
+	return d.err
+}
+
+// DebugDump writes the plan's resolved configuration to w, including whether
+// WithRealFFT took effect and, if not, why. See Plan.DebugDump for the format.
+func (p *Plan3DPeriodic) DebugDump(w io.Writer) error {
+	d := &dumpWriter{w: w}
+
+	d.printf("plan: Plan3DPeriodic\n")
+	d.printf("grid: n=[%d %d %d] h=[%g %g %g]\n", p.nx, p.ny, p.nz, p.hx, p.hy, p.hz)
+	d.eigenvalues(0, p.eigX)
+	d.eigenvalues(1, p.eigY)
+	d.eigenvalues(2, p.eigZ)
+	d.printf("real FFT: %s\n", p.realFFT)
+	d.options(p.opts, p.work.Bytes()+4*len(p.rbuf)+8*len(p.rspec)+p.r64.bytes(), p.AccuracyProfile())
+
+	return d.err
+}
+
+// DebugDump writes the plan's resolved configuration to w.
+// See Plan.DebugDump for the format.
+func (p *PlanNDPeriodic) DebugDump(w io.Writer) error {
+	d := &dumpWriter{w: w}
+
+	d.printf("plan: PlanNDPeriodic (dim=%d)\n", len(p.shape))
+	d.printf("grid: n=%v h=%v\n", []int(p.shape), p.h)
+	for axis, eig := range p.eig {
+		d.eigenvalues(axis, eig)
+	}
+	if p.opts.UseRealFFT {
+		d.printf("real FFT: disabled: not supported for arbitrary dimensions\n")
+	} else {
+		d.printf("real FFT: not requested\n")
+	}
+	d.options(p.opts, p.work.Bytes(), p.AccuracyProfile())
+
+	return d.err
+}
+
+// dumpWriter formats DebugDump lines and keeps the first write error.
+type dumpWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (d *dumpWriter) printf(format string, args ...any) {
+	if d.err != nil {
+		return
+	}
+
+	_, d.err = fmt.Fprintf(d.w, format, args...)
+}
+
+func (d *dumpWriter) eigenvalues(axis int, eig []float64) {
+	d.printf("eigenvalues axis %d: n=%d min=%g max=%g\n", axis, len(eig), slices.Min(eig), slices.Max(eig))
+}
+
+// options writes the lines shared by all plan types.
+func (d *dumpWriter) options(opts Options, workBytes int, profile AccuracyProfile) {
+	d.printf("nullspace handling: %s\n", opts.Nullspace)
+	if opts.SolutionMean != nil {
+		d.printf("solution mean: %g\n", *opts.SolutionMean)
+	}
+	d.printf("resonance handling: %s (tolerance=%g)\n", opts.Resonance, opts.ResonanceTolerance)
+	d.printf("workers: %d (GOMAXPROCS=%d)\n", opts.Workers, runtime.GOMAXPROCS(0))
+	d.printf("transform strategy: %s\n", opts.TransformStrategy)
+	d.printf("in-place: %t\n", opts.InPlace)
+	d.printf("workspace bytes: %d\n", workBytes)
+	d.printf("accuracy path: %s\n", profile.Path)
+}
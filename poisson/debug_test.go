@@ -0,0 +1,70 @@
+package poisson_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_DebugDump(t *testing.T) {
+	plan, err := poisson.NewHelmholtzPlan(2, []int{8, 6}, []float64{0.5, 0.25},
+		[]poisson.BCType{poisson.Periodic, poisson.Dirichlet}, 2, poisson.WithWorkers(3))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := plan.DebugDump(&buf); err != nil {
+		t.Fatalf("DebugDump failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"bc: [Periodic Dirichlet]",
+		"alpha: 2\n",
+		"eigenvalues axis 0: n=8 min=0 max=16\n",
+		"eigenvalues axis 1: n=6",
+		"workers: 3 ",
+		"workspace bytes: ",
+		"real FFT: not requested",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dump missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPlan2DPeriodic_DebugDumpRealFFTReason(t *testing.T) {
+	plan, err := poisson.NewPlan2DPeriodic(6, 5, 1, 1, poisson.WithRealFFT(true))
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := plan.DebugDump(&buf); err != nil {
+		t.Fatalf("DebugDump failed: %v", err)
+	}
+	if want := "real FFT: disabled: requires even ny"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("dump missing %q:\n%s", want, buf.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestPlan_DebugDumpWriteError(t *testing.T) {
+	plan, err := poisson.NewPlan(1, []int{4}, []float64{1}, []poisson.BCType{poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	if err := plan.DebugDump(failingWriter{}); err == nil {
+		t.Fatal("expected write error")
+	}
+}
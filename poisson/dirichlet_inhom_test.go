@@ -163,3 +163,38 @@ func applyInhomDirichlet2D(dst, src []float64, shape grid.Shape, hx, hy float64,
 		}
 	}
 }
+
+// FaceCells pairs each boundary point with its index in the face data, in
+// the layout of BoundaryData.Values.
+func TestFaceCells(t *testing.T) {
+	shape := grid.NewShape3D(3, 4, 5)
+
+	for face := poisson.XLow; face <= poisson.ZHigh; face++ {
+		axis := int(face) / 2
+		a, b := (axis+1)%3, (axis+2)%3
+		a, b = min(a, b), max(a, b)
+
+		f := 0
+		for cell, got := range poisson.FaceCells(shape, face) {
+			var c [3]int
+			c[0], c[1], c[2] = grid.FromIndex3D(cell, shape)
+
+			wantAxis := 0
+			if face%2 == 1 {
+				wantAxis = shape[axis] - 1
+			}
+			if c[axis] != wantAxis || got != f || c[a]*shape[b]+c[b] != f {
+				t.Fatalf("%s: cell %v with face index %d, want %d", face, c, got, f)
+			}
+			f++
+		}
+
+		if f != shape[a]*shape[b] {
+			t.Errorf("%s: %d cells, want %d", face, f, shape[a]*shape[b])
+		}
+	}
+
+	for range poisson.FaceCells(shape, poisson.BoundaryFace(7)) {
+		t.Fatal("unknown face yielded a cell")
+	}
+}
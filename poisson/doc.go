@@ -10,6 +10,41 @@
 //	(α - Δ)u = f
 //
 // For diffusion steps u - νΔu = f, divide by ν to set α = 1/ν and RHS = f/ν.
+// NewAnisotropicHelmholtzPlan generalizes this to per-axis coefficients,
+// (α - c_x ∂xx - c_y ∂yy - c_z ∂zz)u = f. NewHyperdiffusionPlan solves the
+// implicit hyperviscosity step (I + ν(-Δ)^p)u = f for integer p >= 2, and
+// NewHyperviscosityFilter provides the explicit per-axis exponential filter
+// step exp(-dt Σ ν_axis λ_axis^p) as a stabilization option.
+//
+// WithStencil(Isotropic9) and WithStencil(Isotropic27) replace the standard
+// 5- and 7-point Laplacian of 2D and 3D plans with the compact 9- and
+// 27-point stencils, whose truncation error is isotropic to leading order.
+// Their symbols are exact polynomials of the per-axis eigenvalues, so every
+// boundary combination stays a direct solve.
+//
+// NewSlabPlan solves the nz independent 2D problems stored in the (x, y)
+// slabs of one 3D array in a single call, sharing the x/y transforms.
+// NewLayeredHelmholtzPlan does the same with its own shift per slab, for
+// layered media or a stack of per-frequency Helmholtz problems.
+// HelmholtzSweep builds on it to compute the frequency response of one source
+// at a set of probe points.
+//
+// The eigenvalue division runs line by line through a DivideKernel; the
+// default picks an AVX2 kernel on capable amd64 CPUs and a portable Go kernel
+// elsewhere. WithDivideKernel overrides the choice. WithPrecomputedInverse
+// instead stores the inverse symbol of every mode, so solves multiply and
+// never divide, at 8 bytes per grid point. WithTransformStrategy selects
+// how transforms along strided axes access memory: line by line, through
+// cache-blocked copies of adjacent lines, or whichever timed faster when the
+// plan was created.
+//
+// WithGPU moves the transforms and eigenvalue division of all-periodic plans
+// to a CUDA GPU in binaries built with the cuda tag.
+//
+// For indefinite Helmholtz problems (α < 0), modes where α cancels an
+// eigenvalue make Solve fail with ErrResonant. WithResonanceTolerance widens
+// the check to near-singular modes, and WithResonance(ResonancePseudoInverse)
+// or WithTikhonov(μ) regularize those modes instead of failing.
 //
 // # Boundary Conditions
 //
@@ -21,6 +56,13 @@
 //
 // Mixed boundary conditions (different BC per axis) are also supported.
 //
+// Each boundary condition places the grid points differently: periodic
+// points at x_i = i*h, Dirichlet interior points at x_i = (i+1)*h, and
+// Neumann cell centers at x_i = (i+0.5)*h. Coordinates and Plan.Coordinates
+// return these positions, Plan.Sample evaluates a function at every grid
+// point, and MaxNorm, L2Norm and MaxAbsDiff measure errors against a known
+// solution.
+//
 // # Plan-Based API
 //
 // The solver uses a plan-based API for efficiency:
@@ -44,9 +86,60 @@
 //	    return err
 //	}
 //
+// NewBuilder offers the same constructors through chained setters, e.g.
+// NewBuilder().Dims(nx, ny).Spacing(h).BC(Dirichlet, Neumann).Alpha(2).Build().
+// PlanConfig describes a plan as data for configuration files: LoadPlanConfig
+// reads and validates JSON, and NewPlanFromConfig builds the plan. Enums are
+// written by name ("dirichlet", "subtract_mean"), so YAML decoders that use
+// the yaml tags and encoding.TextUnmarshaler read the same settings.
+// A Cache keeps the plans of recently used configurations with LRU eviction
+// and hit/miss counters, e.g. one plan per frequency in a Helmholtz service.
+//
 // For inhomogeneous Dirichlet/Neumann data, use SolveWithBC and provide
-// boundary values per face. The solver applies the boundary contributions
-// before solving.
+// boundary values per face; faces left out stay homogeneous. The solver
+// applies the boundary contributions before solving. For time-stepping, set
+// BoundaryData.Func instead of Values and call SolveWithBCAt(t, ...) to
+// evaluate the boundary values at time t.
+// On pure Neumann problems, WithFluxBalance removes the net boundary flux as
+// a uniform source so incompatible flux data can still be solved.
+// SolveBoundary solves the homogeneous problem driven by boundary data alone
+// (Laplace problems of potential flow or electrostatics) without a zero RHS
+// array, skipping the forward transforms of planes the data does not touch.
+// Fields with an imposed mean gradient across a periodic box, u = G·x + w
+// with periodic w, are solved by SolveWithMeanGradient; AddMeanGradient and
+// SubtractMeanGradient convert between u and its periodic fluctuation.
+//
+// Solve and SolveInPlace on Plan accept optional SolveOptions that override
+// plan defaults for a single call: WithGauge (solution mean),
+// WithSolveNullspace (nullspace policy), WithSolveWorkers (fewer workers),
+// WithVerify (residual check), and WithFilter (spectral filter). SolveWithBC
+// and the other solve methods of Plan accept them too. WithSpectralFilter sets
+// a filter for every solve of a plan; TwoThirdsFilter and ExponentialFilter
+// cover the usual dealiasing and high-mode damping cases. WithPrecisionCheck
+// recomputes sampled spectral divisions in higher precision to catch
+// cancellation on very fine grids or near-resonant shifts.
+//
+// SolveView and SolveWithBCView read and write strided windows (grid.View)
+// of larger arrays, so fields with ghost layers need no packing. SolveAt
+// returns the solution only at a list of probe points, summing the spectral
+// coefficients directly instead of running the inverse transforms.
+//
+// For Krylov workflows, Plan.Operator exposes the discrete operator as a
+// matrix-free linear operator and Plan.Preconditioner exposes the fast solve
+// as its inverse; OperatorFunc and PreconditionerFunc return plain functions.
+// InverseSymbol and SymbolFactors export the spectral Green's function, so
+// external pipelines such as GPU shaders can apply the same solve.
+// AxisTransform returns the plan's transform along one axis for partial
+// transforms with the same conventions; every axis transform round-trips
+// exactly (NormalizationFactor 1).
+//
+// Chain composes plans of one grid with FilterStage and DerivativeStage into
+// a Pipeline that applies all of them with a single forward/inverse transform
+// pair, e.g. a Helmholtz solve followed by a hyperdiffusion step and a
+// derivative on a periodic axis.
+//
+// To bisect accuracy problems, WithReferenceTransforms replaces the fast
+// transforms of a Plan by direct O(N²) sums; results should agree to round-off.
 //
 // # Nullspace Handling
 //
@@ -57,9 +150,58 @@
 //   - NullspaceSubtractMean: Automatically subtract mean from RHS
 //   - NullspaceError: Return error if nullspace exists
 //
+// Pass WithStats to Plan.Solve to receive the subtracted RHS mean and the
+// applied solution mean in a SolveStats value. ZeroModeCoefficient and
+// NetSource report the zero-mode coefficient the solve dropped (the net
+// source, e.g. net charge) under every nullspace policy.
+//
+// # Accuracy
+//
+// Every plan type reports the accuracy to expect from its numeric path via
+// AccuracyProfile(): the float64 complex path and the float32 real FFT path
+// (WithRealFFT) have different solution and residual tolerances.
+// WithRealFFTPrecision(RealFFTFloat64) keeps the real FFT speedup at float64
+// accuracy.
+//
+// Options that do not apply are not errors: plans fall back (complex FFTs
+// when WithRealFFT cannot be used for the grid) or ignore them. Report()
+// returns a PlanReport listing every requested option as honored,
+// downgraded or rejected, with the reason.
+//
+// # Errors
+//
+// Invalid input is reported before any work as a *ValidationError (bad
+// arguments and option values; wraps ErrInvalidSize or ErrInvalidSpacing for
+// non-positive sizes and spacings) or a *SizeError (a buffer of the wrong
+// length, named by method and argument; wraps ErrSizeMismatch). Failures
+// while creating a plan's transforms or solving are runtime errors: a
+// *TransformError names the stage, transform, axis and grid shape ("forward
+// DST-I axis 1 (n=37) of 12x37 grid: ..."), and *ResonantError,
+// *VerificationError and *PrecisionError report numerical failures.
+// WithCheckFinite adds a *NonFiniteError (wrapping ErrNonFinite) for a
+// right-hand side holding NaN or Inf, with the index of the first one. Use
+// errors.As to tell the groups apart; errors.Is on the sentinels keeps
+// working.
+//
 // # Performance
 //
 // The solver has O(N log N) complexity where N is the total number of grid points.
 // Plans should be reused for multiple solves to avoid repeated setup costs.
-// The Solve method is designed for zero allocations when using pre-made plans.
+// Solves on pre-made plans with one worker (WithWorkers(1)) do not
+// allocate, for every plan type and for SolveWithBC; tests gate this with
+// testing.AllocsPerRun. With more workers, each parallel pass allocates a
+// little to start its goroutines. SolveOptions allocate when they are built,
+// so hot loops should build them once and reuse them.
+// WithWorkspace lets many plans share one workspace and WithPooledWorkspace
+// borrows it from a package pool per solve, so idle plans hold no large
+// buffers.
+// WithAutoTune(budget) benchmarks worker counts, strided versus blocked
+// transforms and (with WithRealFFT) real versus complex FFTs at plan
+// creation and keeps the fastest configuration, reported by Report().
+// WithTiming makes Plan accumulate the time spent in forward transforms,
+// eigenvalue division, inverse transforms and copies; Plan.Stats shows where
+// a large solve spends its time.
+// For long-running services, WithMetrics reports every solve call with its
+// duration and error to a Metrics implementation, e.g. one backed by expvar
+// counters or Prometheus collectors.
 package poisson
@@ -3,6 +3,11 @@ package poisson
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
 )
 
 var (
@@ -28,11 +33,20 @@ var (
 	// ErrNilBuffer is returned when a required buffer is nil.
 	ErrNilBuffer = errors.New("buffer is nil")
 
+	// ErrGPUUnavailable is returned when WithGPU is requested in a binary
+	// built without GPU support (the cuda build tag).
+	ErrGPUUnavailable = errors.New("GPU backend not available: build with -tags cuda")
+
 	// ErrResonant is returned when the Helmholtz operator is singular.
 	ErrResonant = errors.New("helmholtz operator is singular: alpha cancels eigenvalue")
+
+	// ErrNonFinite is returned with WithCheckFinite when an input holds NaN
+	// or Inf.
+	ErrNonFinite = errors.New("non-finite input")
 )
 
-// SizeError provides details about a size mismatch.
+// SizeError provides details about a size mismatch. It wraps
+// ErrSizeMismatch, so errors.Is(err, ErrSizeMismatch) keeps working.
 type SizeError struct {
 	Expected int
 	Got      int
@@ -44,12 +58,270 @@ func (e *SizeError) Error() string {
 		e.Context, e.Expected, e.Got)
 }
 
+// Unwrap returns ErrSizeMismatch.
+func (e *SizeError) Unwrap() error {
+	return ErrSizeMismatch
+}
+
+// checkBuffers returns a *SizeError naming the first of dst and src (the
+// argument srcName of method) whose length is not size.
+func checkBuffers(method string, size int, dst, src []float64, srcName string) error {
+	if len(dst) != size {
+		return &SizeError{Expected: size, Got: len(dst), Context: method + " dst"}
+	}
+
+	if len(src) != size {
+		return &SizeError{Expected: size, Got: len(src), Context: method + " " + srcName}
+	}
+
+	return nil
+}
+
+// checkViews returns an error naming the first of the dst and rhs views of
+// method that does not fit its buffer.
+func checkViews(method string, shape grid.Shape, dst []float64, dstView grid.View, rhs []float64, rhsView grid.View) error {
+	if !dstView.Fits(shape, len(dst)) {
+		return viewError(method+" dst view", shape, dstView, len(dst))
+	}
+
+	if !rhsView.Fits(shape, len(rhs)) {
+		return viewError(method+" rhs view", shape, rhsView, len(rhs))
+	}
+
+	return nil
+}
+
+// viewError describes why view does not fit a buffer of length n: a
+// *SizeError if the buffer is too short, a *ValidationError wrapping
+// ErrSizeMismatch for a negative offset or non-positive strides.
+func viewError(context string, shape grid.Shape, view grid.View, n int) error {
+	need := view.Index(shape[0]-1, shape[1]-1, shape[2]-1) + 1
+	if view.Fits(shape, need) {
+		return &SizeError{Expected: need, Got: n, Context: context}
+	}
+
+	return &ValidationError{Field: context, Message: "negative offset or non-positive stride", Err: ErrSizeMismatch}
+}
+
+// TransformError reports a transform that failed while creating a plan or
+// solving, with the transform, the axis and the grid shape, e.g.
+// "forward DST-I axis 1 (n=37) of 12x37 grid: ...". It wraps the
+// underlying error.
+type TransformError struct {
+	// Stage is "setup" (plan creation), "forward" or "inverse".
+	Stage string
+
+	// Transform names the transform: "FFT", "real FFT", "DST-I", "DCT-II"
+	// or "GPU".
+	Transform string
+
+	// Axis is the transformed axis, or -1 for a transform over the whole
+	// grid (real FFT and GPU paths).
+	Axis int
+
+	// Shape is the grid shape.
+	Shape Shape
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *TransformError) Error() string {
+	dims := make([]string, len(e.Shape))
+	for i, n := range e.Shape {
+		dims[i] = strconv.Itoa(n)
+	}
+	extent := strings.Join(dims, "x")
+
+	if e.Axis < 0 || e.Axis >= len(e.Shape) {
+		return fmt.Sprintf("%s %s of %s grid: %v", e.Stage, e.Transform, extent, e.Err)
+	}
+
+	return fmt.Sprintf("%s %s axis %d (n=%d) of %s grid: %v",
+		e.Stage, e.Transform, e.Axis, e.Shape[e.Axis], extent, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+// transformError wraps err in a *TransformError for the first dim axes of
+// shape.
+func transformError(stage, transform string, axis int, shape grid.Shape, dim int, err error) error {
+	return &TransformError{
+		Stage:     stage,
+		Transform: transform,
+		Axis:      axis,
+		Shape:     Shape(append([]int(nil), shape[:dim]...)),
+		Err:       err,
+	}
+}
+
+// transformName returns the name of the axis transform for bc.
+func transformName(bc BCType) string {
+	switch bc {
+	case Dirichlet:
+		return "DST-I"
+	case Neumann:
+		return "DCT-II"
+	default:
+		return "FFT"
+	}
+}
+
+// ResonantError reports the mode at which the Helmholtz operator is singular
+// (or near-singular with a resonance tolerance). It wraps ErrResonant, so
+// errors.Is(err, ErrResonant) keeps working.
+type ResonantError struct {
+	// Mode holds the spectral indices (i, j, k) of the offending mode.
+	// Indices for unused axes are zero.
+	Mode [3]int
+
+	// Eigenvalue is the Laplacian eigenvalue sum λ of the mode.
+	Eigenvalue float64
+
+	// Alpha is the plan's shift; the mode's symbol is Alpha + Eigenvalue.
+	Alpha float64
+}
+
+func (e *ResonantError) Error() string {
+	return fmt.Sprintf("%v: mode (%d, %d, %d) eigenvalue %g, alpha %g",
+		ErrResonant, e.Mode[0], e.Mode[1], e.Mode[2], e.Eigenvalue, e.Alpha)
+}
+
+// Unwrap returns ErrResonant.
+func (e *ResonantError) Unwrap() error {
+	return ErrResonant
+}
+
+// NonFiniteError reports the first NaN or Inf found in an input by
+// WithCheckFinite. It wraps ErrNonFinite, so errors.Is(err, ErrNonFinite)
+// keeps working.
+type NonFiniteError struct {
+	// Context names the input, e.g. "Solve rhs".
+	Context string
+
+	// Index is the buffer index of the offending value.
+	Index int
+
+	// Value is the offending value.
+	Value float64
+}
+
+func (e *NonFiniteError) Error() string {
+	return fmt.Sprintf("%v: %s[%d] = %v", ErrNonFinite, e.Context, e.Index, e.Value)
+}
+
+// Unwrap returns ErrNonFinite.
+func (e *NonFiniteError) Unwrap() error {
+	return ErrNonFinite
+}
+
+// checkFinite returns a *NonFiniteError for the first NaN or Inf in buf.
+func checkFinite(context string, buf []float64) error {
+	for i, v := range buf {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return &NonFiniteError{Context: context, Index: i, Value: v}
+		}
+	}
+
+	return nil
+}
+
+// checkFiniteView is checkFinite for the window of shape at view in buf, in
+// row-major order.
+func checkFiniteView(context string, buf []float64, shape grid.Shape, view grid.View) error {
+	if view.IsPacked(shape) {
+		return checkFinite(context, buf[:shape.Size()])
+	}
+
+	for i := range shape[0] {
+		for j := range shape[1] {
+			for k := range shape[2] {
+				idx := view.Index(i, j, k)
+				if v := buf[idx]; math.IsNaN(v) || math.IsInf(v, 0) {
+					return &NonFiniteError{Context: context, Index: idx, Value: v}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// VerificationError is returned by Solve when WithVerify is requested and the
+// relative residual of the computed solution exceeds the tolerance.
+type VerificationError struct {
+	Residual  float64
+	Tolerance float64
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("solution verification failed: relative residual %g exceeds tolerance %g",
+		e.Residual, e.Tolerance)
+}
+
+// PrecisionError is returned by Solve when WithPrecisionCheck is requested
+// and the float64 symbol of a sampled mode deviates from its high-precision
+// value by more than the tolerance.
+type PrecisionError struct {
+	// Mode holds the spectral indices (i, j, k) of the worst sampled mode.
+	Mode      [3]int
+	Deviation float64
+	Tolerance float64
+}
+
+func (e *PrecisionError) Error() string {
+	return fmt.Sprintf("precision check failed: mode %v deviates by %g (relative), tolerance %g",
+		e.Mode, e.Deviation, e.Tolerance)
+}
+
 // ValidationError wraps validation failures with context.
 type ValidationError struct {
 	Field   string
 	Message string
+
+	// Err is the sentinel the failure corresponds to (ErrInvalidSize,
+	// ErrInvalidSpacing), if any.
+	Err error
 }
 
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error for %s: %s", e.Field, e.Message)
 }
+
+// Unwrap returns the sentinel error of the failure, or nil.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// checkGrid returns a *ValidationError for the first non-positive size in n
+// or spacing in h of a periodic plan constructor, named after its arguments
+// (nx, ny, nz, hx, hy, hz).
+func checkGrid(n []int, h []float64) error {
+	for axis, size := range n {
+		if size < 1 {
+			return invalidSize("n" + string("xyz"[axis]))
+		}
+	}
+
+	for axis, spacing := range h {
+		if spacing <= 0 {
+			return invalidSpacing("h" + string("xyz"[axis]))
+		}
+	}
+
+	return nil
+}
+
+// invalidSize returns a *ValidationError wrapping ErrInvalidSize for field.
+func invalidSize(field string) error {
+	return &ValidationError{Field: field, Message: "must be positive", Err: ErrInvalidSize}
+}
+
+// invalidSpacing returns a *ValidationError wrapping ErrInvalidSpacing for
+// field.
+func invalidSpacing(field string) error {
+	return &ValidationError{Field: field, Message: "must be positive", Err: ErrInvalidSpacing}
+}
@@ -0,0 +1,101 @@
+package poisson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestValidationErrorsWrapSentinels(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() error
+		field    string
+		sentinel error
+	}{
+		{"Plan size", func() error {
+			_, err := poisson.NewPlan(2, []int{4, 0}, []float64{1, 1}, []poisson.BCType{poisson.Periodic, poisson.Periodic})
+			return err
+		}, "n[1]", poisson.ErrInvalidSize},
+		{"Plan spacing", func() error {
+			_, err := poisson.NewPlan(1, []int{4}, []float64{-1}, []poisson.BCType{poisson.Dirichlet})
+			return err
+		}, "h[0]", poisson.ErrInvalidSpacing},
+		{"Plan2DPeriodic spacing", func() error {
+			_, err := poisson.NewPlan2DPeriodic(4, 4, 1, 0)
+			return err
+		}, "hy", poisson.ErrInvalidSpacing},
+		{"Plan3DPeriodic size", func() error {
+			_, err := poisson.NewPlan3DPeriodic(4, 4, 0, 1, 1, 1)
+			return err
+		}, "nz", poisson.ErrInvalidSize},
+		{"PlanNDPeriodic size", func() error {
+			_, err := poisson.NewPlanNDPeriodic(poisson.Shape{4, 0, 2, 2}, []float64{1, 1, 1, 1})
+			return err
+		}, "shape[1]", poisson.ErrInvalidSize},
+	}
+
+	for _, tt := range tests {
+		err := tt.build()
+
+		var vErr *poisson.ValidationError
+		if !errors.As(err, &vErr) {
+			t.Errorf("%s: expected ValidationError, got %v", tt.name, err)
+			continue
+		}
+		if vErr.Field != tt.field {
+			t.Errorf("%s: Field = %q, want %q", tt.name, vErr.Field, tt.field)
+		}
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("%s: %v does not wrap %v", tt.name, err, tt.sentinel)
+		}
+	}
+}
+
+func TestSizeErrorsNameTheBuffer(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{4, 3}, []float64{1, 1}, []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	dst := make([]float64, 12)
+
+	err = plan.Solve(dst, make([]float64, 10))
+	var sizeErr *poisson.SizeError
+	if !errors.As(err, &sizeErr) || !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("expected SizeError wrapping ErrSizeMismatch, got %v", err)
+	}
+	if sizeErr.Context != "Solve rhs" || sizeErr.Expected != 12 || sizeErr.Got != 10 {
+		t.Errorf("SizeError = %+v", sizeErr)
+	}
+
+	// A window at offset 5 of a 4x3 view with row stride 3 ends at index 16.
+	view := grid.View{Offset: 5, Stride: grid.RowMajorStride(grid.Shape{4, 3, 1})}
+	err = plan.SolveView(dst, grid.PackedView(grid.Shape{4, 3, 1}), make([]float64, 12), view)
+	if !errors.As(err, &sizeErr) || sizeErr.Context != "SolveView rhs view" || sizeErr.Expected != 17 {
+		t.Errorf("expected SizeError for the rhs view, got %v", err)
+	}
+
+	view.Offset = -1
+	err = plan.SolveView(dst, view, dst, grid.PackedView(grid.Shape{4, 3, 1}))
+	var vErr *poisson.ValidationError
+	if !errors.As(err, &vErr) || !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Errorf("expected ValidationError wrapping ErrSizeMismatch for a negative offset, got %v", err)
+	}
+}
+
+func TestTransformError_Message(t *testing.T) {
+	inner := errors.New("boom")
+	err := &poisson.TransformError{
+		Stage: "forward", Transform: "DST-I", Axis: 1, Shape: poisson.Shape{12, 37}, Err: inner,
+	}
+
+	if got, want := err.Error(), "forward DST-I axis 1 (n=37) of 12x37 grid: boom"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, inner) {
+		t.Error("TransformError does not unwrap to its cause")
+	}
+}
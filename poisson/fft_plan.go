@@ -17,6 +17,8 @@ type FFTPlan struct {
 	plans    []*algofft.Plan[complex128]
 	scratchA [][]complex128
 	scratchB [][]complex128
+	blocks   lineBlocks
+	workerLimit
 }
 
 // NewFFTPlan creates a new complex FFT plan for length n.
@@ -74,7 +76,7 @@ func (p *FFTPlan) Len() int {
 // data is modified in-place.
 //
 // For axis-wise transforms, this method relies on algo-fft's strided transform
-// support and does not allocate.
+// support and does not allocate with a single worker.
 func (p *FFTPlan) TransformLines(data []complex128, shape grid.Shape, axis int, inverse bool) error {
 	if data == nil {
 		return ErrNilBuffer
@@ -88,23 +90,33 @@ func (p *FFTPlan) TransformLines(data []complex128, shape grid.Shape, axis int,
 		return ErrSizeMismatch
 	}
 
-	useOutOfPlace := !isPowerOfTwo(p.n)
 	lineStride := grid.RowMajorStride(shape)[axis]
+	if p.blocks.active(lineStride) {
+		return p.transformBlocked(data, lineStride, inverse, nil, 0)
+	}
+
 	numLines := lineCount(shape, axis)
-	workers := clampWorkers(p.workers, numLines)
-
-	return parallelFor(workers, numLines, func(worker, startLine, endLine int) error {
-		plan := p.plans[worker]
-		scratchA := p.scratchA[worker]
-		scratchB := p.scratchB[worker]
-		for line := startLine; line < endLine; line++ {
-			start := lineStartIndex(shape, axis, line)
-			if err := p.transformLine(plan, scratchA, scratchB, data, start, lineStride, inverse, useOutOfPlace); err != nil {
-				return err
-			}
+	workers := clampWorkers(p.capped(p.workers), numLines)
+	pass := linePass{data: data, shape: shape, axis: axis, inverse: inverse}
+
+	return parallelRun(workers, numLines, p, pass, (*FFTPlan).lines)
+}
+
+// lines is the body of TransformLines for lines [startLine, endLine).
+func (p *FFTPlan) lines(pass linePass, worker, startLine, endLine int) error {
+	useOutOfPlace := !isPowerOfTwo(p.n)
+	lineStride := grid.RowMajorStride(pass.shape)[pass.axis]
+	plan := p.plans[worker]
+	scratchA := p.scratchA[worker]
+	scratchB := p.scratchB[worker]
+
+	for line := startLine; line < endLine; line++ {
+		start := lineStartIndex(pass.shape, pass.axis, line)
+		if err := p.transformLine(plan, scratchA, scratchB, pass.data, start, lineStride, pass.inverse, useOutOfPlace); err != nil {
+			return err
 		}
-		return nil
-	})
+	}
+	return nil
 }
 
 func (p *FFTPlan) transformLine(
@@ -157,6 +169,77 @@ func (p *FFTPlan) transformLine(
 	return nil
 }
 
+// inverseLinesReal is the last inverse pass of a solve: it applies the
+// inverse FFT along all lines parallel to axis and stores the real part of
+// each result plus shift into dst (row-major, same shape) instead of data,
+// so no separate output sweep is needed. data is used as input only.
+func (p *FFTPlan) inverseLinesReal(data []complex128, shape grid.Shape, axis int, dst []float64, shift float64) error {
+	if data == nil || dst == nil {
+		return ErrNilBuffer
+	}
+
+	if len(data) != shape.Size() || len(dst) != len(data) || shape.N(axis) != p.n {
+		return ErrSizeMismatch
+	}
+
+	lineStride := grid.RowMajorStride(shape)[axis]
+	if p.blocks.active(lineStride) {
+		return p.transformBlocked(data, lineStride, true, dst, shift)
+	}
+
+	numLines := lineCount(shape, axis)
+	workers := clampWorkers(p.capped(p.workers), numLines)
+	pass := linePass{data: data, shape: shape, axis: axis, inverse: true, out: dst, shift: shift}
+
+	return parallelRun(workers, numLines, p, pass, (*FFTPlan).realLines)
+}
+
+// realLines is the body of inverseLinesReal for lines [startLine, endLine).
+func (p *FFTPlan) realLines(pass linePass, worker, startLine, endLine int) error {
+	lineStride := grid.RowMajorStride(pass.shape)[pass.axis]
+	plan := p.plans[worker]
+	scratchA := p.scratchA[worker]
+	scratchB := p.scratchB[worker]
+
+	for line := startLine; line < endLine; line++ {
+		start := lineStartIndex(pass.shape, pass.axis, line)
+		for i := range p.n {
+			scratchA[i] = pass.data[start+i*lineStride]
+		}
+
+		if err := plan.Inverse(scratchB, scratchA); err != nil {
+			return err
+		}
+
+		for i, v := range scratchB {
+			pass.out[start+i*lineStride] = real(v) + pass.shift
+		}
+	}
+	return nil
+}
+
+func (p *FFTPlan) setBlocked(enabled bool) {
+	p.blocks.set(enabled, p.n, p.workers)
+}
+
+// transformBlocked runs the TransformBlocked strategy; see lineBlocks.run.
+func (p *FFTPlan) transformBlocked(data []complex128, stride int, inverse bool, out []float64, shift float64) error {
+	return p.blocks.run(data, p.n, stride, p.capped(p.workers), out, shift, func(worker int, line []complex128) error {
+		scratch := p.scratchB[worker]
+		var err error
+		if inverse {
+			err = p.plans[worker].Inverse(scratch, line)
+		} else {
+			err = p.plans[worker].Forward(scratch, line)
+		}
+		if err != nil {
+			return err
+		}
+		copy(line, scratch)
+		return nil
+	})
+}
+
 func isPowerOfTwo(n int) bool {
 	return n > 0 && (n&(n-1)) == 0
 }
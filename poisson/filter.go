@@ -0,0 +1,89 @@
+package poisson
+
+import "math"
+
+// FilterFunc returns the multiplier applied to a spectral coefficient.
+//
+// eta holds the normalized frequency of the mode along each axis, in [0, 1]:
+// 0 is the lowest mode and 1 the highest resolvable mode for the axis'
+// transform. Entries for unused axes are zero. A return value of 1 keeps the
+// mode unchanged, 0 removes it.
+type FilterFunc func(eta [3]float64) float64
+
+// TwoThirdsFilter returns the 2/3-rule dealiasing filter: modes with a
+// normalized frequency above 2/3 along any axis are removed, the rest are
+// kept unchanged. This removes quadratic aliasing errors in pseudo-spectral
+// products.
+func TwoThirdsFilter() FilterFunc {
+	return CutoffFilter(2.0 / 3.0)
+}
+
+// CutoffFilter returns a sharp filter that removes modes whose normalized
+// frequency exceeds cutoff along any axis.
+func CutoffFilter(cutoff float64) FilterFunc {
+	return func(eta [3]float64) float64 {
+		for _, e := range eta {
+			if e > cutoff {
+				return 0
+			}
+		}
+		return 1
+	}
+}
+
+// ExponentialFilter returns the smooth filter
+//
+//	σ(η) = exp(-alpha * Σ_axis η_axis^order)
+//
+// which leaves low modes nearly untouched and damps the highest mode by
+// exp(-alpha) per axis. alpha = 36 damps the highest mode to double precision
+// round-off; order (typically 8–36) controls how sharply the damping sets in.
+// Non-positive orders are treated as 1.
+func ExponentialFilter(alpha float64, order int) FilterFunc {
+	if order < 1 {
+		order = 1
+	}
+
+	return func(eta [3]float64) float64 {
+		s := 0.0
+		for _, e := range eta {
+			s += intPow(e, order)
+		}
+		return math.Exp(-alpha * s)
+	}
+}
+
+// normalizedFrequencies returns eta for every mode of a 1D transform of
+// length n with the given boundary condition.
+func normalizedFrequencies(n int, bc BCType) []float64 {
+	eta := make([]float64, n)
+	switch bc {
+	case Periodic:
+		half := float64(n / 2)
+		if half == 0 {
+			return eta
+		}
+		for m := range n {
+			k := m
+			if n-m < k {
+				k = n - m
+			}
+			eta[m] = float64(k) / half
+		}
+
+	case Dirichlet:
+		for m := range n {
+			eta[m] = float64(m+1) / float64(n)
+		}
+
+	case Neumann:
+		if n == 1 {
+			return eta
+		}
+		for m := range n {
+			eta[m] = float64(m) / float64(n-1)
+		}
+	}
+
+	return eta
+}
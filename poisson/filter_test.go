@@ -0,0 +1,101 @@
+package poisson_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestTwoThirdsFilter(t *testing.T) {
+	filter := poisson.TwoThirdsFilter()
+
+	tests := []struct {
+		eta  [3]float64
+		want float64
+	}{
+		{[3]float64{0, 0, 0}, 1},
+		{[3]float64{0.5, 0.6, 2.0 / 3.0}, 1},
+		{[3]float64{0.7, 0, 0}, 0},
+		{[3]float64{0, 0, 1}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := filter(tt.eta); got != tt.want {
+			t.Fatalf("TwoThirdsFilter(%v) = %g, want %g", tt.eta, got, tt.want)
+		}
+	}
+}
+
+func TestExponentialFilter(t *testing.T) {
+	filter := poisson.ExponentialFilter(36, 8)
+
+	if got := filter([3]float64{}); got != 1 {
+		t.Fatalf("filter(0) = %g, want 1", got)
+	}
+
+	if got, want := filter([3]float64{1, 0, 0}), math.Exp(-36); math.Abs(got-want) > 1e-30 {
+		t.Fatalf("filter(1) = %g, want %g", got, want)
+	}
+
+	if got := filter([3]float64{0.25, 0.25, 0}); math.Abs(got-1) > 1e-2 {
+		t.Fatalf("filter(0.25, 0.25) = %g, want ≈1", got)
+	}
+
+	prev := 1.0
+	for i := 1; i <= 10; i++ {
+		got := filter([3]float64{float64(i) / 10})
+		if got > prev {
+			t.Fatalf("filter not monotone at eta=%g: %g > %g", float64(i)/10, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestPlan_WithSpectralFilter(t *testing.T) {
+	nx, ny := 12, 16
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Periodic}
+
+	filtered, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, []float64{0.1, 0.1}, bc, 1,
+		poisson.WithSpectralFilter(poisson.TwoThirdsFilter()))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	plain, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, []float64{0.1, 0.1}, bc, 1)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, nx*ny)
+	for i := range rhs {
+		rhs[i] = math.Sin(float64(i)*0.7) + 0.3*math.Cos(float64(i*i)*0.01)
+	}
+
+	got := make([]float64, nx*ny)
+	if err := filtered.Solve(got, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	want := make([]float64, nx*ny)
+	if err := plain.Solve(want, rhs, poisson.WithFilter(poisson.TwoThirdsFilter())); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if diff := maxAbsDiff(got, want); diff > solveOptionsTol {
+		t.Fatalf("plan filter differs from per-call filter by %g", diff)
+	}
+
+	if err := plain.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if diff := maxAbsDiff(got, want); diff < 1e-6 {
+		t.Fatalf("2/3 filter did not change the solution (diff %g)", diff)
+	}
+
+	if err := filtered.Solve(got, rhs, poisson.WithFilter(nil)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if diff := maxAbsDiff(got, want); diff > solveOptionsTol {
+		t.Fatalf("WithFilter(nil) did not disable plan filter (diff %g)", diff)
+	}
+}
@@ -0,0 +1,65 @@
+package poisson
+
+import "fmt"
+
+// gpuBackend runs the forward transforms, the symbol multiplication and the
+// inverse transforms of a Plan on a GPU.
+type gpuBackend interface {
+	// solve replaces data (the plan's packed complex workspace) by
+	// IFFT(symbol * FFT(data)) in place.
+	solve(data []complex128) error
+}
+
+// newGPUBackend creates a backend for a packed row-major complex array of
+// shape n with dim transformed axes. symbol includes the 1/size scaling of
+// the unnormalized inverse transform. It is nil unless the binary was built
+// with the cuda tag.
+var newGPUBackend func(n [3]int, dim int, symbol []complex128) (gpuBackend, error)
+
+// initGPU precomputes the plan's inverse symbol and uploads it to a GPU
+// backend.
+func (p *Plan) initGPU() error {
+	if newGPUBackend == nil {
+		return ErrGPUUnavailable
+	}
+
+	for axis := 0; axis < p.dim; axis++ {
+		if p.bc[axis] != Periodic {
+			return &ValidationError{
+				Field:   "UseGPU",
+				Message: fmt.Sprintf("GPU backend requires periodic axes, axis %d is %s", axis, p.bc[axis]),
+			}
+		}
+	}
+
+	if p.slabs() > 1 {
+		return &ValidationError{
+			Field:   "UseGPU",
+			Message: "GPU backend does not support batch (slab) plans",
+		}
+	}
+
+	// The symbol is what applyEigenvalues multiplies each mode by, so apply
+	// it to a vector of ones. Resonant modes are therefore reported when the
+	// plan is created rather than on every Solve.
+	for idx := range p.work.Complex {
+		p.work.Complex[idx] = 1
+	}
+	if err := p.applyEigenvalues(nil); err != nil {
+		return fmt.Errorf("gpu: %w", err)
+	}
+
+	size := p.size()
+	symbol := make([]complex128, size)
+	for idx, v := range p.work.Complex[:size] {
+		symbol[idx] = v / complex(float64(size), 0)
+	}
+
+	gpu, err := newGPUBackend(p.n, p.dim, symbol)
+	if err != nil {
+		return transformError("setup", "GPU", -1, p.shape(), p.gridDim(), err)
+	}
+	p.gpu = gpu
+
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !cuda
+
+package poisson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestWithGPU_UnavailableWithoutCUDA(t *testing.T) {
+	_, err := poisson.NewPlan(1, []int{8}, []float64{1}, []poisson.BCType{poisson.Periodic}, poisson.WithGPU())
+	if !errors.Is(err, poisson.ErrGPUUnavailable) {
+		t.Fatalf("expected ErrGPUUnavailable, got %v", err)
+	}
+}
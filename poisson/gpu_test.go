@@ -0,0 +1,167 @@
+package poisson
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
+// hostGPUBackend emulates the cuda backend on the CPU: unnormalized forward
+// and inverse FFTs around a multiplication by the uploaded symbol.
+type hostGPUBackend struct {
+	shape  grid.Shape
+	dim    int
+	fft    [3]*FFTPlan
+	symbol []complex128
+	calls  int
+}
+
+func (b *hostGPUBackend) solve(data []complex128) error {
+	b.calls++
+	for axis := 0; axis < b.dim; axis++ {
+		if err := b.fft[axis].TransformLines(data, b.shape, axis, false); err != nil {
+			return err
+		}
+	}
+
+	for idx := range data {
+		data[idx] *= b.symbol[idx]
+	}
+
+	for axis := b.dim - 1; axis >= 0; axis-- {
+		if err := b.fft[axis].TransformLines(data, b.shape, axis, true); err != nil {
+			return err
+		}
+	}
+
+	// TransformLines normalizes the inverse; cuFFT does not.
+	for idx := range data {
+		data[idx] *= complex(float64(len(data)), 0)
+	}
+
+	return nil
+}
+
+func withHostGPU(t *testing.T) *hostGPUBackend {
+	t.Helper()
+
+	backend := &hostGPUBackend{}
+	saved := newGPUBackend
+	newGPUBackend = func(n [3]int, dim int, symbol []complex128) (gpuBackend, error) {
+		backend.shape = grid.Shape(n)
+		backend.dim = dim
+		backend.symbol = symbol
+		for axis := 0; axis < dim; axis++ {
+			plan, err := NewFFTPlan(n[axis])
+			if err != nil {
+				return nil, err
+			}
+			backend.fft[axis] = plan
+		}
+		return backend, nil
+	}
+	t.Cleanup(func() { newGPUBackend = saved })
+
+	return backend
+}
+
+func TestPlan_GPUBackendMatchesCPU(t *testing.T) {
+	backend := withHostGPU(t)
+
+	n := []int{8, 6, 5}
+	h := []float64{0.1, 0.2, 0.3}
+	bc := []BCType{Periodic, Periodic, Periodic}
+
+	cpu, err := NewHelmholtzPlan(3, n, h, bc, 0, WithNullspace(NullspaceSubtractMean))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+	gpu, err := NewHelmholtzPlan(3, n, h, bc, 0, WithNullspace(NullspaceSubtractMean), WithGPU())
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan with WithGPU failed: %v", err)
+	}
+
+	rhs := make([]float64, 8*6*5)
+	for idx := range rhs {
+		rhs[idx] = math.Sin(0.3*float64(idx)) + 1
+	}
+
+	want := make([]float64, len(rhs))
+	got := make([]float64, len(rhs))
+	if err := cpu.Solve(want, rhs); err != nil {
+		t.Fatalf("CPU Solve failed: %v", err)
+	}
+	if err := gpu.Solve(got, rhs); err != nil {
+		t.Fatalf("GPU Solve failed: %v", err)
+	}
+
+	if backend.calls != 1 {
+		t.Fatalf("backend called %d times, want 1", backend.calls)
+	}
+	for idx := range want {
+		if diff := math.Abs(got[idx] - want[idx]); diff > fftTol {
+			t.Fatalf("index %d differs by %g", idx, diff)
+		}
+	}
+
+	// A per-call filter falls back to the CPU path.
+	if err := gpu.Solve(got, rhs, WithFilter(TwoThirdsFilter())); err != nil {
+		t.Fatalf("filtered Solve failed: %v", err)
+	}
+	if backend.calls != 1 {
+		t.Fatalf("filtered solve used the GPU backend")
+	}
+}
+
+func TestPlan_GPUBackendRejectsUnsupportedPlans(t *testing.T) {
+	withHostGPU(t)
+
+	var vErr *ValidationError
+	if _, err := NewPlan(2, []int{4, 4}, []float64{1, 1}, []BCType{Periodic, Dirichlet}, WithGPU()); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for Dirichlet axis, got %v", err)
+	}
+
+	if _, err := NewSlabPlan([]int{4, 4, 2}, []float64{1, 1}, []BCType{Periodic, Periodic}, 1, WithGPU()); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for slab plan, got %v", err)
+	}
+
+	lambda := 2 - 2*math.Cos(2*math.Pi/8)
+	if _, err := NewHelmholtzPlan(1, []int{8}, []float64{1}, []BCType{Periodic}, -lambda, WithGPU()); !errors.Is(err, ErrResonant) {
+		t.Fatalf("expected ErrResonant, got %v", err)
+	}
+}
+
+type failingGPUBackend struct{ err error }
+
+func (b failingGPUBackend) solve([]complex128) error { return b.err }
+
+func TestPlan_GPUBackendFailureIsTransformError(t *testing.T) {
+	deviceErr := errors.New("device lost")
+	saved := newGPUBackend
+	newGPUBackend = func([3]int, int, []complex128) (gpuBackend, error) {
+		return failingGPUBackend{deviceErr}, nil
+	}
+	t.Cleanup(func() { newGPUBackend = saved })
+
+	plan, err := NewPlan(2, []int{4, 6}, []float64{1, 1}, []BCType{Periodic, Periodic},
+		WithNullspace(NullspaceSubtractMean), WithGPU())
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	buf := make([]float64, 24)
+	err = plan.SolveInPlace(buf)
+
+	var tErr *TransformError
+	if !errors.As(err, &tErr) || !errors.Is(err, deviceErr) {
+		t.Fatalf("expected TransformError wrapping the device error, got %v", err)
+	}
+	if tErr.Stage != "forward" || tErr.Transform != "GPU" || tErr.Axis != -1 {
+		t.Errorf("TransformError = %+v", tErr)
+	}
+	if got, want := err.Error(), "forward GPU of 4x6 grid: device lost"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package poisson
+
+import "slices"
+
+// SymbolFactors is a compact, separable description of the inverse symbol
+// (the discrete Green's function in spectral space) of a Plan, for tools
+// that apply the same solve in their own pipelines, e.g. GPU shaders. Mode
+// (i, j, k) of the plan's transforms is multiplied by
+//
+//	1 / (Alpha[a] + Scale * (Eigenvalues[0][i] + Eigenvalues[1][j] + Eigenvalues[2][k])^Power)
+//
+// where a is 0, or k for layered plans with one shift per slab, the power is
+// applied only for Power != 1 (by repeated squaring), and
+// eigenvalues of untransformed axes count as 0. If DropZeroMode is set,
+// modes with index 0 on every transformed axis whose denominator is exactly
+// 0 are set to zero instead (the nullspace of periodic and Neumann
+// problems). For an isotropic Stencil the sum of the eigenvalues is
+// replaced by the stencil's combination of them, whose weights depend on
+// Spacing (see Stencil). Spectral
+// filters and resonance regularization are not part of the factors; use
+// Plan.InverseSymbol for plans with either.
+type SymbolFactors struct {
+	// Dim is the number of transformed axes.
+	Dim int `json:"dim"`
+
+	// Shape holds the number of modes per axis: 1 for unused axes and the
+	// slab count on axis 2 of slab plans.
+	Shape [3]int `json:"shape"`
+
+	// Transforms names the transform of each transformed axis: "fft"
+	// (complex DFT, unnormalized forward, 1/n inverse), "dst1" (DST-I) or
+	// "dct2" (DCT-II), with the conventions of the package transforms.
+	Transforms []string `json:"transforms"`
+
+	// Eigenvalues holds λ per transformed axis in spectral index order, with
+	// per-axis coefficients folded in.
+	Eigenvalues [][]float64 `json:"eigenvalues"`
+
+	// Alpha holds the shift: one value, or one per slab for layered plans.
+	Alpha []float64 `json:"alpha"`
+
+	// Scale and Power describe hyperdiffusion symbols; 1 and 1 otherwise.
+	Scale float64 `json:"scale"`
+	Power int     `json:"power"`
+
+	// DropZeroMode reports that the boundary conditions have a nullspace.
+	DropZeroMode bool `json:"drop_zero_mode"`
+
+	// Stencil is the plan's Laplacian stencil; the default StandardStencil
+	// is omitted from JSON.
+	Stencil Stencil `json:"stencil,omitempty"`
+
+	// Spacing holds the grid spacing per transformed axis for isotropic
+	// stencils, and is nil for the standard one.
+	Spacing []float64 `json:"spacing,omitempty"`
+}
+
+// SymbolFactors returns the separable description of the plan's inverse
+// symbol. The slices are copies.
+func (p *Plan) SymbolFactors() SymbolFactors {
+	f := SymbolFactors{
+		Dim:          p.dim,
+		Shape:        p.n,
+		Alpha:        []float64{p.alpha},
+		Scale:        p.scale,
+		Power:        p.power,
+		DropZeroMode: p.bcNullspace(),
+		Stencil:      p.opts.Stencil,
+	}
+
+	if p.sliceAlpha != nil {
+		f.Alpha = slices.Clone(p.sliceAlpha)
+	}
+
+	if p.opts.Stencil != StandardStencil {
+		f.Spacing = slices.Clone(p.h[:p.dim])
+	}
+
+	for axis := 0; axis < p.dim; axis++ {
+		f.Eigenvalues = append(f.Eigenvalues, slices.Clone(p.eig[axis]))
+		switch p.bc[axis] {
+		case Dirichlet:
+			f.Transforms = append(f.Transforms, "dst1")
+		case Neumann:
+			f.Transforms = append(f.Transforms, "dct2")
+		default:
+			f.Transforms = append(f.Transforms, "fft")
+		}
+	}
+
+	return f
+}
+
+// InverseSymbol returns the factor Solve applies to every spectral
+// coefficient, as a row-major table over the plan's modes (see
+// SymbolFactors for the index order): 1/(alpha + λ) with the plan's spectral
+// filter, resonance handling and hyperdiffusion power applied, and 0 for a
+// dropped zero mode. It fails with a *ResonantError where Solve would.
+//
+// The table is computed by the same code as Solve. Solve divides by
+// alpha + λ on its default path, so multiplying by the table reproduces
+// Solve to round-off, and bit for bit on plans created with
+// WithPrecomputedInverse, which multiply by exactly these values.
+func (p *Plan) InverseSymbol() ([]float64, error) {
+	if p.inverse != nil && p.opts.SpectralFilter == nil {
+		return slices.Clone(p.inverse), nil
+	}
+
+	table := make([]float64, p.size())
+	for idx := range table {
+		inv, err := p.modeInverse(idx, p.opts.SpectralFilter)
+		if err != nil {
+			return nil, err
+		}
+		table[idx] = inv
+	}
+
+	return table, nil
+}
@@ -0,0 +1,193 @@
+package poisson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/cmplx"
+	"reflect"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const greenTol = 1e-12
+
+// symbolFromFactors evaluates the formula documented on SymbolFactors.
+func symbolFromFactors(f poisson.SymbolFactors, i, j, k int) float64 {
+	lambda := 0.0
+	for axis, idx := range [3]int{i, j, k} {
+		if axis < len(f.Eigenvalues) {
+			lambda += f.Eigenvalues[axis][idx]
+		}
+	}
+	if f.Power != 1 {
+		lambda = f.Scale * math.Pow(lambda, float64(f.Power))
+	}
+
+	alpha := f.Alpha[0]
+	if len(f.Alpha) > 1 {
+		alpha = f.Alpha[k]
+	}
+
+	zero := i == 0 && (f.Dim < 2 || j == 0) && (f.Dim < 3 || k == 0)
+	if f.DropZeroMode && zero && alpha+lambda == 0 {
+		return 0
+	}
+
+	return 1 / (alpha + lambda)
+}
+
+func TestPlan_SymbolFactorsReproduceInverseSymbol(t *testing.T) {
+	helmholtz, err := poisson.NewHelmholtzPlan(3, []int{8, 6, 4}, []float64{0.1, 0.2, 0.3},
+		[]poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}, 0.5)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	neumann, err := poisson.NewPlan(2, []int{8, 4}, []float64{0.1, 0.2},
+		[]poisson.BCType{poisson.Neumann, poisson.Periodic})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	hyper, err := poisson.NewHyperdiffusionPlan(2, []int{8, 8}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Periodic, poisson.Periodic}, 1e-3, 2)
+	if err != nil {
+		t.Fatalf("NewHyperdiffusionPlan failed: %v", err)
+	}
+
+	layered, err := poisson.NewLayeredHelmholtzPlan([]int{8, 4, 3}, []float64{0.1, 0.2},
+		[]poisson.BCType{poisson.Periodic, poisson.Periodic}, []float64{0, 1, 2})
+	if err != nil {
+		t.Fatalf("NewLayeredHelmholtzPlan failed: %v", err)
+	}
+
+	for name, plan := range map[string]*poisson.Plan{
+		"helmholtz": helmholtz,
+		"neumann":   neumann,
+		"hyper":     hyper,
+		"layered":   layered,
+	} {
+		table, err := plan.InverseSymbol()
+		if err != nil {
+			t.Fatalf("%s: InverseSymbol failed: %v", name, err)
+		}
+
+		f := plan.SymbolFactors()
+		if len(f.Transforms) != f.Dim || len(f.Eigenvalues) != f.Dim {
+			t.Fatalf("%s: %d transforms and %d eigenvalue tables for dim %d",
+				name, len(f.Transforms), len(f.Eigenvalues), f.Dim)
+		}
+		if got := f.Shape[0] * f.Shape[1] * f.Shape[2]; got != len(table) {
+			t.Fatalf("%s: shape %v has %d modes, table has %d", name, f.Shape, got, len(table))
+		}
+
+		idx := 0
+		for i := range f.Shape[0] {
+			for j := range f.Shape[1] {
+				for k := range f.Shape[2] {
+					want := table[idx]
+					got := symbolFromFactors(f, i, j, k)
+					if math.Abs(got-want) > greenTol*math.Max(1, math.Abs(want)) {
+						t.Fatalf("%s: mode (%d,%d,%d) = %g, table has %g", name, i, j, k, got, want)
+					}
+					idx++
+				}
+			}
+		}
+	}
+}
+
+func TestPlan_InverseSymbolReproducesSolve(t *testing.T) {
+	const n = 16
+	h := 1.0 / n
+
+	plan, err := poisson.NewHelmholtzPlan(1, []int{n}, []float64{h},
+		[]poisson.BCType{poisson.Periodic}, 2, poisson.WithPrecomputedInverse())
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, n)
+	for i := range rhs {
+		x := float64(i) * h
+		rhs[i] = math.Sin(2*math.Pi*x) + 0.3*math.Cos(6*math.Pi*x) + 0.1
+	}
+
+	want := make([]float64, n)
+	if err := plan.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	table, err := plan.InverseSymbol()
+	if err != nil {
+		t.Fatalf("InverseSymbol failed: %v", err)
+	}
+
+	f := plan.SymbolFactors()
+	if f.Transforms[0] != "fft" {
+		t.Fatalf("transform = %q, want fft", f.Transforms[0])
+	}
+
+	// Apply the symbol with a naive DFT pair, as an external tool would.
+	coeff := make([]complex128, n)
+	for k := range coeff {
+		for j, v := range rhs {
+			coeff[k] += complex(v, 0) * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/n))
+		}
+		coeff[k] *= complex(table[k], 0)
+	}
+
+	for j := range n {
+		var u complex128
+		for k, c := range coeff {
+			u += c * cmplx.Exp(complex(0, 2*math.Pi*float64(j*k)/n))
+		}
+		if d := math.Abs(real(u)/n - want[j]); d > greenTol {
+			t.Fatalf("u[%d] differs from Solve by %g", j, d)
+		}
+	}
+}
+
+func TestPlan_InverseSymbolResonant(t *testing.T) {
+	eig := fd.EigenvaluesDirichlet(8, 0.1)
+
+	plan, err := poisson.NewHelmholtzPlan(1, []int{8}, []float64{0.1},
+		[]poisson.BCType{poisson.Dirichlet}, -eig[3])
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	if _, err := plan.InverseSymbol(); !errors.Is(err, poisson.ErrResonant) {
+		t.Fatalf("expected ErrResonant, got %v", err)
+	}
+
+	if f := plan.SymbolFactors(); f.Transforms[0] != "dst1" || f.Alpha[0] != -eig[3] {
+		t.Fatalf("factors = %+v", f)
+	}
+}
+
+func TestSymbolFactors_JSONRoundTrip(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{8, 4}, []float64{0.1, 0.2},
+		[]poisson.BCType{poisson.Neumann, poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	f := plan.SymbolFactors()
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var got poisson.SymbolFactors
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, f) {
+		t.Fatalf("round trip = %+v, want %+v", got, f)
+	}
+}
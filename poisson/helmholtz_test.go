@@ -68,6 +68,37 @@ func TestHelmholtzPlan1D_NegativeAlphaResonant(t *testing.T) {
 	}
 }
 
+func TestHelmholtzPlan2D_ResonantErrorReportsMode(t *testing.T) {
+	nx, ny := 12, 10
+	hx, hy := 0.1, 0.2
+	eigX := fd.EigenvaluesDirichlet(nx, hx)
+	eigY := fd.EigenvaluesNeumann(ny, hy)
+	alpha := -(eigX[2] + eigY[3])
+
+	plan, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, []float64{hx, hy},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann}, alpha, poisson.WithWorkers(1))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	buf := make([]float64, nx*ny)
+	err = plan.SolveInPlace(buf)
+
+	var rErr *poisson.ResonantError
+	if !errors.As(err, &rErr) {
+		t.Fatalf("expected ResonantError, got %v", err)
+	}
+	if !errors.Is(err, poisson.ErrResonant) {
+		t.Fatalf("ResonantError must wrap ErrResonant")
+	}
+	if rErr.Mode != [3]int{2, 3, 0} {
+		t.Fatalf("Mode = %v, want [2 3 0]", rErr.Mode)
+	}
+	if rErr.Alpha+rErr.Eigenvalue != 0 {
+		t.Fatalf("Alpha + Eigenvalue = %g, want 0", rErr.Alpha+rErr.Eigenvalue)
+	}
+}
+
 func TestHelmholtzPlan2D_PositiveAlpha(t *testing.T) {
 	nx, ny := 48, 36
 	hx := 1.0 / float64(nx+1)
@@ -155,3 +186,171 @@ func TestHelmholtzPlan3D_PositiveAlpha(t *testing.T) {
 		t.Fatalf("max error %g exceeds tol %g", max, helmholtz3dTol)
 	}
 }
+
+func TestAnisotropicHelmholtzPlan2D(t *testing.T) {
+	nx, ny := 20, 14
+	hx, hy := 0.05, 0.08
+	alpha := 0.5
+	cx, cy := 3.0, 0.25
+
+	plan, err := poisson.NewAnisotropicHelmholtzPlan(
+		2,
+		[]int{nx, ny},
+		[]float64{hx, hy},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann},
+		alpha,
+		[]float64{cx, cy},
+	)
+	if err != nil {
+		t.Fatalf("NewAnisotropicHelmholtzPlan failed: %v", err)
+	}
+
+	u := make([]float64, nx*ny)
+	for i := range u {
+		u[i] = math.Sin(float64(i)*0.3) + 0.1*float64(i%ny)
+	}
+
+	rhs := make([]float64, nx*ny)
+	col := make([]float64, nx)
+	colLap := make([]float64, nx)
+	for j := range ny {
+		for i := range nx {
+			col[i] = u[i*ny+j]
+		}
+		fd.Apply1D(colLap, col, hx, poisson.Dirichlet)
+		for i := range nx {
+			rhs[i*ny+j] = alpha*u[i*ny+j] + cx*colLap[i]
+		}
+	}
+
+	rowLap := make([]float64, ny)
+	for i := range nx {
+		fd.Apply1D(rowLap, u[i*ny:(i+1)*ny], hy, poisson.Neumann)
+		for j := range ny {
+			rhs[i*ny+j] += cy * rowLap[j]
+		}
+	}
+
+	got := make([]float64, nx*ny)
+	if err := plan.Solve(got, rhs, poisson.WithVerify(1e-10)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if max := maxAbsDiff(got, u); max > helmholtz2dTol {
+		t.Fatalf("max error %g exceeds tol %g", max, helmholtz2dTol)
+	}
+}
+
+func TestAnisotropicHelmholtzPlan_InvalidCoefficients(t *testing.T) {
+	n := []int{8, 8}
+	h := []float64{1, 1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}
+
+	for _, coeff := range [][]float64{{1}, {1, -1}, {math.NaN(), 1}} {
+		_, err := poisson.NewAnisotropicHelmholtzPlan(2, n, h, bc, 1, coeff)
+
+		var vErr *poisson.ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("coeff %v: expected ValidationError, got %v", coeff, err)
+		}
+	}
+}
+
+func TestAnisotropicHelmholtzPlan_ZeroCoefficientNeedsAlpha(t *testing.T) {
+	n := []int{8, 8}
+	h := []float64{1, 1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}
+
+	_, err := poisson.NewAnisotropicHelmholtzPlan(2, n, h, bc, 0, []float64{1, 0})
+
+	var vErr *poisson.ValidationError
+	if !errors.As(err, &vErr) || vErr.Field != "coeff" {
+		t.Fatalf("expected ValidationError for coeff, got %v", err)
+	}
+
+	if _, err := poisson.NewAnisotropicHelmholtzPlan(2, n, h, bc, 0.5, []float64{1, 0}); err != nil {
+		t.Fatalf("zero coefficient with alpha 0.5: %v", err)
+	}
+}
+
+func TestHelmholtzPlan1D_NearResonance(t *testing.T) {
+	n := 16
+	h := 1.0 / float64(n+1)
+	eig := fd.EigenvaluesDirichlet(n, h)
+	alpha := -eig[0] * (1 + 1e-10)
+
+	mode := func(m int) []float64 {
+		v := make([]float64, n)
+		for i := range v {
+			v[i] = math.Sin(math.Pi * float64(m*(i+1)) / float64(n+1))
+		}
+		return v
+	}
+
+	mode1 := mode(1)
+	mode3 := mode(3)
+	rhs := make([]float64, n)
+	for i := range rhs {
+		rhs[i] = mode1[i] + mode3[i]
+	}
+
+	dst := make([]float64, n)
+	bc := []poisson.BCType{poisson.Dirichlet}
+
+	strict, err := poisson.NewHelmholtzPlan(1, []int{n}, []float64{h}, bc, alpha, poisson.WithResonanceTolerance(1e-8))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+	if err := strict.Solve(dst, rhs); !errors.Is(err, poisson.ErrResonant) {
+		t.Fatalf("expected ErrResonant, got %v", err)
+	}
+
+	pinv, err := poisson.NewHelmholtzPlan(1, []int{n}, []float64{h}, bc, alpha,
+		poisson.WithResonanceTolerance(1e-8), poisson.WithResonance(poisson.ResonancePseudoInverse))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+	if err := pinv.Solve(dst, rhs); err != nil {
+		t.Fatalf("pseudo-inverse Solve failed: %v", err)
+	}
+	for i := range dst {
+		want := mode3[i] / (alpha + eig[2])
+		if math.Abs(dst[i]-want) > helmholtz1dTol {
+			t.Fatalf("pseudo-inverse i=%d: got %g, want %g", i, dst[i], want)
+		}
+	}
+
+	mu := 1.0
+	tikhonov, err := poisson.NewHelmholtzPlan(1, []int{n}, []float64{h}, bc, alpha,
+		poisson.WithResonanceTolerance(1e-8), poisson.WithTikhonov(mu))
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+	if err := tikhonov.Solve(dst, rhs); err != nil {
+		t.Fatalf("Tikhonov Solve failed: %v", err)
+	}
+	d := alpha + eig[0]
+	for i := range dst {
+		want := mode1[i]*d/(d*d+mu*mu) + mode3[i]/(alpha+eig[2])
+		if math.Abs(dst[i]-want) > helmholtz1dTol {
+			t.Fatalf("Tikhonov i=%d: got %g, want %g", i, dst[i], want)
+		}
+	}
+}
+
+func TestHelmholtzPlan_InvalidResonanceOptions(t *testing.T) {
+	opts := [][]poisson.Option{
+		{poisson.WithResonanceTolerance(-1)},
+		{poisson.WithTikhonov(0)},
+		{poisson.WithResonance(poisson.ResonanceHandling(7))},
+	}
+
+	for _, o := range opts {
+		_, err := poisson.NewHelmholtzPlan(1, []int{8}, []float64{1}, []poisson.BCType{poisson.Dirichlet}, -1, o...)
+
+		var vErr *poisson.ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError, got %v", err)
+		}
+	}
+}
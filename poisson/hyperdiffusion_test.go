@@ -0,0 +1,96 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const hyperdiffusionTol = 1e-10
+
+func TestHyperdiffusionPlan1D_PeriodicMode(t *testing.T) {
+	n := 32
+	h := 1.0 / float64(n)
+	nu := 1e-6
+
+	eig := fd.EigenvaluesPeriodic(n, h)
+
+	for _, order := range []int{2, 3, 4} {
+		plan, err := poisson.NewHyperdiffusionPlan(1, []int{n}, []float64{h}, []poisson.BCType{poisson.Periodic}, nu, order)
+		if err != nil {
+			t.Fatalf("NewHyperdiffusionPlan(p=%d) failed: %v", order, err)
+		}
+
+		k := 5
+		rhs := make([]float64, n)
+		for i := range rhs {
+			rhs[i] = math.Cos(2 * math.Pi * float64(k*i) / float64(n))
+		}
+
+		got := make([]float64, n)
+		if err := plan.Solve(got, rhs); err != nil {
+			t.Fatalf("Solve(p=%d) failed: %v", order, err)
+		}
+
+		factor := 1 + nu*math.Pow(eig[k], float64(order))
+		for i := range got {
+			if want := rhs[i] / factor; math.Abs(got[i]-want) > hyperdiffusionTol {
+				t.Fatalf("p=%d i=%d: got %g, want %g", order, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestHyperdiffusionPlan2D_Verify(t *testing.T) {
+	nx, ny := 12, 10
+	plan, err := poisson.NewHyperdiffusionPlan(
+		2,
+		[]int{nx, ny},
+		[]float64{0.5, 0.4},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann},
+		0.01,
+		2,
+	)
+	if err != nil {
+		t.Fatalf("NewHyperdiffusionPlan failed: %v", err)
+	}
+
+	buf := make([]float64, nx*ny)
+	for i := range buf {
+		buf[i] = math.Sin(float64(i) * 0.7)
+	}
+
+	if err := plan.SolveInPlace(buf, poisson.WithVerify(1e-10)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+}
+
+func TestHyperdiffusionPlan_InvalidInputs(t *testing.T) {
+	n := []int{8}
+	h := []float64{1}
+	bc := []poisson.BCType{poisson.Periodic}
+
+	tests := []struct {
+		name  string
+		nu    float64
+		order int
+	}{
+		{"OrderOne", 1, 1},
+		{"NegativeNu", -1, 2},
+		{"InfNu", math.Inf(1), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := poisson.NewHyperdiffusionPlan(1, n, h, bc, tt.nu, tt.order)
+
+			var vErr *poisson.ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
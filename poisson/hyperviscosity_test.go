@@ -0,0 +1,96 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const hyperviscosityTol = 1e-12
+
+func TestHyperviscosityFilter_AnisotropicDamping(t *testing.T) {
+	nx, ny := 16, 12
+	hx, hy := 1.0/float64(nx), 1.0/float64(ny)
+	dt := 1e-3
+	nu := []float64{1e-4, 5e-4}
+	order := 2
+
+	plan, err := poisson.NewPlan(
+		2,
+		[]int{nx, ny},
+		[]float64{hx, hy},
+		[]poisson.BCType{poisson.Periodic, poisson.Neumann},
+	)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	filter, err := poisson.NewHyperviscosityFilter(plan, dt, nu, order)
+	if err != nil {
+		t.Fatalf("NewHyperviscosityFilter failed: %v", err)
+	}
+
+	kx, my := 3, 4
+	u := make([]float64, nx*ny)
+	for i := range nx {
+		for j := range ny {
+			u[i*ny+j] = 2 + math.Cos(2*math.Pi*float64(kx*i)/float64(nx))*
+				math.Cos(math.Pi*float64(my)*(float64(j)+0.5)/float64(ny))
+		}
+	}
+
+	got := make([]float64, nx*ny)
+	if err := filter.Apply(got, u); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	lx := fd.EigenvaluesPeriodic(nx, hx)[kx]
+	ly := fd.EigenvaluesNeumann(ny, hy)[my]
+	damp := math.Exp(-dt * (nu[0]*lx*lx + nu[1]*ly*ly))
+	for idx := range got {
+		want := 2 + (u[idx]-2)*damp
+		if math.Abs(got[idx]-want) > hyperviscosityTol {
+			t.Fatalf("idx %d: got %g, want %g", idx, got[idx], want)
+		}
+	}
+
+	if err := filter.Apply(u, u); err != nil {
+		t.Fatalf("in-place Apply failed: %v", err)
+	}
+	if diff := maxAbsDiff(u, got); diff > hyperviscosityTol {
+		t.Fatalf("in-place result differs by %g", diff)
+	}
+}
+
+func TestHyperviscosityFilter_InvalidInputs(t *testing.T) {
+	plan, err := poisson.NewPlan(1, []int{8}, []float64{1}, []poisson.BCType{poisson.Periodic})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		dt    float64
+		nu    []float64
+		order int
+	}{
+		{"ZeroOrder", 1, []float64{1}, 0},
+		{"NegativeDt", -1, []float64{1}, 2},
+		{"WrongNuLength", 1, []float64{1, 1}, 2},
+		{"NegativeNu", 1, []float64{-1}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := poisson.NewHyperviscosityFilter(plan, tt.dt, tt.nu, tt.order)
+
+			var vErr *poisson.ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
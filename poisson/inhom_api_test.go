@@ -1,6 +1,7 @@
 package poisson_test
 
 import (
+	"errors"
 	"math"
 	"testing"
 
@@ -247,3 +248,278 @@ func applyInhomDirichletNeumann3D(dst, src []float64, shape grid.Shape, hx, hy,
 		}
 	}
 }
+
+func TestPlan2D_SolveWithBCAt_MatchesValues(t *testing.T) {
+	nx, ny := 16, 12
+	hx := 1.0 / float64(nx+1)
+	hy := 1.0 / float64(ny)
+
+	plan, err := poisson.NewPlan(
+		2,
+		[]int{nx, ny},
+		[]float64{hx, hy},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann},
+	)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, nx*ny)
+	for i := range rhs {
+		rhs[i] = math.Sin(float64(i) * 0.1)
+	}
+
+	xLow := func(time float64, idx int) float64 { return math.Cos(time) * float64(idx) * hy }
+	yHigh := func(time float64, idx int) float64 { return time * float64(idx) * hx }
+	funcBC := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Func: xLow},
+		{Face: poisson.YHigh, Type: poisson.Neumann, Func: yHigh},
+	}
+
+	got := make([]float64, nx*ny)
+	want := make([]float64, nx*ny)
+	for _, time := range []float64{0, 0.5, 1.25} {
+		xLowValues := make([]float64, ny)
+		for j := range xLowValues {
+			xLowValues[j] = xLow(time, j)
+		}
+		yHighValues := make([]float64, nx)
+		for i := range yHighValues {
+			yHighValues[i] = yHigh(time, i)
+		}
+		valueBC := poisson.BoundaryConditions{
+			{Face: poisson.XLow, Type: poisson.Dirichlet, Values: xLowValues},
+			{Face: poisson.YHigh, Type: poisson.Neumann, Values: yHighValues},
+		}
+
+		if err := plan.SolveWithBCAt(time, got, rhs, funcBC); err != nil {
+			t.Fatalf("SolveWithBCAt(%v) failed: %v", time, err)
+		}
+		if err := plan.SolveWithBC(want, rhs, valueBC); err != nil {
+			t.Fatalf("SolveWithBC failed: %v", err)
+		}
+
+		if max := maxAbsDiff(got, want); max > inhomAPITol {
+			t.Fatalf("t=%v: max diff %g exceeds tol %g", time, max, inhomAPITol)
+		}
+	}
+}
+
+func TestPlan_SolveWithBCAt_RejectsValuesAndFunc(t *testing.T) {
+	n := 8
+	plan, err := poisson.NewPlan(1, []int{n}, []float64{1}, []poisson.BCType{poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	bc := poisson.BoundaryConditions{{
+		Face:   poisson.XLow,
+		Type:   poisson.Dirichlet,
+		Values: []float64{1},
+		Func:   func(float64, int) float64 { return 1 },
+	}}
+
+	buf := make([]float64, n)
+	var vErr *poisson.ValidationError
+	if err := plan.SolveWithBCAt(1, buf, buf, bc); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestPlan_SolveBoundary_LinearPotential(t *testing.T) {
+	nx, ny, nz := 15, 8, 6
+	hx := 1.0 / float64(nx+1)
+
+	plan, err := poisson.NewPlan(
+		3,
+		[]int{nx, ny, nz},
+		[]float64{hx, 0.125, 0.2},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Periodic, poisson.Periodic},
+	)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	// u = 1 + 2x is discretely harmonic for x-face Dirichlet data 1 and 3.
+	low := make([]float64, ny*nz)
+	high := make([]float64, ny*nz)
+	for i := range low {
+		low[i] = 1
+		high[i] = 3
+	}
+
+	bc := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Values: low},
+		{Face: poisson.XHigh, Type: poisson.Dirichlet, Values: high},
+	}
+
+	got := make([]float64, nx*ny*nz)
+	if err := plan.SolveBoundary(got, bc); err != nil {
+		t.Fatalf("SolveBoundary failed: %v", err)
+	}
+
+	want := make([]float64, len(got))
+	for idx := range want {
+		x := float64(idx/(ny*nz)+1) * hx
+		want[idx] = 1 + 2*x
+	}
+
+	if max := maxAbsDiff(got, want); max > inhomAPITol {
+		t.Fatalf("max error %g exceeds tol %g", max, inhomAPITol)
+	}
+}
+
+func TestPlan_SolveBoundary_MatchesSolveWithBC(t *testing.T) {
+	nx, ny, nz := 12, 10, 8
+
+	face := func(n int, scale float64) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = scale * math.Sin(0.7*float64(i)+scale)
+		}
+		return values
+	}
+
+	cases := []struct {
+		name string
+		bc   []poisson.BCType
+		data poisson.BoundaryConditions
+		opts []poisson.Option
+	}{
+		{
+			name: "x faces",
+			bc:   []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet, poisson.Periodic},
+			data: poisson.BoundaryConditions{
+				{Face: poisson.XLow, Type: poisson.Dirichlet, Values: face(ny*nz, 1)},
+				{Face: poisson.XHigh, Type: poisson.Dirichlet, Values: face(ny*nz, -2)},
+			},
+		},
+		{
+			name: "all faces",
+			bc:   []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet, poisson.Neumann},
+			data: poisson.BoundaryConditions{
+				{Face: poisson.XLow, Type: poisson.Dirichlet, Values: face(ny*nz, 1)},
+				{Face: poisson.YHigh, Type: poisson.Dirichlet, Values: face(nx*nz, 0.5)},
+				{Face: poisson.ZLow, Type: poisson.Neumann, Values: face(nx*ny, 3)},
+			},
+		},
+		{
+			name: "flux balance",
+			bc:   []poisson.BCType{poisson.Neumann, poisson.Periodic, poisson.Periodic},
+			data: poisson.BoundaryConditions{
+				{Face: poisson.XLow, Type: poisson.Neumann, Values: face(ny*nz, 1)},
+			},
+			opts: []poisson.Option{poisson.WithFluxBalance()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := poisson.NewPlan(3, []int{nx, ny, nz}, []float64{0.1, 0.1, 0.1}, tc.bc, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewPlan failed: %v", err)
+			}
+
+			var wantStats, gotStats poisson.SolveStats
+			want := make([]float64, nx*ny*nz)
+			if err := plan.SolveWithBC(want, make([]float64, len(want)), tc.data, poisson.WithStats(&wantStats)); err != nil {
+				t.Fatalf("SolveWithBC failed: %v", err)
+			}
+
+			got := make([]float64, len(want))
+			if err := plan.SolveBoundary(got, tc.data, poisson.WithStats(&gotStats)); err != nil {
+				t.Fatalf("SolveBoundary failed: %v", err)
+			}
+
+			if max := maxAbsDiff(got, want); max > 1e-12 {
+				t.Fatalf("max diff %g", max)
+			}
+			if math.Abs(gotStats.FluxAdjustment-wantStats.FluxAdjustment) > 1e-15 {
+				t.Fatalf("FluxAdjustment = %g, want %g", gotStats.FluxAdjustment, wantStats.FluxAdjustment)
+			}
+		})
+	}
+}
+
+func TestPlan_SolveBoundary_Errors(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{8, 8}, []float64{0.1, 0.1}, []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	if err := plan.SolveBoundary(nil, nil); !errors.Is(err, poisson.ErrNilBuffer) {
+		t.Fatalf("nil dst: got %v", err)
+	}
+	if err := plan.SolveBoundary(make([]float64, 63), nil); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("short dst: got %v", err)
+	}
+}
+
+func TestPlan_SolveWithBC_PartialFacesDefaultHomogeneous(t *testing.T) {
+	n := []int{8, 6}
+	plan, err := poisson.NewPlan(2, n, []float64{0.1, 0.2}, []poisson.BCType{poisson.Dirichlet, poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	xLow := make([]float64, n[1])
+	for j := range xLow {
+		xLow[j] = 1 + 0.1*float64(j)
+	}
+
+	rhs := make([]float64, n[0]*n[1])
+	partial := make([]float64, len(rhs))
+	full := make([]float64, len(rhs))
+
+	if err := plan.SolveWithBC(partial, rhs, poisson.BoundaryConditions{plan.FaceData(poisson.XLow, xLow)}); err != nil {
+		t.Fatalf("SolveWithBC (partial) failed: %v", err)
+	}
+
+	all := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Values: xLow},
+		{Face: poisson.XHigh, Type: poisson.Dirichlet, Values: make([]float64, n[1])},
+		{Face: poisson.YLow, Type: poisson.Neumann, Values: make([]float64, n[0])},
+		{Face: poisson.YHigh, Type: poisson.Neumann, Values: make([]float64, n[0])},
+	}
+	if err := plan.SolveWithBC(full, rhs, all); err != nil {
+		t.Fatalf("SolveWithBC (all faces) failed: %v", err)
+	}
+
+	if d := maxAbsDiff(partial, full); d > inhomAPITol {
+		t.Fatalf("partial faces differ from explicit homogeneous faces by %g", d)
+	}
+}
+
+func TestPlan_SolveWithBC_ValidatesFaceCombination(t *testing.T) {
+	n := []int{8, 6}
+	plan, err := poisson.NewPlan(2, n, []float64{0.1, 0.2}, []poisson.BCType{poisson.Dirichlet, poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	if got := plan.FaceData(poisson.YHigh, nil).Type; got != poisson.Neumann {
+		t.Fatalf("FaceData(YHigh).Type = %v, want Neumann", got)
+	}
+
+	rhs := make([]float64, n[0]*n[1])
+	dst := make([]float64, len(rhs))
+
+	var vErr *poisson.ValidationError
+	dup := poisson.BoundaryConditions{
+		plan.FaceData(poisson.XLow, make([]float64, n[1])),
+		plan.FaceData(poisson.XLow, make([]float64, n[1])),
+	}
+	if err := plan.SolveWithBC(dst, rhs, dup); !errors.As(err, &vErr) || vErr.Field != "Face" {
+		t.Fatalf("duplicate face: expected Face ValidationError, got %v", err)
+	}
+
+	var sErr *poisson.SizeError
+	short := poisson.BoundaryConditions{plan.FaceData(poisson.YLow, make([]float64, n[0]-1))}
+	if err := plan.SolveWithBC(dst, rhs, short); !errors.As(err, &sErr) || sErr.Expected != n[0] {
+		t.Fatalf("short face: expected SizeError, got %v", err)
+	}
+
+	if err := plan.SolveBoundary(dst, dup); !errors.As(err, &vErr) {
+		t.Fatalf("SolveBoundary duplicate face: expected ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package poisson
+
+// DivideKernel divides contiguous lines of spectral coefficients by the
+// operator symbol. Plan uses it for the eigenvalue division of Solve, the
+// innermost loop of every solve; implementations trade portability for
+// SIMD throughput.
+//
+// Kernels must be safe for concurrent use on disjoint lines.
+type DivideKernel interface {
+	// Name identifies the kernel, e.g. in benchmarks and DebugDump.
+	Name() string
+
+	// DivideLine sets data[k] /= base + eig[k] for every k. len(eig) equals
+	// len(data); denominators are never zero.
+	DivideLine(data []complex128, base float64, eig []float64)
+}
+
+// GoDivideKernel returns the portable pure-Go kernel.
+func GoDivideKernel() DivideKernel {
+	return goDivideKernel{}
+}
+
+// DefaultDivideKernel returns the fastest kernel supported by the running
+// CPU (AVX2 on amd64), falling back to GoDivideKernel.
+func DefaultDivideKernel() DivideKernel {
+	kernels := DivideKernels()
+	return kernels[len(kernels)-1]
+}
+
+// DivideKernels returns all kernels supported by the running CPU, from the
+// portable Go kernel to the fastest SIMD kernel.
+func DivideKernels() []DivideKernel {
+	return append([]DivideKernel{goDivideKernel{}}, simdDivideKernels()...)
+}
+
+type goDivideKernel struct{}
+
+func (goDivideKernel) Name() string {
+	return "go"
+}
+
+func (goDivideKernel) DivideLine(data []complex128, base float64, eig []float64) {
+	eig = eig[:len(data)]
+	for k, v := range data {
+		denom := base + eig[k]
+		data[k] = complex(real(v)/denom, imag(v)/denom)
+	}
+}
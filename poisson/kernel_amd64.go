@@ -0,0 +1,32 @@
+package poisson
+
+import "golang.org/x/sys/cpu"
+
+func simdDivideKernels() []DivideKernel {
+	if cpu.X86.HasAVX2 {
+		return []DivideKernel{avx2DivideKernel{}}
+	}
+
+	return nil
+}
+
+type avx2DivideKernel struct{}
+
+func (avx2DivideKernel) Name() string {
+	return "avx2"
+}
+
+func (avx2DivideKernel) DivideLine(data []complex128, base float64, eig []float64) {
+	if len(data) == 0 {
+		return
+	}
+
+	eig = eig[:len(data)]
+	divideLineAVX2(&data[0], &eig[0], len(data), base)
+}
+
+// divideLineAVX2 divides n complex values at data by base + eig[k], two
+// complex values per 256-bit vector.
+//
+//go:noescape
+func divideLineAVX2(data *complex128, eig *float64, n int, base float64)
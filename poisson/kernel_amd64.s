@@ -0,0 +1,55 @@
+#include "textflag.h"
+
+// func divideLineAVX2(data *complex128, eig *float64, n int, base float64)
+TEXT ·divideLineAVX2(SB), NOSPLIT, $0-32
+	MOVQ         data+0(FP), DI
+	MOVQ         eig+8(FP), SI
+	MOVQ         n+16(FP), CX
+	VBROADCASTSD base+24(FP), Y2
+
+loop4:
+	CMPQ    CX, $4
+	JLT     loop2
+	VMOVUPD (SI), X0
+	VMOVUPD 16(SI), X3
+	VPERMPD $0x50, Y0, Y0 // [e0 e0 e1 e1]
+	VPERMPD $0x50, Y3, Y3 // [e2 e2 e3 e3]
+	VADDPD  Y2, Y0, Y0
+	VADDPD  Y2, Y3, Y3
+	VMOVUPD (DI), Y1
+	VMOVUPD 32(DI), Y4
+	VDIVPD  Y0, Y1, Y1
+	VDIVPD  Y3, Y4, Y4
+	VMOVUPD Y1, (DI)
+	VMOVUPD Y4, 32(DI)
+	ADDQ    $32, SI
+	ADDQ    $64, DI
+	SUBQ    $4, CX
+	JMP     loop4
+
+loop2:
+	CMPQ    CX, $2
+	JLT     tail
+	VMOVUPD (SI), X0
+	VPERMPD $0x50, Y0, Y0
+	VADDPD  Y2, Y0, Y0
+	VMOVUPD (DI), Y1
+	VDIVPD  Y0, Y1, Y1
+	VMOVUPD Y1, (DI)
+	ADDQ    $16, SI
+	ADDQ    $32, DI
+	SUBQ    $2, CX
+
+tail:
+	TESTQ    CX, CX
+	JEQ      done
+	VMOVSD   (SI), X0
+	VADDSD   X2, X0, X0
+	VMOVDDUP X0, X0
+	VMOVUPD  (DI), X1
+	VDIVPD   X0, X1, X1
+	VMOVUPD  X1, (DI)
+
+done:
+	VZEROUPPER
+	RET
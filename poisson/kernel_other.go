@@ -0,0 +1,7 @@
+//go:build !amd64
+
+package poisson
+
+func simdDivideKernels() []DivideKernel {
+	return nil
+}
@@ -0,0 +1,192 @@
+package poisson_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const kernelTol = 1e-15
+
+func TestDivideKernels_MatchGoKernel(t *testing.T) {
+	ref := poisson.GoDivideKernel()
+
+	for _, kernel := range poisson.DivideKernels() {
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 7, 8, 33} {
+			t.Run(fmt.Sprintf("%s/%d", kernel.Name(), n), func(t *testing.T) {
+				eig := make([]float64, n)
+				want := make([]complex128, n)
+				for k := range n {
+					eig[k] = 0.5 + float64(k*k)
+					want[k] = complex(math.Sin(float64(k)), math.Cos(float64(3*k)))
+				}
+				got := append([]complex128(nil), want...)
+
+				ref.DivideLine(want, 1.25, eig)
+				kernel.DivideLine(got, 1.25, eig)
+
+				for k := range want {
+					if d := cmplxAbsDiff(got[k], want[k]); d > kernelTol {
+						t.Fatalf("k=%d: got %v, want %v", k, got[k], want[k])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestPlan_DivideKernelsAgree(t *testing.T) {
+	n := []int{12, 10, 9}
+	h := []float64{0.1, 0.2, 0.3}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Neumann, poisson.Neumann}
+
+	rhs := make([]float64, 12*10*9)
+	for i := range rhs {
+		rhs[i] = math.Sin(0.1 * float64(i*i))
+	}
+
+	var want []float64
+	for _, kernel := range poisson.DivideKernels() {
+		plan, err := poisson.NewPlan(3, n, h, bc,
+			poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithDivideKernel(kernel))
+		if err != nil {
+			t.Fatalf("NewPlan failed: %v", err)
+		}
+
+		got := make([]float64, len(rhs))
+		if err := plan.Solve(got, rhs, poisson.WithVerify(1e-9)); err != nil {
+			t.Fatalf("%s: Solve failed: %v", kernel.Name(), err)
+		}
+
+		if want == nil {
+			want = got
+			continue
+		}
+		if diff := maxAbsDiff(got, want); diff > 1e-12 {
+			t.Fatalf("%s differs from go kernel by %g", kernel.Name(), diff)
+		}
+	}
+}
+
+func BenchmarkDivideKernel(b *testing.B) {
+	const n = 1024
+	eig := make([]float64, n)
+	data := make([]complex128, n)
+	for k := range n {
+		eig[k] = 1 + float64(k)
+		data[k] = complex(float64(k), 1)
+	}
+
+	for _, kernel := range poisson.DivideKernels() {
+		b.Run(kernel.Name(), func(b *testing.B) {
+			b.SetBytes(n * 16)
+			for b.Loop() {
+				kernel.DivideLine(data, 1, eig)
+			}
+		})
+	}
+}
+
+func cmplxAbsDiff(a, b complex128) float64 {
+	return math.Max(math.Abs(real(a)-real(b)), math.Abs(imag(a)-imag(b)))
+}
+
+func TestPlan_PrecomputedInverseMatchesDefault(t *testing.T) {
+	n := []int{16, 12, 10}
+	h := []float64{0.1, 0.2, 0.15}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+
+	cases := []struct {
+		name string
+		make func(opts ...poisson.Option) (*poisson.Plan, error)
+	}{
+		{"poisson", func(opts ...poisson.Option) (*poisson.Plan, error) {
+			return poisson.NewPlan(3, n, h, bc, opts...)
+		}},
+		{"helmholtz", func(opts ...poisson.Option) (*poisson.Plan, error) {
+			return poisson.NewHelmholtzPlan(3, n, h, bc, 2.5, opts...)
+		}},
+		{"hyperdiffusion", func(opts ...poisson.Option) (*poisson.Plan, error) {
+			return poisson.NewHyperdiffusionPlan(3, n, h, bc, 0.01, 2, opts...)
+		}},
+	}
+
+	rhs := make([]float64, n[0]*n[1]*n[2])
+	for i := range rhs {
+		rhs[i] = math.Sin(0.37*float64(i)) + 0.25
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := tc.make()
+			if err != nil {
+				t.Fatalf("plan: %v", err)
+			}
+			plan, err := tc.make(poisson.WithPrecomputedInverse())
+			if err != nil {
+				t.Fatalf("plan: %v", err)
+			}
+
+			want := make([]float64, len(rhs))
+			if err := ref.Solve(want, rhs); err != nil {
+				t.Fatalf("Solve: %v", err)
+			}
+
+			before := plan.WorkBytes()
+			// Solve twice: the first call builds the table, the second reuses it.
+			got := make([]float64, len(rhs))
+			for range 2 {
+				if err := plan.Solve(got, rhs); err != nil {
+					t.Fatalf("Solve: %v", err)
+				}
+				if d := maxAbsDiff(got, want); d > 1e-12 {
+					t.Fatalf("max diff %g", d)
+				}
+			}
+
+			if grown := plan.WorkBytes() - before; grown != 8*len(rhs) {
+				t.Fatalf("WorkBytes grew by %d, want %d", grown, 8*len(rhs))
+			}
+		})
+	}
+}
+
+func BenchmarkPrecomputedInverse(b *testing.B) {
+	n := []int{128, 128}
+	h := []float64{1.0 / 128, 1.0 / 128}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Periodic}
+
+	rhs := make([]float64, n[0]*n[1])
+	for i := range rhs {
+		rhs[i] = float64(i % 7)
+	}
+	dst := make([]float64, len(rhs))
+
+	for _, tc := range []struct {
+		name string
+		opts []poisson.Option
+	}{
+		{"divide", nil},
+		{"table", []poisson.Option{poisson.WithPrecomputedInverse()}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			plan, err := poisson.NewHelmholtzPlan(2, n, h, bc, 1.5, tc.opts...)
+			if err != nil {
+				b.Fatalf("plan: %v", err)
+			}
+			if err := plan.Solve(dst, rhs); err != nil {
+				b.Fatalf("Solve: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := plan.Solve(dst, rhs); err != nil {
+					b.Fatalf("Solve: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package poisson
+
+// LinearOperator exposes the discrete operator of a Plan, A = alpha + L with
+// L the negative Laplacian (including per-axis coefficients and powers), as a
+// matrix-free linear operator for Krylov solvers.
+//
+// It works on plain slices so that poisson does not depend on gonum;
+// fd/gonumfd.NewOperator adapts it to the MulVecToer interface of gonum's
+// linsolve package. A is symmetric, so the transpose flag of MulVecTo is
+// ignored.
+//
+// A LinearOperator shares scratch buffers with its plan and must not be used
+// concurrently with it.
+type LinearOperator struct {
+	plan *Plan
+	buf  []float64
+}
+
+// Operator returns the plan's operator as a LinearOperator.
+func (p *Plan) Operator() *LinearOperator {
+	return &LinearOperator{plan: p}
+}
+
+// Dims returns the operator dimensions (rows, columns).
+func (o *LinearOperator) Dims() (r, c int) {
+	n := o.plan.size()
+	return n, n
+}
+
+// MulVecTo computes dst = A x (or Aᵀ x, which is the same). dst and x may alias.
+func (o *LinearOperator) MulVecTo(dst []float64, _ bool, x []float64) error {
+	return o.MulVec(dst, x)
+}
+
+// MulVec computes dst = A x. dst and x may alias.
+func (o *LinearOperator) MulVec(dst, x []float64) error {
+	if dst == nil || x == nil {
+		return ErrNilBuffer
+	}
+
+	size := o.plan.size()
+	if err := checkBuffers("MulVec", size, dst, x, "x"); err != nil {
+		return err
+	}
+
+	src := x
+	if &dst[0] == &x[0] {
+		if len(o.buf) < size {
+			o.buf = make([]float64, size)
+		}
+		src = o.buf[:size]
+		copy(src, x)
+	}
+
+	o.plan.applyOperator(dst, src)
+
+	return nil
+}
+
+// Preconditioner exposes the plan's fast solve as the inverse of its operator,
+// for use as a preconditioner in Krylov solvers on nearby problems (e.g.
+// variable coefficients or irregular domains).
+//
+// Like LinearOperator it works on plain slices; fd/gonumfd.NewPreconditioner
+// provides the PreconSolve of gonum's linsolve settings. For nullspace
+// problems, create the plan with WithNullspace(NullspaceSubtractMean) so
+// residuals with a non-zero mean are accepted.
+type Preconditioner struct {
+	plan *Plan
+}
+
+// Preconditioner returns the plan's solve as a Preconditioner.
+func (p *Plan) Preconditioner() *Preconditioner {
+	return &Preconditioner{plan: p}
+}
+
+// PreconSolve solves A dst = rhs (Aᵀ = A, so trans is ignored).
+// dst and rhs may alias.
+func (m *Preconditioner) PreconSolve(dst []float64, _ bool, rhs []float64) error {
+	return m.plan.Solve(dst, rhs)
+}
+
+// OperatorFunc returns dst = A src as a plain function, the shape most
+// Krylov libraries accept for matrix-free operators.
+func (p *Plan) OperatorFunc() func(dst, src []float64) error {
+	return p.Operator().MulVec
+}
+
+// PreconditionerFunc returns dst = A⁻¹ src as a plain function.
+func (p *Plan) PreconditionerFunc() func(dst, src []float64) error {
+	return func(dst, src []float64) error {
+		return p.Solve(dst, src)
+	}
+}
@@ -0,0 +1,94 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_OperatorMatchesStencil(t *testing.T) {
+	nx, ny := 9, 7
+	h := [2]float64{0.2, 0.3}
+	bc := [2]poisson.BCType{poisson.Neumann, poisson.Periodic}
+	alpha := 1.5
+
+	plan, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, h[:], bc[:], alpha)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	op := plan.Operator()
+	if r, c := op.Dims(); r != nx*ny || c != nx*ny {
+		t.Fatalf("Dims = (%d, %d), want (%d, %d)", r, c, nx*ny, nx*ny)
+	}
+
+	x := make([]float64, nx*ny)
+	for i := range x {
+		x[i] = math.Sin(float64(i) * 0.41)
+	}
+
+	want := make([]float64, nx*ny)
+	fd.Apply2D(want, x, grid.NewShape2D(nx, ny), h, bc)
+	for i := range want {
+		want[i] += alpha * x[i]
+	}
+
+	got := make([]float64, nx*ny)
+	if err := op.MulVecTo(got, true, x); err != nil {
+		t.Fatalf("MulVecTo failed: %v", err)
+	}
+	if diff := maxAbsDiff(got, want); diff > 1e-12 {
+		t.Fatalf("MulVecTo differs from stencil by %g", diff)
+	}
+
+	apply := plan.OperatorFunc()
+	if err := apply(x, x); err != nil {
+		t.Fatalf("OperatorFunc failed: %v", err)
+	}
+	if diff := maxAbsDiff(x, want); diff > 1e-12 {
+		t.Fatalf("aliased OperatorFunc differs from stencil by %g", diff)
+	}
+
+	if err := op.MulVec(got[:1], x); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("expected ErrSizeMismatch, got %v", err)
+	}
+}
+
+func TestPlan_PreconditionerInvertsOperator(t *testing.T) {
+	n := []int{8, 6, 5}
+	plan, err := poisson.NewPlan(3, n, []float64{0.1, 0.2, 0.3},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann, poisson.Periodic})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	size := n[0] * n[1] * n[2]
+	x := make([]float64, size)
+	for i := range x {
+		x[i] = math.Cos(float64(i) * 0.13)
+	}
+
+	ax := make([]float64, size)
+	if err := plan.OperatorFunc()(ax, x); err != nil {
+		t.Fatalf("OperatorFunc failed: %v", err)
+	}
+
+	got := make([]float64, size)
+	if err := plan.Preconditioner().PreconSolve(got, false, ax); err != nil {
+		t.Fatalf("PreconSolve failed: %v", err)
+	}
+	if diff := maxAbsDiff(got, x); diff > 1e-10 {
+		t.Fatalf("PreconSolve(A x) differs from x by %g", diff)
+	}
+
+	if err := plan.PreconditionerFunc()(ax, ax); err != nil {
+		t.Fatalf("PreconditionerFunc failed: %v", err)
+	}
+	if diff := maxAbsDiff(ax, x); diff > 1e-10 {
+		t.Fatalf("PreconditionerFunc(A x) differs from x by %g", diff)
+	}
+}
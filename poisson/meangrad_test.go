@@ -0,0 +1,109 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_SolveWithMeanGradient(t *testing.T) {
+	nx, ny := 8, 6
+	hx, hy := 0.25, 0.5
+	grad := []float64{0.7, -1.2}
+
+	plan, err := poisson.NewPlan(2, []int{nx, ny}, []float64{hx, hy},
+		[]poisson.BCType{poisson.Periodic, poisson.Periodic})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	// Zero-mean periodic fluctuation w and its discrete RHS.
+	w := make([]float64, nx*ny)
+	for i := 0; i < nx; i++ {
+		for j := 0; j < ny; j++ {
+			w[i*ny+j] = math.Sin(2*math.Pi*float64(i)/float64(nx)) * math.Cos(2*math.Pi*float64(j)/float64(ny))
+		}
+	}
+	rhs := make([]float64, nx*ny)
+	if err := plan.Operator().MulVec(rhs, w); err != nil {
+		t.Fatalf("MulVec failed: %v", err)
+	}
+
+	got := make([]float64, nx*ny)
+	if err := plan.SolveWithMeanGradient(got, rhs, grad); err != nil {
+		t.Fatalf("SolveWithMeanGradient failed: %v", err)
+	}
+
+	want := make([]float64, nx*ny)
+	for i := 0; i < nx; i++ {
+		for j := 0; j < ny; j++ {
+			x := (float64(i) - float64(nx-1)/2) * hx
+			y := (float64(j) - float64(ny-1)/2) * hy
+			want[i*ny+j] = w[i*ny+j] + grad[0]*x + grad[1]*y
+		}
+	}
+
+	if diff := maxAbsDiff(got, want); diff > 1e-12 {
+		t.Errorf("solution differs from w + G·(x - x_c) by %g", diff)
+	}
+
+	// Removing the linear part leaves the periodic fluctuation.
+	if err := plan.SubtractMeanGradient(got, grad); err != nil {
+		t.Fatalf("SubtractMeanGradient failed: %v", err)
+	}
+	if diff := maxAbsDiff(got, w); diff > 1e-12 {
+		t.Errorf("fluctuation differs by %g", diff)
+	}
+
+	// The gauge still sets the mean of the full field.
+	if err := plan.SolveWithMeanGradient(got, rhs, grad, poisson.WithGauge(3)); err != nil {
+		t.Fatalf("SolveWithMeanGradient with gauge failed: %v", err)
+	}
+	mean := 0.0
+	for _, v := range got {
+		mean += v
+	}
+	if mean /= float64(len(got)); math.Abs(mean-3) > 1e-12 {
+		t.Errorf("mean = %g, want 3", mean)
+	}
+}
+
+func TestPlan_SolveWithMeanGradientValidation(t *testing.T) {
+	n := []int{8, 8}
+	h := []float64{0.1, 0.1}
+	mixed := []poisson.BCType{poisson.Periodic, poisson.Dirichlet}
+	periodic := []poisson.BCType{poisson.Periodic, poisson.Periodic}
+
+	mixedPlan, err := poisson.NewPlan(2, n, h, mixed)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	helmholtz, err := poisson.NewHelmholtzPlan(2, n, h, periodic, 1)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 64)
+	dst := make([]float64, 64)
+
+	var verr *poisson.ValidationError
+	if err := mixedPlan.SolveWithMeanGradient(dst, rhs, []float64{0, 1}); !errors.As(err, &verr) || verr.Field != "grad[1]" {
+		t.Errorf("gradient on Dirichlet axis: got %v", err)
+	}
+	if err := mixedPlan.SolveWithMeanGradient(dst, rhs, []float64{1, 0}); err != nil {
+		t.Errorf("gradient on periodic axis of mixed plan: %v", err)
+	}
+	if err := helmholtz.SolveWithMeanGradient(dst, rhs, []float64{1, 0}); !errors.As(err, &verr) || verr.Field != "alpha" {
+		t.Errorf("gradient with alpha != 0: got %v", err)
+	}
+	if err := helmholtz.SolveWithMeanGradient(dst, rhs, []float64{0, 0}); err != nil {
+		t.Errorf("zero gradient with alpha != 0: %v", err)
+	}
+
+	var serr *poisson.SizeError
+	if err := mixedPlan.SolveWithMeanGradient(dst, rhs, []float64{1}); !errors.As(err, &serr) {
+		t.Errorf("wrong gradient length: got %v", err)
+	}
+}
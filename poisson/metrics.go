@@ -0,0 +1,45 @@
+package poisson
+
+import "time"
+
+// Metrics receives one event per Plan solve call, for exporting solve
+// counts, durations and errors to a monitoring system such as expvar or
+// Prometheus from a long-running solver service. See WithMetrics.
+//
+// A Metrics shared by plans that solve concurrently must be safe for
+// concurrent use.
+type Metrics interface {
+	// ObserveSolve is called when a solve call returns, with the name of the
+	// method family ("Solve", "SolveWithBC", "SolveBoundary" or "SolveAt"),
+	// the wall time of the call and the error it returned (nil on success).
+	ObserveSolve(method string, elapsed time.Duration, err error)
+}
+
+// WithMetrics makes Plan report every solve call to m. Calls that fail
+// validation are reported with their error too. The benchmark solves of
+// WithAutoTune are not reported.
+func WithMetrics(m Metrics) Option {
+	return func(o *Options) {
+		o.Metrics = m
+	}
+}
+
+// metricsStart returns the start time of a solve call, or the zero time
+// when the plan has no Metrics.
+func (p *Plan) metricsStart() time.Time {
+	if p.opts.Metrics == nil {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// observeSolve reports a solve call that started at start and returned *err
+// to the plan's Metrics. It is deferred by the exported solve methods.
+func (p *Plan) observeSolve(method string, start time.Time, err *error) {
+	if p.opts.Metrics == nil {
+		return
+	}
+
+	p.opts.Metrics.ObserveSolve(method, time.Since(start), *err)
+}
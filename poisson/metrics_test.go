@@ -0,0 +1,96 @@
+package poisson_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+type solveEvent struct {
+	method string
+	err    error
+}
+
+type recordingMetrics struct {
+	events []solveEvent
+	total  time.Duration
+}
+
+func (m *recordingMetrics) ObserveSolve(method string, elapsed time.Duration, err error) {
+	m.events = append(m.events, solveEvent{method, err})
+	m.total += elapsed
+}
+
+func TestPlan_MetricsObservesSolveCalls(t *testing.T) {
+	metrics := &recordingMetrics{}
+	plan, err := poisson.NewPlan(2, []int{8, 6}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann}, poisson.WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	requireStatus(t, plan.Report(), "WithMetrics", poisson.OptionHonored)
+
+	rhs := make([]float64, 8*6)
+	rhs[10] = 1
+	dst := make([]float64, len(rhs))
+	bc := poisson.BoundaryConditions{plan.FaceData(poisson.XLow, make([]float64, 6))}
+
+	if err := plan.Solve(dst, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if err := plan.SolveWithBC(dst, rhs, bc); err != nil {
+		t.Fatalf("SolveWithBC failed: %v", err)
+	}
+	if err := plan.SolveBoundary(dst, bc); err != nil {
+		t.Fatalf("SolveBoundary failed: %v", err)
+	}
+	if err := plan.SolveAt(dst[:1], rhs, [][3]int{{1, 2, 0}}); err != nil {
+		t.Fatalf("SolveAt failed: %v", err)
+	}
+	if err := plan.Solve(nil, rhs); !errors.Is(err, poisson.ErrNilBuffer) {
+		t.Fatalf("expected ErrNilBuffer, got %v", err)
+	}
+
+	want := []string{"Solve", "SolveWithBC", "SolveBoundary", "SolveAt", "Solve"}
+	if len(metrics.events) != len(want) {
+		t.Fatalf("observed %d solves, want %d: %v", len(metrics.events), len(want), metrics.events)
+	}
+	for i, method := range want {
+		if metrics.events[i].method != method {
+			t.Errorf("event %d method = %q, want %q", i, metrics.events[i].method, method)
+		}
+	}
+	if err := metrics.events[4].err; !errors.Is(err, poisson.ErrNilBuffer) {
+		t.Errorf("failed solve observed with error %v, want ErrNilBuffer", err)
+	}
+	if metrics.events[0].err != nil {
+		t.Errorf("successful solve observed with error %v", metrics.events[0].err)
+	}
+	if metrics.total <= 0 {
+		t.Error("observed durations sum to zero")
+	}
+}
+
+func TestPlan_MetricsSkipsAutoTuneSolves(t *testing.T) {
+	metrics := &recordingMetrics{}
+	plan, err := poisson.NewPlan(2, []int{8, 8}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Periodic, poisson.Periodic},
+		poisson.WithAutoTune(5*time.Millisecond), poisson.WithMetrics(metrics))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	if len(metrics.events) != 0 {
+		t.Fatalf("plan creation reported %d solves", len(metrics.events))
+	}
+
+	buf := make([]float64, 64)
+	if err := plan.SolveInPlace(buf); err != nil {
+		t.Fatalf("SolveInPlace failed: %v", err)
+	}
+	if len(metrics.events) != 1 {
+		t.Errorf("observed %d solves after autotuning, want 1", len(metrics.events))
+	}
+}
@@ -1,6 +1,7 @@
 package poisson_test
 
 import (
+	"errors"
 	"math"
 	"testing"
 
@@ -176,3 +177,55 @@ func applyInhomNeumann2D(dst, src []float64, shape grid.Shape, hx, hy float64, x
 		}
 	}
 }
+
+func TestPlan1DNeumann_SolveWithBC_FluxBalance(t *testing.T) {
+	n := 32
+	h := 1.0 / float64(n)
+	g0, gL := 1.0, 3.0
+
+	rhs := make([]float64, n)
+	for i := range rhs {
+		rhs[i] = math.Cos(math.Pi * (float64(i) + 0.5) / float64(n))
+	}
+
+	bc := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Neumann, Values: []float64{g0}},
+		{Face: poisson.XHigh, Type: poisson.Neumann, Values: []float64{gL}},
+	}
+
+	strict, err := poisson.NewPlan(1, []int{n}, []float64{h}, []poisson.BCType{poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	got := make([]float64, n)
+	if err := strict.SolveWithBC(got, rhs, bc); !errors.Is(err, poisson.ErrNonZeroMean) {
+		t.Fatalf("expected ErrNonZeroMean for unbalanced flux, got %v", err)
+	}
+
+	balanced, err := poisson.NewPlan(1, []int{n}, []float64{h}, []poisson.BCType{poisson.Neumann},
+		poisson.WithFluxBalance())
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	var stats poisson.SolveStats
+	if err := balanced.SolveWithBC(got, rhs, bc, poisson.WithStats(&stats)); err != nil {
+		t.Fatalf("SolveWithBC failed: %v", err)
+	}
+
+	want := (gL - g0) / h / float64(n)
+	if math.Abs(stats.FluxAdjustment-want) > neumannInhomTol {
+		t.Fatalf("FluxAdjustment = %g, want %g", stats.FluxAdjustment, want)
+	}
+
+	// The solution must satisfy the balanced problem: -Δu = f - adjustment
+	// with the given boundary fluxes.
+	check := make([]float64, n)
+	applyInhomNeumann1D(check, got, h, g0, gL)
+	for i := range check {
+		if diff := math.Abs(check[i] - (rhs[i] - want)); diff > neumannInhomTol {
+			t.Fatalf("residual at %d: %g", i, diff)
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package poisson
 
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
 // NullspaceHandling specifies how to handle the nullspace (constant mode)
 // for boundary conditions that have zero eigenvalues (Periodic, Neumann).
 type NullspaceHandling int
@@ -19,7 +25,114 @@ const (
 	NullspaceError
 )
 
+// ResonanceHandling specifies how singular or near-singular Helmholtz modes
+// (alpha cancelling an eigenvalue) are treated during Solve.
+type ResonanceHandling int
+
+const (
+	// ResonanceError makes Solve fail with ErrResonant (default).
+	ResonanceError ResonanceHandling = iota
+
+	// ResonancePseudoInverse zeroes resonant modes, i.e. applies the
+	// pseudo-inverse of the operator.
+	ResonancePseudoInverse
+
+	// ResonanceTikhonov replaces 1/d by d/(d² + μ²) on resonant modes, where d
+	// is the mode's symbol and μ the Tikhonov parameter.
+	ResonanceTikhonov
+)
+
+// TransformStrategy selects how transforms along non-contiguous axes (every
+// axis but the last in row-major order) access memory.
+type TransformStrategy int
+
+const (
+	// TransformStrided transforms each strided line in place (default).
+	TransformStrided TransformStrategy = iota
+
+	// TransformBlocked copies blocks of adjacent lines to contiguous scratch,
+	// transforms them there and copies them back (a cache-blocked transpose).
+	TransformBlocked
+
+	// TransformAuto times both strategies per axis when the plan is created
+	// and keeps the faster one. Plan construction takes a few extra
+	// transform passes.
+	TransformAuto
+)
+
+// String returns the string representation of the transform strategy.
+func (s TransformStrategy) String() string {
+	switch s {
+	case TransformStrided:
+		return "TransformStrided"
+	case TransformBlocked:
+		return "TransformBlocked"
+	case TransformAuto:
+		return "TransformAuto"
+	default:
+		return "Unknown"
+	}
+}
+
+// String returns the string representation of the resonance handling mode.
+func (h ResonanceHandling) String() string {
+	switch h {
+	case ResonanceError:
+		return "ResonanceError"
+	case ResonancePseudoInverse:
+		return "ResonancePseudoInverse"
+	case ResonanceTikhonov:
+		return "ResonanceTikhonov"
+	default:
+		return "Unknown"
+	}
+}
+
+// String returns the string representation of the nullspace handling mode.
+func (h NullspaceHandling) String() string {
+	switch h {
+	case NullspaceZeroMode:
+		return "NullspaceZeroMode"
+	case NullspaceSubtractMean:
+		return "NullspaceSubtractMean"
+	case NullspaceError:
+		return "NullspaceError"
+	default:
+		return "Unknown"
+	}
+}
+
+// RealFFTPrecision selects the working precision of the real FFT path
+// (WithRealFFT) of the periodic 2D/3D plans.
+type RealFFTPrecision int
+
+const (
+	// RealFFTFloat32 runs the real FFTs in float32 (default): the fastest
+	// path, with relative errors around 1e-6.
+	RealFFTFloat32 RealFFTPrecision = iota
+
+	// RealFFTFloat64 runs real-to-complex FFTs in float64 along the last
+	// axis and complex128 FFTs along the others, keeping the accuracy of
+	// the complex path at roughly half its transform cost.
+	RealFFTFloat64
+)
+
+// String returns the string representation of the precision.
+func (p RealFFTPrecision) String() string {
+	switch p {
+	case RealFFTFloat32:
+		return "RealFFTFloat32"
+	case RealFFTFloat64:
+		return "RealFFTFloat64"
+	default:
+		return "Unknown"
+	}
+}
+
 // Options configures the behavior of a Poisson solver.
+//
+// All plan constructors share this option set. Options are normally built
+// from Option values via ApplyOptions, which also validates them.
 type Options struct {
 	// Nullspace handling for problems with zero eigenvalues.
 	Nullspace NullspaceHandling
@@ -32,6 +145,10 @@ type Options struct {
 	// This uses algo-fft's real FFT plans, which operate on float32 buffers.
 	UseRealFFT bool
 
+	// RealFFTPrecision selects float32 or float64 real FFTs when UseRealFFT
+	// is set.
+	RealFFTPrecision RealFFTPrecision
+
 	// Workers is the number of parallel workers for transforms.
 	// 0 means use runtime.GOMAXPROCS.
 	Workers int
@@ -39,6 +156,80 @@ type Options struct {
 	// InPlace allows the solver to modify the input RHS buffer.
 	// When true, Solve may use rhs as scratch space.
 	InPlace bool
+
+	// Resonance selects how resonant Helmholtz modes are handled.
+	Resonance ResonanceHandling
+
+	// ResonanceTolerance widens resonance detection from exact zeros to
+	// near-singular modes: a mode with symbol d = alpha + λ is resonant when
+	// |d| <= ResonanceTolerance * (|alpha| + |λ|). 0 detects exact zeros only.
+	ResonanceTolerance float64
+
+	// TikhonovParameter is μ for ResonanceTikhonov.
+	TikhonovParameter float64
+
+	// BalanceFlux makes SolveWithBC remove the net flux of inhomogeneous
+	// Neumann data as a uniform volumetric source on nullspace problems,
+	// so incompatible boundary data does not violate the solvability
+	// condition. The RHS itself must still satisfy the nullspace policy.
+	BalanceFlux bool
+
+	// SpectralFilter is applied to the solution coefficients of every
+	// Plan.Solve call (see FilterFunc). A per-call WithFilter overrides it.
+	// Nil disables filtering.
+	SpectralFilter FilterFunc
+
+	// ReferenceTransforms replaces the FFT-based axis transforms of Plan
+	// with direct O(N²) summations. Debug only.
+	ReferenceTransforms bool
+
+	// PrecomputedInverse makes Plan store 1/(alpha + λ) for every mode so
+	// Solve multiplies instead of dividing. Costs 8 bytes per grid point.
+	PrecomputedInverse bool
+
+	// Workspace, if non-nil, is an external workspace the plan borrows
+	// during each solve instead of allocating its own. See WithWorkspace.
+	Workspace *Workspace
+
+	// PooledWorkspace makes the plan borrow its workspace from a package
+	// pool during each solve. See WithPooledWorkspace.
+	PooledWorkspace bool
+
+	// TransformStrategy selects the memory access strategy of transforms
+	// along non-contiguous axes of Plan and the 2D/3D periodic plans.
+	TransformStrategy TransformStrategy
+
+	// DivideKernel performs the eigenvalue division of Plan.Solve on
+	// contiguous lines. Nil selects DefaultDivideKernel().
+	DivideKernel DivideKernel
+
+	// UseGPU runs the transforms and eigenvalue division of all-periodic
+	// Plans on a GPU. It requires a build with the cuda tag; see WithGPU.
+	UseGPU bool
+
+	// AutoTune, if positive, is the time budget for benchmarking candidate
+	// configurations at plan creation. See WithAutoTune.
+	AutoTune time.Duration
+
+	// Timing records per-stage solve times in Plan.Stats. See WithTiming.
+	Timing bool
+
+	// Metrics receives an event for every solve call. See WithMetrics.
+	Metrics Metrics
+
+	// CheckFinite rejects right-hand sides holding NaN or Inf. See
+	// WithCheckFinite.
+	CheckFinite bool
+
+	// Stencil selects the discrete Laplacian of Plan. See WithStencil.
+	Stencil Stencil
+
+	// nullspaceSet records that an option explicitly chose a nullspace mode,
+	// so that a later option choosing a different mode can be reported.
+	nullspaceSet bool
+
+	// err holds the first conflict detected while applying options.
+	err error
 }
 
 // Option is a function that modifies Options.
@@ -52,21 +243,38 @@ func DefaultOptions() Options {
 		UseRealFFT:   false,
 		Workers:      0,
 		InPlace:      false,
+		Resonance:    ResonanceError,
 	}
 }
 
 // WithNullspace sets the nullspace handling mode.
+//
+// This is the canonical way to select a nullspace mode. Selecting two
+// different modes in the same option list is reported as a conflict.
 func WithNullspace(h NullspaceHandling) Option {
 	return func(o *Options) {
-		o.Nullspace = h
+		o.setNullspace(h)
 	}
 }
 
 // WithSubtractMean enables automatic mean subtraction for nullspace handling.
+//
+// Deprecated: Use WithNullspace(NullspaceSubtractMean) instead.
 func WithSubtractMean() Option {
-	return func(o *Options) {
-		o.Nullspace = NullspaceSubtractMean
+	return WithNullspace(NullspaceSubtractMean)
+}
+
+// SubtractMean enables (true) or disables (false) automatic mean subtraction.
+// Disabling selects NullspaceZeroMode.
+//
+// Deprecated: Use WithNullspace(NullspaceSubtractMean) or
+// WithNullspace(NullspaceZeroMode) instead.
+func SubtractMean(enabled bool) Option {
+	if enabled {
+		return WithNullspace(NullspaceSubtractMean)
 	}
+
+	return WithNullspace(NullspaceZeroMode)
 }
 
 // WithWorkers sets the number of parallel workers.
@@ -84,13 +292,25 @@ func WithSolutionMean(mean float64) Option {
 	}
 }
 
-// WithRealFFT enables or disables real FFT plans when available.
+// WithRealFFT enables or disables real FFT plans when available. The 2D and
+// 3D periodic plans support any size whose last dimension is even, including
+// non-power-of-two sizes such as 100×100 or 360×180; other plans fall back to
+// complex FFTs (see PlanReport).
 func WithRealFFT(enabled bool) Option {
 	return func(o *Options) {
 		o.UseRealFFT = enabled
 	}
 }
 
+// WithRealFFTPrecision selects the precision of the real FFT path enabled by
+// WithRealFFT. RealFFTFloat64 keeps float64 accuracy; the default
+// RealFFTFloat32 trades accuracy (about 1e-6) for speed and memory.
+func WithRealFFTPrecision(p RealFFTPrecision) Option {
+	return func(o *Options) {
+		o.RealFFTPrecision = p
+	}
+}
+
 // WithInPlace allows the solver to modify the input RHS.
 func WithInPlace(inPlace bool) Option {
 	return func(o *Options) {
@@ -98,11 +318,285 @@ func WithInPlace(inPlace bool) Option {
 	}
 }
 
-// ApplyOptions applies option functions to a base Options struct.
-func ApplyOptions(base Options, opts []Option) Options {
+// WithResonance sets how resonant Helmholtz modes are handled.
+func WithResonance(h ResonanceHandling) Option {
+	return func(o *Options) {
+		o.Resonance = h
+	}
+}
+
+// WithResonanceTolerance treats modes within a relative distance eps of
+// resonance as resonant. See Options.ResonanceTolerance.
+func WithResonanceTolerance(eps float64) Option {
+	return func(o *Options) {
+		o.ResonanceTolerance = eps
+	}
+}
+
+// WithTikhonov selects ResonanceTikhonov with regularization parameter mu.
+func WithTikhonov(mu float64) Option {
+	return func(o *Options) {
+		o.Resonance = ResonanceTikhonov
+		o.TikhonovParameter = mu
+	}
+}
+
+// WithFluxBalance enables automatic balancing of the net Neumann boundary
+// flux in SolveWithBC. See Options.BalanceFlux.
+func WithFluxBalance() Option {
+	return func(o *Options) {
+		o.BalanceFlux = true
+	}
+}
+
+// WithSpectralFilter applies filter to the solution coefficients of every
+// Plan.Solve call, e.g. TwoThirdsFilter() for pseudo-spectral dealiasing or
+// ExponentialFilter to damp high-mode noise. See Options.SpectralFilter.
+func WithSpectralFilter(filter FilterFunc) Option {
+	return func(o *Options) {
+		o.SpectralFilter = filter
+	}
+}
+
+// WithReferenceTransforms makes Plan use slow direct-sum DFT/DST-I/DCT-II
+// transforms instead of the FFT-based ones. Results should agree to round-off;
+// a discrepancy points at the fast transforms rather than the solver logic.
+// Meant for debugging and bisecting accuracy issues, never for production.
+func WithReferenceTransforms() Option {
+	return func(o *Options) {
+		o.ReferenceTransforms = true
+	}
+}
+
+// WithPrecomputedInverse makes Plan precompute the full table of inverse
+// symbols 1/(alpha + λx + λy + λz) on the first Solve, so every solve
+// multiplies instead of dividing. It trades 8 bytes per grid point for speed
+// and suits memory-rich users solving many times on one plan. Solves with a
+// spectral filter do not use the table.
+func WithPrecomputedInverse() Option {
+	return func(o *Options) {
+		o.PrecomputedInverse = true
+	}
+}
+
+// WithWorkspace makes the plan use ws as its workspace instead of allocating
+// one. Plans sharing ws hold no large buffers of their own; ws grows to the
+// largest plan on first use. Plans sharing a workspace must not solve
+// concurrently.
+func WithWorkspace(ws *Workspace) Option {
+	return func(o *Options) {
+		o.Workspace = ws
+	}
+}
+
+// WithPooledWorkspace makes the plan borrow its workspace from a
+// package-level pool for each solve and return it afterwards, so many idle
+// plans hold no large buffers and short-lived solves reuse memory instead of
+// allocating.
+func WithPooledWorkspace() Option {
+	return func(o *Options) {
+		o.PooledWorkspace = true
+	}
+}
+
+// WithTransformStrategy selects how transforms along non-contiguous axes
+// access memory. TransformAuto benchmarks both strategies at plan creation.
+func WithTransformStrategy(s TransformStrategy) Option {
+	return func(o *Options) {
+		o.TransformStrategy = s
+	}
+}
+
+// WithDivideKernel selects the kernel used for the eigenvalue division of
+// Plan.Solve, e.g. GoDivideKernel() to rule out SIMD code when debugging.
+func WithDivideKernel(kernel DivideKernel) Option {
+	return func(o *Options) {
+		o.DivideKernel = kernel
+	}
+}
+
+// WithGPU makes Plan run its transforms and eigenvalue division on a CUDA
+// GPU (cuFFT, double precision) with pinned-memory staging. It requires a
+// binary built with the cuda build tag and is supported for all-periodic,
+// non-batch plans; otherwise plan construction fails with ErrGPUUnavailable
+// or a *ValidationError, and resonant modes are reported at construction.
+// Solves with a spectral filter fall back to the CPU.
+func WithGPU() Option {
+	return func(o *Options) {
+		o.UseGPU = true
+	}
+}
+
+// WithAutoTune makes Plan and the 2D/3D periodic plans benchmark candidate
+// configurations at creation and keep the fastest, FFTW-style: worker counts
+// (up to Workers, if set), strided versus blocked transforms and, for
+// periodic plans created with WithRealFFT, real versus complex FFTs. Each
+// candidate plan is built and timed on a solve until budget is spent; at
+// least the first candidate is always measured. Report() names the
+// configuration that was kept.
+//
+// Tuned plans are only as fast as the machine was quiet during planning;
+// tune once and reuse the plan, or persist the choice with explicit options.
+func WithAutoTune(budget time.Duration) Option {
+	return func(o *Options) {
+		o.AutoTune = budget
+	}
+}
+
+// WithCheckFinite makes every solve scan its right-hand side for NaN and Inf
+// before transforming it, returning a *NonFiniteError with the buffer index
+// of the first offending value. Without it, a single NaN silently spreads
+// over the whole solution. The scan costs one pass over the input.
+func WithCheckFinite() Option {
+	return func(o *Options) {
+		o.CheckFinite = true
+	}
+}
+
+// WithStencil selects the discrete Laplacian that Plan inverts: the
+// isotropic Isotropic9 (2D) or Isotropic27 (3D) compact stencils reduce the
+// grid-aligned anisotropy of the truncation error on coarse grids. They stay
+// diagonal in the transforms of every boundary condition, but their symbol
+// is not a sum over axes, so solves skip the line-wise divide kernel, the
+// GPU path is unavailable and SolveWithBC rejects inhomogeneous boundary
+// data. Plans of another dimension fail with a *ValidationError.
+func WithStencil(s Stencil) Option {
+	return func(o *Options) {
+		o.Stencil = s
+	}
+}
+
+// ApplyOptions applies option functions to a base Options struct and
+// validates the result. Nil options are ignored.
+//
+// It returns a *ValidationError when an option value is unknown or when
+// options conflict, e.g. mean subtraction combined with NullspaceError.
+func ApplyOptions(base Options, opts []Option) (Options, error) {
 	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
 		opt(&base)
 	}
 
-	return base
+	if base.err != nil {
+		return base, base.err
+	}
+
+	if err := base.Validate(); err != nil {
+		return base, err
+	}
+
+	return base, nil
+}
+
+// Validate checks the options for unknown values and conflicting settings.
+func (o Options) Validate() error {
+	switch o.Nullspace {
+	case NullspaceZeroMode, NullspaceSubtractMean, NullspaceError:
+	default:
+		return &ValidationError{
+			Field:   "Nullspace",
+			Message: fmt.Sprintf("unknown nullspace handling %d", int(o.Nullspace)),
+		}
+	}
+
+	if o.Workers < 0 {
+		return &ValidationError{
+			Field:   "Workers",
+			Message: "must be non-negative (0 selects GOMAXPROCS)",
+		}
+	}
+
+	switch o.Resonance {
+	case ResonanceError, ResonancePseudoInverse, ResonanceTikhonov:
+	default:
+		return &ValidationError{
+			Field:   "Resonance",
+			Message: fmt.Sprintf("unknown resonance handling %d", int(o.Resonance)),
+		}
+	}
+
+	switch o.RealFFTPrecision {
+	case RealFFTFloat32, RealFFTFloat64:
+	default:
+		return &ValidationError{
+			Field:   "RealFFTPrecision",
+			Message: fmt.Sprintf("unknown real FFT precision %d", int(o.RealFFTPrecision)),
+		}
+	}
+
+	switch o.TransformStrategy {
+	case TransformStrided, TransformBlocked, TransformAuto:
+	default:
+		return &ValidationError{
+			Field:   "TransformStrategy",
+			Message: fmt.Sprintf("unknown transform strategy %d", int(o.TransformStrategy)),
+		}
+	}
+
+	switch o.Stencil {
+	case StandardStencil, Isotropic9, Isotropic27:
+	default:
+		return &ValidationError{
+			Field:   "Stencil",
+			Message: fmt.Sprintf("unknown stencil %d", int(o.Stencil)),
+		}
+	}
+
+	if o.Stencil != StandardStencil && o.UseGPU {
+		return &ValidationError{
+			Field:   "Stencil",
+			Message: fmt.Sprintf("%s conflicts with UseGPU", o.Stencil),
+		}
+	}
+
+	if o.ResonanceTolerance < 0 || math.IsNaN(o.ResonanceTolerance) {
+		return &ValidationError{
+			Field:   "ResonanceTolerance",
+			Message: "must be non-negative",
+		}
+	}
+
+	if o.Resonance == ResonanceTikhonov && (o.TikhonovParameter <= 0 || math.IsNaN(o.TikhonovParameter)) {
+		return &ValidationError{
+			Field:   "TikhonovParameter",
+			Message: "must be positive for ResonanceTikhonov",
+		}
+	}
+
+	if o.AutoTune < 0 {
+		return &ValidationError{
+			Field:   "AutoTune",
+			Message: "budget must be non-negative",
+		}
+	}
+
+	if o.Workspace != nil && o.PooledWorkspace {
+		return &ValidationError{
+			Field:   "Workspace",
+			Message: "conflicts with PooledWorkspace",
+		}
+	}
+
+	if o.Nullspace == NullspaceError && o.SolutionMean != nil {
+		return &ValidationError{
+			Field:   "SolutionMean",
+			Message: "conflicts with NullspaceError: a solution mean only applies to nullspace problems",
+		}
+	}
+
+	return nil
+}
+
+func (o *Options) setNullspace(h NullspaceHandling) {
+	if o.nullspaceSet && o.Nullspace != h && o.err == nil {
+		o.err = &ValidationError{
+			Field:   "Nullspace",
+			Message: fmt.Sprintf("conflicting options: %s and %s", o.Nullspace, h),
+		}
+	}
+
+	o.Nullspace = h
+	o.nullspaceSet = true
 }
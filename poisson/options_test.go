@@ -0,0 +1,197 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestApplyOptions_Aliases(t *testing.T) {
+	aliases := map[string]poisson.Option{
+		"WithNullspace":    poisson.WithNullspace(poisson.NullspaceSubtractMean),
+		"WithSubtractMean": poisson.WithSubtractMean(),
+		"SubtractMean":     poisson.SubtractMean(true),
+	}
+
+	for name, opt := range aliases {
+		t.Run(name, func(t *testing.T) {
+			opts, err := poisson.ApplyOptions(poisson.DefaultOptions(), []poisson.Option{opt})
+			if err != nil {
+				t.Fatalf("ApplyOptions failed: %v", err)
+			}
+			if opts.Nullspace != poisson.NullspaceSubtractMean {
+				t.Fatalf("Nullspace = %v, want %v", opts.Nullspace, poisson.NullspaceSubtractMean)
+			}
+		})
+	}
+
+	opts, err := poisson.ApplyOptions(poisson.DefaultOptions(), []poisson.Option{poisson.SubtractMean(false)})
+	if err != nil {
+		t.Fatalf("ApplyOptions failed: %v", err)
+	}
+	if opts.Nullspace != poisson.NullspaceZeroMode {
+		t.Fatalf("Nullspace = %v, want %v", opts.Nullspace, poisson.NullspaceZeroMode)
+	}
+}
+
+func TestApplyOptions_RepeatedSameModeAllowed(t *testing.T) {
+	_, err := poisson.ApplyOptions(poisson.DefaultOptions(), []poisson.Option{
+		poisson.WithSubtractMean(),
+		poisson.WithNullspace(poisson.NullspaceSubtractMean),
+		nil,
+	})
+	if err != nil {
+		t.Fatalf("ApplyOptions failed: %v", err)
+	}
+}
+
+func TestApplyOptions_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []poisson.Option
+		field string
+	}{
+		{
+			name:  "SubtractMeanThenError",
+			opts:  []poisson.Option{poisson.WithSubtractMean(), poisson.WithNullspace(poisson.NullspaceError)},
+			field: "Nullspace",
+		},
+		{
+			name:  "ErrorThenSubtractMean",
+			opts:  []poisson.Option{poisson.WithNullspace(poisson.NullspaceError), poisson.SubtractMean(true)},
+			field: "Nullspace",
+		},
+		{
+			name:  "UnknownNullspace",
+			opts:  []poisson.Option{poisson.WithNullspace(poisson.NullspaceHandling(42))},
+			field: "Nullspace",
+		},
+		{
+			name:  "NegativeWorkers",
+			opts:  []poisson.Option{poisson.WithWorkers(-1)},
+			field: "Workers",
+		},
+		{
+			name:  "SolutionMeanWithNullspaceError",
+			opts:  []poisson.Option{poisson.WithNullspace(poisson.NullspaceError), poisson.WithSolutionMean(1)},
+			field: "SolutionMean",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := poisson.ApplyOptions(poisson.DefaultOptions(), tt.opts)
+
+			var vErr *poisson.ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if vErr.Field != tt.field {
+				t.Fatalf("Field = %q, want %q", vErr.Field, tt.field)
+			}
+		})
+	}
+}
+
+func TestConstructors_RejectConflictingOptions(t *testing.T) {
+	opts := []poisson.Option{poisson.WithSubtractMean(), poisson.WithNullspace(poisson.NullspaceError)}
+
+	constructors := map[string]func() error{
+		"NewPlan1DPeriodic": func() error {
+			_, err := poisson.NewPlan1DPeriodic(8, 1, opts...)
+			return err
+		},
+		"NewPlan2DPeriodic": func() error {
+			_, err := poisson.NewPlan2DPeriodic(8, 8, 1, 1, opts...)
+			return err
+		},
+		"NewPlan3DPeriodic": func() error {
+			_, err := poisson.NewPlan3DPeriodic(4, 4, 4, 1, 1, 1, opts...)
+			return err
+		},
+		"NewPlanNDPeriodic": func() error {
+			_, err := poisson.NewPlanNDPeriodic(poisson.Shape{4, 4}, []float64{1, 1}, opts...)
+			return err
+		},
+		"NewPlan": func() error {
+			_, err := poisson.NewPlan(1, []int{8}, []float64{1}, []poisson.BCType{poisson.Neumann}, opts...)
+			return err
+		},
+	}
+
+	for name, construct := range constructors {
+		t.Run(name, func(t *testing.T) {
+			var vErr *poisson.ValidationError
+			if err := construct(); !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWithCheckFinite(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{6, 5}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann}, poisson.WithCheckFinite())
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	requireStatus(t, plan.Report(), "WithCheckFinite", poisson.OptionHonored)
+
+	rhs := make([]float64, 6*5)
+	dst := make([]float64, len(rhs))
+	if err := plan.Solve(dst, rhs); err != nil {
+		t.Fatalf("Solve of a finite rhs failed: %v", err)
+	}
+
+	rhs[17] = math.NaN()
+
+	var nonFinite *poisson.NonFiniteError
+	err = plan.Solve(dst, rhs)
+	if !errors.As(err, &nonFinite) || nonFinite.Index != 17 {
+		t.Fatalf("Solve: got %v, want a NonFiniteError at index 17", err)
+	}
+	if !errors.Is(err, poisson.ErrNonFinite) {
+		t.Errorf("NonFiniteError does not wrap ErrNonFinite")
+	}
+
+	if err := plan.SolveAt(dst[:1], rhs, [][3]int{{1, 1, 0}}); !errors.As(err, &nonFinite) || nonFinite.Index != 17 {
+		t.Errorf("SolveAt: got %v, want a NonFiniteError at index 17", err)
+	}
+
+	// The index is that of the buffer, not of the window.
+	parent := grid.NewShape2D(8, 5)
+	field := make([]float64, parent.Size())
+	view := grid.SubView(parent, [3]int{1, 0, 0})
+	field[view.Index(2, 3, 0)] = math.Inf(1)
+	if err := plan.SolveView(field, view, field, view); !errors.As(err, &nonFinite) || nonFinite.Index != view.Index(2, 3, 0) {
+		t.Errorf("SolveView: got %v, want a NonFiniteError at index %d", err, view.Index(2, 3, 0))
+	}
+
+	unchecked, err := poisson.NewPlan(2, []int{6, 5}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	if err := unchecked.Solve(dst, rhs); err != nil {
+		t.Errorf("Solve without WithCheckFinite failed: %v", err)
+	}
+}
+
+func TestWithCheckFinite_Periodic(t *testing.T) {
+	plan, err := poisson.NewPlan2DPeriodic(4, 4, 0.1, 0.1, poisson.WithSubtractMean(), poisson.WithCheckFinite())
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+	requireStatus(t, plan.Report(), "WithCheckFinite", poisson.OptionHonored)
+
+	rhs := make([]float64, 16)
+	rhs[6] = math.Inf(-1)
+
+	var nonFinite *poisson.NonFiniteError
+	if err := plan.Solve(make([]float64, 16), rhs); !errors.As(err, &nonFinite) || nonFinite.Index != 6 || nonFinite.Context != "Solve rhs" {
+		t.Errorf("Solve: got %v, want a NonFiniteError for rhs[6]", err)
+	}
+}
@@ -1,71 +1,77 @@
 package poisson
 
 import (
-	"runtime"
-	"sync"
-
 	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/par"
 )
 
 func effectiveWorkers(workers int) int {
-	if workers <= 0 {
-		workers = runtime.GOMAXPROCS(0)
-	}
-	if workers < 1 {
-		workers = 1
-	}
-	return workers
+	return par.Workers(workers)
 }
 
 func clampWorkers(workers, tasks int) int {
-	if tasks < 1 {
-		return 1
-	}
-	if workers < 1 {
-		workers = 1
-	}
-	if workers > tasks {
-		return tasks
+	return par.Clamp(workers, tasks)
+}
+
+// workerLimit caps the number of workers a transform uses below the count it
+// was created for, for the per-solve WithSolveWorkers override. The zero
+// value imposes no cap.
+type workerLimit struct {
+	limit int
+}
+
+// limitWorkers caps later calls at n workers; n <= 0 removes the cap.
+func (l *workerLimit) limitWorkers(n int) {
+	l.limit = n
+}
+
+// capped returns workers, reduced to the cap if one is set.
+func (l *workerLimit) capped(workers int) int {
+	if l.limit > 0 && l.limit < workers {
+		return l.limit
 	}
 	return workers
 }
 
+// workerLimiter is implemented by axis transforms that can run with fewer
+// workers than they were created for.
+type workerLimiter interface {
+	limitWorkers(n int)
+}
+
 func parallelFor(workers, tasks int, fn func(worker, start, end int) error) error {
+	return par.For(workers, tasks, fn)
+}
+
+// parallelRun is parallelFor for loop bodies without closures: fn, a
+// function or method expression, is called with recv and args. The
+// single-worker path calls fn directly and does not allocate, which keeps
+// sequential solves allocation-free; only the parallel path builds closures.
+func parallelRun[R, A any](workers, tasks int, recv R, args A, fn func(recv R, args A, worker, start, end int) error) error {
 	if tasks <= 0 {
 		return nil
 	}
 	if workers <= 1 || tasks == 1 {
-		return fn(0, 0, tasks)
+		return fn(recv, args, 0, 0, tasks)
 	}
 
-	chunk := (tasks + workers - 1) / workers
-	var wg sync.WaitGroup
-	var errOnce sync.Once
-	var err error
-
-	for w := 0; w < workers; w++ {
-		start := w * chunk
-		if start >= tasks {
-			break
-		}
-		end := start + chunk
-		if end > tasks {
-			end = tasks
-		}
+	return parallelFor(workers, tasks, func(worker, start, end int) error {
+		return fn(recv, args, worker, start, end)
+	})
+}
 
-		wg.Add(1)
-		go func(worker, start, end int) {
-			defer wg.Done()
-			if e := fn(worker, start, end); e != nil {
-				errOnce.Do(func() {
-					err = e
-				})
-			}
-		}(w, start, end)
-	}
+// linePass holds the arguments of one transform pass over all lines of a
+// grid along an axis, for parallelRun.
+type linePass struct {
+	data    []complex128
+	shape   grid.Shape
+	axis    int
+	inverse bool
 
-	wg.Wait()
-	return err
+	// out and shift are the fused output of an inverse pass: out receives
+	// the real part of every result plus shift.
+	out   []float64
+	shift float64
 }
 
 func lineCount(shape grid.Shape, axis int) int {
@@ -1,8 +1,8 @@
 package poisson
 
 import (
-	"fmt"
 	"math"
+	"slices"
 
 	"github.com/MeKo-Tech/algo-pde/grid"
 )
@@ -12,26 +12,28 @@ const meanTol = 1e-12
 // Plan1DPeriodic is a reusable plan for solving 1D periodic Poisson problems.
 // It solves -Δu = f on a periodic grid with spacing h.
 type Plan1DPeriodic struct {
-	n     int
-	h     float64
-	eig   []float64
-	fft   *FFTPlan
-	work  Workspace
-	opts  Options
-	shape grid.Shape
+	n      int
+	h      float64
+	eig    []float64
+	fft    *FFTPlan
+	work   Workspace
+	wsrc   workspaceSource
+	opts   Options
+	report PlanReport
+	shape  grid.Shape
 }
 
 // NewPlan1DPeriodic creates a new 1D periodic Poisson plan.
 func NewPlan1DPeriodic(nx int, hx float64, opts ...Option) (*Plan1DPeriodic, error) {
-	if nx < 1 {
-		return nil, ErrInvalidSize
+	if err := checkGrid([]int{nx}, []float64{hx}); err != nil {
+		return nil, err
 	}
 
-	if hx <= 0 {
-		return nil, ErrInvalidSpacing
+	options, err := ApplyOptions(DefaultOptions(), opts)
+	if err != nil {
+		return nil, err
 	}
-
-	options := ApplyOptions(DefaultOptions(), opts)
+	report := newPlanReport("Plan1DPeriodic", options, periodicSupported("WithWorkers")...)
 	options.Workers = effectiveWorkers(options.Workers)
 
 	fftPlan, err := NewFFTPlanWithWorkers(nx, options.Workers)
@@ -39,31 +41,52 @@ func NewPlan1DPeriodic(nx int, hx float64, opts ...Option) (*Plan1DPeriodic, err
 		return nil, err
 	}
 
+	wsrc, work := newWorkspaceSource(options, 0, nx)
+
 	return &Plan1DPeriodic{
-		n:     nx,
-		h:     hx,
-		eig:   eigenvaluesPeriodic(nx, hx),
-		fft:   fftPlan,
-		work:  NewWorkspace(0, nx),
-		opts:  options,
-		shape: grid.NewShape1D(nx),
+		n:      nx,
+		h:      hx,
+		eig:    eigenvaluesPeriodic(nx, hx),
+		fft:    fftPlan,
+		work:   work,
+		wsrc:   wsrc,
+		opts:   options,
+		report: report,
+		shape:  grid.NewShape1D(nx),
 	}, nil
 }
 
+// Report returns how the plan resolved its options. See PlanReport.
+func (p *Plan1DPeriodic) Report() PlanReport {
+	r := p.report
+	r.Options = slices.Clone(r.Options)
+
+	return r
+}
+
 // Solve computes the solution into dst for a given RHS.
 func (p *Plan1DPeriodic) Solve(dst, rhs []float64) error {
 	if dst == nil || rhs == nil {
 		return ErrNilBuffer
 	}
 
-	if len(dst) != p.n || len(rhs) != p.n {
-		return ErrSizeMismatch
+	if err := checkBuffers("Solve", p.n, dst, rhs, "rhs"); err != nil {
+		return err
+	}
+
+	if p.opts.CheckFinite {
+		if err := checkFinite("Solve rhs", rhs); err != nil {
+			return err
+		}
 	}
 
 	if p.opts.Nullspace == NullspaceError {
 		return ErrNullspace
 	}
 
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
 	mean, maxAbs := meanAndMaxAbs(rhs)
 	if p.opts.Nullspace == NullspaceZeroMode && !meanWithinTolerance(mean, maxAbs) {
 		return ErrNonZeroMean
@@ -79,39 +102,39 @@ func (p *Plan1DPeriodic) Solve(dst, rhs []float64) error {
 	}
 
 	if err := p.fft.TransformLines(p.work.Complex, p.shape, 0, false); err != nil {
-		return fmt.Errorf("FFT forward: %w", err)
+		return transformError("forward", "FFT", 0, p.shape, 1, err)
 	}
 
 	workers := clampWorkers(p.opts.Workers, p.n)
-	if err := parallelFor(workers, p.n, func(_ int, start, end int) error {
-		for i := start; i < end; i++ {
-			if p.eig[i] == 0 {
-				p.work.Complex[i] = 0
-				continue
-			}
-			p.work.Complex[i] /= complex(p.eig[i], 0)
-		}
-		return nil
-	}); err != nil {
+	if err := parallelRun(workers, p.n, p, p.work.Complex, (*Plan1DPeriodic).divide); err != nil {
 		return err
 	}
 
-	if err := p.fft.TransformLines(p.work.Complex, p.shape, 0, true); err != nil {
-		return fmt.Errorf("FFT inverse: %w", err)
-	}
-
 	addMean := 0.0
 	if p.opts.SolutionMean != nil {
 		addMean = *p.opts.SolutionMean
 	}
 
-	for i := range p.n {
-		dst[i] = real(p.work.Complex[i]) + addMean
+	if err := p.fft.inverseLinesReal(p.work.Complex, p.shape, 0, dst, addMean); err != nil {
+		return transformError("inverse", "FFT", 0, p.shape, 1, err)
 	}
 
 	return nil
 }
 
+// divide divides modes [start, end) of spec by their eigenvalues, dropping
+// the zero mode.
+func (p *Plan1DPeriodic) divide(spec []complex128, _ int, start, end int) error {
+	for i := start; i < end; i++ {
+		if p.eig[i] == 0 {
+			spec[i] = 0
+			continue
+		}
+		spec[i] /= complex(p.eig[i], 0)
+	}
+	return nil
+}
+
 // SolveInPlace solves the system in-place, overwriting buf with the solution.
 func (p *Plan1DPeriodic) SolveInPlace(buf []float64) error {
 	return p.Solve(buf, buf)
@@ -2,7 +2,8 @@ package poisson
 
 import (
 	"fmt"
-	"log"
+	"slices"
+	"strings"
 
 	algofft "github.com/MeKo-Christian/algo-fft"
 	"github.com/MeKo-Tech/algo-pde/grid"
@@ -18,55 +19,113 @@ type Plan2DPeriodic struct {
 	fftX   *FFTPlan
 	fftY   *FFTPlan
 	work   Workspace
-	rfft   *algofft.PlanReal2D
+	wsrc   workspaceSource
+	rfft   realTransform[float32, complex64]
 	rbuf   []float32
 	rspec  []complex64
 	rhalf  int
 	useR   bool
+	r64    *realFFT64
 	opts   Options
-	shape  grid.Shape
+	// realFFT records how WithRealFFT was resolved, for DebugDump.
+	realFFT string
+	report  PlanReport
+	shape   grid.Shape
 }
 
 // NewPlan2DPeriodic creates a new 2D periodic Poisson plan.
 func NewPlan2DPeriodic(nx, ny int, hx, hy float64, opts ...Option) (*Plan2DPeriodic, error) {
-	if nx < 1 || ny < 1 {
-		return nil, ErrInvalidSize
+	if err := checkGrid([]int{nx, ny}, []float64{hx, hy}); err != nil {
+		return nil, err
 	}
 
-	if hx <= 0 || hy <= 0 {
-		return nil, ErrInvalidSpacing
+	options, err := ApplyOptions(DefaultOptions(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if options.AutoTune > 0 {
+		build := func(opts ...Option) (*Plan2DPeriodic, error) {
+			return NewPlan2DPeriodic(nx, ny, hx, hy, opts...)
+		}
+		size := func(p *Plan2DPeriodic) int {
+			return p.nx * p.ny
+		}
+
+		plan, entry, err := autoTune(options.AutoTune, tuneCandidates(options, true), opts, build, size, (*Plan2DPeriodic).Solve)
+		if err != nil {
+			return nil, err
+		}
+		plan.report.Options = append(plan.report.Options, entry)
+
+		return plan, nil
 	}
 
-	options := ApplyOptions(DefaultOptions(), opts)
+	report := newPlanReport("Plan2DPeriodic", options,
+		periodicSupported("WithWorkers", "WithRealFFT", "WithRealFFTPrecision", "WithTransformStrategy")...)
 	options.Workers = effectiveWorkers(options.Workers)
 
 	var (
 		fftX  *FFTPlan
 		fftY  *FFTPlan
-		rfft  *algofft.PlanReal2D
+		rfft  realTransform[float32, complex64]
 		rbuf  []float32
 		rspec []complex64
 		rhalf int
 		useR  bool
+		r64   *realFFT64
 	)
 
+	realFFT := "not requested"
+
 	if options.UseRealFFT {
-		if ny%2 != 0 || ny < 2 || !isPowerOfTwo(nx) || !isPowerOfTwo(ny) {
-			log.Printf("poisson: real FFT disabled for 2D plan (nx=%d, ny=%d): requires even ny and power-of-two sizes", nx, ny)
+		// The half spectrum is stored along y, which must be even. algo-fft's
+		// real FFTs give wrong spectra for sizes that are not powers of two
+		// (e.g. 100×100 or 360×180), so those run on packedRealGrid32.
+		if ny%2 != 0 || ny < 2 {
+			realFFT = "disabled: requires even ny"
+		} else if options.RealFFTPrecision == RealFFTFloat64 {
+			r, err := newRealFFT64(grid.NewShape2D(nx, ny), 1, options.Workers)
+			if err != nil {
+				realFFT = fmt.Sprintf("disabled: %v", err)
+			} else {
+				r64 = r
+				rhalf = ny/2 + 1
+				useR = true
+				realFFT = "enabled (float64)"
+			}
 		} else {
-			plan, err := algofft.NewPlanReal2D(nx, ny)
+			var (
+				plan realTransform[float32, complex64]
+				err  error
+			)
+			if isPowerOfTwo(nx) && isPowerOfTwo(ny) {
+				plan, err = algofft.NewPlanReal2D(nx, ny)
+			} else {
+				plan, err = newPackedRealGrid32(grid.NewShape2D(nx, ny), 1)
+			}
 			if err != nil {
-				log.Printf("poisson: real FFT disabled for 2D plan (nx=%d, ny=%d): %v", nx, ny, err)
+				realFFT = fmt.Sprintf("disabled: %v", err)
 			} else {
 				rfft = plan
 				rhalf = ny/2 + 1
 				rbuf = make([]float32, nx*ny)
 				rspec = make([]complex64, nx*rhalf)
 				useR = true
+				realFFT = "enabled"
 			}
 		}
 	}
 
+	if options.UseRealFFT && !useR {
+		report.set("WithRealFFT", OptionDowngraded, strings.TrimPrefix(realFFT, "disabled: ")+"; complex FFTs are used")
+	}
+	if useR {
+		report.set("WithTransformStrategy", OptionRejected, "not used by the real FFT path")
+	}
+	if !useR {
+		report.set("WithRealFFTPrecision", OptionRejected, "real FFT not in use")
+	}
+
 	if !useR {
 		var err error
 		fftX, err = NewFFTPlanWithWorkers(nx, options.Workers)
@@ -80,24 +139,50 @@ func NewPlan2DPeriodic(nx, ny int, hx, hy float64, opts ...Option) (*Plan2DPerio
 		}
 	}
 
-	return &Plan2DPeriodic{
-		nx:    nx,
-		ny:    ny,
-		hx:    hx,
-		hy:    hy,
-		eigX:  eigenvaluesPeriodic(nx, hx),
-		eigY:  eigenvaluesPeriodic(ny, hy),
-		fftX:  fftX,
-		fftY:  fftY,
-		work:  NewWorkspace(0, nx*ny),
-		rfft:  rfft,
-		rbuf:  rbuf,
-		rspec: rspec,
-		rhalf: rhalf,
-		useR:  useR,
-		opts:  options,
-		shape: grid.NewShape2D(nx, ny),
-	}, nil
+	wsrc, work := newWorkspaceSource(options, 0, nx*ny)
+
+	plan := &Plan2DPeriodic{
+		nx:      nx,
+		ny:      ny,
+		hx:      hx,
+		hy:      hy,
+		eigX:    eigenvaluesPeriodic(nx, hx),
+		eigY:    eigenvaluesPeriodic(ny, hy),
+		fftX:    fftX,
+		fftY:    fftY,
+		work:    work,
+		wsrc:    wsrc,
+		rfft:    rfft,
+		rbuf:    rbuf,
+		rspec:   rspec,
+		rhalf:   rhalf,
+		useR:    useR,
+		r64:     r64,
+		realFFT: realFFT,
+		report:  report,
+		opts:    options,
+		shape:   grid.NewShape2D(nx, ny),
+	}
+
+	if !useR {
+		plan.wsrc.acquire(&plan.work)
+		defer plan.wsrc.release(&plan.work)
+
+		if err := applyTransformStrategy(&fftAxisTransform{plan: fftX}, options.TransformStrategy, plan.work.Complex, plan.shape, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// Report returns how the plan resolved its options, including whether
+// WithRealFFT took effect. See PlanReport.
+func (p *Plan2DPeriodic) Report() PlanReport {
+	r := p.report
+	r.Options = slices.Clone(r.Options)
+
+	return r
 }
 
 // Solve computes the solution into dst for a given RHS.
@@ -106,14 +191,23 @@ func (p *Plan2DPeriodic) Solve(dst, rhs []float64) error {
 		return ErrNilBuffer
 	}
 
-	if len(dst) != p.nx*p.ny || len(rhs) != p.nx*p.ny {
-		return ErrSizeMismatch
+	if err := checkBuffers("Solve", p.nx*p.ny, dst, rhs, "rhs"); err != nil {
+		return err
+	}
+
+	if p.opts.CheckFinite {
+		if err := checkFinite("Solve rhs", rhs); err != nil {
+			return err
+		}
 	}
 
 	if p.opts.Nullspace == NullspaceError {
 		return ErrNullspace
 	}
 
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
 	mean, maxAbs := meanAndMaxAbs(rhs)
 	if p.opts.Nullspace == NullspaceZeroMode && !meanWithinTolerance(mean, maxAbs) {
 		return ErrNonZeroMean
@@ -124,35 +218,26 @@ func (p *Plan2DPeriodic) Solve(dst, rhs []float64) error {
 		offset = mean
 	}
 
+	if p.r64 != nil {
+		return p.solveReal64(dst, rhs, offset)
+	}
+
 	if p.useR {
 		for i, v := range rhs {
 			p.rbuf[i] = float32(v - offset)
 		}
 
 		if err := p.rfft.Forward(p.rspec, p.rbuf); err != nil {
-			return fmt.Errorf("real FFT forward: %w", err)
+			return transformError("forward", "real FFT", -1, p.shape, 2, err)
 		}
 
 		workers := clampWorkers(p.opts.Workers, p.nx)
-		if err := parallelFor(workers, p.nx, func(_ int, start, end int) error {
-			for i := start; i < end; i++ {
-				base := i * p.rhalf
-				for j := 0; j < p.rhalf; j++ {
-					denom := p.eigX[i] + p.eigY[j]
-					if denom == 0 {
-						p.rspec[base+j] = 0
-						continue
-					}
-					p.rspec[base+j] /= complex(float32(denom), 0)
-				}
-			}
-			return nil
-		}); err != nil {
+		if err := parallelRun(workers, p.nx, p, p.rspec, (*Plan2DPeriodic).divideReal); err != nil {
 			return err
 		}
 
 		if err := p.rfft.Inverse(p.rbuf, p.rspec); err != nil {
-			return fmt.Errorf("real FFT inverse: %w", err)
+			return transformError("inverse", "real FFT", -1, p.shape, 2, err)
 		}
 
 		addMean := 0.0
@@ -172,37 +257,20 @@ func (p *Plan2DPeriodic) Solve(dst, rhs []float64) error {
 	}
 
 	if err := p.fftX.TransformLines(p.work.Complex, p.shape, 0, false); err != nil {
-		return fmt.Errorf("FFT forward axis 0: %w", err)
+		return transformError("forward", "FFT", 0, p.shape, 2, err)
 	}
 
 	if err := p.fftY.TransformLines(p.work.Complex, p.shape, 1, false); err != nil {
-		return fmt.Errorf("FFT forward axis 1: %w", err)
+		return transformError("forward", "FFT", 1, p.shape, 2, err)
 	}
 
 	workers := clampWorkers(p.opts.Workers, p.nx)
-	if err := parallelFor(workers, p.nx, func(_ int, start, end int) error {
-		for i := start; i < end; i++ {
-			base := i * p.ny
-			for j := 0; j < p.ny; j++ {
-				denom := p.eigX[i] + p.eigY[j]
-				if denom == 0 {
-					p.work.Complex[base+j] = 0
-					continue
-				}
-				p.work.Complex[base+j] /= complex(denom, 0)
-			}
-		}
-		return nil
-	}); err != nil {
+	if err := parallelRun(workers, p.nx, p, p.work.Complex, (*Plan2DPeriodic).divide); err != nil {
 		return err
 	}
 
 	if err := p.fftY.TransformLines(p.work.Complex, p.shape, 1, true); err != nil {
-		return fmt.Errorf("FFT inverse axis 1: %w", err)
-	}
-
-	if err := p.fftX.TransformLines(p.work.Complex, p.shape, 0, true); err != nil {
-		return fmt.Errorf("FFT inverse axis 0: %w", err)
+		return transformError("inverse", "FFT", 1, p.shape, 2, err)
 	}
 
 	addMean := 0.0
@@ -210,13 +278,82 @@ func (p *Plan2DPeriodic) Solve(dst, rhs []float64) error {
 		addMean = *p.opts.SolutionMean
 	}
 
-	for i := range p.nx * p.ny {
-		dst[i] = real(p.work.Complex[i]) + addMean
+	if err := p.fftX.inverseLinesReal(p.work.Complex, p.shape, 0, dst, addMean); err != nil {
+		return transformError("inverse", "FFT", 0, p.shape, 2, err)
+	}
+
+	return nil
+}
+
+// divide divides rows [start, end) of spec by their eigenvalues, dropping
+// the zero mode.
+func (p *Plan2DPeriodic) divide(spec []complex128, _ int, start, end int) error {
+	for i := start; i < end; i++ {
+		base := i * p.ny
+		for j := 0; j < p.ny; j++ {
+			denom := p.eigX[i] + p.eigY[j]
+			if denom == 0 {
+				spec[base+j] = 0
+				continue
+			}
+			spec[base+j] /= complex(denom, 0)
+		}
 	}
+	return nil
+}
 
+// divideReal is divide for the half spectrum of the real FFT path.
+func (p *Plan2DPeriodic) divideReal(spec []complex64, _ int, start, end int) error {
+	for i := start; i < end; i++ {
+		base := i * p.rhalf
+		for j := 0; j < p.rhalf; j++ {
+			denom := p.eigX[i] + p.eigY[j]
+			if denom == 0 {
+				spec[base+j] = 0
+				continue
+			}
+			spec[base+j] /= complex(float32(denom), 0)
+		}
+	}
 	return nil
 }
 
+// divideReal64 is divideReal for the float64 real FFT path.
+func (p *Plan2DPeriodic) divideReal64(spec []complex128, _ int, start, end int) error {
+	for i := start; i < end; i++ {
+		base := i * p.rhalf
+		for j := 0; j < p.rhalf; j++ {
+			denom := p.eigX[i] + p.eigY[j]
+			if denom == 0 {
+				spec[base+j] = 0
+				continue
+			}
+			spec[base+j] /= complex(denom, 0)
+		}
+	}
+	return nil
+}
+
+// solveReal64 is Solve on the float64 real FFT path, with the RHS offset
+// of the nullspace handling already computed.
+func (p *Plan2DPeriodic) solveReal64(dst, rhs []float64, offset float64) error {
+	if err := p.r64.forward(rhs, offset); err != nil {
+		return err
+	}
+
+	workers := clampWorkers(p.opts.Workers, p.nx)
+	if err := parallelRun(workers, p.nx, p, p.r64.spec, (*Plan2DPeriodic).divideReal64); err != nil {
+		return err
+	}
+
+	addMean := 0.0
+	if p.opts.SolutionMean != nil {
+		addMean = *p.opts.SolutionMean
+	}
+
+	return p.r64.inverse(dst, addMean)
+}
+
 // SolveInPlace solves the system in-place, overwriting buf with the solution.
 func (p *Plan2DPeriodic) SolveInPlace(buf []float64) error {
 	return p.Solve(buf, buf)
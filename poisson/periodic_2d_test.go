@@ -134,6 +134,67 @@ func TestPlan2DPeriodic_Solve_RealFFT(t *testing.T) {
 	}
 }
 
+// Non-power-of-two sizes fall back to complex FFTs, since algo-fft's real
+// FFT is inaccurate for them, and still solve accurately.
+func TestPlan2DPeriodic_Solve_RealFFTNonPowerOfTwo(t *testing.T) {
+	precisions := []struct {
+		precision poisson.RealFFTPrecision
+		tol       float64
+	}{
+		{poisson.RealFFTFloat32, periodic2dRealTol},
+		{poisson.RealFFTFloat64, 1e-10},
+	}
+
+	for _, size := range [][2]int{{20, 12}, {15, 18}, {100, 100}} {
+		nx, ny := size[0], size[1]
+		hx := 1.0 / float64(nx)
+		hy := 1.0 / float64(ny)
+
+		u := make([]float64, nx*ny)
+		for i := range nx {
+			for j := range ny {
+				u[i*ny+j] = math.Sin(2*math.Pi*float64(i)*hx) * math.Cos(4*math.Pi*float64(j)*hy)
+			}
+		}
+
+		rhs := make([]float64, nx*ny)
+		fd.Apply2D(rhs, u, grid.NewShape2D(nx, ny), [2]float64{hx, hy}, [2]poisson.BCType{
+			poisson.Periodic, poisson.Periodic,
+		})
+
+		complexPlan, err := poisson.NewPlan2DPeriodic(nx, ny, hx, hy)
+		if err != nil {
+			t.Fatalf("NewPlan2DPeriodic(%d, %d) failed: %v", nx, ny, err)
+		}
+
+		want := make([]float64, nx*ny)
+		if err := complexPlan.Solve(want, rhs); err != nil {
+			t.Fatalf("complex Solve failed: %v", err)
+		}
+
+		for _, tc := range precisions {
+			plan, err := poisson.NewPlan2DPeriodic(nx, ny, hx, hy,
+				poisson.WithRealFFT(true), poisson.WithRealFFTPrecision(tc.precision))
+			if err != nil {
+				t.Fatalf("NewPlan2DPeriodic(%d, %d) failed: %v", nx, ny, err)
+			}
+			requireStatus(t, plan.Report(), "WithRealFFT", poisson.OptionHonored)
+
+			got := make([]float64, nx*ny)
+			if err := plan.Solve(got, rhs); err != nil {
+				t.Fatalf("Solve failed: %v", err)
+			}
+
+			if max := maxAbsDiff(got, want); max > tc.tol {
+				t.Errorf("%dx%d %v: max difference to complex path %g exceeds tol %g", nx, ny, tc.precision, max, tc.tol)
+			}
+			if max := maxAbsDiff(got, u); max > periodic2dRealTol {
+				t.Errorf("%dx%d %v: max error %g exceeds tol %g", nx, ny, tc.precision, max, periodic2dRealTol)
+			}
+		}
+	}
+}
+
 func TestPlan2DPeriodic_Convergence(t *testing.T) {
 	sizes := []int{16, 32, 64}
 	errors := make([]float64, len(sizes))
@@ -2,7 +2,8 @@ package poisson
 
 import (
 	"fmt"
-	"log"
+	"slices"
+	"strings"
 
 	algofft "github.com/MeKo-Christian/algo-fft"
 	"github.com/MeKo-Tech/algo-pde/grid"
@@ -20,56 +21,113 @@ type Plan3DPeriodic struct {
 	fftY       *FFTPlan
 	fftZ       *FFTPlan
 	work       Workspace
-	rfft       *algofft.PlanReal3D
+	wsrc       workspaceSource
+	rfft       realTransform[float32, complex64]
 	rbuf       []float32
 	rspec      []complex64
 	rhalf      int
 	useR       bool
+	r64        *realFFT64
 	opts       Options
-	shape      grid.Shape
+	// realFFT records how WithRealFFT was resolved, for DebugDump.
+	realFFT string
+	report  PlanReport
+	shape   grid.Shape
 }
 
 // NewPlan3DPeriodic creates a new 3D periodic Poisson plan.
 func NewPlan3DPeriodic(nx, ny, nz int, hx, hy, hz float64, opts ...Option) (*Plan3DPeriodic, error) {
-	if nx < 1 || ny < 1 || nz < 1 {
-		return nil, ErrInvalidSize
+	if err := checkGrid([]int{nx, ny, nz}, []float64{hx, hy, hz}); err != nil {
+		return nil, err
 	}
 
-	if hx <= 0 || hy <= 0 || hz <= 0 {
-		return nil, ErrInvalidSpacing
+	options, err := ApplyOptions(DefaultOptions(), opts)
+	if err != nil {
+		return nil, err
 	}
+	if options.AutoTune > 0 {
+		build := func(opts ...Option) (*Plan3DPeriodic, error) {
+			return NewPlan3DPeriodic(nx, ny, nz, hx, hy, hz, opts...)
+		}
+		size := func(p *Plan3DPeriodic) int {
+			return p.nx * p.ny * p.nz
+		}
+
+		plan, entry, err := autoTune(options.AutoTune, tuneCandidates(options, true), opts, build, size, (*Plan3DPeriodic).Solve)
+		if err != nil {
+			return nil, err
+		}
+		plan.report.Options = append(plan.report.Options, entry)
 
-	options := ApplyOptions(DefaultOptions(), opts)
+		return plan, nil
+	}
+
+	report := newPlanReport("Plan3DPeriodic", options,
+		periodicSupported("WithWorkers", "WithRealFFT", "WithRealFFTPrecision", "WithTransformStrategy")...)
 	options.Workers = effectiveWorkers(options.Workers)
 
 	var (
 		fftX  *FFTPlan
 		fftY  *FFTPlan
 		fftZ  *FFTPlan
-		rfft  *algofft.PlanReal3D
+		rfft  realTransform[float32, complex64]
 		rbuf  []float32
 		rspec []complex64
 		rhalf int
 		useR  bool
+		r64   *realFFT64
 	)
 
+	realFFT := "not requested"
+
 	if options.UseRealFFT {
-		if nz%2 != 0 || nz < 2 || !isPowerOfTwo(nx) || !isPowerOfTwo(ny) || !isPowerOfTwo(nz) {
-			log.Printf("poisson: real FFT disabled for 3D plan (nx=%d, ny=%d, nz=%d): requires even nz and power-of-two sizes", nx, ny, nz)
+		// The half spectrum is stored along z, which must be even; sizes that
+		// are not powers of two run on packedRealGrid32, see NewPlan2DPeriodic.
+		if nz%2 != 0 || nz < 2 {
+			realFFT = "disabled: requires even nz"
+		} else if options.RealFFTPrecision == RealFFTFloat64 {
+			r, err := newRealFFT64(grid.NewShape3D(nx, ny, nz), 2, options.Workers)
+			if err != nil {
+				realFFT = fmt.Sprintf("disabled: %v", err)
+			} else {
+				r64 = r
+				rhalf = nz/2 + 1
+				useR = true
+				realFFT = "enabled (float64)"
+			}
 		} else {
-			plan, err := algofft.NewPlanReal3D(nx, ny, nz)
+			var (
+				plan realTransform[float32, complex64]
+				err  error
+			)
+			if isPowerOfTwo(nx) && isPowerOfTwo(ny) && isPowerOfTwo(nz) {
+				plan, err = algofft.NewPlanReal3D(nx, ny, nz)
+			} else {
+				plan, err = newPackedRealGrid32(grid.NewShape3D(nx, ny, nz), 2)
+			}
 			if err != nil {
-				log.Printf("poisson: real FFT disabled for 3D plan (nx=%d, ny=%d, nz=%d): %v", nx, ny, nz, err)
+				realFFT = fmt.Sprintf("disabled: %v", err)
 			} else {
 				rfft = plan
 				rhalf = nz/2 + 1
 				rbuf = make([]float32, nx*ny*nz)
 				rspec = make([]complex64, nx*ny*rhalf)
 				useR = true
+				realFFT = "enabled"
 			}
 		}
 	}
 
+	if options.UseRealFFT && !useR {
+		report.set("WithRealFFT", OptionDowngraded, strings.TrimPrefix(realFFT, "disabled: ")+"; complex FFTs are used")
+	}
+	if useR {
+		report.set("WithTransformStrategy", OptionRejected, "not used by the real FFT path")
+	}
+	if !useR {
+		report.set("WithRealFFTPrecision", OptionRejected, "real FFT not in use")
+	}
+
 	if !useR {
 		var err error
 		fftX, err = NewFFTPlanWithWorkers(nx, options.Workers)
@@ -88,28 +146,56 @@ func NewPlan3DPeriodic(nx, ny, nz int, hx, hy, hz float64, opts ...Option) (*Pla
 		}
 	}
 
-	return &Plan3DPeriodic{
-		nx:    nx,
-		ny:    ny,
-		nz:    nz,
-		hx:    hx,
-		hy:    hy,
-		hz:    hz,
-		eigX:  eigenvaluesPeriodic(nx, hx),
-		eigY:  eigenvaluesPeriodic(ny, hy),
-		eigZ:  eigenvaluesPeriodic(nz, hz),
-		fftX:  fftX,
-		fftY:  fftY,
-		fftZ:  fftZ,
-		work:  NewWorkspace(0, nx*ny*nz),
-		rfft:  rfft,
-		rbuf:  rbuf,
-		rspec: rspec,
-		rhalf: rhalf,
-		useR:  useR,
-		opts:  options,
-		shape: grid.NewShape3D(nx, ny, nz),
-	}, nil
+	wsrc, work := newWorkspaceSource(options, 0, nx*ny*nz)
+
+	plan := &Plan3DPeriodic{
+		nx:      nx,
+		ny:      ny,
+		nz:      nz,
+		hx:      hx,
+		hy:      hy,
+		hz:      hz,
+		eigX:    eigenvaluesPeriodic(nx, hx),
+		eigY:    eigenvaluesPeriodic(ny, hy),
+		eigZ:    eigenvaluesPeriodic(nz, hz),
+		fftX:    fftX,
+		fftY:    fftY,
+		fftZ:    fftZ,
+		work:    work,
+		wsrc:    wsrc,
+		rfft:    rfft,
+		rbuf:    rbuf,
+		rspec:   rspec,
+		rhalf:   rhalf,
+		useR:    useR,
+		r64:     r64,
+		realFFT: realFFT,
+		report:  report,
+		opts:    options,
+		shape:   grid.NewShape3D(nx, ny, nz),
+	}
+
+	if !useR {
+		plan.wsrc.acquire(&plan.work)
+		defer plan.wsrc.release(&plan.work)
+
+		for axis, fft := range []*FFTPlan{fftX, fftY} {
+			if err := applyTransformStrategy(&fftAxisTransform{plan: fft}, options.TransformStrategy, plan.work.Complex, plan.shape, axis); err != nil {
+				return nil, transformError("setup", "FFT", axis, plan.shape, 3, err)
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Report returns how the plan resolved its options, including whether
+// WithRealFFT took effect. See PlanReport.
+func (p *Plan3DPeriodic) Report() PlanReport {
+	r := p.report
+	r.Options = slices.Clone(r.Options)
+
+	return r
 }
 
 // Solve computes the solution into dst for a given RHS.
@@ -118,10 +204,19 @@ func (p *Plan3DPeriodic) Solve(dst, rhs []float64) error {
 		return ErrNilBuffer
 	}
 
-	if len(dst) != p.nx*p.ny*p.nz || len(rhs) != p.nx*p.ny*p.nz {
-		return ErrSizeMismatch
+	if err := checkBuffers("Solve", p.nx*p.ny*p.nz, dst, rhs, "rhs"); err != nil {
+		return err
+	}
+
+	if p.opts.CheckFinite {
+		if err := checkFinite("Solve rhs", rhs); err != nil {
+			return err
+		}
 	}
 
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
 	if p.opts.Nullspace == NullspaceError {
 		return ErrNullspace
 	}
@@ -136,39 +231,26 @@ func (p *Plan3DPeriodic) Solve(dst, rhs []float64) error {
 		offset = mean
 	}
 
+	if p.r64 != nil {
+		return p.solveReal64(dst, rhs, offset)
+	}
+
 	if p.useR {
 		for i, v := range rhs {
 			p.rbuf[i] = float32(v - offset)
 		}
 
 		if err := p.rfft.Forward(p.rspec, p.rbuf); err != nil {
-			return fmt.Errorf("real FFT forward: %w", err)
+			return transformError("forward", "real FFT", -1, p.shape, 3, err)
 		}
 
 		workers := clampWorkers(p.opts.Workers, p.nx)
-		if err := parallelFor(workers, p.nx, func(_ int, start, end int) error {
-			for i := start; i < end; i++ {
-				baseXY := i * p.ny * p.rhalf
-				for j := 0; j < p.ny; j++ {
-					base := baseXY + j*p.rhalf
-					xy := p.eigX[i] + p.eigY[j]
-					for k := 0; k < p.rhalf; k++ {
-						denom := xy + p.eigZ[k]
-						if denom == 0 {
-							p.rspec[base+k] = 0
-							continue
-						}
-						p.rspec[base+k] /= complex(float32(denom), 0)
-					}
-				}
-			}
-			return nil
-		}); err != nil {
+		if err := parallelRun(workers, p.nx, p, p.rspec, (*Plan3DPeriodic).divideReal); err != nil {
 			return err
 		}
 
 		if err := p.rfft.Inverse(p.rbuf, p.rspec); err != nil {
-			return fmt.Errorf("real FFT inverse: %w", err)
+			return transformError("inverse", "real FFT", -1, p.shape, 3, err)
 		}
 
 		addMean := 0.0
@@ -188,49 +270,28 @@ func (p *Plan3DPeriodic) Solve(dst, rhs []float64) error {
 	}
 
 	if err := p.fftX.TransformLines(p.work.Complex, p.shape, 0, false); err != nil {
-		return fmt.Errorf("FFT forward axis 0: %w", err)
+		return transformError("forward", "FFT", 0, p.shape, 3, err)
 	}
 
 	if err := p.fftY.TransformLines(p.work.Complex, p.shape, 1, false); err != nil {
-		return fmt.Errorf("FFT forward axis 1: %w", err)
+		return transformError("forward", "FFT", 1, p.shape, 3, err)
 	}
 
 	if err := p.fftZ.TransformLines(p.work.Complex, p.shape, 2, false); err != nil {
-		return fmt.Errorf("FFT forward axis 2: %w", err)
+		return transformError("forward", "FFT", 2, p.shape, 3, err)
 	}
 
 	workers := clampWorkers(p.opts.Workers, p.nx)
-	if err := parallelFor(workers, p.nx, func(_ int, start, end int) error {
-		for i := start; i < end; i++ {
-			baseXY := i * p.ny * p.nz
-			for j := 0; j < p.ny; j++ {
-				base := baseXY + j*p.nz
-				xy := p.eigX[i] + p.eigY[j]
-				for k := 0; k < p.nz; k++ {
-					denom := xy + p.eigZ[k]
-					if denom == 0 {
-						p.work.Complex[base+k] = 0
-						continue
-					}
-					p.work.Complex[base+k] /= complex(denom, 0)
-				}
-			}
-		}
-		return nil
-	}); err != nil {
+	if err := parallelRun(workers, p.nx, p, p.work.Complex, (*Plan3DPeriodic).divide); err != nil {
 		return err
 	}
 
 	if err := p.fftZ.TransformLines(p.work.Complex, p.shape, 2, true); err != nil {
-		return fmt.Errorf("FFT inverse axis 2: %w", err)
+		return transformError("inverse", "FFT", 2, p.shape, 3, err)
 	}
 
 	if err := p.fftY.TransformLines(p.work.Complex, p.shape, 1, true); err != nil {
-		return fmt.Errorf("FFT inverse axis 1: %w", err)
-	}
-
-	if err := p.fftX.TransformLines(p.work.Complex, p.shape, 0, true); err != nil {
-		return fmt.Errorf("FFT inverse axis 0: %w", err)
+		return transformError("inverse", "FFT", 1, p.shape, 3, err)
 	}
 
 	addMean := 0.0
@@ -238,13 +299,94 @@ func (p *Plan3DPeriodic) Solve(dst, rhs []float64) error {
 		addMean = *p.opts.SolutionMean
 	}
 
-	for i := range p.nx * p.ny * p.nz {
-		dst[i] = real(p.work.Complex[i]) + addMean
+	if err := p.fftX.inverseLinesReal(p.work.Complex, p.shape, 0, dst, addMean); err != nil {
+		return transformError("inverse", "FFT", 0, p.shape, 3, err)
 	}
 
 	return nil
 }
 
+// divide divides x-planes [start, end) of spec by their eigenvalues,
+// dropping the zero mode.
+func (p *Plan3DPeriodic) divide(spec []complex128, _ int, start, end int) error {
+	for i := start; i < end; i++ {
+		baseXY := i * p.ny * p.nz
+		for j := 0; j < p.ny; j++ {
+			base := baseXY + j*p.nz
+			xy := p.eigX[i] + p.eigY[j]
+			for k := 0; k < p.nz; k++ {
+				denom := xy + p.eigZ[k]
+				if denom == 0 {
+					spec[base+k] = 0
+					continue
+				}
+				spec[base+k] /= complex(denom, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// divideReal is divide for the half spectrum of the real FFT path.
+func (p *Plan3DPeriodic) divideReal(spec []complex64, _ int, start, end int) error {
+	for i := start; i < end; i++ {
+		baseXY := i * p.ny * p.rhalf
+		for j := 0; j < p.ny; j++ {
+			base := baseXY + j*p.rhalf
+			xy := p.eigX[i] + p.eigY[j]
+			for k := 0; k < p.rhalf; k++ {
+				denom := xy + p.eigZ[k]
+				if denom == 0 {
+					spec[base+k] = 0
+					continue
+				}
+				spec[base+k] /= complex(float32(denom), 0)
+			}
+		}
+	}
+	return nil
+}
+
+// divideReal64 is divideReal for the float64 real FFT path.
+func (p *Plan3DPeriodic) divideReal64(spec []complex128, _ int, start, end int) error {
+	for i := start; i < end; i++ {
+		baseXY := i * p.ny * p.rhalf
+		for j := 0; j < p.ny; j++ {
+			base := baseXY + j*p.rhalf
+			xy := p.eigX[i] + p.eigY[j]
+			for k := 0; k < p.rhalf; k++ {
+				denom := xy + p.eigZ[k]
+				if denom == 0 {
+					spec[base+k] = 0
+					continue
+				}
+				spec[base+k] /= complex(denom, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// solveReal64 is Solve on the float64 real FFT path, with the RHS offset
+// of the nullspace handling already computed.
+func (p *Plan3DPeriodic) solveReal64(dst, rhs []float64, offset float64) error {
+	if err := p.r64.forward(rhs, offset); err != nil {
+		return err
+	}
+
+	workers := clampWorkers(p.opts.Workers, p.nx)
+	if err := parallelRun(workers, p.nx, p, p.r64.spec, (*Plan3DPeriodic).divideReal64); err != nil {
+		return err
+	}
+
+	addMean := 0.0
+	if p.opts.SolutionMean != nil {
+		addMean = *p.opts.SolutionMean
+	}
+
+	return p.r64.inverse(dst, addMean)
+}
+
 // SolveInPlace solves the system in-place, overwriting buf with the solution.
 func (p *Plan3DPeriodic) SolveInPlace(buf []float64) error {
 	return p.Solve(buf, buf)
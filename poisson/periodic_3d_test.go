@@ -117,6 +117,65 @@ func TestPlan3DPeriodic_Solve_RealFFT(t *testing.T) {
 	}
 }
 
+// Non-power-of-two sizes fall back to complex FFTs, since algo-fft's real
+// FFT is inaccurate for them, and still solve accurately.
+func TestPlan3DPeriodic_Solve_RealFFTNonPowerOfTwo(t *testing.T) {
+	nx, ny, nz := 6, 5, 10
+	hx, hy, hz := 1.0/float64(nx), 1.0/float64(ny), 1.0/float64(nz)
+
+	u := make([]float64, nx*ny*nz)
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				u[(i*ny+j)*nz+k] = math.Cos(2*math.Pi*float64(i)*hx) * math.Sin(2*math.Pi*float64(j)*hy) *
+					math.Cos(2*math.Pi*float64(k)*hz)
+			}
+		}
+	}
+
+	rhs := make([]float64, nx*ny*nz)
+	fd.Apply3D(rhs, u, grid.NewShape3D(nx, ny, nz), [3]float64{hx, hy, hz}, [3]poisson.BCType{
+		poisson.Periodic, poisson.Periodic, poisson.Periodic,
+	})
+
+	complexPlan, err := poisson.NewPlan3DPeriodic(nx, ny, nz, hx, hy, hz)
+	if err != nil {
+		t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+	}
+
+	want := make([]float64, nx*ny*nz)
+	if err := complexPlan.Solve(want, rhs); err != nil {
+		t.Fatalf("complex Solve failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		precision poisson.RealFFTPrecision
+		tol       float64
+	}{
+		{poisson.RealFFTFloat32, periodic3dRealTol},
+		{poisson.RealFFTFloat64, 1e-10},
+	} {
+		plan, err := poisson.NewPlan3DPeriodic(nx, ny, nz, hx, hy, hz,
+			poisson.WithRealFFT(true), poisson.WithRealFFTPrecision(tc.precision))
+		if err != nil {
+			t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+		}
+		requireStatus(t, plan.Report(), "WithRealFFT", poisson.OptionHonored)
+
+		got := make([]float64, nx*ny*nz)
+		if err := plan.Solve(got, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+
+		if max := maxAbsDiff(got, want); max > tc.tol {
+			t.Errorf("%v: max difference to complex path %g exceeds tol %g", tc.precision, max, tc.tol)
+		}
+		if max := maxAbsDiff(got, u); max > periodic3dRealTol {
+			t.Errorf("%v: max error %g exceeds tol %g", tc.precision, max, periodic3dRealTol)
+		}
+	}
+}
+
 func TestPlan3DPeriodic_NonZeroMean_Default(t *testing.T) {
 	nx, ny, nz := 6, 6, 6
 	hx, hy, hz := 1.0, 1.0, 1.0
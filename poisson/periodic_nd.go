@@ -2,9 +2,10 @@ package poisson
 
 import (
 	"fmt"
-	"log"
+	"slices"
 
 	algofft "github.com/MeKo-Christian/algo-fft"
+	"github.com/MeKo-Tech/algo-pde/grid"
 )
 
 // PlanNDPeriodic is a reusable plan for solving N-dimensional periodic Poisson problems.
@@ -16,23 +17,23 @@ type PlanNDPeriodic struct {
 	fft    []*axisPlan
 	stride []int
 	work   Workspace
+	wsrc   workspaceSource
 	opts   Options
+	report PlanReport
 
 	eigIndices []int
-	axisDims   [][]int
-	axisIdx    [][]int
-	axisOther  [][]int
+	lines      []*grid.NDLineIterator
 }
 
 // NewPlanNDPeriodic creates a new N-dimensional periodic Poisson plan.
 func NewPlanNDPeriodic(shape Shape, h []float64, opts ...Option) (*PlanNDPeriodic, error) {
 	if len(shape) == 0 {
-		return nil, ErrInvalidSize
+		return nil, &ValidationError{Field: "shape", Message: "must have at least one axis", Err: ErrInvalidSize}
 	}
 
-	for _, n := range shape {
+	for axis, n := range shape {
 		if n < 1 {
-			return nil, ErrInvalidSize
+			return nil, invalidSize(fmt.Sprintf("shape[%d]", axis))
 		}
 	}
 
@@ -43,16 +44,18 @@ func NewPlanNDPeriodic(shape Shape, h []float64, opts ...Option) (*PlanNDPeriodi
 		}
 	}
 
-	for _, spacing := range h {
+	for axis, spacing := range h {
 		if spacing <= 0 {
-			return nil, ErrInvalidSpacing
+			return nil, invalidSpacing(fmt.Sprintf("h[%d]", axis))
 		}
 	}
 
-	options := ApplyOptions(DefaultOptions(), opts)
-	if options.UseRealFFT {
-		log.Printf("poisson: real FFT disabled for ND plan: not supported for arbitrary dimensions")
+	options, err := ApplyOptions(DefaultOptions(), opts)
+	if err != nil {
+		return nil, err
 	}
+	report := newPlanReport("PlanNDPeriodic", options, periodicSupported("WithRealFFT")...)
+	report.set("WithRealFFT", OptionDowngraded, "not supported for arbitrary dimensions; complex FFTs are used")
 
 	dims := make(Shape, len(shape))
 	copy(dims, shape)
@@ -69,66 +72,64 @@ func NewPlanNDPeriodic(shape Shape, h []float64, opts ...Option) (*PlanNDPeriodi
 	for i, n := range dims {
 		plan, err := newAxisPlan(n)
 		if err != nil {
-			return nil, fmt.Errorf("creating FFT plan for axis %d: %w", i, err)
+			return nil, &TransformError{Stage: "setup", Transform: "FFT", Axis: i, Shape: dims, Err: err}
 		}
 		plans[i] = plan
 	}
 
-	stride := make([]int, len(dims))
-	step := 1
-	for i := len(dims) - 1; i >= 0; i-- {
-		stride[i] = step
-		step *= dims[i]
-	}
-
-	axisDims := make([][]int, len(dims))
-	axisIdx := make([][]int, len(dims))
-	axisOther := make([][]int, len(dims))
+	lines := make([]*grid.NDLineIterator, len(dims))
 	for axis := range dims {
-		reduced := make([]int, 0, len(dims)-1)
-		other := make([]int, 0, len(dims)-1)
-		for d := range dims {
-			if d == axis {
-				continue
-			}
-			reduced = append(reduced, dims[d])
-			other = append(other, d)
-		}
-		axisDims[axis] = reduced
-		axisIdx[axis] = make([]int, len(reduced))
-		axisOther[axis] = other
+		lines[axis] = grid.NewNDLineIterator(dims, axis)
 	}
 
+	wsrc, work := newWorkspaceSource(options, 0, dims.Size())
+
 	return &PlanNDPeriodic{
 		shape:      dims,
 		h:          hCopy,
 		eig:        eig,
 		fft:        plans,
-		stride:     stride,
-		work:       NewWorkspace(0, dims.Size()),
+		stride:     dims.Strides(),
+		work:       work,
+		wsrc:       wsrc,
 		opts:       options,
+		report:     report,
 		eigIndices: make([]int, len(dims)),
-		axisDims:   axisDims,
-		axisIdx:    axisIdx,
-		axisOther:  axisOther,
+		lines:      lines,
 	}, nil
 }
 
+// Report returns how the plan resolved its options. See PlanReport.
+func (p *PlanNDPeriodic) Report() PlanReport {
+	r := p.report
+	r.Options = slices.Clone(r.Options)
+
+	return r
+}
+
 // Solve computes the solution into dst for a given RHS.
 func (p *PlanNDPeriodic) Solve(dst, rhs []float64) error {
 	if dst == nil || rhs == nil {
 		return ErrNilBuffer
 	}
 
-	size := p.shape.Size()
-	if len(dst) != size || len(rhs) != size {
-		return ErrSizeMismatch
+	if err := checkBuffers("Solve", p.shape.Size(), dst, rhs, "rhs"); err != nil {
+		return err
+	}
+
+	if p.opts.CheckFinite {
+		if err := checkFinite("Solve rhs", rhs); err != nil {
+			return err
+		}
 	}
 
 	if p.opts.Nullspace == NullspaceError {
 		return ErrNullspace
 	}
 
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
 	mean, maxAbs := meanAndMaxAbs(rhs)
 	if p.opts.Nullspace == NullspaceZeroMode && !meanWithinTolerance(mean, maxAbs) {
 		return ErrNonZeroMean
@@ -145,15 +146,15 @@ func (p *PlanNDPeriodic) Solve(dst, rhs []float64) error {
 
 	for axis := range p.fft {
 		if err := p.transformAxis(axis, false); err != nil {
-			return fmt.Errorf("FFT forward axis %d: %w", axis, err)
+			return p.transformError("forward", axis, err)
 		}
 	}
 
 	p.applyEigenvalues(p.work.Complex)
 
-	for axis := len(p.fft) - 1; axis >= 0; axis-- {
+	for axis := len(p.fft) - 1; axis > 0; axis-- {
 		if err := p.transformAxis(axis, true); err != nil {
-			return fmt.Errorf("FFT inverse axis %d: %w", axis, err)
+			return p.transformError("inverse", axis, err)
 		}
 	}
 
@@ -162,13 +163,18 @@ func (p *PlanNDPeriodic) Solve(dst, rhs []float64) error {
 		addMean = *p.opts.SolutionMean
 	}
 
-	for i := range p.work.Complex {
-		dst[i] = real(p.work.Complex[i]) + addMean
+	if err := p.inverseAxis0Real(dst, addMean); err != nil {
+		return p.transformError("inverse", 0, err)
 	}
 
 	return nil
 }
 
+// transformError wraps a failure of the FFT along axis in a *TransformError.
+func (p *PlanNDPeriodic) transformError(stage string, axis int, err error) error {
+	return &TransformError{Stage: stage, Transform: "FFT", Axis: axis, Shape: slices.Clone(p.shape), Err: err}
+}
+
 // SolveInPlace solves the system in-place, overwriting buf with the solution.
 func (p *PlanNDPeriodic) SolveInPlace(buf []float64) error {
 	return p.Solve(buf, buf)
@@ -192,43 +198,41 @@ func (p *PlanNDPeriodic) applyEigenvalues(data []complex128) {
 			data[idx] /= complex(denom, 0)
 		}
 
-		for d := len(indices) - 1; d >= 0; d-- {
-			indices[d]++
-			if indices[d] < p.shape[d] {
-				break
-			}
-			indices[d] = 0
-		}
+		p.shape.Next(indices)
 	}
 }
 
 func (p *PlanNDPeriodic) transformAxis(axis int, inverse bool) error {
-	lineLen := p.shape[axis]
-	lineStride := p.stride[axis]
-	totalLines := p.shape.Size() / lineLen
+	it := p.lines[axis]
+	it.Reset()
 
-	reducedDims := p.axisDims[axis]
-	indices := p.axisIdx[axis]
-	for i := range indices {
-		indices[i] = 0
+	for ok := true; ok; ok = it.Next() {
+		if err := p.fft[axis].transformLine(p.work.Complex, it.StartIndex(), it.LineStride(), inverse); err != nil {
+			return err
+		}
 	}
-	otherAxes := p.axisOther[axis]
-	for range totalLines {
-		start := 0
-		for i, d := range otherAxes {
-			start += indices[i] * p.stride[d]
+
+	return nil
+}
+
+// inverseAxis0Real is the last inverse pass: it transforms the lines along
+// axis 0 and stores their real part plus shift in dst, fusing the output
+// sweep into the transform. Axis-0 lines start at every index of the first
+// hyperplane.
+func (p *PlanNDPeriodic) inverseAxis0Real(dst []float64, shift float64) error {
+	plan := p.fft[0]
+	stride := p.stride[0]
+	for start := range stride {
+		for i := range plan.n {
+			plan.scratchA[i] = p.work.Complex[start+i*stride]
 		}
 
-		if err := p.fft[axis].transformLine(p.work.Complex, start, lineStride, inverse); err != nil {
+		if err := plan.fftPlan.Inverse(plan.scratchB, plan.scratchA); err != nil {
 			return err
 		}
 
-		for i := len(indices) - 1; i >= 0; i-- {
-			indices[i]++
-			if indices[i] < reducedDims[i] {
-				break
-			}
-			indices[i] = 0
+		for i, v := range plan.scratchB {
+			dst[start+i*stride] = real(v) + shift
 		}
 	}
 
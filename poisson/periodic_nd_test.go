@@ -5,6 +5,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/MeKo-Tech/algo-pde/fd"
 	"github.com/MeKo-Tech/algo-pde/poisson"
 )
 
@@ -95,69 +96,15 @@ func manufacturedND(dims poisson.Shape, h []float64) ([]float64, []float64) {
 		}
 
 		u[idx] = val
-
-		for d := len(indices) - 1; d >= 0; d-- {
-			indices[d]++
-			if indices[d] < dims[d] {
-				break
-			}
-			indices[d] = 0
-		}
-	}
-
-	applyPeriodicND(rhs, u, dims, h)
-
-	return u, rhs
-}
-
-func applyPeriodicND(dst, src []float64, dims poisson.Shape, h []float64) {
-	nDims := len(dims)
-	if len(h) != nDims {
-		return
-	}
-
-	total := dims.Size()
-	if len(dst) != total || len(src) != total {
-		return
+		dims.Next(indices)
 	}
 
-	strides := make([]int, nDims)
-	stride := 1
-	for d := nDims - 1; d >= 0; d-- {
-		strides[d] = stride
-		stride *= dims[d]
+	bc := make([]poisson.BCType, len(dims))
+	for d := range bc {
+		bc[d] = poisson.Periodic
 	}
 
-	indices := make([]int, nDims)
-	for idx := range src {
-		u := src[idx]
-		sum := 0.0
+	fd.ApplyND(rhs, u, dims, h, bc)
 
-		for d := 0; d < nDims; d++ {
-			left := indices[d] - 1
-			if left < 0 {
-				left = dims[d] - 1
-			}
-
-			right := indices[d] + 1
-			if right >= dims[d] {
-				right = 0
-			}
-
-			leftOffset := idx + (left-indices[d])*strides[d]
-			rightOffset := idx + (right-indices[d])*strides[d]
-
-			sum += (2.0*u - src[leftOffset] - src[rightOffset]) / (h[d] * h[d])
-		}
-
-		dst[idx] = sum
-
-		for d := nDims - 1; d >= 0; d-- {
-			indices[d]++
-			if indices[d] < dims[d] {
-				break
-			}
-			indices[d] = 0
-		}
-	}
+	return u, rhs
 }
@@ -2,6 +2,8 @@ package poisson
 
 import (
 	"fmt"
+	"math"
+	"slices"
 
 	"github.com/MeKo-Tech/algo-pde/grid"
 )
@@ -15,8 +17,74 @@ type Plan struct {
 	eig   [3][]float64
 	tr    [3]AxisTransform
 	work  Workspace
+	wsrc  workspaceSource
 	opts  Options
 	alpha float64
+
+	// coeff holds the per-axis diffusion coefficients c_axis, folded into eig.
+	coeff [3]float64
+
+	// power and scale turn the Laplacian symbol λ into scale*λ^power.
+	// The default (1, 1) gives the plain (alpha - Δ) operator.
+	power int
+	scale float64
+
+	// eta holds normalized mode frequencies per axis for spectral filters.
+	eta [3][]float64
+
+	// verifyIn, verifyOut, verifyTmp and verifySol are lazily allocated
+	// scratch buffers for WithVerify residual checks.
+	verifyIn  []float64
+	verifyOut []float64
+	verifyTmp []float64
+	verifySol []float64
+
+	// stencilBuf holds lazily allocated scratch for applying the product
+	// terms of isotropic stencils in residual checks.
+	stencilBuf [2][]float64
+
+	// sliceAlpha holds one shift per slab for layered plans; nil means every
+	// mode uses alpha.
+	sliceAlpha []float64
+
+	// slabMean and slabMax are per-slab nullspace scratch for batch plans.
+	slabMean []float64
+	slabMax  []float64
+
+	// kernel performs line-wise eigenvalue division on the fast path.
+	// divideChecked and divideFast cache whether the fast path applies,
+	// i.e. whether no mode except the allowed zero mode is resonant.
+	kernel        DivideKernel
+	divideChecked bool
+	divideFast    bool
+
+	// inverse is the precomputed inverse symbol table (WithPrecomputedInverse),
+	// built on first use.
+	inverse []float64
+
+	// report records how the requested options were resolved.
+	report PlanReport
+
+	// gpu runs the spectral part of Solve on a GPU when set (WithGPU).
+	gpu gpuBackend
+
+	// faceBuf holds per-face scratch for boundary values produced by
+	// BoundaryFunc callbacks, indexed by BoundaryFace.
+	faceBuf [6][]float64
+
+	// dirichletBuf and neumannBuf hold the boundary data of a SolveWithBC
+	// call split by type.
+	dirichletBuf, neumannBuf BoundaryConditions
+
+	// so collects the SolveOptions of the running call; see solveOptions.
+	so solveOptions
+
+	// stats accumulates stage times for WithTiming.
+	stats PlanStats
+
+	// workerCap is the WithSolveWorkers limit of the running call; 0 means
+	// the plan's worker count.
+	workerCap int
 }
 
 // NewPlan creates a new Poisson plan with per-axis boundary conditions.
@@ -26,12 +94,70 @@ func NewPlan(dim int, n []int, h []float64, bc []BCType, opts ...Option) (*Plan,
 
 // NewHelmholtzPlan creates a new Helmholtz plan for (alpha - Δ)u = f.
 // Negative alpha values are allowed but may lead to singular operators when
-// alpha cancels an eigenvalue; Solve will return ErrResonant in that case.
+// alpha cancels an eigenvalue; Solve will return a *ResonantError wrapping
+// ErrResonant in that case.
 func NewHelmholtzPlan(dim int, n []int, h []float64, bc []BCType, alpha float64, opts ...Option) (*Plan, error) {
 	return newPlanWithAlpha(dim, n, h, bc, alpha, opts...)
 }
 
+// NewAnisotropicHelmholtzPlan creates a plan for the tensor-screened operator
+//
+//	(alpha - c_x ∂xx - c_y ∂yy - c_z ∂zz) u = f
+//
+// with one non-negative coefficient per axis in coeff. The coefficients are
+// folded into the per-axis eigenvalue tables at plan time, so Solve costs the
+// same as for NewHelmholtzPlan. This suits operator-split schemes where axes
+// carry different implicit coefficients. A zero coefficient removes the
+// derivative along that axis and requires a non-zero alpha: with alpha == 0
+// every mode that varies only along that axis would be singular.
+func NewAnisotropicHelmholtzPlan(
+	dim int, n []int, h []float64, bc []BCType, alpha float64, coeff []float64, opts ...Option,
+) (*Plan, error) {
+	if len(coeff) != dim {
+		return nil, &ValidationError{
+			Field:   "coeff",
+			Message: "length must match dim",
+		}
+	}
+
+	for axis, c := range coeff {
+		if c < 0 || math.IsNaN(c) || math.IsInf(c, 0) {
+			return nil, &ValidationError{
+				Field:   fmt.Sprintf("coeff[%d]", axis),
+				Message: "must be finite and non-negative",
+			}
+		}
+	}
+
+	if alpha == 0 && slices.Contains(coeff, 0) {
+		return nil, &ValidationError{
+			Field:   "coeff",
+			Message: "zero coefficients require a non-zero alpha",
+		}
+	}
+
+	plan, err := newPlanWithAlpha(dim, n, h, bc, alpha, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for axis, c := range coeff {
+		plan.coeff[axis] = c
+		for m := range plan.eig[axis] {
+			plan.eig[axis][m] *= c
+		}
+	}
+
+	return plan, nil
+}
+
 func newPlanWithAlpha(dim int, n []int, h []float64, bc []BCType, alpha float64, opts ...Option) (*Plan, error) {
+	return newPlan(dim, n, h, bc, alpha, 1, opts...)
+}
+
+// newPlan builds a plan that transforms the first dim axes. slabs > 1 adds
+// an untransformed batch axis 2 of that length (dim must be 2).
+func newPlan(dim int, n []int, h []float64, bc []BCType, alpha float64, slabs int, opts ...Option) (*Plan, error) {
 	if dim < 1 || dim > 3 {
 		return nil, &ValidationError{
 			Field:   "dim",
@@ -60,7 +186,22 @@ func newPlanWithAlpha(dim int, n []int, h []float64, bc []BCType, alpha float64,
 		}
 	}
 
-	options := ApplyOptions(DefaultOptions(), opts)
+	options, err := ApplyOptions(DefaultOptions(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if d := options.Stencil.dim(); d != 0 && d != dim {
+		return nil, &ValidationError{
+			Field:   "Stencil",
+			Message: fmt.Sprintf("%s requires a %dD plan", options.Stencil, d),
+		}
+	}
+
+	if options.AutoTune > 0 {
+		return autoTunePlan(dim, n, h, bc, alpha, slabs, options, opts)
+	}
+
+	report := newPlanReport("Plan", options, requestedOptions(options)...)
 	options.Workers = effectiveWorkers(options.Workers)
 	plan := &Plan{
 		dim:   dim,
@@ -69,15 +210,18 @@ func newPlanWithAlpha(dim int, n []int, h []float64, bc []BCType, alpha float64,
 		bc:    [3]BCType{Periodic, Periodic, Periodic},
 		opts:  options,
 		alpha: alpha,
+		coeff: [3]float64{1, 1, 1},
+		power: 1,
+		scale: 1,
 	}
 
 	size := 1
 	for axis := 0; axis < dim; axis++ {
 		if n[axis] < 1 {
-			return nil, ErrInvalidSize
+			return nil, invalidSize(fmt.Sprintf("n[%d]", axis))
 		}
 		if h[axis] <= 0 {
-			return nil, ErrInvalidSpacing
+			return nil, invalidSpacing(fmt.Sprintf("h[%d]", axis))
 		}
 
 		switch bc[axis] {
@@ -96,101 +240,536 @@ func newPlanWithAlpha(dim int, n []int, h []float64, bc []BCType, alpha float64,
 	}
 
 	for axis := 0; axis < dim; axis++ {
-		var err error
+		plan.eta[axis] = normalizedFrequencies(plan.n[axis], plan.bc[axis])
+
 		switch plan.bc[axis] {
 		case Periodic:
 			plan.eig[axis] = eigenvaluesPeriodic(plan.n[axis], plan.h[axis])
-			plan.tr[axis], err = newFFTAxisTransform(plan.n[axis], options.Workers)
 		case Dirichlet:
 			plan.eig[axis] = eigenvaluesDirichlet(plan.n[axis], plan.h[axis])
-			plan.tr[axis], err = newDSTAxisTransform(plan.n[axis], options.Workers)
 		case Neumann:
 			plan.eig[axis] = eigenvaluesNeumann(plan.n[axis], plan.h[axis])
+		}
+
+		var err error
+		switch {
+		case options.ReferenceTransforms:
+			plan.tr[axis], err = newReferenceAxisTransform(plan.n[axis], plan.bc[axis], options.Workers)
+		case plan.bc[axis] == Periodic:
+			plan.tr[axis], err = newFFTAxisTransform(plan.n[axis], options.Workers)
+		case plan.bc[axis] == Dirichlet:
+			plan.tr[axis], err = newDSTAxisTransform(plan.n[axis], options.Workers)
+		case plan.bc[axis] == Neumann:
 			plan.tr[axis], err = newDCTAxisTransform(plan.n[axis], options.Workers)
 		}
 		if err != nil {
-			return nil, fmt.Errorf("axis %d: %w", axis, err)
+			return nil, plan.transformError("setup", axis, err)
 		}
 	}
 
+	if slabs > 1 {
+		plan.n[2] = slabs
+		size *= slabs
+		plan.slabMean = make([]float64, slabs)
+		plan.slabMax = make([]float64, slabs)
+	}
+
 	realSize := 0
 	if !options.InPlace {
 		realSize = size
 	}
-	plan.work = NewWorkspace(realSize, size)
+	plan.wsrc, plan.work = newWorkspaceSource(options, realSize, size)
+
+	// Strategy tuning and GPU setup use the workspace.
+	plan.wsrc.acquire(&plan.work)
+	defer plan.wsrc.release(&plan.work)
+
+	for axis := 0; axis < dim; axis++ {
+		if err := applyTransformStrategy(plan.tr[axis], options.TransformStrategy, plan.work.Complex, plan.shape(), axis); err != nil {
+			return nil, plan.transformError("setup", axis, err)
+		}
+	}
+
+	plan.kernel = options.DivideKernel
+	if plan.kernel == nil {
+		plan.kernel = DefaultDivideKernel()
+	}
+
+	if options.UseGPU {
+		if err := plan.initGPU(); err != nil {
+			return nil, err
+		}
+	}
+
+	plan.report = plan.resolveReport(report)
+
+	return plan, nil
+}
+
+// autoTunePlan implements WithAutoTune for newPlan.
+func autoTunePlan(
+	dim int, n []int, h []float64, bc []BCType, alpha float64, slabs int, options Options, opts []Option,
+) (*Plan, error) {
+	candidates := tuneCandidates(options, dim > 1 && !options.ReferenceTransforms)
+	build := func(opts ...Option) (*Plan, error) {
+		return newPlan(dim, n, h, bc, alpha, slabs, opts...)
+	}
+	solve := func(p *Plan, dst, rhs []float64) error {
+		// Benchmark solves are not reported to WithMetrics.
+		p.opts.Metrics = nil
+		return p.Solve(dst, rhs)
+	}
+
+	plan, entry, err := autoTune(options.AutoTune, candidates, opts, build, (*Plan).size, solve)
+	if err != nil {
+		return nil, err
+	}
+	plan.report.Options = append(plan.report.Options, entry)
+	plan.opts.Metrics = options.Metrics
+	plan.ResetStats()
 
 	return plan, nil
 }
 
+// Report returns how the plan resolved its options. See PlanReport.
+func (p *Plan) Report() PlanReport {
+	r := p.report
+	r.Options = slices.Clone(r.Options)
+
+	return r
+}
+
+// resolveReport records the fallbacks newPlan applied to the options of r.
+func (p *Plan) resolveReport(r PlanReport) PlanReport {
+	r.set("WithRealFFT", OptionDowngraded, "not supported by Plan; complex transforms are used")
+	r.set("WithRealFFTPrecision", OptionRejected, "Plan has no real FFT path")
+
+	switch {
+	case p.opts.ReferenceTransforms:
+		r.set("WithTransformStrategy", OptionRejected, "reference transforms have no blocked variant")
+	case p.opts.TransformStrategy == TransformAuto:
+		var blocked []int
+		for axis := 0; axis < p.dim; axis++ {
+			if blockedEnabled(p.tr[axis]) {
+				blocked = append(blocked, axis)
+			}
+		}
+		r.set("WithTransformStrategy", OptionHonored, fmt.Sprintf("timing selected blocked axes %v", blocked))
+	}
+
+	if p.opts.PrecomputedInverse {
+		if p.opts.SpectralFilter != nil {
+			r.set("WithPrecomputedInverse", OptionDowngraded, "solves with the plan's spectral filter divide per mode")
+		} else {
+			r.set("WithDivideKernel", OptionRejected, "WithPrecomputedInverse multiplies instead of dividing")
+		}
+	}
+
+	return r
+}
+
 // Solve computes the solution into dst for a given RHS.
-func (p *Plan) Solve(dst, rhs []float64) error {
+// Optional SolveOptions override plan defaults for this call only.
+func (p *Plan) Solve(dst, rhs []float64, opts ...SolveOption) (err error) {
+	defer p.observeSolve("Solve", p.metricsStart(), &err)
+
+	if dst == nil || rhs == nil {
+		return ErrNilBuffer
+	}
+
+	size := p.size()
+	if err := checkBuffers("Solve", size, dst, rhs, "rhs"); err != nil {
+		return err
+	}
+
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
+	packed := grid.PackedView(p.shape())
+
+	return p.solve(dst, packed, rhs, packed, p.solveOptions(opts))
+}
+
+// SolveView is like Solve, but reads the RHS from and writes the solution to
+// strided windows of larger arrays, e.g. the interior of a field with ghost
+// layers (see grid.SubView). The views must fit their buffers; elements
+// outside the windows are left untouched. dst and rhs may be the same buffer
+// with the same view.
+func (p *Plan) SolveView(
+	dst []float64, dstView grid.View, rhs []float64, rhsView grid.View, opts ...SolveOption,
+) (err error) {
+	defer p.observeSolve("Solve", p.metricsStart(), &err)
+
 	if dst == nil || rhs == nil {
 		return ErrNilBuffer
 	}
 
+	shape := p.shape()
+	if err := checkViews("SolveView", shape, dst, dstView, rhs, rhsView); err != nil {
+		return err
+	}
+
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
+	return p.solve(dst, dstView, rhs, rhsView, p.solveOptions(opts))
+}
+
+func (p *Plan) solve(dst []float64, dstView grid.View, rhs []float64, rhsView grid.View, so solveOptions) error {
+	if so.err != nil {
+		return so.err
+	}
+
+	if so.workers != p.opts.Workers {
+		p.limitWorkers(so.workers)
+		defer p.limitWorkers(0)
+	}
+
 	size := p.size()
-	if len(dst) != size || len(rhs) != size {
-		return ErrSizeMismatch
+	start := p.stageStart()
+	hasNullspace, err := p.loadRHS(rhs, rhsView, so)
+	if err != nil {
+		return err
+	}
+
+	if so.verify && len(p.verifyIn) < size {
+		p.verifyIn = make([]float64, size)
+	}
+
+	if so.verify {
+		for i, v := range p.work.Complex {
+			p.verifyIn[i] = real(v)
+		}
+		if hasNullspace && so.nullspace != NullspaceSubtractMean {
+			p.removeZeroMode(p.verifyIn[:size])
+		}
+	}
+	p.stageEnd(&p.stats.Copy, start)
+
+	addMean := 0.0
+	if hasNullspace && so.solutionMean != nil {
+		addMean = *so.solutionMean
+	}
+
+	if so.stats != nil {
+		so.stats.SolutionMean = addMean
+	}
+
+	// The last inverse pass writes the solution straight into a packed dst
+	// unless the workspace result is still needed (verification, layered
+	// gauges).
+	var out []float64
+	fused := dstView.IsPacked(p.shape()) && !so.verify && (p.sliceAlpha == nil || addMean == 0)
+	if fused {
+		out = dst
+	}
+
+	if err := p.solveSpectral(so.filter, so.sparseRHS, out, addMean); err != nil {
+		return err
+	}
+
+	var precisionErr error
+	if so.precisionSamples > 0 {
+		dev, mode := p.precisionDeviation(so.precisionSamples)
+		if so.stats != nil {
+			so.stats.PrecisionDeviation = dev
+		}
+		if !(dev <= so.precisionTol) {
+			precisionErr = &PrecisionError{Mode: mode, Deviation: dev, Tolerance: so.precisionTol}
+		}
+	}
+
+	if p.sliceAlpha != nil && addMean != 0 {
+		// Only the singular slabs of a layered plan carry a gauge.
+		p.addSlabGauge(addMean)
+		addMean = 0
+	}
+
+	if so.verify {
+		if len(p.verifySol) < size {
+			p.verifySol = make([]float64, size)
+		}
+		start = p.stageStart()
+		sol := p.verifySol[:size]
+		for i, v := range p.work.Complex {
+			sol[i] = real(v) + addMean
+		}
+		p.scatter(dst, dstView, addMean)
+		p.stageEnd(&p.stats.Copy, start)
+		p.countSolve()
+
+		ref := p.verifyIn[:size]
+		if so.filter != nil {
+			if err := p.filterReference(ref, so.filter); err != nil {
+				return err
+			}
+		}
+
+		if err := p.verifyResidual(sol, ref, so.verifyTol); err != nil {
+			return err
+		}
+		return precisionErr
+	}
+
+	if !fused {
+		start = p.stageStart()
+		p.scatter(dst, dstView, addMean)
+		p.stageEnd(&p.stats.Copy, start)
+	}
+	p.countSolve()
+
+	return precisionErr
+}
+
+// loadRHS gathers the RHS window into the complex workspace, applies the
+// nullspace policy and resets so.stats. It reports whether the problem has a
+// nullspace.
+func (p *Plan) loadRHS(rhs []float64, rhsView grid.View, so solveOptions) (bool, error) {
+	if p.opts.CheckFinite {
+		if err := checkFiniteView("rhs", rhs, p.shape(), rhsView); err != nil {
+			return false, err
+		}
 	}
 
 	hasNullspace := p.hasNullspace()
-	if hasNullspace && p.opts.Nullspace == NullspaceError {
-		return ErrNullspace
+	if hasNullspace && so.nullspace == NullspaceError {
+		return false, ErrNullspace
 	}
 
-	offset := 0.0
+	p.gather(rhs, rhsView)
+
+	zeroMode := 0.0
 	if hasNullspace {
-		mean, maxAbs := meanAndMaxAbs(rhs)
-		if p.opts.Nullspace == NullspaceZeroMode && !meanWithinTolerance(mean, maxAbs) {
-			return ErrNonZeroMean
+		var err error
+		if zeroMode, err = p.projectNullspace(so.nullspace); err != nil {
+			return false, err
 		}
+	}
 
-		if p.opts.Nullspace == NullspaceSubtractMean {
-			offset = mean
+	if so.stats != nil {
+		*so.stats = SolveStats{Nullspace: hasNullspace, ZeroModeCoefficient: zeroMode, NetSource: zeroMode * p.domainMeasure()}
+		if so.nullspace == NullspaceSubtractMean {
+			so.stats.SubtractedMean = zeroMode
 		}
 	}
 
-	for i, v := range rhs {
-		p.work.Complex[i] = complex(v-offset, 0)
+	return hasNullspace, nil
+}
+
+// solveSpectral transforms the complex workspace, divides by the symbol
+// (applying filter, if any) and transforms back. With sparse set, the
+// forward transforms skip all-zero x-planes (see forwardSparse).
+//
+// If out is non-nil, the real part of the solution plus shift is stored in
+// out (packed) instead of the workspace; with transforms implementing
+// realInverter this happens inside the last inverse pass, saving a sweep.
+func (p *Plan) solveSpectral(filter FilterFunc, sparse bool, out []float64, shift float64) error {
+	start := p.stageStart()
+	if p.gpu != nil && filter == nil {
+		if err := p.gpu.solve(p.work.Complex); err != nil {
+			return transformError("forward", "GPU", -1, p.shape(), p.gridDim(), err)
+		}
+		start = p.stageEnd(&p.stats.Forward, start)
+		if out != nil {
+			p.scatter(out, grid.PackedView(p.shape()), shift)
+			p.stageEnd(&p.stats.Copy, start)
+		}
+		return nil
 	}
 
+	if sparse && p.dim > 1 {
+		if err := p.forwardSparse(); err != nil {
+			return err
+		}
+	} else if err := p.forwardAll(); err != nil {
+		return err
+	}
+	start = p.stageEnd(&p.stats.Forward, start)
+
+	if err := p.applyEigenvalues(filter); err != nil {
+		return err
+	}
+	start = p.stageEnd(&p.stats.Divide, start)
+
+	if err := p.inverseAll(out, shift); err != nil {
+		return err
+	}
+	p.stageEnd(&p.stats.Inverse, start)
+
+	return nil
+}
+
+// forwardAll applies the forward transforms along every axis of the complex
+// workspace.
+func (p *Plan) forwardAll() error {
 	shape := p.shape()
 	for axis := 0; axis < p.dim; axis++ {
 		if err := p.tr[axis].Forward(p.work.Complex, shape, axis); err != nil {
-			return fmt.Errorf("forward axis %d: %w", axis, err)
+			return p.transformError("forward", axis, err)
 		}
 	}
 
-	if err := p.applyEigenvalues(); err != nil {
-		return err
-	}
+	return nil
+}
 
-	for axis := p.dim - 1; axis >= 0; axis-- {
+// inverseAll applies the inverse transforms along every axis of the complex
+// workspace. If out is non-nil, the real part of the result plus shift is
+// stored in out (packed); see solveSpectral.
+func (p *Plan) inverseAll(out []float64, shift float64) error {
+	shape := p.shape()
+	for axis := p.dim - 1; axis > 0; axis-- {
 		if err := p.tr[axis].Inverse(p.work.Complex, shape, axis); err != nil {
-			return fmt.Errorf("inverse axis %d: %w", axis, err)
+			return p.transformError("inverse", axis, err)
 		}
 	}
 
-	addMean := 0.0
-	if hasNullspace && p.opts.SolutionMean != nil {
-		addMean = *p.opts.SolutionMean
+	if r, ok := p.tr[0].(realInverter); ok && out != nil {
+		if err := r.inverseReal(p.work.Complex, shape, 0, out, shift); err != nil {
+			return p.transformError("inverse", 0, err)
+		}
+		return nil
+	}
+
+	if err := p.tr[0].Inverse(p.work.Complex, shape, 0); err != nil {
+		return p.transformError("inverse", 0, err)
+	}
+
+	if out != nil {
+		p.scatter(out, grid.PackedView(shape), shift)
+	}
+
+	return nil
+}
+
+// forwardSparse applies the forward transforms to a workspace that is zero
+// on most x-planes. The axis transforms commute, so the y and z transforms run
+// first and only on the contiguous x-planes holding non-zero values (the
+// transform of a zero plane is zero); the x transform then runs on the whole
+// array.
+func (p *Plan) forwardSparse() error {
+	shape := p.shape()
+	planeShape := grid.Shape{1, shape[1], shape[2]}
+	plane := shape[1] * shape[2]
+
+	for i := range shape[0] {
+		data := p.work.Complex[i*plane : (i+1)*plane]
+		if !slices.ContainsFunc(data, func(v complex128) bool { return v != 0 }) {
+			continue
+		}
+
+		for axis := 1; axis < p.dim; axis++ {
+			if err := p.tr[axis].Forward(data, planeShape, axis); err != nil {
+				return p.transformError("forward", axis, err)
+			}
+		}
 	}
 
-	for i := range p.work.Complex {
-		dst[i] = real(p.work.Complex[i]) + addMean
+	if err := p.tr[0].Forward(p.work.Complex, shape, 0); err != nil {
+		return p.transformError("forward", 0, err)
 	}
 
 	return nil
 }
 
+// transformError wraps a failure of the transform along axis in a
+// *TransformError carrying the plan's transform name and grid shape.
+func (p *Plan) transformError(stage string, axis int, err error) error {
+	return transformError(stage, transformName(p.bc[axis]), axis, p.shape(), p.gridDim(), err)
+}
+
+// gridDim returns the number of grid axes, including the batch axis of slab
+// plans.
+func (p *Plan) gridDim() int {
+	if p.slabs() > 1 {
+		return 3
+	}
+
+	return p.dim
+}
+
+// gather loads the RHS window into the real part of the complex workspace.
+func (p *Plan) gather(src []float64, view grid.View) {
+	shape := p.shape()
+	if view.IsPacked(shape) {
+		for i, v := range src[:p.size()] {
+			p.work.Complex[i] = complex(v, 0)
+		}
+		return
+	}
+
+	idx := 0
+	for i := 0; i < shape[0]; i++ {
+		for j := 0; j < shape[1]; j++ {
+			off := view.Index(i, j, 0)
+			for k := 0; k < shape[2]; k++ {
+				p.work.Complex[idx] = complex(src[off+k*view.Stride[2]], 0)
+				idx++
+			}
+		}
+	}
+}
+
+// scatter stores the real part of the complex workspace plus shift into the
+// destination window.
+func (p *Plan) scatter(dst []float64, view grid.View, shift float64) {
+	shape := p.shape()
+	if view.IsPacked(shape) {
+		for i, v := range p.work.Complex {
+			dst[i] = real(v) + shift
+		}
+		return
+	}
+
+	idx := 0
+	for i := 0; i < shape[0]; i++ {
+		for j := 0; j < shape[1]; j++ {
+			off := view.Index(i, j, 0)
+			for k := 0; k < shape[2]; k++ {
+				dst[off+k*view.Stride[2]] = real(p.work.Complex[idx]) + shift
+				idx++
+			}
+		}
+	}
+}
+
+// addSlabGauge adds mean to the solution of every singular slab of a
+// layered plan; non-singular slabs have a unique solution.
+func (p *Plan) addSlabGauge(mean float64) {
+	slabs := p.slabs()
+	for idx := range p.work.Complex {
+		if p.slabHasNullspace(idx % slabs) {
+			p.work.Complex[idx] += complex(mean, 0)
+		}
+	}
+}
+
+// meanAndMaxAbsReal is meanAndMaxAbs over the real parts of values.
+func meanAndMaxAbsReal(values []complex128) (mean, maxAbs float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		r := real(v)
+		sum += r
+		maxAbs = math.Max(maxAbs, math.Abs(r))
+	}
+
+	return sum / float64(len(values)), maxAbs
+}
+
 // SolveInPlace solves the system in-place, overwriting buf with the solution.
-func (p *Plan) SolveInPlace(buf []float64) error {
-	return p.Solve(buf, buf)
+func (p *Plan) SolveInPlace(buf []float64, opts ...SolveOption) error {
+	return p.Solve(buf, buf, opts...)
 }
 
-// WorkBytes returns the size of the plan's workspace buffers in bytes.
+// WorkBytes returns the size of the workspace buffers the plan holds in
+// bytes, including the inverse symbol table of WithPrecomputedInverse once
+// built. Plans using WithWorkspace or WithPooledWorkspace hold none between
+// solves.
 func (p *Plan) WorkBytes() int {
-	return p.work.Bytes()
+	return p.work.Bytes() + 8*len(p.inverse)
 }
 
 func (p *Plan) shape() grid.Shape {
@@ -198,18 +777,113 @@ func (p *Plan) shape() grid.Shape {
 }
 
 func (p *Plan) size() int {
-	size := 1
+	return p.n[0] * p.n[1] * p.n[2]
+}
+
+// slabs returns the number of independent problems along the batch axis
+// (1 for plans without one).
+func (p *Plan) slabs() int {
+	if p.dim == 2 {
+		return p.n[2]
+	}
+	return 1
+}
+
+// projectNullspace applies the nullspace policy to the RHS in the complex
+// workspace, independently for every slab of a batch plan. It returns the
+// RHS mean, i.e. the normalized zero-mode coefficient that the solve removes
+// (averaged over singular slabs).
+func (p *Plan) projectNullspace(nullspace NullspaceHandling) (float64, error) {
+	slabs := p.slabs()
+	if slabs == 1 {
+		mean, maxAbs := meanAndMaxAbsReal(p.work.Complex)
+		if nullspace == NullspaceZeroMode && !meanWithinTolerance(mean, maxAbs) {
+			return 0, ErrNonZeroMean
+		}
+
+		if nullspace != NullspaceSubtractMean {
+			return mean, nil
+		}
+
+		for i := range p.work.Complex {
+			p.work.Complex[i] -= complex(mean, 0)
+		}
+		return mean, nil
+	}
+
+	for k := range slabs {
+		p.slabMean[k] = 0
+		p.slabMax[k] = 0
+	}
+	for idx, v := range p.work.Complex {
+		k := idx % slabs
+		p.slabMean[k] += real(v)
+		p.slabMax[k] = math.Max(p.slabMax[k], math.Abs(real(v)))
+	}
+
+	perSlab := float64(p.size() / slabs)
+	total := 0.0
+	singular := 0
+	for k := range slabs {
+		if !p.slabHasNullspace(k) {
+			p.slabMean[k] = 0
+			continue
+		}
+
+		p.slabMean[k] /= perSlab
+		total += p.slabMean[k]
+		singular++
+		if nullspace == NullspaceZeroMode && !meanWithinTolerance(p.slabMean[k], p.slabMax[k]) {
+			return 0, ErrNonZeroMean
+		}
+	}
+
+	if singular == 0 {
+		return 0, nil
+	}
+
+	mean := total / float64(singular)
+	if nullspace != NullspaceSubtractMean {
+		return mean, nil
+	}
+
+	for idx := range p.work.Complex {
+		p.work.Complex[idx] -= complex(p.slabMean[idx%slabs], 0)
+	}
+	return mean, nil
+}
+
+// domainMeasure returns the length, area or volume of one problem's domain,
+// n_axis*h_axis multiplied over the transformed axes.
+func (p *Plan) domainMeasure() float64 {
+	measure := 1.0
 	for axis := 0; axis < p.dim; axis++ {
-		size *= p.n[axis]
+		measure *= float64(p.n[axis]) * p.h[axis]
 	}
-	return size
+
+	return measure
 }
 
 func (p *Plan) hasNullspace() bool {
-	if p.alpha != 0 {
+	if !p.bcNullspace() {
 		return false
 	}
 
+	if p.sliceAlpha == nil {
+		return p.alpha == 0
+	}
+
+	for _, a := range p.sliceAlpha {
+		if a == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// bcNullspace reports whether the boundary conditions alone admit a constant
+// mode, i.e. whether a zero shift makes the operator singular.
+func (p *Plan) bcNullspace() bool {
 	for axis := 0; axis < p.dim; axis++ {
 		if !p.bc[axis].HasNullspace() {
 			return false
@@ -218,41 +892,236 @@ func (p *Plan) hasNullspace() bool {
 	return true
 }
 
-func (p *Plan) applyEigenvalues() error {
-	_, ny, nz := p.n[0], p.n[1], p.n[2]
-	strideYZ := ny * nz
-	strideZ := nz
-	allowZeroMode := p.hasNullspace()
+// alphaAt returns the shift of slab k (the z index of a mode).
+func (p *Plan) alphaAt(k int) float64 {
+	if p.sliceAlpha != nil {
+		return p.sliceAlpha[k]
+	}
+	return p.alpha
+}
+
+// slabHasNullspace reports whether slab k of a batch plan is singular.
+func (p *Plan) slabHasNullspace(k int) bool {
+	return p.bcNullspace() && p.alphaAt(k) == 0
+}
+
+func (p *Plan) applyEigenvalues(filter FilterFunc) error {
+	if filter == nil && p.opts.PrecomputedInverse {
+		return p.multiplyInverse()
+	}
+
+	if filter == nil && p.canDivideLines() {
+		return p.divideLines()
+	}
+
 	size := p.size()
-	workers := clampWorkers(p.opts.Workers, size)
-
-	return parallelFor(workers, size, func(_ int, start, end int) error {
-		for idx := start; idx < end; idx++ {
-			i := idx / strideYZ
-			rem := idx % strideYZ
-			j := rem / strideZ
-			k := rem % strideZ
-
-			denom := p.alpha + p.eig[0][i]
-			if p.dim > 1 {
-				denom += p.eig[1][j]
-			}
-			if p.dim > 2 {
-				denom += p.eig[2][k]
-			}
+	workers := clampWorkers(p.workers(), size)
+
+	return parallelRun(workers, size, p, filter, (*Plan).scaleModes)
+}
+
+// scaleModes multiplies modes [start, end) of the complex workspace by
+// their inverse symbol with filter applied.
+func (p *Plan) scaleModes(filter FilterFunc, _ int, start, end int) error {
+	for idx := start; idx < end; idx++ {
+		inv, err := p.modeInverse(idx, filter)
+		if err != nil {
+			return err
+		}
+
+		p.work.Complex[idx] *= complex(inv, 0)
+	}
+	return nil
+}
+
+// modeInverse returns the factor that mode idx (row-major spectral index) is
+// multiplied by: 1/(alpha + λ) with the plan's resonance handling and the
+// optional filter applied, and 0 for the dropped zero mode.
+func (p *Plan) modeInverse(idx int, filter FilterFunc) (float64, error) {
+	ny, nz := p.n[1], p.n[2]
+	i := idx / (ny * nz)
+	j := idx / nz % ny
+	k := idx % nz
+
+	lambda := p.laplacianSymbol(i, j, k)
+	if p.power != 1 {
+		lambda = p.scale * intPow(lambda, p.power)
+	}
+	alpha := p.alphaAt(k)
+	denom := alpha + lambda
+
+	inv := 0.0
+	switch {
+	case denom == 0 && p.bcNullspace() && i == 0 && (p.dim < 2 || j == 0) && (p.dim < 3 || k == 0):
+		return 0, nil
+	case p.isResonant(denom, alpha, lambda):
+		var ok bool
+		inv, ok = p.regularizedInverse(denom)
+		if !ok {
+			return 0, &ResonantError{Mode: [3]int{i, j, k}, Eigenvalue: lambda, Alpha: alpha}
+		}
+	default:
+		inv = 1 / denom
+	}
+
+	if filter != nil {
+		inv *= filter(p.modeEta(i, j, k))
+	}
+
+	return inv, nil
+}
+
+// modeEta returns the normalized frequencies of mode (i, j, k) passed to a
+// FilterFunc.
+func (p *Plan) modeEta(i, j, k int) [3]float64 {
+	eta := [3]float64{p.eta[0][i]}
+	if p.dim > 1 {
+		eta[1] = p.eta[1][j]
+	}
+	if p.dim > 2 {
+		eta[2] = p.eta[2][k]
+	}
+
+	return eta
+}
+
+// filterModes multiplies modes [start, end) of the complex workspace by
+// filter alone, without the inverse symbol.
+func (p *Plan) filterModes(filter FilterFunc, _ int, start, end int) error {
+	ny, nz := p.n[1], p.n[2]
+	for idx := start; idx < end; idx++ {
+		eta := p.modeEta(idx/(ny*nz), idx/nz%ny, idx%nz)
+		p.work.Complex[idx] *= complex(filter(eta), 0)
+	}
+	return nil
+}
+
+// multiplyInverse multiplies the complex workspace by the precomputed
+// inverse symbol table, building the table on first use.
+func (p *Plan) multiplyInverse() error {
+	size := p.size()
+	workers := clampWorkers(p.workers(), size)
 
-			if denom == 0 {
-				if allowZeroMode && i == 0 && (p.dim < 2 || j == 0) && (p.dim < 3 || k == 0) {
-					p.work.Complex[idx] = 0
-					continue
+	if p.inverse == nil {
+		table := make([]float64, size)
+		err := parallelFor(workers, size, func(_ int, start, end int) error {
+			for idx := start; idx < end; idx++ {
+				inv, err := p.modeInverse(idx, nil)
+				if err != nil {
+					return err
 				}
-				return ErrResonant
+				table[idx] = inv
 			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		p.inverse = table
+	}
+
+	return parallelRun(workers, size, p, p.inverse, (*Plan).multiplyModes)
+}
+
+// multiplyModes multiplies modes [start, end) of the complex workspace by
+// the precomputed table.
+func (p *Plan) multiplyModes(table []float64, _ int, start, end int) error {
+	for idx := start; idx < end; idx++ {
+		p.work.Complex[idx] *= complex(table[idx], 0)
+	}
+	return nil
+}
+
+// canDivideLines reports whether the symbol is alpha + Σλ (standard
+// stencil, plain power, no per-slab shifts) without resonant modes, so that applyEigenvalues can
+// hand whole lines to the divide kernel. The check runs once per plan.
+func (p *Plan) canDivideLines() bool {
+	if p.divideChecked {
+		return p.divideFast
+	}
+	p.divideChecked = true
 
-			p.work.Complex[idx] /= complex(denom, 0)
+	if p.power != 1 || p.sliceAlpha != nil || p.slabs() > 1 || p.opts.Stencil != StandardStencil {
+		return false
+	}
+
+	allowZeroMode := p.bcNullspace()
+	for idx := range p.size() {
+		i, j, k := idx/(p.n[1]*p.n[2]), idx/p.n[2]%p.n[1], idx%p.n[2]
+		lambda := p.laplacianSymbol(i, j, k)
+
+		denom := p.alpha + lambda
+		if idx == 0 && denom == 0 && allowZeroMode {
+			continue
 		}
-		return nil
-	})
+		if p.isResonant(denom, p.alpha, lambda) {
+			return false
+		}
+	}
+
+	p.divideFast = true
+	return true
+}
+
+// divideLines divides the complex workspace by alpha + Σλ line by line
+// along the last transformed axis using the plan's divide kernel.
+func (p *Plan) divideLines() error {
+	lines := p.size() / p.n[p.dim-1]
+	workers := clampWorkers(p.workers(), lines)
+
+	return parallelRun(workers, lines, p, p.work.Complex, (*Plan).divideLineRange)
+}
+
+// divideLineRange is the body of divideLines for lines [start, end) of
+// spec.
+func (p *Plan) divideLineRange(spec []complex128, _ int, start, end int) error {
+	last := p.dim - 1
+	lineLen := p.n[last]
+
+	for line := start; line < end; line++ {
+		base := p.alpha
+		switch p.dim {
+		case 2:
+			base += p.eig[0][line]
+		case 3:
+			base += p.eig[0][line/p.n[1]] + p.eig[1][line%p.n[1]]
+		}
+
+		data := spec[line*lineLen : (line+1)*lineLen]
+		if line == 0 && base+p.eig[last][0] == 0 {
+			// Zero mode of a nullspace problem: drop it.
+			data[0] = 0
+			p.kernel.DivideLine(data[1:], base, p.eig[last][1:])
+			continue
+		}
+		p.kernel.DivideLine(data, base, p.eig[last])
+	}
+	return nil
+}
+
+// isResonant reports whether a mode with symbol denom = alpha + lambda is
+// singular or, with a resonance tolerance, near-singular.
+func (p *Plan) isResonant(denom, alpha, lambda float64) bool {
+	if denom == 0 {
+		return true
+	}
+
+	tol := p.opts.ResonanceTolerance
+	return tol > 0 && math.Abs(denom) <= tol*(math.Abs(alpha)+math.Abs(lambda))
+}
+
+// regularizedInverse returns the replacement for 1/denom on a resonant mode
+// according to the plan's resonance handling. ok is false for ResonanceError.
+func (p *Plan) regularizedInverse(denom float64) (inv float64, ok bool) {
+	switch p.opts.Resonance {
+	case ResonancePseudoInverse:
+		return 0, true
+	case ResonanceTikhonov:
+		mu := p.opts.TikhonovParameter
+		return denom / (denom*denom + mu*mu), true
+	default:
+		return 0, false
+	}
 }
 
 func isZeroMode(indices *[3]int, dim int) bool {
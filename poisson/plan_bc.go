@@ -2,69 +2,246 @@ package poisson
 
 import (
 	"fmt"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
 )
 
 // SolveWithBC computes the solution into dst for a given RHS and boundary data.
 // The boundary data is applied as inhomogeneous Dirichlet/Neumann contributions.
-func (p *Plan) SolveWithBC(dst, rhs []float64, bc BoundaryConditions) error {
+// bc may list any subset of the non-periodic faces, each at most once; the
+// other faces keep the homogeneous condition of the plan's boundary type
+// (u = 0 on Dirichlet faces, ∂u/∂n = 0 on Neumann faces). FaceData builds
+// an entry with the matching type.
+// Boundary entries that carry a Func are evaluated at t = 0.
+// Optional SolveOptions are passed through to Solve.
+func (p *Plan) SolveWithBC(dst, rhs []float64, bc BoundaryConditions, opts ...SolveOption) error {
+	return p.SolveWithBCAt(0, dst, rhs, bc, opts...)
+}
+
+// SolveWithBCAt is like SolveWithBC, but evaluates time-dependent boundary
+// callbacks (BoundaryData.Func) at time t.
+//
+// Callback values are written into face buffers owned by the plan, so
+// time-stepping loops can reuse the same BoundaryConditions every step
+// without reallocating face arrays.
+//
+// With WithFluxBalance on a pure Neumann/periodic plan, the net flux of the
+// Neumann data is removed as a uniform volumetric source; the removed value
+// is reported in SolveStats.FluxAdjustment.
+func (p *Plan) SolveWithBCAt(t float64, dst, rhs []float64, bc BoundaryConditions, opts ...SolveOption) (err error) {
+	defer p.observeSolve("SolveWithBC", p.metricsStart(), &err)
+
 	if dst == nil || rhs == nil {
 		return ErrNilBuffer
 	}
 
-	size := p.size()
-	if len(dst) != size || len(rhs) != size {
-		return ErrSizeMismatch
+	if err := checkBuffers("SolveWithBC", p.size(), dst, rhs, "rhs"); err != nil {
+		return err
+	}
+
+	packed := grid.PackedView(p.shape())
+
+	return p.solveWithBC(t, dst, packed, rhs, packed, bc, p.solveOptions(opts))
+}
+
+// SolveWithBCView is like SolveWithBCAt, but reads the RHS from and writes
+// the solution to strided windows of larger arrays (see SolveView). The RHS
+// window is never modified, even with WithInPlace.
+func (p *Plan) SolveWithBCView(
+	t float64, dst []float64, dstView grid.View, rhs []float64, rhsView grid.View, bc BoundaryConditions, opts ...SolveOption,
+) (err error) {
+	defer p.observeSolve("SolveWithBC", p.metricsStart(), &err)
+
+	if dst == nil || rhs == nil {
+		return ErrNilBuffer
 	}
 
+	if err := checkViews("SolveWithBCView", p.shape(), dst, dstView, rhs, rhsView); err != nil {
+		return err
+	}
+
+	return p.solveWithBC(t, dst, dstView, rhs, rhsView, bc, p.solveOptions(opts))
+}
+
+func (p *Plan) solveWithBC(
+	t float64, dst []float64, dstView grid.View, rhs []float64, rhsView grid.View, bc BoundaryConditions, so solveOptions,
+) error {
 	if len(bc) == 0 {
-		return p.Solve(dst, rhs)
+		return p.solve(dst, dstView, rhs, rhsView, so)
 	}
 
 	if err := p.validateBoundaryConditions(bc); err != nil {
 		return err
 	}
 
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
+	size := p.size()
+	shape := p.shape()
+	packed := grid.PackedView(shape)
+
 	buf := rhs
-	if !p.opts.InPlace {
+	if !p.opts.InPlace || !rhsView.IsPacked(shape) {
 		if len(p.work.Real) < size {
 			p.work.Real = make([]float64, size)
 		}
 		buf = p.work.Real[:size]
-		copy(buf, rhs)
+		rhsView.Shape = shape
+		rhsView.CopyOut(buf, rhs)
+	}
+
+	adjustment, err := p.applyBoundaryRHS(t, buf, bc)
+	if err != nil {
+		return err
+	}
+
+	if err := p.solve(dst, dstView, buf, packed, so); err != nil {
+		return err
+	}
+
+	if so.stats != nil {
+		so.stats.FluxAdjustment = adjustment
+	}
+
+	return nil
+}
+
+// SolveBoundary solves the homogeneous problem (alpha - Δ) u = 0 driven only
+// by the boundary data bc, e.g. the Laplace problem of a potential-flow or
+// electrostatic field. It is SolveWithBC with an all-zero RHS, but neither
+// needs nor gathers a zero RHS array, and skips the forward transforms of
+// grid planes the boundary data does not touch. With data only on the x
+// faces of a 3D grid, the y and z transforms run on two of nx planes.
+// Boundary entries that carry a Func are evaluated at t = 0.
+func (p *Plan) SolveBoundary(dst []float64, bc BoundaryConditions, opts ...SolveOption) error {
+	return p.SolveBoundaryAt(0, dst, bc, opts...)
+}
+
+// SolveBoundaryAt is like SolveBoundary, but evaluates time-dependent
+// boundary callbacks (BoundaryData.Func) at time t.
+func (p *Plan) SolveBoundaryAt(t float64, dst []float64, bc BoundaryConditions, opts ...SolveOption) (err error) {
+	defer p.observeSolve("SolveBoundary", p.metricsStart(), &err)
+
+	if dst == nil {
+		return ErrNilBuffer
+	}
+
+	size := p.size()
+	if len(dst) != size {
+		return &SizeError{Expected: size, Got: len(dst), Context: "SolveBoundary dst"}
+	}
+
+	if err := p.validateBoundaryConditions(bc); err != nil {
+		return err
+	}
+
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
+	if len(p.work.Real) < size {
+		p.work.Real = make([]float64, size)
+	}
+	buf := p.work.Real[:size]
+	clear(buf)
+
+	adjustment, err := p.applyBoundaryRHS(t, buf, bc)
+	if err != nil {
+		return err
+	}
+
+	so := p.solveOptions(opts)
+	so.sparseRHS = true
+
+	packed := grid.PackedView(p.shape())
+	if err := p.solve(dst, packed, buf, packed, so); err != nil {
+		return err
+	}
+
+	if so.stats != nil {
+		so.stats.FluxAdjustment = adjustment
 	}
 
-	var dirichlet, neumann BoundaryConditions
+	return nil
+}
+
+// applyBoundaryRHS adds the contributions of bc, evaluated at t, to the
+// packed RHS buf. With WithFluxBalance on a plan with a nullspace it removes
+// the net Neumann flux as a uniform source and returns the removed value.
+func (p *Plan) applyBoundaryRHS(t float64, buf []float64, bc BoundaryConditions) (float64, error) {
+	shape := p.shape()
+
+	// Reuse the split buffers of earlier calls; they are cleared on return
+	// so the plan holds no references to the caller's face data.
+	dirichlet, neumann := p.dirichletBuf[:0], p.neumannBuf[:0]
+	defer func() {
+		clear(dirichlet)
+		clear(neumann)
+		p.dirichletBuf, p.neumannBuf = dirichlet[:0], neumann[:0]
+	}()
+
 	for _, data := range bc {
+		data = p.resolveBoundaryData(t, data)
 		switch data.Type {
 		case Dirichlet:
 			dirichlet = append(dirichlet, data)
 		case Neumann:
 			neumann = append(neumann, data)
 		default:
-			return &ValidationError{
+			return 0, &ValidationError{
 				Field:   "Type",
 				Message: "unsupported boundary condition",
 			}
 		}
 	}
 
-	shape := p.shape()
 	h := p.h
 	if len(dirichlet) > 0 {
 		if err := ApplyDirichletRHS(buf, shape, h, dirichlet); err != nil {
-			return err
+			return 0, err
 		}
 	}
+	adjustment := 0.0
 	if len(neumann) > 0 {
+		balance := p.opts.BalanceFlux && p.hasNullspace()
+		before := 0.0
+		if balance {
+			before = sum(buf)
+		}
+
 		if err := ApplyNeumannRHS(buf, shape, h, neumann); err != nil {
-			return err
+			return 0, err
 		}
+
+		if balance {
+			adjustment = (sum(buf) - before) / float64(len(buf))
+			for i := range buf {
+				buf[i] -= adjustment
+			}
+		}
+	}
+
+	return adjustment, nil
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
 	}
 
-	return p.Solve(dst, buf)
+	return total
 }
 
 func (p *Plan) validateBoundaryConditions(bc BoundaryConditions) error {
+	if len(bc) > 0 && p.opts.Stencil != StandardStencil {
+		return &ValidationError{
+			Field:   "Stencil",
+			Message: fmt.Sprintf("boundary data is only supported with StandardStencil, not %s", p.opts.Stencil),
+		}
+	}
+
+	var seen [6]bool
 	for _, data := range bc {
 		axis, ok := faceAxis(data.Face)
 		if !ok || axis >= p.dim {
@@ -74,6 +251,14 @@ func (p *Plan) validateBoundaryConditions(bc BoundaryConditions) error {
 			}
 		}
 
+		if seen[data.Face] {
+			return &ValidationError{
+				Field:   "Face",
+				Message: fmt.Sprintf("face %s listed more than once", data.Face),
+			}
+		}
+		seen[data.Face] = true
+
 		if p.bc[axis] == Periodic {
 			return &ValidationError{
 				Field:   "Face",
@@ -81,17 +266,77 @@ func (p *Plan) validateBoundaryConditions(bc BoundaryConditions) error {
 			}
 		}
 
+		if data.Func != nil && data.Values != nil {
+			return &ValidationError{
+				Field:   "Func",
+				Message: "Values and Func are mutually exclusive",
+			}
+		}
+
 		if p.bc[axis] != data.Type {
 			return &ValidationError{
 				Field:   "Type",
 				Message: fmt.Sprintf("boundary type %s does not match plan axis %s", data.Type, p.bc[axis]),
 			}
 		}
+
+		if n := p.faceSize(data.Face); data.Func == nil && len(data.Values) != n {
+			return &SizeError{
+				Expected: n,
+				Got:      len(data.Values),
+				Context:  fmt.Sprintf("%s face values", data.Face),
+			}
+		}
 	}
 
 	return nil
 }
 
+// FaceData returns boundary data for face with the plan's boundary type on
+// that axis, for use with SolveWithBC. It does not validate the face; see
+// SolveWithBC.
+func (p *Plan) FaceData(face BoundaryFace, values []float64) BoundaryData {
+	axis, _ := faceAxis(face)
+
+	return BoundaryData{Face: face, Type: p.bc[axis], Values: values}
+}
+
+// resolveBoundaryData evaluates a time-dependent entry into the plan's face
+// buffer. Entries without a Func are returned unchanged.
+func (p *Plan) resolveBoundaryData(t float64, data BoundaryData) BoundaryData {
+	if data.Func == nil {
+		return data
+	}
+
+	n := p.faceSize(data.Face)
+	if len(p.faceBuf[data.Face]) < n {
+		p.faceBuf[data.Face] = make([]float64, n)
+	}
+
+	values := p.faceBuf[data.Face][:n]
+	for idx := range values {
+		values[idx] = data.Func(t, idx)
+	}
+
+	data.Values = values
+	data.Func = nil
+
+	return data
+}
+
+// faceSize returns the number of grid points on a boundary face.
+func (p *Plan) faceSize(face BoundaryFace) int {
+	axis, _ := faceAxis(face)
+	size := 1
+	for d := 0; d < 3; d++ {
+		if d != axis {
+			size *= p.n[d]
+		}
+	}
+
+	return size
+}
+
 func faceAxis(face BoundaryFace) (int, bool) {
 	switch face {
 	case XLow, XHigh:
@@ -104,3 +349,8 @@ func faceAxis(face BoundaryFace) (int, bool) {
 		return 0, false
 	}
 }
+
+// highFace reports whether face lies at the upper end of its axis.
+func highFace(face BoundaryFace) bool {
+	return face == XHigh || face == YHigh || face == ZHigh
+}
@@ -76,3 +76,32 @@ func BenchmarkPlanSolve2D_Dirichlet_Workers(b *testing.B) {
 		bench(runtime.GOMAXPROCS(0))
 	})
 }
+
+func BenchmarkPlanSolve3D_TransformStrategy(b *testing.B) {
+	n := []int{64, 64, 64}
+	h := []float64{1.0 / 64, 1.0 / 64, 1.0 / 64}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Periodic, poisson.Periodic}
+
+	rhs := make([]float64, n[0]*n[1]*n[2])
+	for i := range rhs {
+		rhs[i] = float64(i%7) - 3
+	}
+	dst := make([]float64, len(rhs))
+
+	for _, strategy := range []poisson.TransformStrategy{poisson.TransformStrided, poisson.TransformBlocked} {
+		b.Run(strategy.String(), func(b *testing.B) {
+			plan, err := poisson.NewHelmholtzPlan(3, n, h, bc, 1, poisson.WithTransformStrategy(strategy))
+			if err != nil {
+				b.Fatalf("NewHelmholtzPlan failed: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := plan.Solve(dst, rhs); err != nil {
+					b.Fatalf("Solve failed: %v", err)
+				}
+			}
+		})
+	}
+}
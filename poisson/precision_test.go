@@ -0,0 +1,61 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestWithPrecisionCheck_WellConditioned(t *testing.T) {
+	plan, err := poisson.NewHelmholtzPlan(2, []int{16, 12}, []float64{0.1, 0.2},
+		[]poisson.BCType{poisson.Neumann, poisson.Dirichlet}, 0.5)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 16*12)
+	for i := range rhs {
+		rhs[i] = math.Sin(float64(i))
+	}
+
+	var stats poisson.SolveStats
+	dst := make([]float64, len(rhs))
+	if err := plan.Solve(dst, rhs, poisson.WithPrecisionCheck(32, 1e-12), poisson.WithStats(&stats)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if stats.PrecisionDeviation > 1e-14 {
+		t.Fatalf("PrecisionDeviation = %g, want <= 1e-14", stats.PrecisionDeviation)
+	}
+}
+
+func TestWithPrecisionCheck_DetectsCancellation(t *testing.T) {
+	// On a fine grid, 2 - 2cos(θ) for the lowest mode loses about
+	// log10(1/θ²) digits.
+	n := 1023
+	plan, err := poisson.NewPlan(1, []int{n}, []float64{1 / float64(n+1)}, []poisson.BCType{poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, n)
+	for i := range rhs {
+		rhs[i] = 1
+	}
+
+	var stats poisson.SolveStats
+	dst := make([]float64, n)
+	err = plan.Solve(dst, rhs, poisson.WithPrecisionCheck(4, 1e-12), poisson.WithStats(&stats))
+
+	var pErr *poisson.PrecisionError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("expected PrecisionError, got %v", err)
+	}
+	if pErr.Deviation != stats.PrecisionDeviation || pErr.Deviation <= 1e-12 {
+		t.Fatalf("Deviation = %g, stats %g", pErr.Deviation, stats.PrecisionDeviation)
+	}
+	if dst[n/2] == 0 {
+		t.Fatal("solution not written")
+	}
+}
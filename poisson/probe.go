@@ -0,0 +1,169 @@
+package poisson
+
+import (
+	"fmt"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
+// SolveAt solves for rhs like Solve, but evaluates the solution only at the
+// grid points probes ({i, j, k}; indices of unused axes must be 0) and stores
+// u(probes[q]) in dst[q]. The forward transforms and eigenvalue division run
+// as usual; instead of the inverse transforms, every probe value is a direct
+// sum over the spectral coefficients, so no full solution array is formed.
+//
+// Each probe costs O(N) for N grid points, against O(N log N) for the full
+// inverse transform, so SolveAt pays off for a few probes on large grids,
+// e.g. a sensor network. It allocates per-probe basis weights.
+//
+// SolveOptions apply as for Solve, except WithVerify, which needs the full
+// solution and is rejected. SolveAt runs on the CPU even for WithGPU plans.
+func (p *Plan) SolveAt(dst, rhs []float64, probes [][3]int, opts ...SolveOption) (err error) {
+	defer p.observeSolve("SolveAt", p.metricsStart(), &err)
+
+	if dst == nil || rhs == nil {
+		return ErrNilBuffer
+	}
+
+	if len(rhs) != p.size() {
+		return &SizeError{Expected: p.size(), Got: len(rhs), Context: "SolveAt rhs"}
+	}
+
+	if len(dst) != len(probes) {
+		return &SizeError{Expected: len(probes), Got: len(dst), Context: "SolveAt dst (one value per probe)"}
+	}
+
+	for q, probe := range probes {
+		for axis := range 3 {
+			if probe[axis] < 0 || probe[axis] >= p.n[axis] {
+				return &ValidationError{
+					Field:   fmt.Sprintf("probes[%d]", q),
+					Message: "outside the grid",
+				}
+			}
+		}
+	}
+
+	so := p.solveOptions(opts)
+	if so.err != nil {
+		return so.err
+	}
+	if so.verify {
+		return &ValidationError{
+			Field:   "WithVerify",
+			Message: "not supported by SolveAt: the residual needs the full solution",
+		}
+	}
+
+	if so.workers != p.opts.Workers {
+		p.limitWorkers(so.workers)
+		defer p.limitWorkers(0)
+	}
+
+	p.wsrc.acquire(&p.work)
+	defer p.wsrc.release(&p.work)
+
+	hasNullspace, err := p.loadRHS(rhs, grid.PackedView(p.shape()), so)
+	if err != nil {
+		return err
+	}
+
+	if err := p.forwardAll(); err != nil {
+		return err
+	}
+
+	if err := p.applyEigenvalues(so.filter); err != nil {
+		return err
+	}
+
+	addMean := 0.0
+	if hasNullspace && so.solutionMean != nil {
+		addMean = *so.solutionMean
+	}
+
+	if so.stats != nil {
+		so.stats.SolutionMean = addMean
+	}
+
+	var precisionErr error
+	if so.precisionSamples > 0 {
+		dev, mode := p.precisionDeviation(so.precisionSamples)
+		if so.stats != nil {
+			so.stats.PrecisionDeviation = dev
+		}
+		if !(dev <= so.precisionTol) {
+			precisionErr = &PrecisionError{Mode: mode, Deviation: dev, Tolerance: so.precisionTol}
+		}
+	}
+
+	workers := clampWorkers(p.workers(), len(probes))
+	if err := parallelFor(workers, len(probes), func(_ int, start, end int) error {
+		var weights [3][]complex128
+		for axis := range 3 {
+			weights[axis] = make([]complex128, p.n[axis])
+		}
+
+		for q := start; q < end; q++ {
+			probe := probes[q]
+			for axis := range 3 {
+				p.probeWeights(weights[axis], axis, probe[axis])
+			}
+
+			shift := addMean
+			if p.sliceAlpha != nil && !p.slabHasNullspace(probe[2]) {
+				shift = 0
+			}
+
+			dst[q] = p.modalSum(weights) + shift
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return precisionErr
+}
+
+// probeWeights fills w with the inverse-transform weights of every spectral
+// index along axis at grid index j. Untransformed axes (unused or the batch
+// axis of slab plans) select j directly.
+func (p *Plan) probeWeights(w []complex128, axis, j int) {
+	if axis >= p.dim {
+		clear(w)
+		w[j] = 1
+		return
+	}
+
+	n := p.n[axis]
+	for k := range w {
+		_, w[k] = basisCoefficients(n, p.bc[axis], j, k)
+	}
+}
+
+// modalSum returns the real part of Σ c[i,j,k] w0[i] w1[j] w2[k] over the
+// spectral coefficients c in the complex workspace.
+func (p *Plan) modalSum(w [3][]complex128) float64 {
+	ny, nz := p.n[1], p.n[2]
+
+	var total complex128
+	for i, wi := range w[0] {
+		if wi == 0 {
+			continue
+		}
+		for j, wj := range w[1] {
+			wij := wi * wj
+			if wij == 0 {
+				continue
+			}
+
+			row := p.work.Complex[(i*ny+j)*nz : (i*ny+j+1)*nz]
+			var line complex128
+			for k, c := range row {
+				line += c * w[2][k]
+			}
+			total += wij * line
+		}
+	}
+
+	return real(total)
+}
@@ -0,0 +1,110 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const probeTol = 1e-11
+
+func TestPlan_SolveAt_MatchesSolve(t *testing.T) {
+	n := []int{10, 9, 7}
+	h := []float64{0.1, 0.12, 0.2}
+	probes := [][3]int{{0, 0, 0}, {9, 8, 6}, {4, 3, 2}, {7, 0, 5}}
+
+	for _, bc := range [][]poisson.BCType{
+		{poisson.Periodic, poisson.Periodic, poisson.Periodic},
+		{poisson.Dirichlet, poisson.Neumann, poisson.Periodic},
+		{poisson.Neumann, poisson.Dirichlet, poisson.Dirichlet},
+	} {
+		t.Run(bc[0].String()+"/"+bc[1].String()+"/"+bc[2].String(), func(t *testing.T) {
+			plan, err := poisson.NewPlan(3, n, h, bc,
+				poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithSolutionMean(2))
+			if err != nil {
+				t.Fatalf("NewPlan: %v", err)
+			}
+
+			rhs := make([]float64, n[0]*n[1]*n[2])
+			for i := range rhs {
+				rhs[i] = math.Sin(0.35*float64(i)) + 0.2
+			}
+
+			full := make([]float64, len(rhs))
+			if err := plan.Solve(full, rhs); err != nil {
+				t.Fatalf("Solve: %v", err)
+			}
+
+			got := make([]float64, len(probes))
+			if err := plan.SolveAt(got, rhs, probes); err != nil {
+				t.Fatalf("SolveAt: %v", err)
+			}
+
+			for q, probe := range probes {
+				want := full[(probe[0]*n[1]+probe[1])*n[2]+probe[2]]
+				if math.Abs(got[q]-want) > probeTol {
+					t.Fatalf("probe %v: got %g, want %g", probe, got[q], want)
+				}
+			}
+		})
+	}
+}
+
+func TestPlan_SolveAt_LayeredSlabs(t *testing.T) {
+	n := []int{8, 6, 3}
+	alphas := []float64{0, 1, 4}
+	plan, err := poisson.NewLayeredHelmholtzPlan(n, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Periodic, poisson.Neumann}, alphas,
+		poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithSolutionMean(1))
+	if err != nil {
+		t.Fatalf("NewLayeredHelmholtzPlan: %v", err)
+	}
+
+	rhs := make([]float64, n[0]*n[1]*n[2])
+	for i := range rhs {
+		rhs[i] = math.Cos(0.5 * float64(i))
+	}
+
+	full := make([]float64, len(rhs))
+	if err := plan.Solve(full, rhs); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+
+	probes := [][3]int{{1, 2, 0}, {1, 2, 1}, {7, 5, 2}}
+	got := make([]float64, len(probes))
+	if err := plan.SolveAt(got, rhs, probes); err != nil {
+		t.Fatalf("SolveAt: %v", err)
+	}
+
+	for q, probe := range probes {
+		want := full[(probe[0]*n[1]+probe[1])*n[2]+probe[2]]
+		if math.Abs(got[q]-want) > probeTol {
+			t.Fatalf("probe %v: got %g, want %g", probe, got[q], want)
+		}
+	}
+}
+
+func TestPlan_SolveAt_Errors(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{8, 8}, []float64{0.1, 0.1}, []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan: %v", err)
+	}
+
+	rhs := make([]float64, 64)
+	var verr *poisson.ValidationError
+
+	if err := plan.SolveAt(make([]float64, 1), rhs, [][3]int{{0, 8, 0}}); !errors.As(err, &verr) {
+		t.Fatalf("out of range probe: got %v", err)
+	}
+	if err := plan.SolveAt(make([]float64, 1), rhs, [][3]int{{0, 0, 1}}); !errors.As(err, &verr) {
+		t.Fatalf("unused axis index: got %v", err)
+	}
+	if err := plan.SolveAt(make([]float64, 2), rhs, [][3]int{{0, 0, 0}}); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("dst length: got %v", err)
+	}
+	if err := plan.SolveAt(make([]float64, 1), rhs, [][3]int{{0, 0, 0}}, poisson.WithVerify(1e-8)); !errors.As(err, &verr) {
+		t.Fatalf("WithVerify: got %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package poisson_test
+
+func init() {
+	raceEnabled = true
+}
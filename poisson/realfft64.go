@@ -0,0 +1,171 @@
+package poisson
+
+import (
+	algofft "github.com/MeKo-Christian/algo-fft"
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
+// realFFT64 is the float64 real FFT path of the periodic 2D/3D plans
+// (WithRealFFTPrecision(RealFFTFloat64)): real-to-complex FFTs along the last
+// axis into a half spectrum of n/2+1 modes, then complex FFTs along the
+// leading axes of the half spectrum. Last axes whose length is not a power
+// of two use packedRealFFT instead of algo-fft's real plan.
+type realFFT64 struct {
+	n, half int
+	lines   int // number of lines along the last axis
+
+	// plans and in hold one real FFT plan and input line per worker.
+	plans []realTransform[float64, complex128]
+	in    [][]float64
+
+	// lead transforms the leading axes of the half spectrum.
+	lead    []*FFTPlan
+	spec    []complex128
+	shape   grid.Shape
+	workers int
+
+	// grid and dim describe the real grid, for errors.
+	grid grid.Shape
+	dim  int
+}
+
+// realLinePass holds the arguments of one pass over the lines along the last
+// axis, for parallelRun.
+type realLinePass struct {
+	data  []float64
+	shift float64
+}
+
+// newRealFFT64 creates the path for shape, whose last axis (lastAxis) must
+// have even length.
+func newRealFFT64(shape grid.Shape, lastAxis, workers int) (*realFFT64, error) {
+	n := shape[lastAxis]
+	half := n/2 + 1
+	lines := shape.Size() / n
+
+	plans := make([]realTransform[float64, complex128], workers)
+	in := make([][]float64, workers)
+	for w := range workers {
+		var (
+			plan realTransform[float64, complex128]
+			err  error
+		)
+		if isPowerOfTwo(n) {
+			plan, err = algofft.NewPlanReal64(n)
+		} else {
+			plan, err = newPackedRealFFT[float64, complex128](n)
+		}
+		if err != nil {
+			return nil, transformError("setup", "real FFT", lastAxis, shape, lastAxis+1, err)
+		}
+		plans[w] = plan
+		in[w] = make([]float64, n)
+	}
+
+	specShape := shape
+	specShape[lastAxis] = half
+
+	lead := make([]*FFTPlan, lastAxis)
+	for axis := range lead {
+		plan, err := NewFFTPlanWithWorkers(shape[axis], workers)
+		if err != nil {
+			return nil, err
+		}
+		lead[axis] = plan
+	}
+
+	return &realFFT64{
+		n:       n,
+		half:    half,
+		lines:   lines,
+		plans:   plans,
+		in:      in,
+		lead:    lead,
+		spec:    make([]complex128, lines*half),
+		shape:   specShape,
+		workers: workers,
+		grid:    shape,
+		dim:     lastAxis + 1,
+	}, nil
+}
+
+// forward transforms src - shift into the half spectrum r.spec.
+func (r *realFFT64) forward(src []float64, shift float64) error {
+	workers := clampWorkers(r.workers, r.lines)
+	if err := parallelRun(workers, r.lines, r, realLinePass{data: src, shift: shift}, (*realFFT64).forwardLines); err != nil {
+		return transformError("forward", "real FFT", r.dim-1, r.grid, r.dim, err)
+	}
+
+	for axis, plan := range r.lead {
+		if err := plan.TransformLines(r.spec, r.shape, axis, false); err != nil {
+			return transformError("forward", "FFT", axis, r.grid, r.dim, err)
+		}
+	}
+
+	return nil
+}
+
+// inverse transforms the half spectrum r.spec back into dst and adds shift.
+// r.spec is overwritten.
+func (r *realFFT64) inverse(dst []float64, shift float64) error {
+	for axis := len(r.lead) - 1; axis >= 0; axis-- {
+		if err := r.lead[axis].TransformLines(r.spec, r.shape, axis, true); err != nil {
+			return transformError("inverse", "FFT", axis, r.grid, r.dim, err)
+		}
+	}
+
+	workers := clampWorkers(r.workers, r.lines)
+	if err := parallelRun(workers, r.lines, r, realLinePass{data: dst, shift: shift}, (*realFFT64).inverseLines); err != nil {
+		return transformError("inverse", "real FFT", r.dim-1, r.grid, r.dim, err)
+	}
+
+	return nil
+}
+
+func (r *realFFT64) forwardLines(pass realLinePass, worker, start, end int) error {
+	in := r.in[worker]
+	for line := start; line < end; line++ {
+		for i, v := range pass.data[line*r.n : (line+1)*r.n] {
+			in[i] = v - pass.shift
+		}
+
+		if err := r.plans[worker].Forward(r.spec[line*r.half:(line+1)*r.half], in); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *realFFT64) inverseLines(pass realLinePass, worker, start, end int) error {
+	for line := start; line < end; line++ {
+		// The DC and Nyquist modes of a real line are real; drop the
+		// rounding residue of the leading-axis transforms, which the
+		// inverse rejects beyond an absolute 1e-12.
+		spec := r.spec[line*r.half : (line+1)*r.half]
+		spec[0] = complex(real(spec[0]), 0)
+		spec[r.half-1] = complex(real(spec[r.half-1]), 0)
+
+		out := pass.data[line*r.n : (line+1)*r.n]
+		if err := r.plans[worker].Inverse(out, spec); err != nil {
+			return err
+		}
+
+		if pass.shift != 0 {
+			for i := range out {
+				out[i] += pass.shift
+			}
+		}
+	}
+
+	return nil
+}
+
+// bytes returns the size of the path's buffers.
+func (r *realFFT64) bytes() int {
+	if r == nil {
+		return 0
+	}
+
+	return 16*len(r.spec) + 8*r.n*len(r.in)
+}
@@ -0,0 +1,127 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan2DPeriodic_RealFFTFloat64MatchesComplex(t *testing.T) {
+	nx, ny := 16, 8
+	h := 1.0 / 16
+
+	rhs := make([]float64, nx*ny)
+	for i := range nx {
+		for j := range ny {
+			rhs[i*ny+j] = math.Sin(float64(3*i+j)) + 0.25
+		}
+	}
+
+	for _, opts := range [][]poisson.Option{
+		{poisson.WithNullspace(poisson.NullspaceSubtractMean)},
+		{poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithSolutionMean(2), poisson.WithWorkers(3)},
+	} {
+		ref, err := poisson.NewPlan2DPeriodic(nx, ny, h, h, opts...)
+		if err != nil {
+			t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+		}
+
+		plan, err := poisson.NewPlan2DPeriodic(nx, ny, h, h, append(opts,
+			poisson.WithRealFFT(true), poisson.WithRealFFTPrecision(poisson.RealFFTFloat64))...)
+		if err != nil {
+			t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+		}
+		requireStatus(t, plan.Report(), "WithRealFFTPrecision", poisson.OptionHonored)
+		if path := plan.AccuracyProfile().Path; path != poisson.PathRealFloat64 {
+			t.Errorf("AccuracyProfile().Path = %v, want %v", path, poisson.PathRealFloat64)
+		}
+
+		want := make([]float64, nx*ny)
+		if err := ref.Solve(want, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+
+		got := append([]float64(nil), rhs...)
+		if err := plan.SolveInPlace(got); err != nil {
+			t.Fatalf("SolveInPlace failed: %v", err)
+		}
+
+		if diff := maxAbsDiff(got, want); diff > 1e-12 {
+			t.Errorf("float64 real FFT differs from complex path by %g", diff)
+		}
+	}
+}
+
+func TestPlan3DPeriodic_RealFFTFloat64MatchesComplex(t *testing.T) {
+	nx, ny, nz := 4, 8, 2
+	h := 0.2
+
+	rhs := make([]float64, nx*ny*nz)
+	for i := range rhs {
+		rhs[i] = math.Cos(float64(7 * i))
+	}
+
+	opts := []poisson.Option{poisson.WithNullspace(poisson.NullspaceSubtractMean)}
+	ref, err := poisson.NewPlan3DPeriodic(nx, ny, nz, h, h, h, opts...)
+	if err != nil {
+		t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+	}
+
+	plan, err := poisson.NewPlan3DPeriodic(nx, ny, nz, h, h, h, append(opts,
+		poisson.WithRealFFT(true), poisson.WithRealFFTPrecision(poisson.RealFFTFloat64))...)
+	if err != nil {
+		t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+	}
+	if path := plan.AccuracyProfile().Path; path != poisson.PathRealFloat64 {
+		t.Errorf("AccuracyProfile().Path = %v, want %v", path, poisson.PathRealFloat64)
+	}
+
+	want := make([]float64, nx*ny*nz)
+	if err := ref.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	got := make([]float64, nx*ny*nz)
+	if err := plan.Solve(got, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if diff := maxAbsDiff(got, want); diff > 1e-12 {
+		t.Errorf("float64 real FFT differs from complex path by %g", diff)
+	}
+}
+
+func TestRealFFTPrecision_ReportAndValidation(t *testing.T) {
+	// Without WithRealFFT, or with an odd last axis, the precision has no
+	// effect.
+	plan, err := poisson.NewPlan2DPeriodic(8, 8, 0.1, 0.1, poisson.WithRealFFTPrecision(poisson.RealFFTFloat64))
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+	requireStatus(t, plan.Report(), "WithRealFFTPrecision", poisson.OptionRejected)
+
+	odd, err := poisson.NewPlan3DPeriodic(4, 4, 3, 0.1, 0.1, 0.1,
+		poisson.WithRealFFT(true), poisson.WithRealFFTPrecision(poisson.RealFFTFloat64))
+	if err != nil {
+		t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+	}
+	requireStatus(t, odd.Report(), "WithRealFFTPrecision", poisson.OptionRejected)
+	if path := odd.AccuracyProfile().Path; path != poisson.PathComplex128 {
+		t.Errorf("AccuracyProfile().Path = %v, want %v", path, poisson.PathComplex128)
+	}
+
+	general, err := poisson.NewPlan(2, []int{8, 8}, []float64{0.1, 0.1}, []poisson.BCType{poisson.Periodic, poisson.Periodic},
+		poisson.WithRealFFT(true), poisson.WithRealFFTPrecision(poisson.RealFFTFloat64))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	requireStatus(t, general.Report(), "WithRealFFTPrecision", poisson.OptionRejected)
+
+	var verr *poisson.ValidationError
+	_, err = poisson.NewPlan2DPeriodic(8, 8, 0.1, 0.1, poisson.WithRealFFTPrecision(poisson.RealFFTPrecision(7)))
+	if !errors.As(err, &verr) || verr.Field != "RealFFTPrecision" {
+		t.Errorf("expected RealFFTPrecision ValidationError, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package poisson
+
+import (
+	"fmt"
+	"math"
+
+	algofft "github.com/MeKo-Christian/algo-fft"
+	"github.com/MeKo-Tech/algo-pde/grid"
+)
+
+// realTransform is a real-to-complex FFT into a half spectrum and its
+// normalized inverse, over one line or a whole grid. algo-fft's real plans
+// implement it, and so do packedRealFFT and packedRealGrid32 for the sizes
+// where those plans are wrong. Inverse may overwrite src.
+type realTransform[F float32 | float64, C complex64 | complex128] interface {
+	Forward(dst []C, src []F) error
+	Inverse(dst []F, src []C) error
+}
+
+// packedRealFFT is the real FFT of even length n computed with one
+// n/2-point complex FFT. The samples are packed as z[m] = x[2m] + i x[2m+1],
+// and with Z the FFT of z and w = exp(-2πi/n) the spectrum is untangled as
+//
+//	X[k] = (Z[k] + conj(Z[n/2-k]))/2 - i w^k (Z[k] - conj(Z[n/2-k]))/2.
+//
+// algo-fft's real plans use the same packing but run the complex FFT in
+// place, which gives wrong results for lengths that are not powers of two;
+// packedRealFFT runs it out of place, which is correct for every length.
+// The twiddles are applied in float64 for either precision.
+type packedRealFFT[F float32 | float64, C complex64 | complex128] struct {
+	n, half int
+	plan    *algofft.Plan[C]
+	twiddle []complex128 // w^k for k = 0..n/2
+	z, zhat []C
+}
+
+func newPackedRealFFT[F float32 | float64, C complex64 | complex128](n int) (*packedRealFFT[F, C], error) {
+	if n < 2 || n%2 != 0 {
+		return nil, fmt.Errorf("packed real FFT: length %d is not even", n)
+	}
+
+	half := n / 2
+	plan, err := algofft.NewPlanT[C](half)
+	if err != nil {
+		return nil, fmt.Errorf("creating FFT plan: %w", err)
+	}
+
+	twiddle := make([]complex128, half+1)
+	for k := range twiddle {
+		angle := -2 * math.Pi * float64(k) / float64(n)
+		twiddle[k] = complex(math.Cos(angle), math.Sin(angle))
+	}
+
+	return &packedRealFFT[F, C]{
+		n:       n,
+		half:    half,
+		plan:    plan,
+		twiddle: twiddle,
+		z:       make([]C, half),
+		zhat:    make([]C, half),
+	}, nil
+}
+
+// Forward computes the n/2+1 modes of the FFT of src into dst.
+func (p *packedRealFFT[F, C]) Forward(dst []C, src []F) error {
+	if len(dst) != p.half+1 || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	for m := range p.z {
+		p.z[m] = C(complex(float64(src[2*m]), float64(src[2*m+1])))
+	}
+
+	if err := p.plan.Forward(p.zhat, p.z); err != nil {
+		return err
+	}
+
+	z0 := complex128(p.zhat[0])
+	dst[0] = C(complex(real(z0)+imag(z0), 0))
+	dst[p.half] = C(complex(real(z0)-imag(z0), 0))
+
+	for k := 1; k < p.half; k++ {
+		a := complex128(p.zhat[k])
+		b := complex128(p.zhat[p.half-k])
+		b = complex(real(b), -imag(b))
+		dst[k] = C((a+b)/2 - 1i*p.twiddle[k]*(a-b)/2)
+	}
+
+	return nil
+}
+
+// Inverse computes the normalized inverse FFT of the half spectrum src into
+// dst, inverting Forward. src is not modified.
+func (p *packedRealFFT[F, C]) Inverse(dst []F, src []C) error {
+	if len(dst) != p.n || len(src) != p.half+1 {
+		return ErrSizeMismatch
+	}
+
+	// Z[k] = E[k] + i O[k] with the spectra E and O of the even and odd
+	// samples, E[k] = (X[k] + conj(X[n/2-k]))/2 and
+	// O[k] = conj(w^k) (X[k] - conj(X[n/2-k]))/2.
+	for k := range p.half {
+		a := complex128(src[k])
+		b := complex128(src[p.half-k])
+		b = complex(real(b), -imag(b))
+		w := p.twiddle[k]
+		p.zhat[k] = C((a+b)/2 + 1i*complex(real(w), -imag(w))*(a-b)/2)
+	}
+
+	if err := p.plan.Inverse(p.z, p.zhat); err != nil {
+		return err
+	}
+
+	for m, v := range p.z {
+		c := complex128(v)
+		dst[2*m] = F(real(c))
+		dst[2*m+1] = F(imag(c))
+	}
+
+	return nil
+}
+
+// packedRealGrid32 is the float32 real FFT of a periodic 2D or 3D grid for
+// sizes that are not powers of two, in the layout of algo-fft's PlanReal2D
+// and PlanReal3D: packed real FFTs along the last axis into n/2+1 modes,
+// then out-of-place complex FFTs along the leading axes of the half
+// spectrum. Like those plans it runs on one goroutine.
+type packedRealGrid32 struct {
+	n, half int
+	lines   int
+
+	row  *packedRealFFT[float32, complex64]
+	lead []*algofft.Plan[complex64] // nil for axes of length 1
+	spec grid.Shape
+	in   []complex64
+	out  []complex64
+}
+
+// newPackedRealGrid32 creates the transform for shape, whose last axis
+// (lastAxis) must have even length.
+func newPackedRealGrid32(shape grid.Shape, lastAxis int) (*packedRealGrid32, error) {
+	n := shape[lastAxis]
+	row, err := newPackedRealFFT[float32, complex64](n)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := shape
+	spec[lastAxis] = n/2 + 1
+
+	lead := make([]*algofft.Plan[complex64], lastAxis)
+	longest := 0
+	for axis := range lead {
+		if shape[axis] == 1 {
+			continue
+		}
+
+		plan, err := algofft.NewPlan32(shape[axis])
+		if err != nil {
+			return nil, fmt.Errorf("creating FFT plan: %w", err)
+		}
+		lead[axis] = plan
+		longest = max(longest, shape[axis])
+	}
+
+	return &packedRealGrid32{
+		n:     n,
+		half:  n/2 + 1,
+		lines: shape.Size() / n,
+		row:   row,
+		lead:  lead,
+		spec:  spec,
+		in:    make([]complex64, longest),
+		out:   make([]complex64, longest),
+	}, nil
+}
+
+// Forward transforms the grid src into the half spectrum dst.
+func (g *packedRealGrid32) Forward(dst []complex64, src []float32) error {
+	if len(dst) != g.lines*g.half || len(src) != g.lines*g.n {
+		return ErrSizeMismatch
+	}
+
+	for line := range g.lines {
+		if err := g.row.Forward(dst[line*g.half:(line+1)*g.half], src[line*g.n:(line+1)*g.n]); err != nil {
+			return err
+		}
+	}
+
+	for axis := range g.lead {
+		if err := g.transformLead(dst, axis, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Inverse transforms the half spectrum src back into the grid dst. src is
+// overwritten.
+func (g *packedRealGrid32) Inverse(dst []float32, src []complex64) error {
+	if len(dst) != g.lines*g.n || len(src) != g.lines*g.half {
+		return ErrSizeMismatch
+	}
+
+	for axis := len(g.lead) - 1; axis >= 0; axis-- {
+		if err := g.transformLead(src, axis, true); err != nil {
+			return err
+		}
+	}
+
+	for line := range g.lines {
+		if err := g.row.Inverse(dst[line*g.n:(line+1)*g.n], src[line*g.half:(line+1)*g.half]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// transformLead applies the complex FFT along a leading axis of the half
+// spectrum, line by line through the out-of-place buffers.
+func (g *packedRealGrid32) transformLead(spec []complex64, axis int, inverse bool) error {
+	plan := g.lead[axis]
+	if plan == nil {
+		return nil
+	}
+
+	m := g.spec[axis]
+	stride := grid.RowMajorStride(g.spec)[axis]
+	in, out := g.in[:m], g.out[:m]
+
+	for line := range lineCount(g.spec, axis) {
+		start := lineStartIndex(g.spec, axis, line)
+		for i := range in {
+			in[i] = spec[start+i*stride]
+		}
+
+		if err := plan.Transform(out, in, inverse); err != nil {
+			return err
+		}
+
+		for i, v := range out {
+			spec[start+i*stride] = v
+		}
+	}
+
+	return nil
+}
+
+// bytes returns the size of the transform's buffers.
+func (g *packedRealGrid32) bytes() int {
+	return 8 * (len(g.in) + len(g.out) + len(g.row.z) + len(g.row.zhat))
+}
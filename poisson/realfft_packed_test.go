@@ -0,0 +1,50 @@
+package poisson
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestPackedRealFFT_MatchesDFT(t *testing.T) {
+	for _, n := range []int{2, 6, 10, 12, 18, 20, 50, 100} {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = math.Sin(float64(i*i)+0.5) + 0.1*float64(i)
+		}
+
+		plan, err := newPackedRealFFT[float64, complex128](n)
+		if err != nil {
+			t.Fatalf("n=%d: newPackedRealFFT failed: %v", n, err)
+		}
+
+		spec := make([]complex128, n/2+1)
+		if err := plan.Forward(spec, x); err != nil {
+			t.Fatalf("n=%d: Forward failed: %v", n, err)
+		}
+
+		for k := range spec {
+			var want complex128
+			for j, v := range x {
+				want += complex(v, 0) * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k)/float64(n)))
+			}
+			if d := cmplx.Abs(spec[k] - want); d > 1e-10 {
+				t.Fatalf("n=%d: X[%d] = %v, want %v", n, k, spec[k], want)
+			}
+		}
+
+		back := make([]float64, n)
+		if err := plan.Inverse(back, spec); err != nil {
+			t.Fatalf("n=%d: Inverse failed: %v", n, err)
+		}
+		for i := range x {
+			if math.Abs(back[i]-x[i]) > 1e-12 {
+				t.Fatalf("n=%d: round trip x[%d] = %g, want %g", n, i, back[i], x[i])
+			}
+		}
+	}
+
+	if _, err := newPackedRealFFT[float32, complex64](7); err == nil {
+		t.Fatal("expected error for odd length")
+	}
+}
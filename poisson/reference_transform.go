@@ -0,0 +1,121 @@
+package poisson
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/r2r"
+)
+
+// referenceAxisTransform evaluates the DFT, DST-I or DCT-II of every line by
+// direct O(n²) summation. It uses the same conventions as the fast
+// transforms (unnormalized forward, normalized inverse) and exists for
+// debugging: swapping it in isolates transform errors from solver logic.
+type referenceAxisTransform struct {
+	n       int
+	fwd     []complex128 // fwd[k*n+j]: coefficient of x[j] in X[k]
+	inv     []complex128 // inv[j*n+k]: coefficient of X[k] in x[j]
+	workers int
+	bufs    [][]complex128
+	workerLimit
+}
+
+func newReferenceAxisTransform(n int, bc BCType, workers int) (AxisTransform, error) {
+	if n < 1 {
+		return nil, ErrInvalidSize
+	}
+
+	t := &referenceAxisTransform{
+		n:       n,
+		fwd:     make([]complex128, n*n),
+		inv:     make([]complex128, n*n),
+		workers: effectiveWorkers(workers),
+	}
+
+	for k := range n {
+		for j := range n {
+			t.fwd[k*n+j], t.inv[j*n+k] = basisCoefficients(n, bc, j, k)
+		}
+	}
+
+	t.bufs = make([][]complex128, t.workers)
+	for i := range t.bufs {
+		t.bufs[i] = make([]complex128, n)
+	}
+
+	return t, nil
+}
+
+// basisCoefficients returns, for a length-n transform with boundary
+// condition bc, the coefficient of x[j] in the forward result X[k] and the
+// coefficient of X[k] in the inverse result x[j], matching the conventions of
+// the fast transforms.
+func basisCoefficients(n int, bc BCType, j, k int) (fwd, inv complex128) {
+	switch bc {
+	case Dirichlet:
+		s := math.Sin(math.Pi * float64((j+1)*(k+1)) / float64(n+1))
+		return complex(s, 0), complex(2*s/float64(n+1), 0)
+	case Neumann:
+		c := r2r.DCT2Coefficient(j, k, n)
+		weight := 2.0 / float64(n)
+		if k == 0 {
+			weight = 1.0 / float64(n)
+		}
+		return complex(c, 0), complex(weight*c, 0)
+	default:
+		theta := 2 * math.Pi * float64(j*k%n) / float64(n)
+		return cmplx.Exp(complex(0, -theta)), cmplx.Exp(complex(0, theta)) / complex(float64(n), 0)
+	}
+}
+
+func (t *referenceAxisTransform) Forward(data []complex128, shape grid.Shape, axis int) error {
+	return t.transformLines(data, shape, axis, t.fwd)
+}
+
+func (t *referenceAxisTransform) Inverse(data []complex128, shape grid.Shape, axis int) error {
+	return t.transformLines(data, shape, axis, t.inv)
+}
+
+func (t *referenceAxisTransform) Length() int {
+	return t.n
+}
+
+func (t *referenceAxisTransform) NormalizationFactor() float64 {
+	return 1.0
+}
+
+func (t *referenceAxisTransform) transformLines(data []complex128, shape grid.Shape, axis int, matrix []complex128) error {
+	if data == nil {
+		return ErrNilBuffer
+	}
+
+	if len(data) != shape.Size() || shape.N(axis) != t.n {
+		return ErrSizeMismatch
+	}
+
+	n := t.n
+	stride := grid.RowMajorStride(shape)[axis]
+	numLines := lineCount(shape, axis)
+	workers := clampWorkers(t.capped(t.workers), numLines)
+
+	return parallelFor(workers, numLines, func(worker, startLine, endLine int) error {
+		buf := t.bufs[worker]
+		for line := startLine; line < endLine; line++ {
+			start := lineStartIndex(shape, axis, line)
+			for j := range n {
+				buf[j] = data[start+j*stride]
+			}
+
+			for k := range n {
+				var sum complex128
+				row := matrix[k*n : (k+1)*n]
+				for j, v := range buf {
+					sum += row[j] * v
+				}
+				data[start+k*stride] = sum
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+package poisson_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_WithReferenceTransformsMatchesFast(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     []int
+		bc    []poisson.BCType
+		alpha float64
+	}{
+		{"periodic 1D odd", []int{15}, []poisson.BCType{poisson.Periodic}, 1},
+		{"dirichlet 2D", []int{9, 12}, []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}, 0},
+		{"mixed 3D", []int{8, 7, 6}, []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := make([]float64, len(tt.n))
+			size := 1
+			for axis, n := range tt.n {
+				h[axis] = 1.0 / float64(n)
+				size *= n
+			}
+
+			fast, err := poisson.NewHelmholtzPlan(len(tt.n), tt.n, h, tt.bc, tt.alpha)
+			if err != nil {
+				t.Fatalf("NewHelmholtzPlan failed: %v", err)
+			}
+
+			ref, err := poisson.NewHelmholtzPlan(len(tt.n), tt.n, h, tt.bc, tt.alpha,
+				poisson.WithReferenceTransforms(), poisson.WithWorkers(2))
+			if err != nil {
+				t.Fatalf("NewHelmholtzPlan (reference) failed: %v", err)
+			}
+
+			rhs := make([]float64, size)
+			for i := range rhs {
+				rhs[i] = math.Sin(0.37*float64(i)) + 0.1*math.Cos(1.3*float64(i))
+			}
+
+			want := make([]float64, size)
+			if err := fast.Solve(want, rhs); err != nil {
+				t.Fatalf("fast Solve failed: %v", err)
+			}
+
+			got := make([]float64, size)
+			if err := ref.Solve(got, rhs, poisson.WithVerify(1e-9)); err != nil {
+				t.Fatalf("reference Solve failed: %v", err)
+			}
+
+			if diff := maxAbsDiff(got, want); diff > 1e-10 {
+				t.Fatalf("reference and fast transforms differ by %g", diff)
+			}
+		})
+	}
+}
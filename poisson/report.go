@@ -0,0 +1,167 @@
+package poisson
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// OptionStatus says how a plan resolved a requested option.
+type OptionStatus int
+
+const (
+	// OptionHonored means the option takes effect as requested.
+	OptionHonored OptionStatus = iota
+
+	// OptionDowngraded means a fallback with the same results is used
+	// instead, e.g. complex transforms when WithRealFFT is not supported
+	// for the grid.
+	OptionDowngraded
+
+	// OptionRejected means the option has no effect on this plan.
+	OptionRejected
+)
+
+// String returns the status name.
+func (s OptionStatus) String() string {
+	switch s {
+	case OptionHonored:
+		return "honored"
+	case OptionDowngraded:
+		return "downgraded"
+	case OptionRejected:
+		return "rejected"
+	default:
+		return "Unknown"
+	}
+}
+
+// OptionReport describes how one requested option was resolved.
+type OptionReport struct {
+	// Option is the name of the option function, e.g. "WithRealFFT".
+	Option string
+	Status OptionStatus
+
+	// Reason explains downgrades and rejections, and the choice made by
+	// options that select at plan time (e.g. TransformAuto).
+	Reason string
+}
+
+// PlanReport lists the options a plan was created with (those differing
+// from DefaultOptions) and how each was resolved. Plans fall back silently
+// rather than failing when an option does not apply; the report makes those
+// fallbacks visible. Retrieve it with Report on any plan type.
+type PlanReport struct {
+	// Plan is the plan type, e.g. "Plan2DPeriodic".
+	Plan    string
+	Options []OptionReport
+}
+
+// Lookup returns the entry for option, if it was requested.
+func (r PlanReport) Lookup(option string) (OptionReport, bool) {
+	for _, o := range r.Options {
+		if o.Option == option {
+			return o, true
+		}
+	}
+
+	return OptionReport{}, false
+}
+
+// AllHonored reports whether every requested option took effect.
+func (r PlanReport) AllHonored() bool {
+	for _, o := range r.Options {
+		if o.Status != OptionHonored {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String formats the report as one "option: status (reason)" line per option.
+func (r PlanReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s options:", r.Plan)
+	if len(r.Options) == 0 {
+		b.WriteString(" defaults")
+	}
+
+	for _, o := range r.Options {
+		fmt.Fprintf(&b, "\n  %s: %s", o.Option, o.Status)
+		if o.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", o.Reason)
+		}
+	}
+
+	return b.String()
+}
+
+// newPlanReport lists the options requested in opts. Options in supported
+// are honored; the others are rejected as unsupported by the plan type.
+// Constructors refine entries with set.
+func newPlanReport(plan string, opts Options, supported ...string) PlanReport {
+	r := PlanReport{Plan: plan}
+	for _, option := range requestedOptions(opts) {
+		entry := OptionReport{Option: option, Status: OptionHonored}
+		if !slices.Contains(supported, option) {
+			entry.Status = OptionRejected
+			entry.Reason = "not supported by " + plan
+		}
+		r.Options = append(r.Options, entry)
+	}
+
+	return r
+}
+
+// set updates the entry of a requested option; options that were not
+// requested are left out.
+func (r *PlanReport) set(option string, status OptionStatus, reason string) {
+	for i := range r.Options {
+		if r.Options[i].Option == option {
+			r.Options[i].Status = status
+			r.Options[i].Reason = reason
+		}
+	}
+}
+
+// requestedOptions returns the names of the options in opts that differ from
+// DefaultOptions, in declaration order.
+func requestedOptions(opts Options) []string {
+	var names []string
+	add := func(set bool, name string) {
+		if set {
+			names = append(names, name)
+		}
+	}
+
+	add(opts.Nullspace != NullspaceZeroMode, "WithNullspace")
+	add(opts.SolutionMean != nil, "WithSolutionMean")
+	add(opts.UseRealFFT, "WithRealFFT")
+	add(opts.RealFFTPrecision != RealFFTFloat32, "WithRealFFTPrecision")
+	add(opts.Workers != 0, "WithWorkers")
+	add(opts.InPlace, "WithInPlace")
+	add(opts.Resonance != ResonanceError || opts.ResonanceTolerance != 0, "WithResonance")
+	add(opts.BalanceFlux, "WithFluxBalance")
+	add(opts.SpectralFilter != nil, "WithSpectralFilter")
+	add(opts.ReferenceTransforms, "WithReferenceTransforms")
+	add(opts.PrecomputedInverse, "WithPrecomputedInverse")
+	add(opts.Workspace != nil, "WithWorkspace")
+	add(opts.PooledWorkspace, "WithPooledWorkspace")
+	add(opts.TransformStrategy != TransformStrided, "WithTransformStrategy")
+	add(opts.DivideKernel != nil, "WithDivideKernel")
+	add(opts.UseGPU, "WithGPU")
+	add(opts.AutoTune != 0, "WithAutoTune")
+	add(opts.Timing, "WithTiming")
+	add(opts.Metrics != nil, "WithMetrics")
+	add(opts.CheckFinite, "WithCheckFinite")
+	add(opts.Stencil != StandardStencil, "WithStencil")
+
+	return names
+}
+
+// periodicSupported lists the options every periodic plan type honors,
+// followed by extra.
+func periodicSupported(extra ...string) []string {
+	return append([]string{"WithNullspace", "WithSolutionMean", "WithWorkspace", "WithPooledWorkspace", "WithCheckFinite"}, extra...)
+}
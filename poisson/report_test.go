@@ -0,0 +1,121 @@
+package poisson_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func requireStatus(t *testing.T, r poisson.PlanReport, option string, want poisson.OptionStatus) poisson.OptionReport {
+	t.Helper()
+
+	entry, ok := r.Lookup(option)
+	if !ok {
+		t.Fatalf("%s: %s not reported:\n%s", r.Plan, option, r)
+	}
+	if entry.Status != want {
+		t.Errorf("%s: %s status = %s, want %s (%s)", r.Plan, option, entry.Status, want, entry.Reason)
+	}
+
+	return entry
+}
+
+func TestPlanReport_Defaults(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{8, 8}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	r := plan.Report()
+	if len(r.Options) != 0 || !r.AllHonored() {
+		t.Errorf("default plan reports options:\n%s", r)
+	}
+	if got := r.String(); got != "Plan options: defaults" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestPlanReport_RealFFTFallbacks(t *testing.T) {
+	p2, err := poisson.NewPlan2DPeriodic(8, 7, 0.1, 0.1, poisson.WithRealFFT(true))
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+	entry := requireStatus(t, p2.Report(), "WithRealFFT", poisson.OptionDowngraded)
+	if !strings.Contains(entry.Reason, "even ny") {
+		t.Errorf("reason %q does not explain the fallback", entry.Reason)
+	}
+
+	real2, err := poisson.NewPlan2DPeriodic(8, 8, 0.1, 0.1, poisson.WithRealFFT(true),
+		poisson.WithTransformStrategy(poisson.TransformBlocked))
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic failed: %v", err)
+	}
+	requireStatus(t, real2.Report(), "WithRealFFT", poisson.OptionHonored)
+	requireStatus(t, real2.Report(), "WithTransformStrategy", poisson.OptionRejected)
+
+	p3, err := poisson.NewPlan3DPeriodic(4, 4, 3, 0.1, 0.1, 0.1, poisson.WithRealFFT(true))
+	if err != nil {
+		t.Fatalf("NewPlan3DPeriodic failed: %v", err)
+	}
+	requireStatus(t, p3.Report(), "WithRealFFT", poisson.OptionDowngraded)
+
+	pn, err := poisson.NewPlanNDPeriodic(poisson.Shape{4, 4}, []float64{0.1, 0.1},
+		poisson.WithRealFFT(true), poisson.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("NewPlanNDPeriodic failed: %v", err)
+	}
+	requireStatus(t, pn.Report(), "WithRealFFT", poisson.OptionDowngraded)
+	requireStatus(t, pn.Report(), "WithWorkers", poisson.OptionRejected)
+
+	plan, err := poisson.NewPlan(1, []int{8}, []float64{0.1}, []poisson.BCType{poisson.Periodic},
+		poisson.WithRealFFT(true))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	requireStatus(t, plan.Report(), "WithRealFFT", poisson.OptionDowngraded)
+}
+
+func TestPlanReport_UnsupportedAndConflicting(t *testing.T) {
+	p1, err := poisson.NewPlan1DPeriodic(8, 0.1, poisson.WithInPlace(true), poisson.WithWorkers(1),
+		poisson.WithNullspace(poisson.NullspaceSubtractMean))
+	if err != nil {
+		t.Fatalf("NewPlan1DPeriodic failed: %v", err)
+	}
+	r := p1.Report()
+	requireStatus(t, r, "WithInPlace", poisson.OptionRejected)
+	requireStatus(t, r, "WithWorkers", poisson.OptionHonored)
+	requireStatus(t, r, "WithNullspace", poisson.OptionHonored)
+	if r.AllHonored() {
+		t.Error("AllHonored() with a rejected option")
+	}
+
+	n := []int{8, 6}
+	h := []float64{0.1, 0.1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Periodic}
+
+	filtered, err := poisson.NewPlan(2, n, h, bc, poisson.WithPrecomputedInverse(),
+		poisson.WithSpectralFilter(poisson.TwoThirdsFilter()))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	requireStatus(t, filtered.Report(), "WithPrecomputedInverse", poisson.OptionDowngraded)
+	requireStatus(t, filtered.Report(), "WithSpectralFilter", poisson.OptionHonored)
+
+	reference, err := poisson.NewPlan(2, n, h, bc, poisson.WithReferenceTransforms(),
+		poisson.WithTransformStrategy(poisson.TransformBlocked))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	requireStatus(t, reference.Report(), "WithTransformStrategy", poisson.OptionRejected)
+
+	// Report returns a copy.
+	r = reference.Report()
+	for i := range r.Options {
+		r.Options[i].Status = poisson.OptionHonored
+	}
+	if reference.Report().AllHonored() {
+		t.Error("modifying the report changed the plan")
+	}
+}
@@ -0,0 +1,165 @@
+package poisson
+
+import "math"
+
+// applyOperator computes dst = (alpha + scale*L^power) src, where L is the
+// negative Laplacian -Σ c_axis ∂²_axis with the plan's per-axis boundary
+// conditions, using the stencil that the spectral solve diagonalizes (see
+// Stencil). dst and src must not alias.
+func (p *Plan) applyOperator(dst, src []float64) {
+	if p.power == 1 {
+		p.applyLaplacian(dst, src)
+		for i, u := range src {
+			dst[i] = p.alphaAt(i%p.n[2])*u + p.scale*dst[i]
+		}
+		return
+	}
+
+	if len(p.verifyTmp) < len(src) {
+		p.verifyTmp = make([]float64, len(src))
+	}
+	tmp := p.verifyTmp[:len(src)]
+
+	copy(tmp, src)
+	for range p.power {
+		p.applyLaplacian(dst, tmp)
+		copy(tmp, dst)
+	}
+
+	for i, u := range src {
+		dst[i] = p.alphaAt(i%p.n[2])*u + p.scale*tmp[i]
+	}
+}
+
+// applyLaplacian computes dst = -Σ c_axis ∂²_axis src with the plan's
+// stencil. dst and src must not alias.
+func (p *Plan) applyLaplacian(dst, src []float64) {
+	p.applyStandardLaplacian(dst, src)
+	if p.opts.Stencil != StandardStencil {
+		p.addStencilProducts(dst, src)
+	}
+}
+
+// applyStandardLaplacian computes dst = -Σ c_axis ∂²_axis src with the
+// standard stencil. dst and src must not alias.
+func (p *Plan) applyStandardLaplacian(dst, src []float64) {
+	stride := [3]int{p.n[1] * p.n[2], p.n[2], 1}
+
+	for idx := range src {
+		u := src[idx]
+		coord := [3]int{idx / stride[0], (idx % stride[0]) / stride[1], idx % stride[1]}
+		sum := 0.0
+
+		for axis := 0; axis < p.dim; axis++ {
+			n := p.n[axis]
+			c := coord[axis]
+			s := stride[axis]
+
+			var left, right float64
+			switch {
+			case c > 0:
+				left = src[idx-s]
+			case p.bc[axis] == Periodic:
+				left = src[idx+(n-1)*s]
+			case p.bc[axis] == Neumann:
+				left = u
+			}
+
+			switch {
+			case c+1 < n:
+				right = src[idx+s]
+			case p.bc[axis] == Periodic:
+				right = src[idx-(n-1)*s]
+			case p.bc[axis] == Neumann:
+				right = u
+			}
+
+			sum += p.coeff[axis] * (2.0*u - left - right) / (p.h[axis] * p.h[axis])
+		}
+
+		dst[idx] = sum
+	}
+}
+
+// removeZeroMode subtracts from the verification reference ref the RHS mean
+// that the solve drops with the zero mode, for every singular slab.
+func (p *Plan) removeZeroMode(ref []float64) {
+	slabs := p.slabs()
+	if slabs > 1 {
+		// projectNullspace left the means of the singular slabs, and zero
+		// for the others, in slabMean.
+		for idx := range ref {
+			ref[idx] -= p.slabMean[idx%slabs]
+		}
+		return
+	}
+
+	mean := 0.0
+	for _, v := range ref {
+		mean += v
+	}
+	mean /= float64(len(ref))
+
+	for i := range ref {
+		ref[i] -= mean
+	}
+}
+
+// filterReference applies filter to the verification reference ref, so that
+// it is the RHS whose solution the filtered solve computed. It overwrites the
+// complex workspace.
+func (p *Plan) filterReference(ref []float64, filter FilterFunc) error {
+	for i, v := range ref {
+		p.work.Complex[i] = complex(v, 0)
+	}
+
+	if err := p.forwardAll(); err != nil {
+		return err
+	}
+
+	size := p.size()
+	workers := clampWorkers(p.workers(), size)
+	if err := parallelRun(workers, size, p, filter, (*Plan).filterModes); err != nil {
+		return err
+	}
+
+	if err := p.inverseAll(nil, 0); err != nil {
+		return err
+	}
+
+	for i, v := range p.work.Complex {
+		ref[i] = real(v)
+	}
+
+	return nil
+}
+
+// verifyResidual checks the solution in sol against the RHS stored in ref.
+func (p *Plan) verifyResidual(sol, ref []float64, tol float64) error {
+	maxRHS := 0.0
+	for _, v := range ref {
+		maxRHS = math.Max(maxRHS, math.Abs(v))
+	}
+
+	if len(p.verifyOut) < len(sol) {
+		p.verifyOut = make([]float64, len(sol))
+	}
+	out := p.verifyOut[:len(sol)]
+	p.applyOperator(out, sol)
+
+	maxRes := 0.0
+	for i, v := range out {
+		maxRes = math.Max(maxRes, math.Abs(v-ref[i]))
+	}
+
+	residual := maxRes
+	if maxRHS > 0 {
+		residual = maxRes / maxRHS
+	}
+
+	if residual > tol {
+		return &VerificationError{Residual: residual, Tolerance: tol}
+	}
+
+	return nil
+}
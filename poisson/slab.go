@@ -0,0 +1,84 @@
+package poisson
+
+import (
+	"fmt"
+	"math"
+)
+
+// NewSlabPlan creates a plan that solves nz independent 2D problems
+//
+//	(alpha - Δ_xy) u(:, :, k) = f(:, :, k),  k = 0..nz-1
+//
+// stored in one row-major 3D array of shape (nx, ny, nz), as produced by
+// operator splitting along z. n is {nx, ny, nz}; h and bc describe the x and
+// y axes only. The z axis is a batch axis: it is not transformed and the
+// slabs do not couple. The x/y transforms are shared by all slabs and
+// parallelized across all lines of the array.
+//
+// Nullspace handling applies per slab: each slab's RHS mean is checked or
+// subtracted on its own. SolveStats.SubtractedMean reports the average over
+// slabs. Boundary data for SolveWithBC covers all slabs (ny*nz values on X
+// faces, nx*nz on Y faces, in row-major order).
+func NewSlabPlan(n []int, h []float64, bc []BCType, alpha float64, opts ...Option) (*Plan, error) {
+	if len(n) != 3 {
+		return nil, &ValidationError{
+			Field:   "n",
+			Message: "must hold nx, ny, nz",
+		}
+	}
+
+	if n[2] < 1 {
+		return nil, invalidSize("n[2]")
+	}
+
+	return newPlan(2, n[:2], h, bc, alpha, n[2], opts...)
+}
+
+// NewLayeredHelmholtzPlan is like NewSlabPlan, but gives every slab its own
+// shift: slab k solves (alphas[k] - Δ_xy) u = f. This covers layered media
+// (one screening per layer) and per-frequency Helmholtz stacks
+// (alphas[k] = -ω_k²/c²), solved for all slabs in one parallel call.
+//
+// Slabs with a zero shift and Periodic/Neumann x/y boundaries are singular
+// and follow the plan's nullspace policy; the solution mean (WithSolutionMean,
+// WithGauge) applies to those slabs only. Resonance handling applies per mode
+// with the slab's own shift.
+func NewLayeredHelmholtzPlan(n []int, h []float64, bc []BCType, alphas []float64, opts ...Option) (*Plan, error) {
+	if len(n) != 3 {
+		return nil, &ValidationError{
+			Field:   "n",
+			Message: "must hold nx, ny, nz",
+		}
+	}
+
+	if len(alphas) != n[2] {
+		return nil, &ValidationError{
+			Field:   "alphas",
+			Message: "length must match nz",
+		}
+	}
+
+	for k, a := range alphas {
+		if math.IsNaN(a) || math.IsInf(a, 0) {
+			return nil, &ValidationError{
+				Field:   fmt.Sprintf("alphas[%d]", k),
+				Message: "must be finite",
+			}
+		}
+	}
+
+	plan, err := NewSlabPlan(n, h, bc, 0, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.sliceAlpha = append([]float64(nil), alphas...)
+
+	return plan, nil
+}
+
+// Slabs returns the number of independent 2D problems of a plan created with
+// NewSlabPlan or NewLayeredHelmholtzPlan, and 1 for all other plans.
+func (p *Plan) Slabs() int {
+	return p.slabs()
+}
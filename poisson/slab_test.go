@@ -0,0 +1,203 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestSlabPlan_MatchesPerSlab2D(t *testing.T) {
+	nx, ny, nz := 12, 9, 5
+	h := []float64{0.1, 0.2}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet}
+	alpha := 0.75
+
+	slab, err := poisson.NewSlabPlan([]int{nx, ny, nz}, h, bc, alpha, poisson.WithWorkers(3))
+	if err != nil {
+		t.Fatalf("NewSlabPlan failed: %v", err)
+	}
+
+	plan2D, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, h, bc, alpha)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, nx*ny*nz)
+	for i := range nx {
+		for j := range ny {
+			for k := range nz {
+				rhs[(i*ny+j)*nz+k] = math.Sin(0.4*float64(i)+float64(k)) * math.Cos(0.3*float64(j*(k+1)))
+			}
+		}
+	}
+
+	got := make([]float64, len(rhs))
+	if err := slab.Solve(got, rhs, poisson.WithVerify(1e-9)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	slice := make([]float64, nx*ny)
+	want := make([]float64, nx*ny)
+	for k := range nz {
+		for idx := range slice {
+			slice[idx] = rhs[idx*nz+k]
+		}
+		if err := plan2D.Solve(want, slice); err != nil {
+			t.Fatalf("2D Solve failed: %v", err)
+		}
+		for idx := range want {
+			if diff := math.Abs(got[idx*nz+k] - want[idx]); diff > 1e-12 {
+				t.Fatalf("slab %d index %d differs by %g", k, idx, diff)
+			}
+		}
+	}
+}
+
+func TestSlabPlan_NullspacePerSlab(t *testing.T) {
+	nx, ny, nz := 8, 6, 3
+	n := []int{nx, ny, nz}
+	h := []float64{0.25, 0.25}
+	bc := []poisson.BCType{poisson.Neumann, poisson.Periodic}
+
+	rhs := make([]float64, nx*ny*nz)
+	for idx := range rhs {
+		k := idx % nz
+		rhs[idx] = math.Sin(float64(idx)) + float64(k) // slab k has mean ≈ k
+	}
+
+	strict, err := poisson.NewSlabPlan(n, h, bc, 0)
+	if err != nil {
+		t.Fatalf("NewSlabPlan failed: %v", err)
+	}
+	dst := make([]float64, len(rhs))
+	if err := strict.Solve(dst, rhs); !errors.Is(err, poisson.ErrNonZeroMean) {
+		t.Fatalf("expected ErrNonZeroMean, got %v", err)
+	}
+
+	plan, err := poisson.NewSlabPlan(n, h, bc, 0, poisson.WithNullspace(poisson.NullspaceSubtractMean))
+	if err != nil {
+		t.Fatalf("NewSlabPlan failed: %v", err)
+	}
+	if err := plan.Solve(dst, rhs, poisson.WithVerify(1e-9)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	for k := range nz {
+		mean := 0.0
+		for idx := k; idx < len(dst); idx += nz {
+			mean += dst[idx]
+		}
+		if mean /= float64(nx * ny); math.Abs(mean) > 1e-12 {
+			t.Fatalf("slab %d solution mean %g, want 0", k, mean)
+		}
+	}
+}
+
+func TestNewSlabPlan_Invalid(t *testing.T) {
+	var vErr *poisson.ValidationError
+	if _, err := poisson.NewSlabPlan([]int{4, 4}, []float64{1, 1}, []poisson.BCType{poisson.Periodic, poisson.Periodic}, 1); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if _, err := poisson.NewSlabPlan([]int{4, 4, 0}, []float64{1, 1}, []poisson.BCType{poisson.Periodic, poisson.Periodic}, 1); !errors.Is(err, poisson.ErrInvalidSize) {
+		t.Fatalf("expected ErrInvalidSize, got %v", err)
+	}
+}
+
+func TestLayeredHelmholtzPlan_MatchesPerSlice(t *testing.T) {
+	nx, ny, nz := 10, 8, 4
+	n := []int{nx, ny, nz}
+	h := []float64{0.2, 0.15}
+	bc := []poisson.BCType{poisson.Neumann, poisson.Periodic}
+	alphas := []float64{0, 0.5, 3, 12}
+
+	plan, err := poisson.NewLayeredHelmholtzPlan(n, h, bc, alphas,
+		poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithSolutionMean(2))
+	if err != nil {
+		t.Fatalf("NewLayeredHelmholtzPlan failed: %v", err)
+	}
+	if plan.Slabs() != len(alphas) {
+		t.Fatalf("Slabs() = %d, want %d", plan.Slabs(), len(alphas))
+	}
+
+	rhs := make([]float64, nx*ny*nz)
+	for idx := range rhs {
+		rhs[idx] = math.Cos(0.7*float64(idx)) + 0.5
+	}
+
+	got := make([]float64, len(rhs))
+	if err := plan.Solve(got, rhs, poisson.WithVerify(1e-9)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	slice := make([]float64, nx*ny)
+	want := make([]float64, nx*ny)
+	for k, alpha := range alphas {
+		plan2D, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, h, bc, alpha,
+			poisson.WithNullspace(poisson.NullspaceSubtractMean), poisson.WithSolutionMean(2))
+		if err != nil {
+			t.Fatalf("NewHelmholtzPlan failed: %v", err)
+		}
+		for idx := range slice {
+			slice[idx] = rhs[idx*nz+k]
+		}
+		if err := plan2D.Solve(want, slice); err != nil {
+			t.Fatalf("2D Solve failed: %v", err)
+		}
+		for idx := range want {
+			if diff := math.Abs(got[idx*nz+k] - want[idx]); diff > 1e-12 {
+				t.Fatalf("slab %d (alpha %g) index %d differs by %g", k, alpha, idx, diff)
+			}
+		}
+	}
+
+	lo, hi := plan.MinMaxEigenvalue()
+	if lo != 0 || hi <= alphas[nz-1] {
+		t.Fatalf("MinMaxEigenvalue = (%g, %g), want (0, > %g)", lo, hi, alphas[nz-1])
+	}
+}
+
+func TestLayeredHelmholtzPlan_ResonantSlab(t *testing.T) {
+	n := []int{8, 8, 2}
+	h := []float64{1, 1}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Periodic}
+
+	// λ = 2 - 2cos(2π/8) is a mode of the periodic 8-point Laplacian.
+	lambda := 2 - 2*math.Cos(2*math.Pi/8)
+	plan, err := poisson.NewLayeredHelmholtzPlan(n, h, bc, []float64{1, -lambda})
+	if err != nil {
+		t.Fatalf("NewLayeredHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 8*8*2)
+	for idx := range rhs {
+		rhs[idx] = math.Sin(float64(idx))
+	}
+
+	var resErr *poisson.ResonantError
+	if err := plan.Solve(make([]float64, len(rhs)), rhs); !errors.As(err, &resErr) {
+		t.Fatalf("expected ResonantError, got %v", err)
+	}
+	if resErr.Alpha != -lambda {
+		t.Fatalf("ResonantError.Alpha = %g, want %g", resErr.Alpha, -lambda)
+	}
+}
+
+func TestNewLayeredHelmholtzPlan_Invalid(t *testing.T) {
+	n := []int{4, 4, 3}
+	h := []float64{1, 1}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Periodic}
+
+	for name, alphas := range map[string][]float64{
+		"Length": {1, 2},
+		"NaN":    {1, math.NaN(), 2},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var vErr *poisson.ValidationError
+			if _, err := poisson.NewLayeredHelmholtzPlan(n, h, bc, alphas); !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package poisson
+
+import "fmt"
+
+// SolveOption overrides plan options for a single Solve call.
+//
+// Solve options let one plan serve occasional variations (a different gauge
+// or nullspace policy, fewer workers, a verification pass, a spectral
+// filter) without building near-duplicate plans. They never modify the plan
+// itself.
+type SolveOption func(*solveOptions)
+
+// solveOptions holds the effective per-call configuration.
+type solveOptions struct {
+	nullspace    NullspaceHandling
+	workers      int
+	solutionMean *float64
+	verify       bool
+	verifyTol    float64
+	filter       FilterFunc
+	stats        *SolveStats
+
+	precisionSamples int
+	precisionTol     float64
+
+	// sparseRHS marks RHS arrays that are zero on most x-planes
+	// (SolveBoundary), so solveSpectral skips their transforms.
+	sparseRHS bool
+
+	// err holds the first invalid option value.
+	err error
+}
+
+// SolveStats reports what a single Solve call did to the nullspace, so
+// callers can reconstruct absolute fields or monitor drift between steps.
+type SolveStats struct {
+	// Nullspace reports whether the solved problem had a nullspace
+	// (constant mode with zero eigenvalue).
+	Nullspace bool
+
+	// SubtractedMean is the mean removed from the RHS before solving.
+	// It is non-zero only with NullspaceSubtractMean.
+	SubtractedMean float64
+
+	// ZeroModeCoefficient is the RHS mean, i.e. the normalized coefficient of
+	// the constant mode that a nullspace solve removes instead of inverting.
+	// Unlike SubtractedMean it is reported for every nullspace policy; with
+	// NullspaceZeroMode it is within round-off of zero.
+	ZeroModeCoefficient float64
+
+	// NetSource is ZeroModeCoefficient times the domain measure (length,
+	// area or volume, Π n_axis*h_axis): the net source that was dropped,
+	// e.g. the net charge for charge-neutrality checks in electrostatics.
+	NetSource float64
+
+	// SolutionMean is the constant added to the solution (the gauge value).
+	SolutionMean float64
+
+	// FluxAdjustment is the uniform volumetric source removed from the RHS
+	// to balance the net Neumann boundary flux (see WithFluxBalance).
+	// It is only set by SolveWithBC/SolveWithBCAt.
+	FluxAdjustment float64
+
+	// PrecisionDeviation is the largest relative deviation of the float64
+	// spectral symbol from a high-precision recomputation over the sampled
+	// modes. It is only set with WithPrecisionCheck.
+	PrecisionDeviation float64
+}
+
+// WithStats records per-solve statistics into stats. The struct is
+// overwritten on every call that receives this option.
+func WithStats(stats *SolveStats) SolveOption {
+	return func(o *solveOptions) {
+		o.stats = stats
+	}
+}
+
+// WithGauge sets the solution mean (gauge value) for this call only,
+// overriding the plan's WithSolutionMean setting. Like WithSolutionMean,
+// it only applies to problems with a nullspace.
+func WithGauge(mean float64) SolveOption {
+	// The option shares its captured mean, so reusing one option across
+	// calls does not allocate.
+	return func(o *solveOptions) {
+		o.solutionMean = &mean
+	}
+}
+
+// WithSolveNullspace sets the nullspace policy for this call only,
+// overriding the plan's WithNullspace setting, e.g. NullspaceSubtractMean
+// for an RHS that is known to carry a net source.
+func WithSolveNullspace(h NullspaceHandling) SolveOption {
+	return func(o *solveOptions) {
+		switch h {
+		case NullspaceZeroMode, NullspaceSubtractMean, NullspaceError:
+			o.nullspace = h
+		default:
+			o.setErr(&ValidationError{
+				Field:   "WithSolveNullspace",
+				Message: fmt.Sprintf("unknown nullspace handling %d", int(h)),
+			})
+		}
+	}
+}
+
+// WithSolveWorkers limits this call to n workers, e.g. to run small solves
+// sequentially on a plan built for large ones. The plan's transforms are
+// set up for its WithWorkers count, so larger values are clamped to it.
+func WithSolveWorkers(n int) SolveOption {
+	return func(o *solveOptions) {
+		if n < 1 {
+			o.setErr(&ValidationError{
+				Field:   "WithSolveWorkers",
+				Message: "must be at least 1",
+			})
+			return
+		}
+		o.workers = min(n, o.workers)
+	}
+}
+
+// WithVerify enables a residual check after solving. The discrete operator is
+// applied to the solution and compared with the RHS that was actually solved:
+// without the mean the nullspace handling removes (NullspaceSubtractMean) or
+// drops with the zero mode (NullspaceZeroMode), and with the spectral filter
+// of WithFilter or WithSpectralFilter applied. If the relative max-norm
+// residual exceeds tol, Solve returns a *VerificationError. The solution is
+// still written to dst.
+//
+// Verification costs one stencil application, plus a forward and inverse
+// transform of the RHS when filtering, and is meant for debugging and
+// testing, not for hot loops.
+func WithVerify(tol float64) SolveOption {
+	return func(o *solveOptions) {
+		o.verify = true
+		o.verifyTol = tol
+	}
+}
+
+// WithFilter applies a spectral filter to the solution coefficients for this
+// call only, replacing the plan's WithSpectralFilter setting. A nil filter
+// disables filtering.
+func WithFilter(filter FilterFunc) SolveOption {
+	return func(o *solveOptions) {
+		o.filter = filter
+	}
+}
+
+func (o *solveOptions) setErr(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+func (p *Plan) solveOptions(opts []SolveOption) solveOptions {
+	// The options are collected in the plan rather than a local, which
+	// would escape to the heap through the option calls.
+	p.so = solveOptions{
+		nullspace:    p.opts.Nullspace,
+		workers:      p.opts.Workers,
+		solutionMean: p.opts.SolutionMean,
+		filter:       p.opts.SpectralFilter,
+	}
+
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&p.so)
+		}
+	}
+
+	return p.so
+}
+
+// limitWorkers caps the plan's loops and axis transforms at n workers for
+// the running call; n <= 0 (or the plan's own count) removes the cap.
+func (p *Plan) limitWorkers(n int) {
+	if n >= p.opts.Workers {
+		n = 0
+	}
+
+	p.workerCap = n
+	for axis := range p.dim {
+		if l, ok := p.tr[axis].(workerLimiter); ok {
+			l.limitWorkers(n)
+		}
+	}
+}
+
+// workers returns the worker count of the plan's own loops.
+func (p *Plan) workers() int {
+	if p.workerCap > 0 {
+		return p.workerCap
+	}
+
+	return p.opts.Workers
+}
@@ -0,0 +1,449 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+const solveOptionsTol = 1e-10
+
+func TestPlan_Solve_WithGaugeOverridesPlanMean(t *testing.T) {
+	n := 32
+	h := 1.0 / float64(n)
+
+	plan, err := poisson.NewPlan(
+		1,
+		[]int{n},
+		[]float64{h},
+		[]poisson.BCType{poisson.Neumann},
+		poisson.WithNullspace(poisson.NullspaceSubtractMean),
+		poisson.WithSolutionMean(1.5),
+	)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, n)
+	for i := range rhs {
+		rhs[i] = math.Cos(math.Pi * (float64(i) + 0.5) / float64(n))
+	}
+
+	dst := make([]float64, n)
+	if err := plan.Solve(dst, rhs, poisson.WithGauge(-2)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if mean := sliceMean(dst); math.Abs(mean+2) > solveOptionsTol {
+		t.Fatalf("override mean = %g, want -2", mean)
+	}
+
+	if err := plan.Solve(dst, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if mean := sliceMean(dst); math.Abs(mean-1.5) > solveOptionsTol {
+		t.Fatalf("plan mean = %g, want 1.5 (override must not persist)", mean)
+	}
+}
+
+func TestPlan_Solve_WithVerify(t *testing.T) {
+	bcs := [][]poisson.BCType{
+		{poisson.Dirichlet, poisson.Neumann},
+		{poisson.Periodic, poisson.Dirichlet},
+		{poisson.Neumann, poisson.Neumann},
+	}
+
+	nx, ny := 12, 10
+	for _, bc := range bcs {
+		t.Run(bc[0].String()+"_"+bc[1].String(), func(t *testing.T) {
+			plan, err := poisson.NewPlan(
+				2,
+				[]int{nx, ny},
+				[]float64{0.1, 0.2},
+				bc,
+				poisson.WithNullspace(poisson.NullspaceSubtractMean),
+			)
+			if err != nil {
+				t.Fatalf("NewPlan failed: %v", err)
+			}
+
+			buf := make([]float64, nx*ny)
+			for i := range buf {
+				buf[i] = math.Sin(float64(i)*0.37) + 0.25
+			}
+
+			if err := plan.SolveInPlace(buf, poisson.WithVerify(1e-10)); err != nil {
+				t.Fatalf("Solve with verification failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlan_Solve_WithVerifyAndFilter(t *testing.T) {
+	bcs := [][]poisson.BCType{
+		{poisson.Dirichlet, poisson.Neumann},
+		{poisson.Periodic, poisson.Dirichlet},
+		{poisson.Neumann, poisson.Neumann},
+	}
+
+	nx, ny := 12, 10
+	for _, bc := range bcs {
+		t.Run(bc[0].String()+"_"+bc[1].String(), func(t *testing.T) {
+			plan, err := poisson.NewPlan(
+				2,
+				[]int{nx, ny},
+				[]float64{0.1, 0.2},
+				bc,
+				poisson.WithNullspace(poisson.NullspaceSubtractMean),
+				poisson.WithSpectralFilter(poisson.ExponentialFilter(36, 4)),
+			)
+			if err != nil {
+				t.Fatalf("NewPlan failed: %v", err)
+			}
+
+			buf := make([]float64, nx*ny)
+			for i := range buf {
+				buf[i] = math.Sin(float64(i)*0.37) + 0.25
+			}
+
+			// The filter removes most of the RHS's high modes, so the residual
+			// against the unfiltered RHS is large.
+			if err := plan.Solve(make([]float64, nx*ny), buf, poisson.WithVerify(1e-10)); err != nil {
+				t.Fatalf("Solve with spectral filter and verification failed: %v", err)
+			}
+			if err := plan.SolveInPlace(buf, poisson.WithFilter(poisson.TwoThirdsFilter()), poisson.WithVerify(1e-10)); err != nil {
+				t.Fatalf("Solve with WithFilter and verification failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestPlan_Solve_WithVerifyZeroModeMean(t *testing.T) {
+	n := 32
+	rhs := make([]float64, n)
+	for i := range rhs {
+		rhs[i] = 10*math.Cos(math.Pi*(float64(i)+0.5)/float64(n)) + 5e-12
+	}
+
+	plan, err := poisson.NewPlan(1, []int{n}, []float64{1.0 / float64(n)}, []poisson.BCType{poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	// NullspaceZeroMode accepts the small mean and drops it with the zero
+	// mode; verification must not count it as residual.
+	dst := make([]float64, n)
+	if err := plan.Solve(dst, rhs, poisson.WithVerify(1e-13)); err != nil {
+		t.Fatalf("Solve with verification failed: %v", err)
+	}
+
+	layered, err := poisson.NewLayeredHelmholtzPlan(
+		[]int{8, 6, 3},
+		[]float64{0.1, 0.2},
+		[]poisson.BCType{poisson.Periodic, poisson.Neumann},
+		[]float64{0, 2, 0},
+	)
+	if err != nil {
+		t.Fatalf("NewLayeredHelmholtzPlan failed: %v", err)
+	}
+
+	buf := make([]float64, 8*6*3)
+	for i := range buf {
+		buf[i] = 10*math.Sin(2*math.Pi*float64(i/18)/8) + 3e-12*float64(1+i%3)
+	}
+	if err := layered.SolveInPlace(buf, poisson.WithVerify(1e-13)); err != nil {
+		t.Fatalf("layered Solve with verification failed: %v", err)
+	}
+}
+
+func TestPlan_Solve_WithFilter(t *testing.T) {
+	n := 16
+	plan, err := poisson.NewHelmholtzPlan(1, []int{n}, []float64{1}, []poisson.BCType{poisson.Dirichlet}, 1)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, n)
+	for i := range rhs {
+		rhs[i] = math.Sin(math.Pi*float64(i+1)/float64(n+1)) +
+			math.Sin(math.Pi*float64(12*(i+1))/float64(n+1))
+	}
+
+	want := make([]float64, n)
+	if err := plan.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	got := make([]float64, n)
+	keepAll := func([3]float64) float64 { return 1 }
+	if err := plan.Solve(got, rhs, poisson.WithFilter(keepAll)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if diff := maxAbsDiff(got, want); diff > solveOptionsTol {
+		t.Fatalf("identity filter changed solution by %g", diff)
+	}
+
+	lowPass := func(eta [3]float64) float64 {
+		if eta[0] > 0.5 {
+			return 0
+		}
+		return 1
+	}
+	if err := plan.Solve(got, rhs, poisson.WithFilter(lowPass)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	lambda := 1 + 2 - 2*math.Cos(math.Pi/float64(n+1))
+	for i := range got {
+		exact := math.Sin(math.Pi*float64(i+1)/float64(n+1)) / lambda
+		if math.Abs(got[i]-exact) > solveOptionsTol {
+			t.Fatalf("filtered solution at %d: got %g, want %g", i, got[i], exact)
+		}
+	}
+
+	// Verification compares against the filtered RHS.
+	if err := plan.Solve(got, rhs, poisson.WithFilter(lowPass), poisson.WithVerify(1e-10)); err != nil {
+		t.Fatalf("filtered solve with verification failed: %v", err)
+	}
+}
+
+func TestPlan_Solve_WithStatsReportsMeans(t *testing.T) {
+	nx, ny := 16, 8
+	plan, err := poisson.NewPlan(
+		2,
+		[]int{nx, ny},
+		[]float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Periodic, poisson.Neumann},
+		poisson.WithNullspace(poisson.NullspaceSubtractMean),
+		poisson.WithSolutionMean(0.75),
+	)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, nx*ny)
+	for i := range rhs {
+		rhs[i] = 2 + math.Sin(float64(i))
+	}
+	wantMean := sliceMean(rhs)
+
+	var stats poisson.SolveStats
+	dst := make([]float64, nx*ny)
+	if err := plan.Solve(dst, rhs, poisson.WithStats(&stats)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if !stats.Nullspace {
+		t.Fatalf("expected Nullspace = true")
+	}
+	if math.Abs(stats.SubtractedMean-wantMean) > solveOptionsTol {
+		t.Fatalf("SubtractedMean = %g, want %g", stats.SubtractedMean, wantMean)
+	}
+	if stats.SolutionMean != 0.75 {
+		t.Fatalf("SolutionMean = %g, want 0.75", stats.SolutionMean)
+	}
+
+	dirichlet, err := poisson.NewPlan(1, []int{8}, []float64{1}, []poisson.BCType{poisson.Dirichlet},
+		poisson.WithNullspace(poisson.NullspaceSubtractMean))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	buf := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	if err := dirichlet.SolveInPlace(buf, poisson.WithStats(&stats)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if stats != (poisson.SolveStats{}) {
+		t.Fatalf("expected zero stats for Dirichlet problem, got %+v", stats)
+	}
+}
+
+func TestPlan_Solve_WithStatsReportsZeroMode(t *testing.T) {
+	n, h := 32, 0.05
+	rhs := make([]float64, n)
+	for i := range rhs {
+		rhs[i] = math.Cos(2 * math.Pi * float64(i) / float64(n))
+	}
+	rhs[3] += 1e-3 // net source of 1e-3 * h
+
+	plan, err := poisson.NewPlan(1, []int{n}, []float64{h}, []poisson.BCType{poisson.Periodic},
+		poisson.WithNullspace(poisson.NullspaceSubtractMean))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	var stats poisson.SolveStats
+	dst := make([]float64, n)
+	if err := plan.Solve(dst, rhs, poisson.WithStats(&stats)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	wantMean := sliceMean(rhs)
+	if math.Abs(stats.ZeroModeCoefficient-wantMean) > solveOptionsTol {
+		t.Fatalf("ZeroModeCoefficient = %g, want %g", stats.ZeroModeCoefficient, wantMean)
+	}
+	if math.Abs(stats.NetSource-1e-3*h) > solveOptionsTol {
+		t.Fatalf("NetSource = %g, want %g", stats.NetSource, 1e-3*h)
+	}
+
+	// NullspaceZeroMode reports the (round-off sized) coefficient it drops
+	// without subtracting anything.
+	rhs[3] -= 1e-3
+	strict, err := poisson.NewPlan(1, []int{n}, []float64{h}, []poisson.BCType{poisson.Periodic})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	if err := strict.Solve(dst, rhs, poisson.WithStats(&stats)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if stats.SubtractedMean != 0 || stats.ZeroModeCoefficient != sliceMean(rhs) {
+		t.Fatalf("stats = %+v, want ZeroModeCoefficient %g and no subtraction", stats, sliceMean(rhs))
+	}
+}
+
+// WithVerify keeps the solution in the plan workspace and so takes the
+// unfused output path; both paths must agree.
+func TestPlan_Solve_FusedOutputMatchesVerifiedSolve(t *testing.T) {
+	n := []int{10, 9, 8}
+	h := []float64{0.1, 0.1, 0.125}
+
+	for _, bcX := range []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann} {
+		t.Run(bcX.String(), func(t *testing.T) {
+			plan, err := poisson.NewPlan(
+				3, n, h,
+				[]poisson.BCType{bcX, poisson.Neumann, poisson.Periodic},
+				poisson.WithNullspace(poisson.NullspaceSubtractMean),
+				poisson.WithSolutionMean(0.75),
+			)
+			if err != nil {
+				t.Fatalf("NewPlan failed: %v", err)
+			}
+
+			rhs := make([]float64, n[0]*n[1]*n[2])
+			for i := range rhs {
+				rhs[i] = math.Cos(0.3*float64(i)) + 0.1
+			}
+
+			want := make([]float64, len(rhs))
+			if err := plan.Solve(want, rhs, poisson.WithVerify(1e-6)); err != nil {
+				t.Fatalf("Solve with verify failed: %v", err)
+			}
+
+			got := append([]float64(nil), rhs...)
+			if err := plan.SolveInPlace(got); err != nil {
+				t.Fatalf("SolveInPlace failed: %v", err)
+			}
+
+			if d := maxAbsDiff(got, want); d > 1e-13 {
+				t.Fatalf("max diff %g", d)
+			}
+		})
+	}
+}
+
+func TestPlan_Solve_WithSolveNullspaceOverridesPolicy(t *testing.T) {
+	n := []int{8, 6}
+	h := []float64{0.1, 0.1}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Neumann}
+
+	plan, err := poisson.NewPlan(2, n, h, bc)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 8*6)
+	for i := range rhs {
+		rhs[i] = math.Sin(float64(i)) + 0.5
+	}
+
+	dst := make([]float64, len(rhs))
+	if err := plan.Solve(dst, rhs); !errors.Is(err, poisson.ErrNonZeroMean) {
+		t.Fatalf("plan policy: expected ErrNonZeroMean, got %v", err)
+	}
+
+	var stats poisson.SolveStats
+	if err := plan.SolveWithBC(dst, rhs, nil, poisson.WithSolveNullspace(poisson.NullspaceSubtractMean),
+		poisson.WithStats(&stats)); err != nil {
+		t.Fatalf("SolveWithBC with override failed: %v", err)
+	}
+	if math.Abs(stats.SubtractedMean-sliceMean(rhs)) > solveOptionsTol {
+		t.Errorf("SubtractedMean = %g, want %g", stats.SubtractedMean, sliceMean(rhs))
+	}
+
+	ref, err := poisson.NewPlan(2, n, h, bc, poisson.WithNullspace(poisson.NullspaceSubtractMean))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	want := make([]float64, len(rhs))
+	if err := ref.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if diff := maxAbsDiff(dst, want); diff > solveOptionsTol {
+		t.Errorf("override differs from plan option by %g", diff)
+	}
+
+	if err := plan.Solve(dst, rhs, poisson.WithSolveNullspace(poisson.NullspaceError)); !errors.Is(err, poisson.ErrNullspace) {
+		t.Errorf("expected ErrNullspace, got %v", err)
+	}
+	if err := plan.Solve(dst, rhs); !errors.Is(err, poisson.ErrNonZeroMean) {
+		t.Errorf("override persisted: got %v", err)
+	}
+
+	var verr *poisson.ValidationError
+	if err := plan.Solve(dst, rhs, poisson.WithSolveNullspace(poisson.NullspaceHandling(9))); !errors.As(err, &verr) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestPlan_Solve_WithSolveWorkers(t *testing.T) {
+	n := []int{6, 8, 5}
+	h := []float64{0.1, 0.2, 0.1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Periodic, poisson.Neumann}
+
+	plan, err := poisson.NewPlan(3, n, h, bc, poisson.WithWorkers(3), poisson.WithTransformStrategy(poisson.TransformBlocked))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 6*8*5)
+	for i := range rhs {
+		rhs[i] = math.Cos(float64(3 * i))
+	}
+
+	want := make([]float64, len(rhs))
+	if err := plan.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	for _, workers := range []int{1, 2, 8} {
+		got := make([]float64, len(rhs))
+		if err := plan.Solve(got, rhs, poisson.WithSolveWorkers(workers)); err != nil {
+			t.Fatalf("Solve with %d workers failed: %v", workers, err)
+		}
+		if diff := maxAbsDiff(got, want); diff > solveOptionsTol {
+			t.Errorf("%d workers: solution differs by %g", workers, diff)
+		}
+	}
+
+	var verr *poisson.ValidationError
+	if err := plan.Solve(want, rhs, poisson.WithSolveWorkers(0)); !errors.As(err, &verr) {
+		t.Errorf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestPlan_Solve_WithSolveWorkersDoesNotAllocate(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{8, 8}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}, poisson.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 64)
+	dst := make([]float64, 64)
+	opt := poisson.WithSolveWorkers(1)
+
+	requireNoAllocs(t, "Solve with WithSolveWorkers", func() error {
+		return plan.Solve(dst, rhs, opt)
+	})
+}
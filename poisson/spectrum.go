@@ -0,0 +1,87 @@
+package poisson
+
+import (
+	"math"
+	"slices"
+)
+
+// AxisEigenvalues returns a copy of the 1D Laplacian eigenvalues used along
+// axis, in spectral index order. For plans with per-axis coefficients the
+// coefficient is already folded in. It returns nil for an invalid axis.
+func (p *Plan) AxisEigenvalues(axis int) []float64 {
+	if axis < 0 || axis >= p.dim {
+		return nil
+	}
+
+	eig := make([]float64, len(p.eig[axis]))
+	copy(eig, p.eig[axis])
+
+	return eig
+}
+
+// MinMaxEigenvalue returns the smallest and largest eigenvalue of the full
+// operator solved by the plan, i.e. alpha + λ over all modes (with λ raised
+// to the plan's power for hyperdiffusion plans).
+//
+// The ratio max/min is the condition number of a positive-definite operator.
+// For layered plans the bounds span all slab shifts.
+// For indefinite Helmholtz plans (alpha < 0), min may be negative; the
+// distance to resonance is the smallest |alpha + λ|, which lies between them.
+// Plans with an isotropic Stencil scan all modes.
+func (p *Plan) MinMaxEigenvalue() (minEig, maxEig float64) {
+	lo, hi := p.laplacianRange()
+
+	if p.power != 1 {
+		lo = p.scale * intPow(lo, p.power)
+		hi = p.scale * intPow(hi, p.power)
+	}
+
+	if p.sliceAlpha != nil {
+		return lo + slices.Min(p.sliceAlpha), hi + slices.Max(p.sliceAlpha)
+	}
+
+	return p.alpha + lo, p.alpha + hi
+}
+
+// laplacianRange returns the smallest and largest eigenvalue λ of the
+// plan's Laplacian stencil. For the standard stencil they are the sums of
+// the per-axis extremes; isotropic stencils scan all modes.
+func (p *Plan) laplacianRange() (lo, hi float64) {
+	if p.opts.Stencil != StandardStencil {
+		lo, hi = math.Inf(1), math.Inf(-1)
+		for idx := range p.size() {
+			lambda := p.laplacianSymbol(idx/(p.n[1]*p.n[2]), idx/p.n[2]%p.n[1], idx%p.n[2])
+			lo, hi = min(lo, lambda), max(hi, lambda)
+		}
+
+		return lo, hi
+	}
+
+	for axis := 0; axis < p.dim; axis++ {
+		axisMin, axisMax := p.eig[axis][0], p.eig[axis][0]
+		for _, v := range p.eig[axis][1:] {
+			axisMin = min(axisMin, v)
+			axisMax = max(axisMax, v)
+		}
+		lo += axisMin
+		hi += axisMax
+	}
+
+	return lo, hi
+}
+
+// AxisTransform returns the transform the plan applies along axis (FFT for
+// periodic, DST-I for Dirichlet, DCT-II for Neumann axes), so callers can
+// run partial transforms, e.g. along y only, with exactly the plan's
+// conventions and normalization. It returns nil for an invalid axis.
+//
+// The transform accepts any shape whose extent along the transformed axis is
+// Length(). It shares scratch buffers with the plan and must not be used
+// concurrently with it.
+func (p *Plan) AxisTransform(axis int) AxisTransform {
+	if axis < 0 || axis >= p.dim {
+		return nil
+	}
+
+	return p.tr[axis]
+}
@@ -0,0 +1,171 @@
+package poisson_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_AxisEigenvalues(t *testing.T) {
+	nx, ny := 10, 7
+	hx, hy := 0.1, 0.3
+
+	plan, err := poisson.NewPlan(2, []int{nx, ny}, []float64{hx, hy},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	want := [][]float64{fd.EigenvaluesDirichlet(nx, hx), fd.EigenvaluesNeumann(ny, hy)}
+	for axis, w := range want {
+		got := plan.AxisEigenvalues(axis)
+		if len(got) != len(w) {
+			t.Fatalf("axis %d: len %d, want %d", axis, len(got), len(w))
+		}
+		for i := range got {
+			if math.Abs(got[i]-w[i]) > 1e-12 {
+				t.Fatalf("axis %d mode %d: got %g, want %g", axis, i, got[i], w[i])
+			}
+		}
+
+		got[0] = -1
+		if plan.AxisEigenvalues(axis)[0] == -1 {
+			t.Fatalf("axis %d: AxisEigenvalues must return a copy", axis)
+		}
+	}
+
+	if plan.AxisEigenvalues(2) != nil || plan.AxisEigenvalues(-1) != nil {
+		t.Fatalf("expected nil for invalid axis")
+	}
+}
+
+func TestPlan_MinMaxEigenvalue(t *testing.T) {
+	nx, ny := 9, 6
+	hx, hy := 0.2, 0.25
+	alpha := 0.5
+
+	plan, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, []float64{hx, hy},
+		[]poisson.BCType{poisson.Periodic, poisson.Dirichlet}, alpha)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	eigX := fd.EigenvaluesPeriodic(nx, hx)
+	eigY := fd.EigenvaluesDirichlet(ny, hy)
+	wantMin, wantMax := math.Inf(1), math.Inf(-1)
+	for _, ex := range eigX {
+		for _, ey := range eigY {
+			wantMin = math.Min(wantMin, alpha+ex+ey)
+			wantMax = math.Max(wantMax, alpha+ex+ey)
+		}
+	}
+
+	gotMin, gotMax := plan.MinMaxEigenvalue()
+	if math.Abs(gotMin-wantMin) > 1e-12 || math.Abs(gotMax-wantMax) > 1e-12 {
+		t.Fatalf("MinMaxEigenvalue = (%g, %g), want (%g, %g)", gotMin, gotMax, wantMin, wantMax)
+	}
+}
+
+func TestPlan_AxisTransformMatchesSolve(t *testing.T) {
+	shape := grid.NewShape3D(4, 5, 6)
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+
+	plan, err := poisson.NewPlan(3, shape[:], []float64{0.3, 0.2, 0.1}, bc)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	if plan.AxisTransform(3) != nil || plan.AxisTransform(-1) != nil {
+		t.Error("AxisTransform accepted an invalid axis")
+	}
+
+	rhs := make([]float64, shape.Size())
+	data := make([]complex128, shape.Size())
+	for i := range rhs {
+		rhs[i] = math.Sin(float64(i)*0.7) + 0.25*float64(i%3)
+		data[i] = complex(rhs[i], 0)
+	}
+
+	// Forward along every axis, divide by the symbol, transform back: this
+	// must reproduce Solve.
+	norm := 1.0
+	for axis := range 3 {
+		tr := plan.AxisTransform(axis)
+		if tr.Length() != shape[axis] {
+			t.Fatalf("axis %d: Length() = %d, want %d", axis, tr.Length(), shape[axis])
+		}
+		if err := tr.Forward(data, shape, axis); err != nil {
+			t.Fatalf("axis %d: Forward failed: %v", axis, err)
+		}
+		norm *= tr.NormalizationFactor()
+	}
+
+	eig := [3][]float64{plan.AxisEigenvalues(0), plan.AxisEigenvalues(1), plan.AxisEigenvalues(2)}
+	idx := 0
+	for i := range shape[0] {
+		for j := range shape[1] {
+			for k := range shape[2] {
+				data[idx] /= complex(eig[0][i]+eig[1][j]+eig[2][k], 0)
+				idx++
+			}
+		}
+	}
+
+	for axis := range 3 {
+		if err := plan.AxisTransform(axis).Inverse(data, shape, axis); err != nil {
+			t.Fatalf("axis %d: Inverse failed: %v", axis, err)
+		}
+	}
+
+	got := make([]float64, len(data))
+	for i, v := range data {
+		got[i] = real(v) / norm
+	}
+
+	want := make([]float64, shape.Size())
+	if err := plan.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if diff := maxAbsDiff(got, want); diff > 1e-12 {
+		t.Errorf("transforms + symbol differ from Solve by %g", diff)
+	}
+}
+
+func TestPlan_AxisTransformPartial(t *testing.T) {
+	// A single Dirichlet mode along y transforms to one coefficient per x.
+	nx, ny := 3, 7
+	mode := 2
+	plan, err := poisson.NewPlan(2, []int{nx, ny}, []float64{1, 1},
+		[]poisson.BCType{poisson.Periodic, poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	data := make([]complex128, nx*ny)
+	for i := range nx {
+		for j := range ny {
+			data[i*ny+j] = complex(float64(i+1)*math.Sin(math.Pi*float64((j+1)*(mode+1))/float64(ny+1)), 0)
+		}
+	}
+
+	if err := plan.AxisTransform(1).Forward(data, grid.NewShape2D(nx, ny), 1); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+
+	for i := range nx {
+		for j := range ny {
+			v := data[i*ny+j]
+			if j != mode && cmplx.Abs(v) > 1e-12 {
+				t.Errorf("(%d, %d): coefficient %v, want 0", i, j, v)
+			}
+			if j == mode && cmplx.Abs(v) < 1e-6 {
+				t.Errorf("(%d, %d): mode coefficient vanished", i, j)
+			}
+		}
+	}
+}
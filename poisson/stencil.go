@@ -0,0 +1,197 @@
+package poisson
+
+import "math/big"
+
+// Stencil selects the discrete Laplacian a Plan inverts.
+type Stencil int
+
+const (
+	// StandardStencil is the second-order stencil with three points per
+	// axis: 5 points in 2D and 7 in 3D (default).
+	StandardStencil Stencil = iota
+
+	// Isotropic9 is the compact 9-point stencil of 2D plans,
+	//
+	//	(20u - 4Σ edge neighbors - Σ corner neighbors) / (6h²)
+	//
+	// for equal spacings. Its truncation error is isotropic to leading
+	// order, so coarse-grid solutions show less grid-aligned anisotropy.
+	Isotropic9
+
+	// Isotropic27 is the compact 27-point stencil of 3D plans,
+	//
+	//	(128u - 14Σ face - 3Σ edge - Σ corner neighbors) / (30h²)
+	//
+	// for equal spacings, with isotropic leading truncation error.
+	Isotropic27
+)
+
+// String returns the string representation of the stencil.
+func (s Stencil) String() string {
+	switch s {
+	case StandardStencil:
+		return "StandardStencil"
+	case Isotropic9:
+		return "Isotropic9"
+	case Isotropic27:
+		return "Isotropic27"
+	default:
+		return "Unknown"
+	}
+}
+
+// dim returns the plan dimension the stencil requires, or 0 for any.
+func (s Stencil) dim() int {
+	switch s {
+	case Isotropic9:
+		return 2
+	case Isotropic27:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// stencilWeights returns the weights of the product terms of the isotropic
+// stencils: the pair weights (h_a² + h_b²)/12 for the axis pairs (x,y),
+// (y,z), (x,z), and the triple weight of 27-point stencils.
+//
+// In the eigenbasis both stencils are polynomials of the per-axis
+// eigenvalues λ_a of the standard stencil,
+//
+//	λ = Σ λ_a - Σ_{a<b} pair_ab λ_a λ_b + triple λ_x λ_y λ_z,
+//
+// with triple = (hx²hy² + hy²hz² + hz²hx²)/90, which for equal spacings
+// gives the weights (20, -4, -1)/6 of Isotropic9 and (128, -14, -3, -1)/30
+// of Isotropic27.
+func stencilWeights(s Stencil, h [3]float64) (pair [3]float64, triple float64) {
+	h2 := [3]float64{h[0] * h[0], h[1] * h[1], h[2] * h[2]}
+	pair = [3]float64{(h2[0] + h2[1]) / 12, (h2[1] + h2[2]) / 12, (h2[0] + h2[2]) / 12}
+
+	if s == Isotropic27 {
+		triple = (h2[0]*h2[1] + h2[1]*h2[2] + h2[2]*h2[0]) / 90
+	}
+
+	return pair, triple
+}
+
+// laplacianSymbol returns the eigenvalue λ of the plan's Laplacian stencil
+// for mode (i, j, k), before any hyperdiffusion power.
+func (p *Plan) laplacianSymbol(i, j, k int) float64 {
+	l := [3]float64{p.eig[0][i]}
+	if p.dim > 1 {
+		l[1] = p.eig[1][j]
+	}
+	if p.dim > 2 {
+		l[2] = p.eig[2][k]
+	}
+
+	lambda := l[0] + l[1] + l[2]
+	if p.opts.Stencil == StandardStencil {
+		return lambda
+	}
+
+	pair, triple := stencilWeights(p.opts.Stencil, p.h)
+
+	return lambda - pair[0]*l[0]*l[1] - pair[1]*l[1]*l[2] - pair[2]*l[0]*l[2] + triple*l[0]*l[1]*l[2]
+}
+
+// combineSymbol applies the stencil combination of laplacianSymbol to the
+// high-precision per-axis eigenvalues l and returns λ.
+func (p *Plan) combineSymbol(l [3]*big.Float) *big.Float {
+	newFloat := func() *big.Float { return new(big.Float).SetPrec(precisionBits) }
+
+	lambda := newFloat()
+	for axis := range p.dim {
+		lambda.Add(lambda, l[axis])
+	}
+
+	if p.opts.Stencil == StandardStencil {
+		return lambda
+	}
+
+	pair, triple := stencilWeights(p.opts.Stencil, p.h)
+	for e, ab := range [3][2]int{{0, 1}, {1, 2}, {0, 2}} {
+		if ab[1] >= p.dim {
+			continue
+		}
+
+		term := newFloat().Mul(l[ab[0]], l[ab[1]])
+		lambda.Sub(lambda, term.Mul(term, newFloat().SetFloat64(pair[e])))
+	}
+
+	if p.dim == 3 && triple != 0 {
+		term := newFloat().Mul(l[0], l[1])
+		term.Mul(term, l[2])
+		lambda.Add(lambda, term.Mul(term, newFloat().SetFloat64(triple)))
+	}
+
+	return lambda
+}
+
+// applyAxis computes dst = -c ∂²src along one axis with the plan's boundary
+// condition on it. dst and src must not alias.
+func (p *Plan) applyAxis(dst, src []float64, axis int) {
+	stride := [3]int{p.n[1] * p.n[2], p.n[2], 1}
+	n, s := p.n[axis], stride[axis]
+	w := p.coeff[axis] / (p.h[axis] * p.h[axis])
+
+	for idx, u := range src {
+		c := idx / s % n
+
+		var left, right float64
+		switch {
+		case c > 0:
+			left = src[idx-s]
+		case p.bc[axis] == Periodic:
+			left = src[idx+(n-1)*s]
+		case p.bc[axis] == Neumann:
+			left = u
+		}
+
+		switch {
+		case c+1 < n:
+			right = src[idx+s]
+		case p.bc[axis] == Periodic:
+			right = src[idx-(n-1)*s]
+		case p.bc[axis] == Neumann:
+			right = u
+		}
+
+		dst[idx] = w * (2.0*u - left - right)
+	}
+}
+
+// addStencilProducts adds the product terms of an isotropic stencil to
+// dst, which holds the standard stencil applied to src, using the axis
+// operators of applyAxis. dst and src must not alias.
+func (p *Plan) addStencilProducts(dst, src []float64) {
+	for i := range p.stencilBuf {
+		if len(p.stencilBuf[i]) < len(src) {
+			p.stencilBuf[i] = make([]float64, len(src))
+		}
+	}
+	a, b := p.stencilBuf[0][:len(src)], p.stencilBuf[1][:len(src)]
+
+	pair, triple := stencilWeights(p.opts.Stencil, p.h)
+	for e, ab := range [3][2]int{{0, 1}, {1, 2}, {0, 2}} {
+		if ab[1] >= p.dim {
+			continue
+		}
+
+		p.applyAxis(a, src, ab[1])
+		p.applyAxis(b, a, ab[0])
+		for idx, v := range b {
+			dst[idx] -= pair[e] * v
+		}
+	}
+
+	if p.dim == 3 && triple != 0 {
+		p.applyAxis(a, src, 2)
+		p.applyAxis(b, a, 1)
+		p.applyAxis(a, b, 0)
+		for idx, v := range a {
+			dst[idx] += triple * v
+		}
+	}
+}
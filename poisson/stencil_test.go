@@ -0,0 +1,137 @@
+package poisson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/fd"
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestStencil_Isotropic9Verify(t *testing.T) {
+	nx, ny := 12, 10
+	h := []float64{0.1, 0.12}
+
+	for _, bc := range [][]poisson.BCType{
+		{poisson.Dirichlet, poisson.Neumann},
+		{poisson.Periodic, poisson.Dirichlet},
+	} {
+		plan, err := poisson.NewPlan(2, []int{nx, ny}, h, bc, poisson.WithStencil(poisson.Isotropic9))
+		if err != nil {
+			t.Fatalf("%v: NewPlan failed: %v", bc, err)
+		}
+
+		buf := make([]float64, nx*ny)
+		for i := range buf {
+			buf[i] = math.Sin(float64(i) * 0.7)
+		}
+
+		if err := plan.SolveInPlace(buf, poisson.WithVerify(1e-10)); err != nil {
+			t.Fatalf("%v: Solve failed: %v", bc, err)
+		}
+
+		lo, hi := plan.MinMaxEigenvalue()
+		eig := fd.Eigenvalues2D9(grid.NewShape2D(nx, ny), [2]float64{h[0], h[1]}, [2]poisson.BCType{bc[0], bc[1]})
+		wantLo, wantHi := math.Inf(1), 0.0
+		for _, v := range eig {
+			wantLo = math.Min(wantLo, v)
+			wantHi = math.Max(wantHi, v)
+		}
+		if math.Abs(lo-wantLo) > 1e-9 || math.Abs(hi-wantHi) > 1e-9*wantHi {
+			t.Errorf("%v: MinMaxEigenvalue = (%g, %g), want (%g, %g)", bc, lo, hi, wantLo, wantHi)
+		}
+	}
+}
+
+func TestStencil_Isotropic27Verify(t *testing.T) {
+	n := []int{6, 5, 4}
+	plan, err := poisson.NewPlan(3, n, []float64{0.2, 0.25, 0.3},
+		[]poisson.BCType{poisson.Neumann, poisson.Periodic, poisson.Dirichlet},
+		poisson.WithStencil(poisson.Isotropic27))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	buf := make([]float64, n[0]*n[1]*n[2])
+	for i := range buf {
+		buf[i] = math.Cos(float64(i) * 0.3)
+	}
+
+	if err := plan.SolveInPlace(buf, poisson.WithVerify(1e-10)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	requireStatus(t, plan.Report(), "WithStencil", poisson.OptionHonored)
+}
+
+func TestStencil_Rejected(t *testing.T) {
+	n := []int{8, 8}
+	h := []float64{0.1, 0.1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}
+
+	tests := []struct {
+		name string
+		dim  int
+		opts []poisson.Option
+	}{
+		{"WrongDimension", 2, []poisson.Option{poisson.WithStencil(poisson.Isotropic27)}},
+		{"OneDimensional", 1, []poisson.Option{poisson.WithStencil(poisson.Isotropic9)}},
+		{"Unknown", 2, []poisson.Option{poisson.WithStencil(poisson.Stencil(7))}},
+		{"GPU", 2, []poisson.Option{poisson.WithStencil(poisson.Isotropic9), poisson.WithGPU()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := poisson.NewPlan(tt.dim, n[:tt.dim], h[:tt.dim], bc[:tt.dim], tt.opts...)
+
+			var vErr *poisson.ValidationError
+			if !errors.As(err, &vErr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+		})
+	}
+
+	plan, err := poisson.NewPlan(2, n, h, bc, poisson.WithStencil(poisson.Isotropic9))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	dst := make([]float64, 64)
+	err = plan.SolveWithBC(dst, make([]float64, 64), poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Values: make([]float64, 8)},
+	})
+
+	var vErr *poisson.ValidationError
+	if !errors.As(err, &vErr) || vErr.Field != "Stencil" {
+		t.Errorf("SolveWithBC with boundary data: got %v, want Stencil ValidationError", err)
+	}
+}
+
+func TestStencil_ConfigRoundTrip(t *testing.T) {
+	cfg := poisson.PlanConfig{
+		Dims:    []int{8, 8},
+		Spacing: []float64{0.1, 0.1},
+		BC:      []poisson.BCType{poisson.Periodic, poisson.Periodic},
+		Stencil: poisson.Isotropic9,
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"stencil":"isotropic9"`) {
+		t.Errorf("stencil not encoded by name: %s", data)
+	}
+
+	got, err := poisson.LoadPlanConfig(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("LoadPlanConfig failed: %v", err)
+	}
+	if got.Stencil != poisson.Isotropic9 {
+		t.Errorf("Stencil = %v, want Isotropic9", got.Stencil)
+	}
+}
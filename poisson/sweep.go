@@ -0,0 +1,139 @@
+package poisson
+
+import (
+	"fmt"
+	"math"
+)
+
+// HelmholtzSweep solves the 2D Helmholtz problems
+//
+//	(alpha_k - Δ) u_k = f,  k = 0..len(alphas)-1
+//
+// for one source f and a list of shifts, as in an acoustic frequency-response
+// computation. All shifts are solved in one call of a layered plan (see
+// NewLayeredHelmholtzPlan), so the x/y transforms are shared across the
+// sweep. Memory grows with nx*ny*len(alphas).
+//
+// A HelmholtzSweep is not safe for concurrent use.
+type HelmholtzSweep struct {
+	plan   *Plan
+	alphas []float64
+	nx, ny int
+
+	rhs []float64
+	sol []float64
+}
+
+// NewHelmholtzSweep creates a sweep over alphas on an nx×ny grid. n is
+// {nx, ny}; h and bc describe the x and y axes as for NewPlan. Options are
+// passed to the underlying layered plan.
+func NewHelmholtzSweep(n []int, h []float64, bc []BCType, alphas []float64, opts ...Option) (*HelmholtzSweep, error) {
+	if len(n) != 2 {
+		return nil, &ValidationError{
+			Field:   "n",
+			Message: "must hold nx, ny",
+		}
+	}
+
+	if len(alphas) == 0 {
+		return nil, &ValidationError{
+			Field:   "alphas",
+			Message: "must not be empty",
+		}
+	}
+
+	plan, err := NewLayeredHelmholtzPlan([]int{n[0], n[1], len(alphas)}, h, bc, alphas, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	size := n[0] * n[1] * len(alphas)
+
+	return &HelmholtzSweep{
+		plan:   plan,
+		alphas: append([]float64(nil), alphas...),
+		nx:     n[0],
+		ny:     n[1],
+		rhs:    make([]float64, size),
+		sol:    make([]float64, size),
+	}, nil
+}
+
+// AlphasForFrequencies converts frequencies f (in Hz) to the Helmholtz
+// shifts alpha = -(2πf/c)² of (alpha - Δ) u = s for wave speed c, i.e. the
+// sign convention of NewHelmholtzPlan for -Δu - k²u = s.
+func AlphasForFrequencies(freqs []float64, c float64) []float64 {
+	alphas := make([]float64, len(freqs))
+	for i, f := range freqs {
+		k := 2 * math.Pi * f / c
+		alphas[i] = -k * k
+	}
+
+	return alphas
+}
+
+// Alphas returns a copy of the sweep's shifts.
+func (s *HelmholtzSweep) Alphas() []float64 {
+	return append([]float64(nil), s.alphas...)
+}
+
+// Plan returns the underlying layered plan.
+func (s *HelmholtzSweep) Plan() *Plan {
+	return s.plan
+}
+
+// Solve solves all shifts for the source rhs (length nx*ny, row-major) and
+// returns the solutions as a row-major (nx, ny, len(alphas)) array: the
+// response to alphas[k] at grid point (i, j) is at (i*ny+j)*len(alphas)+k.
+// The returned slice is owned by the sweep and overwritten by the next call.
+func (s *HelmholtzSweep) Solve(rhs []float64, opts ...SolveOption) ([]float64, error) {
+	if rhs == nil {
+		return nil, ErrNilBuffer
+	}
+
+	if len(rhs) != s.nx*s.ny {
+		return nil, ErrSizeMismatch
+	}
+
+	nk := len(s.alphas)
+	for idx, v := range rhs {
+		row := s.rhs[idx*nk : (idx+1)*nk]
+		for k := range row {
+			row[k] = v
+		}
+	}
+
+	if err := s.plan.Solve(s.sol, s.rhs, opts...); err != nil {
+		return nil, err
+	}
+
+	return s.sol, nil
+}
+
+// TransferFunction solves all shifts for the source rhs and samples the
+// responses at the probe grid points {i, j}. The result holds one row per
+// probe with one value per shift: result[p][k] = u_k(probes[p]).
+func (s *HelmholtzSweep) TransferFunction(rhs []float64, probes [][2]int, opts ...SolveOption) ([][]float64, error) {
+	for p, probe := range probes {
+		if probe[0] < 0 || probe[0] >= s.nx || probe[1] < 0 || probe[1] >= s.ny {
+			return nil, &ValidationError{
+				Field:   fmt.Sprintf("probes[%d]", p),
+				Message: "outside the grid",
+			}
+		}
+	}
+
+	sol, err := s.Solve(rhs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	nk := len(s.alphas)
+	result := make([][]float64, len(probes))
+	for p, probe := range probes {
+		off := (probe[0]*s.ny + probe[1]) * nk
+		result[p] = append([]float64(nil), sol[off:off+nk]...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestHelmholtzSweep_MatchesSingleSolves(t *testing.T) {
+	nx, ny := 16, 12
+	n := []int{nx, ny}
+	h := []float64{0.1, 0.1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Neumann}
+	alphas := poisson.AlphasForFrequencies([]float64{50, 120, 300}, 343)
+
+	sweep, err := poisson.NewHelmholtzSweep(n, h, bc, alphas, poisson.WithWorkers(2))
+	if err != nil {
+		t.Fatalf("NewHelmholtzSweep failed: %v", err)
+	}
+
+	rhs := make([]float64, nx*ny)
+	rhs[5*ny+4] = 1 / (h[0] * h[1]) // point source
+
+	probes := [][2]int{{5, 4}, {12, 9}, {0, 0}}
+	tf, err := sweep.TransferFunction(rhs, probes)
+	if err != nil {
+		t.Fatalf("TransferFunction failed: %v", err)
+	}
+
+	want := make([]float64, nx*ny)
+	for k, alpha := range alphas {
+		plan, err := poisson.NewHelmholtzPlan(2, n, h, bc, alpha)
+		if err != nil {
+			t.Fatalf("NewHelmholtzPlan failed: %v", err)
+		}
+		if err := plan.Solve(want, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+		for p, probe := range probes {
+			if diff := math.Abs(tf[p][k] - want[probe[0]*ny+probe[1]]); diff > 1e-12 {
+				t.Fatalf("probe %v alpha %g differs by %g", probe, alpha, diff)
+			}
+		}
+	}
+}
+
+func TestAlphasForFrequencies(t *testing.T) {
+	alphas := poisson.AlphasForFrequencies([]float64{0, 1}, 2*math.Pi)
+	if alphas[0] != 0 || math.Abs(alphas[1]+1) > 1e-15 {
+		t.Fatalf("AlphasForFrequencies = %v, want [0 -1]", alphas)
+	}
+}
+
+func TestHelmholtzSweep_Invalid(t *testing.T) {
+	h := []float64{1, 1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}
+
+	var vErr *poisson.ValidationError
+	if _, err := poisson.NewHelmholtzSweep([]int{4, 4}, h, bc, nil); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for empty alphas, got %v", err)
+	}
+
+	sweep, err := poisson.NewHelmholtzSweep([]int{4, 4}, h, bc, []float64{1, 2})
+	if err != nil {
+		t.Fatalf("NewHelmholtzSweep failed: %v", err)
+	}
+	if _, err := sweep.TransferFunction(make([]float64, 16), [][2]int{{4, 0}}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for probe outside grid, got %v", err)
+	}
+	if _, err := sweep.Solve(make([]float64, 15)); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("expected ErrSizeMismatch, got %v", err)
+	}
+}
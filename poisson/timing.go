@@ -0,0 +1,102 @@
+package poisson
+
+import (
+	"fmt"
+	"time"
+)
+
+// PlanStats accumulates the time a Plan spent in each stage of its solves,
+// recorded with WithTiming. Stages that a solve path fuses are attributed to
+// the stage doing the work: the output copy of a fused last inverse pass
+// counts as Inverse, and the device round trip of a WithGPU solve as
+// Forward.
+type PlanStats struct {
+	// Solves is the number of timed solves.
+	Solves uint64
+
+	// Forward is the time spent in forward transforms.
+	Forward time.Duration
+
+	// Divide is the time spent applying the inverse symbol (eigenvalue
+	// division, multiplication by a precomputed inverse, spectral filters).
+	Divide time.Duration
+
+	// Inverse is the time spent in inverse transforms.
+	Inverse time.Duration
+
+	// Copy is the time spent moving data between the caller's arrays and
+	// the workspace, including nullspace projection of the RHS.
+	Copy time.Duration
+}
+
+// Total returns the summed time of all stages.
+func (s PlanStats) Total() time.Duration {
+	return s.Forward + s.Divide + s.Inverse + s.Copy
+}
+
+// String formats the stage times with their share of the total.
+func (s PlanStats) String() string {
+	total := s.Total()
+	share := func(d time.Duration) float64 {
+		if total == 0 {
+			return 0
+		}
+		return 100 * float64(d) / float64(total)
+	}
+
+	return fmt.Sprintf("%d solves, %v: forward %v (%.0f%%), divide %v (%.0f%%), inverse %v (%.0f%%), copy %v (%.0f%%)",
+		s.Solves, total,
+		s.Forward, share(s.Forward), s.Divide, share(s.Divide),
+		s.Inverse, share(s.Inverse), s.Copy, share(s.Copy))
+}
+
+// WithTiming makes Plan record the time spent per solve stage (forward
+// transforms, eigenvalue division, inverse transforms, copies), read with
+// Plan.Stats. Without it, solves take no timestamps.
+func WithTiming() Option {
+	return func(o *Options) {
+		o.Timing = true
+	}
+}
+
+// Stats returns the stage times accumulated since the plan was created or
+// ResetStats was called. It is all zero for plans created without
+// WithTiming.
+func (p *Plan) Stats() PlanStats {
+	return p.stats
+}
+
+// ResetStats clears the accumulated stage times.
+func (p *Plan) ResetStats() {
+	p.stats = PlanStats{}
+}
+
+// countSolve counts a completed solve for WithTiming.
+func (p *Plan) countSolve() {
+	if p.opts.Timing {
+		p.stats.Solves++
+	}
+}
+
+// stageStart returns the start time of a stage, or the zero time when the
+// plan does not record timings.
+func (p *Plan) stageStart() time.Time {
+	if !p.opts.Timing {
+		return time.Time{}
+	}
+
+	return time.Now()
+}
+
+// stageEnd adds the time since start to stage, and returns the current time
+// as the start of the next stage.
+func (p *Plan) stageEnd(stage *time.Duration, start time.Time) time.Time {
+	if !p.opts.Timing {
+		return time.Time{}
+	}
+
+	now := time.Now()
+	*stage += now.Sub(start)
+
+	return now
+}
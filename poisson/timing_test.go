@@ -0,0 +1,83 @@
+package poisson_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_StatsRecordsStages(t *testing.T) {
+	n := []int{16, 12}
+	h := []float64{0.1, 0.1}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Periodic}
+
+	plan, err := poisson.NewPlan(2, n, h, bc, poisson.WithTiming())
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+	requireStatus(t, plan.Report(), "WithTiming", poisson.OptionHonored)
+
+	rhs := make([]float64, 16*12)
+	rhs[5] = 1
+	dst := make([]float64, len(rhs))
+
+	for range 3 {
+		if err := plan.Solve(dst, rhs); err != nil {
+			t.Fatalf("Solve failed: %v", err)
+		}
+	}
+	if err := plan.Solve(dst, rhs, poisson.WithVerify(1e-8)); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	stats := plan.Stats()
+	if stats.Solves != 4 {
+		t.Errorf("Solves = %d, want 4", stats.Solves)
+	}
+	if stats.Forward <= 0 || stats.Divide <= 0 || stats.Inverse <= 0 || stats.Copy <= 0 {
+		t.Errorf("stage not timed: %+v", stats)
+	}
+	if stats.Total() != stats.Forward+stats.Divide+stats.Inverse+stats.Copy {
+		t.Errorf("Total() = %v does not sum the stages", stats.Total())
+	}
+	if !strings.HasPrefix(stats.String(), "4 solves") {
+		t.Errorf("String() = %q", stats.String())
+	}
+
+	plan.ResetStats()
+	if plan.Stats() != (poisson.PlanStats{}) {
+		t.Errorf("ResetStats left %+v", plan.Stats())
+	}
+}
+
+func TestPlan_StatsWithoutTiming(t *testing.T) {
+	plan, err := poisson.NewPlan(1, []int{8}, []float64{0.1}, []poisson.BCType{poisson.Dirichlet})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 8)
+	if err := plan.Solve(rhs, rhs); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if plan.Stats() != (poisson.PlanStats{}) {
+		t.Errorf("untimed plan recorded %+v", plan.Stats())
+	}
+}
+
+func TestPlan_TimingDoesNotAllocate(t *testing.T) {
+	plan, err := poisson.NewPlan(2, []int{8, 8}, []float64{0.1, 0.1},
+		[]poisson.BCType{poisson.Neumann, poisson.Dirichlet}, poisson.WithTiming(), poisson.WithWorkers(1))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	rhs := make([]float64, 64)
+	dst := make([]float64, 64)
+
+	requireNoAllocs(t, "Solve with WithTiming", func() error {
+		return plan.Solve(dst, rhs)
+	})
+}
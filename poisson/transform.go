@@ -23,6 +23,14 @@ type AxisTransform interface {
 	NormalizationFactor() float64
 }
 
+// realInverter is implemented by axis transforms that can fuse the last
+// inverse pass of a solve with the output loop: inverseReal applies the
+// inverse transform along axis and stores the real part of each result plus
+// shift into dst (row-major, same shape), leaving data as scratch.
+type realInverter interface {
+	inverseReal(data []complex128, shape grid.Shape, axis int, dst []float64, shift float64) error
+}
+
 // Workspace holds pre-allocated buffers for solver operations.
 type Workspace struct {
 	// Real holds real-valued intermediate data.
@@ -0,0 +1,111 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestPlan_SolveView_GhostLayers(t *testing.T) {
+	nx, ny, ghost := 10, 7, 2
+	parent := grid.NewShape2D(nx+2*ghost, ny+2*ghost)
+	view := grid.SubView(parent, [3]int{ghost, ghost, 0})
+
+	plan, err := poisson.NewHelmholtzPlan(2, []int{nx, ny}, []float64{0.1, 0.15},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Neumann}, 0.5)
+	if err != nil {
+		t.Fatalf("NewHelmholtzPlan failed: %v", err)
+	}
+
+	packedRHS := make([]float64, nx*ny)
+	field := make([]float64, parent.Size())
+	for i := range field {
+		field[i] = -99
+	}
+	for i := range nx {
+		for j := range ny {
+			v := math.Sin(0.3*float64(i)) + math.Cos(0.7*float64(j))
+			packedRHS[i*ny+j] = v
+			field[view.Index(i, j, 0)] = v
+		}
+	}
+
+	want := make([]float64, nx*ny)
+	if err := plan.Solve(want, packedRHS); err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+
+	if err := plan.SolveView(field, view, field, view, poisson.WithVerify(1e-9)); err != nil {
+		t.Fatalf("SolveView failed: %v", err)
+	}
+
+	for i := range parent[0] {
+		for j := range parent[1] {
+			v := field[i*parent[1]+j]
+			interior := i >= ghost && i < nx+ghost && j >= ghost && j < ny+ghost
+			if !interior {
+				if v != -99 {
+					t.Fatalf("ghost cell (%d,%d) modified: %g", i, j, v)
+				}
+				continue
+			}
+			if diff := math.Abs(v - want[(i-ghost)*ny+(j-ghost)]); diff > 1e-12 {
+				t.Fatalf("interior (%d,%d) differs from packed solve by %g", i, j, diff)
+			}
+		}
+	}
+
+	if err := plan.SolveView(field, grid.SubView(parent, [3]int{ghost + 3, ghost, 0}), field, view); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("expected ErrSizeMismatch for out-of-range view, got %v", err)
+	}
+}
+
+func TestPlan_SolveWithBCView(t *testing.T) {
+	nx, ny := 8, 6
+	parent := grid.NewShape2D(nx+2, ny+2)
+	view := grid.SubView(parent, [3]int{1, 1, 0})
+
+	plan, err := poisson.NewPlan(2, []int{nx, ny}, []float64{0.2, 0.2},
+		[]poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}, poisson.WithInPlace(true))
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	bc := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Func: func(tm float64, idx int) float64 { return tm + float64(idx) }},
+		{Face: poisson.YHigh, Type: poisson.Dirichlet, Values: []float64{1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+
+	rhs := make([]float64, nx*ny)
+	field := make([]float64, parent.Size())
+	for i := range nx {
+		for j := range ny {
+			rhs[i*ny+j] = float64(i - j)
+			field[view.Index(i, j, 0)] = float64(i - j)
+		}
+	}
+
+	want := make([]float64, nx*ny)
+	if err := plan.SolveWithBCAt(0.5, want, append([]float64(nil), rhs...), bc); err != nil {
+		t.Fatalf("SolveWithBCAt failed: %v", err)
+	}
+
+	out := make([]float64, parent.Size())
+	if err := plan.SolveWithBCView(0.5, out, view, field, view, bc); err != nil {
+		t.Fatalf("SolveWithBCView failed: %v", err)
+	}
+
+	for i := range nx {
+		for j := range ny {
+			if diff := math.Abs(out[view.Index(i, j, 0)] - want[i*ny+j]); diff > 1e-12 {
+				t.Fatalf("(%d,%d) differs by %g", i, j, diff)
+			}
+			if field[view.Index(i, j, 0)] != rhs[i*ny+j] {
+				t.Fatalf("RHS window modified at (%d,%d)", i, j)
+			}
+		}
+	}
+}
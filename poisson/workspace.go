@@ -0,0 +1,93 @@
+package poisson
+
+import "sync"
+
+// workspacePool holds *Workspace values released by plans created with
+// WithPooledWorkspace. Workspaces of different sizes share the pool and are
+// grown on reuse when too small.
+var workspacePool sync.Pool
+
+// workspaceSource provides the workspace of a plan for the duration of a
+// solve: the plan's own buffers (default), an external Workspace shared with
+// other plans (WithWorkspace), or a buffer borrowed from the package pool
+// (WithPooledWorkspace).
+type workspaceSource struct {
+	external *Workspace
+	pooled   bool
+
+	realSize, complexSize int
+
+	// borrowed is the pooled workspace held between acquire and release.
+	borrowed *Workspace
+}
+
+// newWorkspaceSource returns the source for opts and the plan's initial
+// workspace: owned buffers of the given sizes by default, empty otherwise.
+func newWorkspaceSource(opts Options, realSize, complexSize int) (workspaceSource, Workspace) {
+	s := workspaceSource{
+		external:    opts.Workspace,
+		pooled:      opts.PooledWorkspace,
+		realSize:    realSize,
+		complexSize: complexSize,
+	}
+
+	if s.owned() {
+		return s, NewWorkspace(realSize, complexSize)
+	}
+
+	return s, Workspace{}
+}
+
+func (s *workspaceSource) owned() bool {
+	return s.external == nil && !s.pooled
+}
+
+// acquire points w at buffers of the plan's sizes. It is a no-op for owned
+// workspaces.
+func (s *workspaceSource) acquire(w *Workspace) {
+	switch {
+	case s.external != nil:
+		s.external.grow(s.realSize, s.complexSize)
+		w.Real = s.external.Real[:s.realSize]
+		w.Complex = s.external.Complex[:s.complexSize]
+	case s.pooled:
+		ws, _ := workspacePool.Get().(*Workspace)
+		if ws == nil {
+			ws = &Workspace{}
+		}
+		ws.grow(s.realSize, s.complexSize)
+		s.borrowed = ws
+		w.Real = ws.Real[:s.realSize]
+		w.Complex = ws.Complex[:s.complexSize]
+	}
+}
+
+// release detaches w from external or pooled buffers, returning pooled ones
+// to the pool. It is a no-op for owned workspaces.
+func (s *workspaceSource) release(w *Workspace) {
+	if s.owned() {
+		return
+	}
+
+	if s.borrowed != nil {
+		// Keep buffers the plan grew during the solve, e.g. SolveWithBC's
+		// RHS scratch on WithInPlace plans.
+		if cap(w.Real) > cap(s.borrowed.Real) {
+			s.borrowed.Real = w.Real[:cap(w.Real)]
+		}
+		workspacePool.Put(s.borrowed)
+		s.borrowed = nil
+	}
+
+	*w = Workspace{}
+}
+
+// grow makes the buffers at least realSize and complexSize long.
+func (w *Workspace) grow(realSize, complexSize int) {
+	if len(w.Real) < realSize {
+		w.Real = make([]float64, realSize)
+	}
+	if len(w.Complex) < complexSize {
+		w.Complex = make([]complex128, complexSize)
+	}
+}
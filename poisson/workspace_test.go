@@ -0,0 +1,127 @@
+package poisson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+func TestWithWorkspace_SharedAcrossPlans(t *testing.T) {
+	ws := &poisson.Workspace{}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Neumann}
+
+	sizes := [][]int{{8, 6}, {16, 12}, {4, 5}}
+	for _, n := range sizes {
+		h := []float64{0.1, 0.2}
+		owned, err := poisson.NewHelmholtzPlan(2, n, h, bc, 1)
+		if err != nil {
+			t.Fatalf("NewHelmholtzPlan: %v", err)
+		}
+		shared, err := poisson.NewHelmholtzPlan(2, n, h, bc, 1, poisson.WithWorkspace(ws))
+		if err != nil {
+			t.Fatalf("NewHelmholtzPlan: %v", err)
+		}
+
+		if got := shared.WorkBytes(); got != 0 {
+			t.Fatalf("shared plan holds %d workspace bytes", got)
+		}
+
+		rhs := make([]float64, n[0]*n[1])
+		for i := range rhs {
+			rhs[i] = math.Sin(float64(i))
+		}
+
+		want := make([]float64, len(rhs))
+		if err := owned.Solve(want, rhs); err != nil {
+			t.Fatalf("Solve: %v", err)
+		}
+		got := make([]float64, len(rhs))
+		if err := shared.Solve(got, rhs); err != nil {
+			t.Fatalf("Solve: %v", err)
+		}
+
+		if d := maxAbsDiff(got, want); d != 0 {
+			t.Fatalf("n=%v: max diff %g", n, d)
+		}
+	}
+
+	if len(ws.Complex) != 16*12 {
+		t.Fatalf("workspace has %d complex values, want the largest plan's %d", len(ws.Complex), 16*12)
+	}
+}
+
+func TestWithPooledWorkspace_MatchesOwned(t *testing.T) {
+	nx, ny := 12, 10
+	rhs := make([]float64, nx*ny)
+	for i := range rhs {
+		rhs[i] = math.Cos(0.3 * float64(i))
+	}
+
+	owned, err := poisson.NewPlan2DPeriodic(nx, ny, 0.1, 0.1, poisson.WithSubtractMean())
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic: %v", err)
+	}
+	pooled, err := poisson.NewPlan2DPeriodic(nx, ny, 0.1, 0.1, poisson.WithSubtractMean(), poisson.WithPooledWorkspace())
+	if err != nil {
+		t.Fatalf("NewPlan2DPeriodic: %v", err)
+	}
+
+	want := make([]float64, len(rhs))
+	if err := owned.Solve(want, rhs); err != nil {
+		t.Fatalf("Solve: %v", err)
+	}
+
+	got := make([]float64, len(rhs))
+	for range 3 {
+		if err := pooled.Solve(got, rhs); err != nil {
+			t.Fatalf("Solve: %v", err)
+		}
+		if d := maxAbsDiff(got, want); d != 0 {
+			t.Fatalf("max diff %g", d)
+		}
+	}
+}
+
+func TestWithPooledWorkspace_PlanSolveVariants(t *testing.T) {
+	n := []int{9, 7}
+	bc := []poisson.BCType{poisson.Dirichlet, poisson.Dirichlet}
+	plan, err := poisson.NewPlan(2, n, []float64{0.1, 0.1}, bc, poisson.WithPooledWorkspace(), poisson.WithInPlace(true))
+	if err != nil {
+		t.Fatalf("NewPlan: %v", err)
+	}
+
+	face := make([]float64, n[1])
+	for i := range face {
+		face[i] = 1
+	}
+	data := poisson.BoundaryConditions{{Face: poisson.XLow, Type: poisson.Dirichlet, Values: face}}
+
+	want := make([]float64, n[0]*n[1])
+	if err := plan.SolveWithBC(want, make([]float64, len(want)), data); err != nil {
+		t.Fatalf("SolveWithBC: %v", err)
+	}
+
+	got := make([]float64, len(want))
+	if err := plan.SolveBoundary(got, data); err != nil {
+		t.Fatalf("SolveBoundary: %v", err)
+	}
+	if d := maxAbsDiff(got, want); d > 1e-12 {
+		t.Fatalf("max diff %g", d)
+	}
+
+	if got := plan.WorkBytes(); got != 0 {
+		t.Fatalf("pooled plan holds %d workspace bytes", got)
+	}
+}
+
+func TestWithWorkspace_ConflictsWithPool(t *testing.T) {
+	_, err := poisson.NewPlan(1, []int{8}, []float64{0.1}, []poisson.BCType{poisson.Dirichlet},
+		poisson.WithWorkspace(&poisson.Workspace{}), poisson.WithPooledWorkspace())
+
+	var verr *poisson.ValidationError
+	if !errors.As(err, &verr) || verr.Field != "Workspace" {
+		t.Fatalf("got %v, want Workspace ValidationError", err)
+	}
+}
@@ -0,0 +1,181 @@
+package r2r
+
+import "math"
+
+// basisMatrix returns the rows×cols matrix with entries entry(k, j), backed
+// by one contiguous row-major slice.
+func basisMatrix(rows, cols int, entry func(k, j int) float64) [][]float64 {
+	data := make([]float64, rows*cols)
+	m := make([][]float64, rows)
+	for k := range m {
+		m[k] = data[k*cols : (k+1)*cols : (k+1)*cols]
+		for j := range cols {
+			m[k][j] = entry(k, j)
+		}
+	}
+
+	return m
+}
+
+// BasisMatrix returns the N×N matrix M of Forward, including the plan's
+// normalization: X[k] = Σ M[k][j] x[j]. Row k is basis function k sampled
+// at the grid points. The entries are evaluated from the closed-form
+// kernel (DST1Coefficient), not by the FFT, so the matrix serves as a dense
+// reference for testing and cross-validation. It costs O(N²) memory.
+func (p *DSTPlan) BasisMatrix() [][]float64 {
+	scale := 1.0
+	if p.opts.Normalization == NormOrtho {
+		scale = math.Sqrt(2.0 / float64(p.n+1))
+	}
+
+	return basisMatrix(p.n, p.n, func(k, j int) float64 {
+		return scale * DST1Coefficient(j, k, p.n)
+	})
+}
+
+// BasisMatrix returns the N×N matrix of Forward; see DSTPlan.BasisMatrix.
+func (p *DST2Plan) BasisMatrix() [][]float64 {
+	return basisMatrix(p.n, p.n, func(k, j int) float64 {
+		return p.modeScale(k) * DST2Coefficient(j, k, p.n)
+	})
+}
+
+// modeScale returns the NormOrtho scale of DST-II mode k (1/sqrt(N) for the
+// highest mode, sqrt(2/N) for the others), or 1.
+func (p *DST2Plan) modeScale(k int) float64 {
+	if k == p.n-1 {
+		return p.fft.s0
+	}
+
+	return p.fft.s
+}
+
+// BasisMatrix returns the N×N matrix of Forward, the transpose of the
+// DST-II's with NormOrtho; see DSTPlan.BasisMatrix.
+func (p *DST3Plan) BasisMatrix() [][]float64 {
+	n := p.dst2.n
+	ortho := p.dst2.opts.Normalization == NormOrtho
+
+	return basisMatrix(n, n, func(k, j int) float64 {
+		weight := 1.0
+		switch {
+		case ortho:
+			weight = p.dst2.modeScale(j)
+		case j == n-1:
+			weight = 0.5
+		}
+
+		return weight * DST3Coefficient(j, k, n)
+	})
+}
+
+// BasisMatrix returns the N×N matrix of Forward; see DSTPlan.BasisMatrix.
+func (p *DST4Plan) BasisMatrix() [][]float64 {
+	scale := p.dct4.orthoScale()
+
+	return basisMatrix(p.dct4.n, p.dct4.n, func(k, j int) float64 {
+		return scale * DST4Coefficient(j, k, p.dct4.n)
+	})
+}
+
+// BasisMatrix returns the N×N matrix of Forward, with the endpoint weight 1
+// and interior weight 2 of DCT-I; see DSTPlan.BasisMatrix.
+func (p *DCTPlan) BasisMatrix() [][]float64 {
+	scale := 1.0
+	if p.opts.Normalization == NormOrtho {
+		scale = 1.0 / math.Sqrt(2.0*float64(p.n-1))
+	}
+
+	return basisMatrix(p.n, p.n, func(k, j int) float64 {
+		weight := 2.0
+		if j == 0 || j == p.n-1 {
+			weight = 1.0
+		}
+
+		return scale * weight * DCT1Coefficient(j, k, p.n)
+	})
+}
+
+// BasisMatrix returns the N×N matrix of Forward; see DSTPlan.BasisMatrix.
+func (p *DCT2Plan) BasisMatrix() [][]float64 {
+	return basisMatrix(p.n, p.n, func(k, j int) float64 {
+		return p.modeScale(k) * DCT2Coefficient(j, k, p.n)
+	})
+}
+
+// modeScale returns the NormOrtho scale of DCT-II mode k (1/sqrt(N) for
+// mode 0, sqrt(2/N) for the others), or 1.
+func (p *DCT2Plan) modeScale(k int) float64 {
+	if k == 0 {
+		return p.fft.s0
+	}
+
+	return p.fft.s
+}
+
+// BasisMatrix returns the N×N matrix of Forward, the transpose of the
+// DCT-II's with NormOrtho; see DSTPlan.BasisMatrix.
+func (p *DCT3Plan) BasisMatrix() [][]float64 {
+	n := p.dct2.n
+	ortho := p.dct2.opts.Normalization == NormOrtho
+
+	return basisMatrix(n, n, func(k, j int) float64 {
+		weight := 1.0
+		switch {
+		case ortho:
+			weight = p.dct2.modeScale(j)
+		case j == 0:
+			weight = 0.5
+		}
+
+		return weight * DCT2Coefficient(k, j, n)
+	})
+}
+
+// BasisMatrix returns the N×N matrix of Forward; see DSTPlan.BasisMatrix.
+func (p *DCT4Plan) BasisMatrix() [][]float64 {
+	scale := p.orthoScale()
+
+	return basisMatrix(p.n, p.n, func(k, j int) float64 {
+		return scale * DCT4Coefficient(j, k, p.n)
+	})
+}
+
+// BasisMatrix returns the N×N matrix of Forward; see DSTPlan.BasisMatrix.
+func (p *DHTPlan) BasisMatrix() [][]float64 {
+	scale := 1.0
+	if p.opts.Normalization == NormOrtho {
+		scale = 1.0 / math.Sqrt(float64(p.n))
+	}
+
+	return basisMatrix(p.n, p.n, func(k, j int) float64 {
+		return scale * DHTCoefficient(j, k, p.n)
+	})
+}
+
+// BasisMatrix returns the N×2N matrix of Forward, including the window;
+// see DSTPlan.BasisMatrix. Inverse maps a single block by its transpose
+// (times 2/N with NormBackward).
+func (p *MDCTPlan) BasisMatrix() [][]float64 {
+	scale := p.scale()
+
+	return basisMatrix(p.n, 2*p.n, func(k, j int) float64 {
+		weight := scale
+		if p.window != nil {
+			weight *= p.window[j]
+		}
+
+		return weight * MDCTCoefficient(j, k, p.n)
+	})
+}
+
+// MDCTCoefficient returns the MDCT coefficient for mode k at position n of
+// a block of 2*size samples.
+// This is the basis function: cos(π(n+1/2+size/2)(k+1/2)/size).
+func MDCTCoefficient(n, k, size int) float64 {
+	if size <= 0 {
+		return 0
+	}
+
+	return math.Cos(math.Pi * (float64(n) + 0.5 + float64(size)/2) * (float64(k) + 0.5) / float64(size))
+}
@@ -0,0 +1,120 @@
+package r2r
+
+import (
+	"math"
+	"testing"
+)
+
+type basisPlan interface {
+	r2rPlan
+	BasisMatrix() [][]float64
+}
+
+func TestBasisMatrix_MatchesForward(t *testing.T) {
+	constructors := []struct {
+		name string
+		plan func(n int, opts ...Option) (basisPlan, error)
+	}{
+		{"DCT-I", func(n int, opts ...Option) (basisPlan, error) { return NewDCTPlan(n, opts...) }},
+		{"DCT-II", func(n int, opts ...Option) (basisPlan, error) { return NewDCT2Plan(n, opts...) }},
+		{"DCT-III", func(n int, opts ...Option) (basisPlan, error) { return NewDCT3Plan(n, opts...) }},
+		{"DCT-IV", func(n int, opts ...Option) (basisPlan, error) { return NewDCT4Plan(n, opts...) }},
+		{"DST-I", func(n int, opts ...Option) (basisPlan, error) { return NewDSTPlan(n, opts...) }},
+		{"DST-II", func(n int, opts ...Option) (basisPlan, error) { return NewDST2Plan(n, opts...) }},
+		{"DST-III", func(n int, opts ...Option) (basisPlan, error) { return NewDST3Plan(n, opts...) }},
+		{"DST-IV", func(n int, opts ...Option) (basisPlan, error) { return NewDST4Plan(n, opts...) }},
+		{"DHT", func(n int, opts ...Option) (basisPlan, error) { return NewDHTPlan(n, opts...) }},
+	}
+
+	for _, c := range constructors {
+		for _, n := range []int{5, 8} {
+			for _, norm := range []Normalization{NormBackward, NormOrtho} {
+				plan, err := c.plan(n, WithNormalization(norm))
+				if err != nil {
+					t.Fatalf("%s: creating plan failed: %v", c.name, err)
+				}
+
+				m := plan.BasisMatrix()
+				if len(m) != n || len(m[0]) != n {
+					t.Fatalf("%s: BasisMatrix is %dx%d, want %dx%d", c.name, len(m), len(m[0]), n, n)
+				}
+
+				// Column j of the matrix is Forward of the unit vector e_j.
+				unit := make([]float64, n)
+				col := make([]float64, n)
+				for j := range n {
+					clear(unit)
+					unit[j] = 1
+
+					if err := plan.Forward(col, unit); err != nil {
+						t.Fatalf("%s: Forward failed: %v", c.name, err)
+					}
+
+					for k := range n {
+						if math.Abs(m[k][j]-col[k]) > tolerance*float64(n) {
+							t.Errorf("%s n=%d norm %d: M[%d][%d] = %v, Forward gives %v", c.name, n, norm, k, j, m[k][j], col[k])
+						}
+					}
+				}
+
+				if norm != NormOrtho || c.name == "DCT-I" {
+					continue
+				}
+
+				// Orthonormal rows: M Mᵀ = I.
+				for a := range n {
+					for b := range n {
+						dot := 0.0
+						for j := range n {
+							dot += m[a][j] * m[b][j]
+						}
+
+						want := 0.0
+						if a == b {
+							want = 1
+						}
+
+						if math.Abs(dot-want) > tolerance*float64(n) {
+							t.Errorf("%s n=%d: row %d · row %d = %v, want %v", c.name, n, a, b, dot, want)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMDCTPlan_BasisMatrix(t *testing.T) {
+	const n = 6
+
+	plan, err := NewMDCTPlan(n, KBDWindow(n, 4), WithNormalization(NormOrtho))
+	if err != nil {
+		t.Fatalf("NewMDCTPlan failed: %v", err)
+	}
+
+	m := plan.BasisMatrix()
+	if len(m) != n || len(m[0]) != 2*n {
+		t.Fatalf("BasisMatrix is %dx%d, want %dx%d", len(m), len(m[0]), n, 2*n)
+	}
+
+	src := make([]float64, 2*n)
+	for i := range src {
+		src[i] = math.Cos(float64(i) * 1.1)
+	}
+
+	got := make([]float64, n)
+	if err := plan.Forward(got, src); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+
+	for k := range n {
+		want := 0.0
+		for j := range 2 * n {
+			want += m[k][j] * src[j]
+		}
+
+		if math.Abs(got[k]-want) > tolerance*n {
+			t.Errorf("X[%d] = %v, BasisMatrix gives %v", k, got[k], want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package r2r
+
+import (
+	"errors"
+
+	"github.com/MeKo-Tech/algo-pde/par"
+)
+
+// batchPlans returns plan followed by workers-1 clones of it, the per-worker
+// plans of ForwardMany and InverseMany. Clones share the read-only twiddle
+// tables of plan and own their FFT buffers.
+func batchPlans[P any](plan P, workers int, clone func() (P, error)) ([]P, error) {
+	plans := make([]P, max(workers, 1))
+	plans[0] = plan
+
+	for w := 1; w < len(plans); w++ {
+		c, err := clone()
+		if err != nil {
+			return nil, err
+		}
+		plans[w] = c
+	}
+
+	return plans, nil
+}
+
+// runBatch applies fn to the count vectors of length n stored back to back
+// in dst and src, splitting them into contiguous ranges over the plans with
+// par.For. It returns the error of the first failing range.
+func runBatch[P any](plans []P, dst, src []float64, n, count int, fn func(p P, dst, src []float64) error) error {
+	if count < 0 || len(dst) != n*count || len(src) != n*count {
+		return ErrSizeMismatch
+	}
+
+	workers := min(len(plans), count)
+	if workers <= 1 {
+		return runVectors(plans[0], dst, src, n, 0, count, fn)
+	}
+
+	// Errors are collected per range so that the first failing range, not
+	// the first to fail, is reported.
+	errs := make([]error, workers)
+	_ = par.For(workers, count, func(w, start, end int) error {
+		errs[w] = runVectors(plans[w], dst, src, n, start, end, fn)
+		return nil
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runVectors applies fn with plan p to vectors [start, end) of a batch. The
+// index of a *NonFiniteError is made relative to the whole batch.
+func runVectors[P any](p P, dst, src []float64, n, start, end int, fn func(p P, dst, src []float64) error) error {
+	for i := start; i < end; i++ {
+		if err := fn(p, dst[i*n:(i+1)*n], src[i*n:(i+1)*n]); err != nil {
+			var nonFinite *NonFiniteError
+			if errors.As(err, &nonFinite) {
+				nonFinite.Index += i * n
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// batchBytes returns the memory of the clones in plans, which are not
+// counted by the Bytes of plans[0] itself: the FFT buffers of each, as
+// returned by buffers, since the twiddle tables are shared.
+func batchBytes[P any](plans []P, buffers func(P) int) int {
+	total := 0
+	for _, p := range plans[min(1, len(plans)):] {
+		total += buffers(p)
+	}
+
+	return total
+}
+
+// ForwardMany applies Forward to count vectors of length n stored back to
+// back in src (vector i is src[i*n:(i+1)*n]) and writes the results to dst
+// in the same layout. dst and src may be the same slice. The vectors are
+// split over the goroutines set with WithWorkers; the plan's twiddle tables
+// are shared, so a batch costs count single transforms without per-call
+// setup.
+func (p *DSTPlan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DSTPlan).Forward)
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// ForwardMany for the layout.
+func (p *DSTPlan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DSTPlan).Inverse)
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DST2Plan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DST2Plan).Forward)
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DST2Plan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DST2Plan).Inverse)
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DST3Plan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.dst2.batch, dst, src, p.dst2.n, count, func(q *DST2Plan, dst, src []float64) error {
+		return (&DST3Plan{dst2: q}).Forward(dst, src)
+	})
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DST3Plan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.dst2.batch, dst, src, p.dst2.n, count, func(q *DST2Plan, dst, src []float64) error {
+		return (&DST3Plan{dst2: q}).Inverse(dst, src)
+	})
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DST4Plan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.dct4.batch, dst, src, p.dct4.n, count, func(q *DCT4Plan, dst, src []float64) error {
+		return (&DST4Plan{dct4: q}).Forward(dst, src)
+	})
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DST4Plan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.dct4.batch, dst, src, p.dct4.n, count, func(q *DCT4Plan, dst, src []float64) error {
+		return (&DST4Plan{dct4: q}).Inverse(dst, src)
+	})
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCTPlan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DCTPlan).Forward)
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCTPlan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DCTPlan).Inverse)
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCT2Plan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DCT2Plan).Forward)
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCT2Plan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DCT2Plan).Inverse)
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCT3Plan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.dct2.batch, dst, src, p.dct2.n, count, func(q *DCT2Plan, dst, src []float64) error {
+		return (&DCT3Plan{dct2: q}).Forward(dst, src)
+	})
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCT3Plan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.dct2.batch, dst, src, p.dct2.n, count, func(q *DCT2Plan, dst, src []float64) error {
+		return (&DCT3Plan{dct2: q}).Inverse(dst, src)
+	})
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCT4Plan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DCT4Plan).Forward)
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DCT4Plan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DCT4Plan).Inverse)
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DHTPlan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DHTPlan).Forward)
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *DHTPlan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*DHTPlan).Inverse)
+}
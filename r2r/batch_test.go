@@ -0,0 +1,160 @@
+package r2r
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type batchPlan interface {
+	r2rPlan
+	ForwardMany(dst, src []float64, count int) error
+	InverseMany(dst, src []float64, count int) error
+}
+
+func TestForwardMany_MatchesSingleVectors(t *testing.T) {
+	constructors := []struct {
+		name string
+		plan func(n int, opts ...Option) (batchPlan, error)
+	}{
+		{"DCT-I", func(n int, opts ...Option) (batchPlan, error) { return NewDCTPlan(n, opts...) }},
+		{"DCT-II", func(n int, opts ...Option) (batchPlan, error) { return NewDCT2Plan(n, opts...) }},
+		{"DCT-III", func(n int, opts ...Option) (batchPlan, error) { return NewDCT3Plan(n, opts...) }},
+		{"DCT-IV", func(n int, opts ...Option) (batchPlan, error) { return NewDCT4Plan(n, opts...) }},
+		{"DST-I", func(n int, opts ...Option) (batchPlan, error) { return NewDSTPlan(n, opts...) }},
+		{"DST-II", func(n int, opts ...Option) (batchPlan, error) { return NewDST2Plan(n, opts...) }},
+		{"DST-III", func(n int, opts ...Option) (batchPlan, error) { return NewDST3Plan(n, opts...) }},
+		{"DST-IV", func(n int, opts ...Option) (batchPlan, error) { return NewDST4Plan(n, opts...) }},
+		{"DHT", func(n int, opts ...Option) (batchPlan, error) { return NewDHTPlan(n, opts...) }},
+		{"Chebyshev", func(n int, opts ...Option) (batchPlan, error) { return NewChebyshevPlan(n, opts...) }},
+	}
+
+	const n, count = 12, 7
+
+	for _, c := range constructors {
+		for _, workers := range []int{1, 3} {
+			batched, err := c.plan(n, WithWorkers(workers), WithNormalization(NormOrtho))
+			if err != nil {
+				t.Fatalf("%s: creating plan failed: %v", c.name, err)
+			}
+
+			single, err := c.plan(n, WithNormalization(NormOrtho))
+			if err != nil {
+				t.Fatalf("%s: creating plan failed: %v", c.name, err)
+			}
+
+			src := make([]float64, n*count)
+			for i := range src {
+				src[i] = math.Cos(float64(i) * 0.7)
+			}
+
+			want := make([]float64, n*count)
+			for v := range count {
+				if err := single.Forward(want[v*n:(v+1)*n], src[v*n:(v+1)*n]); err != nil {
+					t.Fatalf("%s: Forward failed: %v", c.name, err)
+				}
+			}
+
+			got := append([]float64(nil), src...)
+			if err := batched.ForwardMany(got, got, count); err != nil {
+				t.Fatalf("%s: ForwardMany failed: %v", c.name, err)
+			}
+
+			for i := range got {
+				if got[i] != want[i] {
+					t.Fatalf("%s, %d workers: ForwardMany[%d] = %v, want %v", c.name, workers, i, got[i], want[i])
+				}
+			}
+
+			if err := batched.InverseMany(got, got, count); err != nil {
+				t.Fatalf("%s: InverseMany failed: %v", c.name, err)
+			}
+
+			for i := range got {
+				if math.Abs(got[i]-src[i]) > tolerance {
+					t.Fatalf("%s, %d workers: round trip [%d] = %v, want %v", c.name, workers, i, got[i], src[i])
+				}
+			}
+		}
+	}
+}
+
+func TestForwardMany_Errors(t *testing.T) {
+	plan, err := NewDSTPlan(4, WithWorkers(2))
+	if err != nil {
+		t.Fatalf("NewDSTPlan failed: %v", err)
+	}
+
+	buf := make([]float64, 12)
+	if err := plan.ForwardMany(buf, buf, 4); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("ForwardMany with short buffers: got %v, want ErrSizeMismatch", err)
+	}
+
+	if err := plan.InverseMany(buf[:8], buf, 3); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("InverseMany with mismatched dst: got %v, want ErrSizeMismatch", err)
+	}
+
+	if err := plan.ForwardMany(nil, nil, 0); err != nil {
+		t.Errorf("ForwardMany of an empty batch failed: %v", err)
+	}
+}
+
+func TestForwardMany_WorkersShareTwiddles(t *testing.T) {
+	one, err := NewDCTPlan(33)
+	if err != nil {
+		t.Fatalf("NewDCTPlan failed: %v", err)
+	}
+
+	four, err := NewDCTPlan(33, WithWorkers(4))
+	if err != nil {
+		t.Fatalf("NewDCTPlan failed: %v", err)
+	}
+
+	// Each extra worker adds its FFT buffers but not the sin/cos tables.
+	tables := len(one.sin)*8 + len(one.cos)*8
+	if got, want := four.Bytes(), 4*one.Bytes()-3*tables; got != want {
+		t.Errorf("Bytes with 4 workers = %d, want %d", got, want)
+	}
+
+	if &four.batch[1].sin[0] != &four.sin[0] {
+		t.Error("worker plan does not share the twiddle table")
+	}
+}
+
+func TestForwardMany_SingleWorkerNoAlloc(t *testing.T) {
+	plan, err := NewDCT2Plan(16)
+	if err != nil {
+		t.Fatalf("NewDCT2Plan failed: %v", err)
+	}
+
+	buf := make([]float64, 16*8)
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = plan.ForwardMany(buf, buf, 8)
+	})
+	if allocs != 0 {
+		t.Errorf("ForwardMany allocated %v times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkDSTPlan_ForwardMany(b *testing.B) {
+	const n, count = 255, 256
+
+	for _, workers := range []int{1, 4} {
+		b.Run("workers-"+itoa(workers), func(b *testing.B) {
+			plan, err := NewDSTPlan(n, WithWorkers(workers))
+			if err != nil {
+				b.Fatalf("NewDSTPlan failed: %v", err)
+			}
+
+			buf := make([]float64, n*count)
+			for i := range buf {
+				buf[i] = float64(i % n)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				_ = plan.ForwardMany(buf, buf, count)
+			}
+		})
+	}
+}
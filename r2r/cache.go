@@ -0,0 +1,58 @@
+package r2r
+
+import "sync"
+
+// planKey identifies the cached plans: the transform, its size and the
+// options it was created with.
+type planKey struct {
+	kind string
+	n    int
+	opts Options
+}
+
+// planCache maps planKey to the prototype plan of that key. Prototypes are
+// never transformed with, only cloned, so their tables are read-only.
+var planCache sync.Map
+
+// cachedPlan returns a Clone of the cached prototype for (kind, n, opts),
+// building it with build on the first request, if opts enable the plan
+// cache. ok reports whether the cache was enabled; otherwise the caller
+// builds the plan itself.
+func cachedPlan[P interface{ Clone() (P, error) }](
+	kind string,
+	n int,
+	opts []Option,
+	build func(n int, opts ...Option) (P, error),
+) (plan P, ok bool, err error) {
+	options := applyOptions(opts)
+	if !options.Cache {
+		return plan, false, nil
+	}
+
+	key := planKey{kind: kind, n: n, opts: options}
+
+	cached, found := planCache.Load(key)
+	if !found {
+		prototype, err := build(n, append(opts[:len(opts):len(opts)], withoutPlanCache)...)
+		if err != nil {
+			return plan, true, err
+		}
+
+		cached, _ = planCache.LoadOrStore(key, prototype)
+	}
+
+	plan, err = cached.(P).Clone()
+
+	return plan, true, err
+}
+
+// withoutPlanCache builds a plan directly, for the cache's own prototypes.
+func withoutPlanCache(o *Options) {
+	o.Cache = false
+}
+
+// ClearPlanCache drops all plans cached by WithPlanCache. Plans already
+// returned keep working; later requests build new prototypes.
+func ClearPlanCache() {
+	planCache.Clear()
+}
@@ -0,0 +1,139 @@
+package r2r
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithPlanCache_SharesTables(t *testing.T) {
+	t.Cleanup(ClearPlanCache)
+	ClearPlanCache()
+
+	first, err := NewDCTPlan(40, WithPlanCache())
+	if err != nil {
+		t.Fatalf("NewDCTPlan failed: %v", err)
+	}
+
+	second, err := NewDCTPlan(40, WithPlanCache())
+	if err != nil {
+		t.Fatalf("NewDCTPlan failed: %v", err)
+	}
+
+	if &first.sin[0] != &second.sin[0] {
+		t.Error("cached plans do not share the twiddle table")
+	}
+
+	if &first.fft.in[0] == &second.fft.in[0] {
+		t.Error("cached plans share their scratch buffers")
+	}
+
+	uncached, err := NewDCTPlan(40)
+	if err != nil {
+		t.Fatalf("NewDCTPlan failed: %v", err)
+	}
+
+	if &uncached.sin[0] == &first.sin[0] {
+		t.Error("a plan without WithPlanCache uses the cache")
+	}
+
+	// The options are part of the key.
+	ortho, err := NewDCTPlan(40, WithPlanCache(), WithNormalization(NormOrtho))
+	if err != nil {
+		t.Fatalf("NewDCTPlan failed: %v", err)
+	}
+
+	if ortho.opts.Normalization != NormOrtho || ortho.NormalizationFactor() != 1 {
+		t.Errorf("cached plan has options %+v, want NormOrtho", ortho.opts)
+	}
+
+	ClearPlanCache()
+
+	third, err := NewDCTPlan(40, WithPlanCache())
+	if err != nil {
+		t.Fatalf("NewDCTPlan failed: %v", err)
+	}
+
+	if &third.sin[0] == &first.sin[0] {
+		t.Error("ClearPlanCache did not drop the cached plan")
+	}
+}
+
+func TestWithPlanCache_AllTransforms(t *testing.T) {
+	t.Cleanup(ClearPlanCache)
+
+	constructors := []struct {
+		name string
+		plan func(n int, opts ...Option) (r2rPlan, error)
+	}{
+		{"DCT-I", func(n int, opts ...Option) (r2rPlan, error) { return NewDCTPlan(n, opts...) }},
+		{"DCT-II", func(n int, opts ...Option) (r2rPlan, error) { return NewDCT2Plan(n, opts...) }},
+		{"DCT-III", func(n int, opts ...Option) (r2rPlan, error) { return NewDCT3Plan(n, opts...) }},
+		{"DCT-IV", func(n int, opts ...Option) (r2rPlan, error) { return NewDCT4Plan(n, opts...) }},
+		{"DST-I", func(n int, opts ...Option) (r2rPlan, error) { return NewDSTPlan(n, opts...) }},
+		{"DST-II", func(n int, opts ...Option) (r2rPlan, error) { return NewDST2Plan(n, opts...) }},
+		{"DST-III", func(n int, opts ...Option) (r2rPlan, error) { return NewDST3Plan(n, opts...) }},
+		{"DST-IV", func(n int, opts ...Option) (r2rPlan, error) { return NewDST4Plan(n, opts...) }},
+		{"DHT", func(n int, opts ...Option) (r2rPlan, error) { return NewDHTPlan(n, opts...) }},
+		{"Chebyshev", func(n int, opts ...Option) (r2rPlan, error) { return NewChebyshevPlan(n, opts...) }},
+	}
+
+	const n = 10
+
+	for _, c := range constructors {
+		want := make([]float64, n)
+		src := make([]float64, n)
+		for i := range src {
+			src[i] = float64(i*i%7) - 3
+		}
+
+		plan, err := c.plan(n)
+		if err != nil {
+			t.Fatalf("%s: creating plan failed: %v", c.name, err)
+		}
+
+		if err := plan.Forward(want, src); err != nil {
+			t.Fatalf("%s: Forward failed: %v", c.name, err)
+		}
+
+		for range 2 {
+			cached, err := c.plan(n, WithPlanCache())
+			if err != nil {
+				t.Fatalf("%s: creating cached plan failed: %v", c.name, err)
+			}
+
+			got := make([]float64, n)
+			if err := cached.Forward(got, src); err != nil {
+				t.Fatalf("%s: Forward failed: %v", c.name, err)
+			}
+
+			for k := range n {
+				if got[k] != want[k] {
+					t.Fatalf("%s: cached X[%d] = %v, want %v", c.name, k, got[k], want[k])
+				}
+			}
+		}
+	}
+
+	if _, err := NewDSTPlan(0, WithPlanCache()); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewDSTPlan(0) with the cache: got %v, want ErrInvalidSize", err)
+	}
+}
+
+func BenchmarkNewDSTPlan_Cache(b *testing.B) {
+	b.Cleanup(ClearPlanCache)
+
+	for _, cache := range []bool{false, true} {
+		name := "uncached"
+		var opts []Option
+		if cache {
+			name = "cached"
+			opts = append(opts, WithPlanCache())
+		}
+
+		b.Run(name, func(b *testing.B) {
+			for range b.N {
+				_, _ = NewDSTPlan(1023, opts...)
+			}
+		})
+	}
+}
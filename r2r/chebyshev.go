@@ -0,0 +1,178 @@
+package r2r
+
+import "math"
+
+// ChebyshevPlan is a pre-computed fast Chebyshev transform plan.
+//
+// It maps the values f[j] = f(x[j]) of a function at the N Chebyshev
+// (Gauss-Lobatto) points x[j] = cos(πj/(N-1)), j = 0..N-1 (from 1 down to
+// -1, see ChebyshevPoints), to the coefficients of its interpolant
+//
+//	f(x) = Σ a[k] * T_k(x) for k = 0..N-1,
+//
+// and back. Since T_k(x[j]) = cos(πjk/(N-1)), both directions are a DCT-I
+// with endpoint scaling:
+//
+//	a[k] = (c[k] / (N-1)) * Σ w[j] f[j] * cos(πjk/(N-1))
+//
+// where w[j] and c[j] are 1/2 for j = 0, N-1 and 1 otherwise. A polynomial of degree below N is
+// transformed exactly.
+//
+// The coefficients are always those of the T_k, so the Normalization
+// option has no effect; WithWorkers applies to ForwardMany and InverseMany.
+//
+// Thread safety: A single ChebyshevPlan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
+type ChebyshevPlan struct {
+	n    int
+	opts Options
+	dct  *DCTPlan // unscaled DCT-I
+
+	// Per-worker plans of ForwardMany and InverseMany, starting with this one
+	batch []*ChebyshevPlan
+}
+
+// NewChebyshevPlan creates a new Chebyshev transform plan for n points.
+// The size n must be at least 2.
+func NewChebyshevPlan(n int, opts ...Option) (*ChebyshevPlan, error) {
+	if plan, ok, err := cachedPlan("Chebyshev", n, opts, NewChebyshevPlan); ok {
+		return plan, err
+	}
+
+	dct, err := NewDCTPlan(n, WithNormalization(NormUnscaled))
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ChebyshevPlan{
+		n:    n,
+		opts: applyOptions(opts),
+		dct:  dct,
+	}
+
+	plan.batch, err = batchPlans(plan, plan.opts.Workers, plan.cloneBuffers)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// Len returns the number of points.
+func (p *ChebyshevPlan) Len() int {
+	return p.n
+}
+
+// Forward computes the Chebyshev coefficients dst of the values src at the
+// Chebyshev points.
+// dst and src must have length n. They may be the same slice for in-place operation.
+func (p *ChebyshevPlan) Forward(dst, src []float64) error {
+	if len(dst) != p.n || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	if err := p.dct.Forward(dst, src); err != nil {
+		return err
+	}
+
+	// DCTPlan weights the endpoints 1 and the interior 2, i.e. 2w[j].
+	scale := 1.0 / float64(p.n-1)
+	for k := range dst {
+		dst[k] *= scale
+	}
+
+	dst[0] *= 0.5
+	dst[p.n-1] *= 0.5
+
+	return nil
+}
+
+// Inverse computes the values dst at the Chebyshev points of the series
+// with coefficients src.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// With DCTPlan's weights, f[j] = (DCT-I(a)[j] + a[0] + (-1)^j a[N-1]) / 2.
+func (p *ChebyshevPlan) Inverse(dst, src []float64) error {
+	if len(dst) != p.n || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	first, last := src[0], src[p.n-1]
+
+	if err := p.dct.Forward(dst, src); err != nil {
+		return err
+	}
+
+	for j := range dst {
+		edge := first + last
+		if j%2 == 1 {
+			edge = first - last
+		}
+
+		dst[j] = 0.5 * (dst[j] + edge)
+	}
+
+	return nil
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *ChebyshevPlan) Clone() (*ChebyshevPlan, error) {
+	return clonePlan(p, p.opts.Workers, (*ChebyshevPlan).cloneBuffers, func(c *ChebyshevPlan, batch []*ChebyshevPlan) {
+		c.batch = batch
+	})
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *ChebyshevPlan) cloneBuffers() (*ChebyshevPlan, error) {
+	dct, err := p.dct.cloneBuffers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChebyshevPlan{n: p.n, opts: p.opts, dct: dct}, nil
+}
+
+// ForwardMany applies Forward to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *ChebyshevPlan) ForwardMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*ChebyshevPlan).Forward)
+}
+
+// InverseMany applies Inverse to count vectors stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *ChebyshevPlan) InverseMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*ChebyshevPlan).Inverse)
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *ChebyshevPlan) Bytes() int {
+	return p.dct.Bytes() + batchBytes(p.batch, func(q *ChebyshevPlan) int { return q.dct.fft.bytes() })
+}
+
+// ChebyshevPoints returns the n Chebyshev (Gauss-Lobatto) points
+// cos(πj/(n-1)), j = 0..n-1, from 1 down to -1, at which ChebyshevPlan
+// samples. The values are exactly symmetric about 0, with the middle point
+// of odd n exactly 0.
+func ChebyshevPoints(n int) []float64 {
+	points := make([]float64, n)
+	if n == 1 {
+		return points
+	}
+
+	// sin(π(n-1-2j)/(2(n-1))) equals cos(πj/(n-1)) and is odd in j.
+	for j := range points {
+		points[j] = math.Sin(math.Pi * float64(n-1-2*j) / float64(2*(n-1)))
+	}
+
+	return points
+}
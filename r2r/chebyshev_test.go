@@ -0,0 +1,123 @@
+package r2r
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// chebyshevSeries evaluates Σ a[k] T_k(x) with the three-term recurrence.
+func chebyshevSeries(a []float64, x float64) float64 {
+	prev, cur := 1.0, x
+	sum := a[0]
+	for k := 1; k < len(a); k++ {
+		sum += a[k] * cur
+		prev, cur = cur, 2*x*cur-prev
+	}
+
+	return sum
+}
+
+func TestChebyshevPlan_PolynomialsExact(t *testing.T) {
+	for _, n := range []int{2, 3, 8, 17} {
+		plan, err := NewChebyshevPlan(n)
+		if err != nil {
+			t.Fatalf("NewChebyshevPlan(%d) failed: %v", n, err)
+		}
+
+		// A polynomial of degree n-1, sampled at the Chebyshev points.
+		want := make([]float64, n)
+		for k := range want {
+			want[k] = math.Cos(float64(k)*0.7) / float64(k+1)
+		}
+
+		points := ChebyshevPoints(n)
+		values := make([]float64, n)
+		for j, x := range points {
+			values[j] = chebyshevSeries(want, x)
+		}
+
+		got := append([]float64(nil), values...)
+		if err := plan.Forward(got, got); err != nil {
+			t.Fatalf("Forward failed: %v", err)
+		}
+
+		for k := range n {
+			if math.Abs(got[k]-want[k]) > tolerance*float64(n) {
+				t.Errorf("n=%d: a[%d] = %v, want %v", n, k, got[k], want[k])
+			}
+		}
+
+		if err := plan.Inverse(got, got); err != nil {
+			t.Fatalf("Inverse failed: %v", err)
+		}
+
+		for j := range n {
+			if math.Abs(got[j]-values[j]) > tolerance*float64(n) {
+				t.Errorf("n=%d: f[%d] = %v, want %v", n, j, got[j], values[j])
+			}
+		}
+	}
+}
+
+// The coefficients of a smooth function decay spectrally, and the
+// interpolant agrees with the function between the points.
+func TestChebyshevPlan_SmoothFunctionConverges(t *testing.T) {
+	const n = 33
+
+	f := func(x float64) float64 { return math.Exp(x) * math.Sin(2*x) }
+
+	plan, err := NewChebyshevPlan(n)
+	if err != nil {
+		t.Fatalf("NewChebyshevPlan failed: %v", err)
+	}
+
+	coeffs := make([]float64, n)
+	for j, x := range ChebyshevPoints(n) {
+		coeffs[j] = f(x)
+	}
+
+	if err := plan.Forward(coeffs, coeffs); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+
+	if tail := math.Abs(coeffs[n-1]); tail > 1e-14 {
+		t.Errorf("last coefficient %v has not decayed", tail)
+	}
+
+	for _, x := range []float64{-0.93, -0.4, 0.1, 0.77} {
+		if got, want := chebyshevSeries(coeffs, x), f(x); math.Abs(got-want) > 1e-13 {
+			t.Errorf("interpolant at %v = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestChebyshevPoints(t *testing.T) {
+	points := ChebyshevPoints(5)
+	want := []float64{1, math.Sqrt2 / 2, 0, -math.Sqrt2 / 2, -1}
+
+	for j := range want {
+		if math.Abs(points[j]-want[j]) > tolerance {
+			t.Errorf("x[%d] = %v, want %v", j, points[j], want[j])
+		}
+	}
+
+	if points[2] != 0 || points[1] != -points[3] {
+		t.Errorf("points %v are not exactly symmetric", points)
+	}
+}
+
+func TestChebyshevPlan_Errors(t *testing.T) {
+	if _, err := NewChebyshevPlan(1); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewChebyshevPlan(1): got %v, want ErrInvalidSize", err)
+	}
+
+	plan, err := NewChebyshevPlan(4)
+	if err != nil {
+		t.Fatalf("NewChebyshevPlan failed: %v", err)
+	}
+
+	if err := plan.Inverse(make([]float64, 4), make([]float64, 3)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Inverse with a short src: got %v, want ErrSizeMismatch", err)
+	}
+}
@@ -0,0 +1,197 @@
+package r2r
+
+import (
+	"fmt"
+
+	algofft "github.com/MeKo-Christian/algo-fft"
+)
+
+// Clone returns a plan for the same transform and options with its own FFT
+// buffers and per-worker plans, sharing the read-only twiddle tables of p.
+// A plan is not safe for concurrent use, but p and its clones are
+// independent of each other: give each goroutine a Clone instead of
+// building or pooling plans.
+func (p *DSTPlan) Clone() (*DSTPlan, error) {
+	return clonePlan(p, p.opts.Workers, (*DSTPlan).cloneBuffers, func(c *DSTPlan, batch []*DSTPlan) {
+		c.batch = batch
+	})
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DST2Plan) Clone() (*DST2Plan, error) {
+	return clonePlan(p, p.opts.Workers, (*DST2Plan).cloneBuffers, func(c *DST2Plan, batch []*DST2Plan) {
+		c.batch = batch
+	})
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DST3Plan) Clone() (*DST3Plan, error) {
+	dst2, err := p.dst2.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DST3Plan{dst2: dst2}, nil
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DST4Plan) Clone() (*DST4Plan, error) {
+	dct4, err := p.dct4.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DST4Plan{dct4: dct4}, nil
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DCTPlan) Clone() (*DCTPlan, error) {
+	return clonePlan(p, p.opts.Workers, (*DCTPlan).cloneBuffers, func(c *DCTPlan, batch []*DCTPlan) {
+		c.batch = batch
+	})
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DCT2Plan) Clone() (*DCT2Plan, error) {
+	return clonePlan(p, p.opts.Workers, (*DCT2Plan).cloneBuffers, func(c *DCT2Plan, batch []*DCT2Plan) {
+		c.batch = batch
+	})
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DCT3Plan) Clone() (*DCT3Plan, error) {
+	dct2, err := p.dct2.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DCT3Plan{dct2: dct2}, nil
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DCT4Plan) Clone() (*DCT4Plan, error) {
+	return clonePlan(p, p.opts.Workers, (*DCT4Plan).cloneBuffers, func(c *DCT4Plan, batch []*DCT4Plan) {
+		c.batch = batch
+	})
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *DHTPlan) Clone() (*DHTPlan, error) {
+	return clonePlan(p, p.opts.Workers, (*DHTPlan).cloneBuffers, func(c *DHTPlan, batch []*DHTPlan) {
+		c.batch = batch
+	})
+}
+
+// Clone returns an independent plan sharing the window and the DCT-IV
+// twiddle tables of p; see DSTPlan.Clone.
+func (p *MDCTPlan) Clone() (*MDCTPlan, error) {
+	dct4, err := p.dct4.cloneBuffers()
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *p
+	clone.dct4 = dct4
+	clone.fold = make([]float64, p.n)
+
+	return &clone, nil
+}
+
+// clonePlan returns a copy of p with its own buffers, made by cloneBuffers,
+// and workers per-worker plans stored with setBatch.
+func clonePlan[P any](p P, workers int, cloneBuffers func(P) (P, error), setBatch func(P, []P)) (P, error) {
+	c, err := cloneBuffers(p)
+	if err != nil {
+		return c, err
+	}
+
+	batch, err := batchPlans(c, workers, func() (P, error) { return cloneBuffers(c) })
+	if err != nil {
+		return c, err
+	}
+	setBatch(c, batch)
+
+	return c, nil
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *DSTPlan) cloneBuffers() (*DSTPlan, error) {
+	fft, err := newRealFFT(p.n + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DSTPlan{n: p.n, opts: p.opts, fft: fft, sin: p.sin}, nil
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *DST2Plan) cloneBuffers() (*DST2Plan, error) {
+	fft, err := p.fft.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DST2Plan{n: p.n, opts: p.opts, fft: fft}, nil
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *DCTPlan) cloneBuffers() (*DCTPlan, error) {
+	fft, err := newRealFFT(p.n - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DCTPlan{n: p.n, opts: p.opts, fft: fft, sin: p.sin, cos: p.cos}, nil
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *DCT2Plan) cloneBuffers() (*DCT2Plan, error) {
+	fft, err := p.fft.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DCT2Plan{n: p.n, opts: p.opts, fft: fft}, nil
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *DCT4Plan) cloneBuffers() (*DCT4Plan, error) {
+	fftN := len(p.fftIn)
+
+	fftPlan, err := algofft.NewPlan64(fftN)
+	if err != nil {
+		return nil, fmt.Errorf("creating FFT plan: %w", err)
+	}
+
+	clone := *p
+	clone.fftPlan = fftPlan
+	clone.fftIn = make([]complex128, fftN)
+	clone.fftOut = make([]complex128, fftN)
+	clone.batch = nil
+
+	return &clone, nil
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *DHTPlan) cloneBuffers() (*DHTPlan, error) {
+	fft, err := newRealFFT(p.n)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DHTPlan{n: p.n, opts: p.opts, fft: fft}, nil
+}
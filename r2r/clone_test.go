@@ -0,0 +1,184 @@
+package r2r
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestClone_ConcurrentUse(t *testing.T) {
+	plans := []struct {
+		name  string
+		plan  func(n int) (r2rPlan, error)
+		clone func(p r2rPlan) (r2rPlan, error)
+	}{
+		{
+			"DCT-I",
+			func(n int) (r2rPlan, error) { return NewDCTPlan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DCTPlan).Clone() },
+		},
+		{
+			"DCT-II",
+			func(n int) (r2rPlan, error) { return NewDCT2Plan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DCT2Plan).Clone() },
+		},
+		{
+			"DCT-III",
+			func(n int) (r2rPlan, error) { return NewDCT3Plan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DCT3Plan).Clone() },
+		},
+		{
+			"DCT-IV",
+			func(n int) (r2rPlan, error) { return NewDCT4Plan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DCT4Plan).Clone() },
+		},
+		{
+			"DST-I",
+			func(n int) (r2rPlan, error) { return NewDSTPlan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DSTPlan).Clone() },
+		},
+		{
+			"DST-II",
+			func(n int) (r2rPlan, error) { return NewDST2Plan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DST2Plan).Clone() },
+		},
+		{
+			"DST-III",
+			func(n int) (r2rPlan, error) { return NewDST3Plan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DST3Plan).Clone() },
+		},
+		{
+			"DST-IV",
+			func(n int) (r2rPlan, error) { return NewDST4Plan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DST4Plan).Clone() },
+		},
+		{
+			"DHT",
+			func(n int) (r2rPlan, error) { return NewDHTPlan(n) },
+			func(p r2rPlan) (r2rPlan, error) { return p.(*DHTPlan).Clone() },
+		},
+	}
+
+	const n, goroutines = 20, 4
+
+	for _, tc := range plans {
+		plan, err := tc.plan(n)
+		if err != nil {
+			t.Fatalf("%s: creating plan failed: %v", tc.name, err)
+		}
+
+		inputs := make([][]float64, goroutines)
+		wants := make([][]float64, goroutines)
+		for g := range goroutines {
+			inputs[g] = make([]float64, n)
+			for i := range n {
+				inputs[g][i] = math.Sin(float64((g+1)*i) * 0.3)
+			}
+
+			wants[g] = make([]float64, n)
+			if err := plan.Forward(wants[g], inputs[g]); err != nil {
+				t.Fatalf("%s: Forward failed: %v", tc.name, err)
+			}
+		}
+
+		clones := make([]r2rPlan, goroutines)
+		for g := range clones {
+			clones[g], err = tc.clone(plan)
+			if err != nil {
+				t.Fatalf("%s: Clone failed: %v", tc.name, err)
+			}
+		}
+
+		gots := make([][]float64, goroutines)
+
+		var wg sync.WaitGroup
+		for g := range goroutines {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				gots[g] = make([]float64, n)
+				for range 50 {
+					if err := clones[g].Forward(gots[g], inputs[g]); err != nil {
+						t.Errorf("%s: Forward on clone failed: %v", tc.name, err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		for g := range goroutines {
+			for i := range n {
+				if gots[g][i] != wants[g][i] {
+					t.Fatalf("%s: goroutine %d: X[%d] = %v, want %v", tc.name, g, i, gots[g][i], wants[g][i])
+				}
+			}
+		}
+	}
+}
+
+func TestClone_SharesTwiddlesAndKeepsOptions(t *testing.T) {
+	plan, err := NewDSTPlan(15, WithNormalization(NormOrtho), WithWorkers(3))
+	if err != nil {
+		t.Fatalf("NewDSTPlan failed: %v", err)
+	}
+
+	clone, err := plan.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+
+	if &clone.sin[0] != &plan.sin[0] {
+		t.Error("clone does not share the twiddle table")
+	}
+
+	if clone.fft == plan.fft || &clone.fft.in[0] == &plan.fft.in[0] {
+		t.Error("clone shares the FFT buffers of the plan")
+	}
+
+	if clone.opts != plan.opts || len(clone.batch) != 3 || clone.batch[0] != clone {
+		t.Errorf("clone options %+v with %d worker plans, want %+v with 3", clone.opts, len(clone.batch), plan.opts)
+	}
+
+	if clone.Bytes() != plan.Bytes() {
+		t.Errorf("clone Bytes = %d, want %d", clone.Bytes(), plan.Bytes())
+	}
+}
+
+func TestMDCTPlan_Clone(t *testing.T) {
+	plan, err := NewMDCTPlan(8, SineWindow(8))
+	if err != nil {
+		t.Fatalf("NewMDCTPlan failed: %v", err)
+	}
+
+	clone, err := plan.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+
+	if &clone.fold[0] == &plan.fold[0] || clone.dct4 == plan.dct4 {
+		t.Error("clone shares the buffers of the plan")
+	}
+
+	src := make([]float64, 16)
+	for i := range src {
+		src[i] = float64(i % 5)
+	}
+
+	want := make([]float64, 8)
+	got := make([]float64, 8)
+	if err := plan.Forward(want, src); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+
+	if err := clone.Forward(got, src); err != nil {
+		t.Fatalf("Forward on clone failed: %v", err)
+	}
+
+	for k := range got {
+		if got[k] != want[k] {
+			t.Errorf("clone X[%d] = %v, want %v", k, got[k], want[k])
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package r2r
+
+import "math"
+
+// SymmetricConvolutionPlan convolves signals of N samples with a symmetric
+// kernel under reflective boundaries.
+//
+// The signal is extended by half-sample symmetry about both ends,
+// x[-1-j] = x[j] and x[N+j] = x[N-1-j], and convolved with the kernel
+// h[-m] = h[m]:
+//
+//	y[j] = Σ h[m] * x[j-m] for all m
+//
+// Every DCT-II basis function is an eigenvector of this operation, with
+// eigenvalue
+//
+//	H[k] = h[0] + 2 Σ h[m] * cos(πkm/N) for m >= 1,
+//
+// so Apply is a DCT-II, a multiplication by H and the inverse DCT-II, at the
+// cost of two N-point real FFTs for any kernel length. Kernels longer than
+// the signal wrap around the reflections, as the extension is periodic with
+// period 2N. A kernel summing to 1 (h[0] + 2 Σ h[m] = 1) preserves constants.
+//
+// The Normalization option has no effect; WithWorkers applies to ApplyMany.
+//
+// Thread safety: A single SymmetricConvolutionPlan instance is NOT safe for
+// concurrent use. For parallel convolutions, Clone the plan for each goroutine.
+type SymmetricConvolutionPlan struct {
+	n        int
+	opts     Options
+	dct2     *DCT2Plan // NormBackward DCT-II
+	response []float64 // eigenvalue H[k] of each DCT-II mode
+
+	// Per-worker plans of ApplyMany, starting with this one
+	batch []*SymmetricConvolutionPlan
+}
+
+// NewSymmetricConvolutionPlan creates a plan convolving n samples with the
+// symmetric kernel given by its center tap and one side, kernel[m] = h[±m].
+// The size n and the length of kernel must be at least 1.
+func NewSymmetricConvolutionPlan(n int, kernel []float64, opts ...Option) (*SymmetricConvolutionPlan, error) {
+	if len(kernel) == 0 {
+		return nil, ErrInvalidSize
+	}
+
+	dct2, err := NewDCT2Plan(n)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]float64, n)
+	for k := range response {
+		sum := kernel[0]
+		for m := 1; m < len(kernel); m++ {
+			sum += 2 * kernel[m] * math.Cos(math.Pi*float64(k*m)/float64(n))
+		}
+		response[k] = sum
+	}
+
+	plan := &SymmetricConvolutionPlan{
+		n:        n,
+		opts:     applyOptions(opts),
+		dct2:     dct2,
+		response: response,
+	}
+
+	plan.batch, err = batchPlans(plan, plan.opts.Workers, plan.cloneBuffers)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// Len returns the number of samples.
+func (p *SymmetricConvolutionPlan) Len() int {
+	return p.n
+}
+
+// Response returns the eigenvalues H[k] of the convolution, one per DCT-II
+// mode. The slice is shared with the plan and must not be modified.
+func (p *SymmetricConvolutionPlan) Response() []float64 {
+	return p.response
+}
+
+// Apply convolves src with the plan's kernel into dst.
+// dst and src must have length n. They may be the same slice for in-place operation.
+func (p *SymmetricConvolutionPlan) Apply(dst, src []float64) error {
+	if len(dst) != p.n || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	if err := p.dct2.Forward(dst, src); err != nil {
+		return err
+	}
+
+	for k, h := range p.response {
+		dst[k] *= h
+	}
+
+	return p.dct2.Inverse(dst, dst)
+}
+
+// ApplyMany applies Apply to count signals stored back to back; see
+// DSTPlan.ForwardMany.
+func (p *SymmetricConvolutionPlan) ApplyMany(dst, src []float64, count int) error {
+	return runBatch(p.batch, dst, src, p.n, count, (*SymmetricConvolutionPlan).Apply)
+}
+
+// Clone returns an independent plan sharing the twiddle tables and the
+// response of p; see DSTPlan.Clone.
+func (p *SymmetricConvolutionPlan) Clone() (*SymmetricConvolutionPlan, error) {
+	return clonePlan(p, p.opts.Workers, (*SymmetricConvolutionPlan).cloneBuffers,
+		func(c *SymmetricConvolutionPlan, batch []*SymmetricConvolutionPlan) {
+			c.batch = batch
+		})
+}
+
+// cloneBuffers returns a copy of p with its own FFT buffers and without
+// per-worker plans.
+func (p *SymmetricConvolutionPlan) cloneBuffers() (*SymmetricConvolutionPlan, error) {
+	dct2, err := p.dct2.cloneBuffers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SymmetricConvolutionPlan{n: p.n, opts: p.opts, dct2: dct2, response: p.response}, nil
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *SymmetricConvolutionPlan) Bytes() int {
+	return p.dct2.Bytes() + len(p.response)*8 +
+		batchBytes(p.batch, func(q *SymmetricConvolutionPlan) int { return q.dct2.fft.bytes() })
+}
+
+// SymmetricConvolve computes a one-shot symmetric convolution of src with
+// kernel; see SymmetricConvolutionPlan.
+func SymmetricConvolve(dst, src, kernel []float64) error {
+	plan, err := NewSymmetricConvolutionPlan(len(src), kernel)
+	if err != nil {
+		return err
+	}
+
+	return plan.Apply(dst, src)
+}
+
+// ResamplePlan resamples signals from N to M cell-centered samples by
+// DCT-II interpolation.
+//
+// The N samples x[j] = f((j+1/2)/N) of a function on [0, 1] define the
+// cosine interpolant
+//
+//	f(t) = (2/N) * (X[0]/2 + Σ X[k] * cos(πkt)) for k = 1..N-1,
+//
+// with X the DCT-II of x. Apply evaluates it at the M cell centers
+// (i+1/2)/M, keeping the first min(N, M) modes: upsampling zero-pads the
+// coefficients, downsampling truncates them, an ideal low-pass filter. The
+// implied boundary extension is reflective, so unlike Fourier resampling
+// there is no ringing from a jump between the two ends. Cosines cos(πkt)
+// with k < min(N, M) are resampled exactly.
+//
+// The Normalization option has no effect.
+//
+// Thread safety: A single ResamplePlan instance is NOT safe for concurrent
+// use. For parallel resampling, Clone the plan for each goroutine.
+type ResamplePlan struct {
+	n, m   int
+	opts   Options
+	from   *DCT2Plan // N-point DCT-II
+	to     *DCT2Plan // M-point DCT-II, for its inverse
+	coeffs []float64 // N DCT-II coefficients of the input
+}
+
+// NewResamplePlan creates a plan resampling n samples to m samples.
+// Both sizes must be at least 1.
+func NewResamplePlan(n, m int, opts ...Option) (*ResamplePlan, error) {
+	from, err := NewDCT2Plan(n)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := NewDCT2Plan(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResamplePlan{
+		n:      n,
+		m:      m,
+		opts:   applyOptions(opts),
+		from:   from,
+		to:     to,
+		coeffs: make([]float64, n),
+	}, nil
+}
+
+// Len returns the input size N and the output size M.
+func (p *ResamplePlan) Len() (n, m int) {
+	return p.n, p.m
+}
+
+// Apply resamples the n samples src to the m samples dst.
+// dst must have length m and src length n; they must not overlap.
+func (p *ResamplePlan) Apply(dst, src []float64) error {
+	if len(dst) != p.m || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	if err := p.from.Forward(p.coeffs, src); err != nil {
+		return err
+	}
+
+	// The inverse DCT-II of size M scales by 2/M instead of 2/N.
+	scale := float64(p.m) / float64(p.n)
+	kept := copy(dst, p.coeffs)
+	for k := range kept {
+		dst[k] *= scale
+	}
+	clear(dst[kept:])
+
+	return p.to.Inverse(dst, dst)
+}
+
+// Clone returns an independent plan sharing the twiddle tables of p; see
+// DSTPlan.Clone.
+func (p *ResamplePlan) Clone() (*ResamplePlan, error) {
+	from, err := p.from.cloneBuffers()
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := p.to.cloneBuffers()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResamplePlan{
+		n:      p.n,
+		m:      p.m,
+		opts:   p.opts,
+		from:   from,
+		to:     to,
+		coeffs: make([]float64, p.n),
+	}, nil
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *ResamplePlan) Bytes() int {
+	return p.from.Bytes() + p.to.Bytes() + len(p.coeffs)*8
+}
+
+// Resample computes a one-shot DCT-II resampling of src to len(dst)
+// samples; see ResamplePlan.
+func Resample(dst, src []float64) error {
+	plan, err := NewResamplePlan(len(src), len(dst))
+	if err != nil {
+		return err
+	}
+
+	return plan.Apply(dst, src)
+}
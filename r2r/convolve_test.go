@@ -0,0 +1,176 @@
+package r2r
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// reflectIndex maps j onto 0..n-1 by half-sample symmetric extension.
+func reflectIndex(j, n int) int {
+	j %= 2 * n
+	if j < 0 {
+		j += 2 * n
+	}
+
+	if j >= n {
+		j = 2*n - 1 - j
+	}
+
+	return j
+}
+
+// symmetricConvolutionReference convolves x with the symmetric kernel by
+// direct summation over the reflected signal.
+func symmetricConvolutionReference(x, kernel []float64) []float64 {
+	n := len(x)
+	y := make([]float64, n)
+	for j := range y {
+		y[j] = kernel[0] * x[j]
+		for m := 1; m < len(kernel); m++ {
+			y[j] += kernel[m] * (x[reflectIndex(j-m, n)] + x[reflectIndex(j+m, n)])
+		}
+	}
+
+	return y
+}
+
+func TestSymmetricConvolutionPlan_MatchesDirectSum(t *testing.T) {
+	kernels := [][]float64{
+		{1},
+		{0.5, 0.25},
+		{0.4, 0.2, 0.1},
+		{0.3, -0.2, 0.15, 0.05, 0.01, 0.02, -0.03, 0.04, 0.01, 0.1, 0.2, -0.1, 0.05}, // longer than 2N
+	}
+
+	for _, n := range []int{1, 5, 6} {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = math.Sin(float64(i)*0.9) + float64(i%3)
+		}
+
+		for _, kernel := range kernels {
+			plan, err := NewSymmetricConvolutionPlan(n, kernel)
+			if err != nil {
+				t.Fatalf("NewSymmetricConvolutionPlan(%d) failed: %v", n, err)
+			}
+
+			got := append([]float64(nil), x...)
+			if err := plan.Apply(got, got); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			want := symmetricConvolutionReference(x, kernel)
+			for j := range want {
+				if math.Abs(got[j]-want[j]) > tolerance*10 {
+					t.Errorf("n=%d, %d taps: y[%d] = %v, want %v", n, len(kernel), j, got[j], want[j])
+				}
+			}
+		}
+	}
+}
+
+func TestSymmetricConvolutionPlan_ApplyMany(t *testing.T) {
+	const n, count = 7, 5
+
+	kernel := []float64{0.5, 0.25}
+	plan, err := NewSymmetricConvolutionPlan(n, kernel, WithWorkers(2))
+	if err != nil {
+		t.Fatalf("NewSymmetricConvolutionPlan failed: %v", err)
+	}
+
+	src := make([]float64, n*count)
+	for i := range src {
+		src[i] = float64(i % 4)
+	}
+
+	dst := make([]float64, len(src))
+	if err := plan.ApplyMany(dst, src, count); err != nil {
+		t.Fatalf("ApplyMany failed: %v", err)
+	}
+
+	for c := range count {
+		want := symmetricConvolutionReference(src[c*n:(c+1)*n], kernel)
+		for j := range want {
+			if math.Abs(dst[c*n+j]-want[j]) > tolerance {
+				t.Errorf("signal %d: y[%d] = %v, want %v", c, j, dst[c*n+j], want[j])
+			}
+		}
+	}
+}
+
+func TestResamplePlan_CosinesExact(t *testing.T) {
+	sample := func(n, k int) []float64 {
+		x := make([]float64, n)
+		for j := range x {
+			x[j] = math.Cos(math.Pi * float64(k) * (float64(j) + 0.5) / float64(n))
+		}
+
+		return x
+	}
+
+	for _, size := range [][2]int{{8, 8}, {8, 13}, {13, 8}, {1, 4}, {6, 1}} {
+		n, m := size[0], size[1]
+
+		plan, err := NewResamplePlan(n, m)
+		if err != nil {
+			t.Fatalf("NewResamplePlan(%d, %d) failed: %v", n, m, err)
+		}
+
+		for k := range min(n, m) {
+			got := make([]float64, m)
+			if err := plan.Apply(got, sample(n, k)); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			want := sample(m, k)
+			for i := range want {
+				if math.Abs(got[i]-want[i]) > tolerance*10 {
+					t.Errorf("%d -> %d, mode %d: y[%d] = %v, want %v", n, m, k, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+// Downsampling truncates the modes that the coarse grid cannot represent.
+func TestResample_DropsHighModes(t *testing.T) {
+	const n, m = 12, 6
+
+	src := make([]float64, n)
+	for j := range src {
+		x := (float64(j) + 0.5) / n
+		src[j] = 2 + math.Cos(math.Pi*x) + 0.5*math.Cos(9*math.Pi*x)
+	}
+
+	dst := make([]float64, m)
+	if err := Resample(dst, src); err != nil {
+		t.Fatalf("Resample failed: %v", err)
+	}
+
+	for i := range dst {
+		x := (float64(i) + 0.5) / m
+		if want := 2 + math.Cos(math.Pi*x); math.Abs(dst[i]-want) > tolerance*10 {
+			t.Errorf("y[%d] = %v, want %v", i, dst[i], want)
+		}
+	}
+}
+
+func TestConvolutionPlans_Errors(t *testing.T) {
+	if _, err := NewSymmetricConvolutionPlan(4, nil); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("empty kernel: got %v, want ErrInvalidSize", err)
+	}
+
+	if _, err := NewResamplePlan(4, 0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewResamplePlan(4, 0): got %v, want ErrInvalidSize", err)
+	}
+
+	plan, err := NewResamplePlan(4, 6)
+	if err != nil {
+		t.Fatalf("NewResamplePlan failed: %v", err)
+	}
+
+	if err := plan.Apply(make([]float64, 4), make([]float64, 4)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Apply with a short dst: got %v, want ErrSizeMismatch", err)
+	}
+}
@@ -3,6 +3,7 @@ package r2r
 import (
 	"fmt"
 	"math"
+	"math/cmplx"
 
 	algofft "github.com/MeKo-Christian/algo-fft"
 )
@@ -21,22 +22,20 @@ import (
 // Note: DCT-I requires N >= 2.
 //
 // Thread safety: A single DCTPlan instance is NOT safe for concurrent use.
-// For parallel transforms, create separate plan instances per goroutine.
+// For parallel transforms, Clone the plan for each goroutine.
 type DCTPlan struct {
 	n    int // Original transform size
 	opts Options
 
-	// Extended FFT size: 2*(N-1) for DCT-I
-	extendedN int
+	// Real FFT of size N-1 of the folded input
+	fft *realFFT
 
-	// Underlying complex FFT plan for the extended size
-	fftPlan *algofft.Plan[complex128]
+	// sin(πj/(N-1)) and cos(πj/(N-1)) for j = 0..N-2
+	sin []float64
+	cos []float64
 
-	// Pre-allocated buffers
-	// Note: We use separate input and output buffers because the algo-fft
-	// library has issues with in-place FFT for certain sizes.
-	fftIn  []complex128 // FFT input buffer
-	fftOut []complex128 // FFT output buffer
+	// Per-worker plans of ForwardMany and InverseMany, starting with this one
+	batch []*DCTPlan
 }
 
 // DCT2Plan is a pre-computed Discrete Cosine Transform plan (Type II).
@@ -45,81 +44,205 @@ type DCTPlan struct {
 //
 //	X[k] = Σ x[n] * cos(π(n+1/2)k/N) for k = 0..N-1
 //
-// The inverse is the weighted transpose of the DCT-II kernel.
+// Forward and Inverse each cost one N-point real FFT; the inverse is a
+// scaled DCT-III.
 //
 // Thread safety: A single DCT2Plan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
 type DCT2Plan struct {
 	n    int // Original transform size
 	opts Options
 
-	// Extended FFT size: 2*N for DCT-II
-	extendedN int
+	// Real FFT of size N of the reordered input
+	fft *dct2FFT
+
+	// Per-worker plans of ForwardMany and InverseMany, starting with this one
+	batch []*DCT2Plan
+}
+
+// DCT3Plan is a pre-computed Discrete Cosine Transform plan (Type III).
+//
+// For input x[0..N-1], the DCT-III is defined as:
+//
+//	X[n] = x[0]/2 + Σ x[k] * cos(π(n+1/2)k/N) for k = 1..N-1
+//
+// DCT-III and DCT-II are inverses of each other up to a factor N/2, so
+// DCT-III maps cell-centered cosine coefficients back to grid values. Both
+// directions cost one N-point real FFT.
+//
+// Thread safety: A single DCT3Plan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
+type DCT3Plan struct {
+	dct2 *DCT2Plan
+}
+
+// DCT4Plan is a pre-computed Discrete Cosine Transform plan (Type IV).
+//
+// For input x[0..N-1], the DCT-IV is defined as:
+//
+//	X[k] = Σ x[n] * cos(π(n+1/2)(k+1/2)/N) for k = 0..N-1
+//
+// Its basis is even about the half-sample point left of x[0] and odd about
+// the one right of x[N-1], so it diagonalizes the cell-centered Laplacian
+// with a Neumann face at one end and a Dirichlet face at the other. DCT-IV
+// is its own inverse up to a factor N/2 and is the core of the MDCT and
+// other lapped transforms.
+//
+// Thread safety: A single DCT4Plan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
+type DCT4Plan struct {
+	n    int // Original transform size
+	opts Options
 
-	// Underlying complex FFT plan for the extended size
+	// Complex FFT of size N/2 of the folded input x[2m] + i*x[N-1-2m] for
+	// even N, and of size N of the packed DCT-II/DST-II inputs for odd N
 	fftPlan *algofft.Plan[complex128]
 
 	// Pre-allocated buffers
 	fftIn  []complex128 // FFT input buffer
 	fftOut []complex128 // FFT output buffer
-	phase  []complex128 // exp(-i*pi*k/(2N)) phase factors
+	pre    []complex128 // input twiddles
+	post   []complex128 // output twiddles
+
+	// Per-worker plans of ForwardMany and InverseMany, starting with this one
+	batch []*DCT4Plan
 }
 
 // NewDCTPlan creates a new DCT-I plan for the given size.
 // The size n must be at least 2.
 func NewDCTPlan(n int, opts ...Option) (*DCTPlan, error) {
+	if plan, ok, err := cachedPlan("DCT-I", n, opts, NewDCTPlan); ok {
+		return plan, err
+	}
+
 	if n < 2 {
 		return nil, ErrInvalidSize
 	}
 
-	// DCT-I via FFT uses even extension: embed n points into 2*(n-1) points
-	// x[0..n-1] -> [x[0], x[1], ..., x[n-1], x[n-2], ..., x[1]]
-	extendedN := 2 * (n - 1)
+	m := n - 1
 
-	fftPlan, err := algofft.NewPlan64(extendedN)
+	fft, err := newRealFFT(m)
 	if err != nil {
-		return nil, fmt.Errorf("creating FFT plan: %w", err)
+		return nil, err
+	}
+
+	sin := make([]float64, m)
+	cos := make([]float64, m)
+	for j := range m {
+		sin[j], cos[j] = math.Sincos(math.Pi * float64(j) / float64(m))
+	}
+
+	plan := &DCTPlan{
+		n:    n,
+		opts: applyOptions(opts),
+		fft:  fft,
+		sin:  sin,
+		cos:  cos,
+	}
+
+	plan.batch, err = batchPlans(plan, plan.opts.Workers, plan.cloneBuffers)
+	if err != nil {
+		return nil, err
 	}
 
-	return &DCTPlan{
-		n:         n,
-		opts:      applyOptions(opts),
-		extendedN: extendedN,
-		fftPlan:   fftPlan,
-		fftIn:     make([]complex128, extendedN),
-		fftOut:    make([]complex128, extendedN),
-	}, nil
+	return plan, nil
 }
 
 // NewDCT2Plan creates a new DCT-II plan for the given size.
 // The size n must be at least 1.
 func NewDCT2Plan(n int, opts ...Option) (*DCT2Plan, error) {
+	if plan, ok, err := cachedPlan("DCT-II", n, opts, NewDCT2Plan); ok {
+		return plan, err
+	}
+
 	if n < 1 {
 		return nil, ErrInvalidSize
 	}
 
-	extendedN := 2 * n
+	options := applyOptions(opts)
+
+	fft, err := newDCT2FFT(n, options.Normalization)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &DCT2Plan{
+		n:    n,
+		opts: options,
+		fft:  fft,
+	}
 
-	fftPlan, err := algofft.NewPlan64(extendedN)
+	plan.batch, err = batchPlans(plan, options.Workers, plan.cloneBuffers)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// NewDCT4Plan creates a new DCT-IV plan for the given size.
+// The size n must be at least 1.
+func NewDCT4Plan(n int, opts ...Option) (*DCT4Plan, error) {
+	if plan, ok, err := cachedPlan("DCT-IV", n, opts, NewDCT4Plan); ok {
+		return plan, err
+	}
+
+	if n < 1 {
+		return nil, ErrInvalidSize
+	}
+
+	// Even n fold into n/2 complex points; odd n pack two real sequences
+	// into n complex points.
+	fftN := n
+	if n%2 == 0 {
+		fftN = n / 2
+	}
+
+	fftPlan, err := algofft.NewPlan64(fftN)
 	if err != nil {
 		return nil, fmt.Errorf("creating FFT plan: %w", err)
 	}
 
-	phase := make([]complex128, n)
-	den := 2.0 * float64(n)
-	for k := range n {
-		angle := -math.Pi * float64(k) / den
-		phase[k] = complex(math.Cos(angle), math.Sin(angle))
+	pre := make([]complex128, fftN)
+	post := make([]complex128, fftN)
+	for k := range fftN {
+		if n%2 == 0 {
+			pre[k] = cmplx.Exp(complex(0, -math.Pi*float64(k)/float64(n)))
+			post[k] = cmplx.Exp(complex(0, -math.Pi*float64(4*k+1)/float64(4*n)))
+		} else {
+			// cos and sin of π(2k+1)/(4N), and the DCT-II phase factors
+			pre[k] = cmplx.Exp(complex(0, math.Pi*float64(2*k+1)/float64(4*n)))
+			post[k] = cmplx.Exp(complex(0, -math.Pi*float64(k)/float64(2*n)))
+		}
+	}
+
+	plan := &DCT4Plan{
+		n:       n,
+		opts:    applyOptions(opts),
+		fftPlan: fftPlan,
+		fftIn:   make([]complex128, fftN),
+		fftOut:  make([]complex128, fftN),
+		pre:     pre,
+		post:    post,
+	}
+
+	plan.batch, err = batchPlans(plan, plan.opts.Workers, plan.cloneBuffers)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// NewDCT3Plan creates a new DCT-III plan for the given size.
+// The size n must be at least 1.
+func NewDCT3Plan(n int, opts ...Option) (*DCT3Plan, error) {
+	dct2, err := NewDCT2Plan(n, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	return &DCT2Plan{
-		n:         n,
-		opts:      applyOptions(opts),
-		extendedN: extendedN,
-		fftPlan:   fftPlan,
-		fftIn:     make([]complex128, extendedN),
-		fftOut:    make([]complex128, extendedN),
-		phase:     phase,
-	}, nil
+	return &DCT3Plan{dct2: dct2}, nil
 }
 
 // Len returns the transform size.
@@ -132,103 +255,246 @@ func (p *DCT2Plan) Len() int {
 	return p.n
 }
 
-// Forward computes the forward DCT-I transform.
+// Len returns the transform size.
+func (p *DCT3Plan) Len() int {
+	return p.dct2.n
+}
+
+// Len returns the transform size.
+func (p *DCT4Plan) Len() int {
+	return p.n
+}
+
+// Forward computes the forward DCT-IV transform.
 // dst and src must have length n. They may be the same slice for in-place operation.
 //
-// Output normalization: The output is NOT normalized.
-// For orthogonal normalization, divide by sqrt(2*(N-1)).
-func (p *DCTPlan) Forward(dst, src []float64) error {
+// Output normalization: The output is NOT normalized. With NormOrtho it is
+// scaled by sqrt(2/N), which makes the transform orthonormal and its own
+// inverse.
+func (p *DCT4Plan) Forward(dst, src []float64) error {
+	return p.transform(dst, src, false)
+}
+
+// transform computes the DCT-IV, or with sine set the DST-IV, of src into
+// dst, using DST-IV(x)[k] = (-1)^k DCT-IV(x reversed)[k].
+//
+// For even N, the folded sequence z[m] = x[2m] + i x[N-1-2m] gives both
+// halves of the output from one N/2-point FFT:
+//
+//	Z[p] = exp(-iπ(4p+1)/(4N)) Σ z[m] exp(-iπm/N) exp(-2πi mp/(N/2))
+//
+// with X[2p] = Re Z[p] and X[N-1-2p] = -Im Z[p]. For odd N, splitting
+// the DCT-IV kernel at the angle φ[n] = π(2n+1)/(4N) gives
+//
+//	DCT-IV(x)[k] = DCT-II(x cos φ)[k] - DST-II(x sin φ)[k-1]
+//
+// (the DST-II term vanishing for k = 0), and both N-point transforms
+// come from one complex FFT; see transformOdd.
+func (p *DCT4Plan) transform(dst, src []float64, sine bool) error {
 	if len(dst) != p.n || len(src) != p.n {
 		return ErrSizeMismatch
 	}
 
-	// Clear input buffer
-	for i := range p.extendedN {
-		p.fftIn[i] = 0
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
 	}
 
-	// Build even-symmetric extension:
-	// Position 0..n-1: x[0..n-1]
-	// Position n..2n-3: x[n-2..1] (mirror without endpoints)
-	for i := range p.n {
-		p.fftIn[i] = complex(src[i], 0)
+	scale := p.orthoScale()
+
+	if p.n%2 != 0 {
+		return p.transformOdd(dst, src, sine, scale)
 	}
 
-	for i := 1; i < p.n-1; i++ {
-		p.fftIn[p.extendedN-i] = complex(src[i], 0)
+	half := p.n / 2
+	for m := range half {
+		even, odd := src[2*m], src[p.n-1-2*m]
+		if sine {
+			even, odd = odd, even
+		}
+		p.fftIn[m] = complex(even, odd) * p.pre[m]
 	}
 
-	// FFT with separate input/output buffers (avoids in-place FFT issues)
-	err := p.fftPlan.Forward(p.fftOut, p.fftIn)
-	if err != nil {
+	if err := p.fftPlan.Forward(p.fftOut, p.fftIn); err != nil {
 		return fmt.Errorf("FFT forward: %w", err)
 	}
 
-	// Extract DCT coefficients from real parts
-	// The DCT-I coefficients are in the real part of FFT output at positions 0..n-1
-	scale := 1.0
+	for k := range half {
+		z := p.fftOut[k] * p.post[k]
+		if sine {
+			// The sign (-1)^k flips the odd outputs N-1-2k.
+			dst[2*k] = real(z) * scale
+			dst[p.n-1-2*k] = imag(z) * scale
+		} else {
+			dst[2*k] = real(z) * scale
+			dst[p.n-1-2*k] = -imag(z) * scale
+		}
+	}
+
+	return nil
+}
+
+// orthoScale returns the NormOrtho scale sqrt(2/N) of the type-IV
+// transforms, or 1.
+func (p *DCT4Plan) orthoScale() float64 {
 	if p.opts.Normalization == NormOrtho {
-		scale = 1.0 / math.Sqrt(2.0*float64(p.n-1))
+		return math.Sqrt(2.0 / float64(p.n))
 	}
 
-	for k := range p.n {
-		dst[k] = real(p.fftOut[k]) * scale
+	return 1.0
+}
+
+// transformOdd is transform for odd N. The DCT-II of a = x cos φ and the
+// DST-II of b = x sin φ, itself the DCT-II of c = (-1)^n b with reversed
+// outputs, both use Makhoul's reordering (see dct2FFT). The reordered a and c
+// are packed into the real and imaginary parts of one N-point FFT W, and
+// separated again by the symmetry of real spectra:
+//
+//	A[k] = (W[k] + conj W[N-k]) / 2,   C[k] = (W[k] - conj W[N-k]) / 2i
+func (p *DCT4Plan) transformOdd(dst, src []float64, sine bool, scale float64) error {
+	n := p.n
+	for i := range n {
+		x := src[i]
+		if sine {
+			x = src[n-1-i]
+		}
+
+		a, b := x*real(p.pre[i]), x*imag(p.pre[i])
+		if i%2 == 0 {
+			p.fftIn[i/2] = complex(a, b)
+		} else {
+			p.fftIn[n-1-i/2] = complex(a, -b)
+		}
+	}
+
+	if err := p.fftPlan.Forward(p.fftOut, p.fftIn); err != nil {
+		return fmt.Errorf("FFT forward: %w", err)
+	}
+
+	// dct2 returns the DCT-II of a (or of c with odd set) at k.
+	dct2 := func(k int, odd bool) float64 {
+		w, wc := p.fftOut[k], cmplx.Conj(p.fftOut[(n-k)%n])
+		v := (w + wc) / 2
+		if odd {
+			v = (w - wc) / 2i
+		}
+
+		return real(p.post[k] * v)
+	}
+
+	for k := range n {
+		value := dct2(k, false)
+		if k > 0 {
+			value -= dct2(n-k, true)
+		}
+
+		// DST-IV(x)[k] = (-1)^k DCT-IV(x reversed)[k]
+		if sine && k%2 == 1 {
+			value = -value
+		}
+		dst[k] = value * scale
 	}
 
 	return nil
 }
 
-// Forward computes the forward DCT-II transform.
+// Inverse computes the inverse DCT-IV transform.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Note: DCT-IV is self-inverse up to scaling. The inverse is:
+// x[n] = (2/N) * DCT-IV(X)[n] with NormBackward, the unscaled DCT-IV with
+// NormUnscaled, and Forward itself with NormOrtho.
+func (p *DCT4Plan) Inverse(dst, src []float64) error {
+	if err := p.Forward(dst, src); err != nil {
+		return err
+	}
+
+	if scale := p.opts.inverseScale(float64(p.n) / 2); scale != 1 {
+		for i := range dst {
+			dst[i] *= scale
+		}
+	}
+
+	return nil
+}
+
+// Forward computes the forward DCT-I transform.
 // dst and src must have length n. They may be the same slice for in-place operation.
 //
 // Output normalization: The output is NOT normalized.
-// For orthogonal normalization, multiply by sqrt(2/N) with k=0 scaled by 1/sqrt(N).
-func (p *DCT2Plan) Forward(dst, src []float64) error {
+// For orthogonal normalization, divide by sqrt(2*(N-1)).
+//
+// With M = N-1, the folded sequence
+//
+//	y[j] = (x[j] + x[M-j])/2 - sin(πj/M) (x[j] - x[M-j])   for j = 0..M-1
+//
+// has an M-point FFT Y with X[2k] = 2 Re Y[k] and the recurrence
+// X[2k+1] = X[2k-1] - 2 Im Y[k], started from X[1] computed directly.
+func (p *DCTPlan) Forward(dst, src []float64) error {
 	if len(dst) != p.n || len(src) != p.n {
 		return ErrSizeMismatch
 	}
 
-	for i := range p.extendedN {
-		p.fftIn[i] = 0
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
 	}
 
-	// Even extension:
-	// x[0..n-1] -> [x[0], ..., x[n-1], x[n-1], ..., x[0]]
-	for i := range p.n {
-		p.fftIn[i] = complex(src[i], 0)
-		p.fftIn[p.extendedN-1-i] = complex(src[i], 0)
-	}
+	m := p.n - 1
+	y := p.fft.in
 
-	err := p.fftPlan.Forward(p.fftOut, p.fftIn)
-	if err != nil {
-		return fmt.Errorf("FFT forward: %w", err)
+	y[0] = 0.5 * (src[0] + src[m])
+	odd := 0.5 * (src[0] - src[m])
+	for j := 1; j < m; j++ {
+		a, b := src[j], src[m-j]
+		y[j] = 0.5*(a+b) - p.sin[j]*(a-b)
+		odd += a * p.cos[j]
 	}
 
-	for k := range p.n {
-		shifted := p.fftOut[k] * p.phase[k]
-		value := real(shifted) / 2.0
+	if err := p.fft.forward(); err != nil {
+		return err
+	}
 
-		if p.opts.Normalization == NormOrtho {
-			scale := math.Sqrt(2.0 / float64(p.n))
-			if k == 0 {
-				scale = 1.0 / math.Sqrt(float64(p.n))
-			}
+	scale := 2.0
+	if p.opts.Normalization == NormOrtho {
+		scale /= math.Sqrt(2.0 * float64(m))
+	}
 
-			value *= scale
+	spec := p.fft.spec
+	dst[1] = odd * scale
+	for k := 0; 2*k <= m; k++ {
+		dst[2*k] = real(spec[k]) * scale
+		if k > 0 && 2*k+1 <= m {
+			odd -= imag(spec[k])
+			dst[2*k+1] = odd * scale
 		}
-
-		dst[k] = value
 	}
 
 	return nil
 }
 
+// Forward computes the forward DCT-II transform.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Output normalization: The output is NOT normalized.
+// For orthogonal normalization, multiply by sqrt(2/N) with k=0 scaled by 1/sqrt(N).
+func (p *DCT2Plan) Forward(dst, src []float64) error {
+	if len(dst) != p.n || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	return p.fft.forward(dst, src, false, 1)
+}
+
 // Inverse computes the inverse DCT-I transform.
 // dst and src must have length n. They may be the same slice for in-place operation.
 //
 // Note: DCT-I is its own inverse up to scaling. The inverse is:
-// x[n] = (1/(N-1)) * DCT-I(X)[n] for interior points.
-// x[0] and x[N-1] are scaled by 1/(2*(N-1)).
+// x[n] = (1/(2*(N-1))) * DCT-I(X)[n] with NormBackward (the endpoint
+// weights 1 and interior weights 2 of DCT-I are part of the kernel), the
+// unscaled DCT-I with NormUnscaled, and Forward itself with NormOrtho.
 func (p *DCTPlan) Inverse(dst, src []float64) error {
 	// DCT-I is self-inverse (up to normalization)
 	err := p.Forward(dst, src)
@@ -236,16 +502,10 @@ func (p *DCTPlan) Inverse(dst, src []float64) error {
 		return err
 	}
 
-	// Apply normalization factor: 1/(2*(N-1)) for endpoints, 1/(N-1) for interior
-	// For uniform scaling in spectral methods, we use 1/(N-1) everywhere
-	// and adjust the endpoint weights in the eigenvalue computation
-	scale := 1.0 / float64(p.extendedN)
-	if p.opts.Normalization == NormOrtho {
-		scale = 1.0
-	}
-
-	for i := range p.n {
-		dst[i] *= scale
+	if scale := p.opts.inverseScale(2 * float64(p.n-1)); scale != 1 {
+		for i := range dst {
+			dst[i] *= scale
+		}
 	}
 
 	return nil
@@ -254,68 +514,112 @@ func (p *DCTPlan) Inverse(dst, src []float64) error {
 // Inverse computes the inverse DCT-II transform.
 // dst and src must have length n. They may be the same slice for in-place operation.
 //
-// Note: DCT-II is inverted by a weighted transpose of the DCT-II kernel.
+// Note: DCT-II is inverted by the DCT-III scaled by 2/N with NormBackward
+// (the unscaled DCT-III with NormUnscaled, the orthonormal DCT-III with
+// NormOrtho).
 func (p *DCT2Plan) Inverse(dst, src []float64) error {
 	if len(dst) != p.n || len(src) != p.n {
 		return ErrSizeMismatch
 	}
 
-	srcData := src
-	if len(src) > 0 && len(dst) > 0 && &src[0] == &dst[0] {
-		srcData = make([]float64, p.n)
-		copy(srcData, src)
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
 	}
 
-	for n := range p.n {
-		sum := 0.0
-		for k := range p.n {
-			weight := 2.0 / float64(p.n)
-			if k == 0 {
-				weight = 1.0 / float64(p.n)
-			}
+	return p.fft.inverse(dst, src, false, p.opts.normalizationFactor(float64(p.n)/2))
+}
 
-			if p.opts.Normalization == NormOrtho {
-				weight = math.Sqrt(2.0 / float64(p.n))
-				if k == 0 {
-					weight = 1.0 / math.Sqrt(float64(p.n))
-				}
-			}
+// Forward computes the forward DCT-III transform.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Output normalization: The output is NOT normalized. With NormOrtho the
+// transform is orthonormal (the transpose of the orthonormal DCT-II).
+func (p *DCT3Plan) Forward(dst, src []float64) error {
+	if len(dst) != p.dct2.n || len(src) != p.dct2.n {
+		return ErrSizeMismatch
+	}
 
-			sum += (srcData[k] * weight) * DCT2Coefficient(n, k, p.n)
-		}
+	if err := p.dct2.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	return p.dct2.fft.inverse(dst, src, false, p.scale())
+}
 
-		dst[n] = sum
+// Inverse computes the inverse DCT-III transform: the DCT-II scaled by 2/N
+// with NormBackward (the unscaled DCT-II with NormUnscaled, the orthonormal
+// DCT-II with NormOrtho).
+// dst and src must have length n. They may be the same slice for in-place operation.
+func (p *DCT3Plan) Inverse(dst, src []float64) error {
+	if len(dst) != p.dct2.n || len(src) != p.dct2.n {
+		return ErrSizeMismatch
 	}
 
-	return nil
+	if err := p.dct2.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	return p.dct2.fft.forward(dst, src, false, p.dct2.opts.inverseScale(float64(p.dct2.n)/2))
 }
 
-// NormalizationFactor returns the factor by which values are scaled
-// after a Forward followed by Inverse transform.
-// For DCT-I: Forward * Inverse = 2*(N-1) * I.
-func (p *DCTPlan) NormalizationFactor() float64 {
-	if p.opts.Normalization == NormOrtho {
+// scale returns the factor N/2 between the DCT-III and the inverse DCT-II,
+// or 1 with NormOrtho.
+func (p *DCT3Plan) scale() float64 {
+	if p.dct2.opts.Normalization == NormOrtho {
 		return 1.0
 	}
 
-	return float64(p.extendedN)
+	return float64(p.dct2.n) / 2.0
 }
 
 // NormalizationFactor returns the factor by which values are scaled
 // after a Forward followed by Inverse transform.
-// For DCT-II: Forward followed by Inverse returns the original signal.
+// For DCT-I: 2*(N-1) with NormUnscaled, 1 otherwise.
+func (p *DCTPlan) NormalizationFactor() float64 {
+	return p.opts.normalizationFactor(2 * float64(p.n-1))
+}
+
+// NormalizationFactor returns the factor by which values are scaled
+// after a Forward followed by Inverse transform.
+// For DCT-II: N/2 with NormUnscaled, 1 otherwise.
 func (p *DCT2Plan) NormalizationFactor() float64 {
-	return 1.0
+	return p.opts.normalizationFactor(float64(p.n) / 2)
+}
+
+// NormalizationFactor returns the factor by which values are scaled
+// after a Forward followed by Inverse transform.
+// For DCT-III: N/2 with NormUnscaled, 1 otherwise.
+func (p *DCT3Plan) NormalizationFactor() float64 {
+	return p.dct2.NormalizationFactor()
+}
+
+// NormalizationFactor returns the factor by which values are scaled
+// after a Forward followed by Inverse transform.
+// For DCT-IV: N/2 with NormUnscaled, 1 otherwise.
+func (p *DCT4Plan) NormalizationFactor() float64 {
+	return p.opts.normalizationFactor(float64(p.n) / 2)
 }
 
 // Bytes returns the memory used by the plan in bytes.
 func (p *DCTPlan) Bytes() int {
-	return len(p.fftIn)*16 + len(p.fftOut)*16
+	return p.fft.bytes() + len(p.sin)*8 + len(p.cos)*8 +
+		batchBytes(p.batch, func(q *DCTPlan) int { return q.fft.bytes() })
 }
 
 // Bytes returns the memory used by the plan in bytes.
 func (p *DCT2Plan) Bytes() int {
-	return len(p.fftIn)*16 + len(p.fftOut)*16 + len(p.phase)*16
+	return p.fft.bytes() + batchBytes(p.batch, func(q *DCT2Plan) int { return q.fft.fft.bytes() })
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *DCT3Plan) Bytes() int {
+	return p.dct2.Bytes()
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *DCT4Plan) Bytes() int {
+	return len(p.fftIn)*16 + len(p.fftOut)*16 + len(p.pre)*16 + len(p.post)*16 +
+		batchBytes(p.batch, func(q *DCT4Plan) int { return len(q.fftIn)*16 + len(q.fftOut)*16 })
 }
 
 // DCT1 computes a one-shot DCT-I transform without reusing a plan.
@@ -340,6 +644,26 @@ func DCT2Forward(dst, src []float64) error {
 	return plan.Forward(dst, src)
 }
 
+// DCT3Forward computes a one-shot DCT-III transform without reusing a plan.
+func DCT3Forward(dst, src []float64) error {
+	plan, err := NewDCT3Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Forward(dst, src)
+}
+
+// DCT4 computes a one-shot DCT-IV transform without reusing a plan.
+func DCT4(dst, src []float64) error {
+	plan, err := NewDCT4Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Forward(dst, src)
+}
+
 // DCT1Inverse computes a one-shot inverse DCT-I transform.
 func DCT1Inverse(dst, src []float64) error {
 	plan, err := NewDCTPlan(len(src))
@@ -360,6 +684,26 @@ func DCT2Inverse(dst, src []float64) error {
 	return plan.Inverse(dst, src)
 }
 
+// DCT3Inverse computes a one-shot inverse DCT-III transform.
+func DCT3Inverse(dst, src []float64) error {
+	plan, err := NewDCT3Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Inverse(dst, src)
+}
+
+// DCT4Inverse computes a one-shot inverse DCT-IV transform.
+func DCT4Inverse(dst, src []float64) error {
+	plan, err := NewDCT4Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Inverse(dst, src)
+}
+
 // DCT1Coefficient returns the DCT-I coefficient for mode k at position n.
 // This is the basis function: cos(πnk/(size-1)).
 func DCT1Coefficient(n, k, size int) float64 {
@@ -379,3 +723,13 @@ func DCT2Coefficient(n, k, size int) float64 {
 
 	return math.Cos(math.Pi * (float64(n) + 0.5) * float64(k) / float64(size))
 }
+
+// DCT4Coefficient returns the DCT-IV coefficient for mode k at position n.
+// This is the basis function: cos(π(n+1/2)(k+1/2)/size).
+func DCT4Coefficient(n, k, size int) float64 {
+	if size <= 0 {
+		return 0
+	}
+
+	return math.Cos(math.Pi * (float64(n) + 0.5) * (float64(k) + 0.5) / float64(size))
+}
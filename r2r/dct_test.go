@@ -331,6 +331,29 @@ func TestDCT2Plan_Orthogonality(t *testing.T) {
 	}
 }
 
+func TestDCT4Plan_Orthogonality(t *testing.T) {
+	for _, n := range []int{1, 6, 7} {
+		for k1 := range n {
+			for k2 := range n {
+				sum := 0.0
+				for i := range n {
+					sum += DCT4Coefficient(i, k1, n) * DCT4Coefficient(i, k2, n)
+				}
+
+				expected := 0.0
+				if k1 == k2 {
+					expected = float64(n) / 2.0
+				}
+
+				if math.Abs(sum-expected) > tolerance {
+					t.Errorf("n=%d: orthogonality failed for k1=%d, k2=%d: got %v, want %v",
+						n, k1, k2, sum, expected)
+				}
+			}
+		}
+	}
+}
+
 func TestDCT2Plan_KnownValues(t *testing.T) {
 	n := 8
 
@@ -485,6 +508,313 @@ func TestDCT2Plan_Reference(t *testing.T) {
 	}
 }
 
+// The FFT-based inverse must match the weighted transpose of the DCT-II
+// kernel it replaced, in both normalizations and for non-power-of-two sizes.
+func TestDCT2Plan_InverseMatchesWeightedTranspose(t *testing.T) {
+	for _, norm := range []Normalization{NormBackward, NormOrtho} {
+		for _, n := range []int{37, 100} {
+			plan, err := NewDCT2Plan(n, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("NewDCT2Plan(%d) failed: %v", n, err)
+			}
+
+			src := make([]float64, n)
+			for k := range n {
+				src[k] = math.Sin(float64(k)*0.37) / float64(k+1)
+			}
+
+			dst := make([]float64, n)
+			if err := plan.Inverse(dst, src); err != nil {
+				t.Fatalf("Inverse failed: %v", err)
+			}
+
+			for i := range n {
+				want := 0.0
+				for k := range n {
+					weight := 2.0 / float64(n)
+					if k == 0 {
+						weight = 1.0 / float64(n)
+					}
+					if norm == NormOrtho {
+						weight = math.Sqrt(weight)
+					}
+					want += src[k] * weight * DCT2Coefficient(i, k, n)
+				}
+
+				if math.Abs(dst[i]-want) > 1e-12 {
+					t.Errorf("norm %d, n=%d: Inverse[%d] = %v, want %v", norm, n, i, dst[i], want)
+				}
+			}
+		}
+	}
+}
+
+func TestDCT2Plan_InverseInPlaceNoAlloc(t *testing.T) {
+	plan, err := NewDCT2Plan(8)
+	if err != nil {
+		t.Fatalf("NewDCT2Plan failed: %v", err)
+	}
+
+	buf := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = plan.Inverse(buf, buf)
+	})
+	if allocs != 0 {
+		t.Errorf("in-place Inverse allocated %v times, want 0", allocs)
+	}
+}
+
+func TestDCT3Plan_Reference(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8, 12, 31} {
+		t.Run("dct3-"+sizeStr(n), func(t *testing.T) {
+			plan, err := NewDCT3Plan(n)
+			if err != nil {
+				t.Fatalf("NewDCT3Plan(%d) failed: %v", n, err)
+			}
+
+			src := make([]float64, n)
+			for i := range n {
+				src[i] = math.Sin(float64(i)*0.7) + 0.1*float64(i)
+			}
+
+			dst := make([]float64, n)
+			ref := make([]float64, n)
+			if err := plan.Forward(dst, src); err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+
+			dct3Reference(ref, src)
+			for i := range n {
+				if math.Abs(dst[i]-ref[i]) > 1e-9 {
+					t.Errorf("reference mismatch at [%d]: got %v, want %v", i, dst[i], ref[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDCT3Plan_RoundTrip(t *testing.T) {
+	for _, norm := range []Normalization{NormBackward, NormOrtho} {
+		for _, n := range []int{1, 3, 8, 17, 64} {
+			plan, err := NewDCT3Plan(n, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("NewDCT3Plan(%d) failed: %v", n, err)
+			}
+
+			buf := make([]float64, n)
+			src := make([]float64, n)
+			for i := range n {
+				src[i] = float64(i%5) - 1.5
+			}
+			copy(buf, src)
+
+			if err := plan.Forward(buf, buf); err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+			if err := plan.Inverse(buf, buf); err != nil {
+				t.Fatalf("Inverse failed: %v", err)
+			}
+
+			for i := range n {
+				if math.Abs(buf[i]-src[i]) > tolerance {
+					t.Errorf("norm %d, n=%d: round-trip mismatch at [%d]: got %v, want %v",
+						norm, n, i, buf[i], src[i])
+				}
+			}
+		}
+	}
+}
+
+func TestDCT3Plan_InvertsDCT2(t *testing.T) {
+	n := 10
+	src := make([]float64, n)
+	for i := range n {
+		src[i] = math.Exp(-float64(i) / 3)
+	}
+
+	coeffs := make([]float64, n)
+	if err := DCT2Forward(coeffs, src); err != nil {
+		t.Fatalf("DCT2Forward failed: %v", err)
+	}
+
+	back := make([]float64, n)
+	if err := DCT3Forward(back, coeffs); err != nil {
+		t.Fatalf("DCT3Forward failed: %v", err)
+	}
+
+	// DCT-III(DCT-II(x)) = N/2 x.
+	for i := range n {
+		if want := float64(n) / 2 * src[i]; math.Abs(back[i]-want) > tolerance {
+			t.Errorf("DCT3(DCT2(x))[%d] = %v, want %v", i, back[i], want)
+		}
+	}
+
+	// DCT3Inverse undoes DCT3Forward, recovering the DCT-II coefficients.
+	if err := DCT3Inverse(back, back); err != nil {
+		t.Fatalf("DCT3Inverse failed: %v", err)
+	}
+	for i := range n {
+		if math.Abs(back[i]-coeffs[i]) > tolerance {
+			t.Errorf("DCT3Inverse[%d] = %v, want %v", i, back[i], coeffs[i])
+		}
+	}
+}
+
+func TestDCT3Plan_Errors(t *testing.T) {
+	if _, err := NewDCT3Plan(0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewDCT3Plan(0) = %v, want ErrInvalidSize", err)
+	}
+
+	plan, err := NewDCT3Plan(4)
+	if err != nil {
+		t.Fatalf("NewDCT3Plan failed: %v", err)
+	}
+	if err := plan.Forward(make([]float64, 4), make([]float64, 3)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Forward with short src = %v, want ErrSizeMismatch", err)
+	}
+	if err := plan.Inverse(make([]float64, 3), make([]float64, 4)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Inverse with short dst = %v, want ErrSizeMismatch", err)
+	}
+	if plan.Len() != 4 || plan.NormalizationFactor() != 1 || plan.Bytes() <= 0 {
+		t.Errorf("Len = %d, NormalizationFactor = %v, Bytes = %d", plan.Len(), plan.NormalizationFactor(), plan.Bytes())
+	}
+}
+
+func TestDCT4Plan_Reference(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8, 12, 31} {
+		t.Run("dct4-"+sizeStr(n), func(t *testing.T) {
+			plan, err := NewDCT4Plan(n)
+			if err != nil {
+				t.Fatalf("NewDCT4Plan(%d) failed: %v", n, err)
+			}
+
+			src := make([]float64, n)
+			for i := range n {
+				src[i] = math.Sin(float64(i)*1.3) + 0.2
+			}
+
+			dst := make([]float64, n)
+			if err := plan.Forward(dst, src); err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+
+			for k := range n {
+				ref := 0.0
+				for i := range n {
+					ref += src[i] * DCT4Coefficient(i, k, n)
+				}
+				if math.Abs(dst[k]-ref) > 1e-9 {
+					t.Errorf("reference mismatch at [%d]: got %v, want %v", k, dst[k], ref)
+				}
+			}
+		})
+	}
+}
+
+func TestDCT4Plan_SelfInverse(t *testing.T) {
+	for _, norm := range []Normalization{NormBackward, NormOrtho} {
+		for _, n := range []int{1, 4, 9, 32} {
+			plan, err := NewDCT4Plan(n, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("NewDCT4Plan(%d) failed: %v", n, err)
+			}
+
+			src := make([]float64, n)
+			for i := range n {
+				src[i] = float64((3*i)%7) - 2
+			}
+			buf := append([]float64(nil), src...)
+
+			if err := plan.Forward(buf, buf); err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+			if err := plan.Inverse(buf, buf); err != nil {
+				t.Fatalf("Inverse failed: %v", err)
+			}
+
+			for i := range n {
+				if math.Abs(buf[i]-src[i]) > tolerance {
+					t.Errorf("norm %d, n=%d: round-trip mismatch at [%d]: got %v, want %v",
+						norm, n, i, buf[i], src[i])
+				}
+			}
+		}
+	}
+
+	// The orthonormal transform is its own inverse.
+	n := 6
+	plan, err := NewDCT4Plan(n, WithNormalization(NormOrtho))
+	if err != nil {
+		t.Fatalf("NewDCT4Plan failed: %v", err)
+	}
+	src := []float64{1, -2, 0.5, 3, 0, -1}
+	buf := append([]float64(nil), src...)
+	_ = plan.Forward(buf, buf)
+	_ = plan.Forward(buf, buf)
+	for i := range n {
+		if math.Abs(buf[i]-src[i]) > tolerance {
+			t.Errorf("ortho DCT-IV twice at [%d] = %v, want %v", i, buf[i], src[i])
+		}
+	}
+}
+
+// The DCT-IV basis diagonalizes the cell-centered 3-point Laplacian with a
+// Neumann face left of point 0 and a Dirichlet face right of point N-1.
+func TestDCT4Plan_NeumannDirichletEigenvectors(t *testing.T) {
+	n := 6
+	for k := range n {
+		v := make([]float64, n)
+		for i := range n {
+			v[i] = DCT4Coefficient(i, k, n)
+		}
+
+		lambda := 2 - 2*math.Cos(math.Pi*(float64(k)+0.5)/float64(n))
+		for i := range n {
+			left, right := v[0], -v[n-1]
+			if i > 0 {
+				left = v[i-1]
+			}
+			if i < n-1 {
+				right = v[i+1]
+			}
+
+			if lap := 2*v[i] - left - right; math.Abs(lap-lambda*v[i]) > tolerance {
+				t.Errorf("mode %d at %d: -Δv = %v, want %v", k, i, lap, lambda*v[i])
+			}
+		}
+	}
+}
+
+func TestDCT4_OneShotAndErrors(t *testing.T) {
+	src := []float64{0.5, 1, -1, 2, 0.25}
+	dst := make([]float64, len(src))
+	if err := DCT4(dst, src); err != nil {
+		t.Fatalf("DCT4 failed: %v", err)
+	}
+	if err := DCT4Inverse(dst, dst); err != nil {
+		t.Fatalf("DCT4Inverse failed: %v", err)
+	}
+	for i := range src {
+		if math.Abs(dst[i]-src[i]) > tolerance {
+			t.Errorf("one-shot round-trip mismatch at [%d]: got %v, want %v", i, dst[i], src[i])
+		}
+	}
+
+	if _, err := NewDCT4Plan(0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewDCT4Plan(0) = %v, want ErrInvalidSize", err)
+	}
+	plan, err := NewDCT4Plan(4)
+	if err != nil {
+		t.Fatalf("NewDCT4Plan failed: %v", err)
+	}
+	if err := plan.Forward(make([]float64, 4), make([]float64, 5)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Forward with long src = %v, want ErrSizeMismatch", err)
+	}
+	if plan.Len() != 4 || plan.NormalizationFactor() != 1 || plan.Bytes() <= 0 {
+		t.Errorf("Len = %d, NormalizationFactor = %v, Bytes = %d", plan.Len(), plan.NormalizationFactor(), plan.Bytes())
+	}
+}
+
 func BenchmarkDCTPlan_Forward(b *testing.B) {
 	sizes := []int{64, 256, 1024}
 
@@ -533,6 +863,56 @@ func BenchmarkDCT2Plan_Forward(b *testing.B) {
 	}
 }
 
+func BenchmarkDCT2Plan_Inverse(b *testing.B) {
+	sizes := []int{64, 256, 1024}
+
+	for _, n := range sizes {
+		b.Run("dct2-"+sizeStr(n), func(b *testing.B) {
+			plan, err := NewDCT2Plan(n)
+			if err != nil {
+				b.Fatalf("NewDCT2Plan failed: %v", err)
+			}
+
+			src := make([]float64, n)
+			dst := make([]float64, n)
+			for i := range n {
+				src[i] = float64(i)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				_ = plan.Inverse(dst, src)
+			}
+		})
+	}
+}
+
+// BenchmarkDCT4Plan_Forward covers the folded N/2-point FFT of even sizes
+// and the packed N-point FFT of odd ones.
+func BenchmarkDCT4Plan_Forward(b *testing.B) {
+	sizes := []int{63, 64, 1023, 1024}
+
+	for _, n := range sizes {
+		b.Run("dct4-"+sizeStr(n), func(b *testing.B) {
+			plan, err := NewDCT4Plan(n)
+			if err != nil {
+				b.Fatalf("NewDCT4Plan failed: %v", err)
+			}
+
+			src := make([]float64, n)
+			dst := make([]float64, n)
+			for i := range n {
+				src[i] = float64(i)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				_ = plan.Forward(dst, src)
+			}
+		})
+	}
+}
+
 func dct2Reference(dst, src []float64) {
 	n := len(src)
 	for k := range n {
@@ -543,3 +923,14 @@ func dct2Reference(dst, src []float64) {
 		dst[k] = sum
 	}
 }
+
+func dct3Reference(dst, src []float64) {
+	n := len(src)
+	for i := range n {
+		sum := src[0] / 2
+		for k := 1; k < n; k++ {
+			sum += src[k] * DCT2Coefficient(i, k, n)
+		}
+		dst[i] = sum
+	}
+}
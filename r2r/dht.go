@@ -0,0 +1,165 @@
+package r2r
+
+import "math"
+
+// DHTPlan is a pre-computed Discrete Hartley Transform plan.
+//
+// For input x[0..N-1], the DHT is defined as:
+//
+//	H[k] = Σ x[n] * cas(2πnk/N) for k = 0..N-1, cas θ = cos θ + sin θ
+//
+// The DHT is real-valued and, like the DFT, diagonalizes periodic
+// convolutions and the periodic Laplacian, whose eigenvalue for mode k is
+// shared by mode N-k. It is its own inverse up to a factor N, so periodic
+// real fields can be transformed without complex buffers. Forward and
+// Inverse each cost one N-point real FFT.
+//
+// Thread safety: A single DHTPlan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
+type DHTPlan struct {
+	n    int
+	opts Options
+	fft  *realFFT
+
+	// Per-worker plans of ForwardMany and InverseMany, starting with this one
+	batch []*DHTPlan
+}
+
+// NewDHTPlan creates a new DHT plan for the given size.
+// The size n must be at least 1.
+func NewDHTPlan(n int, opts ...Option) (*DHTPlan, error) {
+	if plan, ok, err := cachedPlan("DHT", n, opts, NewDHTPlan); ok {
+		return plan, err
+	}
+
+	if n < 1 {
+		return nil, ErrInvalidSize
+	}
+
+	fft, err := newRealFFT(n)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &DHTPlan{
+		n:    n,
+		opts: applyOptions(opts),
+		fft:  fft,
+	}
+
+	plan.batch, err = batchPlans(plan, plan.opts.Workers, plan.cloneBuffers)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// Len returns the transform size.
+func (p *DHTPlan) Len() int {
+	return p.n
+}
+
+// Forward computes the forward DHT.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Output normalization: The output is NOT normalized. With NormOrtho it is
+// scaled by 1/sqrt(N), which makes the transform orthonormal and its own
+// inverse.
+//
+// With X the FFT of x, H[k] = Re X[k] - Im X[k], and X[N-k] = conj X[k]
+// gives H[N-k] = Re X[k] + Im X[k] from the same half-spectrum mode.
+func (p *DHTPlan) Forward(dst, src []float64) error {
+	if len(dst) != p.n || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	copy(p.fft.in, src)
+
+	if err := p.fft.forward(); err != nil {
+		return err
+	}
+
+	scale := 1.0
+	if p.opts.Normalization == NormOrtho {
+		scale = 1.0 / math.Sqrt(float64(p.n))
+	}
+
+	spec := p.fft.spec
+	dst[0] = real(spec[0]) * scale
+	for k := 1; k < len(spec); k++ {
+		re, im := real(spec[k]), imag(spec[k])
+		dst[k] = (re - im) * scale
+		dst[p.n-k] = (re + im) * scale
+	}
+
+	return nil
+}
+
+// Inverse computes the inverse DHT.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Note: the DHT is self-inverse up to scaling. The inverse is:
+// x[n] = (1/N) * DHT(H)[n] with NormBackward, the unscaled DHT with
+// NormUnscaled, and Forward itself with NormOrtho.
+func (p *DHTPlan) Inverse(dst, src []float64) error {
+	if err := p.Forward(dst, src); err != nil {
+		return err
+	}
+
+	if scale := p.opts.inverseScale(float64(p.n)); scale != 1 {
+		for i := range dst {
+			dst[i] *= scale
+		}
+	}
+
+	return nil
+}
+
+// NormalizationFactor returns the factor by which values are scaled
+// after a Forward followed by Inverse transform.
+// For the DHT: N with NormUnscaled, 1 otherwise.
+func (p *DHTPlan) NormalizationFactor() float64 {
+	return p.opts.normalizationFactor(float64(p.n))
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *DHTPlan) Bytes() int {
+	return p.fft.bytes() + batchBytes(p.batch, func(q *DHTPlan) int { return q.fft.bytes() })
+}
+
+// DHT computes a one-shot forward DHT.
+func DHT(dst, src []float64) error {
+	plan, err := NewDHTPlan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Forward(dst, src)
+}
+
+// DHTInverse computes a one-shot inverse DHT.
+func DHTInverse(dst, src []float64) error {
+	plan, err := NewDHTPlan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Inverse(dst, src)
+}
+
+// DHTCoefficient returns the DHT coefficient for mode k at position n.
+// This is the basis function: cas(2πnk/size) = cos(2πnk/size) + sin(2πnk/size).
+func DHTCoefficient(n, k, size int) float64 {
+	if size <= 0 {
+		return 0
+	}
+
+	sin, cos := math.Sincos(2 * math.Pi * float64(n*k%size) / float64(size))
+
+	return cos + sin
+}
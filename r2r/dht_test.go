@@ -0,0 +1,152 @@
+package r2r
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDHTPlan_Basic(t *testing.T) {
+	plan, err := NewDHTPlan(8)
+	if err != nil {
+		t.Fatalf("NewDHTPlan failed: %v", err)
+	}
+
+	if plan.Len() != 8 {
+		t.Errorf("Len() = %d, want 8", plan.Len())
+	}
+
+	if plan.NormalizationFactor() != 1 {
+		t.Errorf("NormalizationFactor() = %v, want 1", plan.NormalizationFactor())
+	}
+
+	if _, err := NewDHTPlan(0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewDHTPlan(0): got %v, want ErrInvalidSize", err)
+	}
+
+	buf := make([]float64, 7)
+	if err := plan.Forward(buf, buf); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Forward with short buffers: got %v, want ErrSizeMismatch", err)
+	}
+}
+
+func TestDHTPlan_OrthoSelfInverse(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 16, 27} {
+		plan, err := NewDHTPlan(n, WithNormalization(NormOrtho))
+		if err != nil {
+			t.Fatalf("NewDHTPlan(%d) failed: %v", n, err)
+		}
+
+		src := make([]float64, n)
+		for i := range src {
+			src[i] = math.Exp(-float64(i)/3) - 0.5
+		}
+
+		got := make([]float64, n)
+		if err := plan.Forward(got, src); err != nil {
+			t.Fatalf("Forward failed: %v", err)
+		}
+
+		// Orthonormal: the energy is preserved.
+		energy, want := 0.0, 0.0
+		for i := range n {
+			energy += got[i] * got[i]
+			want += src[i] * src[i]
+		}
+
+		if math.Abs(energy-want) > tolerance*float64(n) {
+			t.Errorf("n=%d: energy %v, want %v", n, energy, want)
+		}
+
+		if err := plan.Forward(got, got); err != nil {
+			t.Fatalf("Forward failed: %v", err)
+		}
+
+		for i := range n {
+			if math.Abs(got[i]-src[i]) > tolerance*float64(n) {
+				t.Errorf("n=%d: Forward(Forward(x))[%d] = %v, want %v", n, i, got[i], src[i])
+			}
+		}
+	}
+}
+
+// The DHT diagonalizes the periodic second difference: mode k is scaled by
+// the DFT eigenvalue 2cos(2πk/N) - 2, which is the same for k and N-k.
+func TestDHTPlan_DiagonalizesPeriodicLaplacian(t *testing.T) {
+	const n = 12
+
+	plan, err := NewDHTPlan(n)
+	if err != nil {
+		t.Fatalf("NewDHTPlan failed: %v", err)
+	}
+
+	src := make([]float64, n)
+	for i := range src {
+		src[i] = math.Sin(float64(i*i) * 0.3)
+	}
+
+	lap := make([]float64, n)
+	for i := range n {
+		lap[i] = src[(i+n-1)%n] - 2*src[i] + src[(i+1)%n]
+	}
+
+	spec := make([]float64, n)
+	if err := plan.Forward(spec, src); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+
+	for k := range spec {
+		spec[k] *= 2*math.Cos(2*math.Pi*float64(k)/n) - 2
+	}
+
+	got := make([]float64, n)
+	if err := plan.Inverse(got, spec); err != nil {
+		t.Fatalf("Inverse failed: %v", err)
+	}
+
+	for i := range n {
+		if math.Abs(got[i]-lap[i]) > tolerance*n {
+			t.Errorf("Laplacian[%d] = %v, want %v", i, got[i], lap[i])
+		}
+	}
+}
+
+func TestDHT_OneShot(t *testing.T) {
+	src := []float64{1, -2, 0.5, 3, 4, -1}
+	spec := make([]float64, len(src))
+	if err := DHT(spec, src); err != nil {
+		t.Fatalf("DHT failed: %v", err)
+	}
+
+	got := make([]float64, len(src))
+	if err := DHTInverse(got, spec); err != nil {
+		t.Fatalf("DHTInverse failed: %v", err)
+	}
+
+	for i := range src {
+		if math.Abs(got[i]-src[i]) > tolerance {
+			t.Errorf("round trip [%d] = %v, want %v", i, got[i], src[i])
+		}
+	}
+}
+
+func BenchmarkDHTPlan_Forward(b *testing.B) {
+	for _, n := range []int{64, 1024} {
+		b.Run(sizeStr(n), func(b *testing.B) {
+			plan, err := NewDHTPlan(n)
+			if err != nil {
+				b.Fatalf("NewDHTPlan failed: %v", err)
+			}
+
+			buf := make([]float64, n)
+			for i := range buf {
+				buf[i] = float64(i)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				_ = plan.Forward(buf, buf)
+			}
+		})
+	}
+}
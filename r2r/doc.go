@@ -1,4 +1,4 @@
-// Package r2r provides real-to-real transforms (DST/DCT) implemented via FFT.
+// Package r2r provides real-to-real transforms (DST/DCT/DHT) implemented via FFT.
 //
 // These transforms are used internally by the Poisson solver to handle
 // Dirichlet and Neumann boundary conditions.
@@ -7,17 +7,112 @@
 //
 // DST is used for Dirichlet boundary conditions where u = 0 at boundaries.
 // The transform diagonalizes the discrete Laplacian with these BCs.
+// DST3Plan provides the DST-III, whose basis is zero at one end and flat at
+// the other (a Dirichlet and a Neumann boundary); it also inverts DST-II up
+// to a factor N/2. DST4Plan is the sine counterpart of DCT4Plan, with the
+// Dirichlet face on the left.
 //
 // # Discrete Cosine Transform (DCT)
 //
 // DCT is used for Neumann boundary conditions where ∂u/∂n = 0 at boundaries.
 // The transform diagonalizes the discrete Laplacian with these BCs.
+// DCT-I acts on vertex-centered grids, DCT-II on cell-centered (staggered)
+// grids; DCT3Plan provides the DCT-III, which inverts DCT-II up to a factor
+// N/2 and serves as its O(N log N) inverse. DCT4Plan provides the
+// self-inverse DCT-IV for a Neumann and a Dirichlet face on a cell-centered
+// grid, and as the core of lapped transforms such as the MDCT.
+//
+// # Discrete Hartley Transform (DHT)
+//
+// DHTPlan provides the DHT, a real self-inverse alternative to the FFT for
+// periodic real fields: it diagonalizes the periodic Laplacian like the
+// DFT, with modes k and N-k sharing an eigenvalue, and needs no complex
+// buffers on the caller's side.
+//
+// # Chebyshev transform
+//
+// ChebyshevPlan maps values at the Chebyshev points cos(πj/(N-1)) to the
+// coefficients of their interpolant in Chebyshev polynomials T_k and back,
+// via DCT-I with the endpoint scaling of the Gauss-Lobatto rule.
+//
+// # Lapped transforms (MDCT)
+//
+// MDCTPlan provides the MDCT, which maps blocks of 2N samples advanced by N
+// to N coefficients each, and the IMDCT, whose overlap-added blocks cancel
+// each other's time-domain aliasing. Both fold onto a DCT-IV; SineWindow and
+// KBDWindow return windows for which the reconstruction is exact. The
+// normalizations act as for DCT-IV, with round-trip factor N/2 for the
+// overlap-added output.
+//
+// # Convolution and resampling
+//
+// The DCT-II diagonalizes convolution with a symmetric kernel under
+// reflective (half-sample symmetric) boundaries. SymmetricConvolutionPlan
+// filters gridded data this way in two real FFTs for any kernel length, and
+// ResamplePlan (and Resample) interpolates N cell-centered samples to M by
+// zero-padding or truncating their DCT-II coefficients.
+//
+// # Normalization
+//
+// Forward computes the unscaled sums over j = 0..N-1, for k = 0..N-1:
+//
+//	DST-I    X[k] = Σ x[j] sin(π(j+1)(k+1)/(N+1))
+//	DST-II   X[k] = Σ x[j] sin(π(2j+1)(k+1)/(2N))
+//	DST-III  X[k] = Σ w[j] x[j] sin(π(j+1)(2k+1)/(2N)),  w[N-1] = 1/2
+//	DST-IV   X[k] = Σ x[j] sin(π(2j+1)(2k+1)/(4N))
+//	DCT-I    X[k] = Σ w[j] x[j] cos(πjk/(N-1)),         w[0] = w[N-1] = 1
+//	DCT-II   X[k] = Σ x[j] cos(π(2j+1)k/(2N))
+//	DCT-III  X[k] = Σ w[j] x[j] cos(πj(2k+1)/(2N)),     w[0] = 1/2
+//	DCT-IV   X[k] = Σ x[j] cos(π(2j+1)(2k+1)/(4N))
+//	DHT      X[k] = Σ x[j] (cos(2πjk/N) + sin(2πjk/N))
+//
+// with w[j] = 2 for the interior of DCT-I and 1 wherever not listed. Types
+// II and III invert each other, types I and IV and the DHT invert
+// themselves, up to a round-trip factor f: (N+1)/2 for DST-I, 2(N-1) for
+// DCT-I, N for the DHT and N/2 for the rest. NormBackward (the default)
+// and its older name NormNone scale Inverse by 1/f; NormUnscaled scales
+// neither direction, so Inverse(Forward(x)) = f·x; NormOrtho scales both by
+// 1/√f, which makes every matrix but DCT-I's orthonormal.
+//
+// Every plan's BasisMatrix returns the dense matrix of its Forward, with
+// the normalization applied, evaluated from the closed-form kernels
+// (DCT1Coefficient, ..., MDCTCoefficient) for tests and cross-validation.
+//
+// WithCheckFinite makes the transforms scan their input for NaN and Inf and
+// return a *NonFiniteError (wrapping ErrNonFinite) with the index of the
+// first one, instead of spreading it over every output.
+//
+// # Batches
+//
+// Every plan has ForwardMany and InverseMany, which transform count vectors
+// stored back to back in one slice. WithWorkers splits a batch over several
+// goroutines, each with its own FFT buffers and all sharing the plan's
+// twiddle tables.
+//
+// A plan is not safe for concurrent use. Clone returns an independent plan
+// with its own buffers that shares the twiddle tables, so each goroutine
+// can hold a clone instead of a separately built or pooled plan.
+// WithPlanCache goes one step further: constructors keep one plan per
+// transform, size and options in a package-wide cache and return clones
+// of it.
 //
 // # Implementation
 //
-// Both DST and DCT are implemented via FFT using standard embedding techniques:
-//   - DST: Odd extension of the data, then FFT
-//   - DCT: Even extension of the data, then FFT
+// The transforms fold the real input so that an FFT of about N points does
+// the work, instead of embedding it into a 2N-point complex FFT:
+//   - DCT-II, DCT-III and their sine counterparts: one N-point real FFT of
+//     the even/odd reordered input (Makhoul's algorithm)
+//   - DCT-I and DST-I: one real FFT of N-1 and N+1 points, plus a
+//     recurrence for the odd outputs
+//   - DCT-IV and DST-IV: one N/2-point complex FFT of the input folded into
+//     complex pairs
+//   - DHT: one N-point real FFT, H[k] = Re X[k] - Im X[k]
 //
-// This leverages the optimized FFT implementation from algo-fft.
+// Power-of-two lengths use the real-to-complex FFT of algo-fft. Other
+// lengths use a complex FFT of the same length, since that FFT does not
+// support odd lengths and is inaccurate for even non-power-of-two ones; odd
+// DCT-IV and DST-IV pack their DCT-II and DST-II halves into one. No
+// transform builds the even or odd extension of its input, so a Forward
+// touches O(N) words of buffer rather than clearing and refilling 2N complex
+// values.
 package r2r
@@ -1,11 +1,6 @@
 package r2r
 
-import (
-	"fmt"
-	"math"
-
-	algofft "github.com/MeKo-Christian/algo-fft"
-)
+import "math"
 
 // DSTPlan is a pre-computed Discrete Sine Transform plan.
 // DST is used for Dirichlet boundary conditions where u = 0 at boundaries.
@@ -20,22 +15,19 @@ import (
 // The inverse is the same transform scaled by 2/(N+1).
 //
 // Thread safety: A single DSTPlan instance is NOT safe for concurrent use.
-// For parallel transforms, create separate plan instances per goroutine.
+// For parallel transforms, Clone the plan for each goroutine.
 type DSTPlan struct {
 	n    int // Original transform size
 	opts Options
 
-	// Extended FFT size: 2*(N+1) for DST-I
-	extendedN int
+	// Real FFT of size N+1 of the folded input
+	fft *realFFT
 
-	// Underlying complex FFT plan for the extended size
-	fftPlan *algofft.Plan[complex128]
+	// sin(πj/(N+1)) for j = 0..N
+	sin []float64
 
-	// Pre-allocated buffers
-	// Note: We use separate input and output buffers because the algo-fft
-	// library has issues with in-place FFT for certain sizes (e.g., 18).
-	fftIn  []complex128 // FFT input buffer
-	fftOut []complex128 // FFT output buffer
+	// Per-worker plans of ForwardMany and InverseMany, starting with this one
+	batch []*DSTPlan
 }
 
 // DST2Plan is a pre-computed Discrete Sine Transform plan (Type II).
@@ -44,81 +36,146 @@ type DSTPlan struct {
 //
 //	X[k] = Σ x[n] * sin(π(n+1/2)(k+1)/N) for k = 0..N-1
 //
-// The inverse is DST-II followed by a scale of 2/N.
+// It is the DCT-II of (-1)^n x[n] with the outputs reversed, so Forward and
+// Inverse each cost one N-point real FFT; the inverse is a scaled DST-III.
 //
 // Thread safety: A single DST2Plan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
 type DST2Plan struct {
 	n    int // Original transform size
 	opts Options
 
-	// Extended FFT size: 2*N for DST-II
-	extendedN int
+	// Real FFT of size N of the reordered input, shared with DCT-II
+	fft *dct2FFT
+
+	// Per-worker plans of ForwardMany and InverseMany, starting with this one
+	batch []*DST2Plan
+}
 
-	// Underlying complex FFT plan for the extended size
-	fftPlan *algofft.Plan[complex128]
+// DST3Plan is a pre-computed Discrete Sine Transform plan (Type III).
+//
+// For input x[0..N-1], the DST-III is defined as:
+//
+//	X[k] = (-1)^k * x[N-1]/2 + Σ x[n] * sin(π(n+1)(k+1/2)/N) for n = 0..N-2
+//
+// Its basis functions sin(π(n+1)(k+1/2)/N) vanish at one end of the grid and
+// have zero slope at the other, so DST-III diagonalizes the Laplacian with a
+// Dirichlet and a Neumann boundary. DST-III and DST-II are inverses of each
+// other up to a factor N/2. Both directions cost one N-point real FFT.
+//
+// Thread safety: A single DST3Plan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
+type DST3Plan struct {
+	dst2 *DST2Plan
+}
 
-	// Pre-allocated buffers
-	fftIn  []complex128 // FFT input buffer
-	fftOut []complex128 // FFT output buffer
-	phase  []complex128 // exp(-i*pi*(k+1)/(2N)) phase factors
+// DST4Plan is a pre-computed Discrete Sine Transform plan (Type IV).
+//
+// For input x[0..N-1], the DST-IV is defined as:
+//
+//	X[k] = Σ x[n] * sin(π(n+1/2)(k+1/2)/N) for k = 0..N-1
+//
+// Its basis is odd about the half-sample point left of x[0] and even about
+// the one right of x[N-1] (a Dirichlet and a Neumann face on a cell-centered
+// grid), mirroring DCT-IV. DST-IV is its own inverse up to a factor N/2 and
+// shares the FFT algorithm of DCT4Plan.
+//
+// Thread safety: A single DST4Plan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
+type DST4Plan struct {
+	dct4 *DCT4Plan
 }
 
 // NewDSTPlan creates a new DST-I plan for the given size.
 // The size n must be at least 1.
 func NewDSTPlan(n int, opts ...Option) (*DSTPlan, error) {
+	if plan, ok, err := cachedPlan("DST-I", n, opts, NewDSTPlan); ok {
+		return plan, err
+	}
+
 	if n < 1 {
 		return nil, ErrInvalidSize
 	}
 
-	// DST-I via FFT uses odd extension: embed n points into 2*(n+1) points
-	// x[0..n-1] -> [0, x[0], x[1], ..., x[n-1], 0, -x[n-1], ..., -x[0]]
-	extendedN := 2 * (n + 1)
+	m := n + 1
+
+	fft, err := newRealFFT(m)
+	if err != nil {
+		return nil, err
+	}
+
+	sin := make([]float64, m)
+	for j := range m {
+		sin[j] = math.Sin(math.Pi * float64(j) / float64(m))
+	}
+
+	plan := &DSTPlan{
+		n:    n,
+		opts: applyOptions(opts),
+		fft:  fft,
+		sin:  sin,
+	}
 
-	fftPlan, err := algofft.NewPlan64(extendedN)
+	plan.batch, err = batchPlans(plan, plan.opts.Workers, plan.cloneBuffers)
 	if err != nil {
-		return nil, fmt.Errorf("creating FFT plan: %w", err)
+		return nil, err
 	}
 
-	return &DSTPlan{
-		n:         n,
-		opts:      applyOptions(opts),
-		extendedN: extendedN,
-		fftPlan:   fftPlan,
-		fftIn:     make([]complex128, extendedN),
-		fftOut:    make([]complex128, extendedN),
-	}, nil
+	return plan, nil
 }
 
 // NewDST2Plan creates a new DST-II plan for the given size.
 // The size n must be at least 1.
 func NewDST2Plan(n int, opts ...Option) (*DST2Plan, error) {
+	if plan, ok, err := cachedPlan("DST-II", n, opts, NewDST2Plan); ok {
+		return plan, err
+	}
+
 	if n < 1 {
 		return nil, ErrInvalidSize
 	}
 
-	extendedN := 2 * n
+	options := applyOptions(opts)
+
+	fft, err := newDCT2FFT(n, options.Normalization)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &DST2Plan{
+		n:    n,
+		opts: options,
+		fft:  fft,
+	}
+
+	plan.batch, err = batchPlans(plan, options.Workers, plan.cloneBuffers)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
 
-	fftPlan, err := algofft.NewPlan64(extendedN)
+// NewDST4Plan creates a new DST-IV plan for the given size.
+// The size n must be at least 1.
+func NewDST4Plan(n int, opts ...Option) (*DST4Plan, error) {
+	dct4, err := NewDCT4Plan(n, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("creating FFT plan: %w", err)
+		return nil, err
 	}
 
-	phase := make([]complex128, n)
-	den := 2.0 * float64(n)
-	for k := range n {
-		angle := -math.Pi * float64(k+1) / den
-		phase[k] = complex(math.Cos(angle), math.Sin(angle))
+	return &DST4Plan{dct4: dct4}, nil
+}
+
+// NewDST3Plan creates a new DST-III plan for the given size.
+// The size n must be at least 1.
+func NewDST3Plan(n int, opts ...Option) (*DST3Plan, error) {
+	dst2, err := NewDST2Plan(n, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	return &DST2Plan{
-		n:         n,
-		opts:      applyOptions(opts),
-		extendedN: extendedN,
-		fftPlan:   fftPlan,
-		fftIn:     make([]complex128, extendedN),
-		fftOut:    make([]complex128, extendedN),
-		phase:     phase,
-	}, nil
+	return &DST3Plan{dst2: dst2}, nil
 }
 
 // Len returns the transform size.
@@ -131,46 +188,94 @@ func (p *DST2Plan) Len() int {
 	return p.n
 }
 
+// Len returns the transform size.
+func (p *DST3Plan) Len() int {
+	return p.dst2.n
+}
+
+// Len returns the transform size.
+func (p *DST4Plan) Len() int {
+	return p.dct4.n
+}
+
+// Forward computes the forward DST-IV transform.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Output normalization: The output is NOT normalized. With NormOrtho it is
+// scaled by sqrt(2/N), which makes the transform orthonormal and its own
+// inverse.
+func (p *DST4Plan) Forward(dst, src []float64) error {
+	return p.dct4.transform(dst, src, true)
+}
+
+// Inverse computes the inverse DST-IV transform.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Note: DST-IV is self-inverse up to scaling. The inverse is:
+// x[n] = (2/N) * DST-IV(X)[n] with NormBackward, the unscaled DST-IV with
+// NormUnscaled, and Forward itself with NormOrtho.
+func (p *DST4Plan) Inverse(dst, src []float64) error {
+	if err := p.Forward(dst, src); err != nil {
+		return err
+	}
+
+	if scale := p.dct4.opts.inverseScale(float64(p.dct4.n) / 2); scale != 1 {
+		for i := range dst {
+			dst[i] *= scale
+		}
+	}
+
+	return nil
+}
+
 // Forward computes the forward DST-I transform.
 // dst and src must have length n. They may be the same slice for in-place operation.
 //
 // Output normalization: The output is NOT normalized.
 // For orthogonal normalization, divide by sqrt(2*(N+1)).
+//
+// With M = N+1 and f[j] = x[j-1] (f[0] = f[M] = 0), the folded sequence
+//
+//	y[j] = sin(πj/M) (f[j] + f[M-j]) + (f[j] - f[M-j])/2   for j = 0..M-1
+//
+// has an M-point FFT Y with X[2k-1] = -Im Y[k] and the recurrence
+// X[2k] = X[2k-2] + Re Y[k], started from X[0] = Re Y[0]/2.
 func (p *DSTPlan) Forward(dst, src []float64) error {
 	if len(dst) != p.n || len(src) != p.n {
 		return ErrSizeMismatch
 	}
 
-	// Build odd-symmetric extension:
-	// Position 0: 0
-	// Position 1..n: x[0..n-1]
-	// Position n+1: 0
-	// Position n+2..2n+1: -x[n-1..0]
-	for i := range p.extendedN {
-		p.fftIn[i] = 0
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
 	}
 
-	for i := range p.n {
-		p.fftIn[i+1] = complex(src[i], 0)
-		p.fftIn[p.extendedN-1-i] = complex(-src[i], 0)
+	m := p.n + 1
+	y := p.fft.in
+
+	y[0] = 0
+	for j := 1; j < m; j++ {
+		a, b := src[j-1], src[p.n-j]
+		y[j] = p.sin[j]*(a+b) + 0.5*(a-b)
 	}
 
-	// FFT with separate input/output buffers (avoids in-place FFT issues)
-	err := p.fftPlan.Forward(p.fftOut, p.fftIn)
-	if err != nil {
-		return fmt.Errorf("FFT forward: %w", err)
+	if err := p.fft.forward(); err != nil {
+		return err
 	}
 
-	// Extract DST coefficients from imaginary parts
-	// For DST-I with odd extension of size 2*(N+1):
-	// X_dst[k] = -Im(Y[k+1]) / 2 for k = 0..n-1
 	scale := 1.0
 	if p.opts.Normalization == NormOrtho {
-		scale = math.Sqrt(2.0 / float64(p.n+1))
+		scale = math.Sqrt(2.0 / float64(m))
 	}
 
-	for k := range p.n {
-		dst[k] = (-imag(p.fftOut[k+1]) / 2) * scale
+	spec := p.fft.spec
+	odd := 0.5 * real(spec[0])
+	dst[0] = odd * scale
+	for k := 1; 2*k-1 < p.n; k++ {
+		dst[2*k-1] = -imag(spec[k]) * scale
+		if 2*k < p.n {
+			odd += real(spec[k])
+			dst[2*k] = odd * scale
+		}
 	}
 
 	return nil
@@ -186,60 +291,29 @@ func (p *DST2Plan) Forward(dst, src []float64) error {
 		return ErrSizeMismatch
 	}
 
-	for i := range p.extendedN {
-		p.fftIn[i] = 0
-	}
-
-	// Odd extension:
-	// x[0..n-1] -> [x[0], ..., x[n-1], -x[n-1], ..., -x[0]]
-	for i := range p.n {
-		p.fftIn[i] = complex(src[i], 0)
-		p.fftIn[p.extendedN-1-i] = complex(-src[i], 0)
-	}
-
-	err := p.fftPlan.Forward(p.fftOut, p.fftIn)
-	if err != nil {
-		return fmt.Errorf("FFT forward: %w", err)
-	}
-
-	// DST-II coefficients from phase-shifted FFT output.
-	for k := range p.n {
-		shifted := p.fftOut[k+1] * p.phase[k]
-		value := -imag(shifted) / 2.0
-
-		if p.opts.Normalization == NormOrtho {
-			scale := math.Sqrt(2.0 / float64(p.n))
-			if k == p.n-1 {
-				scale = 1.0 / math.Sqrt(float64(p.n))
-			}
-
-			value *= scale
-		}
-
-		dst[k] = value
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
 	}
 
-	return nil
+	return p.fft.forward(dst, src, true, 1)
 }
 
 // Inverse computes the inverse DST-I transform.
 // dst and src must have length n. They may be the same slice for in-place operation.
 //
 // Note: DST-I is its own inverse up to scaling.
-// The inverse is: x[n] = (2/(N+1)) * DST-I(X)[n].
+// The inverse is: x[n] = (2/(N+1)) * DST-I(X)[n] with NormBackward, the
+// unscaled DST-I with NormUnscaled, and Forward itself with NormOrtho.
 func (p *DSTPlan) Inverse(dst, src []float64) error {
 	// DST-I is self-inverse (up to normalization)
 	if err := p.Forward(dst, src); err != nil {
 		return err
 	}
 
-	scale := 2.0 / float64(p.n+1)
-	if p.opts.Normalization == NormOrtho {
-		scale = 1.0
-	}
-
-	for i := range p.n {
-		dst[i] *= scale
+	if scale := p.opts.inverseScale(float64(p.n+1) / 2); scale != 1 {
+		for i := range dst {
+			dst[i] *= scale
+		}
 	}
 
 	return nil
@@ -248,69 +322,111 @@ func (p *DSTPlan) Inverse(dst, src []float64) error {
 // Inverse computes the inverse DST-II transform.
 // dst and src must have length n. They may be the same slice for in-place operation.
 //
-// Note: DST-II is inverted by a weighted transpose of the DST-II kernel.
+// Note: DST-II is inverted by the DST-III scaled by 2/N with NormBackward
+// (the unscaled DST-III with NormUnscaled, the orthonormal DST-III with
+// NormOrtho).
 func (p *DST2Plan) Inverse(dst, src []float64) error {
 	if len(dst) != p.n || len(src) != p.n {
 		return ErrSizeMismatch
 	}
 
-	srcData := src
-	if len(src) > 0 && len(dst) > 0 && &src[0] == &dst[0] {
-		srcData = make([]float64, p.n)
-		copy(srcData, src)
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
 	}
 
-	// Weighted transpose of DST-II kernel (O(N^2)); TODO: replace with FFT-based DST-II inverse.
-	for n := range p.n {
-		sum := 0.0
-		for k := range p.n {
-			weight := 2.0 / float64(p.n)
-			if k == p.n-1 {
-				weight = 1.0 / float64(p.n)
-			}
+	return p.fft.inverse(dst, src, true, p.opts.normalizationFactor(float64(p.n)/2))
+}
+
+// Forward computes the forward DST-III transform.
+// dst and src must have length n. They may be the same slice for in-place operation.
+//
+// Output normalization: The output is NOT normalized. With NormOrtho the
+// transform is orthonormal (the transpose of the orthonormal DST-II).
+func (p *DST3Plan) Forward(dst, src []float64) error {
+	if len(dst) != p.dst2.n || len(src) != p.dst2.n {
+		return ErrSizeMismatch
+	}
 
-			if p.opts.Normalization == NormOrtho {
-				weight = math.Sqrt(2.0 / float64(p.n))
-				if k == p.n-1 {
-					weight = 1.0 / math.Sqrt(float64(p.n))
-				}
-			}
+	if err := p.dst2.opts.checkFinite(src); err != nil {
+		return err
+	}
 
-			sum += (srcData[k] * weight) * DST2Coefficient(n, k, p.n)
-		}
+	return p.dst2.fft.inverse(dst, src, true, p.scale())
+}
 
-		dst[n] = sum
+// Inverse computes the inverse DST-III transform: the DST-II scaled by 2/N
+// with NormBackward (the unscaled DST-II with NormUnscaled, the orthonormal
+// DST-II with NormOrtho).
+// dst and src must have length n. They may be the same slice for in-place operation.
+func (p *DST3Plan) Inverse(dst, src []float64) error {
+	if len(dst) != p.dst2.n || len(src) != p.dst2.n {
+		return ErrSizeMismatch
 	}
 
-	return nil
+	if err := p.dst2.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	return p.dst2.fft.forward(dst, src, true, p.dst2.opts.inverseScale(float64(p.dst2.n)/2))
 }
 
-// NormalizationFactor returns the factor by which values are scaled
-// after a Forward followed by Inverse transform.
-// For DST-I: Forward * Inverse = (N+1)/2 * I.
-func (p *DSTPlan) NormalizationFactor() float64 {
-	if p.opts.Normalization == NormOrtho {
+// scale returns the factor N/2 between the DST-III and the inverse DST-II,
+// or 1 with NormOrtho.
+func (p *DST3Plan) scale() float64 {
+	if p.dst2.opts.Normalization == NormOrtho {
 		return 1.0
 	}
 
-	return float64(p.n+1) / 2.0
+	return float64(p.dst2.n) / 2.0
+}
+
+// NormalizationFactor returns the factor by which values are scaled
+// after a Forward followed by Inverse transform.
+// For DST-I: (N+1)/2 with NormUnscaled, 1 otherwise.
+func (p *DSTPlan) NormalizationFactor() float64 {
+	return p.opts.normalizationFactor(float64(p.n+1) / 2)
 }
 
 // NormalizationFactor returns the factor by which values are scaled
 // after a Forward followed by Inverse transform.
-// For DST-II: Forward followed by Inverse returns the original signal.
+// For DST-II: N/2 with NormUnscaled, 1 otherwise.
 func (p *DST2Plan) NormalizationFactor() float64 {
-	return 1.0
+	return p.opts.normalizationFactor(float64(p.n) / 2)
+}
+
+// NormalizationFactor returns the factor by which values are scaled
+// after a Forward followed by Inverse transform.
+// For DST-III: N/2 with NormUnscaled, 1 otherwise.
+func (p *DST3Plan) NormalizationFactor() float64 {
+	return p.dst2.NormalizationFactor()
+}
+
+// NormalizationFactor returns the factor by which values are scaled
+// after a Forward followed by Inverse transform.
+// For DST-IV: N/2 with NormUnscaled, 1 otherwise.
+func (p *DST4Plan) NormalizationFactor() float64 {
+	return p.dct4.opts.normalizationFactor(float64(p.dct4.n) / 2)
 }
 
 // Bytes returns the memory used by the plan in bytes.
 func (p *DSTPlan) Bytes() int {
-	return len(p.fftIn)*16 + len(p.fftOut)*16
+	return p.fft.bytes() + len(p.sin)*8 +
+		batchBytes(p.batch, func(q *DSTPlan) int { return q.fft.bytes() })
 }
 
 // Bytes returns the memory used by the plan in bytes.
 func (p *DST2Plan) Bytes() int {
-	return len(p.fftIn)*16 + len(p.fftOut)*16 + len(p.phase)*16
+	return p.fft.bytes() + batchBytes(p.batch, func(q *DST2Plan) int { return q.fft.fft.bytes() })
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *DST3Plan) Bytes() int {
+	return p.dst2.Bytes()
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *DST4Plan) Bytes() int {
+	return p.dct4.Bytes()
 }
 
 // DST1 computes a one-shot DST-I transform without reusing a plan.
@@ -335,6 +451,26 @@ func DST2Forward(dst, src []float64) error {
 	return plan.Forward(dst, src)
 }
 
+// DST3Forward computes a one-shot DST-III transform without reusing a plan.
+func DST3Forward(dst, src []float64) error {
+	plan, err := NewDST3Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Forward(dst, src)
+}
+
+// DST4 computes a one-shot DST-IV transform without reusing a plan.
+func DST4(dst, src []float64) error {
+	plan, err := NewDST4Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Forward(dst, src)
+}
+
 // DST1Inverse computes a one-shot inverse DST-I transform.
 func DST1Inverse(dst, src []float64) error {
 	plan, err := NewDSTPlan(len(src))
@@ -355,6 +491,26 @@ func DST2Inverse(dst, src []float64) error {
 	return plan.Inverse(dst, src)
 }
 
+// DST3Inverse computes a one-shot inverse DST-III transform.
+func DST3Inverse(dst, src []float64) error {
+	plan, err := NewDST3Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Inverse(dst, src)
+}
+
+// DST4Inverse computes a one-shot inverse DST-IV transform.
+func DST4Inverse(dst, src []float64) error {
+	plan, err := NewDST4Plan(len(src))
+	if err != nil {
+		return err
+	}
+
+	return plan.Inverse(dst, src)
+}
+
 // DST1Coefficient returns the DST-I coefficient for mode k at position n.
 // This is the basis function: sin(π(n+1)(k+1)/(size+1)).
 func DST1Coefficient(n, k, size int) float64 {
@@ -380,3 +536,13 @@ func DST3Coefficient(n, k, size int) float64 {
 
 	return math.Sin(math.Pi * float64(n+1) * (float64(k) + 0.5) / float64(size))
 }
+
+// DST4Coefficient returns the DST-IV coefficient for mode k at position n.
+// This is the basis function: sin(π(n+1/2)(k+1/2)/size).
+func DST4Coefficient(n, k, size int) float64 {
+	if size <= 0 {
+		return 0
+	}
+
+	return math.Sin(math.Pi * (float64(n) + 0.5) * (float64(k) + 0.5) / float64(size))
+}
@@ -1,6 +1,7 @@
 package r2r
 
 import (
+	"errors"
 	"math"
 	"testing"
 )
@@ -194,6 +195,29 @@ func TestDST2Plan_Orthogonality(t *testing.T) {
 	}
 }
 
+func TestDST4Plan_Orthogonality(t *testing.T) {
+	for _, n := range []int{1, 6, 7} {
+		for k1 := range n {
+			for k2 := range n {
+				sum := 0.0
+				for i := range n {
+					sum += DST4Coefficient(i, k1, n) * DST4Coefficient(i, k2, n)
+				}
+
+				expected := 0.0
+				if k1 == k2 {
+					expected = float64(n) / 2.0
+				}
+
+				if math.Abs(sum-expected) > tolerance {
+					t.Errorf("n=%d: orthogonality failed for k1=%d, k2=%d: got %v, want %v",
+						n, k1, k2, sum, expected)
+				}
+			}
+		}
+	}
+}
+
 func TestDSTPlan_KnownValues(t *testing.T) {
 	// Test with a single sine mode
 	n := 7
@@ -469,6 +493,286 @@ func runConcurrentWorker(size, worker, iterations int) error {
 	return nil
 }
 
+// The FFT-based inverse must match the weighted transpose of the DST-II
+// kernel it replaced, in both normalizations and for non-power-of-two sizes.
+func TestDST2Plan_InverseMatchesWeightedTranspose(t *testing.T) {
+	for _, norm := range []Normalization{NormBackward, NormOrtho} {
+		for _, n := range []int{37, 100} {
+			plan, err := NewDST2Plan(n, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("NewDST2Plan(%d) failed: %v", n, err)
+			}
+
+			src := make([]float64, n)
+			for k := range n {
+				src[k] = math.Cos(float64(k)*0.53) / float64(k+1)
+			}
+
+			dst := make([]float64, n)
+			if err := plan.Inverse(dst, src); err != nil {
+				t.Fatalf("Inverse failed: %v", err)
+			}
+
+			want := make([]float64, n)
+			dst2TransposeInverse(want, src, norm)
+			for i := range n {
+				if math.Abs(dst[i]-want[i]) > 1e-12 {
+					t.Errorf("norm %d, n=%d: Inverse[%d] = %v, want %v", norm, n, i, dst[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestDST2Plan_InverseInPlaceNoAlloc(t *testing.T) {
+	plan, err := NewDST2Plan(8)
+	if err != nil {
+		t.Fatalf("NewDST2Plan failed: %v", err)
+	}
+
+	buf := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = plan.Inverse(buf, buf)
+	})
+	if allocs != 0 {
+		t.Errorf("in-place Inverse allocated %v times, want 0", allocs)
+	}
+}
+
+func TestDST3Plan_Reference(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8, 12, 31} {
+		t.Run("dst3-"+sizeStr(n), func(t *testing.T) {
+			plan, err := NewDST3Plan(n)
+			if err != nil {
+				t.Fatalf("NewDST3Plan(%d) failed: %v", n, err)
+			}
+
+			src := make([]float64, n)
+			for i := range n {
+				src[i] = math.Cos(float64(i)*0.9) - 0.05*float64(i)
+			}
+
+			dst := make([]float64, n)
+			ref := make([]float64, n)
+			if err := plan.Forward(dst, src); err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+
+			dst3Reference(ref, src)
+			for i := range n {
+				if math.Abs(dst[i]-ref[i]) > 1e-9 {
+					t.Errorf("reference mismatch at [%d]: got %v, want %v", i, dst[i], ref[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDST3Plan_RoundTrip(t *testing.T) {
+	for _, norm := range []Normalization{NormBackward, NormOrtho} {
+		for _, n := range []int{1, 3, 8, 17, 64} {
+			plan, err := NewDST3Plan(n, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("NewDST3Plan(%d) failed: %v", n, err)
+			}
+
+			buf := make([]float64, n)
+			src := make([]float64, n)
+			for i := range n {
+				src[i] = float64(i%4) + 0.5
+			}
+			copy(buf, src)
+
+			if err := plan.Forward(buf, buf); err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+			if err := plan.Inverse(buf, buf); err != nil {
+				t.Fatalf("Inverse failed: %v", err)
+			}
+
+			for i := range n {
+				if math.Abs(buf[i]-src[i]) > tolerance {
+					t.Errorf("norm %d, n=%d: round-trip mismatch at [%d]: got %v, want %v",
+						norm, n, i, buf[i], src[i])
+				}
+			}
+		}
+	}
+}
+
+func TestDST3Plan_InvertsDST2(t *testing.T) {
+	n := 9
+	src := make([]float64, n)
+	for i := range n {
+		src[i] = math.Exp(-float64(i)/4) * math.Sin(float64(i))
+	}
+
+	coeffs := make([]float64, n)
+	if err := DST2Forward(coeffs, src); err != nil {
+		t.Fatalf("DST2Forward failed: %v", err)
+	}
+
+	back := make([]float64, n)
+	if err := DST3Forward(back, coeffs); err != nil {
+		t.Fatalf("DST3Forward failed: %v", err)
+	}
+
+	// DST-III(DST-II(x)) = N/2 x.
+	for i := range n {
+		if want := float64(n) / 2 * src[i]; math.Abs(back[i]-want) > tolerance {
+			t.Errorf("DST3(DST2(x))[%d] = %v, want %v", i, back[i], want)
+		}
+	}
+
+	// DST3Inverse undoes DST3Forward, recovering the DST-II coefficients.
+	if err := DST3Inverse(back, back); err != nil {
+		t.Fatalf("DST3Inverse failed: %v", err)
+	}
+	for i := range n {
+		if math.Abs(back[i]-coeffs[i]) > tolerance {
+			t.Errorf("DST3Inverse[%d] = %v, want %v", i, back[i], coeffs[i])
+		}
+	}
+}
+
+// The DST-III basis diagonalizes the 3-point Laplacian with u = 0 left of
+// point 0 and a zero-slope (mirror) boundary right of point N-1.
+func TestDST3Plan_DirichletNeumannEigenvectors(t *testing.T) {
+	n := 7
+	for k := range n {
+		v := make([]float64, n)
+		for i := range n {
+			v[i] = DST3Coefficient(i, k, n)
+		}
+
+		lambda := 2 - 2*math.Cos(math.Pi*(float64(k)+0.5)/float64(n))
+		for i := range n {
+			left, right := 0.0, v[n-2]
+			if i > 0 {
+				left = v[i-1]
+			}
+			if i < n-1 {
+				right = v[i+1]
+			}
+
+			if lap := 2*v[i] - left - right; math.Abs(lap-lambda*v[i]) > tolerance {
+				t.Errorf("mode %d at %d: -Δv = %v, want %v", k, i, lap, lambda*v[i])
+			}
+		}
+	}
+}
+
+func TestDST3Plan_Errors(t *testing.T) {
+	if _, err := NewDST3Plan(0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewDST3Plan(0) = %v, want ErrInvalidSize", err)
+	}
+
+	plan, err := NewDST3Plan(4)
+	if err != nil {
+		t.Fatalf("NewDST3Plan failed: %v", err)
+	}
+	if err := plan.Forward(make([]float64, 4), make([]float64, 3)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Forward with short src = %v, want ErrSizeMismatch", err)
+	}
+	if err := plan.Inverse(make([]float64, 3), make([]float64, 4)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Inverse with short dst = %v, want ErrSizeMismatch", err)
+	}
+	if plan.Len() != 4 || plan.NormalizationFactor() != 1 || plan.Bytes() <= 0 {
+		t.Errorf("Len = %d, NormalizationFactor = %v, Bytes = %d", plan.Len(), plan.NormalizationFactor(), plan.Bytes())
+	}
+}
+
+func TestDST4Plan_KnownValues(t *testing.T) {
+	n := 8
+
+	plan, err := NewDST4Plan(n)
+	if err != nil {
+		t.Fatalf("NewDST4Plan failed: %v", err)
+	}
+
+	k := 5
+	src := make([]float64, n)
+	for i := range n {
+		src[i] = DST4Coefficient(i, k, n)
+	}
+
+	dst := make([]float64, n)
+	if err := plan.Forward(dst, src); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+
+	for j := range n {
+		expected := 0.0
+		if j == k {
+			expected = float64(n) / 2.0
+		}
+		if math.Abs(dst[j]-expected) > tolerance {
+			t.Errorf("dst4[%d] = %v, want %v", j, dst[j], expected)
+		}
+	}
+}
+
+func TestDST4Plan_RoundTrip(t *testing.T) {
+	for _, norm := range []Normalization{NormBackward, NormOrtho} {
+		for _, n := range []int{1, 2, 5, 16, 33} {
+			plan, err := NewDST4Plan(n, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("NewDST4Plan(%d) failed: %v", n, err)
+			}
+
+			src := make([]float64, n)
+			for i := range n {
+				src[i] = math.Cos(float64(i)*0.4) * float64(i+1)
+			}
+			buf := append([]float64(nil), src...)
+
+			if err := plan.Forward(buf, buf); err != nil {
+				t.Fatalf("Forward failed: %v", err)
+			}
+			if err := plan.Inverse(buf, buf); err != nil {
+				t.Fatalf("Inverse failed: %v", err)
+			}
+
+			for i := range n {
+				if math.Abs(buf[i]-src[i]) > tolerance {
+					t.Errorf("norm %d, n=%d: round-trip mismatch at [%d]: got %v, want %v",
+						norm, n, i, buf[i], src[i])
+				}
+			}
+		}
+	}
+}
+
+func TestDST4_OneShotAndErrors(t *testing.T) {
+	src := []float64{2, -1, 0.5, 0, 1.5, -0.25, 3}
+	dst := make([]float64, len(src))
+	if err := DST4(dst, src); err != nil {
+		t.Fatalf("DST4 failed: %v", err)
+	}
+	if err := DST4Inverse(dst, dst); err != nil {
+		t.Fatalf("DST4Inverse failed: %v", err)
+	}
+	for i := range src {
+		if math.Abs(dst[i]-src[i]) > tolerance {
+			t.Errorf("one-shot round-trip mismatch at [%d]: got %v, want %v", i, dst[i], src[i])
+		}
+	}
+
+	if _, err := NewDST4Plan(0); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("NewDST4Plan(0) = %v, want ErrInvalidSize", err)
+	}
+	plan, err := NewDST4Plan(4)
+	if err != nil {
+		t.Fatalf("NewDST4Plan failed: %v", err)
+	}
+	if err := plan.Inverse(make([]float64, 3), make([]float64, 4)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Inverse with short dst = %v, want ErrSizeMismatch", err)
+	}
+	if plan.Len() != 4 || plan.NormalizationFactor() != 1 || plan.Bytes() <= 0 {
+		t.Errorf("Len = %d, NormalizationFactor = %v, Bytes = %d", plan.Len(), plan.NormalizationFactor(), plan.Bytes())
+	}
+}
+
 func BenchmarkDSTPlan_Forward(b *testing.B) {
 	sizes := []int{64, 256, 1024}
 
@@ -504,6 +808,71 @@ func dst2Reference(dst, src []float64) {
 	}
 }
 
+// BenchmarkDST2Plan_Inverse compares the FFT-based inverse with the O(N²)
+// weighted transpose it replaced; at N=4096 the transpose costs 16M
+// multiply-adds per line.
+func BenchmarkDST2Plan_Inverse(b *testing.B) {
+	for _, n := range []int{64, 1024, 4096} {
+		plan, err := NewDST2Plan(n)
+		if err != nil {
+			b.Fatalf("NewDST2Plan failed: %v", err)
+		}
+
+		src := make([]float64, n)
+		dst := make([]float64, n)
+		for i := range n {
+			src[i] = float64(i)
+		}
+
+		b.Run("fft-"+sizeStr(n), func(b *testing.B) {
+			for range b.N {
+				_ = plan.Inverse(dst, src)
+			}
+		})
+
+		b.Run("transpose-"+sizeStr(n), func(b *testing.B) {
+			for range b.N {
+				dst2TransposeInverse(dst, src, NormBackward)
+			}
+		})
+	}
+}
+
+// dst2TransposeInverse inverts DST-II by the weighted transpose of its
+// kernel, in O(N²).
+func dst2TransposeInverse(dst, src []float64, norm Normalization) {
+	n := len(src)
+	for i := range n {
+		sum := 0.0
+		for k := range n {
+			weight := 2.0 / float64(n)
+			if k == n-1 {
+				weight = 1.0 / float64(n)
+			}
+			if norm == NormOrtho {
+				weight = math.Sqrt(weight)
+			}
+			sum += src[k] * weight * DST2Coefficient(i, k, n)
+		}
+		dst[i] = sum
+	}
+}
+
+func dst3Reference(dst, src []float64) {
+	n := len(src)
+	for k := range n {
+		sum := 0.0
+		for i := range n {
+			weight := 1.0
+			if i == n-1 {
+				weight = 0.5
+			}
+			sum += weight * src[i] * DST3Coefficient(i, k, n)
+		}
+		dst[k] = sum
+	}
+}
+
 func sizeStr(n int) string {
 	if n >= 1024 {
 		return itoa(n/1024) + "K"
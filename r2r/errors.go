@@ -1,6 +1,10 @@
 package r2r
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"math"
+)
 
 var (
 	// ErrInvalidSize is returned when the transform size is invalid.
@@ -8,4 +12,40 @@ var (
 
 	// ErrSizeMismatch is returned when buffer sizes don't match the plan.
 	ErrSizeMismatch = errors.New("buffer size mismatch")
+
+	// ErrNonFinite is returned by plans created with WithCheckFinite when
+	// the input holds a NaN or an infinity.
+	ErrNonFinite = errors.New("non-finite input")
 )
+
+// NonFiniteError reports the first NaN or infinite input value found by
+// WithCheckFinite. It wraps ErrNonFinite, so errors.Is(err, ErrNonFinite)
+// works.
+type NonFiniteError struct {
+	// Index is the position of the value in the input slice.
+	Index int
+
+	// Value is the offending value.
+	Value float64
+}
+
+func (e *NonFiniteError) Error() string {
+	return fmt.Sprintf("%v: src[%d] = %v", ErrNonFinite, e.Index, e.Value)
+}
+
+// Unwrap returns ErrNonFinite.
+func (e *NonFiniteError) Unwrap() error {
+	return ErrNonFinite
+}
+
+// checkFinite returns a *NonFiniteError for the first NaN or infinity in
+// src.
+func checkFinite(src []float64) error {
+	for i, v := range src {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return &NonFiniteError{Index: i, Value: v}
+		}
+	}
+
+	return nil
+}
@@ -61,9 +61,8 @@ func (p *DCTPlan) InverseLines(data []float64, shape grid.Shape, axis int) error
 func transformAllLines(
 	data []float64, shape grid.Shape, axis int, transform transformFunc,
 ) error {
-	it := grid.NewLineIterator(shape, axis)
-	lineLen := it.LineLength()
-	lineStride := it.LineStride()
+	lineLen := shape[axis]
+	lineStride := grid.RowMajorStride(shape)[axis]
 
 	// Allocate temporary buffer for non-contiguous lines
 	var buf []float64
@@ -71,14 +70,8 @@ func transformAllLines(
 		buf = make([]float64, lineLen)
 	}
 
-	// Process first line (iterator starts at position 0)
-	if err := processOneLine(data, it.StartIndex(), lineLen, lineStride, buf, transform); err != nil {
-		return err
-	}
-
-	// Process remaining lines
-	for it.Next() {
-		if err := processOneLine(data, it.StartIndex(), lineLen, lineStride, buf, transform); err != nil {
+	for start := range shape.Lines(axis) {
+		if err := processOneLine(data, start, lineLen, lineStride, buf, transform); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,227 @@
+package r2r
+
+import "math"
+
+// MDCTPlan is a pre-computed Modified Discrete Cosine Transform plan.
+//
+// The MDCT maps a block of 2N windowed samples to N coefficients:
+//
+//	X[k] = Σ w[n] x[n] * cos(π(n+1/2+N/2)(k+1/2)/N) for n = 0..2N-1
+//
+// and the IMDCT maps N coefficients back to 2N windowed samples,
+//
+//	y[n] = w[n] * Σ X[k] * cos(π(n+1/2+N/2)(k+1/2)/N) for k = 0..N-1.
+//
+// A single block does not invert (the IMDCT output is time-aliased), but
+// for blocks advanced by N samples and a window with
+// w[n]² + w[n+N]² = 1 (Princen-Bradley; see SineWindow and KBDWindow), the
+// aliasing of overlapping halves cancels: summing the Inverse outputs with
+// an overlap of N reconstructs the signal up to NormalizationFactor.
+// With NormOrtho this is an orthogonal lapped transform.
+//
+// Both directions fold the block into N points and cost one DCT-IV.
+//
+// Thread safety: A single MDCTPlan instance is NOT safe for concurrent use.
+// For parallel transforms, Clone the plan for each goroutine.
+type MDCTPlan struct {
+	n      int
+	opts   Options
+	dct4   *DCT4Plan
+	window []float64 // 2N analysis/synthesis window, nil for none
+	fold   []float64 // N-point DCT-IV buffer
+}
+
+// NewMDCTPlan creates a new MDCT plan with n coefficients per block of 2n
+// samples. The size n must be even and at least 2. window has length 2n
+// and is applied to the input of Forward and the output of Inverse; nil
+// means no window (which does not cancel the aliasing).
+func NewMDCTPlan(n int, window []float64, opts ...Option) (*MDCTPlan, error) {
+	if n < 2 || n%2 != 0 {
+		return nil, ErrInvalidSize
+	}
+
+	if window != nil && len(window) != 2*n {
+		return nil, ErrSizeMismatch
+	}
+
+	// The folding applies the plan's own scaling.
+	dct4, err := NewDCT4Plan(n, WithNormalization(NormUnscaled))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MDCTPlan{
+		n:      n,
+		opts:   applyOptions(opts),
+		dct4:   dct4,
+		window: window,
+		fold:   make([]float64, n),
+	}, nil
+}
+
+// Len returns the number of coefficients N; a block has 2N samples.
+func (p *MDCTPlan) Len() int {
+	return p.n
+}
+
+// Forward computes the MDCT of the 2N-sample block src into the N
+// coefficients dst.
+//
+// Output normalization: The output is NOT normalized. With NormOrtho it is
+// scaled by sqrt(2/N).
+//
+// Splitting the windowed block into quarters (a, b, c, d) of N/2 samples,
+// the MDCT is the DCT-IV of (-c_r - d, a - b_r), where _r reverses a quarter.
+func (p *MDCTPlan) Forward(dst, src []float64) error {
+	if len(dst) != p.n || len(src) != 2*p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	h := p.n / 2
+	for i := range h {
+		p.fold[i] = -p.sample(src, 3*h-1-i) - p.sample(src, 3*h+i)
+		p.fold[h+i] = p.sample(src, i) - p.sample(src, p.n-1-i)
+	}
+
+	if err := p.dct4.Forward(dst, p.fold); err != nil {
+		return err
+	}
+
+	if scale := p.scale(); scale != 1 {
+		for k := range dst {
+			dst[k] *= scale
+		}
+	}
+
+	return nil
+}
+
+// Inverse computes the IMDCT of the N coefficients src into the 2N-sample
+// block dst, windowed for overlap-add.
+//
+// Note: with NormBackward the output is scaled by 2/N, so that overlap-adding
+// consecutive blocks with a Princen-Bradley window returns the signal; with
+// NormUnscaled it is unscaled, and with NormOrtho scaled by sqrt(2/N).
+//
+// The DCT-IV u of src extends to the IMDCT by its symmetries u[2N-1-m] = -u[m]
+// and u[m+2N] = -u[m]: y[n] = u[n+N/2] for the shifted index.
+func (p *MDCTPlan) Inverse(dst, src []float64) error {
+	if len(dst) != 2*p.n || len(src) != p.n {
+		return ErrSizeMismatch
+	}
+
+	if err := p.opts.checkFinite(src); err != nil {
+		return err
+	}
+
+	if err := p.dct4.Forward(p.fold, src); err != nil {
+		return err
+	}
+
+	scale := p.scale() * p.opts.inverseScale(float64(p.n)/2)
+
+	n := p.n
+	for i := range dst {
+		var v float64
+
+		switch m := i + n/2; {
+		case m < n:
+			v = p.fold[m]
+		case m < 2*n:
+			v = -p.fold[2*n-1-m]
+		default:
+			v = -p.fold[m-2*n]
+		}
+
+		if p.window != nil {
+			v *= p.window[i]
+		}
+
+		dst[i] = v * scale
+	}
+
+	return nil
+}
+
+// sample returns the windowed input sample i.
+func (p *MDCTPlan) sample(src []float64, i int) float64 {
+	if p.window == nil {
+		return src[i]
+	}
+
+	return p.window[i] * src[i]
+}
+
+// scale returns the factor sqrt(2/N) of NormOrtho, applied in both
+// directions, or 1.
+func (p *MDCTPlan) scale() float64 {
+	if p.opts.Normalization == NormOrtho {
+		return math.Sqrt(2.0 / float64(p.n))
+	}
+
+	return 1.0
+}
+
+// NormalizationFactor returns the factor by which the overlap-added
+// Inverse outputs of consecutive Forward blocks are scaled, for a
+// Princen-Bradley window.
+// For the MDCT: N/2 with NormUnscaled, 1 otherwise.
+func (p *MDCTPlan) NormalizationFactor() float64 {
+	return p.opts.normalizationFactor(float64(p.n) / 2)
+}
+
+// Bytes returns the memory used by the plan in bytes.
+func (p *MDCTPlan) Bytes() int {
+	return p.dct4.Bytes() + len(p.window)*8 + len(p.fold)*8
+}
+
+// SineWindow returns the 2n-point sine window sin(π(i+1/2)/(2n)), the
+// simplest window satisfying the Princen-Bradley condition.
+func SineWindow(n int) []float64 {
+	window := make([]float64, 2*n)
+	for i := range window {
+		window[i] = math.Sin(math.Pi * (float64(i) + 0.5) / float64(2*n))
+	}
+
+	return window
+}
+
+// KBDWindow returns the 2n-point Kaiser-Bessel-derived window with shape
+// parameter alpha (4 in AC-3, 4 or 6 in AAC). Larger alpha trade a wider
+// main lobe for lower sidelobes. The window satisfies the Princen-Bradley
+// condition.
+func KBDWindow(n int, alpha float64) []float64 {
+	// Cumulative sums of the (n+1)-point Kaiser window.
+	cum := make([]float64, n+1)
+	sum := 0.0
+	for j := range cum {
+		r := 2*float64(j)/float64(n) - 1
+		sum += besselI0(math.Pi * alpha * math.Sqrt(1-r*r))
+		cum[j] = sum
+	}
+
+	window := make([]float64, 2*n)
+	for i := range n {
+		window[i] = math.Sqrt(cum[i] / sum)
+		window[2*n-1-i] = window[i]
+	}
+
+	return window
+}
+
+// besselI0 returns the modified Bessel function of the first kind of order
+// zero, summing its power series to full precision.
+func besselI0(x float64) float64 {
+	sum, term := 1.0, 1.0
+	q := x * x / 4
+	for k := 1; term > sum*1e-17; k++ {
+		term *= q / float64(k*k)
+		sum += term
+	}
+
+	return sum
+}
@@ -0,0 +1,166 @@
+package r2r
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMDCTPlan_MatchesDirectSums(t *testing.T) {
+	for _, n := range []int{2, 4, 6, 16} {
+		window := KBDWindow(n, 4)
+
+		plan, err := NewMDCTPlan(n, window, WithNormalization(NormUnscaled))
+		if err != nil {
+			t.Fatalf("NewMDCTPlan(%d) failed: %v", n, err)
+		}
+
+		src := make([]float64, 2*n)
+		for i := range src {
+			src[i] = math.Sin(float64(i)*0.8) + 0.1*float64(i)
+		}
+
+		got := make([]float64, n)
+		if err := plan.Forward(got, src); err != nil {
+			t.Fatalf("Forward failed: %v", err)
+		}
+
+		for k := range n {
+			want := 0.0
+			for i := range 2 * n {
+				want += window[i] * src[i] * MDCTCoefficient(i, k, n)
+			}
+
+			if math.Abs(got[k]-want) > tolerance*float64(n) {
+				t.Errorf("n=%d: X[%d] = %v, want %v", n, k, got[k], want)
+			}
+		}
+
+		coeffs := append([]float64(nil), got...)
+		block := make([]float64, 2*n)
+		if err := plan.Inverse(block, coeffs); err != nil {
+			t.Fatalf("Inverse failed: %v", err)
+		}
+
+		for i := range 2 * n {
+			want := 0.0
+			for k := range n {
+				want += coeffs[k] * MDCTCoefficient(i, k, n)
+			}
+			want *= window[i]
+
+			if math.Abs(block[i]-want) > tolerance*float64(n*n) {
+				t.Errorf("n=%d: y[%d] = %v, want %v", n, i, block[i], want)
+			}
+		}
+	}
+}
+
+// Overlap-adding the Inverse of consecutive half-overlapping blocks cancels
+// the time-domain aliasing, so every sample covered by two blocks returns.
+func TestMDCTPlan_OverlapAddReconstructs(t *testing.T) {
+	const n, blocks = 16, 5
+
+	windows := map[string][]float64{"sine": SineWindow(n), "KBD": KBDWindow(n, 6)}
+
+	for name, window := range windows {
+		for _, norm := range []Normalization{NormUnscaled, NormBackward, NormOrtho} {
+			plan, err := NewMDCTPlan(n, window, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("NewMDCTPlan failed: %v", err)
+			}
+
+			signal := make([]float64, (blocks+1)*n)
+			for i := range signal {
+				signal[i] = math.Cos(float64(i)*0.37) * math.Exp(-float64(i)/80)
+			}
+
+			out := make([]float64, len(signal))
+			coeffs := make([]float64, n)
+			block := make([]float64, 2*n)
+			for b := range blocks {
+				if err := plan.Forward(coeffs, signal[b*n:(b+2)*n]); err != nil {
+					t.Fatalf("Forward failed: %v", err)
+				}
+
+				if err := plan.Inverse(block, coeffs); err != nil {
+					t.Fatalf("Inverse failed: %v", err)
+				}
+
+				for i, v := range block {
+					out[b*n+i] += v
+				}
+			}
+
+			factor := plan.NormalizationFactor()
+			for i := n; i < blocks*n; i++ {
+				if want := factor * signal[i]; math.Abs(out[i]-want) > tolerance*factor {
+					t.Errorf("%s window, norm %d: sample %d = %v, want %v", name, norm, i, out[i], want)
+				}
+			}
+		}
+	}
+}
+
+func TestMDCTWindows_PrincenBradley(t *testing.T) {
+	const n = 32
+
+	for name, window := range map[string][]float64{"sine": SineWindow(n), "KBD": KBDWindow(n, 4)} {
+		for i := range n {
+			if got := window[i]*window[i] + window[i+n]*window[i+n]; math.Abs(got-1) > tolerance {
+				t.Errorf("%s: w[%d]² + w[%d]² = %v, want 1", name, i, i+n, got)
+			}
+
+			if math.Abs(window[i]-window[2*n-1-i]) > tolerance {
+				t.Errorf("%s: window not symmetric at %d", name, i)
+			}
+		}
+	}
+}
+
+func TestMDCTPlan_Errors(t *testing.T) {
+	for _, n := range []int{0, 3} {
+		if _, err := NewMDCTPlan(n, nil); !errors.Is(err, ErrInvalidSize) {
+			t.Errorf("NewMDCTPlan(%d): got %v, want ErrInvalidSize", n, err)
+		}
+	}
+
+	if _, err := NewMDCTPlan(4, SineWindow(3)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("NewMDCTPlan with a short window: got %v, want ErrSizeMismatch", err)
+	}
+
+	plan, err := NewMDCTPlan(4, nil)
+	if err != nil {
+		t.Fatalf("NewMDCTPlan failed: %v", err)
+	}
+
+	if err := plan.Forward(make([]float64, 4), make([]float64, 4)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Forward of a short block: got %v, want ErrSizeMismatch", err)
+	}
+
+	if err := plan.Inverse(make([]float64, 4), make([]float64, 4)); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("Inverse into a short block: got %v, want ErrSizeMismatch", err)
+	}
+}
+
+func BenchmarkMDCTPlan_Forward(b *testing.B) {
+	for _, n := range []int{256, 1024} {
+		b.Run(sizeStr(n), func(b *testing.B) {
+			plan, err := NewMDCTPlan(n, SineWindow(n))
+			if err != nil {
+				b.Fatalf("NewMDCTPlan failed: %v", err)
+			}
+
+			src := make([]float64, 2*n)
+			for i := range src {
+				src[i] = float64(i % 17)
+			}
+			dst := make([]float64, n)
+
+			b.ResetTimer()
+			for range b.N {
+				_ = plan.Forward(dst, src)
+			}
+		})
+	}
+}
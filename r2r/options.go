@@ -3,16 +3,50 @@ package r2r
 // Normalization controls output scaling of transforms.
 type Normalization int
 
+// Every plan has a round-trip factor f, reported by NormalizationFactor
+// under NormUnscaled: f = (N+1)/2 for DST-I, 2(N-1) for DCT-I, N for the DHT
+// and N/2 for the types II, III and IV. The normalizations split 1/f between
+// the two directions:
+//
+//	             Forward        Inverse        Inverse(Forward(x))
+//	NormNone     T              T⁻¹            x
+//	NormBackward T              T⁻¹            x
+//	NormOrtho    T/√f           T⁻¹·√f         x
+//	NormUnscaled T              T⁻¹·f          f·x
+//
+// where T is the unscaled matrix of the plan's transform and T⁻¹·f that of
+// its inverse type (see the package documentation). NormOrtho makes every
+// type orthonormal except DCT-I, whose endpoint weights leave the scaled
+// matrix self-inverse but not symmetric.
 const (
-	// NormNone leaves outputs unnormalized (default).
+	// NormNone leaves Forward unscaled and scales Inverse by 1/f, so
+	// Inverse undoes Forward exactly. It is the same as NormBackward.
 	NormNone Normalization = iota
 	// NormOrtho applies orthonormal scaling.
 	NormOrtho
+	// NormBackward (the default) leaves Forward unscaled and scales
+	// Inverse by 1/f, so Inverse undoes Forward exactly.
+	NormBackward
+	// NormUnscaled leaves both directions unscaled, so Inverse(Forward(x))
+	// is f·x.
+	NormUnscaled
 )
 
 // Options configures transform plans.
 type Options struct {
 	Normalization Normalization
+
+	// Workers is the number of goroutines ForwardMany and InverseMany
+	// split a batch over (default 1).
+	Workers int
+
+	// Cache makes the constructors return clones of cached plans; see
+	// WithPlanCache.
+	Cache bool
+
+	// CheckFinite makes Forward and Inverse reject NaN and infinite
+	// inputs; see WithCheckFinite.
+	CheckFinite bool
 }
 
 // Option applies a configuration option.
@@ -20,7 +54,7 @@ type Option func(*Options)
 
 // DefaultOptions returns default options.
 func DefaultOptions() Options {
-	return Options{Normalization: NormNone}
+	return Options{Normalization: NormBackward, Workers: 1}
 }
 
 // WithNormalization sets the transform normalization.
@@ -30,6 +64,68 @@ func WithNormalization(norm Normalization) Option {
 	}
 }
 
+// WithWorkers sets the number of goroutines ForwardMany and InverseMany
+// split a batch over. Each worker beyond the first gets its own FFT buffers;
+// the twiddle tables are shared. Values below 1 mean 1.
+func WithWorkers(n int) Option {
+	return func(o *Options) {
+		o.Workers = max(n, 1)
+	}
+}
+
+// WithPlanCache makes the plan constructors share their precomputed data:
+// the first plan of each transform, size and set of options is kept in a
+// package-wide cache, and later constructions with the same arguments
+// return a Clone of it, with its own FFT buffers and scratch but sharing
+// the cached twiddle tables instead of recomputing them. This helps code that creates many short-lived plans of
+// the same sizes. Cached plans stay alive until ClearPlanCache. MDCTPlan,
+// whose window is caller data, is not cached.
+func WithPlanCache() Option {
+	return func(o *Options) {
+		o.Cache = true
+	}
+}
+
+// WithCheckFinite makes Forward, Inverse and their batched forms scan the
+// input and return a *NonFiniteError (wrapping ErrNonFinite) for the first
+// NaN or infinity instead of spreading it over every output. The scan
+// costs one pass over the input.
+func WithCheckFinite() Option {
+	return func(o *Options) {
+		o.CheckFinite = true
+	}
+}
+
+// checkFinite scans src when CheckFinite is set.
+func (o Options) checkFinite(src []float64) error {
+	if !o.CheckFinite {
+		return nil
+	}
+
+	return checkFinite(src)
+}
+
+// inverseScale returns the factor an unscaled inverse kernel with
+// round-trip factor f is multiplied by in Inverse: 1/f with NormNone and
+// NormBackward, and 1 otherwise (NormOrtho scales both kernels by 1/√f
+// itself).
+func (o Options) inverseScale(f float64) float64 {
+	if o.Normalization == NormNone || o.Normalization == NormBackward {
+		return 1 / f
+	}
+
+	return 1
+}
+
+// normalizationFactor returns f with NormUnscaled and 1 otherwise.
+func (o Options) normalizationFactor(f float64) float64 {
+	if o.Normalization == NormUnscaled {
+		return f
+	}
+
+	return 1
+}
+
 func applyOptions(opts []Option) Options {
 	base := DefaultOptions()
 	for _, opt := range opts {
@@ -0,0 +1,188 @@
+package r2r
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type normPlan interface {
+	r2rPlan
+	NormalizationFactor() float64
+}
+
+var normTransforms = []struct {
+	name    string
+	plan    func(n int, opts ...Option) (normPlan, error)
+	inverse func(dst, src []float64) // the unscaled inverse kernel
+}{
+	{"DCT-I", func(n int, opts ...Option) (normPlan, error) { return NewDCTPlan(n, opts...) }, dct1Reference},
+	{"DCT-II", func(n int, opts ...Option) (normPlan, error) { return NewDCT2Plan(n, opts...) }, dct3Reference},
+	{"DCT-III", func(n int, opts ...Option) (normPlan, error) { return NewDCT3Plan(n, opts...) }, dct2Reference},
+	{"DCT-IV", func(n int, opts ...Option) (normPlan, error) { return NewDCT4Plan(n, opts...) }, kernelReference(DCT4Coefficient)},
+	{"DST-I", func(n int, opts ...Option) (normPlan, error) { return NewDSTPlan(n, opts...) }, kernelReference(DST1Coefficient)},
+	{"DST-II", func(n int, opts ...Option) (normPlan, error) { return NewDST2Plan(n, opts...) }, dst3Reference},
+	{"DST-III", func(n int, opts ...Option) (normPlan, error) { return NewDST3Plan(n, opts...) }, dst2Reference},
+	{"DST-IV", func(n int, opts ...Option) (normPlan, error) { return NewDST4Plan(n, opts...) }, kernelReference(DST4Coefficient)},
+	{"DHT", func(n int, opts ...Option) (normPlan, error) { return NewDHTPlan(n, opts...) }, kernelReference(DHTCoefficient)},
+}
+
+func TestNormUnscaled_InverseIsUnscaledKernel(t *testing.T) {
+	for _, tr := range normTransforms {
+		for _, n := range []int{7, 8} {
+			plan, err := tr.plan(n, WithNormalization(NormUnscaled))
+			if err != nil {
+				t.Fatalf("%s: creating plan failed: %v", tr.name, err)
+			}
+
+			src := make([]float64, n)
+			for i := range src {
+				src[i] = math.Cos(float64(i)*0.9) - 0.1*float64(i)
+			}
+
+			want := make([]float64, n)
+			tr.inverse(want, src)
+
+			got := make([]float64, n)
+			if err := plan.Inverse(got, src); err != nil {
+				t.Fatalf("%s: Inverse failed: %v", tr.name, err)
+			}
+
+			for i := range n {
+				if math.Abs(got[i]-want[i]) > tolerance*float64(n) {
+					t.Errorf("%s n=%d: Inverse[%d] = %v, want %v", tr.name, n, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func TestNormalization_RoundTripFactor(t *testing.T) {
+	const n = 9
+
+	for _, tr := range normTransforms {
+		for _, norm := range []Normalization{NormNone, NormBackward, NormOrtho, NormUnscaled} {
+			plan, err := tr.plan(n, WithNormalization(norm))
+			if err != nil {
+				t.Fatalf("%s: creating plan failed: %v", tr.name, err)
+			}
+
+			src := make([]float64, n)
+			for i := range src {
+				src[i] = math.Sin(float64(i)*0.4) + 1
+			}
+
+			got := make([]float64, n)
+			if err := plan.Forward(got, src); err != nil {
+				t.Fatalf("%s: Forward failed: %v", tr.name, err)
+			}
+
+			if err := plan.Inverse(got, got); err != nil {
+				t.Fatalf("%s: Inverse failed: %v", tr.name, err)
+			}
+
+			factor := plan.NormalizationFactor()
+			if norm != NormUnscaled && factor != 1 {
+				t.Errorf("%s norm %d: NormalizationFactor = %v, want 1", tr.name, norm, factor)
+			}
+
+			for i := range n {
+				if want := factor * src[i]; math.Abs(got[i]-want) > tolerance*factor {
+					t.Errorf("%s norm %d: round trip [%d] = %v, want %v", tr.name, norm, i, got[i], want)
+				}
+			}
+		}
+	}
+}
+
+func TestNormNone_MatchesNormBackward(t *testing.T) {
+	const n = 10
+
+	for _, tr := range normTransforms {
+		none, err := tr.plan(n, WithNormalization(NormNone))
+		if err != nil {
+			t.Fatalf("%s: creating plan failed: %v", tr.name, err)
+		}
+		backward, err := tr.plan(n)
+		if err != nil {
+			t.Fatalf("%s: creating plan failed: %v", tr.name, err)
+		}
+
+		src := make([]float64, n)
+		for i := range src {
+			src[i] = math.Cos(float64(i)*1.3) + 0.2
+		}
+
+		got := make([]float64, n)
+		want := make([]float64, n)
+		if err := none.Inverse(got, src); err != nil {
+			t.Fatalf("%s: Inverse failed: %v", tr.name, err)
+		}
+		if err := backward.Inverse(want, src); err != nil {
+			t.Fatalf("%s: Inverse failed: %v", tr.name, err)
+		}
+
+		for i := range n {
+			if got[i] != want[i] {
+				t.Errorf("%s: NormNone Inverse[%d] = %v, NormBackward %v", tr.name, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestDefaultOptions_NormBackward(t *testing.T) {
+	if got := DefaultOptions().Normalization; got != NormBackward {
+		t.Errorf("default normalization = %d, want NormBackward", got)
+	}
+}
+
+func TestWithCheckFinite(t *testing.T) {
+	const n = 8
+
+	for _, tr := range normTransforms {
+		plan, err := tr.plan(n, WithCheckFinite())
+		if err != nil {
+			t.Fatalf("%s: creating plan failed: %v", tr.name, err)
+		}
+
+		src := make([]float64, n)
+		dst := make([]float64, n)
+		if err := plan.Forward(dst, src); err != nil {
+			t.Errorf("%s: Forward of finite input failed: %v", tr.name, err)
+		}
+
+		src[5] = math.NaN()
+		for _, transform := range []func(dst, src []float64) error{plan.Forward, plan.Inverse} {
+			err := transform(dst, src)
+
+			var nonFinite *NonFiniteError
+			if !errors.As(err, &nonFinite) || !errors.Is(err, ErrNonFinite) || nonFinite.Index != 5 {
+				t.Errorf("%s: got %v, want a NonFiniteError at index 5", tr.name, err)
+			}
+		}
+
+		unchecked, err := tr.plan(n)
+		if err != nil {
+			t.Fatalf("%s: creating plan failed: %v", tr.name, err)
+		}
+
+		if err := unchecked.Forward(dst, src); err != nil {
+			t.Errorf("%s: Forward without WithCheckFinite failed: %v", tr.name, err)
+		}
+	}
+}
+
+func TestWithCheckFinite_BatchIndex(t *testing.T) {
+	plan, err := NewDCT2Plan(4, WithCheckFinite(), WithWorkers(2))
+	if err != nil {
+		t.Fatalf("NewDCT2Plan failed: %v", err)
+	}
+
+	buf := make([]float64, 4*3)
+	buf[9] = math.Inf(-1)
+
+	var nonFinite *NonFiniteError
+	if err := plan.ForwardMany(buf, buf, 3); !errors.As(err, &nonFinite) || nonFinite.Index != 9 {
+		t.Errorf("ForwardMany: got %v, want a NonFiniteError at index 9", err)
+	}
+}
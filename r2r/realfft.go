@@ -0,0 +1,273 @@
+package r2r
+
+import (
+	"fmt"
+	"math"
+
+	algofft "github.com/MeKo-Christian/algo-fft"
+)
+
+// realFFT is the FFT of a real sequence of length n that the r2r plans are
+// built on. For power-of-two n it is the real-to-complex FFT of algo-fft,
+// which costs about as much as an n/2-point complex FFT. Other n fall back
+// to an n-point complex FFT: algo-fft does not support odd lengths, and its
+// real FFT returns wrong spectra for even lengths that are not powers of two
+// (e.g. 12, 20 or 100). Either way only the half spectrum of n/2+1 modes is
+// exposed.
+type realFFT struct {
+	n int
+
+	// Power-of-two n: the real FFT plan.
+	realPlan *algofft.PlanRealT[float64, complex128]
+
+	// Other n: the complex FFT plan and its full-spectrum buffers.
+	cplxPlan *algofft.Plan[complex128]
+	cplxIn   []complex128
+	cplxOut  []complex128
+
+	in   []float64    // real sequence
+	spec []complex128 // half spectrum, modes 0..n/2
+}
+
+func newRealFFT(n int) (*realFFT, error) {
+	r := &realFFT{
+		n:    n,
+		in:   make([]float64, n),
+		spec: make([]complex128, n/2+1),
+	}
+
+	if n >= 2 && n&(n-1) == 0 {
+		plan, err := algofft.NewPlanReal64(n)
+		if err != nil {
+			return nil, fmt.Errorf("creating real FFT plan: %w", err)
+		}
+		r.realPlan = plan
+
+		return r, nil
+	}
+
+	plan, err := algofft.NewPlan64(n)
+	if err != nil {
+		return nil, fmt.Errorf("creating FFT plan: %w", err)
+	}
+	r.cplxPlan = plan
+	r.cplxIn = make([]complex128, n)
+	r.cplxOut = make([]complex128, n)
+
+	return r, nil
+}
+
+// forward transforms r.in into the half spectrum r.spec.
+func (r *realFFT) forward() error {
+	if r.realPlan != nil {
+		if err := r.realPlan.Forward(r.spec, r.in); err != nil {
+			return fmt.Errorf("FFT forward: %w", err)
+		}
+
+		return nil
+	}
+
+	for i, v := range r.in {
+		r.cplxIn[i] = complex(v, 0)
+	}
+
+	if err := r.cplxPlan.Forward(r.cplxOut, r.cplxIn); err != nil {
+		return fmt.Errorf("FFT forward: %w", err)
+	}
+
+	copy(r.spec, r.cplxOut)
+
+	return nil
+}
+
+// inverse transforms the half spectrum r.spec back into r.in, scaled by 1/n.
+func (r *realFFT) inverse() error {
+	// The DC and Nyquist modes of a real sequence are real; drop the
+	// rounding residue of the callers' twiddles, which the real inverse
+	// rejects beyond an absolute 1e-12.
+	r.spec[0] = complex(real(r.spec[0]), 0)
+
+	if r.realPlan != nil {
+		r.spec[r.n/2] = complex(real(r.spec[r.n/2]), 0)
+		if err := r.realPlan.Inverse(r.in, r.spec); err != nil {
+			return fmt.Errorf("FFT inverse: %w", err)
+		}
+
+		return nil
+	}
+
+	r.cplxIn[0] = r.spec[0]
+	for k := 1; k < len(r.spec); k++ {
+		r.cplxIn[k] = r.spec[k]
+		r.cplxIn[r.n-k] = complex(real(r.spec[k]), -imag(r.spec[k]))
+	}
+
+	if err := r.cplxPlan.Inverse(r.cplxOut, r.cplxIn); err != nil {
+		return fmt.Errorf("FFT inverse: %w", err)
+	}
+
+	for i := range r.in {
+		r.in[i] = real(r.cplxOut[i])
+	}
+
+	return nil
+}
+
+// bytes returns the size of the buffers.
+func (r *realFFT) bytes() int {
+	return len(r.in)*8 + len(r.spec)*16 + len(r.cplxIn)*16 + len(r.cplxOut)*16
+}
+
+// dct2FFT computes the DCT-II and its inverse with one n-point real FFT
+// (Makhoul's algorithm). With v[m] = x[2m] and v[n-1-m] = x[2m+1], and V the
+// FFT of v, the DCT-II X of x satisfies
+//
+//	X[k] - i X[n-k] = exp(-iπk/(2n)) V[k]   (X[n] = 0)
+//
+// so the half spectrum of V yields all n outputs, and the inverse runs the
+// same steps backwards. The DST-II is the DCT-II of the sign-alternated
+// sequence (-1)^m x[m] with its outputs reversed, so DST2Plan shares it.
+type dct2FFT struct {
+	n   int
+	fft *realFFT
+
+	phase []complex128 // exp(-iπk/(2n)) for k = 0..n/2
+
+	// s0 and s are the output scales of the DCT-II for the plan's
+	// normalization: s0 for mode 0 (the highest mode of the DST-II),
+	// s for the others.
+	s0, s float64
+}
+
+func newDCT2FFT(n int, norm Normalization) (*dct2FFT, error) {
+	fft, err := newRealFFT(n)
+	if err != nil {
+		return nil, err
+	}
+
+	phase := make([]complex128, n/2+1)
+	for k := range phase {
+		angle := -math.Pi * float64(k) / (2.0 * float64(n))
+		phase[k] = complex(math.Cos(angle), math.Sin(angle))
+	}
+
+	s0, s := 1.0, 1.0
+	if norm == NormOrtho {
+		s0, s = 1.0/math.Sqrt(float64(n)), math.Sqrt(2.0/float64(n))
+	}
+
+	return &dct2FFT{n: n, fft: fft, phase: phase, s0: s0, s: s}, nil
+}
+
+// clone returns a kernel with its own FFT buffers that shares the phase
+// factors of c.
+func (c *dct2FFT) clone() (*dct2FFT, error) {
+	fft, err := newRealFFT(c.n)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *c
+	clone.fft = fft
+
+	return &clone, nil
+}
+
+// forward computes the DCT-II of src into dst, or with sine set the DST-II,
+// times scale. src is read before dst is written.
+func (c *dct2FFT) forward(dst, src []float64, sine bool, scale float64) error {
+	n := c.n
+	v := c.fft.in
+	for i, x := range src {
+		if sine && i%2 == 1 {
+			x = -x
+		}
+
+		if i%2 == 0 {
+			v[i/2] = x
+		} else {
+			v[n-1-i/2] = x
+		}
+	}
+
+	if err := c.fft.forward(); err != nil {
+		return err
+	}
+
+	out := func(k int, value float64) {
+		if sine {
+			k = n - 1 - k
+		}
+		dst[k] = value
+	}
+
+	s0, s := c.s0*scale, c.s*scale
+	for k := 0; k <= n/2; k++ {
+		z := c.phase[k] * c.fft.spec[k]
+		if k == 0 {
+			out(0, real(z)*s0)
+			continue
+		}
+
+		out(k, real(z)*s)
+		out(n-k, -imag(z)*s)
+	}
+
+	return nil
+}
+
+// inverse computes the inverse DCT-II of src into dst, or with sine set
+// the inverse DST-II, times scale. src is read before dst is written.
+func (c *dct2FFT) inverse(dst, src []float64, sine bool, scale float64) error {
+	n := c.n
+	w0, w := scale/c.s0, scale/c.s
+
+	coeff := func(k int) float64 {
+		if k == n {
+			return 0
+		}
+
+		weight := w
+		if k == 0 {
+			weight = w0
+		}
+
+		if sine {
+			return src[n-1-k] * weight
+		}
+
+		return src[k] * weight
+	}
+
+	for k := 0; k <= n/2; k++ {
+		// V[k] = exp(iπk/(2n)) (X[k] - i X[n-k])
+		p := c.phase[k]
+		c.fft.spec[k] = complex(real(p), -imag(p)) * complex(coeff(k), -coeff(n-k))
+	}
+
+	if err := c.fft.inverse(); err != nil {
+		return err
+	}
+
+	v := c.fft.in
+	for i := range dst {
+		var x float64
+		if i%2 == 0 {
+			x = v[i/2]
+		} else {
+			x = v[n-1-i/2]
+		}
+
+		if sine && i%2 == 1 {
+			x = -x
+		}
+		dst[i] = x
+	}
+
+	return nil
+}
+
+// bytes returns the size of the buffers and twiddles.
+func (c *dct2FFT) bytes() int {
+	return c.fft.bytes() + len(c.phase)*16
+}
@@ -0,0 +1,187 @@
+package r2r
+
+import (
+	"math"
+	"testing"
+
+	algofft "github.com/MeKo-Christian/algo-fft"
+)
+
+type r2rPlan interface {
+	Forward(dst, src []float64) error
+	Inverse(dst, src []float64) error
+}
+
+// Every plan is built on the n-point real FFT for even lengths and on the
+// complex fallback for odd ones; sweep both paths, including the folded
+// lengths N-1 (DCT-I), N+1 (DST-I) and N/2 (DCT-IV, DST-IV), against the
+// direct sums.
+func TestRealFFTPlans_MatchDirectSums(t *testing.T) {
+	transforms := []struct {
+		name    string
+		minSize int
+		plan    func(n int) (r2rPlan, error)
+		ref     func(dst, src []float64)
+	}{
+		{"DCT-I", 2, func(n int) (r2rPlan, error) { return NewDCTPlan(n) }, dct1Reference},
+		{"DCT-II", 1, func(n int) (r2rPlan, error) { return NewDCT2Plan(n) }, dct2Reference},
+		{"DCT-III", 1, func(n int) (r2rPlan, error) { return NewDCT3Plan(n) }, dct3Reference},
+		{"DCT-IV", 1, func(n int) (r2rPlan, error) { return NewDCT4Plan(n) }, kernelReference(DCT4Coefficient)},
+		{"DST-I", 1, func(n int) (r2rPlan, error) { return NewDSTPlan(n) }, kernelReference(DST1Coefficient)},
+		{"DST-II", 1, func(n int) (r2rPlan, error) { return NewDST2Plan(n) }, dst2Reference},
+		{"DST-III", 1, func(n int) (r2rPlan, error) { return NewDST3Plan(n) }, dst3Reference},
+		{"DST-IV", 1, func(n int) (r2rPlan, error) { return NewDST4Plan(n) }, kernelReference(DST4Coefficient)},
+		{"DHT", 1, func(n int) (r2rPlan, error) { return NewDHTPlan(n) }, kernelReference(DHTCoefficient)},
+	}
+
+	for _, tr := range transforms {
+		for n := tr.minSize; n <= 40; n++ {
+			plan, err := tr.plan(n)
+			if err != nil {
+				t.Fatalf("%s: creating plan for n=%d failed: %v", tr.name, n, err)
+			}
+
+			src := make([]float64, n)
+			for i := range src {
+				src[i] = math.Sin(float64(i)*1.3) + 0.25*float64(i%3)
+			}
+
+			want := make([]float64, n)
+			tr.ref(want, src)
+
+			// In place, to also check that src is consumed before dst is written.
+			got := append([]float64(nil), src...)
+			if err := plan.Forward(got, got); err != nil {
+				t.Fatalf("%s: Forward(n=%d) failed: %v", tr.name, n, err)
+			}
+
+			for k := range n {
+				if math.Abs(got[k]-want[k]) > tolerance*float64(n) {
+					t.Errorf("%s n=%d: X[%d] = %v, want %v", tr.name, n, k, got[k], want[k])
+				}
+			}
+
+			if err := plan.Inverse(got, got); err != nil {
+				t.Fatalf("%s: Inverse(n=%d) failed: %v", tr.name, n, err)
+			}
+
+			for i := range n {
+				if math.Abs(got[i]-src[i]) > tolerance*float64(n) {
+					t.Errorf("%s n=%d: round trip x[%d] = %v, want %v", tr.name, n, i, got[i], src[i])
+				}
+			}
+		}
+	}
+}
+
+func TestRealFFT_RoundTrip(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		r, err := newRealFFT(n)
+		if err != nil {
+			t.Fatalf("newRealFFT(%d) failed: %v", n, err)
+		}
+
+		want := make([]float64, n)
+		for i := range want {
+			want[i] = float64(i*i) - 2
+			r.in[i] = want[i]
+		}
+
+		if err := r.forward(); err != nil {
+			t.Fatalf("forward(n=%d) failed: %v", n, err)
+		}
+
+		if got := real(r.spec[0]); math.Abs(got-float64(n*(n-1)*(2*n-1)/6-2*n)) > tolerance {
+			t.Errorf("n=%d: DC mode = %v, want the sum of the input", n, got)
+		}
+
+		if err := r.inverse(); err != nil {
+			t.Fatalf("inverse(n=%d) failed: %v", n, err)
+		}
+
+		for i := range n {
+			if math.Abs(r.in[i]-want[i]) > tolerance*float64(n) {
+				t.Errorf("n=%d: x[%d] = %v, want %v", n, i, r.in[i], want[i])
+			}
+		}
+	}
+}
+
+// BenchmarkDSTPlan_Forward_RealFFT compares DST-I on the folded (N+1)-point
+// real FFT with the odd extension into a 2(N+1)-point complex FFT it
+// replaced.
+func BenchmarkDSTPlan_Forward_RealFFT(b *testing.B) {
+	for _, n := range []int{63, 255, 1023} {
+		src := make([]float64, n)
+		dst := make([]float64, n)
+		for i := range n {
+			src[i] = float64(i)
+		}
+
+		b.Run("real-"+sizeStr(n), func(b *testing.B) {
+			plan, err := NewDSTPlan(n)
+			if err != nil {
+				b.Fatalf("NewDSTPlan failed: %v", err)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				_ = plan.Forward(dst, src)
+			}
+		})
+
+		b.Run("extension-"+sizeStr(n), func(b *testing.B) {
+			extendedN := 2 * (n + 1)
+			plan, err := algofft.NewPlan64(extendedN)
+			if err != nil {
+				b.Fatalf("NewPlan64 failed: %v", err)
+			}
+
+			in := make([]complex128, extendedN)
+			out := make([]complex128, extendedN)
+
+			b.ResetTimer()
+			for range b.N {
+				clear(in)
+				for i := range n {
+					in[i+1] = complex(src[i], 0)
+					in[extendedN-1-i] = complex(-src[i], 0)
+				}
+
+				_ = plan.Forward(out, in)
+				for k := range n {
+					dst[k] = -imag(out[k+1]) / 2
+				}
+			}
+		})
+	}
+}
+
+func dct1Reference(dst, src []float64) {
+	n := len(src)
+	for k := range n {
+		sum := 0.0
+		for i := range n {
+			weight := 2.0
+			if i == 0 || i == n-1 {
+				weight = 1.0
+			}
+			sum += weight * src[i] * DCT1Coefficient(i, k, n)
+		}
+		dst[k] = sum
+	}
+}
+
+// kernelReference returns the direct sum X[k] = Σ x[i] coeff(i, k, n).
+func kernelReference(coeff func(i, k, n int) float64) func(dst, src []float64) {
+	return func(dst, src []float64) {
+		n := len(src)
+		for k := range n {
+			sum := 0.0
+			for i := range n {
+				sum += src[i] * coeff(i, k, n)
+			}
+			dst[k] = sum
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package scenario
+
+import "github.com/MeKo-Tech/algo-pde/poisson"
+
+// BackwardEuler is the first-order implicit Euler scheme
+//
+//	u^{n+1} - νΔt Δu^{n+1} = u^n + Δt f^{n+1}
+//
+// solved as the Helmholtz problem (α - Δ)u^{n+1} = α u^n + f^{n+1}/ν with
+// α = 1/(νΔt). It is unconditionally stable.
+type BackwardEuler struct{}
+
+// NewPlan builds the Helmholtz plan for the scenario's grid and time step.
+func (BackwardEuler) NewPlan(s *Scenario) (*poisson.Plan, error) {
+	return poisson.NewHelmholtzPlan(s.Dim, s.N, s.H, s.BC, alpha(s), s.Options...)
+}
+
+// Step advances u by one implicit Euler step.
+func (BackwardEuler) Step(plan *poisson.Plan, s *Scenario, u, f []float64, t float64) error {
+	a := alpha(s)
+	for i := range f {
+		f[i] = a*u[i] + f[i]/s.Diffusivity
+	}
+
+	return plan.SolveWithBCAt(t+s.Dt, u, f, s.Boundary)
+}
+
+func alpha(s *Scenario) float64 {
+	return 1 / (s.Diffusivity * s.Dt)
+}
@@ -0,0 +1,297 @@
+package scenario
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Source is a volumetric source term f(t, x) evaluated at grid points.
+type Source func(t float64, x [3]float64) float64
+
+// BoundaryUpdater modifies the scenario's boundary data before each step,
+// e.g. to switch faces on or off. It is called with the time of the new
+// time level. For smooth time dependence, prefer BoundaryData.Func.
+type BoundaryUpdater func(t float64, bc poisson.BoundaryConditions) error
+
+// Observer is called with the current field after the initial condition is
+// set (step 0) and after every ObserveEvery-th step. Returning an error
+// stops the run.
+type Observer func(step int, t float64, u []float64) error
+
+// Writer persists snapshots of the field. It is called like an Observer,
+// every WriteEvery-th step.
+type Writer interface {
+	Write(step int, t float64, u []float64) error
+}
+
+// Integrator advances the field by one time step.
+type Integrator interface {
+	// NewPlan builds the solver plan used by Step.
+	NewPlan(s *Scenario) (*poisson.Plan, error)
+
+	// Step advances u in place from t to t+s.Dt. f holds the summed sources
+	// evaluated at t+s.Dt and may be overwritten.
+	Step(plan *poisson.Plan, s *Scenario, u, f []float64, t float64) error
+}
+
+// Scenario declares a diffusion simulation ∂u/∂t = νΔu + f.
+type Scenario struct {
+	// Name identifies the scenario in writer output.
+	Name string
+
+	// Dim, N, H and BC describe the grid as for poisson.NewPlan.
+	Dim int
+	N   []int
+	H   []float64
+	BC  []poisson.BCType
+
+	// Diffusivity is ν and must be positive.
+	Diffusivity float64
+
+	// Dt is the time step, Steps the number of steps, T0 the start time.
+	Dt    float64
+	Steps int
+	T0    float64
+
+	// Integrator selects the time stepping scheme. Nil selects BackwardEuler.
+	Integrator Integrator
+
+	// Initial returns u(T0, x). Nil starts from zero.
+	Initial func(x [3]float64) float64
+
+	// Sources are summed into the volumetric source term.
+	Sources []Source
+
+	// Boundary holds inhomogeneous boundary data for non-periodic faces.
+	// Entries with a Func are evaluated at each new time level.
+	Boundary poisson.BoundaryConditions
+
+	// BoundaryUpdaters run before each step, in order.
+	BoundaryUpdaters []BoundaryUpdater
+
+	// Observers and Writers receive the field every ObserveEvery and
+	// WriteEvery steps (0 means every step).
+	Observers    []Observer
+	Writers      []Writer
+	ObserveEvery int
+	WriteEvery   int
+
+	// Hyperviscosity, if non-nil, damps high modes after every step.
+	Hyperviscosity *Hyperviscosity
+
+	// Options are passed to the plan constructor.
+	Options []poisson.Option
+}
+
+// Hyperviscosity configures an explicit spectral filter
+//
+//	û ← exp(-Δt Σ_axis ν_axis λ_axis^p) û
+//
+// applied to the field after each integrator step. It is built with
+// poisson.NewHyperviscosityFilter on the integrator's plan.
+type Hyperviscosity struct {
+	// Nu holds the non-negative per-axis strengths, one per dimension.
+	Nu []float64
+
+	// Order is the power p of the Laplacian eigenvalues and must be at least 1.
+	Order int
+}
+
+// Result is the outcome of a completed run.
+type Result struct {
+	// Steps is the number of steps taken and Time the final time.
+	Steps int
+	Time  float64
+
+	// Field is the final solution in row-major order.
+	Field []float64
+}
+
+// Run validates the scenario, builds its plan and advances the field for
+// s.Steps steps.
+func Run(s *Scenario) (*Result, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	integrator := s.Integrator
+	if integrator == nil {
+		integrator = BackwardEuler{}
+	}
+
+	plan, err := integrator.NewPlan(s)
+	if err != nil {
+		return nil, fmt.Errorf("scenario %q: %w", s.Name, err)
+	}
+
+	var filter *poisson.HyperviscosityFilter
+	if hv := s.Hyperviscosity; hv != nil {
+		filter, err = poisson.NewHyperviscosityFilter(plan, s.Dt, hv.Nu, hv.Order)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %q: hyperviscosity: %w", s.Name, err)
+		}
+	}
+
+	size := s.size()
+	u := make([]float64, size)
+	f := make([]float64, size)
+
+	shape := s.shape()
+	coords := s.coordinates()
+	if s.Initial != nil {
+		for idx := range u {
+			u[idx] = s.Initial(coords.point(shape, idx))
+		}
+	}
+
+	t := s.T0
+	if err := s.emit(0, t, u); err != nil {
+		return nil, err
+	}
+
+	for step := 1; step <= s.Steps; step++ {
+		next := s.T0 + float64(step)*s.Dt
+
+		for _, update := range s.BoundaryUpdaters {
+			if err := update(next, s.Boundary); err != nil {
+				return nil, fmt.Errorf("step %d: boundary update: %w", step, err)
+			}
+		}
+
+		s.evalSources(f, next, coords)
+
+		if err := integrator.Step(plan, s, u, f, t); err != nil {
+			return nil, fmt.Errorf("step %d: %w", step, err)
+		}
+
+		if filter != nil {
+			if err := filter.Apply(u, u); err != nil {
+				return nil, fmt.Errorf("step %d: hyperviscosity: %w", step, err)
+			}
+		}
+
+		t = next
+		if err := s.emit(step, t, u); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Result{Steps: s.Steps, Time: t, Field: u}, nil
+}
+
+// Validate checks the scenario for missing or inconsistent settings.
+// Grid settings are validated by the plan constructor.
+func (s *Scenario) Validate() error {
+	switch {
+	case s.Diffusivity <= 0 || math.IsNaN(s.Diffusivity) || math.IsInf(s.Diffusivity, 0):
+		return &poisson.ValidationError{Field: "Diffusivity", Message: "must be positive and finite"}
+	case s.Dt <= 0 || math.IsNaN(s.Dt) || math.IsInf(s.Dt, 0):
+		return &poisson.ValidationError{Field: "Dt", Message: "must be positive and finite"}
+	case s.Steps < 0:
+		return &poisson.ValidationError{Field: "Steps", Message: "must be non-negative"}
+	case s.ObserveEvery < 0:
+		return &poisson.ValidationError{Field: "ObserveEvery", Message: "must be non-negative"}
+	case s.WriteEvery < 0:
+		return &poisson.ValidationError{Field: "WriteEvery", Message: "must be non-negative"}
+	case len(s.N) != s.Dim:
+		return &poisson.ValidationError{Field: "N", Message: "length must match Dim"}
+	case len(s.H) != s.Dim:
+		return &poisson.ValidationError{Field: "H", Message: "length must match Dim"}
+	case len(s.BC) != s.Dim:
+		return &poisson.ValidationError{Field: "BC", Message: "length must match Dim"}
+	}
+
+	return nil
+}
+
+// Point returns the coordinates of the grid point with row-major index idx.
+// See the package documentation for the per-BC point conventions.
+func (s *Scenario) Point(idx int) [3]float64 {
+	return s.coordinates().point(s.shape(), idx)
+}
+
+// coordinates holds the grid point positions along each axis, nil for
+// unused axes.
+type coordinates [3][]float64
+
+// coordinates returns the grid point positions along each axis, as given
+// by poisson.Coordinates.
+func (s *Scenario) coordinates() coordinates {
+	var c coordinates
+	for axis := 0; axis < s.Dim; axis++ {
+		c[axis] = poisson.Coordinates(s.N[axis], s.H[axis], s.BC[axis])
+	}
+
+	return c
+}
+
+// point returns the position of the grid point with row-major index idx.
+func (c coordinates) point(shape grid.Shape, idx int) [3]float64 {
+	i, j, k := grid.FromIndex3D(idx, shape)
+	ijk := [3]int{i, j, k}
+
+	var x [3]float64
+	for axis, xs := range c {
+		if xs != nil {
+			x[axis] = xs[ijk[axis]]
+		}
+	}
+
+	return x
+}
+
+func (s *Scenario) shape() grid.Shape {
+	shape := grid.Shape{1, 1, 1}
+	copy(shape[:], s.N)
+	return shape
+}
+
+func (s *Scenario) size() int {
+	return s.shape().Size()
+}
+
+func (s *Scenario) evalSources(f []float64, t float64, coords coordinates) {
+	for idx := range f {
+		f[idx] = 0
+	}
+	if len(s.Sources) == 0 {
+		return
+	}
+
+	shape := s.shape()
+	for idx := range f {
+		x := coords.point(shape, idx)
+		for _, src := range s.Sources {
+			f[idx] += src(t, x)
+		}
+	}
+}
+
+func (s *Scenario) emit(step int, t float64, u []float64) error {
+	if due(step, s.ObserveEvery, s.Steps) {
+		for _, obs := range s.Observers {
+			if err := obs(step, t, u); err != nil {
+				return fmt.Errorf("step %d: observer: %w", step, err)
+			}
+		}
+	}
+
+	if due(step, s.WriteEvery, s.Steps) {
+		for _, w := range s.Writers {
+			if err := w.Write(step, t, u); err != nil {
+				return fmt.Errorf("step %d: writer: %w", step, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// due reports whether step should be emitted for the given interval.
+// The initial and final steps are always emitted.
+func due(step, every, last int) bool {
+	return every <= 1 || step == 0 || step == last || step%every == 0
+}
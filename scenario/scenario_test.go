@@ -0,0 +1,197 @@
+package scenario_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+	"github.com/MeKo-Tech/algo-pde/scenario"
+)
+
+func TestRun_BackwardEulerModeDecay(t *testing.T) {
+	n := 32
+	h := 1.0 / float64(n)
+	nu, dt, steps := 0.05, 0.01, 20
+
+	var out bytes.Buffer
+	observed := 0
+	s := &scenario.Scenario{
+		Name:        "decay",
+		Dim:         1,
+		N:           []int{n},
+		H:           []float64{h},
+		BC:          []poisson.BCType{poisson.Periodic},
+		Diffusivity: nu,
+		Dt:          dt,
+		Steps:       steps,
+		Initial:     func(x [3]float64) float64 { return math.Sin(2 * math.Pi * x[0]) },
+		Observers: []scenario.Observer{func(int, float64, []float64) error {
+			observed++
+			return nil
+		}},
+		Writers:    []scenario.Writer{scenario.NewSummaryWriter(&out)},
+		WriteEvery: 5,
+	}
+
+	res, err := scenario.Run(s)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if res.Steps != steps || math.Abs(res.Time-float64(steps)*dt) > 1e-12 {
+		t.Fatalf("Result = (%d, %g), want (%d, %g)", res.Steps, res.Time, steps, float64(steps)*dt)
+	}
+
+	lambda := (2 - 2*math.Cos(2*math.Pi/float64(n))) / (h * h)
+	amp := math.Pow(1/(1+nu*dt*lambda), float64(steps))
+	for i, v := range res.Field {
+		want := amp * math.Sin(2*math.Pi*float64(i)*h)
+		if math.Abs(v-want) > 1e-10 {
+			t.Fatalf("u[%d] = %g, want %g", i, v, want)
+		}
+	}
+
+	if observed != steps+1 {
+		t.Fatalf("observer called %d times, want %d", observed, steps+1)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 5 {
+		t.Fatalf("writer produced %d lines, want 5:\n%s", lines, out.String())
+	}
+}
+
+func TestRun_HyperviscosityDampsMode(t *testing.T) {
+	n := 32
+	h := 1.0 / float64(n)
+	nu, dt, steps := 0.05, 0.01, 20
+	hv, p := 1e-6, 2
+
+	s := &scenario.Scenario{
+		Dim:            1,
+		N:              []int{n},
+		H:              []float64{h},
+		BC:             []poisson.BCType{poisson.Periodic},
+		Diffusivity:    nu,
+		Dt:             dt,
+		Steps:          steps,
+		Initial:        func(x [3]float64) float64 { return math.Sin(6 * math.Pi * x[0]) },
+		Hyperviscosity: &scenario.Hyperviscosity{Nu: []float64{hv}, Order: p},
+	}
+
+	res, err := scenario.Run(s)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	lambda := (2 - 2*math.Cos(6*math.Pi/float64(n))) / (h * h)
+	amp := math.Pow(math.Exp(-dt*hv*math.Pow(lambda, float64(p)))/(1+nu*dt*lambda), float64(steps))
+	for i, v := range res.Field {
+		want := amp * math.Sin(6*math.Pi*float64(i)*h)
+		if math.Abs(v-want) > 1e-10 {
+			t.Fatalf("u[%d] = %g, want %g", i, v, want)
+		}
+	}
+}
+
+func TestRun_SourcesAndBoundaryReachSteadyState(t *testing.T) {
+	n := 15
+	h := 1.0 / float64(n+1)
+	updates := 0
+
+	// u = x on [0, 1] with u(0) = 0, u(1) = 1 and f = 0; plus the source
+	// f = 2ν gives the steady state u = x + x(1-x).
+	s := &scenario.Scenario{
+		Dim:         1,
+		N:           []int{n},
+		H:           []float64{h},
+		BC:          []poisson.BCType{poisson.Dirichlet},
+		Diffusivity: 0.5,
+		Dt:          10,
+		Steps:       50,
+		Sources:     []scenario.Source{func(float64, [3]float64) float64 { return 1 }},
+		Boundary: poisson.BoundaryConditions{
+			{Face: poisson.XLow, Type: poisson.Dirichlet, Values: []float64{0}},
+			{Face: poisson.XHigh, Type: poisson.Dirichlet, Func: func(float64, int) float64 { return 1 }},
+		},
+		BoundaryUpdaters: []scenario.BoundaryUpdater{func(float64, poisson.BoundaryConditions) error {
+			updates++
+			return nil
+		}},
+	}
+
+	res, err := scenario.Run(s)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	for i, v := range res.Field {
+		x := s.Point(i)[0]
+		want := x + x*(1-x)
+		if math.Abs(v-want) > 1e-8 {
+			t.Fatalf("u(%g) = %g, want %g", x, v, want)
+		}
+	}
+
+	if updates != s.Steps {
+		t.Fatalf("boundary updater called %d times, want %d", updates, s.Steps)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	base := func() *scenario.Scenario {
+		return &scenario.Scenario{
+			Dim:         1,
+			N:           []int{8},
+			H:           []float64{0.1},
+			BC:          []poisson.BCType{poisson.Neumann},
+			Diffusivity: 1,
+			Dt:          0.1,
+			Steps:       3,
+		}
+	}
+
+	s := base()
+	s.Dt = 0
+	var vErr *poisson.ValidationError
+	if _, err := scenario.Run(s); !errors.As(err, &vErr) || vErr.Field != "Dt" {
+		t.Fatalf("expected Dt ValidationError, got %v", err)
+	}
+
+	s = base()
+	s.Diffusivity = -1
+	if _, err := scenario.Run(s); !errors.As(err, &vErr) || vErr.Field != "Diffusivity" {
+		t.Fatalf("expected Diffusivity ValidationError, got %v", err)
+	}
+
+	s = base()
+	s.WriteEvery = -1
+	if _, err := scenario.Run(s); !errors.As(err, &vErr) || vErr.Field != "WriteEvery" {
+		t.Fatalf("expected WriteEvery ValidationError, got %v", err)
+	}
+
+	s = base()
+	s.H = []float64{0.1, 0.1}
+	if _, err := scenario.Run(s); !errors.As(err, &vErr) || vErr.Field != "H" {
+		t.Fatalf("expected H ValidationError, got %v", err)
+	}
+
+	s = base()
+	s.Hyperviscosity = &scenario.Hyperviscosity{Nu: []float64{1}, Order: 0}
+	if _, err := scenario.Run(s); !errors.As(err, &vErr) || vErr.Field != "p" {
+		t.Fatalf("expected p ValidationError, got %v", err)
+	}
+
+	stop := errors.New("stop")
+	s = base()
+	s.Observers = []scenario.Observer{func(step int, _ float64, _ []float64) error {
+		if step == 2 {
+			return stop
+		}
+		return nil
+	}}
+	if _, err := scenario.Run(s); !errors.Is(err, stop) {
+		t.Fatalf("expected observer error, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package scenario
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// SummaryWriter writes one line per snapshot with the step, time, and the
+// minimum, maximum and mean of the field.
+type SummaryWriter struct {
+	w io.Writer
+}
+
+// NewSummaryWriter returns a SummaryWriter writing to w.
+func NewSummaryWriter(w io.Writer) *SummaryWriter {
+	return &SummaryWriter{w: w}
+}
+
+// Write implements Writer.
+func (sw *SummaryWriter) Write(step int, t float64, u []float64) error {
+	minV, maxV, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, v := range u {
+		minV = math.Min(minV, v)
+		maxV = math.Max(maxV, v)
+		sum += v
+	}
+
+	mean := 0.0
+	if len(u) > 0 {
+		mean = sum / float64(len(u))
+	}
+
+	_, err := fmt.Fprintf(sw.w, "step=%d t=%.6g min=%.6e max=%.6e mean=%.6e\n", step, t, minV, maxV, mean)
+	return err
+}
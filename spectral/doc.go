@@ -0,0 +1,28 @@
+// Package spectral provides spectral differentiation of gridded fields.
+//
+// A Plan computes d^m/dx_axis^m, gradients and Laplacians with the same grid
+// and boundary conventions as poisson plans:
+//
+//   - Periodic: points x_i = i*h on [0, n*h), Fourier series (FFT)
+//   - Dirichlet: interior points x_i = (i+1)*h of [0, (n+1)*h), sine series (DST-I)
+//   - Neumann: cell centers x_i = (i+0.5)*h of [0, n*h), cosine series (DCT-II)
+//
+// Sine and cosine series use the r2r plans of the solvers: DST-I and DCT-II
+// expand each line and their inverses sum the series of even derivatives,
+// while DCT-I (over the nodes including the boundaries) and DST-III sum the
+// cosine and sine series of odd derivatives. Derivatives are exact for every
+// mode the grid resolves. Odd derivatives of the Nyquist mode are set to
+// zero.
+//
+// Unlike the finite-difference operator that poisson plans invert, these are
+// true spectral derivatives (symbol (ik)^m rather than the discrete
+// Laplacian eigenvalues).
+//
+// Resample transfers a field between resolutions of the same domain by
+// zero-padding or truncating its expansion along each axis, e.g. to restart a
+// simulation on a finer grid. ResampleBoundary transfers the matching
+// poisson.BoundaryConditions face data.
+//
+// Data layout is row-major, as in the rest of the module. Plans hold scratch
+// buffers and must not be used concurrently.
+package spectral
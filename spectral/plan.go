@@ -0,0 +1,527 @@
+package spectral
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+	"github.com/MeKo-Tech/algo-pde/r2r"
+)
+
+// Option configures a Plan.
+type Option func(*options)
+
+type options struct {
+	workers int
+}
+
+// WithWorkers sets the number of parallel workers for the line transforms.
+// 0 uses runtime.GOMAXPROCS.
+func WithWorkers(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// Plan is a reusable spectral differentiation plan for one grid.
+type Plan struct {
+	dim int
+	n   [3]int
+	h   [3]float64
+	bc  [3]poisson.BCType
+
+	// fft transforms the lines along Periodic axes in buf.
+	fft [3]*poisson.FFTPlan
+	buf []complex128
+
+	// expand maps the lines along a Dirichlet (DST-I) or Neumann (DCT-II)
+	// axis to their sine or cosine coefficients, and back for even orders.
+	// odd sums the series of an odd derivative: a cosine series over the
+	// n+2 nodes including the boundaries (DCT-I) for Dirichlet, a sine
+	// series (DST-III) for Neumann.
+	expand [3]r2rPlan
+	odd    [3]r2rPlan
+
+	// k holds the wavenumbers per axis, in FFT output order for Periodic
+	// axes and per sine or cosine coefficient otherwise.
+	k [3][]float64
+
+	// lines holds the lines along the axis being differentiated back to
+	// back, and oddLines the input of odd.
+	lines    []float64
+	oddLines []float64
+
+	// symbol caches the per-mode multiplier for the last order used per
+	// axis: (ik)^order for Periodic axes, the real factor (with the inverse
+	// scaling of odd orders folded in) otherwise.
+	symbol      [3][]complex128
+	scale       [3][]float64
+	symbolOrder [3]int
+
+	// acc and tmp are scratch for Laplacian accumulation.
+	acc []float64
+	tmp []float64
+}
+
+// r2rPlan is the batched interface of the r2r plans.
+type r2rPlan interface {
+	ForwardMany(dst, src []float64, count int) error
+	InverseMany(dst, src []float64, count int) error
+}
+
+// NewPlan creates a differentiation plan with per-axis boundary conditions.
+func NewPlan(dim int, n []int, h []float64, bc []poisson.BCType, opts ...Option) (*Plan, error) {
+	if dim < 1 || dim > 3 {
+		return nil, &poisson.ValidationError{Field: "dim", Message: "must be 1, 2, or 3"}
+	}
+
+	if len(n) != dim || len(h) != dim || len(bc) != dim {
+		return nil, &poisson.ValidationError{Field: "n", Message: "n, h and bc lengths must match dim"}
+	}
+
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	if o.workers < 0 {
+		return nil, &poisson.ValidationError{Field: "Workers", Message: "must be non-negative (0 selects GOMAXPROCS)"}
+	}
+
+	p := &Plan{
+		dim: dim,
+		n:   [3]int{1, 1, 1},
+		h:   [3]float64{1, 1, 1},
+		bc:  [3]poisson.BCType{poisson.Periodic, poisson.Periodic, poisson.Periodic},
+	}
+
+	for axis := 0; axis < dim; axis++ {
+		if n[axis] < 1 {
+			return nil, poisson.ErrInvalidSize
+		}
+		if h[axis] <= 0 {
+			return nil, poisson.ErrInvalidSpacing
+		}
+
+		switch bc[axis] {
+		case poisson.Periodic, poisson.Dirichlet, poisson.Neumann:
+		default:
+			return nil, &poisson.ValidationError{
+				Field:   fmt.Sprintf("bc[%d]", axis),
+				Message: "unsupported boundary condition",
+			}
+		}
+
+		p.n[axis] = n[axis]
+		p.h[axis] = h[axis]
+		p.bc[axis] = bc[axis]
+	}
+
+	workers := o.workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	size := p.size()
+	for axis := 0; axis < dim; axis++ {
+		if err := p.initAxis(axis, workers); err != nil {
+			return nil, fmt.Errorf("axis %d: %w", axis, err)
+		}
+
+		if p.bc[axis] == poisson.Periodic {
+			p.buf = make([]complex128, size)
+			p.symbol[axis] = make([]complex128, p.n[axis])
+			continue
+		}
+
+		p.lines = make([]float64, size)
+		p.oddLines = make([]float64, max(len(p.oddLines), size/p.n[axis]*p.oddLen(axis)))
+		p.scale[axis] = make([]float64, p.n[axis])
+	}
+
+	return p, nil
+}
+
+// initAxis creates the transforms and wavenumbers of axis.
+func (p *Plan) initAxis(axis, workers int) error {
+	n, h := p.n[axis], p.h[axis]
+
+	var err error
+	switch p.bc[axis] {
+	case poisson.Periodic:
+		p.fft[axis], err = poisson.NewFFTPlanWithWorkers(n, workers)
+		p.k[axis] = wavenumbers(n, h)
+		return err
+	case poisson.Dirichlet:
+		p.k[axis] = seriesWavenumbers(n, 1, float64(n+1)*h)
+		if p.expand[axis], err = r2r.NewDSTPlan(n, r2r.WithWorkers(workers)); err != nil {
+			return err
+		}
+		p.odd[axis], err = r2r.NewDCTPlan(n+2, r2r.WithWorkers(workers))
+	default:
+		p.k[axis] = seriesWavenumbers(n, 0, float64(n)*h)
+		if p.expand[axis], err = r2r.NewDCT2Plan(n, r2r.WithWorkers(workers)); err != nil {
+			return err
+		}
+		p.odd[axis], err = r2r.NewDST3Plan(n, r2r.WithWorkers(workers))
+	}
+
+	return err
+}
+
+// Derivative computes dst = d^order src / dx_axis^order. order 0 copies src.
+// dst and src may alias.
+func (p *Plan) Derivative(dst, src []float64, axis, order int) error {
+	if err := p.checkBuffers(dst, src); err != nil {
+		return err
+	}
+
+	if axis < 0 || axis >= p.dim {
+		return &poisson.ValidationError{Field: "axis", Message: fmt.Sprintf("must be in [0, %d)", p.dim)}
+	}
+
+	if order < 0 {
+		return &poisson.ValidationError{Field: "order", Message: "must be non-negative"}
+	}
+
+	if order == 0 {
+		copy(dst, src)
+		return nil
+	}
+
+	return p.derivative(dst, src, axis, order)
+}
+
+// Gradient computes ∇src into dst, one component per axis (len(dst) == dim).
+func (p *Plan) Gradient(dst [][]float64, src []float64) error {
+	if len(dst) != p.dim {
+		return &poisson.ValidationError{Field: "dst", Message: "must have one component per axis"}
+	}
+
+	for axis := 0; axis < p.dim; axis++ {
+		if err := p.Derivative(dst[axis], src, axis, 1); err != nil {
+			return fmt.Errorf("axis %d: %w", axis, err)
+		}
+	}
+
+	return nil
+}
+
+// Laplacian computes dst = Δsrc. dst and src may alias.
+func (p *Plan) Laplacian(dst, src []float64) error {
+	if err := p.checkBuffers(dst, src); err != nil {
+		return err
+	}
+
+	size := p.size()
+	if len(p.acc) < size {
+		p.acc = make([]float64, size)
+		p.tmp = make([]float64, size)
+	}
+	acc, tmp := p.acc[:size], p.tmp[:size]
+
+	if err := p.derivative(acc, src, 0, 2); err != nil {
+		return err
+	}
+
+	for axis := 1; axis < p.dim; axis++ {
+		if err := p.derivative(tmp, src, axis, 2); err != nil {
+			return err
+		}
+		for i := range acc {
+			acc[i] += tmp[i]
+		}
+	}
+
+	copy(dst, acc)
+
+	return nil
+}
+
+func (p *Plan) derivative(dst, src []float64, axis, order int) error {
+	if p.bc[axis] != poisson.Periodic {
+		return p.seriesDerivative(dst, src, axis, order)
+	}
+
+	shape := p.shape()
+	buf := p.buf
+	for i, v := range src {
+		buf[i] = complex(v, 0)
+	}
+
+	if err := p.fft[axis].TransformLines(buf, shape, axis, false); err != nil {
+		return fmt.Errorf("forward axis %d: %w", axis, err)
+	}
+
+	p.applySymbol(buf, shape, axis, order)
+
+	if err := p.fft[axis].TransformLines(buf, shape, axis, true); err != nil {
+		return fmt.Errorf("inverse axis %d: %w", axis, err)
+	}
+
+	for i, v := range buf {
+		dst[i] = real(v)
+	}
+
+	return nil
+}
+
+// seriesDerivative differentiates along a Dirichlet or Neumann axis: the
+// lines are expanded into sine or cosine coefficients, scaled, and summed
+// again with the inverse transform for even orders or with odd for odd
+// orders, whose derivatives swap sines and cosines.
+func (p *Plan) seriesDerivative(dst, src []float64, axis, order int) error {
+	shape := p.shape()
+	n := shape[axis]
+	count := p.size() / n
+	lines := p.lines
+
+	gatherLines(lines, src, shape, axis)
+
+	if err := p.expand[axis].ForwardMany(lines, lines, count); err != nil {
+		return fmt.Errorf("forward axis %d: %w", axis, err)
+	}
+
+	scale := p.seriesSymbol(axis, order)
+	if order%2 == 0 {
+		for i := range lines {
+			lines[i] *= scale[i%n]
+		}
+
+		if err := p.expand[axis].InverseMany(lines, lines, count); err != nil {
+			return fmt.Errorf("inverse axis %d: %w", axis, err)
+		}
+
+		scatterLines(dst, lines, shape, axis)
+		return nil
+	}
+
+	// Coefficient c of a Dirichlet line is mode c+1 and goes to node c+1 of
+	// the DCT-I input, whose boundary nodes stay zero; coefficient c of a
+	// Neumann line is mode c and goes to entry c-1 of the DST-III input,
+	// whose last (Nyquist) entry stays zero.
+	shift := 1
+	if p.bc[axis] == poisson.Neumann {
+		shift = -1
+	}
+
+	m := p.oddLen(axis)
+	odd := p.oddLines[:count*m]
+	clear(odd)
+	for l := range count {
+		for c := max(-shift, 0); c < n; c++ {
+			odd[l*m+c+shift] = lines[l*n+c] * scale[c]
+		}
+	}
+
+	if err := p.odd[axis].ForwardMany(odd, odd, count); err != nil {
+		return fmt.Errorf("inverse axis %d: %w", axis, err)
+	}
+
+	first := max(shift, 0)
+	for l := range count {
+		copy(lines[l*n:(l+1)*n], odd[l*m+first:])
+	}
+
+	scatterLines(dst, lines, shape, axis)
+
+	return nil
+}
+
+// oddLen returns the length of the odd transform of a Dirichlet or Neumann
+// axis.
+func (p *Plan) oddLen(axis int) int {
+	if p.bc[axis] == poisson.Dirichlet {
+		return p.n[axis] + 2
+	}
+
+	return p.n[axis]
+}
+
+// seriesSymbol returns the factor every sine or cosine coefficient along
+// axis is multiplied by for a derivative of the given order. Even orders
+// map sin(kx) and cos(kx) to (-1)^(order/2) k^order times themselves. Odd
+// orders map sin(kx) to (-1)^((order-1)/2) k^order cos(kx) and cos(kx) to
+// (-1)^((order+1)/2) k^order sin(kx); the factor then also carries the
+// scaling from Forward's coefficients to the amplitudes summed by odd.
+func (p *Plan) seriesSymbol(axis, order int) []float64 {
+	scale := p.scale[axis]
+	if p.symbolOrder[axis] == order {
+		return scale
+	}
+
+	n := float64(p.n[axis])
+	sign, norm := 1.0, 1.0
+	switch {
+	case order%2 == 0:
+		if order%4 == 2 {
+			sign = -1
+		}
+	case p.bc[axis] == poisson.Dirichlet:
+		// DST-I coefficients carry 2/(n+1), and DCT-I weights interior
+		// nodes by 2.
+		if order%4 == 3 {
+			sign = -1
+		}
+		norm = 1 / (n + 1)
+	default:
+		// DCT-II coefficients carry 2/n.
+		if order%4 == 1 {
+			sign = -1
+		}
+		norm = 2 / n
+	}
+
+	for c, k := range p.k[axis] {
+		scale[c] = sign * norm * math.Pow(k, float64(order))
+	}
+	p.symbolOrder[axis] = order
+
+	return scale
+}
+
+// applySymbol multiplies every mode along a Periodic axis by (ik)^order.
+func (p *Plan) applySymbol(buf []complex128, shape grid.Shape, axis, order int) {
+	m := shape[axis]
+	k := p.k[axis]
+	stride := grid.RowMajorStride(shape)[axis]
+
+	symbol := p.symbol[axis]
+	if p.symbolOrder[axis] != order {
+		for j := range symbol {
+			symbol[j] = 0
+			if order%2 == 1 && m%2 == 0 && j == m/2 {
+				continue
+			}
+			symbol[j] = ipow(order) * complex(math.Pow(k[j], float64(order)), 0)
+		}
+		p.symbolOrder[axis] = order
+	}
+
+	forEachLine(shape, shape, axis, func(_, start int) {
+		for j := 0; j < m; j++ {
+			buf[start+j*stride] *= symbol[j]
+		}
+	})
+}
+
+func (p *Plan) checkBuffers(dst, src []float64) error {
+	if dst == nil || src == nil {
+		return poisson.ErrNilBuffer
+	}
+
+	size := p.size()
+	if len(dst) != size || len(src) != size {
+		return poisson.ErrSizeMismatch
+	}
+
+	return nil
+}
+
+func (p *Plan) shape() grid.Shape {
+	return grid.Shape{p.n[0], p.n[1], p.n[2]}
+}
+
+func (p *Plan) size() int {
+	return p.n[0] * p.n[1] * p.n[2]
+}
+
+// forEachLine calls fn with the start index of every line along axis in
+// shape and in extShape, which differ only in their length along axis.
+func forEachLine(shape, extShape grid.Shape, axis int, fn func(start, extStart int)) {
+	stride := grid.RowMajorStride(shape)
+	extStride := grid.RowMajorStride(extShape)
+
+	var idx [3]int
+	for idx[0] = 0; idx[0] < shape[0]; idx[0]++ {
+		if axis == 0 && idx[0] > 0 {
+			break
+		}
+		for idx[1] = 0; idx[1] < shape[1]; idx[1]++ {
+			if axis == 1 && idx[1] > 0 {
+				break
+			}
+			for idx[2] = 0; idx[2] < shape[2]; idx[2]++ {
+				if axis == 2 && idx[2] > 0 {
+					break
+				}
+				fn(grid.Index(idx[0], idx[1], idx[2], stride), grid.Index(idx[0], idx[1], idx[2], extStride))
+			}
+		}
+	}
+}
+
+// gatherLines copies the lines along axis of src back to back into lines.
+func gatherLines(lines, src []float64, shape grid.Shape, axis int) {
+	n := shape[axis]
+	stride := grid.RowMajorStride(shape)[axis]
+
+	l := 0
+	forEachLine(shape, shape, axis, func(start, _ int) {
+		line := lines[l*n : (l+1)*n]
+		for i := range line {
+			line[i] = src[start+i*stride]
+		}
+		l++
+	})
+}
+
+// scatterLines copies the lines stored back to back in lines into dst, in
+// the order of gatherLines.
+func scatterLines(dst, lines []float64, shape grid.Shape, axis int) {
+	n := shape[axis]
+	stride := grid.RowMajorStride(shape)[axis]
+
+	l := 0
+	forEachLine(shape, shape, axis, func(start, _ int) {
+		for i, v := range lines[l*n : (l+1)*n] {
+			dst[start+i*stride] = v
+		}
+		l++
+	})
+}
+
+// wavenumbers returns the angular wavenumbers of an FFT of length m with
+// spacing h, in FFT output order.
+func wavenumbers(m int, h float64) []float64 {
+	k := make([]float64, m)
+	scale := 2 * math.Pi / (float64(m) * h)
+	for j := range k {
+		freq := j
+		if j > m/2 {
+			freq = j - m
+		}
+		k[j] = scale * float64(freq)
+	}
+
+	return k
+}
+
+// seriesWavenumbers returns the wavenumbers mπ/length of the n sine or
+// cosine modes m = first, ..., first+n-1.
+func seriesWavenumbers(n, first int, length float64) []float64 {
+	k := make([]float64, n)
+	for c := range k {
+		k[c] = math.Pi * float64(first+c) / length
+	}
+
+	return k
+}
+
+// ipow returns i^order.
+func ipow(order int) complex128 {
+	switch order % 4 {
+	case 0:
+		return 1
+	case 1:
+		return 1i
+	case 2:
+		return -1
+	default:
+		return -1i
+	}
+}
@@ -0,0 +1,195 @@
+package spectral_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+	"github.com/MeKo-Tech/algo-pde/spectral"
+)
+
+const spectralTol = 1e-9
+
+func TestDerivative_1D(t *testing.T) {
+	tests := []struct {
+		name  string
+		bc    poisson.BCType
+		n     int
+		h     float64
+		x     func(i int, h float64) float64
+		u     func(x float64) float64
+		du    func(x float64, order int) float64
+		order int
+	}{
+		{
+			name: "periodic first",
+			bc:   poisson.Periodic, n: 32, h: 1.0 / 32,
+			x: func(i int, h float64) float64 { return float64(i) * h },
+			u: func(x float64) float64 { return math.Sin(2*math.Pi*x) + math.Cos(6*math.Pi*x) },
+			du: func(x float64, _ int) float64 {
+				return 2*math.Pi*math.Cos(2*math.Pi*x) - 6*math.Pi*math.Sin(6*math.Pi*x)
+			},
+			order: 1,
+		},
+		{
+			name: "periodic third",
+			bc:   poisson.Periodic, n: 30, h: 1.0 / 30,
+			x:     func(i int, h float64) float64 { return float64(i) * h },
+			u:     func(x float64) float64 { return math.Sin(4 * math.Pi * x) },
+			du:    func(x float64, _ int) float64 { return -math.Pow(4*math.Pi, 3) * math.Cos(4*math.Pi*x) },
+			order: 3,
+		},
+		{
+			name: "dirichlet first",
+			bc:   poisson.Dirichlet, n: 15, h: 1.0 / 16,
+			x:     func(i int, h float64) float64 { return float64(i+1) * h },
+			u:     func(x float64) float64 { return math.Sin(math.Pi*x) - 0.5*math.Sin(3*math.Pi*x) },
+			du:    func(x float64, _ int) float64 { return math.Pi*math.Cos(math.Pi*x) - 1.5*math.Pi*math.Cos(3*math.Pi*x) },
+			order: 1,
+		},
+		{
+			name: "dirichlet second",
+			bc:   poisson.Dirichlet, n: 20, h: 1.0 / 21,
+			x:     func(i int, h float64) float64 { return float64(i+1) * h },
+			u:     func(x float64) float64 { return math.Sin(2 * math.Pi * x) },
+			du:    func(x float64, _ int) float64 { return -4 * math.Pi * math.Pi * math.Sin(2*math.Pi*x) },
+			order: 2,
+		},
+		{
+			name: "neumann first",
+			bc:   poisson.Neumann, n: 17, h: 1.0 / 17,
+			x: func(i int, h float64) float64 { return (float64(i) + 0.5) * h },
+			u: func(x float64) float64 { return math.Cos(math.Pi*x) + 0.25*math.Cos(5*math.Pi*x) },
+			du: func(x float64, _ int) float64 {
+				return -math.Pi*math.Sin(math.Pi*x) - 1.25*math.Pi*math.Sin(5*math.Pi*x)
+			},
+			order: 1,
+		},
+		{
+			name: "neumann third",
+			bc:   poisson.Neumann, n: 16, h: 1.0 / 16,
+			x:     func(i int, h float64) float64 { return (float64(i) + 0.5) * h },
+			u:     func(x float64) float64 { return math.Cos(3 * math.Pi * x) },
+			du:    func(x float64, _ int) float64 { return math.Pow(3*math.Pi, 3) * math.Sin(3*math.Pi*x) },
+			order: 3,
+		},
+		{
+			name: "neumann second",
+			bc:   poisson.Neumann, n: 16, h: 1.0 / 16,
+			x:     func(i int, h float64) float64 { return (float64(i) + 0.5) * h },
+			u:     func(x float64) float64 { return math.Cos(2 * math.Pi * x) },
+			du:    func(x float64, _ int) float64 { return -4 * math.Pi * math.Pi * math.Cos(2*math.Pi*x) },
+			order: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := spectral.NewPlan(1, []int{tt.n}, []float64{tt.h}, []poisson.BCType{tt.bc})
+			if err != nil {
+				t.Fatalf("NewPlan failed: %v", err)
+			}
+
+			u := make([]float64, tt.n)
+			for i := range u {
+				u[i] = tt.u(tt.x(i, tt.h))
+			}
+
+			if err := plan.Derivative(u, u, 0, tt.order); err != nil {
+				t.Fatalf("Derivative failed: %v", err)
+			}
+
+			scale := math.Pow(6*math.Pi, float64(tt.order))
+			for i, v := range u {
+				want := tt.du(tt.x(i, tt.h), tt.order)
+				if math.Abs(v-want) > spectralTol*scale {
+					t.Fatalf("d^%d u[%d] = %g, want %g", tt.order, i, v, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGradientAndLaplacian_3DMixed(t *testing.T) {
+	n := []int{8, 7, 6}
+	h := []float64{1.0 / 8, 1.0 / 8, 1.0 / 6}
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+
+	plan, err := spectral.NewPlan(3, n, h, bc)
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	size := n[0] * n[1] * n[2]
+	u := make([]float64, size)
+	grad := [][]float64{make([]float64, size), make([]float64, size), make([]float64, size)}
+	wantGrad := [][]float64{make([]float64, size), make([]float64, size), make([]float64, size)}
+	wantLap := make([]float64, size)
+
+	kx, ky, kz := 2*math.Pi, math.Pi, math.Pi
+	for i := range n[0] {
+		x := float64(i) * h[0]
+		for j := range n[1] {
+			y := float64(j+1) * h[1]
+			for k := range n[2] {
+				z := (float64(k) + 0.5) * h[2]
+				idx := (i*n[1]+j)*n[2] + k
+				sx, cx := math.Sincos(kx * x)
+				sy, cy := math.Sincos(ky * y)
+				sz, cz := math.Sincos(kz * z)
+				u[idx] = sx * sy * cz
+				wantGrad[0][idx] = kx * cx * sy * cz
+				wantGrad[1][idx] = ky * sx * cy * cz
+				wantGrad[2][idx] = -kz * sx * sy * sz
+				wantLap[idx] = -(kx*kx + ky*ky + kz*kz) * u[idx]
+			}
+		}
+	}
+
+	if err := plan.Gradient(grad, u); err != nil {
+		t.Fatalf("Gradient failed: %v", err)
+	}
+	for axis := range grad {
+		for idx, v := range grad[axis] {
+			if math.Abs(v-wantGrad[axis][idx]) > spectralTol*10 {
+				t.Fatalf("grad[%d][%d] = %g, want %g", axis, idx, v, wantGrad[axis][idx])
+			}
+		}
+	}
+
+	if err := plan.Laplacian(u, u); err != nil {
+		t.Fatalf("Laplacian failed: %v", err)
+	}
+	for idx, v := range u {
+		if math.Abs(v-wantLap[idx]) > spectralTol*100 {
+			t.Fatalf("lap[%d] = %g, want %g", idx, v, wantLap[idx])
+		}
+	}
+}
+
+func TestPlan_Errors(t *testing.T) {
+	if _, err := spectral.NewPlan(1, []int{0}, []float64{1}, []poisson.BCType{poisson.Periodic}); !errors.Is(err, poisson.ErrInvalidSize) {
+		t.Fatalf("expected ErrInvalidSize, got %v", err)
+	}
+
+	plan, err := spectral.NewPlan(2, []int{4, 4}, []float64{1, 1}, []poisson.BCType{poisson.Periodic, poisson.Neumann})
+	if err != nil {
+		t.Fatalf("NewPlan failed: %v", err)
+	}
+
+	buf := make([]float64, 16)
+	var vErr *poisson.ValidationError
+	if err := plan.Derivative(buf, buf, 2, 1); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for axis, got %v", err)
+	}
+	if err := plan.Derivative(buf, buf, 0, -1); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for order, got %v", err)
+	}
+	if err := plan.Derivative(buf[:3], buf, 0, 1); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("expected ErrSizeMismatch, got %v", err)
+	}
+	if err := plan.Gradient([][]float64{buf}, buf); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for gradient, got %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package spectral
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/MeKo-Tech/algo-pde/grid"
+	"github.com/MeKo-Tech/algo-pde/poisson"
+)
+
+// Resample interpolates src, given on a grid of shape srcN, onto a grid of
+// shape dstN over the same domain, by zero-padding (refinement) or
+// truncating (coarsening) the field's spectral expansion along each axis.
+//
+// The domain and point layout along each axis follow bc (see the package
+// documentation): Periodic [0, n*h), Dirichlet interior points of
+// [0, (n+1)*h], Neumann cell centers of [0, n*h]. Refinement reproduces every
+// resolved mode exactly; coarsening drops the modes the target grid cannot
+// represent. For periodic axes the Nyquist mode is split or folded so real
+// fields stay real.
+//
+// Resample allocates transform plans and scratch buffers on every call; it is
+// meant for restarts and multiresolution transfers, not inner loops.
+func Resample(dst []float64, dstN []int, src []float64, srcN []int, bc []poisson.BCType) error {
+	dim := len(bc)
+	if dim < 1 || dim > 3 {
+		return &poisson.ValidationError{Field: "bc", Message: "length must be 1, 2, or 3"}
+	}
+
+	if len(dstN) != dim || len(srcN) != dim {
+		return &poisson.ValidationError{Field: "n", Message: "dstN and srcN lengths must match len(bc)"}
+	}
+
+	if dst == nil || src == nil {
+		return poisson.ErrNilBuffer
+	}
+
+	from := grid.Shape{1, 1, 1}
+	to := grid.Shape{1, 1, 1}
+	for axis := 0; axis < dim; axis++ {
+		if srcN[axis] < 1 || dstN[axis] < 1 {
+			return poisson.ErrInvalidSize
+		}
+
+		switch bc[axis] {
+		case poisson.Periodic, poisson.Dirichlet, poisson.Neumann:
+		default:
+			return &poisson.ValidationError{
+				Field:   fmt.Sprintf("bc[%d]", axis),
+				Message: "unsupported boundary condition",
+			}
+		}
+
+		from[axis] = srcN[axis]
+		to[axis] = dstN[axis]
+	}
+
+	if len(src) != from.Size() || len(dst) != to.Size() {
+		return poisson.ErrSizeMismatch
+	}
+
+	cur := append([]float64(nil), src...)
+	shape := from
+	for axis := 0; axis < dim; axis++ {
+		if shape[axis] == to[axis] {
+			continue
+		}
+
+		next := shape
+		next[axis] = to[axis]
+		out := make([]float64, next.Size())
+
+		if err := resampleAxis(out, next, cur, shape, axis, bc[axis]); err != nil {
+			return fmt.Errorf("axis %d: %w", axis, err)
+		}
+
+		cur, shape = out, next
+	}
+
+	copy(dst, cur)
+
+	return nil
+}
+
+// ResampleBoundary resamples the face data of bcs from a grid of shape srcN
+// to shape dstN, as Resample does for the field, and returns new boundary
+// conditions with the same faces and types. bc holds the axis boundary
+// conditions of the grid.
+//
+// Face values are laid out row-major over the two remaining axes, with the
+// point layout of those axes (see the package documentation), so each face
+// is resampled with Resample over its tangential axes. Dirichlet values and
+// Neumann fluxes are pointwise data and need no rescaling with the spacing.
+// Faces of 1D grids hold a single value and are copied. Faces with a Func
+// cannot be resampled and are rejected.
+func ResampleBoundary(bcs poisson.BoundaryConditions, dstN, srcN []int, bc []poisson.BCType) (poisson.BoundaryConditions, error) {
+	dim := len(bc)
+	if dim < 1 || dim > 3 {
+		return nil, &poisson.ValidationError{Field: "bc", Message: "length must be 1, 2, or 3"}
+	}
+
+	if len(dstN) != dim || len(srcN) != dim {
+		return nil, &poisson.ValidationError{Field: "n", Message: "dstN and srcN lengths must match len(bc)"}
+	}
+
+	out := make(poisson.BoundaryConditions, len(bcs))
+	for f, data := range bcs {
+		field := fmt.Sprintf("bcs[%d]", f)
+
+		normal := int(data.Face) / 2
+		if data.Face < poisson.XLow || data.Face > poisson.ZHigh || normal >= dim {
+			return nil, &poisson.ValidationError{Field: field, Message: "face not valid for this dimension"}
+		}
+
+		if data.Func != nil {
+			return nil, &poisson.ValidationError{Field: field, Message: "time-dependent faces (Func) cannot be resampled"}
+		}
+
+		var tanBC []poisson.BCType
+		var tanSrc, tanDst []int
+		srcSize, dstSize := 1, 1
+		for axis := 0; axis < dim; axis++ {
+			if axis == normal {
+				continue
+			}
+			tanBC = append(tanBC, bc[axis])
+			tanSrc = append(tanSrc, srcN[axis])
+			tanDst = append(tanDst, dstN[axis])
+			srcSize *= srcN[axis]
+			dstSize *= dstN[axis]
+		}
+
+		if len(data.Values) != srcSize {
+			return nil, &poisson.SizeError{
+				Expected: srcSize,
+				Got:      len(data.Values),
+				Context:  field + " values",
+			}
+		}
+
+		data.Values = append([]float64(nil), data.Values...)
+		if len(tanBC) > 0 {
+			values := make([]float64, dstSize)
+			if err := Resample(values, tanDst, data.Values, tanSrc, tanBC); err != nil {
+				return nil, fmt.Errorf("%s: %w", field, err)
+			}
+			data.Values = values
+		}
+
+		out[f] = data
+	}
+
+	return out, nil
+}
+
+// resampleAxis resamples every line along axis from shape to next.
+func resampleAxis(dst []float64, next grid.Shape, src []float64, shape grid.Shape, axis int, bc poisson.BCType) error {
+	n, n2 := shape[axis], next[axis]
+	m, m2 := extLength(n, bc), extLength(n2, bc)
+
+	fwd, err := poisson.NewFFTPlan(m)
+	if err != nil {
+		return err
+	}
+
+	inv, err := poisson.NewFFTPlan(m2)
+	if err != nil {
+		return err
+	}
+
+	// Cell-centered Neumann points sit half a cell from the origin, so the
+	// sample offset changes with the spacing; correct it with a phase shift.
+	shift := 0.0
+	if bc == poisson.Neumann {
+		shift = math.Pi * (float64(n)/float64(n2) - 1) / float64(m)
+	}
+
+	in := make([]float64, n)
+	out := make([]float64, n2)
+	a := make([]complex128, m)
+	b := make([]complex128, m2)
+	stride := grid.RowMajorStride(shape)[axis]
+	stride2 := grid.RowMajorStride(next)[axis]
+	scale := complex(float64(m2)/float64(m), 0)
+
+	var lineErr error
+	forEachLine(shape, next, axis, func(start, start2 int) {
+		if lineErr != nil {
+			return
+		}
+
+		for i := range in {
+			in[i] = src[start+i*stride]
+		}
+
+		extendLine(a, in, bc)
+		if lineErr = fwd.TransformLines(a, grid.NewShape1D(m), 0, false); lineErr != nil {
+			return
+		}
+
+		copyModes(b, a)
+		for j := range b {
+			f := j
+			if j > m2/2 {
+				f = j - m2
+			}
+			if shift != 0 {
+				b[j] *= cmplx.Exp(complex(0, shift*float64(f)))
+			}
+			b[j] *= scale
+		}
+
+		if lineErr = inv.TransformLines(b, grid.NewShape1D(m2), 0, true); lineErr != nil {
+			return
+		}
+
+		offset := 0
+		if bc == poisson.Dirichlet {
+			offset = 1
+		}
+		for i := range out {
+			dst[start2+i*stride2] = real(b[i+offset])
+		}
+	})
+
+	return lineErr
+}
+
+// copyModes maps the FFT coefficients a onto b, zero-padding or truncating
+// the high frequencies. An unpaired Nyquist mode is split on refinement and
+// folded on coarsening.
+func copyModes(b, a []complex128) {
+	m, m2 := len(a), len(b)
+	for j := range b {
+		b[j] = 0
+	}
+
+	k := min(m, m2)
+	half := (k - 1) / 2
+	b[0] = a[0]
+	for j := 1; j <= half; j++ {
+		b[j] = a[j]
+		b[m2-j] = a[m-j]
+	}
+
+	if k%2 == 0 {
+		nyq := k / 2
+		switch {
+		case m == m2:
+			b[nyq] = a[nyq]
+		case m < m2:
+			b[nyq] = a[nyq] / 2
+			b[m2-nyq] = a[nyq] / 2
+		default:
+			b[nyq] = a[nyq] + a[m-nyq]
+		}
+	}
+}
+
+// extLength returns the periodic extension length for n points.
+func extLength(n int, bc poisson.BCType) int {
+	switch bc {
+	case poisson.Dirichlet:
+		return 2 * (n + 1)
+	case poisson.Neumann:
+		return 2 * n
+	default:
+		return n
+	}
+}
+
+// extendLine writes the periodic, odd or even extension of src into line.
+func extendLine(line []complex128, src []float64, bc poisson.BCType) {
+	n, m := len(src), len(line)
+	switch bc {
+	case poisson.Dirichlet:
+		line[0] = 0
+		line[n+1] = 0
+		for i, v := range src {
+			line[i+1] = complex(v, 0)
+			line[m-1-i] = complex(-v, 0)
+		}
+	case poisson.Neumann:
+		for i, v := range src {
+			line[i] = complex(v, 0)
+			line[m-1-i] = complex(v, 0)
+		}
+	default:
+		for i, v := range src {
+			line[i] = complex(v, 0)
+		}
+	}
+}
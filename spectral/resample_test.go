@@ -0,0 +1,207 @@
+package spectral_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MeKo-Tech/algo-pde/poisson"
+	"github.com/MeKo-Tech/algo-pde/spectral"
+)
+
+// point returns the coordinate of point i on a unit domain with n points.
+func point(i, n int, bc poisson.BCType) float64 {
+	switch bc {
+	case poisson.Dirichlet:
+		return float64(i+1) / float64(n+1)
+	case poisson.Neumann:
+		return (float64(i) + 0.5) / float64(n)
+	default:
+		return float64(i) / float64(n)
+	}
+}
+
+func TestResample_1D(t *testing.T) {
+	tests := []struct {
+		name  string
+		bc    poisson.BCType
+		n, n2 int
+		f     func(x float64) float64
+	}{
+		{"periodic up", poisson.Periodic, 16, 40, func(x float64) float64 {
+			return 1 + math.Sin(2*math.Pi*x) + 0.3*math.Cos(10*math.Pi*x)
+		}},
+		{"periodic down", poisson.Periodic, 64, 12, func(x float64) float64 {
+			return math.Sin(2*math.Pi*x) - 0.5*math.Cos(4*math.Pi*x)
+		}},
+		{"dirichlet up", poisson.Dirichlet, 7, 31, func(x float64) float64 {
+			return math.Sin(math.Pi*x) + 0.25*math.Sin(5*math.Pi*x)
+		}},
+		{"dirichlet down", poisson.Dirichlet, 31, 9, func(x float64) float64 {
+			return math.Sin(2 * math.Pi * x)
+		}},
+		{"neumann up", poisson.Neumann, 8, 21, func(x float64) float64 {
+			return 2 + math.Cos(math.Pi*x) - 0.5*math.Cos(3*math.Pi*x)
+		}},
+		{"neumann down", poisson.Neumann, 40, 10, func(x float64) float64 {
+			return math.Cos(2 * math.Pi * x)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := make([]float64, tt.n)
+			for i := range src {
+				src[i] = tt.f(point(i, tt.n, tt.bc))
+			}
+
+			dst := make([]float64, tt.n2)
+			if err := spectral.Resample(dst, []int{tt.n2}, src, []int{tt.n}, []poisson.BCType{tt.bc}); err != nil {
+				t.Fatalf("Resample failed: %v", err)
+			}
+
+			for i, v := range dst {
+				want := tt.f(point(i, tt.n2, tt.bc))
+				if math.Abs(v-want) > 1e-12 {
+					t.Fatalf("dst[%d] = %g, want %g", i, v, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResample_3DRoundTrip(t *testing.T) {
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+	coarse := []int{6, 5, 4}
+	fine := []int{12, 11, 9}
+
+	src := make([]float64, coarse[0]*coarse[1]*coarse[2])
+	for i := range coarse[0] {
+		for j := range coarse[1] {
+			for k := range coarse[2] {
+				x, y, z := point(i, coarse[0], bc[0]), point(j, coarse[1], bc[1]), point(k, coarse[2], bc[2])
+				src[(i*coarse[1]+j)*coarse[2]+k] = math.Cos(2*math.Pi*x) * math.Sin(math.Pi*y) * (1 + math.Cos(math.Pi*z))
+			}
+		}
+	}
+
+	up := make([]float64, fine[0]*fine[1]*fine[2])
+	if err := spectral.Resample(up, fine, src, coarse, bc); err != nil {
+		t.Fatalf("Resample up failed: %v", err)
+	}
+
+	back := make([]float64, len(src))
+	if err := spectral.Resample(back, coarse, up, fine, bc); err != nil {
+		t.Fatalf("Resample down failed: %v", err)
+	}
+
+	for i := range src {
+		if math.Abs(back[i]-src[i]) > 1e-12 {
+			t.Fatalf("round trip [%d] = %g, want %g", i, back[i], src[i])
+		}
+	}
+}
+
+func TestResample_Errors(t *testing.T) {
+	bc := []poisson.BCType{poisson.Periodic}
+	if err := spectral.Resample(make([]float64, 4), []int{4}, make([]float64, 3), []int{4}, bc); !errors.Is(err, poisson.ErrSizeMismatch) {
+		t.Fatalf("expected ErrSizeMismatch, got %v", err)
+	}
+	if err := spectral.Resample(make([]float64, 4), []int{4}, nil, []int{0}, bc); !errors.Is(err, poisson.ErrNilBuffer) {
+		t.Fatalf("expected ErrNilBuffer, got %v", err)
+	}
+	var vErr *poisson.ValidationError
+	if err := spectral.Resample(make([]float64, 4), []int{4}, make([]float64, 4), []int{4, 1}, bc); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestResampleBoundary_3D(t *testing.T) {
+	bc := []poisson.BCType{poisson.Periodic, poisson.Dirichlet, poisson.Neumann}
+	coarse := []int{6, 5, 4}
+	fine := []int{12, 11, 8}
+
+	fx := func(x float64) float64 { return 1 + math.Cos(2*math.Pi*x) }
+	fy := func(y float64) float64 { return math.Sin(math.Pi*y) - 0.5*math.Sin(3*math.Pi*y) }
+	fz := func(z float64) float64 { return 2 + math.Cos(math.Pi*z) }
+
+	// face samples g(a, b) over the two tangential axes of a face.
+	face := func(n []int, a, b int, g func(u, v float64) float64) []float64 {
+		values := make([]float64, 0, n[a]*n[b])
+		for i := range n[a] {
+			for j := range n[b] {
+				values = append(values, g(point(i, n[a], bc[a]), point(j, n[b], bc[b])))
+			}
+		}
+		return values
+	}
+	gx := func(y, z float64) float64 { return fy(y) * fz(z) }
+	gy := func(x, z float64) float64 { return fx(x) * fz(z) }
+	gz := func(x, y float64) float64 { return fx(x) * fy(y) }
+
+	bcs := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Values: face(coarse, 1, 2, gx)},
+		{Face: poisson.YHigh, Type: poisson.Neumann, Values: face(coarse, 0, 2, gy)},
+		{Face: poisson.ZLow, Type: poisson.Neumann, Values: face(coarse, 0, 1, gz)},
+	}
+
+	got, err := spectral.ResampleBoundary(bcs, fine, coarse, bc)
+	if err != nil {
+		t.Fatalf("ResampleBoundary failed: %v", err)
+	}
+
+	want := [][]float64{face(fine, 1, 2, gx), face(fine, 0, 2, gy), face(fine, 0, 1, gz)}
+	for f := range got {
+		if got[f].Face != bcs[f].Face || got[f].Type != bcs[f].Type {
+			t.Fatalf("face %d = %v/%v, want %v/%v", f, got[f].Face, got[f].Type, bcs[f].Face, bcs[f].Type)
+		}
+		if len(got[f].Values) != len(want[f]) {
+			t.Fatalf("face %d has %d values, want %d", f, len(got[f].Values), len(want[f]))
+		}
+		for i, v := range got[f].Values {
+			if math.Abs(v-want[f][i]) > 1e-12 {
+				t.Fatalf("face %d value %d = %g, want %g", f, i, v, want[f][i])
+			}
+		}
+	}
+}
+
+func TestResampleBoundary_1DCopies(t *testing.T) {
+	bc := []poisson.BCType{poisson.Dirichlet}
+	bcs := poisson.BoundaryConditions{
+		{Face: poisson.XLow, Type: poisson.Dirichlet, Values: []float64{1.5}},
+		{Face: poisson.XHigh, Type: poisson.Dirichlet, Values: []float64{-2}},
+	}
+
+	got, err := spectral.ResampleBoundary(bcs, []int{31}, []int{7}, bc)
+	if err != nil {
+		t.Fatalf("ResampleBoundary failed: %v", err)
+	}
+
+	got[0].Values[0] = 0
+	if got[1].Values[0] != -2 || bcs[0].Values[0] != 1.5 {
+		t.Fatalf("values = %v, %v; want copies of the input", got[1].Values, bcs[0].Values)
+	}
+}
+
+func TestResampleBoundary_Errors(t *testing.T) {
+	bc := []poisson.BCType{poisson.Periodic, poisson.Periodic}
+	n := []int{4, 4}
+
+	var vErr *poisson.ValidationError
+	zFace := poisson.BoundaryConditions{{Face: poisson.ZLow, Type: poisson.Dirichlet, Values: make([]float64, 16)}}
+	if _, err := spectral.ResampleBoundary(zFace, n, n, bc); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for a Z face in 2D, got %v", err)
+	}
+
+	fn := poisson.BoundaryConditions{{Face: poisson.XLow, Type: poisson.Dirichlet, Func: func(float64, int) float64 { return 0 }}}
+	if _, err := spectral.ResampleBoundary(fn, n, n, bc); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for a Func face, got %v", err)
+	}
+
+	var sErr *poisson.SizeError
+	short := poisson.BoundaryConditions{{Face: poisson.YHigh, Type: poisson.Neumann, Values: make([]float64, 3)}}
+	if _, err := spectral.ResampleBoundary(short, n, n, bc); !errors.As(err, &sErr) {
+		t.Fatalf("expected SizeError, got %v", err)
+	}
+}